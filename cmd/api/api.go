@@ -64,6 +64,7 @@ import (
 	"strings"
 
 	"github.com/lastbackend/lastbackend/pkg/api"
+	"github.com/lastbackend/lastbackend/pkg/storage"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -161,5 +162,9 @@ func main() {
 		}
 	}
 
+	if err := storage.CheckShared(v); err != nil {
+		panic(err.Error())
+	}
+
 	api.Daemon(v)
 }
@@ -64,6 +64,7 @@ import (
 	"strings"
 
 	"github.com/lastbackend/lastbackend/pkg/controller"
+	"github.com/lastbackend/lastbackend/pkg/storage"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -86,6 +87,11 @@ var (
 		Bind string
 	}{
 		{Name: "services-cidr", Short: "", Value: "172.0.0.0/24", Desc: "Services IP CIDR for internal IPAM service", Bind: "service.cidr"},
+		{Name: "services-cidr-v6", Short: "", Value: "", Desc: "Services IPv6 CIDR for internal IPAM service, enables dual stack endpoints", Bind: "service.cidr_v6"},
+		{Name: "services-node-port-range", Short: "", Value: "30000-32767", Desc: "Ports range services are exposed from on all nodes", Bind: "service.node_port_range"},
+		{Name: "services-upstream-release-delay", Short: "", Value: "5s", Desc: "Delay between pod removal from service upstreams and pod termination", Bind: "service.upstream_release_delay"},
+		{Name: "network-cidr", Short: "", Value: "", Desc: "Cluster CIDR for nodes pod subnets allocation", Bind: "network.cidr"},
+		{Name: "network-subnet-prefix", Short: "", Value: 24, Desc: "Prefix length of node pod subnet allocated from cluster CIDR", Bind: "network.subnet_prefix"},
 		{Name: "storage", Short: "", Value: "etcd", Desc: "Set storage driver (Allow: etcd, mock)", Bind: "storage.driver"},
 		{Name: "etcd-cert-file", Short: "", Value: "", Desc: "ETCD database cert file path", Bind: "storage.etcd.tls.cert"},
 		{Name: "etcd-private-key-file", Short: "", Value: "", Desc: "ETCD database private key file path", Bind: "storage.etcd.tls.key"},
@@ -150,5 +156,9 @@ func main() {
 		}
 	}
 
+	if err := storage.CheckShared(v); err != nil {
+		panic(err.Error())
+	}
+
 	controller.Daemon(v)
 }
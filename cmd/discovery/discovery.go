@@ -64,6 +64,7 @@ import (
 	"strings"
 
 	"github.com/lastbackend/lastbackend/pkg/discovery"
+	"github.com/lastbackend/lastbackend/pkg/storage"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -95,6 +96,13 @@ var (
 		{Name: "bind-address", Short: "", Value: "0.0.0.0", Desc: "DNS server bind address", Bind: "dns.host"},
 		{Name: "bind-port", Short: "", Value: 53, Desc: "DNS port listening", Bind: "dns.port"},
 		{Name: "dns-ttl", Short: "", Value: "24h", Desc: "DNS cache ttl", Bind: "dns.ttl"},
+		{Name: "dns-record-ttl", Short: "", Value: "30s", Desc: "TTL of service records in DNS answers", Bind: "dns.record_ttl"},
+		{Name: "dns-headless-ttl", Short: "", Value: "5s", Desc: "TTL of headless service and pod records in DNS answers", Bind: "dns.headless_ttl"},
+		{Name: "dns-negative-ttl", Short: "", Value: "5s", Desc: "DNS cache ttl of unknown cluster domains", Bind: "dns.negative_ttl"},
+		{Name: "dns-upstreams", Short: "", Value: []string{}, Desc: "DNS upstreams for non-cluster domains: [udp|tcp|tls://]ip[:port][#server name], resolv.conf nameservers are used by default", Bind: "dns.upstreams"},
+		{Name: "dns-stub-zones", Short: "", Value: []string{}, Desc: "DNS stub zones forwarded to own servers: zone=upstream", Bind: "dns.stub_zones"},
+		{Name: "dns-forward-timeout", Short: "", Value: "2s", Desc: "DNS upstream query timeout", Bind: "dns.forward_timeout"},
+		{Name: "dns-cache-size", Short: "", Value: 10000, Desc: "DNS forwarded responses cache size", Bind: "dns.cache_size"},
 		{Name: "api-uri", Short: "", Value: "", Desc: "REST API endpoint", Bind: "api.uri"},
 		{Name: "api-cert-file", Short: "", Value: "", Desc: "REST API TLS certificate file path", Bind: "api.tls.cert"},
 		{Name: "api-private-key-file", Short: "", Value: "", Desc: "REST API TLS private key file path", Bind: "api.tls.key"},
@@ -157,5 +165,9 @@ func main() {
 		}
 	}
 
+	if err := storage.CheckShared(v); err != nil {
+		panic(err.Error())
+	}
+
 	discovery.Daemon(v)
 }
@@ -98,6 +98,8 @@ var (
 		{Name: "network-driver", Short: "", Value: "vxlan", Desc: "Container overlay network driver", Bind: "network.cni.type"},
 		{Name: "network-driver-iface-external", Short: "", Value: "eth0", Desc: "", Bind: "network.cni.interface.external"},
 		{Name: "network-driver-iface-bridge", Short: "", Value: "docker0", Desc: "", Bind: "network.cni.interface.internal"},
+		{Name: "network-driver-wireguard-key", Short: "", Value: "", Desc: "WireGuard network driver private key file path", Bind: "network.cni.wireguard.key"},
+		{Name: "network-driver-wireguard-rotation", Short: "", Value: "", Desc: "WireGuard network driver key rotation interval, like 720h (disabled by default)", Bind: "network.cni.wireguard.rotation"},
 		{Name: "network-resolvers", Short: "", Value: []string{"8.8.8.8", "8.8.4.4"}, Desc: "Additional resolvers IPS for Ingress", Bind: "resolver.servers"},
 		{Name: "api-uri", Short: "", Value: "", Desc: "REST API endpoint", Bind: "api.uri"},
 		{Name: "api-cert-file", Short: "", Value: "", Desc: "REST API TLS certificate file path", Bind: "api.tls.cert"},
@@ -0,0 +1,147 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	l "github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const default_env_prefix = "LB"
+const default_config_type = "yaml"
+const default_config_name = "config"
+
+const usage = `Usage: migrate [flags]
+
+Upgrades all stored objects to the current storage schema version.
+Run it after upgrade, before new version of controller is started.
+
+Flags:
+`
+
+var (
+	flags = []struct {
+		// flag name
+		Name string
+		// flag short name
+		Short string
+		// flag value
+		Value interface{}
+		// flag description
+		Desc string
+		// viper name for binding from flag
+		Bind string
+	}{
+		{Name: "storage", Short: "", Value: "etcd", Desc: "Set storage driver (Allow: etcd, bbolt, mock)", Bind: "storage.driver"},
+		{Name: "etcd-cert-file", Short: "", Value: "", Desc: "ETCD database cert file path", Bind: "storage.etcd.tls.cert"},
+		{Name: "etcd-private-key-file", Short: "", Value: "", Desc: "ETCD database private key file path", Bind: "storage.etcd.tls.key"},
+		{Name: "etcd-ca-file", Short: "", Value: "", Desc: "ETCD database certificate authority file", Bind: "storage.etcd.tls.ca"},
+		{Name: "etcd-endpoints", Short: "", Value: []string{"127.0.0.1:2379"}, Desc: "ETCD database endpoints list", Bind: "storage.etcd.endpoints"},
+		{Name: "etcd-prefix", Short: "", Value: "lastbackend", Desc: "ETCD database storage prefix", Bind: "storage.etcd.prefix"},
+		{Name: "bbolt-path", Short: "", Value: "/var/lib/lastbackend/storage.db", Desc: "Embedded database file path", Bind: "storage.bbolt.path"},
+		{Name: "bbolt-prefix", Short: "", Value: "lastbackend", Desc: "Embedded database storage prefix", Bind: "storage.bbolt.prefix"},
+		{Name: "verbose", Short: "v", Value: 0, Desc: "Set log level from 0 to 7", Bind: "verbose"},
+		{Name: "config", Short: "c", Value: "", Desc: "Path for the configuration file", Bind: "config"},
+	}
+)
+
+func main() {
+
+	for _, item := range flags {
+		switch item.Value.(type) {
+		case string:
+			flag.StringP(item.Name, item.Short, item.Value.(string), item.Desc)
+		case int:
+			flag.IntP(item.Name, item.Short, item.Value.(int), item.Desc)
+		case []string:
+			flag.StringSliceP(item.Name, item.Short, item.Value.([]string), item.Desc)
+		default:
+			panic(fmt.Sprintf("bad %s argument value", item.Name))
+		}
+	}
+
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	v := viper.New()
+
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.SetEnvPrefix(default_env_prefix)
+
+	for _, item := range flags {
+
+		if len(flag.Lookup(item.Name).Value.String()) != 0 {
+			if err := v.BindPFlag(item.Bind, flag.Lookup(item.Name)); err != nil {
+				panic(err)
+			}
+		} else {
+			v.SetDefault(item.Bind, nil)
+		}
+
+		name := strings.Replace(strings.ToUpper(item.Name), "-", "_", -1)
+		name = strings.Join([]string{default_env_prefix, name}, "_")
+
+		if err := v.BindEnv(item.Bind, name); err != nil {
+			panic(err)
+		}
+
+		v.SetDefault(item.Bind, item.Value)
+
+	}
+
+	v.SetConfigType(default_config_type)
+	v.SetConfigFile(v.GetString(default_config_name))
+
+	if len(v.GetString("config")) != 0 {
+		if err := v.ReadInConfig(); err != nil {
+			panic(fmt.Sprintf("Read config err: %v", err))
+		}
+	}
+
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	log := l.New(v.GetInt("verbose"))
+
+	stg, err := storage.Get(v)
+	if err != nil {
+		log.Fatalf("Cannot initialize storage: %s", err.Error())
+	}
+
+	count, err := storage.Migrate(context.Background(), stg)
+	if err != nil {
+		log.Fatalf("Migration failed: %s", err.Error())
+	}
+
+	log.Infof("Migration completed: %d objects upgraded", count)
+}
@@ -94,13 +94,19 @@ var (
 		{Name: "network-driver", Short: "", Value: "vxlan", Desc: "Network driver (vxlan by default)", Bind: "network.cni.type"},
 		{Name: "network-driver-iface-external", Short: "", Value: "eth0", Desc: "Container overlay network external interface for host communication", Bind: "network.cni.interface.external"},
 		{Name: "network-driver-iface-internal", Short: "", Value: "docker0", Desc: "Container overlay network internal bridge interface for container intercommunications", Bind: "network.cni.interface.internal"},
+		{Name: "network-driver-wireguard-key", Short: "", Value: "", Desc: "WireGuard network driver private key file path", Bind: "network.cni.wireguard.key"},
+		{Name: "network-driver-wireguard-rotation", Short: "", Value: "", Desc: "WireGuard network driver key rotation interval, like 720h (disabled by default)", Bind: "network.cni.wireguard.rotation"},
 		{Name: "container-runtime", Short: "", Value: "docker", Desc: "Node container runtime", Bind: "container.cri.type"},
 		{Name: "container-runtime-docker-version", Short: "", Value: "1.38", Desc: "Set docker version for docker container runtime", Bind: "container.cri.docker.version"},
 		{Name: "container-runtime-docker-host", Short: "", Value: "unix:///var/run/docker.sock", Desc: "Set docker host for docker container runtime", Bind: "container.cri.docker.host"},
+		{Name: "container-runtime-docker-seccomp-profile-root", Short: "", Value: "/etc/lastbackend/seccomp", Desc: "Set directory with localhost seccomp profiles for docker container runtime", Bind: "container.cri.docker.seccomp_profile_root"},
 		{Name: "container-runtime-docker-tls-verify", Short: "", Value: false, Desc: "Enable check tls for docker container runtime", Bind: "container.cri.docker.tls.verify"},
 		{Name: "container-runtime-docker-tls-ca", Short: "", Value: "", Desc: "Set path to ca file for docker container runtime", Bind: "container.cri.docker.tls.ca_file"},
 		{Name: "container-runtime-docker-tls-cert", Short: "", Value: "", Desc: "Set path to cert file for docker container runtime", Bind: "container.cri.docker.tls.cert_file"},
 		{Name: "container-runtime-docker-tls-key", Short: "", Value: "", Desc: "Set path to key file for docker container runtime", Bind: "container.cri.docker.tls.key_file"},
+		{Name: "container-runtime-containerd-host", Short: "", Value: "unix:///run/containerd/containerd.sock", Desc: "Set containerd host for containerd container runtime", Bind: "container.cri.containerd.host"},
+		{Name: "container-runtime-containerd-log-dir", Short: "", Value: "/var/log/lastbackend/pods", Desc: "Set containers logs directory for containerd container runtime", Bind: "container.cri.containerd.log_dir"},
+		{Name: "container-runtime-containerd-handler", Short: "", Value: "", Desc: "Set runtime handler for containerd container runtime", Bind: "container.cri.containerd.handler"},
 		{Name: "container-storage-root", Short: "", Value: "/var/run/lastbackend", Desc: "Node container storage root", Bind: "container.csi.dir.root"},
 		{Name: "container-image-runtime", Short: "", Value: "docker", Desc: "Node container images runtime", Bind: "container.iri.type"},
 		{Name: "container-image-runtime-docker-version", Short: "", Value: "1.38", Desc: "Set docker version for docker container image runtime", Bind: "container.iri.docker.version"},
@@ -109,9 +115,15 @@ var (
 		{Name: "container-image-runtime-docker-tls-ca", Short: "", Value: "", Desc: "Set path to ca file for docker container image runtime", Bind: "container.iri.docker.tls.ca_file"},
 		{Name: "container-image-runtime-docker-tls-cert", Short: "", Value: "", Desc: "Set path to cert file for docker container image runtime", Bind: "container.iri.docker.tls.cert_file"},
 		{Name: "container-image-runtime-docker-tls-key", Short: "", Value: "", Desc: "Set path to key file for docker container image runtime", Bind: "container.iri.docker.tls.key_file"},
+		{Name: "container-image-runtime-containerd-host", Short: "", Value: "unix:///run/containerd/containerd.sock", Desc: "Set containerd host for containerd container image runtime", Bind: "container.iri.containerd.host"},
+		{Name: "container-image-gc-path", Short: "", Value: "", Desc: "Path to container images storage for disk usage check, by default it is reported by image runtime", Bind: "container.iri.gc.path"},
+		{Name: "container-image-gc-period", Short: "", Value: 300, Desc: "Container images garbage collection period in seconds", Bind: "container.iri.gc.period"},
+		{Name: "container-image-gc-high-threshold", Short: "", Value: 85, Desc: "Disk usage percent after which unused images are removed", Bind: "container.iri.gc.high_threshold"},
+		{Name: "container-image-gc-low-threshold", Short: "", Value: 80, Desc: "Disk usage percent to which unused images are removed", Bind: "container.iri.gc.low_threshold"},
 		{Name: "container-extra-hosts", Short: "", Value: []string{}, Desc: "Set hostname mappings for containers", Bind: "container.extra_hosts"},
 		{Name: "bind-address", Short: "", Value: "0.0.0.0", Desc: "Node bind address", Bind: "server.host"},
 		{Name: "bind-port", Short: "", Value: 2969, Desc: "Node listening port binding", Bind: "server.port"},
+		{Name: "advertise-address", Short: "", Value: "", Desc: "Node address published to cluster api, by default bind or external address is used", Bind: "server.advertise"},
 		{Name: "tls-verify", Short: "", Value: false, Desc: "Node TLS verify options", Bind: "server.tls.verify"},
 		{Name: "tls-cert-file", Short: "", Value: "", Desc: "Node cert file path", Bind: "server.tls.cert"},
 		{Name: "tls-private-key-file", Short: "", Value: "", Desc: "Node private key file path", Bind: "server.tls.key"},
@@ -0,0 +1,149 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+// Standalone runs API server, cluster controller and service discovery in one process.
+// It is required for embedded bbolt storage, database file can be opened by one process only.
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/api"
+	"github.com/lastbackend/lastbackend/pkg/controller"
+	"github.com/lastbackend/lastbackend/pkg/discovery"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const default_env_prefix = "LB"
+const default_config_type = "yaml"
+const default_config_name = "config"
+
+var (
+	flags = []struct {
+		// flag name
+		Name string
+		// flag short name
+		Short string
+		// flag value
+		Value interface{}
+		// flag description
+		Desc string
+		// viper name for binding from flag
+		Bind string
+	}{
+		{Name: "access-token", Short: "", Value: "", Desc: "Access token to API server", Bind: "token"},
+		{Name: "cluster-name", Short: "", Value: "", Desc: "Cluster name info", Bind: "name"},
+		{Name: "cluster-description", Short: "", Value: "", Desc: "Cluster description", Bind: "description"},
+		{Name: "bind-address", Short: "", Value: "0.0.0.0", Desc: "Bind address for listening", Bind: "server.host"},
+		{Name: "bind-port", Short: "", Value: 2967, Desc: "Bind port for listening", Bind: "server.port"},
+		{Name: "tls-cert-file", Short: "", Value: "", Desc: "TLS cert file path", Bind: "server.tls.cert"},
+		{Name: "tls-private-key-file", Short: "", Value: "", Desc: "TLS private key file path", Bind: "server.tls.key"},
+		{Name: "tls-ca-file", Short: "", Value: "", Desc: "TLS certificate authority file path", Bind: "server.tls.ca"},
+		{Name: "domain-internal", Short: "", Value: "lb.local", Desc: "Internal domain name for cluster", Bind: "domain.internal"},
+		{Name: "domain-external", Short: "", Value: "", Desc: "Default external domain for cluster", Bind: "domain.external"},
+		{Name: "services-cidr", Short: "", Value: "172.0.0.0/24", Desc: "Services IP CIDR for internal IPAM service", Bind: "service.cidr"},
+		{Name: "services-cidr-v6", Short: "", Value: "", Desc: "Services IPv6 CIDR for internal IPAM service, enables dual stack endpoints", Bind: "service.cidr_v6"},
+		{Name: "services-node-port-range", Short: "", Value: "30000-32767", Desc: "Ports range services are exposed from on all nodes", Bind: "service.node_port_range"},
+		{Name: "services-upstream-release-delay", Short: "", Value: "5s", Desc: "Delay between pod removal from service upstreams and pod termination", Bind: "service.upstream_release_delay"},
+		{Name: "network-cidr", Short: "", Value: "", Desc: "Cluster CIDR for nodes pod subnets allocation", Bind: "network.cidr"},
+		{Name: "network-subnet-prefix", Short: "", Value: 24, Desc: "Prefix length of node pod subnet allocated from cluster CIDR", Bind: "network.subnet_prefix"},
+		{Name: "storage", Short: "", Value: "bbolt", Desc: "Set storage driver (Allow: etcd, bbolt, mock)", Bind: "storage.driver"},
+		{Name: "etcd-cert-file", Short: "", Value: "", Desc: "ETCD database cert file path", Bind: "storage.etcd.tls.cert"},
+		{Name: "etcd-private-key-file", Short: "", Value: "", Desc: "ETCD database private key file path", Bind: "storage.etcd.tls.key"},
+		{Name: "etcd-ca-file", Short: "", Value: "", Desc: "ETCD database certificate authority file", Bind: "storage.etcd.tls.ca"},
+		{Name: "etcd-endpoints", Short: "", Value: []string{"127.0.0.1:2379"}, Desc: "ETCD database endpoints list", Bind: "storage.etcd.endpoints"},
+		{Name: "etcd-prefix", Short: "", Value: "lastbackend", Desc: "ETCD database storage prefix", Bind: "storage.etcd.prefix"},
+		{Name: "bbolt-path", Short: "", Value: "/var/lib/lastbackend/storage.db", Desc: "Embedded database file path", Bind: "storage.bbolt.path"},
+		{Name: "bbolt-prefix", Short: "", Value: "lastbackend", Desc: "Embedded database storage prefix", Bind: "storage.bbolt.prefix"},
+		{Name: "bbolt-history", Short: "", Value: 10000, Desc: "Embedded database changes count kept for watch requests", Bind: "storage.bbolt.history"},
+		{Name: "dns-bind-address", Short: "", Value: "0.0.0.0", Desc: "DNS server bind address", Bind: "dns.host"},
+		{Name: "dns-bind-port", Short: "", Value: 53, Desc: "DNS port listening", Bind: "dns.port"},
+		{Name: "dns-ttl", Short: "", Value: "24h", Desc: "DNS cache ttl", Bind: "dns.ttl"},
+		{Name: "dns-record-ttl", Short: "", Value: "30s", Desc: "TTL of service records in DNS answers", Bind: "dns.record_ttl"},
+		{Name: "dns-headless-ttl", Short: "", Value: "5s", Desc: "TTL of headless service and pod records in DNS answers", Bind: "dns.headless_ttl"},
+		{Name: "dns-negative-ttl", Short: "", Value: "5s", Desc: "DNS cache ttl of unknown cluster domains", Bind: "dns.negative_ttl"},
+		{Name: "dns-upstreams", Short: "", Value: []string{}, Desc: "DNS upstreams for non-cluster domains: [udp|tcp|tls://]ip[:port][#server name], resolv.conf nameservers are used by default", Bind: "dns.upstreams"},
+		{Name: "dns-stub-zones", Short: "", Value: []string{}, Desc: "DNS stub zones forwarded to own servers: zone=upstream", Bind: "dns.stub_zones"},
+		{Name: "dns-forward-timeout", Short: "", Value: "2s", Desc: "DNS upstream query timeout", Bind: "dns.forward_timeout"},
+		{Name: "dns-cache-size", Short: "", Value: 10000, Desc: "DNS forwarded responses cache size", Bind: "dns.cache_size"},
+		{Name: "verbose", Short: "v", Value: 0, Desc: "Set log level from 0 to 7", Bind: "verbose"},
+		{Name: "config", Short: "c", Value: "", Desc: "Path for the configuration file", Bind: "config"},
+	}
+)
+
+func main() {
+
+	for _, item := range flags {
+		switch item.Value.(type) {
+		case string:
+			flag.StringP(item.Name, item.Short, item.Value.(string), item.Desc)
+		case int:
+			flag.IntP(item.Name, item.Short, item.Value.(int), item.Desc)
+		case []string:
+			flag.StringSliceP(item.Name, item.Short, item.Value.([]string), item.Desc)
+		default:
+			panic(fmt.Sprintf("bad %s argument value", item.Name))
+		}
+	}
+
+	flag.Parse()
+
+	v := viper.New()
+
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.SetEnvPrefix(default_env_prefix)
+
+	for _, item := range flags {
+
+		if len(flag.Lookup(item.Name).Value.String()) != 0 {
+			if err := v.BindPFlag(item.Bind, flag.Lookup(item.Name)); err != nil {
+				panic(err)
+			}
+		} else {
+			v.SetDefault(item.Bind, nil)
+		}
+
+		name := strings.Replace(strings.ToUpper(item.Name), "-", "_", -1)
+		name = strings.Join([]string{default_env_prefix, name}, "_")
+
+		if err := v.BindEnv(item.Bind, name); err != nil {
+			panic(err)
+		}
+
+		v.SetDefault(item.Bind, item.Value)
+
+	}
+
+	v.SetConfigType(default_config_type)
+	v.SetConfigFile(v.GetString(default_config_name))
+
+	if len(v.GetString("config")) != 0 {
+		if err := v.ReadInConfig(); err != nil {
+			panic(fmt.Sprintf("Read config err: %v", err))
+		}
+	}
+
+	// components share storage handle, so embedded database file is opened once
+	go controller.Daemon(v)
+	go discovery.Daemon(v)
+
+	api.Daemon(v)
+}
@@ -3,7 +3,7 @@
 mkdir -p build/linux && mkdir -p build/darwin  && mkdir -p build/windows
 
 ## declare an array of components variable
-declare -a arr=("api" "controller" "node" "ingress" "discovery" "exporter")
+declare -a arr=("api" "controller" "node" "ingress" "discovery" "exporter" "backup" "migrate")
 
 if [[ $1 != "" ]]; then
   arr=($1)
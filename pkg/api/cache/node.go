@@ -35,6 +35,7 @@ type CacheNodeManifest struct {
 	exporter  map[string]*types.Exporter
 	discovery map[string]*types.Discovery
 	configs   map[string]*types.ConfigManifest
+	policies  map[string]*types.NetworkPolicyManifest
 	manifests map[string]*types.NodeManifest
 }
 
@@ -144,6 +145,26 @@ func (c *CacheNodeManifest) SetConfigManifest(name string, s *types.ConfigManife
 	}
 }
 
+func (c *CacheNodeManifest) SetNetworkPolicyManifest(namespace string, s *types.NetworkPolicyManifest) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	log.Debugf("%s set network policy manifest: %s", logCacheNode, namespace)
+
+	if s.State == types.StateDestroy {
+		delete(c.policies, namespace)
+	} else {
+		c.policies[namespace] = s
+	}
+
+	for _, n := range c.manifests {
+		if n.Policies == nil {
+			n.Policies = make(map[string]*types.NetworkPolicyManifest, 0)
+		}
+		n.Policies[namespace] = s
+	}
+}
+
 func (c *CacheNodeManifest) SetEndpointManifest(addr string, s *types.EndpointManifest) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -353,6 +374,18 @@ func (c *CacheNodeManifest) GetConfigs() map[string]*types.ConfigManifest {
 	return c.configs
 }
 
+func (c *CacheNodeManifest) GetNetworkPolicies() map[string]*types.NetworkPolicyManifest {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	policies := make(map[string]*types.NetworkPolicyManifest, 0)
+	for namespace, m := range c.policies {
+		policies[namespace] = m
+	}
+
+	return policies
+}
+
 func (c *CacheNodeManifest) SetNode(node *types.Node) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -395,5 +428,6 @@ func NewCacheNodeManifest() *CacheNodeManifest {
 	c.ingress = make(map[string]*types.Ingress, 0)
 	c.discovery = make(map[string]*types.Discovery, 0)
 	c.configs = make(map[string]*types.ConfigManifest, 0)
+	c.policies = make(map[string]*types.NetworkPolicyManifest, 0)
 	return c
 }
@@ -140,6 +140,25 @@ func (cc *ClusterClient) Get(ctx context.Context) (*vv1.Cluster, error) {
 	return s, nil
 }
 
+func (cc *ClusterClient) IPAM(ctx context.Context) (*vv1.ClusterIPAM, error) {
+
+	var s *vv1.ClusterIPAM
+	var e *errors.Http
+
+	err := cc.client.Get("/cluster/ipam").
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func newClusterClient(req *request.RESTClient) *ClusterClient {
 	return &ClusterClient{client: req}
 }
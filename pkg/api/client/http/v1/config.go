@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -83,14 +84,15 @@ func (sc *ConfigClient) Get(ctx context.Context) (*vv1.Config, error) {
 	return s, nil
 }
 
-func (sc *ConfigClient) List(ctx context.Context) (*vv1.ConfigList, error) {
+func (sc *ConfigClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ConfigList, error) {
 
 	var s *vv1.ConfigList
 	var e *errors.Http
 
-	err := sc.client.Get(fmt.Sprintf("/namespace/%s/config", sc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := sc.client.Get(fmt.Sprintf("/namespace/%s/config", sc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -107,6 +109,10 @@ func (sc *ConfigClient) List(ctx context.Context) (*vv1.ConfigList, error) {
 	return s, nil
 }
 
+func (sc *ConfigClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(sc.client, fmt.Sprintf("/namespace/%s/config", sc.namespace), opts)
+}
+
 func (sc *ConfigClient) Update(ctx context.Context, opts *rv1.ConfigManifest) (*vv1.Config, error) {
 
 	body, err := opts.ToJson()
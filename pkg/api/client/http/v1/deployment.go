@@ -54,14 +54,15 @@ func (dc *DeploymentClient) Pod(args ...string) types.PodClientV1 {
 	return newPodClient(dc.client, dc.namespace.String(), t.KindDeployment, dc.selflink.String(), name)
 }
 
-func (dc *DeploymentClient) List(ctx context.Context) (*vv1.DeploymentList, error) {
+func (dc *DeploymentClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.DeploymentList, error) {
 
 	var s *vv1.DeploymentList
 	var e *errors.Http
 
-	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deployment", dc.namespace.String(), dc.service.Name())).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deployment", dc.namespace.String(), dc.service.Name())).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -78,6 +79,10 @@ func (dc *DeploymentClient) List(ctx context.Context) (*vv1.DeploymentList, erro
 	return s, nil
 }
 
+func (dc *DeploymentClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(dc.client, fmt.Sprintf("/namespace/%s/service/%s/deployment", dc.namespace.String(), dc.service.Name()), opts)
+}
+
 func (dc *DeploymentClient) Get(ctx context.Context) (*vv1.Deployment, error) {
 
 	var s *vv1.Deployment
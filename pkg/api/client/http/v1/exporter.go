@@ -21,6 +21,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -33,14 +34,15 @@ type ExporterClient struct {
 	hostname string
 }
 
-func (ic *ExporterClient) List(ctx context.Context) (*vv1.ExporterList, error) {
+func (ic *ExporterClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ExporterList, error) {
 
 	var i *vv1.ExporterList
 	var e *errors.Http
 
-	err := ic.client.Get(fmt.Sprintf("/exporter")).
-		AddHeader("Content-Type", "application/json").
-		JSON(&i, &e)
+	req := ic.client.Get(fmt.Sprintf("/exporter")).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &i, &e)
 
 	if err != nil {
 		return nil, err
@@ -57,6 +59,10 @@ func (ic *ExporterClient) List(ctx context.Context) (*vv1.ExporterList, error) {
 	return i, nil
 }
 
+func (ic *ExporterClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(ic.client, fmt.Sprintf("/exporter"), opts)
+}
+
 func (ic *ExporterClient) Get(ctx context.Context) (*vv1.Exporter, error) {
 
 	var s *vv1.Exporter
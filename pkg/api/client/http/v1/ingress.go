@@ -21,6 +21,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -33,14 +34,15 @@ type IngressClient struct {
 	hostname string
 }
 
-func (ic *IngressClient) List(ctx context.Context) (*vv1.IngressList, error) {
+func (ic *IngressClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.IngressList, error) {
 
 	var i *vv1.IngressList
 	var e *errors.Http
 
-	err := ic.client.Get(fmt.Sprintf("/ingress")).
-		AddHeader("Content-Type", "application/json").
-		JSON(&i, &e)
+	req := ic.client.Get(fmt.Sprintf("/ingress")).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &i, &e)
 
 	if err != nil {
 		return nil, err
@@ -57,6 +59,10 @@ func (ic *IngressClient) List(ctx context.Context) (*vv1.IngressList, error) {
 	return i, nil
 }
 
+func (ic *IngressClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(ic.client, fmt.Sprintf("/ingress"), opts)
+}
+
 func (ic *IngressClient) Get(ctx context.Context) (*vv1.Ingress, error) {
 
 	var s *vv1.Ingress
@@ -103,14 +103,15 @@ func (sc *JobClient) Run(ctx context.Context, opts *rv1.TaskManifest) (*vv1.Task
 	return s, nil
 }
 
-func (sc *JobClient) List(ctx context.Context) (*vv1.JobList, error) {
+func (sc *JobClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.JobList, error) {
 
 	var s *vv1.JobList
 	var e *errors.Http
 
-	err := sc.client.Get(fmt.Sprintf("/namespace/%s/job", sc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := sc.client.Get(fmt.Sprintf("/namespace/%s/job", sc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -127,6 +128,10 @@ func (sc *JobClient) List(ctx context.Context) (*vv1.JobList, error) {
 	return s, nil
 }
 
+func (sc *JobClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(sc.client, fmt.Sprintf("/namespace/%s/job", sc.namespace), opts)
+}
+
 func (sc *JobClient) Get(ctx context.Context) (*vv1.Job, error) {
 
 	var s *vv1.Job
@@ -0,0 +1,135 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+)
+
+// ListIterator requests objects list page by page
+type ListIterator struct {
+	client *request.RESTClient
+	url    string
+	err    error
+
+	opts rv1.ListOptions
+	meta *vv1.ListMeta
+	done bool
+}
+
+// Next requests the next page and decodes page items into out.
+// It returns false when all pages are received
+func (li *ListIterator) Next(ctx context.Context, out interface{}) (bool, error) {
+
+	if li.err != nil {
+		return false, li.err
+	}
+
+	if li.done {
+		return false, nil
+	}
+
+	var e *errors.Http
+
+	req := li.client.Get(li.url).
+		AddHeader("Content-Type", "application/json")
+
+	meta, err := listPageJSON(req, &li.opts, out, &e)
+	if err != nil {
+		return false, err
+	}
+	if e != nil {
+		return false, errors.New(e.Message)
+	}
+
+	li.meta = meta
+
+	if meta == nil || meta.Continue == "" {
+		li.done = true
+	} else {
+		li.opts.Continue = meta.Continue
+	}
+
+	return true, nil
+}
+
+// Total returns total items count received with the last page
+func (li *ListIterator) Total() int64 {
+	if li.meta == nil {
+		return 0
+	}
+	return li.meta.Total
+}
+
+func newListIterator(client *request.RESTClient, url string, opts *rv1.ListOptions) *ListIterator {
+	li := ListIterator{client: client, url: url}
+	if opts != nil {
+		li.opts = *opts
+	}
+	return &li
+}
+
+// listJSON requests objects list with list options and decodes list items into success
+func listJSON(req *request.Request, opts *rv1.ListOptions, success interface{}, failure interface{}) error {
+	_, err := listPageJSON(req, opts, success, failure)
+	return err
+}
+
+// listPageJSON requests objects list with list options and decodes list items into success.
+// If page is requested, page meta is returned
+func listPageJSON(req *request.Request, opts *rv1.ListOptions, success interface{}, failure interface{}) (*vv1.ListMeta, error) {
+
+	if opts == nil {
+		return nil, req.JSON(success, failure)
+	}
+
+	if opts.LabelSelector != "" {
+		req.Param("labelSelector", opts.LabelSelector)
+	}
+	if opts.FieldSelector != "" {
+		req.Param("fieldSelector", opts.FieldSelector)
+	}
+	if opts.Limit > 0 {
+		req.Param("limit", strconv.FormatInt(opts.Limit, 10))
+	}
+	if opts.Continue != "" {
+		req.Param("continue", opts.Continue)
+	}
+
+	if !opts.Paged() {
+		return nil, req.JSON(success, failure)
+	}
+
+	page := struct {
+		Meta  vv1.ListMeta `json:"meta"`
+		Items interface{}  `json:"items"`
+	}{Items: success}
+
+	if err := req.JSON(&page, failure); err != nil {
+		return nil, err
+	}
+
+	return &page.Meta, nil
+}
@@ -66,6 +66,21 @@ func (nc *NamespaceClient) Config(args ...string) types.ConfigClientV1 {
 	return newConfigClient(nc.client, nc.name, name)
 }
 
+func (nc *NamespaceClient) NetworkPolicy(args ...string) types.NetworkPolicyClientV1 {
+	name := ""
+	// Get any parameters passed to us out of the args variable into "real"
+	// variables we created for them.
+	for i := range args {
+		switch i {
+		case 0: // hostname
+			name = args[0]
+		default:
+			panic("Wrong parameter count: (is allowed from 0 to 1)")
+		}
+	}
+	return newNetworkPolicyClient(nc.client, nc.name, name)
+}
+
 func (nc *NamespaceClient) Service(args ...string) types.ServiceClientV1 {
 	name := ""
 	// Get any parameters passed to us out of the args variable into "real"
@@ -126,14 +141,15 @@ func (nc *NamespaceClient) Volume(args ...string) types.VolumeClientV1 {
 	return newVolumeClient(nc.client, nc.name, name)
 }
 
-func (nc *NamespaceClient) List(ctx context.Context) (*vv1.NamespaceList, error) {
+func (nc *NamespaceClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NamespaceList, error) {
 
 	var s *vv1.NamespaceList
 	var e *errors.Http
 
-	err := nc.client.Get(fmt.Sprintf("/namespace")).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := nc.client.Get(fmt.Sprintf("/namespace")).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -150,6 +166,10 @@ func (nc *NamespaceClient) List(ctx context.Context) (*vv1.NamespaceList, error)
 	return s, nil
 }
 
+func (nc *NamespaceClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(nc.client, fmt.Sprintf("/namespace"), opts)
+}
+
 func (nc *NamespaceClient) Create(ctx context.Context, opts *rv1.NamespaceManifest) (*vv1.Namespace, error) {
 
 	body, err := opts.ToJson()
@@ -200,6 +220,58 @@ func (nc *NamespaceClient) Apply(ctx context.Context, opts *rv1.NamespaceApplyMa
 	return s, nil
 }
 
+// ApplyYaml applies multi-document yaml stream, where every document is an object manifest with kind
+func (nc *NamespaceClient) ApplyYaml(ctx context.Context, data []byte) (*vv1.NamespaceApplyStatus, error) {
+
+	var s *vv1.NamespaceApplyStatus
+	var e *errors.Http
+
+	err := nc.client.Put(fmt.Sprintf("/namespace/%s/apply", nc.name)).
+		AddHeader("Content-Type", "application/yaml").
+		Body(data).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (nc *NamespaceClient) Plan(ctx context.Context, opts *rv1.NamespaceApplyManifest, prune bool) (*vv1.NamespaceApplyPlan, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.NamespaceApplyPlan
+	var e *errors.Http
+
+	req := nc.client.Put(fmt.Sprintf("/namespace/%s/apply", nc.name)).
+		AddHeader("Content-Type", "application/json").
+		Param("dryRun", strconv.FormatBool(true)).
+		Body(body)
+
+	if prune {
+		req.Param("prune", strconv.FormatBool(prune))
+	}
+
+	err = req.JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func (nc *NamespaceClient) Get(ctx context.Context) (*vv1.Namespace, error) {
 
 	var s *vv1.Namespace
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -35,14 +36,15 @@ type NodeClient struct {
 	hostname string
 }
 
-func (nc NodeClient) List(ctx context.Context) (*vv1.NodeList, error) {
+func (nc NodeClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NodeList, error) {
 
 	var s *vv1.NodeList
 	var e *errors.Http
 
-	err := nc.client.Get(fmt.Sprintf("/cluster/node")).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := nc.client.Get(fmt.Sprintf("/cluster/node")).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -54,6 +56,10 @@ func (nc NodeClient) List(ctx context.Context) (*vv1.NodeList, error) {
 	return s, nil
 }
 
+func (nc NodeClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(nc.client, fmt.Sprintf("/cluster/node"), opts)
+}
+
 func (nc NodeClient) Connect(ctx context.Context, opts *rv1.NodeConnectOptions) error {
 
 	body := opts.ToJson()
@@ -21,14 +21,15 @@ package v1
 import (
 	"context"
 	"fmt"
-	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	t "github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http/request"
 )
 
@@ -44,33 +45,20 @@ type PodClient struct {
 	name      string
 }
 
-func (pc *PodClient) List(ctx context.Context) (*vv1.PodList, error) {
+func (pc *PodClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.PodList, error) {
 
 	var s *vv1.PodList
 	var e *errors.Http
 
-	var url string
-
-	switch pc.parent.kind {
-	case types.KindDeployment:
-		dsl := types.DeploymentSelfLink{}
-		if err := dsl.Parse(pc.parent.selflink); err != nil {
-			return nil, err
-		}
-		_, svc := dsl.Parent()
-		url = fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/pod", pc.namespace, svc.Name(), dsl.Name())
-	case types.KindTask:
-		tsl := types.TaskSelfLink{}
-		if err := tsl.Parse(pc.parent.selflink); err != nil {
-			return nil, err
-		}
-		_, job := tsl.Parent()
-		url = fmt.Sprintf("/namespace/%s/job/%s/task/%s/pod", pc.namespace, job.Name(), tsl.Name())
+	url, err := pc.listURL()
+	if err != nil {
+		return nil, err
 	}
 
-	err := pc.client.Get(url).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := pc.client.Get(url).
+		AddHeader("Content-Type", "application/json")
+
+	err = listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -87,6 +75,13 @@ func (pc *PodClient) List(ctx context.Context) (*vv1.PodList, error) {
 	return s, nil
 }
 
+func (pc *PodClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	url, err := pc.listURL()
+	li := newListIterator(pc.client, url, opts)
+	li.err = err
+	return li
+}
+
 func (pc *PodClient) Get(ctx context.Context) (*vv1.Pod, error) {
 
 	var s *vv1.Pod
@@ -95,15 +90,15 @@ func (pc *PodClient) Get(ctx context.Context) (*vv1.Pod, error) {
 	var url string
 
 	switch pc.parent.kind {
-	case types.KindDeployment:
-		dsl := types.DeploymentSelfLink{}
+	case t.KindDeployment:
+		dsl := t.DeploymentSelfLink{}
 		if err := dsl.Parse(pc.parent.selflink); err != nil {
 			return nil, err
 		}
 		_, svc := dsl.Parent()
 		url = fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/pod/%s", pc.namespace, svc.Name(), dsl.Name(), pc.name)
-	case types.KindTask:
-		tsl := types.TaskSelfLink{}
+	case t.KindTask:
+		tsl := t.TaskSelfLink{}
 		if err := tsl.Parse(pc.parent.selflink); err != nil {
 			return nil, err
 		}
@@ -130,16 +125,16 @@ func (pc *PodClient) Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.Rea
 	var url, parent string
 
 	switch pc.parent.kind {
-	case types.KindDeployment:
-		dsl := types.DeploymentSelfLink{}
+	case t.KindDeployment:
+		dsl := t.DeploymentSelfLink{}
 		if err := dsl.Parse(pc.parent.selflink); err != nil {
 			return nil, nil, err
 		}
 		parent = dsl.Name()
 		_, svc := dsl.Parent()
 		url = fmt.Sprintf("/namespace/%s/service/%s/logs", pc.namespace, svc.Name())
-	case types.KindTask:
-		tsl := types.TaskSelfLink{}
+	case t.KindTask:
+		tsl := t.TaskSelfLink{}
 		if err := tsl.Parse(pc.parent.selflink); err != nil {
 			return nil, nil, err
 		}
@@ -153,9 +148,9 @@ func (pc *PodClient) Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.Rea
 	if opts != nil {
 
 		switch pc.parent.kind {
-		case types.KindDeployment:
+		case t.KindDeployment:
 			res.Param("deployment", parent)
-		case types.KindTask:
+		case t.KindTask:
 			res.Param("task", parent)
 		}
 
@@ -170,6 +165,28 @@ func (pc *PodClient) Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.Rea
 	return res.Stream()
 }
 
+func (pc *PodClient) listURL() (string, error) {
+
+	switch pc.parent.kind {
+	case t.KindDeployment:
+		dsl := t.DeploymentSelfLink{}
+		if err := dsl.Parse(pc.parent.selflink); err != nil {
+			return "", err
+		}
+		_, svc := dsl.Parent()
+		return fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/pod", pc.namespace, svc.Name(), dsl.Name()), nil
+	case t.KindTask:
+		tsl := t.TaskSelfLink{}
+		if err := tsl.Parse(pc.parent.selflink); err != nil {
+			return "", err
+		}
+		_, job := tsl.Parent()
+		return fmt.Sprintf("/namespace/%s/job/%s/task/%s/pod", pc.namespace, job.Name(), tsl.Name()), nil
+	}
+
+	return "", nil
+}
+
 func newPodClient(client *request.RESTClient, namespace, kind, parent, name string) *PodClient {
 	pc := PodClient{client: client, namespace: namespace, name: name}
 	pc.parent.kind = kind
@@ -0,0 +1,166 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+)
+
+type NetworkPolicyClient struct {
+	client    *request.RESTClient
+	namespace string
+	name      string
+}
+
+func (sc *NetworkPolicyClient) Create(ctx context.Context, opts *rv1.NetworkPolicyManifest) (*vv1.NetworkPolicy, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.NetworkPolicy
+	var e *errors.Http
+
+	err = sc.client.Post(fmt.Sprintf("/namespace/%s/policy", sc.namespace)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (sc *NetworkPolicyClient) Get(ctx context.Context) (*vv1.NetworkPolicy, error) {
+
+	var s *vv1.NetworkPolicy
+	var e *errors.Http
+
+	err := sc.client.Get(fmt.Sprintf("/namespace/%s/policy/%s", sc.namespace, sc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	if s == nil {
+		s = new(vv1.NetworkPolicy)
+	}
+
+	return s, nil
+}
+
+func (sc *NetworkPolicyClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NetworkPolicyList, error) {
+
+	var s *vv1.NetworkPolicyList
+	var e *errors.Http
+
+	req := sc.client.Get(fmt.Sprintf("/namespace/%s/policy", sc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	if s == nil {
+		list := make(vv1.NetworkPolicyList, 0)
+		s = &list
+	}
+
+	return s, nil
+}
+
+func (sc *NetworkPolicyClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(sc.client, fmt.Sprintf("/namespace/%s/policy", sc.namespace), opts)
+}
+
+func (sc *NetworkPolicyClient) Update(ctx context.Context, opts *rv1.NetworkPolicyManifest) (*vv1.NetworkPolicy, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.NetworkPolicy
+	var e *errors.Http
+
+	err = sc.client.Put(fmt.Sprintf("/namespace/%s/policy/%s", sc.namespace, sc.name)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (sc *NetworkPolicyClient) Remove(ctx context.Context, opts *rv1.NetworkPolicyRemoveOptions) error {
+
+	req := sc.client.Delete(fmt.Sprintf("/namespace/%s/policy/%s", sc.namespace, sc.name)).
+		AddHeader("Content-Type", "application/json")
+
+	if opts != nil {
+		if opts.Force {
+			req.Param("force", strconv.FormatBool(opts.Force))
+		}
+	}
+
+	var e *errors.Http
+
+	if err := req.JSON(nil, &e); err != nil {
+		return err
+	}
+	if e != nil {
+		return errors.New(e.Message)
+	}
+
+	return nil
+}
+
+func newNetworkPolicyClient(client *request.RESTClient, namespace, name string) *NetworkPolicyClient {
+	return &NetworkPolicyClient{client: client, namespace: namespace, name: name}
+}
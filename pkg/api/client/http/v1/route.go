@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -61,14 +62,15 @@ func (rc *RouteClient) Create(ctx context.Context, opts *rv1.RouteManifest) (*vv
 	return s, nil
 }
 
-func (rc *RouteClient) List(ctx context.Context) (*vv1.RouteList, error) {
+func (rc *RouteClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.RouteList, error) {
 
 	var s *vv1.RouteList
 	var e *errors.Http
 
-	err := rc.client.Get(fmt.Sprintf("/namespace/%s/route", rc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := rc.client.Get(fmt.Sprintf("/namespace/%s/route", rc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -85,6 +87,10 @@ func (rc *RouteClient) List(ctx context.Context) (*vv1.RouteList, error) {
 	return s, nil
 }
 
+func (rc *RouteClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(rc.client, fmt.Sprintf("/namespace/%s/route", rc.namespace), opts)
+}
+
 func (rc *RouteClient) Get(ctx context.Context) (*vv1.Route, error) {
 
 	var s *vv1.Route
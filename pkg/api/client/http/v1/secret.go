@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -85,14 +86,15 @@ func (sc *SecretClient) Get(ctx context.Context) (*vv1.Secret, error) {
 	return s, nil
 }
 
-func (sc *SecretClient) List(ctx context.Context) (*vv1.SecretList, error) {
+func (sc *SecretClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.SecretList, error) {
 
 	var s *vv1.SecretList
 	var e *errors.Http
 
-	err := sc.client.Get(fmt.Sprintf("/namespace/%s/secret", sc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := sc.client.Get(fmt.Sprintf("/namespace/%s/secret", sc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -109,6 +111,10 @@ func (sc *SecretClient) List(ctx context.Context) (*vv1.SecretList, error) {
 	return s, nil
 }
 
+func (sc *SecretClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(sc.client, fmt.Sprintf("/namespace/%s/secret", sc.namespace), opts)
+}
+
 func (sc *SecretClient) Update(ctx context.Context, opts *rv1.SecretManifest) (*vv1.Secret, error) {
 
 	body, err := opts.ToJson()
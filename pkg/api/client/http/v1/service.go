@@ -79,14 +79,15 @@ func (sc *ServiceClient) Create(ctx context.Context, opts *rv1.ServiceManifest)
 	return s, nil
 }
 
-func (sc *ServiceClient) List(ctx context.Context) (*vv1.ServiceList, error) {
+func (sc *ServiceClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ServiceList, error) {
 
 	var s *vv1.ServiceList
 	var e *errors.Http
 
-	err := sc.client.Get(fmt.Sprintf("/namespace/%s/service", sc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := sc.client.Get(fmt.Sprintf("/namespace/%s/service", sc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -103,6 +104,10 @@ func (sc *ServiceClient) List(ctx context.Context) (*vv1.ServiceList, error) {
 	return s, nil
 }
 
+func (sc *ServiceClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(sc.client, fmt.Sprintf("/namespace/%s/service", sc.namespace), opts)
+}
+
 func (sc *ServiceClient) Get(ctx context.Context) (*vv1.Service, error) {
 
 	var s *vv1.Service
@@ -54,14 +54,15 @@ func (tc *TaskClient) Pod(args ...string) types.PodClientV1 {
 	return newPodClient(tc.client, tc.namespace.String(), t.KindTask, tc.selflink.String(), name)
 }
 
-func (tc *TaskClient) List(ctx context.Context) (*vv1.TaskList, error) {
+func (tc *TaskClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.TaskList, error) {
 
 	var s *vv1.TaskList
 	var e *errors.Http
 
-	err := tc.client.Get(fmt.Sprintf("/namespace/%s/job/%s/task", tc.namespace.String(), tc.job.Name())).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := tc.client.Get(fmt.Sprintf("/namespace/%s/job/%s/task", tc.namespace.String(), tc.job.Name())).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -78,6 +79,10 @@ func (tc *TaskClient) List(ctx context.Context) (*vv1.TaskList, error) {
 	return s, nil
 }
 
+func (tc *TaskClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(tc.client, fmt.Sprintf("/namespace/%s/job/%s/task", tc.namespace.String(), tc.job.Name()), opts)
+}
+
 func (tc *TaskClient) Create(ctx context.Context, opts *rv1.TaskManifest) (*vv1.Task, error) {
 	body, err := opts.ToJson()
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -61,14 +62,15 @@ func (vc *VolumeClient) Create(ctx context.Context, opts *rv1.VolumeManifest) (*
 	return s, nil
 }
 
-func (vc *VolumeClient) List(ctx context.Context) (*vv1.VolumeList, error) {
+func (vc *VolumeClient) List(ctx context.Context, opts *rv1.ListOptions) (*vv1.VolumeList, error) {
 
 	var s *vv1.VolumeList
 	var e *errors.Http
 
-	err := vc.client.Get(fmt.Sprintf("/namespace/%s/volume", vc.namespace)).
-		AddHeader("Content-Type", "application/json").
-		JSON(&s, &e)
+	req := vc.client.Get(fmt.Sprintf("/namespace/%s/volume", vc.namespace)).
+		AddHeader("Content-Type", "application/json")
+
+	err := listJSON(req, opts, &s, &e)
 
 	if err != nil {
 		return nil, err
@@ -85,6 +87,10 @@ func (vc *VolumeClient) List(ctx context.Context) (*vv1.VolumeList, error) {
 	return s, nil
 }
 
+func (vc *VolumeClient) Iterator(opts *rv1.ListOptions) types.ListIteratorV1 {
+	return newListIterator(vc.client, fmt.Sprintf("/namespace/%s/volume", vc.namespace), opts)
+}
+
 func (vc *VolumeClient) Get(ctx context.Context) (*vv1.Volume, error) {
 
 	var s *vv1.Volume
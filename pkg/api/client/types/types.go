@@ -40,10 +40,12 @@ type ClusterClientV1 interface {
 	API(args ...string) APIClientV1
 	Controller(args ...string) ControllerClientV1
 	Get(ctx context.Context) (*vv1.Cluster, error)
+	IPAM(ctx context.Context) (*vv1.ClusterIPAM, error)
 }
 
 type NodeClientV1 interface {
-	List(ctx context.Context) (*vv1.NodeList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NodeList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Connect(ctx context.Context, opts *rv1.NodeConnectOptions) error
 	Get(ctx context.Context) (*vv1.Node, error)
 	SetStatus(ctx context.Context, opts *rv1.NodeStatusOptions) (*vv1.NodeManifest, error)
@@ -51,21 +53,24 @@ type NodeClientV1 interface {
 }
 
 type DiscoveryClientV1 interface {
-	List(ctx context.Context) (*vv1.DiscoveryList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.DiscoveryList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Discovery, error)
 	Connect(ctx context.Context, opts *rv1.DiscoveryConnectOptions) error
 	SetStatus(ctx context.Context, opts *rv1.DiscoveryStatusOptions) (*vv1.DiscoveryManifest, error)
 }
 
 type IngressClientV1 interface {
-	List(ctx context.Context) (*vv1.IngressList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.IngressList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Ingress, error)
 	Connect(ctx context.Context, opts *rv1.IngressConnectOptions) error
 	SetStatus(ctx context.Context, opts *rv1.IngressStatusOptions) (*vv1.IngressManifest, error)
 }
 
 type ExporterClientV1 interface {
-	List(ctx context.Context) (*vv1.ExporterList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ExporterList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Exporter, error)
 	Connect(ctx context.Context, opts *rv1.ExporterConnectOptions) error
 	SetStatus(ctx context.Context, opts *rv1.ExporterStatusOptions) (*vv1.ExporterManifest, error)
@@ -84,13 +89,17 @@ type ControllerClientV1 interface {
 type NamespaceClientV1 interface {
 	Secret(args ...string) SecretClientV1
 	Config(args ...string) ConfigClientV1
+	NetworkPolicy(args ...string) NetworkPolicyClientV1
 	Service(args ...string) ServiceClientV1
 	Job(args ...string) JobClientV1
 	Route(args ...string) RouteClientV1
 	Volume(args ...string) VolumeClientV1
 	Create(ctx context.Context, opts *rv1.NamespaceManifest) (*vv1.Namespace, error)
 	Apply(ctx context.Context, opts *rv1.NamespaceApplyManifest) (*vv1.NamespaceApplyStatus, error)
-	List(ctx context.Context) (*vv1.NamespaceList, error)
+	ApplyYaml(ctx context.Context, data []byte) (*vv1.NamespaceApplyStatus, error)
+	Plan(ctx context.Context, opts *rv1.NamespaceApplyManifest, prune bool) (*vv1.NamespaceApplyPlan, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NamespaceList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Namespace, error)
 	Update(ctx context.Context, opts *rv1.NamespaceManifest) (*vv1.Namespace, error)
 	Remove(ctx context.Context, opts *rv1.NamespaceRemoveOptions) error
@@ -99,7 +108,8 @@ type NamespaceClientV1 interface {
 type ServiceClientV1 interface {
 	Deployment(args ...string) DeploymentClientV1
 	Create(ctx context.Context, opts *rv1.ServiceManifest) (*vv1.Service, error)
-	List(ctx context.Context) (*vv1.ServiceList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ServiceList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Service, error)
 	Update(ctx context.Context, opts *rv1.ServiceManifest) (*vv1.Service, error)
 	Remove(ctx context.Context, opts *rv1.ServiceRemoveOptions) error
@@ -111,7 +121,8 @@ type JobClientV1 interface {
 
 	Create(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error)
 	Run(ctx context.Context, opts *rv1.TaskManifest) (*vv1.Task, error)
-	List(ctx context.Context) (*vv1.JobList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.JobList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Job, error)
 	Update(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error)
 	Remove(ctx context.Context, opts *rv1.JobRemoveOptions) error
@@ -122,7 +133,8 @@ type TaskClientV1 interface {
 	Pod(args ...string) PodClientV1
 
 	Create(ctx context.Context, opts *rv1.TaskManifest) (*vv1.Task, error)
-	List(ctx context.Context) (*vv1.TaskList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.TaskList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Task, error)
 	Cancel(ctx context.Context, opts *rv1.TaskCancelOptions) (*vv1.Task, error)
 	Remove(ctx context.Context, opts *rv1.TaskRemoveOptions) error
@@ -130,7 +142,8 @@ type TaskClientV1 interface {
 
 type DeploymentClientV1 interface {
 	Pod(args ...string) PodClientV1
-	List(ctx context.Context) (*vv1.DeploymentList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.DeploymentList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Deployment, error)
 	Create(ctx context.Context, opts *rv1.DeploymentManifest) (*vv1.Deployment, error)
 	Update(ctx context.Context, opts *rv1.DeploymentManifest) (*vv1.Deployment, error)
@@ -138,18 +151,25 @@ type DeploymentClientV1 interface {
 }
 
 type PodClientV1 interface {
-	List(ctx context.Context) (*vv1.PodList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.PodList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Pod, error)
 	Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.ReadCloser, *http.Response, error)
 }
 
+type ListIteratorV1 interface {
+	Next(ctx context.Context, out interface{}) (bool, error)
+	Total() int64
+}
+
 type EventsClientV1 interface {
 }
 
 type SecretClientV1 interface {
 	Get(ctx context.Context) (*vv1.Secret, error)
 	Create(ctx context.Context, opts *rv1.SecretManifest) (*vv1.Secret, error)
-	List(ctx context.Context) (*vv1.SecretList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.SecretList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Update(ctx context.Context, opts *rv1.SecretManifest) (*vv1.Secret, error)
 	Remove(ctx context.Context, opts *rv1.SecretRemoveOptions) error
 }
@@ -157,14 +177,25 @@ type SecretClientV1 interface {
 type ConfigClientV1 interface {
 	Get(ctx context.Context) (*vv1.Config, error)
 	Create(ctx context.Context, opts *rv1.ConfigManifest) (*vv1.Config, error)
-	List(ctx context.Context) (*vv1.ConfigList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.ConfigList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Update(ctx context.Context, opts *rv1.ConfigManifest) (*vv1.Config, error)
 	Remove(ctx context.Context, opts *rv1.ConfigRemoveOptions) error
 }
 
+type NetworkPolicyClientV1 interface {
+	Get(ctx context.Context) (*vv1.NetworkPolicy, error)
+	Create(ctx context.Context, opts *rv1.NetworkPolicyManifest) (*vv1.NetworkPolicy, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.NetworkPolicyList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
+	Update(ctx context.Context, opts *rv1.NetworkPolicyManifest) (*vv1.NetworkPolicy, error)
+	Remove(ctx context.Context, opts *rv1.NetworkPolicyRemoveOptions) error
+}
+
 type RouteClientV1 interface {
 	Create(ctx context.Context, opts *rv1.RouteManifest) (*vv1.Route, error)
-	List(ctx context.Context) (*vv1.RouteList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.RouteList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Route, error)
 	Update(ctx context.Context, opts *rv1.RouteManifest) (*vv1.Route, error)
 	Remove(ctx context.Context, opts *rv1.RouteRemoveOptions) error
@@ -172,7 +203,8 @@ type RouteClientV1 interface {
 
 type VolumeClientV1 interface {
 	Create(ctx context.Context, opts *rv1.VolumeManifest) (*vv1.Volume, error)
-	List(ctx context.Context) (*vv1.VolumeList, error)
+	List(ctx context.Context, opts *rv1.ListOptions) (*vv1.VolumeList, error)
+	Iterator(opts *rv1.ListOptions) ListIteratorV1
 	Get(ctx context.Context) (*vv1.Volume, error)
 	Update(ctx context.Context, opts *rv1.VolumeManifest) (*vv1.Volume, error)
 	Remove(ctx context.Context, opts *rv1.VolumeRemoveOptions) error
@@ -82,3 +82,45 @@ func ClusterInfoH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func ClusterIPAMH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /cluster/ipam cluster clusterIPAM
+	//
+	// Shows utilization of cluster IPAM pools
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: Cluster IPAM response
+	//     schema:
+	//       "$ref": "#/definitions/views_cluster_ipam"
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:ipam:> get cluster ipam utilization", logPrefix)
+
+	var im = distribution.NewIPAMModel(r.Context(), envs.Get().GetStorage())
+
+	u, err := im.Utilization()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> get ipam utilization err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Cluster().NewIPAM(u).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
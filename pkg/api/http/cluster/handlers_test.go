@@ -26,12 +26,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/http/cluster"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 	"github.com/stretchr/testify/assert"
@@ -143,6 +145,59 @@ func TestClusterInfo(t *testing.T) {
 	}
 }
 
+// Testing ClusterIPAMH handler
+func TestClusterIPAM(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	im := distribution.NewIPAMModel(context.Background(), stg)
+
+	pool := &types.IPAMPool{Name: types.IPAMPoolService, CIDR: "10.0.0.0/24", Prefix: 32, Size: 255}
+	if !assert.NoError(t, im.PoolSet(pool)) {
+		return
+	}
+
+	for _, addr := range []string{"10.0.0.1", "10.0.0.2"} {
+		l := &types.IPAMLease{Pool: types.IPAMPoolService, Addr: addr, Owner: "ns:svc", Created: time.Now()}
+		if !assert.NoError(t, im.LeasePut(l)) {
+			return
+		}
+	}
+
+	req, err := http.NewRequest("GET", "/cluster/ipam", nil)
+	assert.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/cluster/ipam", cluster.ClusterIPAMH)
+
+	setRequestVars(r, req)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	if !assert.Equal(t, http.StatusOK, res.Code, "status code not equal") {
+		return
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	ci := make(views.ClusterIPAM, 0)
+	if !assert.NoError(t, json.Unmarshal(body, &ci)) {
+		return
+	}
+
+	if assert.Contains(t, ci, types.IPAMPoolService, "pool should be in response") {
+		assert.Equal(t, "10.0.0.0/24", ci[types.IPAMPoolService].CIDR, "cidr not equal")
+		assert.Equal(t, 2, ci[types.IPAMPoolService].Leased, "leased count not equal")
+		assert.Equal(t, 253, ci[types.IPAMPoolService].Available, "available count not equal")
+	}
+}
+
 func getClusterAsset(memory int64) *types.Cluster {
 	var c = types.Cluster{}
 	c.Meta.SelfLink = *types.NewClusterSelfLink(types.EmptyString)
@@ -26,4 +26,5 @@ import (
 var Routes = []http.Route{
 	// Cluster handlers
 	{Path: "/cluster", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ClusterInfoH},
+	{Path: "/cluster/ipam", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ClusterIPAMH},
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"net/http"
 )
 
@@ -90,13 +91,7 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.ConfigManifest
 		}
 	}
 
-	cfg := new(types.Config)
-	cfg.Meta.SetDefault()
-	cfg.Meta.Namespace = ns.Meta.Name
-	cfg.Meta.SelfLink = *types.NewConfigSelfLink(ns.Meta.Name, *mf.Meta.Name)
-
-	mf.SetConfigMeta(cfg)
-	mf.SetConfigSpec(cfg)
+	cfg := prepare(ns, nil, mf)
 
 	if _, err := cm.Create(ns, cfg); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create config err: %s", logPrefix, ns.Meta.Name, err.Error())
@@ -110,13 +105,74 @@ func Update(ctx context.Context, ns *types.Namespace, cfg *types.Config, mf *req
 
 	cm := distribution.NewConfigModel(ctx, envs.Get().GetStorage())
 
-	mf.SetConfigMeta(cfg)
-	mf.SetConfigSpec(cfg)
+	cfg = prepare(ns, cfg, mf)
 
-	if _, err := cm.Update(cfg); err != nil {
+	if _, err := cm.Update(cfg, &types.UpdateOptions{Revision: &cfg.Storage.Revision}); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update config err: %s", logPrefix, err.Error())
+			return nil, errors.New("config").Conflict()
+		}
 		log.V(logLevel).Errorf("%s:update:> update config err: %s", logPrefix, err.Error())
 		return nil, errors.New("config").InternalServerError()
 	}
 
 	return cfg, nil
 }
+
+// Plan returns the stored config and the config as it will be after the manifest is applied.
+// Nothing is saved to storage, the stored config is nil if it does not exist yet.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.ConfigManifest) (*types.Config, *types.Config, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("config").BadParameter("meta.name")
+	}
+
+	cm := distribution.NewConfigModel(ctx, envs.Get().GetStorage())
+
+	cfg, err := cm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get config by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("config").InternalServerError()
+	}
+
+	var next *types.Config
+
+	if cfg != nil {
+		next = new(types.Config)
+		if err := compare.Copy(cfg, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy config `%s` err: %s", logPrefix, cfg.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("config").InternalServerError()
+		}
+	}
+
+	return cfg, prepare(ns, next, mf), nil
+}
+
+// prepare applies manifest to stored config or to a new one if cfg is nil,
+// apply and plan share it to get the same config
+func prepare(ns *types.Namespace, cfg *types.Config, mf *request.ConfigManifest) *types.Config {
+
+	if cfg == nil {
+		cfg = new(types.Config)
+		cfg.Meta.SetDefault()
+		cfg.Meta.Namespace = ns.Meta.Name
+		cfg.Meta.SelfLink = *types.NewConfigSelfLink(ns.Meta.Name, *mf.Meta.Name)
+	}
+
+	mf.SetConfigMeta(cfg)
+	mf.SetConfigSpec(cfg)
+
+	return cfg
+}
+
+func Remove(ctx context.Context, cfg *types.Config) *errors.Err {
+
+	cm := distribution.NewConfigModel(ctx, envs.Get().GetStorage())
+
+	if err := cm.Remove(cfg); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove config `%s` err: %s", logPrefix, cfg.SelfLink().String(), err.Error())
+		return errors.New("config").InternalServerError()
+	}
+
+	return nil
+}
@@ -117,6 +117,26 @@ func ConfigListH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Config list response
@@ -129,6 +149,14 @@ func ConfigListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get configs list", logPrefix)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		nid = utils.Vars(r)["namespace"]
 
@@ -141,8 +169,18 @@ func ConfigListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := rm.List(ns.Meta.Name)
+	items, err := rm.List(ns.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> find config list err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -155,6 +193,15 @@ func ConfigListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(items.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -253,6 +300,11 @@ func ConfigUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: config id
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -265,6 +317,8 @@ func ConfigUpdateH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/views_config"
 	//   '404':
 	//     description: Namespace not found / Config not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -296,6 +350,12 @@ func ConfigUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, cfg.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> config `%s` version mismatch", logPrefix, cid)
+		errors.HTTP.Conflict(w, "config")
+		return
+	}
+
 	cfg, e = config.Update(r.Context(), ns, cfg, opts)
 	if e != nil {
 		e.Http(w)
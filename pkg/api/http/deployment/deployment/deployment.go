@@ -124,7 +124,11 @@ func Update(ctx context.Context, ns *types.Namespace, svc *types.Service, dep *t
 		return nil, errors.New("service").BadRequest(err.Error())
 	}
 
-	if err := dm.Update(dep); err != nil {
+	if err := dm.Update(dep, &types.UpdateOptions{Revision: &dep.Storage.Revision}); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update deployment err: %s", logPrefix, err.Error())
+			return nil, errors.New("deployment").Conflict()
+		}
 		log.V(logLevel).Errorf("%s:update:> update service err: %s", logPrefix, err.Error())
 		return nil, errors.New("service").InternalServerError()
 	}
@@ -58,6 +58,26 @@ func DeploymentListH(w http.ResponseWriter, r *http.Request) {
 	//     description: name of the service
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Deployment list response
@@ -73,6 +93,14 @@ func DeploymentListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get deployments list for `%s/%s`", logPrefix, sid, nid)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
 		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
@@ -104,8 +132,18 @@ func DeploymentListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dl, err := dm.ListByService(srv.Meta.Namespace, srv.Meta.Name)
+	dl, err := dm.ListByService(srv.Meta.Namespace, srv.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get deployment list by service id `%s` err: %s", logPrefix, srv.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -118,6 +156,15 @@ func DeploymentListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(dl.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -337,6 +384,11 @@ func DeploymentUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: name of the deployment
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -349,6 +401,8 @@ func DeploymentUpdateH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/views_deployment"
 	//   '404':
 	//     description: Namespace not found / Service not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -388,6 +442,12 @@ func DeploymentUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, dp.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> deployment `%s` version mismatch", logPrefix, did)
+		errors.HTTP.Conflict(w, "deployment")
+		return
+	}
+
 	dp, e = deployment.Update(r.Context(), ns, svc, dp, opts, &request.DeploymentUpdateOptions{})
 	if e != nil {
 		e.Http(w)
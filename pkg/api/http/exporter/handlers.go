@@ -103,6 +103,27 @@ func ExporterListH(w http.ResponseWriter, r *http.Request) {
 	// ---
 	// produces:
 	// - application/json
+	// parameters:
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Exporter list response
@@ -113,12 +134,30 @@ func ExporterListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get exporters list", logPrefix)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		im = distribution.NewExporterModel(r.Context(), envs.Get().GetStorage())
 	)
 
-	exporters, err := im.List()
+	exporters, err := im.List(opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get exporters list err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -131,6 +170,15 @@ func ExporterListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(exporters.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
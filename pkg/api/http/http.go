@@ -32,6 +32,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/api/http/namespace"
 	"github.com/lastbackend/lastbackend/pkg/api/http/node"
 	"github.com/lastbackend/lastbackend/pkg/api/http/pod"
+	"github.com/lastbackend/lastbackend/pkg/api/http/policy"
 	"github.com/lastbackend/lastbackend/pkg/api/http/route"
 	"github.com/lastbackend/lastbackend/pkg/api/http/secret"
 	"github.com/lastbackend/lastbackend/pkg/api/http/service"
@@ -79,6 +80,7 @@ func init() {
 	AddRoutes(namespace.Routes)
 	AddRoutes(secret.Routes)
 	AddRoutes(config.Routes)
+	AddRoutes(policy.Routes)
 	AddRoutes(route.Routes)
 	AddRoutes(service.Routes)
 	AddRoutes(deployment.Routes)
@@ -54,6 +54,26 @@ func JobListH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Task list response
@@ -68,6 +88,14 @@ func JobListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> list jobs in %s", logPrefix, nid)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		stg = envs.Get().GetStorage()
 		jm  = distribution.NewJobModel(r.Context(), stg)
@@ -79,8 +107,18 @@ func JobListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobs, err := jm.ListByNamespace(ns.Meta.Name)
+	jobs, err := jm.ListByNamespace(ns.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get job list in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -93,6 +131,15 @@ func JobListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(jobs.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -254,6 +301,11 @@ func JobUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: job id
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -266,6 +318,8 @@ func JobUpdateH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/views_job"
 	//   '404':
 	//     description: Namespace not found / Job not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -294,6 +348,12 @@ func JobUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, jb.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> job `%s` version mismatch", logPrefix, sid)
+		errors.HTTP.Conflict(w, "job")
+		return
+	}
+
 	jb, e = job.Update(r.Context(), ns, jb, opts)
 	if e != nil {
 		e.Http(w)
@@ -465,7 +525,7 @@ func JobLogsH(w http.ResponseWriter, r *http.Request) {
 
 	var task *types.Task
 	if tid == types.EmptyString {
-		tl, err := tm.ListByNamespace(ns.SelfLink().String())
+		tl, err := tm.ListByNamespace(ns.SelfLink().String(), nil)
 		if err != nil {
 			log.V(logLevel).Errorf("%s:logs:> get task list `%s` err: %s", logPrefix, err.Error())
 			errors.HTTP.InternalServerError(w)
@@ -538,7 +598,7 @@ func JobLogsH(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	el, err := em.List()
+	el, err := em.List(nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:logs:> get exporters", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
@@ -26,6 +26,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
 	"net/http"
 )
@@ -76,7 +77,6 @@ func Apply(ctx context.Context, ns *types.Namespace, mf *request.JobManifest) (*
 func Create(ctx context.Context, ns *types.Namespace, mf *request.JobManifest) (*types.Job, *errors.Err) {
 
 	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
-	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
 
 	if mf.Meta.Name != nil {
 
@@ -94,17 +94,141 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.JobManifest) (
 		}
 	}
 
-	job := new(types.Job)
-	mf.SetJobMeta(job)
-	job.Meta.SelfLink = *types.NewJobSelfLink(ns.Meta.Name, *mf.Meta.Name)
-	job.Meta.Namespace = ns.Meta.Name
-	job.Status.State = types.StateCreated
+	allocated := ns.Status.Resources.Allocated
+
+	job, e := prepare(ctx, ns, nil, mf, 0)
+	if e != nil {
+		return nil, e
+	}
+
+	// resources are reserved in namespace before job is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
+
+	job, err := jm.Create(job)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create job err: %s", logPrefix, err.Error())
+		release(ctx, ns, change)
+		return nil, errors.New("job").InternalServerError()
+	}
+
+	return job, nil
+}
+
+func Update(ctx context.Context, ns *types.Namespace, job *types.Job, mf *request.JobManifest) (*types.Job, *errors.Err) {
+
+	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
+
+	allocated := ns.Status.Resources.Allocated
+
+	job, e := prepare(ctx, ns, job, mf, 0)
+	if e != nil {
+		return nil, e
+	}
+
+	// resources are reserved in namespace before job is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
+
+	if err := jm.Set(job, &types.UpdateOptions{Revision: &job.Storage.Revision}); err != nil {
+		release(ctx, ns, change)
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update job err: %s", logPrefix, err.Error())
+			return nil, errors.New("job").Conflict()
+		}
+		log.V(logLevel).Errorf("%s:update:> update job err: %s", logPrefix, err.Error())
+		return nil, errors.New("job").InternalServerError()
+	}
+
+	return job, nil
+}
+
+// Plan returns the stored job and the job as it will be after the manifest is applied.
+// Nothing is saved to storage, but resources for the job are allocated in the passed namespace,
+// so several planned jobs are checked against namespace limits together.
+// Planned is count of jobs planned for creation in namespace before this one.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.JobManifest, planned int) (*types.Job, *types.Job, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("job").BadParameter("meta.name")
+	}
+
+	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
+
+	job, err := jm.Get(types.NewJobSelfLink(ns.Meta.Name, *mf.Meta.Name).String())
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get job by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("job").InternalServerError()
+	}
+
+	var next *types.Job
+
+	if job != nil {
+		next = new(types.Job)
+		if err := compare.Copy(job, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy job `%s` err: %s", logPrefix, job.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("job").InternalServerError()
+		}
+	}
+
+	next, e := prepare(ctx, ns, next, mf, planned)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	return job, next, nil
+}
+
+// prepare applies manifest to stored job or to a new one if job is nil.
+// Job is checked against namespace quotas and security policy and its resources are allocated in namespace,
+// apply and plan share it to fail the same way. Planned jobs are counted in quota of new job.
+func prepare(ctx context.Context, ns *types.Namespace, job *types.Job, mf *request.JobManifest, planned int) (*types.Job, *errors.Err) {
+
+	var (
+		exists    = job != nil
+		resources types.ResourceRequest
+	)
+
+	if !exists {
+
+		if ns.Spec.Quotas.Jobs > 0 {
+			jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
+			jl, err := jm.ListByNamespace(ns.Meta.Name, nil)
+			if err != nil {
+				log.V(logLevel).Errorf("%s:prepare:> get jobs in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+				return nil, errors.New("job").InternalServerError()
+			}
+
+			if err := ns.ValidateQuota(types.KindJob, len(jl.Items)+planned); err != nil {
+				log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+				return nil, errors.New("job").BadRequest(err.Error())
+			}
+		}
+
+		job = new(types.Job)
+		mf.SetJobMeta(job)
+		job.Meta.SelfLink = *types.NewJobSelfLink(ns.Meta.Name, *mf.Meta.Name)
+		job.Meta.Namespace = ns.Meta.Name
+		job.Status.State = types.StateCreated
+	} else {
+		resources = job.Spec.GetResourceRequest()
+		mf.SetJobMeta(job)
+	}
 
 	if err := mf.SetJobSpec(job); err != nil {
 		return nil, errors.New("job").BadRequest(err.Error())
 	}
 
-	if ns.Spec.Resources.Limits.RAM != 0 || ns.Spec.Resources.Limits.CPU != 0 {
+	if err := ns.ValidateSecurity(job.Spec.Task.Template); err != nil {
+		log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+		return nil, errors.New("job").Forbidden(err).SetMessage(err.Error())
+	}
+
+	if !exists && (ns.Spec.Resources.Limits.RAM != 0 || ns.Spec.Resources.Limits.CPU != 0) {
 		for _, c := range job.Spec.Task.Template.Containers {
 			if c.Resources.Limits.RAM == 0 {
 				c.Resources.Limits.RAM, _ = resource.DecodeMemoryResource(types.DEFAULT_RESOURCE_LIMITS_RAM)
@@ -115,60 +239,73 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.JobManifest) (
 		}
 	}
 
-	if err := ns.AllocateResources(job.Spec.GetResourceRequest()); err != nil {
-		log.V(logLevel).Errorf("%s:create:> %s", logPrefix, err.Error())
-		return nil, errors.New("job").BadRequest(err.Error())
+	requested := job.Spec.GetResourceRequest()
 
-	} else {
-		if err := nm.Update(ns); err != nil {
-			log.V(logLevel).Errorf("%s:create:> update namespace err: %s", logPrefix, err.Error())
-			return nil, errors.New("job").InternalServerError()
-		}
-	}
+	if !exists || !resources.Equal(requested) {
 
-	job, err := jm.Create(job)
-	if err != nil {
-		log.V(logLevel).Errorf("%s:create:> create job err: %s", logPrefix, err.Error())
-		return nil, errors.New("job").InternalServerError()
+		allocated := ns.Status.Resources.Allocated
+		ns.ReleaseResources(resources)
+
+		if err := ns.AllocateResources(requested); err != nil {
+			ns.Status.Resources.Allocated = allocated
+			log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+			return nil, errors.New("job").BadRequest(err.Error())
+		}
 	}
 
 	return job, nil
 }
 
-func Update(ctx context.Context, ns *types.Namespace, job *types.Job, mf *request.JobManifest) (*types.Job, *errors.Err) {
+// allocate stores change of namespace resources allocation made by prepare,
+// namespace is written with compare and swap, so concurrent allocations are not lost
+func allocate(ctx context.Context, ns *types.Namespace, change types.ResourceItem) *errors.Err {
+
+	if change == (types.ResourceItem{}) {
+		return nil
+	}
 
-	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
 	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
 
-	resources := job.Spec.GetResourceRequest()
+	if err := nm.Allocate(ns, change); err != nil {
+		if errors.IsResourcesLimitExceeded(err) {
+			log.V(logLevel).Warnf("%s:allocate:> %s", logPrefix, err.Error())
+			return errors.New("job").BadRequest(err.Error())
+		}
+		log.V(logLevel).Errorf("%s:allocate:> update namespace err: %s", logPrefix, err.Error())
+		return errors.New("job").InternalServerError()
+	}
 
-	mf.SetJobMeta(job)
-	if err := mf.SetJobSpec(job); err != nil {
-		return nil, errors.New("job").BadRequest(err.Error())
+	return nil
+}
+
+// release rolls back change of namespace resources allocation if job was not saved
+func release(ctx context.Context, ns *types.Namespace, change types.ResourceItem) {
+
+	if change == (types.ResourceItem{}) {
+		return
 	}
 
-	requestedResources := job.Spec.GetResourceRequest()
-	if !resources.Equal(requestedResources) {
-		allocatedResources := ns.Status.Resources.Allocated
-		ns.ReleaseResources(resources)
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
 
-		if err := ns.AllocateResources(job.Spec.GetResourceRequest()); err != nil {
-			ns.Status.Resources.Allocated = allocatedResources
-			log.V(logLevel).Errorf("%s:update:> %s", logPrefix, err.Error())
-			return nil, errors.New("job").BadRequest(err.Error())
-		} else {
-			if err := nm.Update(ns); err != nil {
-				log.V(logLevel).Errorf("%s:update:> update namespace err: %s", logPrefix, err.Error())
-				return nil, errors.New("job").InternalServerError()
-			}
+	revert := types.ResourceItem{}.Diff(change)
+	if err := ns.AllocateChange(revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> release namespace resources err: %s", logPrefix, err.Error())
+		return
+	}
 
-		}
+	if err := nm.Allocate(ns, revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> update namespace err: %s", logPrefix, err.Error())
 	}
+}
 
-	if err := jm.Set(job); err != nil {
-		log.V(logLevel).Errorf("%s:update:> update job err: %s", logPrefix, err.Error())
-		return nil, errors.New("job").InternalServerError()
+func Remove(ctx context.Context, job *types.Job) *errors.Err {
+
+	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
+
+	if _, err := jm.Destroy(job); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove job `%s` err: %s", logPrefix, job.SelfLink().String(), err.Error())
+		return errors.New("job").InternalServerError()
 	}
 
-	return job, nil
+	return nil
 }
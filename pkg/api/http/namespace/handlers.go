@@ -55,6 +55,27 @@ func NamespaceListH(w http.ResponseWriter, r *http.Request) {
 	// ---
 	// produces:
 	// - application/json
+	// parameters:
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Environment list response
@@ -65,12 +86,30 @@ func NamespaceListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get namespace list", logPrefix)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
 	)
 
-	items, err := nsm.List()
+	items, err := nsm.List(opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> find p list err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -83,6 +122,15 @@ func NamespaceListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(items.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err = w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -252,6 +300,11 @@ func NamespaceUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -264,6 +317,8 @@ func NamespaceUpdateH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/views_namespace"
 	//   '404':
 	//     description: Namespace not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -290,6 +345,12 @@ func NamespaceUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, ns.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> namespace `%s` version mismatch", logPrefix, nid)
+		errors.HTTP.Conflict(w, "namespace")
+		return
+	}
+
 	opts.SetNamespaceMeta(ns)
 	if err := opts.SetNamespaceSpec(ns); err != nil {
 		log.V(logLevel).Errorf("%s:create:> set namespace spec err: %s", logPrefix, err.Error())
@@ -297,6 +358,12 @@ func NamespaceUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := ns.ValidateResources(); err != nil {
+		log.V(logLevel).Warnf("%s:update:> %s", logPrefix, err.Error())
+		errors.HTTP.BadRequest(w, err.Error())
+		return
+	}
+
 	internal, _ := envs.Get().GetDomain()
 	ns.Meta.Endpoint = strings.ToLower(fmt.Sprintf("%s.%s", ns.Meta.Name, internal))
 
@@ -312,7 +379,12 @@ func NamespaceUpdateH(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := nsm.Update(ns); err != nil {
+	if err := nsm.Update(ns, &types.UpdateOptions{Revision: &ns.Storage.Revision}); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update namespace `%s` err: %s", logPrefix, nid, err.Error())
+			errors.HTTP.Conflict(w, "namespace")
+			return
+		}
 		log.V(logLevel).Errorf("%s:update:> update namespace `%s` err: %s", logPrefix, nid, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -339,6 +411,9 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 	// Update namespace parameters
 	//
 	// ---
+	// consumes:
+	// - application/json
+	// - application/yaml
 	// produces:
 	// - application/json
 	// parameters:
@@ -347,6 +422,14 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: dryRun
+	//     in: query
+	//     description: validate manifest and return planned actions without applying them
+	//     type: boolean
+	//   - name: prune
+	//     in: query
+	//     description: remove namespace objects missing in manifest
+	//     type: boolean
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -354,7 +437,7 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/request_namespace_apply"
 	// responses:
 	//   '200':
-	//     description: Environment was successfully updated
+	//     description: Environment was successfully updated or apply plan if dryRun is set
 	//     schema:
 	//       "$ref": "#/definitions/views_namespace"
 	//   '404':
@@ -364,6 +447,8 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 
 	nid := utils.Vars(r)["namespace"]
 	redeploy := utils.QueryBool(r, "redeploy")
+	dryRun := utils.QueryBool(r, "dryRun")
+	prune := utils.QueryBool(r, "prune")
 
 	log.V(logLevel).Debugf("%s:apply:> apply namespace %s", logPrefix, nid)
 
@@ -371,8 +456,13 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 		opts = v1.Request().Namespace().ApplyManifest()
 	)
 
-	// request body struct
-	e := opts.DecodeAndValidate(r.Body)
+	// request body struct, yaml body is a multi-document stream of objects manifests
+	var e *errors.Err
+	if utils.IsYaml(r) {
+		e = opts.DecodeYamlAndValidate(r.Body)
+	} else {
+		e = opts.DecodeAndValidate(r.Body)
+	}
 	if e != nil {
 		log.V(logLevel).Errorf("%s:apply:> validation incoming data err: %s", logPrefix, e.Err())
 		e.Http(w)
@@ -483,6 +573,31 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 		status.Routes[fmt.Sprintf("%s:%s", ns.SelfLink(), *m.Meta.Name)] = false
 	}
 
+	var plan *types.NamespaceApplyPlan
+
+	if dryRun || prune {
+		plan, e = namespace.Plan(r.Context(), ns, opts, prune)
+		if e != nil {
+			e.Http(w)
+			return
+		}
+	}
+
+	if dryRun {
+		response, err := v1.View().Namespace().NewApplyPlan(plan).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:apply:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(response); err != nil {
+			log.V(logLevel).Errorf("%s:apply:> write response err: %s", logPrefix, err.Error())
+		}
+		return
+	}
+
 	for _, m := range opts.Configs {
 		c, e := config.Apply(r.Context(), ns, m)
 		if e != nil {
@@ -537,6 +652,13 @@ func NamespaceApplyH(w http.ResponseWriter, r *http.Request) {
 		status.Jobs[j.SelfLink().String()] = true
 	}
 
+	if prune {
+		if e := namespace.Prune(r.Context(), ns, plan); e != nil {
+			e.Http(w)
+			return
+		}
+	}
+
 	response, err := v1.View().Namespace().NewApplyStatus(status).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:apply:> convert struct to json err: %s", logPrefix, err.Error())
@@ -603,7 +725,7 @@ func NamespaceRemoveH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exists, err := sm.List(ns.Meta.Name)
+	exists, err := sm.List(ns.Meta.Name, nil)
 	if len(exists.Items) > 0 {
 		errors.New("namespace").Forbidden().Http(w)
 		return
@@ -568,6 +568,410 @@ func TestNamespaceUpdate(t *testing.T) {
 
 }
 
+// Testing NamespaceApplyH handler
+func TestNamespaceApply(t *testing.T) {
+
+	var ctx = context.Background()
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+
+	cfgKeep := getConfigAsset(ns1.Meta.Name, "keep", map[string]string{"key": "a"})
+	cfgEdit := getConfigAsset(ns1.Meta.Name, "edit", map[string]string{"key": "a"})
+	cfgStale := getConfigAsset(ns1.Meta.Name, "stale", map[string]string{"key": "a"})
+	cfgFresh := getConfigAsset(ns1.Meta.Name, "fresh", map[string]string{"key": "a"})
+
+	sct := new(types.Secret)
+	sct.Meta.Name = "token"
+	sct.Meta.Namespace = ns1.Meta.Name
+	sct.Meta.SelfLink = *types.NewSecretSelfLink(ns1.Meta.Name, sct.Meta.Name)
+	sct.Spec.Data = map[string][]byte{"key": []byte("b2xk")}
+
+	mf := request.NamespaceApplyManifest{
+		Configs: map[string]*request.ConfigManifest{
+			"keep":  getConfigManifest("keep", map[string]string{"key": "a"}),
+			"edit":  getConfigManifest("edit", map[string]string{"key": "b"}),
+			"fresh": getConfigManifest("fresh", map[string]string{"key": "a"}),
+		},
+		Secrets: map[string]*request.SecretManifest{
+			"token": {
+				Meta: request.SecretManifestMeta{RuntimeMeta: request.RuntimeMeta{Name: getStrPtr("token")}},
+				Spec: request.SecretManifestSpec{Data: map[string]string{"key": "new"}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(mf)
+	assert.NoError(t, err)
+
+	type fields struct {
+		stg storage.Storage
+	}
+
+	type args struct {
+		ctx       context.Context
+		namespace *types.Namespace
+	}
+
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		query        string
+		handler      func(http.ResponseWriter, *http.Request)
+		data         string
+		want         *views.NamespaceApplyPlan
+		exists       []*types.Config
+		removed      []*types.Config
+		expectedCode int
+	}{
+		{
+			name:    "checking dry run apply",
+			args:    args{ctx, ns1},
+			fields:  fields{stg},
+			query:   "?dryRun=true",
+			handler: namespace.NamespaceApplyH,
+			data:    string(data),
+			want: &views.NamespaceApplyPlan{
+				Configs: map[string]*views.NamespaceApplyStep{
+					cfgKeep.SelfLink().String(): {Action: types.ApplyActionUnchanged},
+					cfgEdit.SelfLink().String(): {Action: types.ApplyActionUpdate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key", From: "a", To: "b"},
+					}},
+					cfgFresh.SelfLink().String(): {Action: types.ApplyActionCreate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key", To: "a"},
+					}},
+				},
+				Secrets: map[string]*views.NamespaceApplyStep{
+					sct.SelfLink().String(): {Action: types.ApplyActionUpdate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key"},
+					}},
+				},
+			},
+			exists:       []*types.Config{cfgKeep, cfgEdit, cfgStale},
+			removed:      []*types.Config{cfgFresh},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:    "checking dry run apply with prune",
+			args:    args{ctx, ns1},
+			fields:  fields{stg},
+			query:   "?dryRun=true&prune=true",
+			handler: namespace.NamespaceApplyH,
+			data:    string(data),
+			want: &views.NamespaceApplyPlan{
+				Configs: map[string]*views.NamespaceApplyStep{
+					cfgKeep.SelfLink().String(): {Action: types.ApplyActionUnchanged},
+					cfgEdit.SelfLink().String(): {Action: types.ApplyActionUpdate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key", From: "a", To: "b"},
+					}},
+					cfgFresh.SelfLink().String(): {Action: types.ApplyActionCreate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key", To: "a"},
+					}},
+					cfgStale.SelfLink().String(): {Action: types.ApplyActionDelete, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key", From: "a"},
+					}},
+				},
+				Secrets: map[string]*views.NamespaceApplyStep{
+					sct.SelfLink().String(): {Action: types.ApplyActionUpdate, Diff: []*views.NamespaceApplyChange{
+						{Path: "data.key"},
+					}},
+				},
+			},
+			exists:       []*types.Config{cfgKeep, cfgEdit, cfgStale},
+			removed:      []*types.Config{cfgFresh},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking apply with prune",
+			args:         args{ctx, ns1},
+			fields:       fields{stg},
+			query:        "?prune=true",
+			handler:      namespace.NamespaceApplyH,
+			data:         string(data),
+			exists:       []*types.Config{cfgKeep, cfgEdit, cfgFresh},
+			removed:      []*types.Config{cfgStale},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Config(), types.EmptyString)
+		assert.NoError(t, err)
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Secret(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+			assert.NoError(t, err)
+
+			for _, c := range []*types.Config{cfgKeep, cfgEdit, cfgStale} {
+				err = tc.fields.stg.Put(context.Background(), stg.Collection().Config(), c.SelfLink().String(), c, nil)
+				assert.NoError(t, err)
+			}
+
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Secret(), sct.SelfLink().String(), sct, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("PUT", fmt.Sprintf("/namespace/%s/apply%s", tc.args.namespace.Meta.Name, tc.query), strings.NewReader(tc.data))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/apply", tc.handler)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			if tc.want != nil {
+				got := new(views.NamespaceApplyPlan)
+				err := json.Unmarshal(body, got)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tc.want.Configs, got.Configs, "configs plan not equal")
+				assert.Equal(t, tc.want.Secrets, got.Secrets, "secrets plan not equal")
+			}
+
+			for _, c := range tc.exists {
+				got := new(types.Config)
+				err := tc.fields.stg.Get(context.Background(), stg.Collection().Config(), c.SelfLink().String(), got, nil)
+				assert.NoError(t, err, fmt.Sprintf("config %s should exist", c.Meta.Name))
+			}
+
+			for _, c := range tc.removed {
+				got := new(types.Config)
+				err := tc.fields.stg.Get(context.Background(), stg.Collection().Config(), c.SelfLink().String(), got, nil)
+				assert.True(t, errors.Storage().IsErrEntityNotFound(err), fmt.Sprintf("config %s should not exist", c.Meta.Name))
+			}
+		})
+	}
+
+}
+
+// Testing NamespaceApplyH handler with yaml manifests stream
+// Testing NamespaceApplyH handler quotas for objects created by manifest
+func TestNamespaceApplyQuota(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+	ns1.Spec.Quotas.Volumes = 1
+
+	mf := request.NamespaceApplyManifest{
+		Volumes: map[string]*request.VolumeManifest{
+			"data": {Meta: request.VolumeManifestMeta{RuntimeMeta: request.RuntimeMeta{Name: getStrPtr("data")}}},
+			"logs": {Meta: request.VolumeManifestMeta{RuntimeMeta: request.RuntimeMeta{Name: getStrPtr("logs")}}},
+		},
+	}
+
+	data, err := json.Marshal(mf)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{
+			name:         "checking dry run apply over quota",
+			query:        "?dryRun=true",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking apply over quota",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Volume(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("PUT", fmt.Sprintf("/namespace/%s/apply%s", ns1.Meta.Name, tc.query), strings.NewReader(string(data)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/apply", namespace.NamespaceApplyH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			assert.Equal(t, tc.expectedCode, res.Code, "status code not equal")
+		})
+	}
+}
+
+func TestNamespaceApplyYaml(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+
+	tests := []struct {
+		name         string
+		data         string
+		err          string
+		exists       []*types.Config
+		expectedCode int
+	}{
+		{
+			name: "checking apply yaml stream",
+			data: `kind: Config
+version: v1
+meta:
+  name: first
+spec:
+  data:
+    key: a
+---
+kind: Config
+version: v1
+meta:
+  name: second
+spec:
+  data:
+    key: b
+`,
+			exists: []*types.Config{
+				getConfigAsset(ns1.Meta.Name, "first", nil),
+				getConfigAsset(ns1.Meta.Name, "second", nil),
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "checking apply yaml stream with invalid field",
+			data: `kind: Config
+meta:
+  name: first
+---
+kind: Service
+meta:
+  name: proxy
+spec:
+  replicas: two
+`,
+			err:          "document[1]: spec.replicas: cannot unmarshal !!str `two` into int",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "checking apply yaml stream with unsupported kind",
+			data: `kind: Pod
+meta:
+  name: etcd
+`,
+			err:          "document[0]: kind: unsupported kind `Pod`",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "checking apply yaml stream with duplicate names",
+			data: `kind: Config
+meta:
+  name: first
+---
+kind: Config
+meta:
+  name: first
+`,
+			err:          "document[1]: meta.name: duplicate config `first`",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Config(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("PUT", fmt.Sprintf("/namespace/%s/apply", ns1.Meta.Name), strings.NewReader(tc.data))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/yaml")
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/apply", namespace.NamespaceApplyH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			if tc.err != "" {
+				e := new(errors.Http)
+				err := json.Unmarshal(body, e)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.err, e.Message, "error message not equal")
+			}
+
+			for _, c := range tc.exists {
+				got := new(types.Config)
+				err := stg.Get(context.Background(), stg.Collection().Config(), c.SelfLink().String(), got, nil)
+				assert.NoError(t, err, fmt.Sprintf("config %s should exist", c.Meta.Name))
+			}
+		})
+	}
+
+}
+
 // Testing NamespaceRemoveH handler
 func TestNamespaceRemove(t *testing.T) {
 
@@ -701,6 +1105,26 @@ func createNamespaceManifest(name, description string, resources *request.Namesp
 	return opts
 }
 
+func getConfigAsset(namespace, name string, data map[string]string) *types.Config {
+	var c = types.Config{}
+
+	c.Meta.Name = name
+	c.Meta.Namespace = namespace
+	c.Meta.SelfLink = *types.NewConfigSelfLink(namespace, name)
+	c.Spec.Data = data
+
+	return &c
+}
+
+func getConfigManifest(name string, data map[string]string) *request.ConfigManifest {
+	var m = request.ConfigManifest{}
+
+	m.Meta.Name = &name
+	m.Spec.Data = data
+
+	return &m
+}
+
 func getStrPtr(a string) *string {
 	return &a
 }
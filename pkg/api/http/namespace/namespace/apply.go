@@ -0,0 +1,433 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package namespace
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/config/config"
+	"github.com/lastbackend/lastbackend/pkg/api/http/job/job"
+	"github.com/lastbackend/lastbackend/pkg/api/http/route/route"
+	"github.com/lastbackend/lastbackend/pkg/api/http/secret/secret"
+	"github.com/lastbackend/lastbackend/pkg/api/http/service/service"
+	"github.com/lastbackend/lastbackend/pkg/api/http/volume/volume"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
+)
+
+const logApplyPrefix = "api:handler:namespace:apply"
+
+// fields changed on every apply, they are not a part of the plan diff
+var planIgnoreFields = []string{"created", "updated"}
+
+// Plan validates namespace manifest and returns actions required to apply it
+// with field level changes of object specs. Storage is not modified.
+// Namespace objects missing in manifest are planned for removal only if prune is set.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.NamespaceApplyManifest, prune bool) (*types.NamespaceApplyPlan, *errors.Err) {
+
+	var (
+		plan = types.NewNamespaceApplyPlan()
+		// resources are allocated on a namespace copy to check limits for all planned objects
+		scope    = *ns
+		services = make([]*types.Service, 0)
+		routes   = make([]*types.Route, 0)
+		// objects planned for creation are counted in namespace quotas
+		created = make(map[string]int, 0)
+	)
+
+	for _, m := range mf.Configs {
+		cur, next, e := config.Plan(ctx, &scope, m)
+		if e != nil {
+			return nil, e
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		if plan.Configs[next.SelfLink().String()], e = planStep(cur != nil, spec, next.Spec); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, m := range mf.Secrets {
+		cur, next, e := secret.Plan(ctx, &scope, m)
+		if e != nil {
+			return nil, e
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		step, e := planStep(cur != nil, spec, next.Spec)
+		if e != nil {
+			return nil, e
+		}
+
+		plan.Secrets[next.SelfLink().String()] = hideSecretData(step)
+	}
+
+	for _, m := range mf.Volumes {
+		cur, next, e := volume.Plan(ctx, &scope, m, created[types.KindVolume])
+		if e != nil {
+			return nil, e
+		}
+
+		if cur == nil {
+			created[types.KindVolume]++
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		if plan.Volumes[next.SelfLink().String()], e = planStep(cur != nil, spec, next.Spec); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, m := range mf.Services {
+		cur, next, e := service.Plan(ctx, &scope, m, created[types.KindService])
+		if e != nil {
+			return nil, e
+		}
+
+		if cur == nil {
+			created[types.KindService]++
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		if plan.Services[next.SelfLink().String()], e = planStep(cur != nil, spec, next.Spec); e != nil {
+			return nil, e
+		}
+
+		services = append(services, next)
+	}
+
+	for _, m := range mf.Routes {
+		cur, next, e := route.Plan(ctx, &scope, m, created[types.KindRoute], services...)
+		if e != nil {
+			return nil, e
+		}
+
+		if cur == nil {
+			created[types.KindRoute]++
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		if plan.Routes[next.SelfLink().String()], e = planStep(cur != nil, spec, next.Spec); e != nil {
+			return nil, e
+		}
+
+		routes = append(routes, next)
+	}
+
+	for _, m := range mf.Jobs {
+		cur, next, e := job.Plan(ctx, &scope, m, created[types.KindJob])
+		if e != nil {
+			return nil, e
+		}
+
+		if cur == nil {
+			created[types.KindJob]++
+		}
+
+		var spec interface{}
+		if cur != nil {
+			spec = cur.Spec
+		}
+
+		if plan.Jobs[next.SelfLink().String()], e = planStep(cur != nil, spec, next.Spec); e != nil {
+			return nil, e
+		}
+	}
+
+	if !prune {
+		return plan, nil
+	}
+
+	p, e := fetchPrunable(ctx, ns, plan)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, c := range p.configs {
+		if plan.Configs[c.SelfLink().String()], e = planStep(true, c.Spec, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, s := range p.secrets {
+		step, e := planStep(true, s.Spec, nil)
+		if e != nil {
+			return nil, e
+		}
+		plan.Secrets[s.SelfLink().String()] = hideSecretData(step)
+	}
+
+	for _, v := range p.volumes {
+		if plan.Volumes[v.SelfLink().String()], e = planStep(true, v.Spec, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, s := range p.services {
+
+		// routes from manifest are the only routes left in namespace after prune
+		for _, r := range routes {
+			for _, rule := range r.Spec.Rules {
+				if rule.Service == s.Meta.Name {
+					log.V(logLevel).Warnf("%s:plan:> service `%s` used in route `%s`", logApplyPrefix, s.Meta.Name, r.Meta.Name)
+					return nil, errors.New("service").BadRequest(errors.New(r.Meta.Name).Service().RouteBinded(r.Meta.Name).Error())
+				}
+			}
+		}
+
+		if plan.Services[s.SelfLink().String()], e = planStep(true, s.Spec, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, r := range p.routes {
+		if plan.Routes[r.SelfLink().String()], e = planStep(true, r.Spec, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, j := range p.jobs {
+		if plan.Jobs[j.SelfLink().String()], e = planStep(true, j.Spec, nil); e != nil {
+			return nil, e
+		}
+	}
+
+	return plan, nil
+}
+
+// Prune removes namespace objects planned for removal.
+// Routes are removed first to release services used by them.
+func Prune(ctx context.Context, ns *types.Namespace, plan *types.NamespaceApplyPlan) *errors.Err {
+
+	p, e := fetchPrunable(ctx, ns, plan)
+	if e != nil {
+		return e
+	}
+
+	removable := func(steps map[string]*types.NamespaceApplyStep, selflink string) bool {
+		step, ok := steps[selflink]
+		return ok && step.Action == types.ApplyActionDelete
+	}
+
+	for _, r := range p.routes {
+		if removable(plan.Routes, r.SelfLink().String()) {
+			if e := route.Remove(ctx, r); e != nil {
+				return e
+			}
+		}
+	}
+
+	for _, j := range p.jobs {
+		if removable(plan.Jobs, j.SelfLink().String()) {
+			if e := job.Remove(ctx, j); e != nil {
+				return e
+			}
+		}
+	}
+
+	for _, s := range p.services {
+		if removable(plan.Services, s.SelfLink().String()) {
+			if e := service.Remove(ctx, s); e != nil {
+				return e
+			}
+		}
+	}
+
+	for _, v := range p.volumes {
+		if removable(plan.Volumes, v.SelfLink().String()) {
+			if e := volume.Remove(ctx, v); e != nil {
+				return e
+			}
+		}
+	}
+
+	for _, s := range p.secrets {
+		if removable(plan.Secrets, s.SelfLink().String()) {
+			if e := secret.Remove(ctx, s); e != nil {
+				return e
+			}
+		}
+	}
+
+	for _, c := range p.configs {
+		if removable(plan.Configs, c.SelfLink().String()) {
+			if e := config.Remove(ctx, c); e != nil {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+type prunable struct {
+	configs  []*types.Config
+	secrets  []*types.Secret
+	volumes  []*types.Volume
+	services []*types.Service
+	routes   []*types.Route
+	jobs     []*types.Job
+}
+
+// fetchPrunable returns namespace objects which are not a part of apply plan.
+// Objects which are already being destroyed are skipped.
+func fetchPrunable(ctx context.Context, ns *types.Namespace, plan *types.NamespaceApplyPlan) (*prunable, *errors.Err) {
+
+	var (
+		stg = envs.Get().GetStorage()
+		p   = new(prunable)
+	)
+
+	planned := func(steps map[string]*types.NamespaceApplyStep, selflink string) bool {
+		step, ok := steps[selflink]
+		return ok && step.Action != types.ApplyActionDelete
+	}
+
+	cl, err := distribution.NewConfigModel(ctx, stg).List(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get configs err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("config").InternalServerError()
+	}
+	for _, c := range cl.Items {
+		if !planned(plan.Configs, c.SelfLink().String()) {
+			p.configs = append(p.configs, c)
+		}
+	}
+
+	sl, err := distribution.NewSecretModel(ctx, stg).List(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get secrets err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("secret").InternalServerError()
+	}
+	for _, s := range sl.Items {
+		if !planned(plan.Secrets, s.SelfLink().String()) {
+			p.secrets = append(p.secrets, s)
+		}
+	}
+
+	vl, err := distribution.NewVolumeModel(ctx, stg).ListByNamespace(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get volumes err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("volume").InternalServerError()
+	}
+	for _, v := range vl.Items {
+		if v.Status.State != types.StateDestroy && !planned(plan.Volumes, v.SelfLink().String()) {
+			p.volumes = append(p.volumes, v)
+		}
+	}
+
+	svl, err := distribution.NewServiceModel(ctx, stg).List(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get services err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("service").InternalServerError()
+	}
+	for _, s := range svl.Items {
+		if s.Status.State != types.StateDestroy && !planned(plan.Services, s.SelfLink().String()) {
+			p.services = append(p.services, s)
+		}
+	}
+
+	rl, err := distribution.NewRouteModel(ctx, stg).ListByNamespace(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get routes err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("route").InternalServerError()
+	}
+	for _, r := range rl.Items {
+		if r.Status.State != types.StateDestroy && !planned(plan.Routes, r.SelfLink().String()) {
+			p.routes = append(p.routes, r)
+		}
+	}
+
+	jl, err := distribution.NewJobModel(ctx, stg).ListByNamespace(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prune:> get jobs err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("job").InternalServerError()
+	}
+	for _, j := range jl.Items {
+		if j.Status.State != types.StateDestroy && !planned(plan.Jobs, j.SelfLink().String()) {
+			p.jobs = append(p.jobs, j)
+		}
+	}
+
+	return p, nil
+}
+
+func planStep(exists bool, current, next interface{}) (*types.NamespaceApplyStep, *errors.Err) {
+
+	step := new(types.NamespaceApplyStep)
+
+	switch {
+	case !exists:
+		step.Action = types.ApplyActionCreate
+		current = nil
+	case next == nil:
+		step.Action = types.ApplyActionDelete
+	default:
+		step.Action = types.ApplyActionUpdate
+	}
+
+	changes, err := compare.Diff(current, next, planIgnoreFields...)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> compare specs err: %s", logApplyPrefix, err.Error())
+		return nil, errors.New("namespace").InternalServerError()
+	}
+
+	if step.Action == types.ApplyActionUpdate && len(changes) == 0 {
+		step.Action = types.ApplyActionUnchanged
+	}
+
+	step.Changes = changes
+	return step, nil
+}
+
+// hideSecretData keeps changed secret keys in plan but drops their values
+func hideSecretData(step *types.NamespaceApplyStep) *types.NamespaceApplyStep {
+	for _, c := range step.Changes {
+		if strings.HasPrefix(c.Path, "data") {
+			c.From, c.To = nil, nil
+		}
+	}
+	return step
+}
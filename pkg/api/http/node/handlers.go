@@ -20,6 +20,8 @@ package node
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"strings"
@@ -97,6 +99,96 @@ func NodeInfoH(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func NodeStatsH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /cluster/node/{node}/stats node nodeStats
+	//
+	// Shows resources usage of node pods
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: node
+	//     in: path
+	//     description: node id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Node stats response
+	//     schema:
+	//       "$ref": "#/definitions/views_node_stats"
+	//   '404':
+	//     description: Node not found
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:stats:> get node stats", logPrefix)
+
+	var (
+		nm  = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
+		nid = utils.Vars(r)["node"]
+	)
+
+	n, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get node err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if n == nil {
+		log.V(logLevel).Warnf("%s:stats:> node `%s` not found", logPrefix, nid)
+		errors.New("node").NotFound().Http(w)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/stats", n.APIEndpoint()), nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> create http client err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", envs.Get().GetAccessToken()))
+
+	res, err := http.DefaultClient.Do(req.WithContext(r.Context()))
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get node stats err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		log.V(logLevel).Errorf("%s:stats:> get node stats err: node responded with status %d", logPrefix, res.StatusCode)
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	stats := new(types.NodeStats)
+	if err := json.NewDecoder(res.Body).Decode(stats); err != nil {
+		log.V(logLevel).Errorf("%s:stats:> decode node stats err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	stats.Node = n.Meta.Name
+
+	response, err := v1.View().Stats().NewNodeStats(stats).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.Errorf("%s:stats:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
 func NodeGetSpecH(w http.ResponseWriter, r *http.Request) {
 
 	// swagger:operation GET /cluster/node/{node}/spec node nodeGetSpec
@@ -173,6 +265,27 @@ func NodeListH(w http.ResponseWriter, r *http.Request) {
 	// ---
 	// produces:
 	// - application/json
+	// parameters:
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Node list response
@@ -183,12 +296,30 @@ func NodeListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get nodes list", logPrefix)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		nm = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
 	)
 
-	nodes, err := nm.List()
+	nodes, err := nm.List(opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get nodes list err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -201,6 +332,15 @@ func NodeListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(nodes.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -347,6 +487,20 @@ func NodeConnectH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	driver, err := sn.Driver(opts.Network.Type)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:connect:> get cluster network driver err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if driver != opts.Network.Type {
+		log.V(logLevel).Warnf("%s:connect:> node `%s` network driver `%s` does not match cluster network driver `%s`",
+			logPrefix, nid, opts.Network.Type, driver)
+		errors.HTTP.BadRequest(w, fmt.Sprintf("node network driver %s does not match cluster network driver %s", opts.Network.Type, driver))
+		return
+	}
+
 	if node == nil {
 
 		nco := types.NodeCreateOptions{}
@@ -501,6 +655,8 @@ func NodeSetStatusH(w http.ResponseWriter, r *http.Request) {
 	node.Status.State = opts.State
 	node.Status.Online = true
 	node.Status.Capacity = opts.Resources.Capacity
+	node.Status.Pressure = opts.Pressure
+	node.Status.Network = opts.Network
 
 	if err := nm.Set(node); err != nil {
 		log.V(logLevel).Errorf("%s:setstatus:> set status err: %s", logPrefix, err.Error())
@@ -586,7 +742,7 @@ func NodeSetStatusH(w http.ResponseWriter, r *http.Request) {
 		volume.Status.State = s.State
 		volume.Status.Message = s.Message
 
-		if err := vm.Update(volume); err != nil {
+		if err := vm.Update(volume, nil); err != nil {
 			log.V(logLevel).Errorf("%s:set volume status:> update pod err: %s", logPrefix, err.Error())
 			errors.HTTP.InternalServerError(w)
 			return
@@ -691,9 +847,11 @@ func getNodeSpec(ctx context.Context, n *types.Node) (*types.NodeManifest, error
 
 		spec = new(types.NodeManifest)
 		spec.Meta.Initial = true
+		spec.Meta.Subnet = n.Spec.Network.CIDR
 		spec.Resolvers = cache.GetResolvers()
 		spec.Exporter = cache.GetExporterEndpoint()
 		spec.Configs = cache.GetConfigs()
+		spec.Policies = cache.GetNetworkPolicies()
 
 		pods, err := pm.ManifestMap(n.Meta.Name)
 		if err != nil {
@@ -726,6 +884,49 @@ func getNodeSpec(ctx context.Context, n *types.Node) (*types.NodeManifest, error
 	}
 	cache.Flush(n.Meta.Name)
 
+	key, err := getNetworkKey(ctx, n)
+	if err != nil {
+		return spec, err
+	}
+	spec.Meta.NetworkKey = key
+
 	return spec, nil
 
 }
+
+// getNetworkKey returns node next network key if it is accepted by all nodes,
+// node switches to next key only after that, so overlay traffic is not dropped by peers
+func getNetworkKey(ctx context.Context, n *types.Node) (string, error) {
+
+	var (
+		stg = envs.Get().GetStorage()
+		nm  = distribution.NewNodeModel(ctx, stg)
+		sm  = distribution.NewNetworkModel(ctx, stg)
+	)
+
+	if n.Meta.Subnet == types.EmptyString {
+		return types.EmptyString, nil
+	}
+
+	snet, err := sm.SubnetGet(n.Meta.Subnet)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:getmanifest:> get node subnet err: %s", logPrefix, err.Error())
+		return types.EmptyString, err
+	}
+
+	if snet == nil || snet.Spec.KeyNext == types.EmptyString {
+		return types.EmptyString, nil
+	}
+
+	nodes, err := nm.List(nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:getmanifest:> get nodes list err: %s", logPrefix, err.Error())
+		return types.EmptyString, err
+	}
+
+	if !types.SubnetKeyNextAccepted(snet, nodes.Items) {
+		return types.EmptyString, nil
+	}
+
+	return snet.Spec.KeyNext, nil
+}
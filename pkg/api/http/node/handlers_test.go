@@ -20,6 +20,7 @@ package node_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -534,10 +535,18 @@ func TestNodeConnectH(t *testing.T) {
 		n1 = getNodeAsset("test1", "", true)
 		n2 = getNodeAsset("test2", "", true)
 		uo = v1.Request().Node().NodeConnectOptions()
+		wo = v1.Request().Node().NodeConnectOptions()
+		cn = new(types.Network)
 	)
 
 	uo.Info.Hostname = "test2"
 	uo.Info.Architecture = "mac"
+	uo.Network.Type = types.NetworkTypeVxLAN
+
+	wo.Info.Hostname = "test2"
+	wo.Network.Type = "wireguard"
+
+	cn.Spec.Type = types.NetworkTypeVxLAN
 
 	type args struct {
 		ctx  context.Context
@@ -569,6 +578,14 @@ func TestNodeConnectH(t *testing.T) {
 			expectedBody: "",
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "checking connect node failed: network driver does not match cluster",
+			args:         args{ctx, n2.Meta.Name},
+			handler:      node.NodeConnectH,
+			data:         wo.ToJson(),
+			expectedBody: "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"node network driver wireguard does not match cluster network driver vxlan\"}",
+			expectedCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range tests {
@@ -576,6 +593,12 @@ func TestNodeConnectH(t *testing.T) {
 		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Node().Info(), types.EmptyString)
 		assert.NoError(t, err)
 
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Network(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = stg.Put(context.Background(), stg.Collection().Network(), types.EmptyString, cn, nil)
+		assert.NoError(t, err)
+
 		err = stg.Put(context.Background(), stg.Collection().Node().Info(), n1.SelfLink().String(), &n1, nil)
 		assert.NoError(t, err)
 
@@ -652,6 +675,7 @@ func TestNodeSetStatusH(t *testing.T) {
 	nm.Exporter = new(types.ExporterManifest)
 	nm.Resolvers = make(map[string]*types.ResolverManifest, 0)
 	uo.Resources.Capacity.Pods = 20
+	uo.Pressure.Disk = true
 
 	type args struct {
 		ctx  context.Context
@@ -732,12 +756,118 @@ func TestNodeSetStatusH(t *testing.T) {
 				err = envs.Get().GetStorage().Get(context.Background(), stg.Collection().Node().Info(), tc.args.node, got, nil)
 				assert.NoError(t, err)
 				assert.Equal(t, uo.Resources.Capacity.Pods, got.Status.Capacity.Pods, "pods not equal")
+				assert.Equal(t, uo.Pressure.Disk, got.Status.Pressure.Disk, "disk pressure not equal")
 			}
 
 		})
 	}
 }
 
+// Testing NodeStatsH handler
+func TestNodeStatsH(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+	envs.Get().SetAccessToken("token")
+	v.Set("verbose", 0)
+
+	stats := new(types.NodeStats)
+	stats.Pods = make(map[string]*types.PodStats, 0)
+	stats.Endpoints = make(map[string]*types.EndpointStats, 0)
+
+	ns := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(stats)
+	}))
+	defer ns.Close()
+
+	fs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer fs.Close()
+
+	var (
+		n1 = getNodeAsset("test1", "", true)
+		n2 = getNodeAsset("test2", "", true)
+		n3 = getNodeAsset("test3", "", true)
+	)
+
+	n1.Meta.API = types.NodeAPI{Address: strings.TrimPrefix(ns.URL, "http://"), Scheme: "http"}
+	n3.Meta.API = types.NodeAPI{Address: strings.TrimPrefix(fs.URL, "http://"), Scheme: "http"}
+
+	view, err := v1.View().Stats().NewNodeStats(&types.NodeStats{Node: n1.Meta.Name}).ToJson()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		url          string
+		handler      func(http.ResponseWriter, *http.Request)
+		expectedBody string
+		expectedCode int
+	}{
+		{
+			name:         "checking get node stats failed: not found",
+			url:          fmt.Sprintf("/cluster/node/%s/stats", n2.Meta.Name),
+			handler:      node.NodeStatsH,
+			expectedBody: "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Node not found\"}",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking get node stats failed: node responded with error",
+			url:          fmt.Sprintf("/cluster/node/%s/stats", n3.Meta.Name),
+			handler:      node.NodeStatsH,
+			expectedBody: "{\"code\":500,\"status\":\"Internal Server Error\",\"message\":\"Internal Server Error\"}",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "checking get node stats from published node api successfully",
+			url:          fmt.Sprintf("/cluster/node/%s/stats", n1.Meta.Name),
+			handler:      node.NodeStatsH,
+			expectedBody: string(view),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Node().Info(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = stg.Put(context.Background(), stg.Collection().Node().Info(), n1.SelfLink().String(), &n1, nil)
+		assert.NoError(t, err)
+
+		err = stg.Put(context.Background(), stg.Collection().Node().Info(), n3.SelfLink().String(), &n3, nil)
+		assert.NoError(t, err)
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			req, err := http.NewRequest("GET", tc.url, nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/cluster/node/{node}/stats", tc.handler)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+
+			r.ServeHTTP(res, req)
+
+			assert.Equal(t, tc.expectedCode, res.Code, "status code not equal")
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, string(body), "incorrect response body")
+		})
+	}
+}
+
 func setRequestVars(r *mux.Router, req *http.Request) {
 	var match mux.RouteMatch
 	// Take the request and match it
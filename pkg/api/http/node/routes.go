@@ -31,4 +31,5 @@ var Routes = []http.Route{
 	{Path: "/cluster/node/{node}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeConnectH},
 	{Path: "/cluster/node/{node}/meta", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeSetMetaH},
 	{Path: "/cluster/node/{node}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeSetStatusH},
+	{Path: "/cluster/node/{node}/stats", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeStatsH},
 }
@@ -19,12 +19,15 @@
 package pod
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
 )
@@ -59,6 +62,26 @@ func PodListH(w http.ResponseWriter, r *http.Request) {
 	//     description: name of the deployment
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Deployment list response
@@ -75,6 +98,14 @@ func PodListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get pod list for `%s/%s`", logPrefix, sid, nid)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
 		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
@@ -120,8 +151,18 @@ func PodListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pl, err := pm.ListByDeployment(ns.Meta.Name, srv.Meta.Name, dep.Meta.Name)
+	pl, err := pm.ListByDeployment(ns.Meta.Name, srv.Meta.Name, dep.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get pod list by deployment name `%s` err: %s", logPrefix, did, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -134,9 +175,190 @@ func PodListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(pl.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
 		return
 	}
 }
+
+func PodStatsH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/deployment/{deployment}/pod/{pod}/stats pod podStats
+	//
+	// Shows resources usage of pod containers
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	//   - name: pod
+	//     in: path
+	//     description: name of the pod
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Pod stats response
+	//     schema:
+	//       "$ref": "#/definitions/views_pod_stats"
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found / Pod not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+	pid := utils.Vars(r)["pod"]
+
+	log.V(logLevel).Debugf("%s:stats:> get pod stats `%s` for `%s/%s/%s`", logPrefix, pid, nid, sid, did)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+		pm  = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+		nm  = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get namespace err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:stats:> namespace `%s` not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get service by name `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:stats:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	dep, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get deployment by deployment id `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if dep == nil {
+		log.V(logLevel).Warnf("%s:stats:> deployment `%s` in namespace `%s` not found", logPrefix, did, ns.Meta.Name)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	sl, err := types.NewPodSelfLink(types.KindDeployment, dep.SelfLink().String(), pid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> pod selflink create err: %s", logPrefix, err.Error())
+		errors.HTTP.BadRequest(w, "params")
+		return
+	}
+
+	pod, err := pm.Get(sl.String())
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get pod `%s` err: %s", logPrefix, pid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if pod == nil {
+		log.V(logLevel).Warnf("%s:stats:> pod `%s` not found", logPrefix, pid)
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	node, err := nm.Get(pod.Meta.Node)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get node `%s` err: %s", logPrefix, pod.Meta.Node, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if node == nil {
+		log.V(logLevel).Warnf("%s:stats:> node `%s` not found", logPrefix, pod.Meta.Node)
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/pod/%s/stats", node.APIEndpoint(), pod.SelfLink().String()), nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> create http client err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", envs.Get().GetAccessToken()))
+
+	res, err := http.DefaultClient.Do(req.WithContext(r.Context()))
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> get pod stats err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		log.V(logLevel).Warnf("%s:stats:> pod `%s` not found on node `%s`", logPrefix, pid, node.Meta.Name)
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	if res.StatusCode != http.StatusOK {
+		log.V(logLevel).Errorf("%s:stats:> get pod stats err: node responded with status %d", logPrefix, res.StatusCode)
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	stats := new(types.PodStats)
+	if err := json.NewDecoder(res.Body).Decode(stats); err != nil {
+		log.V(logLevel).Errorf("%s:stats:> decode pod stats err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Stats().NewPodStats(stats).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:stats:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:stats:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
@@ -0,0 +1,214 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package pod_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/pod"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing PodStatsH handler
+func TestPodStatsH(t *testing.T) {
+
+	var ctx = context.Background()
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+	envs.Get().SetAccessToken("token")
+
+	ns1 := getNamespaceAsset("demo", "")
+	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
+	d1 := getDeploymentAsset(ns1.Meta.Name, s1.Meta.Name, "demo")
+	p1 := getPodAsset(ns1.Meta.Name, s1.Meta.Name, d1.Meta.Name, "demo", "node")
+	p2 := getPodAsset(ns1.Meta.Name, s1.Meta.Name, d1.Meta.Name, "test", "node")
+	n1 := getNodeAsset("node")
+
+	stats := &types.PodStats{Pod: p1.SelfLink().String()}
+
+	ns := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != fmt.Sprintf("/pod/%s/stats", p1.SelfLink().String()) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(stats)
+	}))
+	defer ns.Close()
+
+	n1.Meta.API = types.NodeAPI{Address: strings.TrimPrefix(ns.URL, "http://"), Scheme: "http"}
+
+	view, err := v1.View().Stats().NewPodStats(stats).ToJson()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		pod          *types.Pod
+		expectedBody string
+		expectedCode int
+	}{
+		{
+			name:         "checking get pod stats failed: pod not found on node",
+			pod:          p2,
+			expectedBody: "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Pod not found\"}",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking get pod stats from published node api successfully",
+			pod:          p1,
+			expectedBody: string(view),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		for _, c := range []string{stg.Collection().Namespace(), stg.Collection().Service(), stg.Collection().Deployment(),
+			stg.Collection().Pod(), stg.Collection().Node().Info()} {
+			err := stg.Del(ctx, c, types.EmptyString)
+			assert.NoError(t, err)
+		}
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(ctx, stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(ctx, stg.Collection().Service(), s1.SelfLink().String(), s1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(ctx, stg.Collection().Deployment(), d1.SelfLink().String(), d1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(ctx, stg.Collection().Pod(), p1.SelfLink().String(), p1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(ctx, stg.Collection().Pod(), p2.SelfLink().String(), p2, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(ctx, stg.Collection().Node().Info(), n1.SelfLink().String(), n1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/pod/%s/stats",
+				ns1.Meta.Name, s1.Meta.Name, d1.Meta.Name, tc.pod.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/deployment/{deployment}/pod/{pod}/stats", pod.PodStatsH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+
+			r.ServeHTTP(res, req)
+
+			assert.Equal(t, tc.expectedCode, res.Code, "status code not equal")
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, string(body), "incorrect response body")
+		})
+	}
+}
+
+func getNamespaceAsset(name, desc string) *types.Namespace {
+	var n = types.Namespace{}
+	n.Meta.SetDefault()
+	n.Meta.Name = name
+	n.Meta.Description = desc
+	n.Meta.SelfLink = *types.NewNamespaceSelfLink(name)
+
+	return &n
+}
+
+func getServiceAsset(namespace, name, desc string) *types.Service {
+	var n = types.Service{}
+
+	n.Meta.SetDefault()
+	n.Meta.Namespace = namespace
+	n.Meta.Name = name
+	n.Meta.Description = desc
+	n.Meta.SelfLink = *types.NewServiceSelfLink(namespace, name)
+	return &n
+}
+
+func getDeploymentAsset(namespace, service, name string) *types.Deployment {
+	var d = types.Deployment{}
+	d.Meta.SetDefault()
+	d.Meta.Namespace = namespace
+	d.Meta.Service = service
+	d.Meta.Name = name
+	d.Meta.SelfLink = *types.NewDeploymentSelfLink(namespace, service, name)
+	return &d
+}
+
+func getPodAsset(namespace, service, deployment, name, node string) *types.Pod {
+	p := types.Pod{}
+
+	p.Meta.Name = name
+	p.Meta.Namespace = namespace
+	p.Meta.Node = node
+	psl, _ := types.NewPodSelfLink(types.KindDeployment, types.NewDeploymentSelfLink(namespace, service, deployment).String(), name)
+	p.Meta.SelfLink = *psl
+
+	return &p
+}
+
+func getNodeAsset(name string) *types.Node {
+	n := types.Node{}
+
+	n.Meta.Name = name
+	n.Meta.Hostname = name
+	n.Meta.SelfLink = *types.NewNodeSelfLink(name)
+
+	return &n
+}
+
+func setRequestVars(r *mux.Router, req *http.Request) {
+	var match mux.RouteMatch
+	// Take the request and match it
+	r.Match(req, &match)
+	// Push the variable onto the context
+	req = mux.SetURLVars(req, match.Vars)
+}
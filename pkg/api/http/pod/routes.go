@@ -25,4 +25,5 @@ import (
 
 var Routes = []http.Route{
 	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/pod", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodListH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/pod/{pod}/stats", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodStatsH},
 }
@@ -0,0 +1,435 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package policy
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/namespace/namespace"
+	"github.com/lastbackend/lastbackend/pkg/api/http/policy/policy"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+	"net/http"
+)
+
+const (
+	logLevel  = 2
+	logPrefix = "api:handler:policy"
+)
+
+func NetworkPolicyGetH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/policy/{policy} policy policyInfo
+	//
+	// Shows network policy info
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: policy
+	//     in: path
+	//     description: policy id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Network policy response
+	//     schema:
+	//       "$ref": "#/definitions/views_network_policy"
+	//   '404':
+	//     description: Namespace not found / Policy not found
+	//   '500':
+	//     description: Internal server error
+
+	var (
+		pid = utils.Vars(r)["policy"]
+		nid = utils.Vars(r)["namespace"]
+	)
+
+	log.V(logLevel).Debugf("%s:get:> get policy `%s`", logPrefix, pid)
+
+	ns, e := namespace.FetchFromRequest(r.Context(), nid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	item, e := policy.Fetch(r.Context(), ns.Meta.Name, pid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	response, err := v1.View().NetworkPolicy().New(item).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:get:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:get:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func NetworkPolicyListH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/policy policy policyList
+	//
+	// Shows a list of network policies
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like meta.name=db
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Network policy list response
+	//     schema:
+	//       "$ref": "#/definitions/views_network_policy_list"
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:list:> get policies list", logPrefix)
+
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	var (
+		nid = utils.Vars(r)["namespace"]
+		pm  = distribution.NewNetworkPolicyModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, e := namespace.FetchFromRequest(r.Context(), nid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	items, err := pm.List(ns.Meta.Name, opts.Page(sel))
+	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
+		log.V(logLevel).Errorf("%s:list:> find policy list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().NetworkPolicy().NewList(items).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(items.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func NetworkPolicyCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/policy policy policyCreate
+	//
+	// Create network policy
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_network_policy_create"
+	// responses:
+	//   '200':
+	//     description: Network policy was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_network_policy"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:create:> create policy", logPrefix)
+
+	var (
+		nid  = utils.Vars(r)["namespace"]
+		opts = v1.Request().NetworkPolicy().Manifest()
+	)
+
+	ns, e := namespace.FetchFromRequest(r.Context(), nid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	// request body struct
+	e = opts.DecodeAndValidate(r.Body)
+	if e != nil {
+		log.V(logLevel).Errorf("%s:create:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	item, e := policy.Create(r.Context(), ns, opts)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	response, err := v1.View().NetworkPolicy().New(item).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:create:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func NetworkPolicyUpdateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /namespace/{namespace}/policy/{policy} policy policyUpdate
+	//
+	// Update network policy
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: policy
+	//     in: path
+	//     description: policy id
+	//     required: true
+	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_network_policy_update"
+	// responses:
+	//   '200':
+	//     description: Network policy was successfully updated
+	//     schema:
+	//       "$ref": "#/definitions/views_network_policy"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Namespace not found / Policy not found
+	//   '409':
+	//     description: Resource version conflict
+	//   '500':
+	//     description: Internal server error
+
+	var (
+		nid  = utils.Vars(r)["namespace"]
+		pid  = utils.Vars(r)["policy"]
+		opts = v1.Request().NetworkPolicy().Manifest()
+	)
+
+	log.V(logLevel).Debugf("%s:update:> update policy `%s`", logPrefix, pid)
+
+	ns, e := namespace.FetchFromRequest(r.Context(), nid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	// request body struct
+	e = opts.DecodeAndValidate(r.Body)
+	if e != nil {
+		log.V(logLevel).Errorf("%s:update:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	item, e := policy.Fetch(r.Context(), ns.Meta.Name, pid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if !utils.IfMatch(r, item.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> policy `%s` version mismatch", logPrefix, pid)
+		errors.HTTP.Conflict(w, "policy")
+		return
+	}
+
+	item, e = policy.Update(r.Context(), item, opts)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	response, err := v1.View().NetworkPolicy().New(item).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:update:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func NetworkPolicyRemoveH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /namespace/{namespace}/policy/{policy} policy policyRemove
+	//
+	// Remove network policy
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: policy
+	//     in: path
+	//     description: policy id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Network policy was successfully removed
+	//   '404':
+	//     description: Namespace not found / Policy not found
+	//   '500':
+	//     description: Internal server error
+
+	var (
+		pid = utils.Vars(r)["policy"]
+		nid = utils.Vars(r)["namespace"]
+	)
+
+	log.V(logLevel).Debugf("%s:remove:> remove policy `%s`", logPrefix, pid)
+
+	ns, e := namespace.FetchFromRequest(r.Context(), nid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	item, e := policy.Fetch(r.Context(), ns.Meta.Name, pid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if e := policy.Remove(r.Context(), item); e != nil {
+		e.Http(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
@@ -0,0 +1,245 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package policy_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/policy"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing NetworkPolicyListH handler
+func TestNetworkPolicyList(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+
+	p1 := getNetworkPolicyAsset(ns1, "demo")
+	p2 := getNetworkPolicyAsset(ns1, "test")
+
+	pl := types.NewNetworkPolicyMap()
+	pl.Items[p1.SelfLink().String()] = p1
+	pl.Items[p2.SelfLink().String()] = p2
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Policy(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	clear()
+	defer clear()
+
+	err := stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+	assert.NoError(t, err)
+
+	err = stg.Put(context.Background(), stg.Collection().Policy(), p1.SelfLink().String(), p1, nil)
+	assert.NoError(t, err)
+
+	err = stg.Put(context.Background(), stg.Collection().Policy(), p2.SelfLink().String(), p2, nil)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s/policy", ns1.Meta.Name), nil)
+	assert.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/namespace/{namespace}/policy", policy.NetworkPolicyListH)
+
+	setRequestVars(r, req)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	if !assert.Equal(t, http.StatusOK, res.Code, "status code not equal") {
+		return
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	list := new(views.NetworkPolicyList)
+	err = json.Unmarshal(body, &list)
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(pl.Items), len(*list), "policies count different")
+	for _, item := range *list {
+		_, ok := pl.Items[item.Meta.SelfLink]
+		assert.True(t, ok, "unexpected policy %s", item.Meta.SelfLink)
+	}
+}
+
+// Testing NetworkPolicyCreateH handler
+func TestNetworkPolicyCreate(t *testing.T) {
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+
+	name := "web"
+
+	mf := new(request.NetworkPolicyManifest)
+	mf.Meta.Name = &name
+	mf.Spec.Selector = map[string]string{"app": "web"}
+	mf.Spec.Ingress = []*request.NetworkPolicyManifestRule{
+		{Labels: map[string]string{"app": "proxy"}, Ports: []string{"80", "53/udp"}},
+	}
+	mf1, _ := mf.ToJson()
+
+	mf.Spec.Ingress[0].Ports = []string{"abc"}
+	mf2, _ := mf.ToJson()
+
+	tests := []struct {
+		name         string
+		data         string
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "check create policy if failed incoming json data",
+			data:         "{name:demo}",
+			err:          "{\"code\":400,\"status\":\"Incorrect Json\",\"message\":\"Incorrect json\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create policy if port is invalid",
+			data:         string(mf2),
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create policy success",
+			data:         string(mf1),
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Policy(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/namespace/%s/policy", ns1.Meta.Name), strings.NewReader(tc.data))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/policy", policy.NetworkPolicyCreateH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				if tc.err != types.EmptyString {
+					assert.Equal(t, tc.err, string(body), "incorrect error message")
+				}
+				return
+			}
+
+			got := new(types.NetworkPolicy)
+			sl := types.NewNetworkPolicySelfLink(ns1.Meta.Name, name).String()
+			err = stg.Get(context.Background(), stg.Collection().Policy(), sl, got, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, ns1.Meta.Name, got.Meta.Namespace, "namespace different")
+			assert.Equal(t, "web", got.Spec.Selector["app"], "selector different")
+			if assert.Len(t, got.Spec.Ingress, 1) && assert.Len(t, got.Spec.Ingress[0].Ports, 2) {
+				assert.Equal(t, uint16(53), got.Spec.Ingress[0].Ports[1].Port, "port different")
+				assert.Equal(t, types.NetworkPolicyProtocolUDP, got.Spec.Ingress[0].Ports[1].Protocol, "protocol different")
+			}
+		})
+	}
+}
+
+func getNamespaceAsset(name, desc string) *types.Namespace {
+	var n = types.Namespace{}
+	n.Meta.SetDefault()
+	n.Meta.Name = name
+	n.Meta.Description = desc
+	n.Meta.SelfLink = *types.NewNamespaceSelfLink(name)
+	return &n
+}
+
+func getNetworkPolicyAsset(namespace *types.Namespace, name string) *types.NetworkPolicy {
+	var p = types.NetworkPolicy{}
+	p.Meta.SetDefault()
+	p.Meta.Name = name
+	p.Meta.Namespace = namespace.Meta.Name
+	p.Meta.SelfLink = *types.NewNetworkPolicySelfLink(namespace.Meta.Name, name)
+	p.Spec.Selector = map[string]string{"app": name}
+	return &p
+}
+
+func setRequestVars(r *mux.Router, req *http.Request) {
+	var match mux.RouteMatch
+	// Take the request and match it
+	r.Match(req, &match)
+	// Push the variable onto the context
+	req = mux.SetURLVars(req, match.Vars)
+}
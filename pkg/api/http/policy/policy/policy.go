@@ -0,0 +1,120 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package policy
+
+import (
+	"context"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"time"
+)
+
+const (
+	logPrefix = "api:handler:policy"
+	logLevel  = 3
+)
+
+func Fetch(ctx context.Context, namespace, name string) (*types.NetworkPolicy, *errors.Err) {
+
+	pm := distribution.NewNetworkPolicyModel(ctx, envs.Get().GetStorage())
+	policy, err := pm.Get(namespace, name)
+
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> err: %s", logPrefix, err.Error())
+		return nil, errors.New("policy").InternalServerError(err)
+	}
+
+	if policy == nil {
+		log.V(logLevel).Warnf("%s:fetch:> policy `%s` in namespace `%s` not found", logPrefix, name, namespace)
+		return nil, errors.New("policy").NotFound()
+	}
+
+	return policy, nil
+}
+
+func Create(ctx context.Context, ns *types.Namespace, mf *request.NetworkPolicyManifest) (*types.NetworkPolicy, *errors.Err) {
+
+	pm := distribution.NewNetworkPolicyModel(ctx, envs.Get().GetStorage())
+
+	item, err := pm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get policy by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, errors.New("policy").InternalServerError()
+	}
+
+	if item != nil {
+		log.V(logLevel).Warnf("%s:create:> policy name `%s` in namespace `%s` not unique", logPrefix, *mf.Meta.Name, ns.Meta.Name)
+		return nil, errors.New("policy").NotUnique("name")
+	}
+
+	policy := new(types.NetworkPolicy)
+	policy.Meta.SetDefault()
+
+	mf.SetNetworkPolicyMeta(policy)
+	if err := mf.SetNetworkPolicySpec(policy); err != nil {
+		log.V(logLevel).Warnf("%s:create:> set policy spec err: %s", logPrefix, err.Error())
+		return nil, errors.New("policy").BadRequest(err.Error())
+	}
+
+	if _, err := pm.Create(ns, policy); err != nil {
+		log.V(logLevel).Errorf("%s:create:> create policy in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+		return nil, errors.New("policy").InternalServerError()
+	}
+
+	return policy, nil
+}
+
+func Update(ctx context.Context, policy *types.NetworkPolicy, mf *request.NetworkPolicyManifest) (*types.NetworkPolicy, *errors.Err) {
+
+	pm := distribution.NewNetworkPolicyModel(ctx, envs.Get().GetStorage())
+
+	mf.SetNetworkPolicyMeta(policy)
+	if err := mf.SetNetworkPolicySpec(policy); err != nil {
+		log.V(logLevel).Warnf("%s:update:> set policy spec err: %s", logPrefix, err.Error())
+		return nil, errors.New("policy").BadRequest(err.Error())
+	}
+	policy.Meta.Updated = time.Now()
+
+	if _, err := pm.Update(policy, &types.UpdateOptions{Revision: &policy.Storage.Revision}); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update policy err: %s", logPrefix, err.Error())
+			return nil, errors.New("policy").Conflict()
+		}
+		log.V(logLevel).Errorf("%s:update:> update policy err: %s", logPrefix, err.Error())
+		return nil, errors.New("policy").InternalServerError()
+	}
+
+	return policy, nil
+}
+
+func Remove(ctx context.Context, policy *types.NetworkPolicy) *errors.Err {
+
+	pm := distribution.NewNetworkPolicyModel(ctx, envs.Get().GetStorage())
+
+	if err := pm.Remove(policy); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove policy `%s` err: %s", logPrefix, policy.SelfLink().String(), err.Error())
+		return errors.New("policy").InternalServerError()
+	}
+
+	return nil
+}
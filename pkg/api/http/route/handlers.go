@@ -53,6 +53,26 @@ func RouteListH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Route list response
@@ -65,6 +85,14 @@ func RouteListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> get routes list", logPrefix)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	nid := utils.Vars(r)["namespace"]
 
 	var (
@@ -77,8 +105,18 @@ func RouteListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := rm.ListByNamespace(ns.Meta.Name)
+	items, err := rm.ListByNamespace(ns.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> find route list err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -91,6 +129,15 @@ func RouteListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(items.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -263,6 +310,11 @@ func RouteUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: route id
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -277,6 +329,8 @@ func RouteUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: Bad rules parameter
 	//   '404':
 	//     description: Namespace not found / Route not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -308,6 +362,12 @@ func RouteUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, rt.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> route `%s` version mismatch", logPrefix, rid)
+		errors.HTTP.Conflict(w, "route")
+		return
+	}
+
 	rt, e = route.Update(r.Context(), ns, rt, mf)
 	if e != nil {
 		e.Http(w)
@@ -384,7 +444,7 @@ func RouteRemoveH(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rs.Status.State = types.StateDestroy
-	_, err = rm.Set(rs)
+	_, err = rm.Set(rs, nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:remove:> remove route `%s` err: %s", logPrefix, rid, err.Error())
 		errors.HTTP.InternalServerError(w)
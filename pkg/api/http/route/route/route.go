@@ -27,6 +27,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"net/http"
 	"strings"
 )
@@ -78,7 +79,6 @@ func Apply(ctx context.Context, ns *types.Namespace, mf *request.RouteManifest)
 func Create(ctx context.Context, ns *types.Namespace, mf *request.RouteManifest) (*types.Route, *errors.Err) {
 
 	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
-	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
 
 	if mf.Meta.Name != nil {
 
@@ -94,34 +94,9 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.RouteManifest)
 		}
 	}
 
-	if err := validateManifest(ctx, mf); err != nil {
-		log.V(logLevel).Errorf("%s:create:> route manifest validation err", logPrefix, err.Err().Error())
-		return nil, err
-	}
-
-	svc, err := sm.List(ns.Meta.Name)
-	if err != nil {
-		log.V(logLevel).Errorf("%s:create:> get services", logPrefix, err.Error())
-		return nil, errors.New("route").InternalServerError()
-	}
-
-	route := new(types.Route)
-	route.Meta.SetDefault()
-	route.Meta.SelfLink = *types.NewRouteSelfLink(ns.Meta.Name, *mf.Meta.Name)
-	route.Meta.Namespace = ns.Meta.Name
-
-	mf.SetRouteMeta(route)
-	mf.SetRouteSpec(route, ns, svc)
-
-	if route.Spec.Endpoint == types.EmptyString {
-		_, external := envs.Get().GetDomain()
-		route.Spec.Endpoint = fmt.Sprintf("%s.%s.%s", strings.ToLower(route.Meta.Name), strings.ToLower(ns.Meta.Name), external)
-	}
-
-	if len(route.Spec.Rules) == 0 {
-		err := errors.New("route rules are incorrect")
-		log.V(logLevel).Errorf("%s:create:> route rules empty: %s", logPrefix, err.Error())
-		return nil, errors.New("route").BadParameter("rules", err)
+	route, e := prepare(ctx, ns, nil, mf, 0)
+	if e != nil {
+		return nil, e
 	}
 
 	if _, err := rm.Add(ns, route); err != nil {
@@ -136,7 +111,6 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.RouteManifest)
 func Update(ctx context.Context, ns *types.Namespace, rt *types.Route, mf *request.RouteManifest) (*types.Route, *errors.Err) {
 
 	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
-	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
 
 	if mf.Meta.Name != nil {
 
@@ -152,34 +126,18 @@ func Update(ctx context.Context, ns *types.Namespace, rt *types.Route, mf *reque
 		}
 	}
 
-	if err := validateManifest(ctx, mf); err != nil {
-		log.V(logLevel).Errorf("%s:update:> route manifest validation err: %s", logPrefix, err.Err().Error())
-		return nil, err
-	}
-
-	svc, err := sm.List(ns.Meta.Name)
-	if err != nil {
-		log.V(logLevel).Errorf("%s:create:> get services err: %s", logPrefix, err.Error())
-		return nil, errors.New("route").InternalServerError()
-	}
-
-	mf.SetRouteMeta(rt)
-	mf.SetRouteSpec(rt, ns, svc)
-
-	if rt.Spec.Endpoint == types.EmptyString {
-		_, external := envs.Get().GetDomain()
-		rt.Spec.Endpoint = fmt.Sprintf("%s.%s.%s", strings.ToLower(rt.Meta.Name), strings.ToLower(ns.Meta.Name), external)
-	}
-
-	if len(rt.Spec.Rules) == 0 {
-		err := errors.New("route rules are incorrect")
-		log.V(logLevel).Errorf("%s:create:> route rules empty: %s", logPrefix, err.Error())
-		return nil, errors.New("route").BadParameter("rules", err)
+	rt, e := prepare(ctx, ns, rt, mf, 0)
+	if e != nil {
+		return nil, e
 	}
 
 	rt.Status.State = types.StateProvision
-	rt, err = rm.Set(rt)
+	rt, err := rm.Set(rt, &types.UpdateOptions{Revision: &rt.Storage.Revision})
 	if err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update route `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+			return nil, errors.New("route").Conflict()
+		}
 		log.V(logLevel).Errorf("%s:update:> update route `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
 		return nil, errors.New("route").InternalServerError()
 	}
@@ -191,7 +149,7 @@ func validateManifest(ctx context.Context, mf *request.RouteManifest) *errors.Er
 
 	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
 
-	rl, err := rm.List()
+	rl, err := rm.List(nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:validate:> route manifest validation failed: %s ", logPrefix, err.Error())
 		return errors.New("route").InternalServerError()
@@ -216,3 +174,109 @@ func validateManifest(ctx context.Context, mf *request.RouteManifest) *errors.Er
 
 	return nil
 }
+
+// Plan returns the stored route and the route as it will be after the manifest is applied.
+// Nothing is saved to storage. Planned services are used to resolve rules for services
+// which are not created yet, created is count of routes planned for creation before this one.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.RouteManifest, created int, planned ...*types.Service) (*types.Route, *types.Route, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("route").BadParameter("meta.name")
+	}
+
+	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
+
+	rt, err := rm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get route by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("route").InternalServerError()
+	}
+
+	var next *types.Route
+
+	if rt != nil {
+		next = new(types.Route)
+		if err := compare.Copy(rt, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy route `%s` err: %s", logPrefix, rt.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("route").InternalServerError()
+		}
+	}
+
+	next, e := prepare(ctx, ns, next, mf, created, planned...)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	return rt, next, nil
+}
+
+// prepare applies manifest to stored route or to a new one if rt is nil.
+// Route is checked against namespace quotas and its rules are resolved to namespace services and planned ones,
+// apply and plan share it to fail the same way. Created routes are counted in quota of new route.
+func prepare(ctx context.Context, ns *types.Namespace, rt *types.Route, mf *request.RouteManifest, created int, planned ...*types.Service) (*types.Route, *errors.Err) {
+
+	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
+
+	if rt == nil && ns.Spec.Quotas.Routes > 0 {
+		rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
+		rl, err := rm.ListByNamespace(ns.Meta.Name, nil)
+		if err != nil {
+			log.V(logLevel).Errorf("%s:prepare:> get routes in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+			return nil, errors.New("route").InternalServerError()
+		}
+
+		if err := ns.ValidateQuota(types.KindRoute, len(rl.Items)+created); err != nil {
+			log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+			return nil, errors.New("route").BadRequest(err.Error())
+		}
+	}
+
+	if err := validateManifest(ctx, mf); err != nil {
+		log.V(logLevel).Errorf("%s:prepare:> route manifest validation err: %s", logPrefix, err.Err().Error())
+		return nil, err
+	}
+
+	svc, err := sm.List(ns.Meta.Name, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:prepare:> get services err: %s", logPrefix, err.Error())
+		return nil, errors.New("route").InternalServerError()
+	}
+
+	svc.Items = append(svc.Items, planned...)
+
+	if rt == nil {
+		rt = new(types.Route)
+		rt.Meta.SetDefault()
+		rt.Meta.SelfLink = *types.NewRouteSelfLink(ns.Meta.Name, *mf.Meta.Name)
+		rt.Meta.Namespace = ns.Meta.Name
+	}
+
+	mf.SetRouteMeta(rt)
+	mf.SetRouteSpec(rt, ns, svc)
+
+	if rt.Spec.Endpoint == types.EmptyString {
+		_, external := envs.Get().GetDomain()
+		rt.Spec.Endpoint = fmt.Sprintf("%s.%s.%s", strings.ToLower(rt.Meta.Name), strings.ToLower(ns.Meta.Name), external)
+	}
+
+	if len(rt.Spec.Rules) == 0 {
+		err := errors.New("route rules are incorrect")
+		log.V(logLevel).Errorf("%s:prepare:> route rules empty: %s", logPrefix, err.Error())
+		return nil, errors.New("route").BadParameter("rules", err)
+	}
+
+	return rt, nil
+}
+
+func Remove(ctx context.Context, rt *types.Route) *errors.Err {
+
+	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
+
+	rt.Status.State = types.StateDestroy
+	if _, err := rm.Set(rt, nil); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove route `%s` err: %s", logPrefix, rt.SelfLink().String(), err.Error())
+		return errors.New("route").InternalServerError()
+	}
+
+	return nil
+}
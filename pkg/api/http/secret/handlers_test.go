@@ -360,6 +360,17 @@ func TestSecretUpdate(t *testing.T) {
 			wantErr:      false,
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "checking update secret if version mismatch",
+			args:         args{ctx, s1},
+			fields:       fields{stg},
+			headers:      map[string]string{"If-Match": "\"1\""},
+			handler:      secret.SecretUpdateH,
+			data:         string(mf2),
+			err:          "{\"code\":409,\"status\":\"Conflict\",\"message\":\"Secret version conflict\"}",
+			wantErr:      true,
+			expectedCode: http.StatusConflict,
+		},
 	}
 
 	clear := func() {
@@ -26,6 +26,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"net/http"
 )
 
@@ -90,13 +91,7 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.SecretManifest
 		}
 	}
 
-	sct := new(types.Secret)
-	sct.Meta.SetDefault()
-	sct.Meta.SelfLink = *types.NewSecretSelfLink(ns.Meta.Name, *mf.Meta.Name)
-	sct.Meta.Namespace = ns.Meta.Name
-
-	mf.SetSecretMeta(sct)
-	mf.SetSecretSpec(sct)
+	sct := prepare(ns, nil, mf)
 
 	if _, err := sm.Create(ns, sct); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create secret err: %s", logPrefix, ns.Meta.Name, err.Error())
@@ -110,13 +105,74 @@ func Update(ctx context.Context, ns *types.Namespace, sct *types.Secret, mf *req
 
 	sm := distribution.NewSecretModel(ctx, envs.Get().GetStorage())
 
-	mf.SetSecretMeta(sct)
-	mf.SetSecretSpec(sct)
+	sct = prepare(ns, sct, mf)
 
-	if _, err := sm.Update(sct); err != nil {
+	if _, err := sm.Update(sct, &types.UpdateOptions{Revision: &sct.Storage.Revision}); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update secret err: %s", logPrefix, err.Error())
+			return nil, errors.New("secret").Conflict()
+		}
 		log.V(logLevel).Errorf("%s:update:> update secret err: %s", logPrefix, err.Error())
 		return nil, errors.New("secret").InternalServerError()
 	}
 
 	return sct, nil
 }
+
+// Plan returns the stored secret and the secret as it will be after the manifest is applied.
+// Nothing is saved to storage, the stored secret is nil if it does not exist yet.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.SecretManifest) (*types.Secret, *types.Secret, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("secret").BadParameter("meta.name")
+	}
+
+	sm := distribution.NewSecretModel(ctx, envs.Get().GetStorage())
+
+	sct, err := sm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get secret by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("secret").InternalServerError()
+	}
+
+	var next *types.Secret
+
+	if sct != nil {
+		next = new(types.Secret)
+		if err := compare.Copy(sct, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy secret `%s` err: %s", logPrefix, sct.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("secret").InternalServerError()
+		}
+	}
+
+	return sct, prepare(ns, next, mf), nil
+}
+
+// prepare applies manifest to stored secret or to a new one if sct is nil,
+// apply and plan share it to get the same secret
+func prepare(ns *types.Namespace, sct *types.Secret, mf *request.SecretManifest) *types.Secret {
+
+	if sct == nil {
+		sct = new(types.Secret)
+		sct.Meta.SetDefault()
+		sct.Meta.Namespace = ns.Meta.Name
+		sct.Meta.SelfLink = *types.NewSecretSelfLink(ns.Meta.Name, *mf.Meta.Name)
+	}
+
+	mf.SetSecretMeta(sct)
+	mf.SetSecretSpec(sct)
+
+	return sct
+}
+
+func Remove(ctx context.Context, sct *types.Secret) *errors.Err {
+
+	sm := distribution.NewSecretModel(ctx, envs.Get().GetStorage())
+
+	if err := sm.Remove(sct); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove secret `%s` err: %s", logPrefix, sct.SelfLink().String(), err.Error())
+		return errors.New("secret").InternalServerError()
+	}
+
+	return nil
+}
@@ -55,6 +55,26 @@ func ServiceListH(w http.ResponseWriter, r *http.Request) {
 	//     description: namespace id
 	//     required: true
 	//     type: string
+	//   - name: labelSelector
+	//     in: query
+	//     description: filter by labels, like app=web,tier!=db
+	//     required: false
+	//     type: string
+	//   - name: fieldSelector
+	//     in: query
+	//     description: filter by fields, like status.state=error
+	//     required: false
+	//     type: string
+	//   - name: limit
+	//     in: query
+	//     description: max items count in page, page is returned as views_list_page
+	//     required: false
+	//     type: integer
+	//   - name: continue
+	//     in: query
+	//     description: continue token returned with the previous page
+	//     required: false
+	//     type: string
 	// responses:
 	//   '200':
 	//     description: Applications list response
@@ -69,6 +89,14 @@ func ServiceListH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debugf("%s:list:> list services in %s", logPrefix, nid)
 
+	opts := v1.Request().List().Options()
+	sel, e := opts.DecodeAndValidate(r.URL.Query())
+	if e != nil {
+		log.V(logLevel).Errorf("%s:list:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	var (
 		stg = envs.Get().GetStorage()
 		sm  = distribution.NewServiceModel(r.Context(), stg)
@@ -88,8 +116,18 @@ func ServiceListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := sm.List(ns.Meta.Name)
+	items, err := sm.List(ns.Meta.Name, opts.Page(sel))
 	if err != nil {
+		if errors.Storage().IsErrContinueIsInvalid(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is invalid", logPrefix)
+			errors.HTTP.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Storage().IsErrContinueIsExpired(err) {
+			log.V(logLevel).Warnf("%s:list:> continue token is expired", logPrefix)
+			errors.HTTP.Gone(w, err.Error())
+			return
+		}
 		log.V(logLevel).Errorf("%s:list:> get service list in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
@@ -102,6 +140,15 @@ func ServiceListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if opts.Paged() {
+		response, err = v1.View().List().NewPage(items.System, response).ToJson()
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(response); err != nil {
 		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
@@ -263,6 +310,11 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 	//     description: service id
 	//     required: true
 	//     type: string
+	//   - name: If-Match
+	//     in: header
+	//     description: expected resource version, update fails with conflict if resource was changed
+	//     required: false
+	//     type: string
 	//   - name: body
 	//     in: body
 	//     required: true
@@ -275,6 +327,8 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/views_service"
 	//   '404':
 	//     description: Namespace not found / Service not found
+	//   '409':
+	//     description: Resource version conflict
 	//   '500':
 	//     description: Internal server error
 
@@ -305,6 +359,12 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !utils.IfMatch(r, svc.ResourceVersion()) {
+		log.V(logLevel).Warnf("%s:update:> service `%s` version mismatch", logPrefix, sid)
+		errors.HTTP.Conflict(w, "service")
+		return
+	}
+
 	svc, e = service.Update(r.Context(), ns, svc, opts, &request.ServiceUpdateOptions{Redeploy: redeploy})
 	if e != nil {
 		e.Http(w)
@@ -390,7 +450,7 @@ func ServiceRemoveH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rl, err := rm.ListByNamespace(nid)
+	rl, err := rm.ListByNamespace(nid, nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:remove:> get routes list in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
@@ -502,7 +562,7 @@ func ServiceLogsH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	el, err := em.List()
+	el, err := em.List(nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:logs:> get exporters", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
@@ -33,6 +33,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/http/service"
+	svc "github.com/lastbackend/lastbackend/pkg/api/http/service/service"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
@@ -191,10 +192,20 @@ func TestServiceList(t *testing.T) {
 	ns2 := getNamespaceAsset("test", "")
 	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
 	s2 := getServiceAsset(ns1.Meta.Name, "test", "")
+	s3 := getServiceAsset(ns1.Meta.Name, "web", "")
+
+	s1.Meta.Labels = map[string]string{"app": "web"}
 
 	sl := types.NewServiceMap()
 	sl.Items[s1.SelfLink().String()] = s1
 	sl.Items[s2.SelfLink().String()] = s2
+	sl.Items[s3.SelfLink().String()] = s3
+
+	sl1 := types.NewServiceMap()
+	sl1.Items[s1.SelfLink().String()] = s1
+
+	sl2 := types.NewServiceMap()
+	sl2.Items[s3.SelfLink().String()] = s3
 
 	type fields struct {
 		stg storage.Storage
@@ -210,6 +221,10 @@ func TestServiceList(t *testing.T) {
 		name         string
 		fields       fields
 		args         args
+		query        string
+		paged        bool
+		total        int64
+		more         bool
 		headers      map[string]string
 		handler      func(http.ResponseWriter, *http.Request)
 		err          string
@@ -235,6 +250,72 @@ func TestServiceList(t *testing.T) {
 			wantErr:      false,
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "checking get services list with label selector",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "labelSelector=app%3Dweb",
+			want:         sl1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking get services list with field selector",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "fieldSelector=meta.name%3Ddemo",
+			want:         sl1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking get services list page",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "limit=1",
+			paged:        true,
+			total:        3,
+			more:         true,
+			want:         sl1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking get services list page with field selector",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "limit=1&fieldSelector=meta.name%3Dweb",
+			paged:        true,
+			total:        1,
+			more:         false,
+			want:         sl2,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking get services list with invalid continue token",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "limit=1&continue=invalid",
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"continue token is invalid\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking get services list with invalid field selector",
+			args:         args{ctx, ns1, nil},
+			fields:       fields{stg},
+			handler:      service.ServiceListH,
+			query:        "fieldSelector=meta.name",
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"invalid field selector: operator is required in `meta.name`\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 	}
 
 	clear := func() {
@@ -261,9 +342,12 @@ func TestServiceList(t *testing.T) {
 			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), s2.SelfLink().String(), s2, nil)
 			assert.NoError(t, err)
 
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), s3.SelfLink().String(), s3, nil)
+			assert.NoError(t, err)
+
 			// Create assert request to pass to our handler. We don't have any query parameters for now, so we'll
 			// pass 'nil' as the third parameter.
-			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s", tc.args.namespace.Meta.Name), nil)
+			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s?%s", tc.args.namespace.Meta.Name, tc.query), nil)
 			assert.NoError(t, err)
 
 			if tc.headers != nil {
@@ -296,9 +380,19 @@ func TestServiceList(t *testing.T) {
 				assert.Equal(t, tc.err, string(body), "incorrect status code")
 			} else {
 
+				if tc.paged {
+					p := new(views.ListPage)
+					err := json.Unmarshal(body, &p)
+					assert.NoError(t, err)
+					assert.Equal(t, tc.total, p.Meta.Total, "total count not equal")
+					assert.Equal(t, tc.more, p.Meta.Continue != "", "continue token not expected")
+					body = p.Items
+				}
+
 				s := new(views.RouteList)
 				err := json.Unmarshal(body, &s)
 				assert.NoError(t, err)
+				assert.Equal(t, len(tc.want.Items), len(*s), "items count not equal")
 
 				for _, item := range *s {
 					if _, ok := tc.want.Items[item.Meta.SelfLink]; !ok {
@@ -330,9 +424,16 @@ func TestServiceCreate(t *testing.T) {
 	ns3.Spec.Resources.Limits.RAM, _ = resource.DecodeMemoryResource("1GB")
 	ns3.Spec.Resources.Limits.CPU, _ = resource.DecodeCpuResource("1")
 
+	ns4 := getNamespaceAsset("secure", "")
+	ns4.Spec.Security.DenyPrivileged = true
+
+	ns5 := getNamespaceAsset("quota", "")
+	ns5.Spec.Quotas.Services = 1
+
 	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
 	s2 := getServiceAsset(ns1.Meta.Name, "success", "")
 	s3 := getServiceAsset(ns3.Meta.Name, "success", "")
+	s4 := getServiceAsset(ns5.Meta.Name, "demo", "")
 
 	sm1 := getServiceManifest("errored", "image")
 	sm1.Spec.Template.Containers[0].Resources = new(request.ManifestSpecTemplateContainerResources)
@@ -377,6 +478,19 @@ func TestServiceCreate(t *testing.T) {
 	sm7.Spec.Template.Containers[0].Resources.Limits.RAM = ""
 	sm7.Spec.Template.Containers[0].Resources.Limits.CPU = ""
 
+	sm8 := getServiceManifest("errored", "image")
+	sm8.Spec.Template.Containers[0].Role = types.ContainerRoleInit
+
+	sm9 := getServiceManifest("errored", "image")
+	sm9.Spec.Template.Containers[0].Security = &request.ManifestSpecSecurity{Privileged: true}
+
+	sm10 := getExternalServiceManifest("errored", "169.254.169.254")
+	sm11 := getExternalServiceManifest("errored", "127.0.0.1")
+	sm12 := getExternalServiceManifest("errored", "172.0.0.10")
+	sm13 := getExternalServiceManifest("success", "203.0.113.10")
+
+	pool := &types.IPAMPool{Name: types.IPAMPoolService, CIDR: "172.0.0.0/24", Prefix: 32, Size: 256}
+
 	type fields struct {
 		stg storage.Storage
 	}
@@ -469,6 +583,66 @@ func TestServiceCreate(t *testing.T) {
 			wantErr:      true,
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name:         "check create service if only init containers",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm8,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad role parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create privileged service if namespace denies privileged containers",
+			args:         args{ctx, ns4, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm9,
+			err:          "{\"code\":403,\"status\":\"Forbidden\",\"message\":\"privileged containers are not allowed in namespace\"}",
+			wantErr:      true,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "check create service if services quota exceeded",
+			args:         args{ctx, ns5, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         getServiceManifest("success", "redis"),
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"service quota exceeded: 1 of 1\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create service if external ip is link local",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm10,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad external_ips parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create service if external ip is loopback",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm11,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad external_ips parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create service if external ip is in cluster services pool",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm12,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad external_ips parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 		// TODO: check another spec parameters
 		{
 			name:         "check create service success",
@@ -500,6 +674,16 @@ func TestServiceCreate(t *testing.T) {
 			wantErr:      false,
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "check create service success with external ip",
+			args:         args{ctx, ns1, s2},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         sm13,
+			want:         v1.View().Service().NewWithDeployment(s2),
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
 	}
 
 	clear := func() {
@@ -508,6 +692,9 @@ func TestServiceCreate(t *testing.T) {
 
 		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
 		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().IPAM().Pool(), types.EmptyString)
+		assert.NoError(t, err)
 	}
 
 	for _, tc := range tests {
@@ -522,9 +709,21 @@ func TestServiceCreate(t *testing.T) {
 			err = tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns3.SelfLink().String(), ns3, nil)
 			assert.NoError(t, err)
 
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns4.SelfLink().String(), ns4, nil)
+			assert.NoError(t, err)
+
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns5.SelfLink().String(), ns5, nil)
+			assert.NoError(t, err)
+
 			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), s1.SelfLink().String(), s1, nil)
 			assert.NoError(t, err)
 
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), s4.SelfLink().String(), s4, nil)
+			assert.NoError(t, err)
+
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().IPAM().Pool(), pool.Name, pool, nil)
+			assert.NoError(t, err)
+
 			// Create assert request to pass to our handler. We don't have any query parameters for now, so we'll
 			// pass 'nil' as the third parameter.
 			bd, err := tc.data.ToJson()
@@ -787,6 +986,28 @@ func TestServiceUpdate(t *testing.T) {
 			wantErr:      false,
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "checking update service if version mismatch",
+			fields:       fields{stg},
+			args:         args{ctx, ns1, s1},
+			headers:      map[string]string{"If-Match": "\"1\""},
+			handler:      service.ServiceUpdateH,
+			data:         m3,
+			err:          "{\"code\":409,\"status\":\"Conflict\",\"message\":\"Service version conflict\"}",
+			wantErr:      true,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "check update service success if version matches any",
+			fields:       fields{stg},
+			args:         args{ctx, ns1, s1},
+			headers:      map[string]string{"If-Match": "*"},
+			handler:      service.ServiceUpdateH,
+			data:         m3,
+			want:         v1.View().Service().NewWithDeployment(s3),
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
 	}
 
 	clear := func() {
@@ -857,6 +1078,7 @@ func TestServiceUpdate(t *testing.T) {
 				assert.NoError(t, err)
 
 				assert.Equal(t, tc.want.Meta.Name, s.Meta.Name, "description not equal")
+				assert.NotEmpty(t, s.Meta.ResourceVersion, "resource version is empty")
 				assert.Equal(t, tc.want.Meta.Description, s.Meta.Description, "description not equal")
 				assert.Equal(t, tc.want.Spec.Replicas, s.Spec.Replicas, "replicas not equal")
 				assert.Equal(t, tc.want.Spec.Network.IP, s.Spec.Network.IP, "network ip spec not equal")
@@ -975,6 +1197,69 @@ func TestServiceUpdate(t *testing.T) {
 
 }
 
+// Testing namespace allocation is released when service update is rejected
+func TestServiceUpdateConflictReleaseResources(t *testing.T) {
+
+	var ctx = context.Background()
+
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+
+	ns := getNamespaceAsset("limits", "")
+	ns.Status.Resources.Allocated.RAM, _ = resource.DecodeMemoryResource("512MB")
+	ns.Status.Resources.Allocated.CPU, _ = resource.DecodeCpuResource("0.5")
+	ns.Spec.Resources.Limits.RAM, _ = resource.DecodeMemoryResource("2GB")
+	ns.Spec.Resources.Limits.CPU, _ = resource.DecodeCpuResource("2")
+
+	s := getServiceAsset(ns.Meta.Name, "limited", "")
+	s.Spec.Template.Containers[0].Resources.Limits.RAM, _ = resource.DecodeMemoryResource("512MB")
+	s.Spec.Template.Containers[0].Resources.Limits.CPU, _ = resource.DecodeCpuResource("0.5")
+
+	m := getServiceManifest(s.Meta.Name, "image")
+	m.Spec.Template.Containers[0].Resources = new(request.ManifestSpecTemplateContainerResources)
+	m.Spec.Template.Containers[0].Resources.Limits = new(request.ManifestSpecTemplateContainerResource)
+	m.Spec.Template.Containers[0].Resources.Limits.RAM = "1GB"
+	m.Spec.Template.Containers[0].Resources.Limits.CPU = "1"
+
+	defer func() {
+		err := stg.Del(ctx, stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = stg.Del(ctx, stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+	}()
+
+	err := stg.Put(ctx, stg.Collection().Namespace(), ns.SelfLink().String(), ns, nil)
+	assert.NoError(t, err)
+
+	err = stg.Put(ctx, stg.Collection().Service(), s.SelfLink().String(), s, nil)
+	assert.NoError(t, err)
+
+	stale := *s
+
+	// service is changed by concurrent request after it was read
+	err = stg.Set(ctx, stg.Collection().Service(), s.SelfLink().String(), s, nil)
+	assert.NoError(t, err)
+
+	_, e := svc.Update(ctx, ns, &stale, m, new(request.ServiceUpdateOptions))
+	if assert.NotNil(t, e, "update should be rejected") {
+		assert.Equal(t, http.StatusText(http.StatusConflict), e.Code, "status code not equal")
+	}
+
+	stored := new(types.Namespace)
+	err = stg.Get(ctx, stg.Collection().Namespace(), ns.SelfLink().String(), stored, nil)
+	assert.NoError(t, err)
+
+	expect, _ := resource.DecodeMemoryResource("512MB")
+	assert.Equal(t, expect, stored.Status.Resources.Allocated.RAM, "namespace ram allocation changed")
+
+	expect, _ = resource.DecodeCpuResource("0.5")
+	assert.Equal(t, expect, stored.Status.Resources.Allocated.CPU, "namespace cpu allocation changed")
+}
+
 // Testing ServiceRemoveH handler
 func TestServiceRemove(t *testing.T) {
 
@@ -1194,3 +1479,11 @@ func getServiceManifest(name, image string) *request.ServiceManifest {
 	mf.Spec.Template.Volumes = append(mf.Spec.Template.Volumes, volume)
 	return mf
 }
+
+func getExternalServiceManifest(name, ip string) *request.ServiceManifest {
+	mf := new(request.ServiceManifest)
+	mf.Meta.Name = &name
+	mf.Spec.Network = new(request.ManifestSpecNetwork)
+	mf.Spec.Network.ExternalIPs = []string{ip}
+	return mf
+}
@@ -27,7 +27,9 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -79,7 +81,6 @@ func Apply(ctx context.Context, ns *types.Namespace, mf *request.ServiceManifest
 
 func Create(ctx context.Context, ns *types.Namespace, mf *request.ServiceManifest) (*types.Service, *errors.Err) {
 
-	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
 	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
 
 	if mf.Meta.Name != nil {
@@ -98,59 +99,134 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.ServiceManifes
 		}
 	}
 
-	svc := new(types.Service)
-	mf.SetServiceMeta(svc)
-	svc.Meta.SelfLink = *types.NewServiceSelfLink(ns.Meta.Name, *mf.Meta.Name)
-	svc.Meta.Namespace = ns.Meta.Name
-	svc.Meta.Endpoint = fmt.Sprintf("%s.%s", strings.ToLower(svc.Meta.Name), ns.Meta.Endpoint)
+	allocated := ns.Status.Resources.Allocated
 
-	if err := mf.SetServiceSpec(svc); err != nil {
-		return nil, errors.New("service").BadRequest(err.Error())
+	svc, e := prepare(ctx, ns, nil, mf, 0)
+	if e != nil {
+		return nil, e
 	}
 
-	if len(svc.Spec.Template.Containers) != 0 {
+	// resources are reserved in namespace before service is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
 
-		if ns.Spec.Resources.Limits.RAM != 0 || ns.Spec.Resources.Limits.CPU != 0 {
-			for _, c := range svc.Spec.Template.Containers {
-				if c.Resources.Limits.RAM == 0 {
-					c.Resources.Limits.RAM, _ = resource.DecodeMemoryResource(types.DEFAULT_RESOURCE_LIMITS_RAM)
-				}
-				if c.Resources.Limits.CPU == 0 {
-					c.Resources.Limits.CPU, _ = resource.DecodeCpuResource(types.DEFAULT_RESOURCE_LIMITS_CPU)
-				}
-			}
-		}
+	svc, err := sm.Create(ns, svc)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create service err: %s", logPrefix, err.Error())
+		release(ctx, ns, change)
+		return nil, errors.New("service").InternalServerError()
+	}
 
-		if err := ns.AllocateResources(svc.Spec.GetResourceRequest()); err != nil {
-			log.V(logLevel).Errorf("%s:create:> %s", logPrefix, err.Error())
-			return nil, errors.New("service").BadRequest(err.Error())
+	return svc, nil
+}
 
-		} else {
-			if err := nm.Update(ns); err != nil {
-				log.V(logLevel).Errorf("%s:update:> update namespace err: %s", logPrefix, err.Error())
-				return nil, errors.New("service").InternalServerError()
-			}
-		}
+func Update(ctx context.Context, ns *types.Namespace, svc *types.Service, mf *request.ServiceManifest, opts *request.ServiceUpdateOptions) (*types.Service, *errors.Err) {
+
+	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
+
+	allocated := ns.Status.Resources.Allocated
 
+	svc, e := prepare(ctx, ns, svc, mf, 0)
+	if e != nil {
+		return nil, e
 	}
 
-	svc, err := sm.Create(ns, svc)
+	if opts.Redeploy {
+		svc.Spec.Template.Updated = time.Now()
+	}
+
+	// resources are reserved in namespace before service is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
+
+	svc, err := sm.Update(svc, &types.UpdateOptions{Revision: &svc.Storage.Revision})
 	if err != nil {
-		log.V(logLevel).Errorf("%s:create:> create service err: %s", logPrefix, err.Error())
+		release(ctx, ns, change)
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update service err: %s", logPrefix, err.Error())
+			return nil, errors.New("service").Conflict()
+		}
+		log.V(logLevel).Errorf("%s:update:> update service err: %s", logPrefix, err.Error())
 		return nil, errors.New("service").InternalServerError()
 	}
 
 	return svc, nil
 }
 
-func Update(ctx context.Context, ns *types.Namespace, svc *types.Service, mf *request.ServiceManifest, opts *request.ServiceUpdateOptions) (*types.Service, *errors.Err) {
+// Plan returns the stored service and the service as it will be after the manifest is applied.
+// Nothing is saved to storage, but resources for the service are allocated in the passed namespace,
+// so several planned services are checked against namespace limits together.
+// Planned is count of services planned for creation in namespace before this one.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.ServiceManifest, planned int) (*types.Service, *types.Service, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("service").BadParameter("meta.name")
+	}
 
-	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
 	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
 
-	resources := svc.Spec.GetResourceRequest()
+	svc, err := sm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get service by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("service").InternalServerError()
+	}
+
+	var next *types.Service
+
+	if svc != nil {
+		next = new(types.Service)
+		if err := compare.Copy(svc, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy service `%s` err: %s", logPrefix, svc.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("service").InternalServerError()
+		}
+	}
+
+	next, e := prepare(ctx, ns, next, mf, planned)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	return svc, next, nil
+}
+
+// prepare applies manifest to stored service or to a new one if svc is nil.
+// Service is checked against namespace quotas and security policy and its resources are allocated in namespace,
+// apply and plan share it to fail the same way. Planned services are counted in quota of new service.
+func prepare(ctx context.Context, ns *types.Namespace, svc *types.Service, mf *request.ServiceManifest, planned int) (*types.Service, *errors.Err) {
+
+	var (
+		exists    = svc != nil
+		resources types.ResourceRequest
+	)
+
+	if !exists {
 
-	mf.SetServiceMeta(svc)
+		if ns.Spec.Quotas.Services > 0 {
+			sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
+			sl, err := sm.List(ns.Meta.Name, nil)
+			if err != nil {
+				log.V(logLevel).Errorf("%s:prepare:> get services in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+				return nil, errors.New("service").InternalServerError()
+			}
+
+			if err := ns.ValidateQuota(types.KindService, len(sl.Items)+planned); err != nil {
+				log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+				return nil, errors.New("service").BadRequest(err.Error())
+			}
+		}
+
+		svc = new(types.Service)
+		mf.SetServiceMeta(svc)
+		svc.Meta.SelfLink = *types.NewServiceSelfLink(ns.Meta.Name, *mf.Meta.Name)
+		svc.Meta.Namespace = ns.Meta.Name
+	} else {
+		resources = svc.Spec.GetResourceRequest()
+		mf.SetServiceMeta(svc)
+	}
 
 	svc.Meta.Endpoint = fmt.Sprintf("%s.%s", strings.ToLower(svc.Meta.Name), ns.Meta.Endpoint)
 
@@ -158,35 +234,131 @@ func Update(ctx context.Context, ns *types.Namespace, svc *types.Service, mf *re
 		return nil, errors.New("service").BadRequest(err.Error())
 	}
 
-	if opts.Redeploy {
-		svc.Spec.Template.Updated = time.Now()
+	if err := ns.ValidateSecurity(svc.Spec.Template); err != nil {
+		log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+		return nil, errors.New("service").Forbidden(err).SetMessage(err.Error())
+	}
+
+	if e := validateExternalIPs(ctx, svc); e != nil {
+		return nil, e
 	}
 
-	requestedResources := svc.Spec.GetResourceRequest()
+	if !exists && (ns.Spec.Resources.Limits.RAM != 0 || ns.Spec.Resources.Limits.CPU != 0) {
+		for _, c := range svc.Spec.Template.Containers {
+			if c.Resources.Limits.RAM == 0 {
+				c.Resources.Limits.RAM, _ = resource.DecodeMemoryResource(types.DEFAULT_RESOURCE_LIMITS_RAM)
+			}
+			if c.Resources.Limits.CPU == 0 {
+				c.Resources.Limits.CPU, _ = resource.DecodeCpuResource(types.DEFAULT_RESOURCE_LIMITS_CPU)
+			}
+		}
+	}
 
-	if !resources.Equal(requestedResources) {
+	requested := svc.Spec.GetResourceRequest()
 
-		allocatedResources := ns.Status.Resources.Allocated
+	if (!exists && len(svc.Spec.Template.Containers) != 0) || (exists && !resources.Equal(requested)) {
+
+		allocated := ns.Status.Resources.Allocated
 		ns.ReleaseResources(resources)
 
-		if err := ns.AllocateResources(svc.Spec.GetResourceRequest()); err != nil {
-			ns.Status.Resources.Allocated = allocatedResources
-			log.V(logLevel).Errorf("%s:update:> %s", logPrefix, err.Error())
+		if err := ns.AllocateResources(requested); err != nil {
+			ns.Status.Resources.Allocated = allocated
+			log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
 			return nil, errors.New("service").BadRequest(err.Error())
-		} else {
-			if err := nm.Update(ns); err != nil {
-				log.V(logLevel).Errorf("%s:update:> update namespace err: %s", logPrefix, err.Error())
-				return nil, errors.New("service").InternalServerError()
-			}
+		}
+	}
 
+	return svc, nil
+}
+
+// allocate stores change of namespace resources allocation made by prepare,
+// namespace is written with compare and swap, so concurrent allocations are not lost
+func allocate(ctx context.Context, ns *types.Namespace, change types.ResourceItem) *errors.Err {
+
+	if change == (types.ResourceItem{}) {
+		return nil
+	}
+
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+
+	if err := nm.Allocate(ns, change); err != nil {
+		if errors.IsResourcesLimitExceeded(err) {
+			log.V(logLevel).Warnf("%s:allocate:> %s", logPrefix, err.Error())
+			return errors.New("service").BadRequest(err.Error())
 		}
+		log.V(logLevel).Errorf("%s:allocate:> update namespace err: %s", logPrefix, err.Error())
+		return errors.New("service").InternalServerError()
+	}
+
+	return nil
+}
+
+// release rolls back change of namespace resources allocation if service was not saved
+func release(ctx context.Context, ns *types.Namespace, change types.ResourceItem) {
+
+	if change == (types.ResourceItem{}) {
+		return
 	}
 
-	svc, err := sm.Update(svc)
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+
+	revert := types.ResourceItem{}.Diff(change)
+	if err := ns.AllocateChange(revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> release namespace resources err: %s", logPrefix, err.Error())
+		return
+	}
+
+	if err := nm.Allocate(ns, revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> update namespace err: %s", logPrefix, err.Error())
+	}
+}
+
+// validateExternalIPs checks external addresses of service are not in cluster IPAM pools:
+// services and pods addresses are routed inside cluster and can not point outside of it
+func validateExternalIPs(ctx context.Context, svc *types.Service) *errors.Err {
+
+	if len(svc.Spec.Network.ExternalIPs) == 0 {
+		return nil
+	}
+
+	im := distribution.NewIPAMModel(ctx, envs.Get().GetStorage())
+
+	pools, err := im.PoolMap()
 	if err != nil {
-		log.V(logLevel).Errorf("%s:update:> update service err: %s", logPrefix, err.Error())
-		return nil, errors.New("service").InternalServerError()
+		log.V(logLevel).Errorf("%s:prepare:> get ipam pools err: %s", logPrefix, err.Error())
+		return errors.New("service").InternalServerError()
 	}
 
-	return svc, nil
+	for name, pool := range pools.Items {
+
+		if name == types.IPAMPoolNodePort {
+			continue
+		}
+
+		_, cidr, err := net.ParseCIDR(pool.CIDR)
+		if err != nil {
+			continue
+		}
+
+		for _, ip := range svc.Spec.Network.ExternalIPs {
+			if cidr.Contains(net.ParseIP(ip)) {
+				log.V(logLevel).Warnf("%s:prepare:> external ip %s is in cluster %s pool", logPrefix, ip, name)
+				return errors.New("service").BadParameter("external_ips")
+			}
+		}
+	}
+
+	return nil
+}
+
+func Remove(ctx context.Context, svc *types.Service) *errors.Err {
+
+	sm := distribution.NewServiceModel(ctx, envs.Get().GetStorage())
+
+	if _, err := sm.Destroy(svc); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove service `%s` err: %s", logPrefix, svc.SelfLink().String(), err.Error())
+		return errors.New("service").InternalServerError()
+	}
+
+	return nil
 }
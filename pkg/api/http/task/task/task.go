@@ -80,6 +80,9 @@ func Create(ctx context.Context, ns *types.Namespace, job *types.Job, mf *reques
 	task.Meta.SetDefault()
 	task.Meta.Namespace = ns.Meta.Name
 	task.Meta.Job = job.Meta.Name
+	for k, v := range job.Meta.Labels {
+		task.Meta.Labels[k] = v
+	}
 
 	if mf.Meta.Name != nil {
 		task.Meta.SelfLink = *types.NewTaskSelfLink(ns.Meta.Name, job.Meta.Name, *mf.Meta.Name)
@@ -101,6 +104,11 @@ func Create(ctx context.Context, ns *types.Namespace, job *types.Job, mf *reques
 		return nil, errors.New("task").BadParameter("spec")
 	}
 
+	if err := ns.ValidateSecurity(task.Spec.Template); err != nil {
+		log.V(logLevel).Warnf("%s:create:> %s", logPrefix, err.Error())
+		return nil, errors.New("task").Forbidden(err).SetMessage(err.Error())
+	}
+
 	if job.Spec.Resources.Limits.RAM != 0 || job.Spec.Resources.Limits.CPU != 0 {
 		for _, c := range task.Spec.Template.Containers {
 			if c.Resources.Limits.RAM == 0 {
@@ -116,7 +124,7 @@ func Create(ctx context.Context, ns *types.Namespace, job *types.Job, mf *reques
 		log.V(logLevel).Errorf("%s:create:> %s", logPrefix, err.Error())
 		return nil, errors.New("job").BadRequest(err.Error())
 	} else {
-		if err := jm.Set(job); err != nil {
+		if err := jm.Set(job, nil); err != nil {
 			log.V(logLevel).Errorf("%s:update:> update namespace err: %s", logPrefix, err.Error())
 			return nil, errors.New("job").InternalServerError()
 		}
@@ -321,6 +321,9 @@ func TestVolumeCreate(t *testing.T) {
 	ns1 := getNamespaceAsset("demo", "")
 	ns2 := getNamespaceAsset("test", "")
 
+	ns3 := getNamespaceAsset("limits", "")
+	ns3.Spec.Resources.Limits.Storage = 128 * 1024 * 1024
+
 	sv1 := getServiceAsset(ns1.Meta.Name, "demo", "")
 
 	vl1 := getVolumeAsset(ns1.Meta.Name, "demo")
@@ -330,6 +333,9 @@ func TestVolumeCreate(t *testing.T) {
 
 	mf1, _ := mf.ToJson()
 
+	mf.Spec.Capacity.Storage = "256MB"
+	mf2, _ := mf.ToJson()
+
 	type fields struct {
 		stg storage.Storage
 	}
@@ -372,6 +378,16 @@ func TestVolumeCreate(t *testing.T) {
 			wantErr:      true,
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name:         "check create volume if storage limit exceeded",
+			args:         args{ctx, ns3},
+			fields:       fields{stg},
+			handler:      volume.VolumeCreateH,
+			data:         string(mf2),
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"resources storage limit exceeded\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 		// TODO: need checking incoming data for validity
 		{
 			name:         "check create volume success",
@@ -405,6 +421,9 @@ func TestVolumeCreate(t *testing.T) {
 			err := tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns1.SelfLink().String(), ns1, nil)
 			assert.NoError(t, err)
 
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), ns3.SelfLink().String(), ns3, nil)
+			assert.NoError(t, err)
+
 			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), sv1.SelfLink().String(), sv1, nil)
 			assert.NoError(t, err)
 
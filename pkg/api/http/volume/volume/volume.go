@@ -26,6 +26,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"net/http"
 )
 
@@ -76,6 +77,7 @@ func Apply(ctx context.Context, ns *types.Namespace, mf *request.VolumeManifest)
 func Create(ctx context.Context, ns *types.Namespace, mf *request.VolumeManifest) (*types.Volume, *errors.Err) {
 
 	vm := distribution.NewVolumeModel(ctx, envs.Get().GetStorage())
+
 	if mf.Meta.Name != nil {
 
 		srv, err := vm.Get(ns.Meta.Name, *mf.Meta.Name)
@@ -90,16 +92,22 @@ func Create(ctx context.Context, ns *types.Namespace, mf *request.VolumeManifest
 		}
 	}
 
-	vol := new(types.Volume)
-	vol.Meta.SetDefault()
-	vol.Meta.SelfLink = *types.NewVolumeSelfLink(ns.Meta.Name, *mf.Meta.Name)
-	vol.Meta.Namespace = ns.Meta.Name
+	allocated := ns.Status.Resources.Allocated
 
-	mf.SetVolumeMeta(vol)
-	mf.SetVolumeSpec(vol)
+	vol, e := prepare(ctx, ns, nil, mf, 0)
+	if e != nil {
+		return nil, e
+	}
+
+	// resources are reserved in namespace before volume is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
 
 	if _, err := vm.Create(ns, vol); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create volume err: %s", logPrefix, ns.Meta.Name, err.Error())
+		release(ctx, ns, change)
 		return nil, errors.New("volume").InternalServerError()
 	}
 
@@ -111,13 +119,170 @@ func Update(ctx context.Context, ns *types.Namespace, vol *types.Volume, mf *req
 
 	vm := distribution.NewVolumeModel(ctx, envs.Get().GetStorage())
 
-	mf.SetVolumeMeta(vol)
-	mf.SetVolumeSpec(vol)
+	allocated := ns.Status.Resources.Allocated
+
+	vol, e := prepare(ctx, ns, vol, mf, 0)
+	if e != nil {
+		return nil, e
+	}
 
-	if err := vm.Update(vol); err != nil {
+	// resources are reserved in namespace before volume is saved and are released if it is not saved
+	change := ns.Status.Resources.Allocated.Diff(allocated)
+	if e := allocate(ctx, ns, change); e != nil {
+		return nil, e
+	}
+
+	if err := vm.Update(vol, &types.UpdateOptions{Revision: &vol.Storage.Revision}); err != nil {
+		release(ctx, ns, change)
+		if errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Warnf("%s:update:> update volume err: %s", logPrefix, err.Error())
+			return nil, errors.New("volume").Conflict()
+		}
 		log.V(logLevel).Errorf("%s:update:> update volume err: %s", logPrefix, err.Error())
 		return nil, errors.New("volume").InternalServerError()
 	}
 
 	return vol, nil
 }
+
+// Plan returns the stored volume and the volume as it will be after the manifest is applied.
+// Nothing is saved to storage, but storage for the volume is allocated in the passed namespace,
+// so several planned volumes are checked against namespace limits together.
+// Planned is count of volumes planned for creation in namespace before this one.
+func Plan(ctx context.Context, ns *types.Namespace, mf *request.VolumeManifest, planned int) (*types.Volume, *types.Volume, *errors.Err) {
+
+	if mf.Meta.Name == nil {
+		return nil, nil, errors.New("volume").BadParameter("meta.name")
+	}
+
+	vm := distribution.NewVolumeModel(ctx, envs.Get().GetStorage())
+
+	vol, err := vm.Get(ns.Meta.Name, *mf.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:plan:> get volume by name `%s` in namespace `%s` err: %s", logPrefix, *mf.Meta.Name, ns.Meta.Name, err.Error())
+		return nil, nil, errors.New("volume").InternalServerError()
+	}
+
+	var next *types.Volume
+
+	if vol != nil {
+		next = new(types.Volume)
+		if err := compare.Copy(vol, next); err != nil {
+			log.V(logLevel).Errorf("%s:plan:> copy volume `%s` err: %s", logPrefix, vol.SelfLink().String(), err.Error())
+			return nil, nil, errors.New("volume").InternalServerError()
+		}
+	}
+
+	next, e := prepare(ctx, ns, next, mf, planned)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	return vol, next, nil
+}
+
+// prepare applies manifest to stored volume or to a new one if vol is nil.
+// Volume is checked against namespace quotas and its storage is allocated in namespace,
+// apply and plan share it to fail the same way. Planned volumes are counted in quota of new volume.
+func prepare(ctx context.Context, ns *types.Namespace, vol *types.Volume, mf *request.VolumeManifest, planned int) (*types.Volume, *errors.Err) {
+
+	var (
+		exists  = vol != nil
+		storage int64
+	)
+
+	if !exists {
+
+		if ns.Spec.Quotas.Volumes > 0 {
+			vm := distribution.NewVolumeModel(ctx, envs.Get().GetStorage())
+			vl, err := vm.ListByNamespace(ns.Meta.Name, nil)
+			if err != nil {
+				log.V(logLevel).Errorf("%s:prepare:> get volumes in namespace `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
+				return nil, errors.New("volume").InternalServerError()
+			}
+
+			if err := ns.ValidateQuota(types.KindVolume, len(vl.Items)+planned); err != nil {
+				log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+				return nil, errors.New("volume").BadRequest(err.Error())
+			}
+		}
+
+		vol = new(types.Volume)
+		vol.Meta.SetDefault()
+		vol.Meta.SelfLink = *types.NewVolumeSelfLink(ns.Meta.Name, *mf.Meta.Name)
+		vol.Meta.Namespace = ns.Meta.Name
+	} else {
+		storage = vol.Spec.Capacity.Storage
+	}
+
+	mf.SetVolumeMeta(vol)
+	mf.SetVolumeSpec(vol)
+
+	if !exists || storage != vol.Spec.Capacity.Storage {
+
+		allocated := ns.Status.Resources.Allocated.Storage
+		ns.ReleaseStorage(storage)
+
+		if err := ns.AllocateStorage(vol.Spec.Capacity.Storage); err != nil {
+			ns.Status.Resources.Allocated.Storage = allocated
+			log.V(logLevel).Warnf("%s:prepare:> %s", logPrefix, err.Error())
+			return nil, errors.New("volume").BadRequest(err.Error())
+		}
+	}
+
+	return vol, nil
+}
+
+// allocate stores change of namespace resources allocation made by prepare,
+// namespace is written with compare and swap, so concurrent allocations are not lost
+func allocate(ctx context.Context, ns *types.Namespace, change types.ResourceItem) *errors.Err {
+
+	if change == (types.ResourceItem{}) {
+		return nil
+	}
+
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+
+	if err := nm.Allocate(ns, change); err != nil {
+		if errors.IsResourcesLimitExceeded(err) {
+			log.V(logLevel).Warnf("%s:allocate:> %s", logPrefix, err.Error())
+			return errors.New("volume").BadRequest(err.Error())
+		}
+		log.V(logLevel).Errorf("%s:allocate:> update namespace err: %s", logPrefix, err.Error())
+		return errors.New("volume").InternalServerError()
+	}
+
+	return nil
+}
+
+// release rolls back change of namespace resources allocation if volume was not saved
+func release(ctx context.Context, ns *types.Namespace, change types.ResourceItem) {
+
+	if change == (types.ResourceItem{}) {
+		return
+	}
+
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+
+	revert := types.ResourceItem{}.Diff(change)
+	if err := ns.AllocateChange(revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> release namespace resources err: %s", logPrefix, err.Error())
+		return
+	}
+
+	if err := nm.Allocate(ns, revert); err != nil {
+		log.V(logLevel).Errorf("%s:release:> update namespace err: %s", logPrefix, err.Error())
+	}
+}
+
+func Remove(ctx context.Context, vol *types.Volume) *errors.Err {
+
+	vm := distribution.NewVolumeModel(ctx, envs.Get().GetStorage())
+
+	if err := vm.Destroy(vol); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove volume `%s` err: %s", logPrefix, vol.SelfLink().String(), err.Error())
+		return errors.New("volume").InternalServerError()
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logPolicyPrefix = "api:runtime:policy"
+
+// policyState keeps namespaces, network policies and pods addresses
+// to build network policy manifests for nodes
+type policyState struct {
+	lock       sync.Mutex
+	namespaces map[string]bool
+	policies   map[string]map[string]*types.NetworkPolicySpec
+	pods       map[string]map[string]*types.NetworkPolicyPod
+}
+
+func (s *policyState) setNamespace(ns *types.Namespace) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deny, ok := s.namespaces[ns.Meta.Name]
+	s.namespaces[ns.Meta.Name] = ns.Spec.Security.DenyNetwork
+
+	return !ok || deny != ns.Spec.Security.DenyNetwork
+}
+
+func (s *policyState) delNamespace(namespace string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.namespaces, namespace)
+	delete(s.policies, namespace)
+	delete(s.pods, namespace)
+}
+
+func (s *policyState) setPolicy(p *types.NetworkPolicy) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.policies[p.Meta.Namespace]; !ok {
+		s.policies[p.Meta.Namespace] = make(map[string]*types.NetworkPolicySpec, 0)
+	}
+
+	spec := p.Spec
+	s.policies[p.Meta.Namespace][p.Meta.Name] = &spec
+}
+
+func (s *policyState) delPolicy(p *types.NetworkPolicy) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.policies[p.Meta.Namespace]; ok {
+		delete(s.policies[p.Meta.Namespace], p.Meta.Name)
+	}
+}
+
+// setPod returns true if pod address or labels are changed
+func (s *policyState) setPod(p *types.Pod) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.pods[p.Meta.Namespace]; !ok {
+		s.pods[p.Meta.Namespace] = make(map[string]*types.NetworkPolicyPod, 0)
+	}
+
+	pod := &types.NetworkPolicyPod{
+		IP:     p.Status.Network.PodIP,
+		IPv6:   p.Status.Network.PodIPv6,
+		Labels: p.Meta.Labels,
+	}
+
+	if item, ok := s.pods[p.Meta.Namespace][p.SelfLink().String()]; ok {
+		if item.IP == pod.IP && item.IPv6 == pod.IPv6 && reflect.DeepEqual(item.Labels, pod.Labels) {
+			return false
+		}
+	}
+
+	s.pods[p.Meta.Namespace][p.SelfLink().String()] = pod
+	return true
+}
+
+// delPod returns true if pod was known
+func (s *policyState) delPod(p *types.Pod) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.pods[p.Meta.Namespace][p.SelfLink().String()]; !ok {
+		return false
+	}
+
+	delete(s.pods[p.Meta.Namespace], p.SelfLink().String())
+	return true
+}
+
+// manifest returns new network policy manifest of namespace
+func (s *policyState) manifest(namespace string) *types.NetworkPolicyManifest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m := new(types.NetworkPolicyManifest)
+	m.State = types.StateReady
+	m.Deny = s.namespaces[namespace]
+	m.Policies = make(map[string]*types.NetworkPolicySpec, 0)
+	m.Pods = make(map[string]*types.NetworkPolicyPod, 0)
+
+	for name, spec := range s.policies[namespace] {
+		m.Policies[name] = spec
+	}
+
+	for name, pod := range s.pods[namespace] {
+		m.Pods[name] = pod
+	}
+
+	return m
+}
+
+func newPolicyState() *policyState {
+	s := new(policyState)
+	s.namespaces = make(map[string]bool, 0)
+	s.policies = make(map[string]map[string]*types.NetworkPolicySpec, 0)
+	s.pods = make(map[string]map[string]*types.NetworkPolicyPod, 0)
+	return s
+}
+
+// networkPolicyRestore builds network policy manifests for all namespaces
+// and starts watching changes of namespaces, policies and pods
+func (r *Runtime) networkPolicyRestore(ctx context.Context) error {
+
+	var (
+		c   = envs.Get().GetCache()
+		stg = envs.Get().GetStorage()
+		nm  = distribution.NewNamespaceModel(ctx, stg)
+		npm = distribution.NewNetworkPolicyModel(ctx, stg)
+		pm  = distribution.NewPodModel(ctx, stg)
+		ps  = newPolicyState()
+	)
+
+	pr, err := pm.Runtime()
+	if err != nil {
+		log.Errorf("%s:restore:> get pods runtime err: %s", logPolicyPrefix, err.Error())
+		return err
+	}
+
+	npr, err := npm.Runtime()
+	if err != nil {
+		log.Errorf("%s:restore:> get policies runtime err: %s", logPolicyPrefix, err.Error())
+		return err
+	}
+
+	nl, err := nm.List(nil)
+	if err != nil {
+		log.Errorf("%s:restore:> get namespaces err: %s", logPolicyPrefix, err.Error())
+		return err
+	}
+
+	pl, err := npm.List(types.EmptyString, nil)
+	if err != nil {
+		log.Errorf("%s:restore:> get policies err: %s", logPolicyPrefix, err.Error())
+		return err
+	}
+
+	for _, p := range pl.Items {
+		ps.setPolicy(p)
+	}
+
+	for _, ns := range nl.Items {
+
+		ps.setNamespace(ns)
+
+		pods, err := pm.ListByNamespace(ns.Meta.Name, nil)
+		if err != nil {
+			log.Errorf("%s:restore:> get pods in namespace `%s` err: %s", logPolicyPrefix, ns.Meta.Name, err.Error())
+			return err
+		}
+
+		for _, p := range pods.Items {
+			if p.Status.State != types.StateDestroyed {
+				ps.setPod(p)
+			}
+		}
+
+		c.Node().SetNetworkPolicyManifest(ns.Meta.Name, ps.manifest(ns.Meta.Name))
+	}
+
+	go r.networkPolicyNamespaceWatch(ctx, ps)
+	go r.networkPolicyWatch(ctx, ps, &npr.Storage.Revision)
+	go r.networkPolicyPodWatch(ctx, ps, &pr.Storage.Revision)
+
+	return nil
+}
+
+func (r *Runtime) networkPolicyNamespaceWatch(ctx context.Context, ps *policyState) {
+
+	var (
+		n = make(chan types.NamespaceEvent)
+		c = envs.Get().GetCache()
+	)
+
+	nm := distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-n:
+
+				if w.Data == nil {
+					continue
+				}
+
+				if w.IsActionRemove() {
+					ps.delNamespace(w.Data.Meta.Name)
+					c.Node().SetNetworkPolicyManifest(w.Data.Meta.Name, &types.NetworkPolicyManifest{State: types.StateDestroy})
+					continue
+				}
+
+				if ps.setNamespace(w.Data) {
+					c.Node().SetNetworkPolicyManifest(w.Data.Meta.Name, ps.manifest(w.Data.Meta.Name))
+				}
+			}
+		}
+	}()
+
+	nm.Watch(n)
+}
+
+func (r *Runtime) networkPolicyWatch(ctx context.Context, ps *policyState, rev *int64) {
+
+	var (
+		n = make(chan types.NetworkPolicyEvent)
+		c = envs.Get().GetCache()
+	)
+
+	npm := distribution.NewNetworkPolicyModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-n:
+
+				if w.Data == nil {
+					continue
+				}
+
+				if w.IsActionRemove() {
+					ps.delPolicy(w.Data)
+				} else {
+					ps.setPolicy(w.Data)
+				}
+
+				c.Node().SetNetworkPolicyManifest(w.Data.Meta.Namespace, ps.manifest(w.Data.Meta.Namespace))
+			}
+		}
+	}()
+
+	npm.Watch(n, rev)
+}
+
+func (r *Runtime) networkPolicyPodWatch(ctx context.Context, ps *policyState, rev *int64) {
+
+	var (
+		n = make(chan types.PodEvent)
+		c = envs.Get().GetCache()
+	)
+
+	pm := distribution.NewPodModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-n:
+
+				if w.Data == nil {
+					continue
+				}
+
+				var changed bool
+
+				if w.IsActionRemove() || w.Data.Status.State == types.StateDestroyed {
+					changed = ps.delPod(w.Data)
+				} else {
+					changed = ps.setPod(w.Data)
+				}
+
+				if changed {
+					c.Node().SetNetworkPolicyManifest(w.Data.Meta.Namespace, ps.manifest(w.Data.Meta.Namespace))
+				}
+			}
+		}
+	}()
+
+	pm.Watch(n, rev)
+}
@@ -76,7 +76,7 @@ func (r *Runtime) Run() {
 	}
 
 	cm := distribution.NewConfigModel(ctx, envs.Get().GetStorage())
-	cl, err := cm.List(types.EmptyString)
+	cl, err := cm.List(types.EmptyString, nil)
 	if err != nil {
 		return
 	}
@@ -89,8 +89,12 @@ func (r *Runtime) Run() {
 		c.Node().SetConfigManifest(i.SelfLink().String(), m)
 	}
 
+	if err := r.networkPolicyRestore(ctx); err != nil {
+		return
+	}
+
 	dm := distribution.NewDiscoveryModel(ctx, envs.Get().GetStorage())
-	dl, err := dm.List()
+	dl, err := dm.List(nil)
 	if err != nil {
 		return
 	}
@@ -101,7 +105,7 @@ func (r *Runtime) Run() {
 	}
 
 	em := distribution.NewExporterModel(ctx, envs.Get().GetStorage())
-	el, err := em.List()
+	el, err := em.List(nil)
 	if err != nil {
 		return
 	}
@@ -42,6 +42,8 @@ func (s *DeploymentManifest) Validate() *errors.Err {
 		return errors.New("deployment").BadParameter("description")
 	case len(s.Spec.Template.Containers) == 0:
 		return errors.New("deployment").BadParameter("spec")
+	case !s.Spec.Template.validContainerRoles():
+		return errors.New("deployment").BadParameter("role")
 	case len(s.Spec.Template.Containers) != 0:
 		for _, container := range s.Spec.Template.Containers {
 			if len(container.Image.Name) == 0 {
@@ -42,6 +42,9 @@ func (j *JobManifest) Validate() *errors.Err {
 		if len(j.Spec.Task.Template.Containers) == 0 {
 			return errors.New("job").BadParameter("spec")
 		}
+		if !j.Spec.Task.Template.validContainerRoles() {
+			return errors.New("job").BadParameter("role")
+		}
 		if len(j.Spec.Task.Template.Containers) != 0 {
 			for _, container := range j.Spec.Task.Template.Containers {
 				if len(container.Image.Name) == 0 {
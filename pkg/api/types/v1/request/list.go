@@ -0,0 +1,32 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+// swagger:ignore
+// ListOptions is used to filter objects in list requests
+type ListOptions struct {
+	// Label selector, like: app=web,tier!=db
+	LabelSelector string `json:"labelSelector"`
+	// Field selector, like: status.state=error
+	FieldSelector string `json:"fieldSelector"`
+	// Max items count in page, 0 means no limit
+	Limit int64 `json:"limit"`
+	// Continue token returned with the previous page
+	Continue string `json:"continue"`
+}
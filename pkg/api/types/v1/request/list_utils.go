@@ -0,0 +1,84 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type ListRequest struct{}
+
+func (ListRequest) Options() *ListOptions {
+	return new(ListOptions)
+}
+
+func (l *ListOptions) Validate() (*types.Selector, *errors.Err) {
+
+	if l.Limit < 0 {
+		return nil, errors.New("list").BadParameter("limit")
+	}
+
+	selector, err := types.ParseSelector(l.LabelSelector, l.FieldSelector)
+	if err != nil {
+		return nil, errors.New("list").BadRequest(err.Error())
+	}
+	return selector, nil
+}
+
+func (l *ListOptions) DecodeAndValidate(values url.Values) (*types.Selector, *errors.Err) {
+
+	if values != nil {
+		l.LabelSelector = values.Get("labelSelector")
+		l.FieldSelector = values.Get("fieldSelector")
+		l.Continue = values.Get("continue")
+
+		if limit := values.Get("limit"); limit != "" {
+			i, err := strconv.ParseInt(limit, 10, 64)
+			if err != nil {
+				return nil, errors.New("list").BadParameter("limit", err)
+			}
+			l.Limit = i
+		}
+	}
+
+	return l.Validate()
+}
+
+// Paged returns true if list page is requested
+func (l *ListOptions) Paged() bool {
+	return l.Limit > 0 || l.Continue != ""
+}
+
+// Page returns storage list options for requested page of objects matched by selector
+func (l *ListOptions) Page(selector *types.Selector) *types.ListOptions {
+
+	if !l.Paged() && selector.Empty() {
+		return nil
+	}
+
+	return &types.ListOptions{
+		Limit:    l.Limit,
+		Continue: l.Continue,
+		Selector: selector,
+	}
+}
@@ -22,6 +22,9 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
+	"github.com/lastbackend/lastbackend/pkg/util/validator"
+	"net"
+	"reflect"
 	"strings"
 	"time"
 
@@ -34,8 +37,17 @@ type ManifestSpecSelector struct {
 }
 
 type ManifestSpecNetwork struct {
-	IP    *string  `json:"ip,omitempty" yaml:"ip,omitempty"`
-	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	IP        *string           `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Ports     []string          `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Route     *string           `json:"route,omitempty" yaml:"route,omitempty"`
+	Affinity  *int              `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+	Weights   map[string]int    `json:"weights,omitempty" yaml:"weights,omitempty"`
+	Expose    *bool             `json:"expose,omitempty" yaml:"expose,omitempty"`
+	Headless  *bool             `json:"headless,omitempty" yaml:"headless,omitempty"`
+	PortNames map[uint16]string `json:"port_names,omitempty" yaml:"port_names,omitempty"`
+	// External DNS name or addresses service domain points to
+	ExternalName *string  `json:"external_name,omitempty" yaml:"external_name,omitempty"`
+	ExternalIPs  []string `json:"external_ips,omitempty" yaml:"external_ips,omitempty"`
 }
 
 type ManifestSpecStrategy struct {
@@ -55,12 +67,15 @@ type ManifestSpecRuntimeTask struct {
 }
 
 type ManifestSpecTemplate struct {
-	Containers []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Volumes    []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Containers  []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Volumes     []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Termination *int                            `json:"termination,omitempty" yaml:"termination,omitempty"`
 }
 
 type ManifestSpecTemplateContainer struct {
 	Name          string                                  `json:"name,omitempty" yaml:"name,omitempty"`
+	Role          string                                  `json:"role,omitempty" yaml:"role,omitempty"`
+	Order         int                                     `json:"order,omitempty" yaml:"order,omitempty"`
 	Command       string                                  `json:"command,omitempty" yaml:"command,omitempty"`
 	Workdir       string                                  `json:"workdir,omitempty" yaml:"workdir,omitempty"`
 	Entrypoint    string                                  `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
@@ -72,6 +87,8 @@ type ManifestSpecTemplateContainer struct {
 	Resources     *ManifestSpecTemplateContainerResources `json:"resources,omitempty" yaml:"resources,omitempty"`
 	RestartPolicy *ManifestSpecTemplateRestartPolicy      `json:"restart,omitempty" yaml:"restart,omitempty"`
 	Security      *ManifestSpecSecurity                   `json:"security,omitempty" yaml:"security,omitempty"`
+	Probes        *ManifestSpecTemplateContainerProbes    `json:"probes,omitempty" yaml:"probes,omitempty"`
+	Lifecycle     *ManifestSpecTemplateContainerLifecycle `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
 }
 
 type ManifestSpecTemplateContainerEnv struct {
@@ -177,7 +194,90 @@ type ManifestSpecTemplateRestartPolicy struct {
 }
 
 type ManifestSpecSecurity struct {
+	// Start container in privileged mode
 	Privileged bool `json:"privileged"`
+	// Run container process as user id
+	User int `json:"user,omitempty" yaml:"user,omitempty"`
+	// Run container process as group id
+	Group int `json:"group,omitempty" yaml:"group,omitempty"`
+	// Supplemental group ids of container process
+	Groups []int `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Linux capabilities to add and drop
+	Capabilities *ManifestSpecSecurityCapabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	// Mount container root filesystem as read only
+	ReadOnlyRootfs bool `json:"readonly_rootfs,omitempty" yaml:"readonly_rootfs,omitempty"`
+	// Do not allow container process to gain new privileges
+	NoNewPrivileges bool `json:"no_new_privileges,omitempty" yaml:"no_new_privileges,omitempty"`
+	// Seccomp profile: unconfined, runtime/default or localhost/<path to profile on node>
+	Seccomp string `json:"seccomp,omitempty" yaml:"seccomp,omitempty"`
+	// AppArmor profile: unconfined, runtime/default or localhost/<profile name>
+	AppArmor string `json:"apparmor,omitempty" yaml:"apparmor,omitempty"`
+	// SELinux label options
+	SELinux *ManifestSpecSecuritySELinux `json:"selinux,omitempty" yaml:"selinux,omitempty"`
+}
+
+type ManifestSpecSecurityCapabilities struct {
+	Add  []string `json:"add,omitempty" yaml:"add,omitempty"`
+	Drop []string `json:"drop,omitempty" yaml:"drop,omitempty"`
+}
+
+type ManifestSpecSecuritySELinux struct {
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
+	Role  string `json:"role,omitempty" yaml:"role,omitempty"`
+	Type  string `json:"type,omitempty" yaml:"type,omitempty"`
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbes struct {
+	// Readiness probe, containers with greater order wait for it
+	Ready *ManifestSpecTemplateContainerProbe `json:"ready,omitempty" yaml:"ready,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbe struct {
+	// Exec command in container, zero exit code is success
+	Exec *ManifestSpecTemplateContainerHookExec `json:"exec,omitempty" yaml:"exec,omitempty"`
+	// Socket to check container readiness
+	Socket *ManifestSpecTemplateContainerProbeSocket `json:"socket,omitempty" yaml:"socket,omitempty"`
+	// Send http GET request to container, status below 400 is success
+	HTTP *ManifestSpecTemplateContainerHookHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+	// Seconds to wait before the first check
+	InitialDelay int `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+	// Seconds to wait for a single check
+	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Seconds between checks
+	Period int `json:"period,omitempty" yaml:"period,omitempty"`
+	// Successful checks in a row to become ready
+	ThresholdSuccess int `json:"threshold_success,omitempty" yaml:"threshold_success,omitempty"`
+	// Failed checks in a row to give up
+	ThresholdFailure int `json:"threshold_failure,omitempty" yaml:"threshold_failure,omitempty"`
+	// Seconds to wait for container to become ready after start
+	StartupTimeout int `json:"startup_timeout,omitempty" yaml:"startup_timeout,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbeSocket struct {
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+type ManifestSpecTemplateContainerLifecycle struct {
+	// Hook is called before container is stopped
+	PreStop *ManifestSpecTemplateContainerHook `json:"pre_stop,omitempty" yaml:"pre_stop,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHook struct {
+	// Exec command in container
+	Exec *ManifestSpecTemplateContainerHookExec `json:"exec,omitempty" yaml:"exec,omitempty"`
+	// Send http GET request to container
+	HTTP *ManifestSpecTemplateContainerHookHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookExec struct {
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookHTTP struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
 }
 
 func (m ManifestSpecSelector) GetSpec() types.SpecSelector {
@@ -202,6 +302,10 @@ func (m ManifestSpecTemplate) GetSpec() types.SpecTemplate {
 		s.Volumes = append(s.Volumes, &sp)
 	}
 
+	if m.Termination != nil {
+		s.Termination = *m.Termination
+	}
+
 	return s
 }
 
@@ -303,6 +407,8 @@ func (m ManifestSpecTemplateVolume) GetSpec() types.SpecTemplateVolume {
 func (m ManifestSpecTemplateContainer) GetSpec() types.SpecTemplateContainer {
 	s := types.SpecTemplateContainer{}
 	s.Name = m.Name
+	s.Role = m.Role
+	s.Order = m.Order
 
 	s.RestartPolicy.Policy = "always"
 
@@ -356,7 +462,15 @@ func (m ManifestSpecTemplateContainer) GetSpec() types.SpecTemplateContainer {
 	s.Image.Secret.Key = m.Image.Secret.Key
 
 	if m.Security != nil {
-		s.Security.Privileged = m.Security.Privileged
+		s.Security = m.Security.GetSpec()
+	}
+
+	if m.Probes != nil && m.Probes.Ready != nil {
+		m.Probes.Ready.SetSpecProbe(&s.Probes.ReadProbe)
+	}
+
+	if m.Lifecycle != nil && m.Lifecycle.PreStop != nil {
+		s.Lifecycle.PreStop = m.Lifecycle.PreStop.GetSpec()
 	}
 
 	if m.Resources != nil {
@@ -575,6 +689,11 @@ func (m ManifestSpecSelector) SetSpecSelector(ss *types.SpecSelector) {
 
 func (m ManifestSpecTemplate) SetSpecTemplate(st *types.SpecTemplate) error {
 
+	if m.Termination != nil && st.Termination != *m.Termination {
+		st.Termination = *m.Termination
+		st.Updated = time.Now()
+	}
+
 	for _, c := range m.Containers {
 
 		var (
@@ -635,8 +754,42 @@ func (m ManifestSpecTemplate) SetSpecTemplate(st *types.SpecTemplate) error {
 			st.Updated = time.Now()
 		}
 
-		if c.Security != nil && spec.Security.Privileged != c.Security.Privileged {
-			spec.Security.Privileged = c.Security.Privileged
+		var security = types.SpecTemplateContainerSecurity{}
+		if c.Security != nil {
+			security = c.Security.GetSpec()
+		}
+
+		if !reflect.DeepEqual(spec.Security, security) {
+			spec.Security = security
+			st.Updated = time.Now()
+		}
+
+		if spec.Role != c.Role || spec.Order != c.Order {
+			spec.Role = c.Role
+			spec.Order = c.Order
+			st.Updated = time.Now()
+		}
+
+		var probe = types.SpecTemplateContainerProbe{}
+		if c.Probes != nil && c.Probes.Ready != nil {
+			c.Probes.Ready.SetSpecProbe(&probe)
+		}
+		if len(probe.Exec.Command) == 0 && len(spec.Probes.ReadProbe.Exec.Command) == 0 {
+			probe.Exec = spec.Probes.ReadProbe.Exec
+		}
+
+		if !reflect.DeepEqual(spec.Probes.ReadProbe, probe) {
+			spec.Probes.ReadProbe = probe
+			st.Updated = time.Now()
+		}
+
+		var lifecycle = types.SpecTemplateContainerLifecycle{}
+		if c.Lifecycle != nil && c.Lifecycle.PreStop != nil {
+			lifecycle.PreStop = c.Lifecycle.PreStop.GetSpec()
+		}
+
+		if !reflect.DeepEqual(spec.Lifecycle, lifecycle) {
+			spec.Lifecycle = lifecycle
 			st.Updated = time.Now()
 		}
 
@@ -1139,6 +1292,10 @@ func (m ManifestSpecSelector) SetManifestSpecSelector(ss *types.ManifestSpecSele
 
 func (m ManifestSpecTemplate) SetManifestSpecTemplate(st *types.ManifestSpecTemplate) error {
 
+	if m.Termination != nil {
+		st.Termination = *m.Termination
+	}
+
 	for _, c := range m.Containers {
 
 		var (
@@ -1190,8 +1347,22 @@ func (m ManifestSpecTemplate) SetManifestSpecTemplate(st *types.ManifestSpecTemp
 			spec.Workdir = c.Workdir
 		}
 
-		if c.Security != nil && spec.Security.Privileged != c.Security.Privileged {
-			spec.Security.Privileged = c.Security.Privileged
+		spec.Security = types.ManifestSpecSecurity{}
+		if c.Security != nil {
+			spec.Security = c.Security.GetManifestSecurity()
+		}
+
+		spec.Role = c.Role
+		spec.Order = c.Order
+
+		spec.Probes = types.ManifestSpecTemplateContainerProbes{}
+		if c.Probes != nil && c.Probes.Ready != nil {
+			spec.Probes.Ready = c.Probes.Ready.GetManifestProbe()
+		}
+
+		spec.Lifecycle = types.ManifestSpecTemplateContainerLifecycle{}
+		if c.Lifecycle != nil && c.Lifecycle.PreStop != nil {
+			spec.Lifecycle.PreStop = c.Lifecycle.PreStop.GetManifestHook()
 		}
 
 		if c.RestartPolicy != nil && (spec.RestartPolicy.Policy != c.RestartPolicy.Policy || spec.RestartPolicy.Attempt != c.RestartPolicy.Attempt) {
@@ -1445,6 +1616,259 @@ func (m ManifestSpecTemplate) SetManifestSpecTemplate(st *types.ManifestSpecTemp
 	return nil
 }
 
+// validContainerRoles checks container roles and requires
+// at least one container which is not an init container
+func (m ManifestSpecTemplate) validContainerRoles() bool {
+
+	if len(m.Containers) == 0 {
+		return true
+	}
+
+	var services int
+	for _, c := range m.Containers {
+		switch c.Role {
+		case types.EmptyString, types.ContainerRolePrimary, types.ContainerRoleSlave:
+			services++
+		case types.ContainerRoleInit:
+		default:
+			return false
+		}
+	}
+
+	return services > 0
+}
+
+// validRoute checks network route strategy is supported by proxy
+func (m ManifestSpecNetwork) validRoute() bool {
+	return m.Route == nil || types.IsValidRouteStrategy(*m.Route)
+}
+
+// validAffinity checks session affinity timeout is in allowed range
+func (m ManifestSpecNetwork) validAffinity() bool {
+	return m.Affinity == nil || (*m.Affinity >= 0 && *m.Affinity <= types.EndpointSpecAffinityMax)
+}
+
+// validWeights checks upstreams weights are set for valid addresses and are in allowed range
+func (m ManifestSpecNetwork) validWeights() bool {
+	for up, w := range m.Weights {
+		if net.ParseIP(up) == nil || w < 1 || w > types.EndpointSpecWeightMax {
+			return false
+		}
+	}
+	return true
+}
+
+// validHeadless checks headless service neither requests virtual IP nor exposes node ports
+func (m ManifestSpecNetwork) validHeadless() bool {
+	if m.Headless == nil || !*m.Headless {
+		return true
+	}
+	return (m.IP == nil || *m.IP == types.EmptyString) && (m.Expose == nil || !*m.Expose)
+}
+
+// validPortNames checks ports names can be used as SRV record service labels
+func (m ManifestSpecNetwork) validPortNames() bool {
+	for _, name := range m.PortNames {
+		if !validator.IsPortName(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// validExternalName checks external name is valid domain and service with external name
+// has neither external addresses nor virtual IP, node ports and headless mode
+func (m ManifestSpecNetwork) validExternalName() bool {
+	if m.ExternalName == nil || *m.ExternalName == types.EmptyString {
+		return true
+	}
+
+	switch true {
+	case !validator.IsDomain(*m.ExternalName):
+		return false
+	case len(m.ExternalIPs) > 0:
+		return false
+	case m.IP != nil && *m.IP != types.EmptyString:
+		return false
+	case m.Expose != nil && *m.Expose:
+		return false
+	case m.Headless != nil && *m.Headless:
+		return false
+	}
+
+	return true
+}
+
+// validExternalIPs checks external addresses are valid and not used by headless service,
+// host local, link local and multicast addresses can not be upstreams of service:
+// traffic to them is handled by node itself, like metadata service at 169.254.169.254
+func (m ManifestSpecNetwork) validExternalIPs() bool {
+	if len(m.ExternalIPs) == 0 {
+		return true
+	}
+
+	if m.Headless != nil && *m.Headless {
+		return false
+	}
+
+	for _, addr := range m.ExternalIPs {
+		ip := net.ParseIP(addr)
+
+		switch true {
+		case ip == nil:
+			return false
+		case ip.IsUnspecified(), ip.IsLoopback():
+			return false
+		case ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+			return false
+		case ip.IsMulticast():
+			return false
+		}
+	}
+
+	return true
+}
+
+// isExternal checks network points service outside of cluster
+func (m ManifestSpecNetwork) isExternal() bool {
+	return (m.ExternalName != nil && *m.ExternalName != types.EmptyString) || len(m.ExternalIPs) > 0
+}
+
+func (m ManifestSpecTemplateContainerProbe) SetSpecProbe(p *types.SpecTemplateContainerProbe) {
+	if m.Exec != nil {
+		p.Exec.Command = m.Exec.Command
+	}
+
+	if m.Socket != nil {
+		p.Socket.Protocol = m.Socket.Protocol
+		p.Socket.Port = m.Socket.Port
+	}
+
+	if m.HTTP != nil {
+		p.HTTP.Path = m.HTTP.Path
+		p.HTTP.Port = m.HTTP.Port
+	}
+
+	p.InitialDelaySeconds = m.InitialDelay
+	p.TimeoutSeconds = m.Timeout
+	p.PeriodSeconds = m.Period
+	p.ThresholdSuccess = m.ThresholdSuccess
+	p.ThresholdFailure = m.ThresholdFailure
+	p.StartupTimeoutSeconds = m.StartupTimeout
+}
+
+func (m ManifestSpecTemplateContainerProbe) GetManifestProbe() types.ManifestSpecTemplateContainerProbe {
+	p := types.ManifestSpecTemplateContainerProbe{
+		InitialDelay:     m.InitialDelay,
+		Timeout:          m.Timeout,
+		Period:           m.Period,
+		ThresholdSuccess: m.ThresholdSuccess,
+		ThresholdFailure: m.ThresholdFailure,
+		StartupTimeout:   m.StartupTimeout,
+	}
+
+	if m.Exec != nil {
+		p.Exec.Command = m.Exec.Command
+	}
+
+	if m.Socket != nil {
+		p.Socket.Protocol = m.Socket.Protocol
+		p.Socket.Port = m.Socket.Port
+	}
+
+	if m.HTTP != nil {
+		p.HTTP.Path = m.HTTP.Path
+		p.HTTP.Port = m.HTTP.Port
+	}
+
+	return p
+}
+
+func (m ManifestSpecTemplateContainerHook) GetSpec() types.SpecTemplateContainerHook {
+	h := types.SpecTemplateContainerHook{}
+
+	if m.Exec != nil {
+		h.Exec.Command = m.Exec.Command
+	}
+
+	if m.HTTP != nil {
+		h.HTTP.Path = m.HTTP.Path
+		h.HTTP.Port = m.HTTP.Port
+	}
+
+	return h
+}
+
+func (m ManifestSpecTemplateContainerHook) GetManifestHook() types.ManifestSpecTemplateContainerHook {
+	h := types.ManifestSpecTemplateContainerHook{}
+
+	if m.Exec != nil {
+		h.Exec.Command = m.Exec.Command
+	}
+
+	if m.HTTP != nil {
+		h.HTTP.Path = m.HTTP.Path
+		h.HTTP.Port = m.HTTP.Port
+	}
+
+	return h
+}
+
+func (m ManifestSpecSecurity) GetSpec() types.SpecTemplateContainerSecurity {
+	s := types.SpecTemplateContainerSecurity{
+		Privileged:      m.Privileged,
+		User:            m.User,
+		Group:           m.Group,
+		Groups:          m.Groups,
+		ReadOnlyRootfs:  m.ReadOnlyRootfs,
+		NoNewPrivileges: m.NoNewPrivileges,
+	}
+
+	if m.Capabilities != nil {
+		s.Capabilities.Add = m.Capabilities.Add
+		s.Capabilities.Drop = m.Capabilities.Drop
+	}
+
+	s.LinuxOptions.Seccomp = m.Seccomp
+	s.LinuxOptions.AppArmor = m.AppArmor
+
+	if m.SELinux != nil {
+		s.LinuxOptions.User = m.SELinux.User
+		s.LinuxOptions.Role = m.SELinux.Role
+		s.LinuxOptions.Type = m.SELinux.Type
+		s.LinuxOptions.Level = m.SELinux.Level
+	}
+
+	return s
+}
+
+func (m ManifestSpecSecurity) GetManifestSecurity() types.ManifestSpecSecurity {
+	s := types.ManifestSpecSecurity{
+		Privileged:      m.Privileged,
+		User:            m.User,
+		Group:           m.Group,
+		Groups:          m.Groups,
+		ReadOnlyRootfs:  m.ReadOnlyRootfs,
+		NoNewPrivileges: m.NoNewPrivileges,
+		Seccomp:         m.Seccomp,
+		AppArmor:        m.AppArmor,
+	}
+
+	if m.Capabilities != nil {
+		s.Capabilities.Add = m.Capabilities.Add
+		s.Capabilities.Drop = m.Capabilities.Drop
+	}
+
+	if m.SELinux != nil {
+		s.SELinux.User = m.SELinux.User
+		s.SELinux.Role = m.SELinux.Role
+		s.SELinux.Type = m.SELinux.Type
+		s.SELinux.Level = m.SELinux.Level
+	}
+
+	return s
+}
+
 func handleErr(msg string, e error) error {
 	log.Errorf("decode resource %s error: %s", msg, e.Error())
 	return e
@@ -37,6 +37,8 @@ type NamespaceManifestMeta struct {
 type NamespaceManifestSpec struct {
 	Domain    *string                    `json:"domain"`
 	Resources *NamespaceResourcesOptions `json:"resources"`
+	Security  *NamespaceSecurityOptions  `json:"security"`
+	Quotas    *NamespaceQuotasOptions    `json:"quotas"`
 }
 
 func (s *NamespaceManifest) FromJson(data []byte) error {
@@ -142,6 +144,34 @@ func (s *NamespaceManifest) SetNamespaceSpec(ns *types.Namespace) error {
 
 	}
 
+	if s.Spec.Quotas != nil {
+
+		if s.Spec.Quotas.Services != nil {
+			ns.Spec.Quotas.Services = *s.Spec.Quotas.Services
+		}
+
+		if s.Spec.Quotas.Jobs != nil {
+			ns.Spec.Quotas.Jobs = *s.Spec.Quotas.Jobs
+		}
+
+		if s.Spec.Quotas.Routes != nil {
+			ns.Spec.Quotas.Routes = *s.Spec.Quotas.Routes
+		}
+
+		if s.Spec.Quotas.Volumes != nil {
+			ns.Spec.Quotas.Volumes = *s.Spec.Quotas.Volumes
+		}
+	}
+
+	if s.Spec.Security != nil {
+		if s.Spec.Security.DenyPrivileged != nil {
+			ns.Spec.Security.DenyPrivileged = *s.Spec.Security.DenyPrivileged
+		}
+		if s.Spec.Security.DenyNetwork != nil {
+			ns.Spec.Security.DenyNetwork = *s.Spec.Security.DenyNetwork
+		}
+	}
+
 	return nil
 
 }
@@ -165,6 +195,20 @@ type NamespaceResourcesOptions struct {
 	Limits  *NamespaceResourceOptions `json:"limits"`
 }
 
+// swagger:model request_namespace_objects_quotas
+type NamespaceQuotasOptions struct {
+	Services *int `json:"services"`
+	Jobs     *int `json:"jobs"`
+	Routes   *int `json:"routes"`
+	Volumes  *int `json:"volumes"`
+}
+
+// swagger:model request_namespace_security
+type NamespaceSecurityOptions struct {
+	DenyPrivileged *bool `json:"deny_privileged"`
+	DenyNetwork    *bool `json:"deny_network"`
+}
+
 // swagger:model request_namespace_quotas
 type NamespaceResourceOptions struct {
 	RAM     *string `json:"ram"`
@@ -19,13 +19,23 @@
 package request
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/decoder"
 	"github.com/lastbackend/lastbackend/pkg/util/validator"
+	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+var yamlLineErr = regexp.MustCompile(`line (\d+): (.*)`)
+
 type NamespaceRequest struct{}
 
 func (NamespaceRequest) Manifest() *NamespaceManifest {
@@ -86,6 +96,7 @@ func (s *NamespaceApplyManifest) Init() {
 	s.Volumes = make(map[string]*VolumeManifest, 0)
 	s.Services = make(map[string]*ServiceManifest, 0)
 	s.Routes = make(map[string]*RouteManifest, 0)
+	s.Jobs = make(map[string]*JobManifest, 0)
 }
 
 func (s *NamespaceApplyManifest) Validate() *errors.Err {
@@ -147,6 +158,144 @@ func (s *NamespaceApplyManifest) DecodeAndValidate(reader io.Reader) *errors.Err
 	return nil
 }
 
+// FromYaml decodes multi-document yaml stream into apply manifest.
+// Every document describes a single object by kind, meta and spec fields.
+// Errors are reported with zero based document index and field path.
+func (s *NamespaceApplyManifest) FromYaml(data []byte) error {
+
+	if s.Configs == nil || s.Jobs == nil {
+		s.Init()
+	}
+
+	for i, doc := range decoder.YamlSplit(data) {
+
+		if len(bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(doc), []byte("---")))) == 0 {
+			continue
+		}
+
+		var m = new(Runtime)
+		if err := yaml.Unmarshal(doc, m); err != nil {
+			return yamlDocumentErr(i, doc, err)
+		}
+
+		var (
+			name   *string
+			exists bool
+			mf     interface {
+				Validate() *errors.Err
+			}
+		)
+
+		switch strings.ToLower(m.Kind) {
+		case types.KindConfig:
+			c := new(ConfigManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Configs[*name]
+				s.Configs[*name] = c
+			}
+		case types.KindSecret:
+			c := new(SecretManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Secrets[*name]
+				s.Secrets[*name] = c
+			}
+		case types.KindVolume:
+			c := new(VolumeManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Volumes[*name]
+				s.Volumes[*name] = c
+			}
+		case types.KindService:
+			c := new(ServiceManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Services[*name]
+				s.Services[*name] = c
+			}
+		case types.KindJob:
+			c := new(JobManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Jobs[*name]
+				s.Jobs[*name] = c
+			}
+		case types.KindRoute:
+			c := new(RouteManifest)
+			if err := c.FromYaml(doc); err != nil {
+				return yamlDocumentErr(i, doc, err)
+			}
+			name, mf = c.Meta.Name, c
+			if name != nil {
+				_, exists = s.Routes[*name]
+				s.Routes[*name] = c
+			}
+		case types.EmptyString:
+			return fmt.Errorf("document[%d]: kind: kind is required", i)
+		default:
+			return fmt.Errorf("document[%d]: kind: unsupported kind `%s`", i, m.Kind)
+		}
+
+		if name == nil {
+			return fmt.Errorf("document[%d]: meta.name: name is required", i)
+		}
+
+		if exists {
+			return fmt.Errorf("document[%d]: meta.name: duplicate %s `%s`", i, strings.ToLower(m.Kind), *name)
+		}
+
+		if err := mf.Validate(); err != nil {
+			switch err.Attr {
+			case "name", "description":
+				return fmt.Errorf("document[%d]: meta.%s: bad parameter", i, err.Attr)
+			case types.EmptyString:
+				return fmt.Errorf("document[%d]: %s", i, err.Err().Error())
+			default:
+				return fmt.Errorf("document[%d]: %s: bad parameter", i, err.Attr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DecodeYamlAndValidate decodes multi-document yaml stream from reader, see FromYaml
+func (s *NamespaceApplyManifest) DecodeYamlAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("namespace").BadRequest(err.Error())
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("namespace").Unknown(err)
+	}
+
+	if err := s.FromYaml(body); err != nil {
+		return errors.New("namespace").BadRequest(err.Error())
+	}
+
+	return nil
+}
+
 func (s *NamespaceApplyManifest) ToJson() ([]byte, error) {
 	return json.Marshal(s)
 }
@@ -162,3 +311,24 @@ func (n *NamespaceRemoveOptions) Validate() *errors.Err {
 func (n *NamespaceRemoveOptions) ToJson() ([]byte, error) {
 	return json.Marshal(n)
 }
+
+// yamlDocumentErr converts yaml decode error into error with document index and field path
+func yamlDocumentErr(index int, doc []byte, err error) error {
+
+	msg := err.Error()
+	if e, ok := err.(*yaml.TypeError); ok && len(e.Errors) > 0 {
+		msg = e.Errors[0]
+	}
+
+	m := yamlLineErr.FindStringSubmatch(msg)
+	if m == nil {
+		return fmt.Errorf("document[%d]: %s", index, strings.TrimPrefix(msg, "yaml: "))
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	if path := decoder.YamlPath(doc, line); path != types.EmptyString {
+		return fmt.Errorf("document[%d]: %s: %s", index, path, m[2])
+	}
+
+	return fmt.Errorf("document[%d]: line %d: %s", index, line, m[2])
+}
@@ -50,6 +50,10 @@ type NodeStatusOptions struct {
 	Volumes map[string]*NodeVolumeStatusOptions `json:"volumes"`
 	// Node resources
 	Resources NodeResourcesOptions `json:"resources"`
+	// Node pressure conditions
+	Pressure types.NodeStatusPressure `json:"pressure"`
+	// Node network state
+	Network types.NodeStatusNetwork `json:"network"`
 }
 
 // swagger:model request_node_resources
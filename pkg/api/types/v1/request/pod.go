@@ -23,6 +23,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
 	"gopkg.in/yaml.v2"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -292,8 +293,13 @@ func (s *PodManifest) SetPodSpec(pod *types.Pod) {
 				pod.Spec.Template.Updated = time.Now()
 			}
 
-			if spec.Security.Privileged != c.Security.Privileged {
-				spec.Security.Privileged = c.Security.Privileged
+			var security = types.SpecTemplateContainerSecurity{}
+			if c.Security != nil {
+				security = c.Security.GetSpec()
+			}
+
+			if !reflect.DeepEqual(spec.Security, security) {
+				spec.Security = security
 				pod.Spec.Template.Updated = time.Now()
 			}
 
@@ -0,0 +1,170 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"gopkg.in/yaml.v2"
+)
+
+type NetworkPolicyManifest struct {
+	Meta NetworkPolicyManifestMeta `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Spec NetworkPolicyManifestSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+type NetworkPolicyManifestMeta struct {
+	RuntimeMeta `yaml:",inline"`
+	Namespace   *string `json:"namespace" yaml:"namespace"`
+}
+
+type NetworkPolicyManifestSpec struct {
+	// Pods labels selector
+	Selector map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Allowed incoming traffic
+	Ingress []*NetworkPolicyManifestRule `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	// Allowed outgoing traffic
+	Egress []*NetworkPolicyManifestRule `json:"egress,omitempty" yaml:"egress,omitempty"`
+}
+
+type NetworkPolicyManifestRule struct {
+	// Peers namespaces
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	// Peers pods labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Peers networks in CIDR notation
+	Networks []string `json:"networks,omitempty" yaml:"networks,omitempty"`
+	// Ports in port[/protocol] format, like 80 or 53/udp
+	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+func (p *NetworkPolicyManifest) FromJson(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+func (p *NetworkPolicyManifest) ToJson() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *NetworkPolicyManifest) FromYaml(data []byte) error {
+	return yaml.Unmarshal(data, p)
+}
+
+func (p *NetworkPolicyManifest) ToYaml() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+func (p *NetworkPolicyManifest) SetNetworkPolicyMeta(policy *types.NetworkPolicy) {
+
+	if policy.Meta.Name == types.EmptyString {
+		policy.Meta.Name = *p.Meta.Name
+	}
+
+	if p.Meta.Description != nil {
+		policy.Meta.Description = *p.Meta.Description
+	}
+
+	if p.Meta.Labels != nil {
+		policy.Meta.Labels = p.Meta.Labels
+	}
+}
+
+func (p *NetworkPolicyManifest) SetNetworkPolicySpec(policy *types.NetworkPolicy) error {
+
+	var err error
+
+	policy.Spec.Selector = make(map[string]string, 0)
+	for k, v := range p.Spec.Selector {
+		policy.Spec.Selector[k] = v
+	}
+
+	if policy.Spec.Ingress, err = p.getRules(p.Spec.Ingress); err != nil {
+		return err
+	}
+
+	if policy.Spec.Egress, err = p.getRules(p.Spec.Egress); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *NetworkPolicyManifest) getRules(rules []*NetworkPolicyManifestRule) ([]*types.NetworkPolicyRule, error) {
+
+	var items = make([]*types.NetworkPolicyRule, 0)
+
+	for _, r := range rules {
+
+		rule := new(types.NetworkPolicyRule)
+		rule.Namespaces = append(make([]string, 0), r.Namespaces...)
+		rule.Networks = append(make([]string, 0), r.Networks...)
+
+		rule.Labels = make(map[string]string, 0)
+		for k, v := range r.Labels {
+			rule.Labels[k] = v
+		}
+
+		rule.Ports = make([]*types.NetworkPolicyPort, 0)
+		for _, port := range r.Ports {
+			pp, err := parseNetworkPolicyPort(port)
+			if err != nil {
+				return nil, err
+			}
+			rule.Ports = append(rule.Ports, pp)
+		}
+
+		items = append(items, rule)
+	}
+
+	return items, nil
+}
+
+func parseNetworkPolicyPort(s string) (*types.NetworkPolicyPort, error) {
+
+	var (
+		port  = new(types.NetworkPolicyPort)
+		parts = strings.SplitN(s, "/", 2)
+	)
+
+	p, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil || p == 0 {
+		return nil, fmt.Errorf("invalid port `%s`", s)
+	}
+	port.Port = uint16(p)
+	port.Protocol = types.NetworkPolicyProtocolTCP
+
+	if len(parts) > 1 {
+		switch strings.ToLower(parts[1]) {
+		case types.NetworkPolicyProtocolTCP, types.NetworkPolicyProtocolUDP:
+			port.Protocol = strings.ToLower(parts[1])
+		default:
+			return nil, fmt.Errorf("invalid port protocol `%s`", s)
+		}
+	}
+
+	return port, nil
+}
+
+type NetworkPolicyRemoveOptions struct {
+	Force bool
+}
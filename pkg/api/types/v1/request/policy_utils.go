@@ -0,0 +1,105 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type NetworkPolicyRequest struct{}
+
+func (NetworkPolicyRequest) Manifest() *NetworkPolicyManifest {
+	return new(NetworkPolicyManifest)
+}
+
+func (p *NetworkPolicyManifest) Validate() *errors.Err {
+
+	if p.Meta.Name == nil || *p.Meta.Name == types.EmptyString {
+		return errors.New("policy").BadParameter("meta.name")
+	}
+
+	for _, r := range p.Spec.Ingress {
+		if err := r.validate(); err != nil {
+			return errors.New("policy").BadParameter("spec.ingress", err)
+		}
+	}
+
+	for _, r := range p.Spec.Egress {
+		if err := r.validate(); err != nil {
+			return errors.New("policy").BadParameter("spec.egress", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *NetworkPolicyManifestRule) validate() error {
+
+	if r == nil {
+		return errors.New("rule can not be null")
+	}
+
+	for _, n := range r.Networks {
+		if _, _, err := net.ParseCIDR(n); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range r.Ports {
+		if _, err := parseNetworkPolicyPort(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *NetworkPolicyManifest) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("policy").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("policy").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, p)
+	if err != nil {
+		return errors.New("policy").IncorrectJSON(err)
+	}
+
+	return p.Validate()
+}
+
+func (NetworkPolicyRequest) RemoveOptions() *NetworkPolicyRemoveOptions {
+	return new(NetworkPolicyRemoveOptions)
+}
+
+func (p *NetworkPolicyRemoveOptions) Validate() *errors.Err {
+	return nil
+}
@@ -126,6 +126,38 @@ func (s *ServiceManifest) SetServiceSpec(svc *types.Service) (err error) {
 			}
 		}
 
+		if s.Spec.Network.Route != nil {
+			svc.Spec.Network.Strategy.Route = *s.Spec.Network.Route
+		}
+
+		if s.Spec.Network.Affinity != nil {
+			svc.Spec.Network.Strategy.Affinity = *s.Spec.Network.Affinity
+		}
+
+		if s.Spec.Network.Weights != nil {
+			svc.Spec.Network.Strategy.Weights = s.Spec.Network.Weights
+		}
+
+		if s.Spec.Network.Expose != nil {
+			svc.Spec.Network.Expose = *s.Spec.Network.Expose
+		}
+
+		if s.Spec.Network.Headless != nil {
+			svc.Spec.Network.Headless = *s.Spec.Network.Headless
+		}
+
+		if s.Spec.Network.PortNames != nil {
+			svc.Spec.Network.PortNames = s.Spec.Network.PortNames
+		}
+
+		if s.Spec.Network.ExternalName != nil {
+			svc.Spec.Network.ExternalName = *s.Spec.Network.ExternalName
+		}
+
+		if s.Spec.Network.ExternalIPs != nil {
+			svc.Spec.Network.ExternalIPs = s.Spec.Network.ExternalIPs
+		}
+
 		svc.Spec.Network.Updated = time.Now()
 	}
 
@@ -39,6 +39,25 @@ func (s *ServiceManifest) Validate() *errors.Err {
 		return errors.New("service").BadParameter("name")
 	case s.Meta.Description != nil && len(*s.Meta.Description) > DEFAULT_DESCRIPTION_LIMIT:
 		return errors.New("service").BadParameter("description")
+	case s.Spec.Template != nil && !s.Spec.Template.validContainerRoles():
+		return errors.New("service").BadParameter("role")
+	case s.Spec.Network != nil && !s.Spec.Network.validRoute():
+		return errors.New("service").BadParameter("route")
+	case s.Spec.Network != nil && !s.Spec.Network.validAffinity():
+		return errors.New("service").BadParameter("affinity")
+	case s.Spec.Network != nil && !s.Spec.Network.validWeights():
+		return errors.New("service").BadParameter("weights")
+	case s.Spec.Network != nil && !s.Spec.Network.validHeadless():
+		return errors.New("service").BadParameter("headless")
+	case s.Spec.Network != nil && !s.Spec.Network.validPortNames():
+		return errors.New("service").BadParameter("port_names")
+	case s.Spec.Network != nil && !s.Spec.Network.validExternalName():
+		return errors.New("service").BadParameter("external_name")
+	case s.Spec.Network != nil && !s.Spec.Network.validExternalIPs():
+		return errors.New("service").BadParameter("external_ips")
+	case s.Spec.Network != nil && s.Spec.Network.isExternal() && s.Spec.Template != nil && len(s.Spec.Template.Containers) > 0:
+		// external service has no pods to balance traffic to
+		return errors.New("service").BadParameter("external")
 	}
 
 	return nil
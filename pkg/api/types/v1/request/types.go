@@ -41,12 +41,14 @@ type IRequest interface {
 	Service() *ServiceRequest
 	Secret() *SecretRequest
 	Config() *ConfigRequest
+	NetworkPolicy() *NetworkPolicyRequest
 	Volume() *VolumeRequest
 	Ingress() *IngressRequest
 	Exporter() *ExporterRequest
 	Discovery() *DiscoveryRequest
 	Job() *JobRequest
 	Task() *TaskRequest
+	List() *ListRequest
 }
 
 type Request struct{}
@@ -78,6 +80,9 @@ func (Request) Secret() *SecretRequest {
 func (Request) Config() *ConfigRequest {
 	return new(ConfigRequest)
 }
+func (Request) NetworkPolicy() *NetworkPolicyRequest {
+	return new(NetworkPolicyRequest)
+}
 func (Request) Volume() *VolumeRequest {
 	return new(VolumeRequest)
 }
@@ -98,3 +103,7 @@ func (Request) Job() *JobRequest {
 func (Request) Task() *TaskRequest {
 	return new(TaskRequest)
 }
+
+func (Request) List() *ListRequest {
+	return new(ListRequest)
+}
@@ -73,3 +73,24 @@ type ClusterResources struct {
 	// storage size
 	Storage string `json:"storage"`
 }
+
+// ClusterIPAM represents utilization of cluster IPAM pools
+//
+// swagger:model views_cluster_ipam
+type ClusterIPAM map[string]*ClusterIPAMPool
+
+// ClusterIPAMPool represents utilization of IPAM pool
+//
+// swagger:model views_cluster_ipam_pool
+type ClusterIPAMPool struct {
+	// pool range
+	CIDR string `json:"cidr"`
+	// prefix length of leased networks
+	Prefix int `json:"prefix"`
+	// number of networks in pool
+	Size int `json:"size"`
+	// number of leased networks
+	Leased int `json:"leased"`
+	// number of available networks
+	Available int `json:"available"`
+}
@@ -79,3 +79,28 @@ func (cv *ClusterView) ToClusterStatus(status types.ClusterStatus) ClusterStatus
 		},
 	}
 }
+
+func (cv *ClusterView) NewIPAM(obj *types.IPAMUtilization) *ClusterIPAM {
+	c := make(ClusterIPAM, 0)
+	if obj == nil {
+		return &c
+	}
+
+	for name, p := range obj.Pools {
+		c[name] = &ClusterIPAMPool{
+			CIDR:      p.CIDR,
+			Prefix:    p.Prefix,
+			Size:      p.Size,
+			Leased:    p.Leased,
+			Available: p.Available,
+		}
+	}
+	return &c
+}
+
+func (ci *ClusterIPAM) ToJson() ([]byte, error) {
+	if ci == nil {
+		ci = &ClusterIPAM{}
+	}
+	return json.Marshal(ci)
+}
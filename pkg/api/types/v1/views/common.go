@@ -27,6 +27,8 @@ type Meta struct {
 	Description string `json:"description",yaml:"description"`
 	// Meta self link
 	SelfLink string `json:"self_link",yaml:"self_link"`
+	// Meta resource version
+	ResourceVersion string `json:"resource_version,omitempty" yaml:"resource_version,omitempty"`
 	// Meta labels
 	Labels map[string]string `json:"labels",yaml:"labels"`
 	// Meta created time
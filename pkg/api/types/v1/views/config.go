@@ -42,12 +42,13 @@ type ConfigSpecData struct {
 
 // swagger:model views_secret_meta
 type ConfigMeta struct {
-	Name      string    `json:"name"`
-	Namespace string    `json:"namespace"`
-	Kind      string    `json:"kind"`
-	SelfLink  string    `json:"self_link"`
-	Updated   time.Time `json:"updated"`
-	Created   time.Time `json:"created"`
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Kind            string    `json:"kind"`
+	SelfLink        string    `json:"self_link"`
+	ResourceVersion string    `json:"resource_version"`
+	Updated         time.Time `json:"updated"`
+	Created         time.Time `json:"created"`
 }
 
 // swagger:ignore
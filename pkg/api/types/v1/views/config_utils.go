@@ -29,6 +29,7 @@ type ConfigView struct{}
 func (sv *ConfigView) New(obj *types.Config) *Config {
 	s := Config{}
 	s.Meta = s.ToMeta(obj.Meta)
+	s.Meta.ResourceVersion = obj.ResourceVersion()
 	s.Spec = s.ToSpec(obj.Spec)
 	return &s
 }
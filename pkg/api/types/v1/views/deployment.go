@@ -58,13 +58,14 @@ type DeploymentMeta struct {
 	// Deployment description
 	Description string `json:"description"`
 
-	Version   int               `json:"version"`
-	Namespace string            `json:"namespace"`
-	Service   string            `json:"service"`
-	Endpoint  string            `json:"endpoint"`
-	SelfLink  string            `json:"self_link"`
-	Status    string            `json:"status"`
-	Labels    map[string]string `json:"labels"`
+	Version         int               `json:"version"`
+	Namespace       string            `json:"namespace"`
+	Service         string            `json:"service"`
+	Endpoint        string            `json:"endpoint"`
+	SelfLink        string            `json:"self_link"`
+	ResourceVersion string            `json:"resource_version"`
+	Status          string            `json:"status"`
+	Labels          map[string]string `json:"labels"`
 
 	// Deployment creation time
 	Created time.Time `json:"created"`
@@ -28,6 +28,7 @@ type DeploymentView struct{}
 func (dv *DeploymentView) New(obj *types.Deployment) *Deployment {
 	d := Deployment{}
 	d.SetMeta(obj.Meta)
+	d.Meta.ResourceVersion = obj.ResourceVersion()
 	d.SetStatus(obj.Status)
 	d.SetSpec(obj.Spec)
 
@@ -41,7 +41,8 @@ type DiscoveryMeta struct {
 // DiscoveryStatus - node state struct
 // swagger:model views_ingress_status
 type DiscoveryStatus struct {
-	Ready bool `json:"ready"`
+	Ready bool                    `json:"ready"`
+	DNS   types.DiscoveryDNSStats `json:"dns"`
 }
 
 // swagger:model views_ingress_spec
@@ -45,6 +45,7 @@ func (nv *DiscoveryView) ToDiscoveryMeta(meta types.DiscoveryMeta) DiscoveryMeta
 func (nv *DiscoveryView) ToDiscoveryStatus(status types.DiscoveryStatus) DiscoveryStatus {
 	return DiscoveryStatus{
 		Ready: status.Ready,
+		DNS:   status.DNS,
 	}
 }
 
@@ -29,6 +29,7 @@ func (jw *JobView) New(obj *types.Job) *Job {
 	j := Job{}
 
 	j.SetMeta(obj.Meta)
+	j.Meta.ResourceVersion = obj.ResourceVersion()
 	j.SetStatus(obj.Status)
 	j.SetSpec(obj.Spec)
 
@@ -0,0 +1,37 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import "encoding/json"
+
+// ListMeta - list page info
+// swagger:model views_list_meta
+type ListMeta struct {
+	// Continue token to request the next page, empty on the last page
+	Continue string `json:"continue,omitempty"`
+	// Total items count in collection
+	Total int64 `json:"total"`
+}
+
+// ListPage - list page returned when limit or continue is requested
+// swagger:model views_list_page
+type ListPage struct {
+	Meta  ListMeta        `json:"meta"`
+	Items json.RawMessage `json:"items"`
+}
@@ -0,0 +1,39 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type ListView struct{}
+
+func (lv *ListView) NewPage(system types.System, items []byte) *ListPage {
+	p := new(ListPage)
+	p.Meta.Continue = system.Storage.Continue
+	p.Meta.Total = system.Storage.Total
+	p.Items = items
+	return p
+}
+
+func (p *ListPage) ToJson() ([]byte, error) {
+	return json.Marshal(p)
+}
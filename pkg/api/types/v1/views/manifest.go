@@ -26,8 +26,18 @@ type ManifestSpecSelector struct {
 }
 
 type ManifestSpecNetwork struct {
-	IP    string            `json:"ip,omitempty" yaml:"ip,omitempty"`
-	Ports map[uint16]string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	IP        string            `json:"ip,omitempty" yaml:"ip,omitempty"`
+	IPv6      string            `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+	Ports     map[uint16]string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Route     string            `json:"route,omitempty" yaml:"route,omitempty"`
+	Affinity  int               `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+	Weights   map[string]int    `json:"weights,omitempty" yaml:"weights,omitempty"`
+	Expose    bool              `json:"expose,omitempty" yaml:"expose,omitempty"`
+	Headless  bool              `json:"headless,omitempty" yaml:"headless,omitempty"`
+	PortNames map[uint16]string `json:"port_names,omitempty" yaml:"port_names,omitempty"`
+	// External DNS name or addresses service domain points to
+	ExternalName string   `json:"external_name,omitempty" yaml:"external_name,omitempty"`
+	ExternalIPs  []string `json:"external_ips,omitempty" yaml:"external_ips,omitempty"`
 }
 
 type ManifestSpecStrategy struct {
@@ -48,12 +58,15 @@ type ManifestSpecRuntimeTask struct {
 }
 
 type ManifestSpecTemplate struct {
-	Containers []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Volumes    []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Containers  []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Volumes     []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Termination int                             `json:"termination,omitempty" yaml:"termination,omitempty"`
 }
 
 type ManifestSpecTemplateContainer struct {
 	Name          string                                  `json:"name,omitempty" yaml:"name,omitempty"`
+	Role          string                                  `json:"role,omitempty" yaml:"role,omitempty"`
+	Order         int                                     `json:"order,omitempty" yaml:"order,omitempty"`
 	Command       string                                  `json:"command,omitempty" yaml:"command,omitempty"`
 	Workdir       string                                  `json:"workdir,omitempty" yaml:"workdir,omitempty"`
 	Entrypoint    string                                  `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
@@ -64,6 +77,9 @@ type ManifestSpecTemplateContainer struct {
 	Resources     *ManifestSpecTemplateContainerResources `json:"resources,omitempty" yaml:"resources,omitempty"`
 	RestartPolicy *ManifestSpecTemplateRestartPolicy      `json:"restart_policy,omitempty" yaml:"restart_policy,omitempty"`
 	Volumes       []ManifestSpecTemplateContainerVolume   `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Probes        *ManifestSpecTemplateContainerProbes    `json:"probes,omitempty" yaml:"probes,omitempty"`
+	Lifecycle     *ManifestSpecTemplateContainerLifecycle `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	Security      *ManifestSpecSecurity                   `json:"security,omitempty" yaml:"security,omitempty"`
 }
 
 type ManifestSpecTemplateContainerEnv struct {
@@ -117,6 +133,70 @@ type ManifestSpecTemplateRestartPolicy struct {
 	Attempt int    `json:"attempt,omitempty" yaml:"attempt,omitempty"`
 }
 
+type ManifestSpecTemplateContainerProbes struct {
+	Ready *ManifestSpecTemplateContainerProbe `json:"ready,omitempty" yaml:"ready,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbe struct {
+	Exec             *ManifestSpecTemplateContainerHookExec    `json:"exec,omitempty" yaml:"exec,omitempty"`
+	Socket           *ManifestSpecTemplateContainerProbeSocket `json:"socket,omitempty" yaml:"socket,omitempty"`
+	HTTP             *ManifestSpecTemplateContainerHookHTTP    `json:"http,omitempty" yaml:"http,omitempty"`
+	InitialDelay     int                                       `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+	Timeout          int                                       `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Period           int                                       `json:"period,omitempty" yaml:"period,omitempty"`
+	ThresholdSuccess int                                       `json:"threshold_success,omitempty" yaml:"threshold_success,omitempty"`
+	ThresholdFailure int                                       `json:"threshold_failure,omitempty" yaml:"threshold_failure,omitempty"`
+	StartupTimeout   int                                       `json:"startup_timeout,omitempty" yaml:"startup_timeout,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbeSocket struct {
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+type ManifestSpecTemplateContainerLifecycle struct {
+	PreStop *ManifestSpecTemplateContainerHook `json:"pre_stop,omitempty" yaml:"pre_stop,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHook struct {
+	Exec *ManifestSpecTemplateContainerHookExec `json:"exec,omitempty" yaml:"exec,omitempty"`
+	HTTP *ManifestSpecTemplateContainerHookHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookExec struct {
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookHTTP struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+type ManifestSpecSecurity struct {
+	Privileged      bool                              `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+	User            int                               `json:"user,omitempty" yaml:"user,omitempty"`
+	Group           int                               `json:"group,omitempty" yaml:"group,omitempty"`
+	Groups          []int                             `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Capabilities    *ManifestSpecSecurityCapabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	ReadOnlyRootfs  bool                              `json:"readonly_rootfs,omitempty" yaml:"readonly_rootfs,omitempty"`
+	NoNewPrivileges bool                              `json:"no_new_privileges,omitempty" yaml:"no_new_privileges,omitempty"`
+	Seccomp         string                            `json:"seccomp,omitempty" yaml:"seccomp,omitempty"`
+	AppArmor        string                            `json:"apparmor,omitempty" yaml:"apparmor,omitempty"`
+	SELinux         *ManifestSpecSecuritySELinux      `json:"selinux,omitempty" yaml:"selinux,omitempty"`
+}
+
+type ManifestSpecSecurityCapabilities struct {
+	Add  []string `json:"add,omitempty" yaml:"add,omitempty"`
+	Drop []string `json:"drop,omitempty" yaml:"drop,omitempty"`
+}
+
+type ManifestSpecSecuritySELinux struct {
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
+	Role  string `json:"role,omitempty" yaml:"role,omitempty"`
+	Type  string `json:"type,omitempty" yaml:"type,omitempty"`
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
 type ManifestSpecTemplateContainerResource struct {
 	// CPU resource option
 	CPU string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
+	"reflect"
 	"strings"
 )
 
@@ -30,14 +31,17 @@ type ManifestView struct{}
 func (mv *ManifestView) NewManifestSpecTemplate(obj types.SpecTemplate) ManifestSpecTemplate {
 
 	mst := ManifestSpecTemplate{
-		Containers: make([]ManifestSpecTemplateContainer, 0),
-		Volumes:    make([]ManifestSpecTemplateVolume, 0),
+		Containers:  make([]ManifestSpecTemplateContainer, 0),
+		Volumes:     make([]ManifestSpecTemplateVolume, 0),
+		Termination: obj.Termination,
 	}
 
 	for _, s := range obj.Containers {
 
 		c := ManifestSpecTemplateContainer{
 			Name:       s.Name,
+			Role:       s.Role,
+			Order:      s.Order,
 			Command:    strings.Join(s.Exec.Command, " "),
 			Workdir:    s.Exec.Workdir,
 			Args:       s.Exec.Args,
@@ -86,6 +90,81 @@ func (mv *ManifestView) NewManifestSpecTemplate(obj types.SpecTemplate) Manifest
 		c.Resources.Request.RAM = resource.EncodeMemoryResource(s.Resources.Request.RAM)
 		c.Resources.Request.CPU = resource.EncodeCpuResource(s.Resources.Request.CPU)
 
+		probe := s.Probes.ReadProbe
+		if probe.IsSet() {
+			c.Probes = new(ManifestSpecTemplateContainerProbes)
+			c.Probes.Ready = &ManifestSpecTemplateContainerProbe{
+				InitialDelay:     probe.InitialDelaySeconds,
+				Timeout:          probe.TimeoutSeconds,
+				Period:           probe.PeriodSeconds,
+				ThresholdSuccess: probe.ThresholdSuccess,
+				ThresholdFailure: probe.ThresholdFailure,
+				StartupTimeout:   probe.StartupTimeoutSeconds,
+			}
+			if len(probe.Exec.Command) != 0 {
+				c.Probes.Ready.Exec = &ManifestSpecTemplateContainerHookExec{
+					Command: probe.Exec.Command,
+				}
+			}
+			if probe.Socket.Port != 0 {
+				c.Probes.Ready.Socket = &ManifestSpecTemplateContainerProbeSocket{
+					Protocol: probe.Socket.Protocol,
+					Port:     probe.Socket.Port,
+				}
+			}
+			if probe.HTTP.Port != 0 {
+				c.Probes.Ready.HTTP = &ManifestSpecTemplateContainerHookHTTP{
+					Path: probe.HTTP.Path,
+					Port: probe.HTTP.Port,
+				}
+			}
+		}
+
+		hook := s.Lifecycle.PreStop
+		if len(hook.Exec.Command) != 0 || hook.HTTP.Port != 0 {
+			c.Lifecycle = new(ManifestSpecTemplateContainerLifecycle)
+			c.Lifecycle.PreStop = new(ManifestSpecTemplateContainerHook)
+			if len(hook.Exec.Command) != 0 {
+				c.Lifecycle.PreStop.Exec = &ManifestSpecTemplateContainerHookExec{
+					Command: hook.Exec.Command,
+				}
+			}
+			if hook.HTTP.Port != 0 {
+				c.Lifecycle.PreStop.HTTP = &ManifestSpecTemplateContainerHookHTTP{
+					Path: hook.HTTP.Path,
+					Port: hook.HTTP.Port,
+				}
+			}
+		}
+
+		sec := s.Security
+		if !reflect.DeepEqual(sec, types.SpecTemplateContainerSecurity{}) {
+			c.Security = &ManifestSpecSecurity{
+				Privileged:      sec.Privileged,
+				User:            sec.User,
+				Group:           sec.Group,
+				Groups:          sec.Groups,
+				ReadOnlyRootfs:  sec.ReadOnlyRootfs,
+				NoNewPrivileges: sec.NoNewPrivileges,
+				Seccomp:         sec.LinuxOptions.Seccomp,
+				AppArmor:        sec.LinuxOptions.AppArmor,
+			}
+			if len(sec.Capabilities.Add) != 0 || len(sec.Capabilities.Drop) != 0 {
+				c.Security.Capabilities = &ManifestSpecSecurityCapabilities{
+					Add:  sec.Capabilities.Add,
+					Drop: sec.Capabilities.Drop,
+				}
+			}
+			if sec.LinuxOptions.User != "" || sec.LinuxOptions.Role != "" || sec.LinuxOptions.Type != "" || sec.LinuxOptions.Level != "" {
+				c.Security.SELinux = &ManifestSpecSecuritySELinux{
+					User:  sec.LinuxOptions.User,
+					Role:  sec.LinuxOptions.Role,
+					Type:  sec.LinuxOptions.Type,
+					Level: sec.LinuxOptions.Level,
+				}
+			}
+		}
+
 		mst.Containers = append(mst.Containers, c)
 	}
 
@@ -31,14 +31,15 @@ type Namespace struct {
 
 // swagger:model views_namespace_meta
 type NamespaceMeta struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	SelfLink    string            `json:"self_link"`
-	Endpoint    string            `json:"endpoint"`
-	Suffix      string            `json:"suffix"`
-	Labels      map[string]string `json:"labels"`
-	Created     time.Time         `json:"created"`
-	Updated     time.Time         `json:"updated"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	SelfLink        string            `json:"self_link"`
+	ResourceVersion string            `json:"resource_version"`
+	Endpoint        string            `json:"endpoint"`
+	Suffix          string            `json:"suffix"`
+	Labels          map[string]string `json:"labels"`
+	Created         time.Time         `json:"created"`
+	Updated         time.Time         `json:"updated"`
 }
 
 // swagger:model views_namespace_spec
@@ -46,6 +47,20 @@ type NamespaceSpec struct {
 	Env       NamespaceEnvs      `json:"env"`
 	Domain    NamespaceDomain    `json:"domain"`
 	Resources NamespaceResources `json:"resources"`
+	Security  NamespaceSecurity  `json:"security"`
+	Quotas    NamespaceQuotas    `json:"quotas"`
+}
+
+type NamespaceQuotas struct {
+	Services int `json:"services"`
+	Jobs     int `json:"jobs"`
+	Routes   int `json:"routes"`
+	Volumes  int `json:"volumes"`
+}
+
+type NamespaceSecurity struct {
+	DenyPrivileged bool `json:"deny_privileged"`
+	DenyNetwork    bool `json:"deny_network"`
 }
 
 type NamespaceStatus struct {
@@ -87,3 +102,24 @@ type NamespaceApplyStatus struct {
 	Routes   map[string]bool `json:"routes,omitempty"`
 	Jobs     map[string]bool `json:"jobs,omitempty"`
 }
+
+// swagger:model views_namespace_apply_plan
+type NamespaceApplyPlan struct {
+	Configs  map[string]*NamespaceApplyStep `json:"configs,omitempty"`
+	Secrets  map[string]*NamespaceApplyStep `json:"secrets,omitempty"`
+	Volumes  map[string]*NamespaceApplyStep `json:"volumes,omitempty"`
+	Services map[string]*NamespaceApplyStep `json:"services,omitempty"`
+	Routes   map[string]*NamespaceApplyStep `json:"routes,omitempty"`
+	Jobs     map[string]*NamespaceApplyStep `json:"jobs,omitempty"`
+}
+
+type NamespaceApplyStep struct {
+	Action string                  `json:"action"`
+	Diff   []*NamespaceApplyChange `json:"diff,omitempty"`
+}
+
+type NamespaceApplyChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
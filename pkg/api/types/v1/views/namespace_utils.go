@@ -31,6 +31,7 @@ type NamespaceView struct{}
 func (nv *NamespaceView) New(obj *types.Namespace) *Namespace {
 	n := Namespace{}
 	n.Meta = n.ToMeta(obj.Meta)
+	n.Meta.ResourceVersion = obj.ResourceVersion()
 	n.Status = n.ToStatus(obj.Status)
 	n.Spec = n.ToSpec(obj.Spec)
 	return &n
@@ -107,6 +108,16 @@ func (r *Namespace) ToSpec(spec types.NamespaceSpec) NamespaceSpec {
 			Internal: spec.Domain.Internal,
 			External: spec.Domain.External,
 		},
+		Security: NamespaceSecurity{
+			DenyPrivileged: spec.Security.DenyPrivileged,
+			DenyNetwork:    spec.Security.DenyNetwork,
+		},
+		Quotas: NamespaceQuotas{
+			Services: spec.Quotas.Services,
+			Jobs:     spec.Quotas.Jobs,
+			Routes:   spec.Quotas.Routes,
+			Volumes:  spec.Quotas.Volumes,
+		},
 	}
 }
 
@@ -128,7 +139,7 @@ func (r *Namespace) ToEnv(obj types.NamespaceEnvs) NamespaceEnvs {
 
 func (r *Namespace) ToResources(obj types.ResourceItem) *NamespaceResource {
 
-	if obj.RAM == 0 || obj.CPU == 0 || obj.Storage == 0 {
+	if obj.RAM == 0 && obj.CPU == 0 && obj.Storage == 0 {
 		return nil
 	}
 
@@ -165,3 +176,31 @@ func (n *NamespaceList) ToJson() ([]byte, error) {
 func (s *NamespaceApplyStatus) ToJson() ([]byte, error) {
 	return json.Marshal(s)
 }
+
+func (nv *NamespaceView) NewApplyPlan(plan *types.NamespaceApplyPlan) *NamespaceApplyPlan {
+	n := NamespaceApplyPlan{}
+	n.Configs = n.ToSteps(plan.Configs)
+	n.Secrets = n.ToSteps(plan.Secrets)
+	n.Volumes = n.ToSteps(plan.Volumes)
+	n.Services = n.ToSteps(plan.Services)
+	n.Routes = n.ToSteps(plan.Routes)
+	n.Jobs = n.ToSteps(plan.Jobs)
+	return &n
+}
+
+func (n *NamespaceApplyPlan) ToSteps(steps map[string]*types.NamespaceApplyStep) map[string]*NamespaceApplyStep {
+	s := make(map[string]*NamespaceApplyStep, 0)
+	for name, step := range steps {
+		st := new(NamespaceApplyStep)
+		st.Action = step.Action
+		for _, c := range step.Changes {
+			st.Diff = append(st.Diff, &NamespaceApplyChange{Path: c.Path, From: c.From, To: c.To})
+		}
+		s[name] = st
+	}
+	return s
+}
+
+func (n *NamespaceApplyPlan) ToJson() ([]byte, error) {
+	return json.Marshal(n)
+}
@@ -58,7 +58,12 @@ type NodeInfo struct {
 		External string `json:"external"`
 		Internal string `json:"internal"`
 	} `json:"ip"`
-	CIDR    string `json:"cidr"`
+	CIDR       string `json:"cidr"`
+	NetworkKey string `json:"network_key,omitempty"`
+	API        struct {
+		Address string `json:"address"`
+		Scheme  string `json:"scheme"`
+	} `json:"api"`
 	Version string `json:"version"`
 }
 
@@ -84,12 +89,24 @@ type NodeStatus struct {
 	Online    bool            `json:"online"`
 	Capacity  NodeResources   `json:"capacity"`
 	Allocated NodeResources   `json:"allocated"`
+	Pressure  NodePressure    `json:"pressure"`
+}
+
+// NodePressure - node pressure conditions
+// swagger:model views_node_pressure
+type NodePressure struct {
+	Disk bool `json:"disk"`
 }
 
 // swagger:ignore
 // swagger:model types_node_spec
 type NodeSpec struct {
-	Security NodeSecurity `json:"security"`
+	Security NodeSecurity    `json:"security"`
+	Network  NodeSpecNetwork `json:"network"`
+}
+
+type NodeSpecNetwork struct {
+	CIDR string `json:"cidr"`
 }
 
 type NodeSecurity struct {
@@ -120,5 +137,7 @@ type NodeManifest struct {
 }
 
 type NodeManifestMeta struct {
-	Initial bool `json:"initial"`
+	Initial    bool   `json:"initial"`
+	Subnet     string `json:"subnet,omitempty"`
+	NetworkKey string `json:"network_key,omitempty"`
 }
@@ -48,6 +48,9 @@ func (nv *NodeView) ToNodeMeta(meta types.NodeMeta) NodeMeta {
 	nm.IP.External = meta.ExternalIP
 	nm.IP.Internal = meta.InternalIP
 	nm.CIDR = meta.CIDR
+	nm.NetworkKey = meta.NetworkKey
+	nm.API.Address = meta.API.Address
+	nm.API.Scheme = meta.API.Scheme
 	return nm
 }
 
@@ -55,6 +58,7 @@ func (nv *NodeView) ToNodeStatus(status types.NodeStatus) NodeStatus {
 	ns := NodeStatus{}
 
 	ns.Online = status.Online
+	ns.Pressure.Disk = status.Pressure.Disk
 
 	ns.Capacity.Containers = status.Capacity.Containers
 	ns.Capacity.Pods = status.Capacity.Pods
@@ -94,6 +98,7 @@ func (nv *NodeView) ToNodeStatus(status types.NodeStatus) NodeStatus {
 func (nv *NodeView) ToNodeSpec(spec types.NodeSpec) NodeSpec {
 	ns := NodeSpec{}
 	ns.Security.TLS = spec.Security.TLS
+	ns.Network.CIDR = spec.Network.CIDR
 	return ns
 }
 
@@ -113,6 +118,8 @@ func (obj *NodeManifest) Decode() *types.NodeManifest {
 	}
 
 	manifest.Meta.Initial = obj.Meta.Initial
+	manifest.Meta.Subnet = obj.Meta.Subnet
+	manifest.Meta.NetworkKey = obj.Meta.NetworkKey
 	manifest.Resolvers = make(map[string]*types.ResolverManifest, 0)
 	manifest.Exporter = new(types.ExporterManifest)
 
@@ -178,6 +185,8 @@ func (nv *NodeView) NewManifest(obj *types.NodeManifest) *NodeManifest {
 	}
 
 	manifest.Meta.Initial = obj.Meta.Initial
+	manifest.Meta.Subnet = obj.Meta.Subnet
+	manifest.Meta.NetworkKey = obj.Meta.NetworkKey
 	manifest.Discovery = obj.Resolvers
 	manifest.Exporter = obj.Exporter
 
@@ -97,6 +97,7 @@ type PodStatus struct {
 }
 
 type PodStatusRuntime struct {
+	Init     []PodContainer          `json:"init"`
 	Services []PodContainer          `json:"services"`
 	Pipeline []PodStatusPipelineStep `json:"pipeline"`
 }
@@ -220,4 +221,6 @@ type PodNetwork struct {
 	HostIP string `json:"host_ip"`
 	// Pod IP
 	PodIP string `json:"pod_ip"`
+	// Pod IPv6
+	PodIPv6 string `json:"pod_ipv6,omitempty"`
 }
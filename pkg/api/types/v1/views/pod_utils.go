@@ -69,6 +69,7 @@ func (p *Pod) SetStatus(pod types.PodStatus) {
 
 	status.Network.HostIP = pod.Network.HostIP
 	status.Network.PodIP = pod.Network.PodIP
+	status.Network.PodIPv6 = pod.Network.PodIPv6
 
 	status.Steps = make(PodSteps, 0)
 	for key, step := range pod.Steps {
@@ -78,10 +79,16 @@ func (p *Pod) SetStatus(pod types.PodStatus) {
 		}
 	}
 	status.Runtime = PodStatusRuntime{
+		Init:     make(PodContainers, 0),
 		Services: make(PodContainers, 0),
 		Pipeline: make([]PodStatusPipelineStep, 0),
 	}
 
+	for _, container := range pod.Runtime.Init {
+		cv := new(ContainerView)
+		status.Runtime.Init = append(status.Runtime.Init, cv.NewPodContainer(container))
+	}
+
 	for _, container := range pod.Runtime.Services {
 		cv := new(ContainerView)
 		status.Runtime.Services = append(status.Runtime.Services, cv.NewPodContainer(container))
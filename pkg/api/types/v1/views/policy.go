@@ -0,0 +1,57 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"time"
+)
+
+// swagger:model views_network_policy
+type NetworkPolicy struct {
+	Meta NetworkPolicyMeta `json:"meta"`
+	Spec NetworkPolicySpec `json:"spec"`
+}
+
+// swagger:model views_network_policy_meta
+type NetworkPolicyMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Description     string            `json:"description"`
+	SelfLink        string            `json:"self_link"`
+	Labels          map[string]string `json:"labels"`
+	ResourceVersion string            `json:"resource_version"`
+	Updated         time.Time         `json:"updated"`
+	Created         time.Time         `json:"created"`
+}
+
+type NetworkPolicySpec struct {
+	Selector map[string]string    `json:"selector"`
+	Ingress  []*NetworkPolicyRule `json:"ingress"`
+	Egress   []*NetworkPolicyRule `json:"egress"`
+}
+
+type NetworkPolicyRule struct {
+	Namespaces []string          `json:"namespaces"`
+	Labels     map[string]string `json:"labels"`
+	Networks   []string          `json:"networks"`
+	Ports      []string          `json:"ports"`
+}
+
+// swagger:model views_network_policy_list
+type NetworkPolicyList []*NetworkPolicy
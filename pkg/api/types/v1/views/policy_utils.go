@@ -0,0 +1,104 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type NetworkPolicyView struct{}
+
+func (pv *NetworkPolicyView) New(obj *types.NetworkPolicy) *NetworkPolicy {
+	p := NetworkPolicy{}
+	p.Meta = p.ToMeta(obj.Meta)
+	p.Meta.ResourceVersion = obj.ResourceVersion()
+	p.Spec = p.ToSpec(obj.Spec)
+	return &p
+}
+
+func (p *NetworkPolicy) ToJson() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *NetworkPolicy) ToMeta(obj types.NetworkPolicyMeta) NetworkPolicyMeta {
+	meta := NetworkPolicyMeta{}
+	meta.Name = obj.Name
+	meta.Namespace = obj.Namespace
+	meta.Description = obj.Description
+	meta.SelfLink = obj.SelfLink.String()
+	meta.Labels = obj.Labels
+	meta.Updated = obj.Updated
+	meta.Created = obj.Created
+	return meta
+}
+
+func (p *NetworkPolicy) ToSpec(obj types.NetworkPolicySpec) NetworkPolicySpec {
+
+	spec := NetworkPolicySpec{}
+	spec.Selector = make(map[string]string, 0)
+	for k, v := range obj.Selector {
+		spec.Selector[k] = v
+	}
+
+	spec.Ingress = p.ToRules(obj.Ingress)
+	spec.Egress = p.ToRules(obj.Egress)
+
+	return spec
+}
+
+func (p *NetworkPolicy) ToRules(obj []*types.NetworkPolicyRule) []*NetworkPolicyRule {
+
+	rules := make([]*NetworkPolicyRule, 0)
+
+	for _, r := range obj {
+		rule := new(NetworkPolicyRule)
+		rule.Namespaces = r.Namespaces
+		rule.Labels = r.Labels
+		rule.Networks = r.Networks
+		rule.Ports = make([]string, 0)
+		for _, port := range r.Ports {
+			rule.Ports = append(rule.Ports, fmt.Sprintf("%d/%s", port.Port, port.GetProtocol()))
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func (pv NetworkPolicyView) NewList(obj *types.NetworkPolicyList) *NetworkPolicyList {
+	if obj == nil {
+		return nil
+	}
+
+	pl := make(NetworkPolicyList, 0)
+	for _, v := range obj.Items {
+		pl = append(pl, pv.New(v))
+	}
+	return &pl
+}
+
+func (pl *NetworkPolicyList) ToJson() ([]byte, error) {
+	if pl == nil {
+		pl = &NetworkPolicyList{}
+	}
+	return json.Marshal(pl)
+}
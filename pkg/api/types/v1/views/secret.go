@@ -36,11 +36,12 @@ type SecretSpec struct {
 
 // swagger:model views_secret_meta
 type SecretMeta struct {
-	Name      string    `json:"name"`
-	Namespace string    `json:"namespace"`
-	SelfLink  string    `json:"self_link"`
-	Updated   time.Time `json:"updated"`
-	Created   time.Time `json:"created"`
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	SelfLink        string    `json:"self_link"`
+	ResourceVersion string    `json:"resource_version"`
+	Updated         time.Time `json:"updated"`
+	Created         time.Time `json:"created"`
 }
 
 // swagger:ignore
@@ -29,6 +29,7 @@ type SecretView struct{}
 func (sv *SecretView) New(obj *types.Secret) *Secret {
 	s := Secret{}
 	s.Meta = s.ToMeta(obj.Meta)
+	s.Meta.ResourceVersion = obj.ResourceVersion()
 	s.Spec = s.ToSpec(obj.Spec)
 	return &s
 }
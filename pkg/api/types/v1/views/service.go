@@ -38,15 +38,17 @@ type Service struct {
 
 // swagger:model views_service_meta
 type ServiceMeta struct {
-	Name        string            `json:"name"`
-	Namespace   string            `json:"namespace"`
-	Description string            `json:"description"`
-	SelfLink    string            `json:"self_link"`
-	Endpoint    string            `json:"endpoint"`
-	Replicas    int               `json:"replicas"`
-	Labels      map[string]string `json:"labels"`
-	Created     time.Time         `json:"created"`
-	Updated     time.Time         `json:"updated"`
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Description     string            `json:"description"`
+	SelfLink        string            `json:"self_link"`
+	ResourceVersion string            `json:"resource_version"`
+	Endpoint        string            `json:"endpoint"`
+	NodePorts       map[uint16]uint16 `json:"node_ports,omitempty"`
+	Replicas        int               `json:"replicas"`
+	Labels          map[string]string `json:"labels"`
+	Created         time.Time         `json:"created"`
+	Updated         time.Time         `json:"updated"`
 }
 
 // swagger:ignore
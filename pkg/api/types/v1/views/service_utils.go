@@ -35,6 +35,7 @@ type ServiceView struct{}
 func (sv *ServiceView) New(srv *types.Service) *Service {
 	s := new(Service)
 	s.Meta = s.ToMeta(srv.Meta)
+	s.Meta.ResourceVersion = srv.ResourceVersion()
 	s.Status = s.ToStatus(srv.Status)
 	s.Spec = s.ToSpec(srv.Spec)
 	return s
@@ -43,6 +44,7 @@ func (sv *ServiceView) New(srv *types.Service) *Service {
 func (sv *ServiceView) NewWithDeployment(srv *types.Service) *Service {
 	s := new(Service)
 	s.Meta = s.ToMeta(srv.Meta)
+	s.Meta.ResourceVersion = srv.ResourceVersion()
 	s.Status = s.ToStatus(srv.Status)
 	s.Spec = s.ToSpec(srv.Spec)
 	return s
@@ -54,6 +56,7 @@ func (sv *Service) ToMeta(obj types.ServiceMeta) ServiceMeta {
 		Description: obj.Description,
 		SelfLink:    obj.SelfLink.String(),
 		Endpoint:    obj.Endpoint,
+		NodePorts:   obj.NodePorts,
 		Namespace:   obj.Namespace,
 		Labels:      obj.Labels,
 		Updated:     obj.Updated,
@@ -84,8 +87,17 @@ func (sv *Service) ToSpec(obj types.ServiceSpec) ServiceSpec {
 		Template: mv.NewManifestSpecTemplate(obj.Template),
 		Selector: mv.NewManifestSpecSelector(obj.Selector),
 		Network: ManifestSpecNetwork{
-			IP:    obj.Network.IP,
-			Ports: obj.Network.Ports,
+			IP:           obj.Network.IP,
+			IPv6:         obj.Network.IPv6,
+			Ports:        obj.Network.Ports,
+			Route:        obj.Network.Strategy.Route,
+			Affinity:     obj.Network.Strategy.Affinity,
+			Weights:      obj.Network.Strategy.Weights,
+			Expose:       obj.Network.Expose,
+			Headless:     obj.Network.Headless,
+			PortNames:    obj.Network.PortNames,
+			ExternalName: obj.Network.ExternalName,
+			ExternalIPs:  obj.Network.ExternalIPs,
 		},
 		Strategy: ManifestSpecStrategy{
 			Type: obj.Strategy.Type,
@@ -137,6 +149,14 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 
 	sm.Spec.Network = new(request.ManifestSpecNetwork)
 	sm.Spec.Network.IP = &sv.Spec.Network.IP
+	sm.Spec.Network.Route = &sv.Spec.Network.Route
+	sm.Spec.Network.Affinity = &sv.Spec.Network.Affinity
+	sm.Spec.Network.Weights = sv.Spec.Network.Weights
+	sm.Spec.Network.Expose = &sv.Spec.Network.Expose
+	sm.Spec.Network.Headless = &sv.Spec.Network.Headless
+	sm.Spec.Network.PortNames = sv.Spec.Network.PortNames
+	sm.Spec.Network.ExternalName = &sv.Spec.Network.ExternalName
+	sm.Spec.Network.ExternalIPs = sv.Spec.Network.ExternalIPs
 	sm.Spec.Network.Ports = make([]string, 0)
 
 	if sv.Spec.Network.Ports != nil {
@@ -178,12 +198,19 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 		}
 	}
 
+	if sv.Spec.Template.Termination != 0 {
+		termination := sv.Spec.Template.Termination
+		sm.Spec.Template.Termination = &termination
+	}
+
 	sm.Spec.Template.Containers = make([]request.ManifestSpecTemplateContainer, 0)
 	if sv.Spec.Template.Containers != nil {
 		for _, v := range sv.Spec.Template.Containers {
 
 			data := request.ManifestSpecTemplateContainer{
 				Name:       v.Name,
+				Role:       v.Role,
+				Order:      v.Order,
 				Command:    v.Command,
 				Workdir:    v.Workdir,
 				Entrypoint: v.Entrypoint,
@@ -253,6 +280,78 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 				data.RestartPolicy.Attempt = v.RestartPolicy.Attempt
 			}
 
+			if v.Probes != nil && v.Probes.Ready != nil {
+				data.Probes = new(request.ManifestSpecTemplateContainerProbes)
+				data.Probes.Ready = &request.ManifestSpecTemplateContainerProbe{
+					InitialDelay:     v.Probes.Ready.InitialDelay,
+					Timeout:          v.Probes.Ready.Timeout,
+					Period:           v.Probes.Ready.Period,
+					ThresholdSuccess: v.Probes.Ready.ThresholdSuccess,
+					ThresholdFailure: v.Probes.Ready.ThresholdFailure,
+					StartupTimeout:   v.Probes.Ready.StartupTimeout,
+				}
+				if v.Probes.Ready.Exec != nil {
+					data.Probes.Ready.Exec = &request.ManifestSpecTemplateContainerHookExec{
+						Command: v.Probes.Ready.Exec.Command,
+					}
+				}
+				if v.Probes.Ready.Socket != nil {
+					data.Probes.Ready.Socket = &request.ManifestSpecTemplateContainerProbeSocket{
+						Protocol: v.Probes.Ready.Socket.Protocol,
+						Port:     v.Probes.Ready.Socket.Port,
+					}
+				}
+				if v.Probes.Ready.HTTP != nil {
+					data.Probes.Ready.HTTP = &request.ManifestSpecTemplateContainerHookHTTP{
+						Path: v.Probes.Ready.HTTP.Path,
+						Port: v.Probes.Ready.HTTP.Port,
+					}
+				}
+			}
+
+			if v.Lifecycle != nil && v.Lifecycle.PreStop != nil {
+				data.Lifecycle = new(request.ManifestSpecTemplateContainerLifecycle)
+				data.Lifecycle.PreStop = new(request.ManifestSpecTemplateContainerHook)
+				if v.Lifecycle.PreStop.Exec != nil {
+					data.Lifecycle.PreStop.Exec = &request.ManifestSpecTemplateContainerHookExec{
+						Command: v.Lifecycle.PreStop.Exec.Command,
+					}
+				}
+				if v.Lifecycle.PreStop.HTTP != nil {
+					data.Lifecycle.PreStop.HTTP = &request.ManifestSpecTemplateContainerHookHTTP{
+						Path: v.Lifecycle.PreStop.HTTP.Path,
+						Port: v.Lifecycle.PreStop.HTTP.Port,
+					}
+				}
+			}
+
+			if v.Security != nil {
+				data.Security = &request.ManifestSpecSecurity{
+					Privileged:      v.Security.Privileged,
+					User:            v.Security.User,
+					Group:           v.Security.Group,
+					Groups:          v.Security.Groups,
+					ReadOnlyRootfs:  v.Security.ReadOnlyRootfs,
+					NoNewPrivileges: v.Security.NoNewPrivileges,
+					Seccomp:         v.Security.Seccomp,
+					AppArmor:        v.Security.AppArmor,
+				}
+				if v.Security.Capabilities != nil {
+					data.Security.Capabilities = &request.ManifestSpecSecurityCapabilities{
+						Add:  v.Security.Capabilities.Add,
+						Drop: v.Security.Capabilities.Drop,
+					}
+				}
+				if v.Security.SELinux != nil {
+					data.Security.SELinux = &request.ManifestSpecSecuritySELinux{
+						User:  v.Security.SELinux.User,
+						Role:  v.Security.SELinux.Role,
+						Type:  v.Security.SELinux.Type,
+						Level: v.Security.SELinux.Level,
+					}
+				}
+			}
+
 			sm.Spec.Template.Containers = append(sm.Spec.Template.Containers, data)
 		}
 	}
@@ -94,6 +94,8 @@ type SpecTemplateContainer struct {
 	Name string `json:"name" yaml:"name"`
 	// Template container role
 	Role string `json:"role" yaml:"role"`
+	// Template container startup order
+	Order int `json:"order" yaml:"order"`
 	// Automatically remove container when it exits
 	AutoRemove bool `json:"autoremove" yaml:"autoremove"`
 	// Labels list
@@ -148,7 +150,7 @@ type SpecTemplateContainerPort struct {
 	// Host port
 	HostPort uint16 `json:"host_port"`
 	// Host port
-	HostIP string `json:"host_port"`
+	HostIP string `json:"host_ip"`
 	// Binding protocol
 	Protocol string `json:"protocol"`
 }
@@ -223,11 +225,17 @@ type SpecTemplateContainerProbe struct {
 		Port     int    `json:"port"`
 	} `json:"socket"`
 
-	InitialDelaySeconds int `json:"initial_delay"`
-	TimeoutSeconds      int `json:"timeout_seconds"`
-	PeriodSeconds       int `json:"period_seconds"`
-	ThresholdSuccess    int `json:"threshold_success"`
-	ThresholdFailure    int `json:"threshold_failure"`
+	HTTP struct {
+		Path string `json:"path"`
+		Port int    `json:"port"`
+	} `json:"http"`
+
+	InitialDelaySeconds   int `json:"initial_delay"`
+	TimeoutSeconds        int `json:"timeout_seconds"`
+	PeriodSeconds         int `json:"period_seconds"`
+	ThresholdSuccess      int `json:"threshold_success"`
+	ThresholdFailure      int `json:"threshold_failure"`
+	StartupTimeoutSeconds int `json:"startup_timeout"`
 }
 
 type SpecTemplateContainerSecurity struct {
@@ -237,11 +245,32 @@ type SpecTemplateContainerSecurity struct {
 	LinuxOptions SpecTemplateContainerSecurityLinuxOptions `json:"linux_options"`
 	// Run container as particular user
 	User int `json:"user"`
+	// Run container as particular group
+	Group int `json:"group"`
+	// Supplemental groups of container process
+	Groups []int `json:"groups"`
+	// Linux capabilities to add and drop
+	Capabilities SpecTemplateContainerSecurityCapabilities `json:"capabilities"`
+	// Mount container root filesystem as read only
+	ReadOnlyRootfs bool `json:"readonly_rootfs"`
+	// Do not allow container process to gain new privileges
+	NoNewPrivileges bool `json:"no_new_privileges"`
 }
 
 // swagger:model types_spec_template_container_security_linux
 type SpecTemplateContainerSecurityLinuxOptions struct {
-	Level string `json:"level"`
+	User     string `json:"user"`
+	Role     string `json:"role"`
+	Type     string `json:"type"`
+	Level    string `json:"level"`
+	Seccomp  string `json:"seccomp"`
+	AppArmor string `json:"apparmor"`
+}
+
+// swagger:model types_spec_template_container_security_capabilities
+type SpecTemplateContainerSecurityCapabilities struct {
+	Add  []string `json:"add"`
+	Drop []string `json:"drop"`
 }
 
 // swagger:model types_spec_template_container_network
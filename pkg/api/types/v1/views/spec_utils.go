@@ -50,6 +50,7 @@ func (sv *SpecView) NewContainer(c *types.SpecTemplateContainer) *SpecTemplateCo
 	s.ID = c.ID
 	s.Name = c.Name
 	s.Role = c.Role
+	s.Order = c.Order
 	s.AutoRemove = c.AutoRemove
 	s.Labels = c.Labels
 	s.Image = SpecTemplateContainerImage{
@@ -117,31 +118,48 @@ func (sv *SpecView) NewContainer(c *types.SpecTemplateContainer) *SpecTemplateCo
 	}
 
 	s.Probes.LiveProbe = SpecTemplateContainerProbe{
-		Exec:                c.Probes.LiveProbe.Exec,
-		Socket:              c.Probes.LiveProbe.Socket,
-		InitialDelaySeconds: c.Probes.LiveProbe.InitialDelaySeconds,
-		TimeoutSeconds:      c.Probes.LiveProbe.TimeoutSeconds,
-		PeriodSeconds:       c.Probes.LiveProbe.PeriodSeconds,
-		ThresholdSuccess:    c.Probes.LiveProbe.ThresholdSuccess,
-		ThresholdFailure:    c.Probes.LiveProbe.ThresholdFailure,
+		Exec:                  c.Probes.LiveProbe.Exec,
+		Socket:                c.Probes.LiveProbe.Socket,
+		HTTP:                  c.Probes.LiveProbe.HTTP,
+		InitialDelaySeconds:   c.Probes.LiveProbe.InitialDelaySeconds,
+		TimeoutSeconds:        c.Probes.LiveProbe.TimeoutSeconds,
+		PeriodSeconds:         c.Probes.LiveProbe.PeriodSeconds,
+		ThresholdSuccess:      c.Probes.LiveProbe.ThresholdSuccess,
+		ThresholdFailure:      c.Probes.LiveProbe.ThresholdFailure,
+		StartupTimeoutSeconds: c.Probes.LiveProbe.StartupTimeoutSeconds,
 	}
 
 	s.Probes.ReadProbe = SpecTemplateContainerProbe{
-		Exec:                c.Probes.ReadProbe.Exec,
-		Socket:              c.Probes.ReadProbe.Socket,
-		InitialDelaySeconds: c.Probes.ReadProbe.InitialDelaySeconds,
-		TimeoutSeconds:      c.Probes.ReadProbe.TimeoutSeconds,
-		PeriodSeconds:       c.Probes.ReadProbe.PeriodSeconds,
-		ThresholdSuccess:    c.Probes.ReadProbe.ThresholdSuccess,
-		ThresholdFailure:    c.Probes.ReadProbe.ThresholdFailure,
+		Exec:                  c.Probes.ReadProbe.Exec,
+		Socket:                c.Probes.ReadProbe.Socket,
+		HTTP:                  c.Probes.ReadProbe.HTTP,
+		InitialDelaySeconds:   c.Probes.ReadProbe.InitialDelaySeconds,
+		TimeoutSeconds:        c.Probes.ReadProbe.TimeoutSeconds,
+		PeriodSeconds:         c.Probes.ReadProbe.PeriodSeconds,
+		ThresholdSuccess:      c.Probes.ReadProbe.ThresholdSuccess,
+		ThresholdFailure:      c.Probes.ReadProbe.ThresholdFailure,
+		StartupTimeoutSeconds: c.Probes.ReadProbe.StartupTimeoutSeconds,
 	}
 
 	s.Security = SpecTemplateContainerSecurity{
 		Privileged: c.Security.Privileged,
 		LinuxOptions: SpecTemplateContainerSecurityLinuxOptions{
-			Level: c.Security.LinuxOptions.Level,
+			User:     c.Security.LinuxOptions.User,
+			Role:     c.Security.LinuxOptions.Role,
+			Type:     c.Security.LinuxOptions.Type,
+			Level:    c.Security.LinuxOptions.Level,
+			Seccomp:  c.Security.LinuxOptions.Seccomp,
+			AppArmor: c.Security.LinuxOptions.AppArmor,
 		},
-		User: c.Security.User,
+		User:   c.Security.User,
+		Group:  c.Security.Group,
+		Groups: c.Security.Groups,
+		Capabilities: SpecTemplateContainerSecurityCapabilities{
+			Add:  c.Security.Capabilities.Add,
+			Drop: c.Security.Capabilities.Drop,
+		},
+		ReadOnlyRootfs:  c.Security.ReadOnlyRootfs,
+		NoNewPrivileges: c.Security.NoNewPrivileges,
 	}
 
 	s.Network = SpecTemplateContainerNetwork{
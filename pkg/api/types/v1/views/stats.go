@@ -0,0 +1,111 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import "time"
+
+// NodeStats - node pods resources usage
+// swagger:model views_node_stats
+type NodeStats struct {
+	// Node name
+	Node string `json:"node"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+	// Node pods total usage
+	Usage StatsUsage `json:"usage"`
+	// Node pods stats
+	Pods map[string]*PodStats `json:"pods"`
+	// Node endpoints stats
+	Endpoints map[string]*EndpointStats `json:"endpoints"`
+}
+
+// PodStats - pod containers resources usage
+// swagger:model views_pod_stats
+type PodStats struct {
+	// Pod selflink
+	Pod string `json:"pod"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+	// Pod containers total usage
+	Usage StatsUsage `json:"usage"`
+	// Pod containers stats
+	Containers map[string]*ContainerStats `json:"containers"`
+}
+
+// ContainerStats - container resources usage
+// swagger:model views_container_stats
+type ContainerStats struct {
+	// Container ID
+	ID string `json:"id"`
+	// Container name
+	Name string `json:"name"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+	// Container usage
+	Usage StatsUsage `json:"usage"`
+}
+
+// EndpointStats - endpoint upstreams connections
+// swagger:model views_endpoint_stats
+type EndpointStats struct {
+	// Endpoint selflink
+	Endpoint string `json:"endpoint"`
+	// Endpoint route strategy
+	Route string `json:"route"`
+	// Endpoint upstreams connections
+	Upstreams map[string]*EndpointUpstreamStats `json:"upstreams"`
+}
+
+type EndpointUpstreamStats struct {
+	// Active connections count
+	Active int `json:"active"`
+	// Inactive connections count
+	Inactive int `json:"inactive"`
+}
+
+// swagger:model views_stats_usage
+type StatsUsage struct {
+	// CPU usage
+	CPU StatsCPU `json:"cpu"`
+	// Memory usage
+	Memory StatsMemory `json:"memory"`
+	// Network IO, received and transmitted bytes
+	Network StatsIO `json:"network"`
+	// Block IO, read and written bytes
+	Block StatsIO `json:"block"`
+}
+
+type StatsCPU struct {
+	// Total consumed CPU time in nanoseconds
+	Total uint64 `json:"total"`
+	// CPU usage percent of node CPU
+	Percent float64 `json:"percent"`
+}
+
+type StatsMemory struct {
+	// Resident memory in bytes
+	RSS uint64 `json:"rss"`
+	// Memory limit in bytes
+	Limit uint64 `json:"limit"`
+}
+
+type StatsIO struct {
+	Read  uint64 `json:"read"`
+	Write uint64 `json:"write"`
+}
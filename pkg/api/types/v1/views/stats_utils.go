@@ -0,0 +1,106 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type StatsView struct{}
+
+func (sv *StatsView) NewNodeStats(obj *types.NodeStats) *NodeStats {
+	s := new(NodeStats)
+	s.Node = obj.Node
+	s.Timestamp = obj.Timestamp
+	s.Usage = sv.NewUsage(obj.StatsUsage)
+	s.Pods = make(map[string]*PodStats, 0)
+
+	for k, p := range obj.Pods {
+		s.Pods[k] = sv.NewPodStats(p)
+	}
+
+	s.Endpoints = make(map[string]*EndpointStats, 0)
+	for k, e := range obj.Endpoints {
+		s.Endpoints[k] = sv.NewEndpointStats(e)
+	}
+
+	return s
+}
+
+func (sv *StatsView) NewPodStats(obj *types.PodStats) *PodStats {
+	s := new(PodStats)
+	s.Pod = obj.Pod
+	s.Timestamp = obj.Timestamp
+	s.Usage = sv.NewUsage(obj.StatsUsage)
+	s.Containers = make(map[string]*ContainerStats, 0)
+
+	for k, c := range obj.Containers {
+		s.Containers[k] = sv.NewContainerStats(c)
+	}
+
+	return s
+}
+
+func (sv *StatsView) NewContainerStats(obj *types.ContainerStats) *ContainerStats {
+	s := new(ContainerStats)
+	s.ID = obj.ID
+	s.Name = obj.Name
+	s.Timestamp = obj.Timestamp
+	s.Usage = sv.NewUsage(obj.StatsUsage)
+	return s
+}
+
+func (sv *StatsView) NewEndpointStats(obj *types.EndpointStats) *EndpointStats {
+	s := new(EndpointStats)
+	s.Endpoint = obj.Endpoint
+	s.Route = obj.Route
+	s.Upstreams = make(map[string]*EndpointUpstreamStats, 0)
+
+	for k, u := range obj.Upstreams {
+		s.Upstreams[k] = &EndpointUpstreamStats{
+			Active:   u.Active,
+			Inactive: u.Inactive,
+		}
+	}
+
+	return s
+}
+
+func (sv *StatsView) NewUsage(obj types.StatsUsage) StatsUsage {
+	u := StatsUsage{}
+	u.CPU.Total = obj.CPU.Total
+	u.CPU.Percent = obj.CPU.Percent
+	u.Memory.RSS = obj.Memory.RSS
+	u.Memory.Limit = obj.Memory.Limit
+	u.Network.Read = obj.Network.Read
+	u.Network.Write = obj.Network.Write
+	u.Block.Read = obj.Block.Read
+	u.Block.Write = obj.Block.Write
+	return u
+}
+
+func (obj *NodeStats) ToJson() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+func (obj *PodStats) ToJson() ([]byte, error) {
+	return json.Marshal(obj)
+}
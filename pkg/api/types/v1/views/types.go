@@ -32,16 +32,19 @@ type IView interface {
 	Service() *ServiceView
 	Secret() *SecretView
 	Config() *ConfigView
+	NetworkPolicy() *NetworkPolicyView
 	Deployment() *DeploymentView
 	Endpoint() *EndpointView
 	Pod() *PodView
 	Container() *ContainerView
 	Volume() *VolumeView
+	Stats() *StatsView
 
 	Job() *JobView
 	Task() *TaskView
 
 	Event() *EventView
+	List() *ListView
 }
 
 type View struct{}
@@ -77,6 +80,9 @@ func (View) Secret() *SecretView {
 func (View) Config() *ConfigView {
 	return new(ConfigView)
 }
+func (View) NetworkPolicy() *NetworkPolicyView {
+	return new(NetworkPolicyView)
+}
 func (View) Deployment() *DeploymentView {
 	return new(DeploymentView)
 }
@@ -94,6 +100,10 @@ func (View) Volume() *VolumeView {
 	return new(VolumeView)
 }
 
+func (View) Stats() *StatsView {
+	return new(StatsView)
+}
+
 func (View) Event() *EventView {
 	return new(EventView)
 }
@@ -105,3 +115,7 @@ func (View) Job() *JobView {
 func (View) Task() *TaskView {
 	return new(TaskView)
 }
+
+func (View) List() *ListView {
+	return new(ListView)
+}
@@ -27,12 +27,13 @@ type Volume struct {
 }
 
 type VolumeMeta struct {
-	Name        string    `json:"name"`
-	Namespace   string    `json:"namespace"`
-	Description string    `json:"description"`
-	SelfLink    string    `json:"self_link"`
-	Updated     time.Time `json:"updated"`
-	Created     time.Time `json:"created"`
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Description     string    `json:"description"`
+	SelfLink        string    `json:"self_link"`
+	ResourceVersion string    `json:"resource_version"`
+	Updated         time.Time `json:"updated"`
+	Created         time.Time `json:"created"`
 }
 
 type VolumeSpec struct {
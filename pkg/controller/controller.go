@@ -26,6 +26,7 @@ import (
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
 	"github.com/lastbackend/lastbackend/pkg/controller/runtime"
 	l "github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
@@ -55,19 +56,38 @@ func Daemon(v *viper.Viper) bool {
 	if err != nil {
 		log.Fatalf("Cannot initialize storage: %s", err.Error())
 	}
+
+	// refuse to manage data written by newer version, changes can not be handled safely
+	if err := storage.CheckSchema(context.Background(), stg); err != nil {
+		log.Fatalf("Cannot use storage: %s", err.Error())
+	}
+
 	env.SetStorage(stg)
 
-	cidr := defaultCIDR
+	cfg := local.Config{}
+	cfg.CIDR = defaultCIDR
 	if v.IsSet("service") && v.IsSet("service.cidr") {
-		cidr = v.GetString("service.cidr")
+		cfg.CIDR = v.GetString("service.cidr")
 	}
 
-	ipm, err := ipam.New(cidr)
+	// IPv6 services range is optional, endpoints get IPv6 address only if it is set.
+	// Cluster range is optional, nodes pod subnets are leased from it if it is set.
+	// Node ports range is optional, services can be exposed on all nodes if it is set
+	cfg.CIDRv6 = v.GetString("service.cidr_v6")
+	cfg.Subnets = v.GetString("network.cidr")
+	cfg.SubnetPrefix = v.GetInt("network.subnet_prefix")
+	cfg.NodePorts = v.GetString("service.node_port_range")
+
+	ipm, err := ipam.New(cfg)
 	if err != nil {
 		log.Fatalf("Cannot initialize ipam service: %s", err.Error())
 	}
 	env.SetIPAM(ipm)
 
+	// pods are terminated after the delay since they are removed from service upstreams,
+	// so nodes proxies and dns have time to stop routing requests to them
+	env.SetUpstreamReleaseDelay(v.GetDuration("service.upstream_release_delay"))
+
 	// Initialize Container
 	r := runtime.NewRuntime(context.Background())
 	r.Loop()
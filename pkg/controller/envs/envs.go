@@ -19,6 +19,8 @@
 package envs
 
 import (
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam/ipam"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 )
@@ -28,6 +30,8 @@ var e Env
 type Env struct {
 	storage storage.Storage
 	ipam    ipam.IPAM
+
+	upstreamReleaseDelay time.Duration
 }
 
 func Get() *Env {
@@ -49,3 +53,11 @@ func (c *Env) SetIPAM(ipam ipam.IPAM) {
 func (c *Env) GetIPAM() ipam.IPAM {
 	return c.ipam
 }
+
+func (c *Env) SetUpstreamReleaseDelay(delay time.Duration) {
+	c.upstreamReleaseDelay = delay
+}
+
+func (c *Env) GetUpstreamReleaseDelay() time.Duration {
+	return c.upstreamReleaseDelay
+}
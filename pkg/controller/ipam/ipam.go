@@ -23,6 +23,6 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
 )
 
-func New(cidr string) (ipam.IPAM, error) {
-	return local.New(cidr)
+func New(cfg local.Config) (ipam.IPAM, error) {
+	return local.New(cfg)
 }
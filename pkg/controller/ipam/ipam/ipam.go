@@ -20,11 +20,24 @@ package ipam
 
 import (
 	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type IPAM interface {
-	Lease() (*net.IP, error)
-	Release(ip *net.IP) error
-	Available() int
-	Reserved() int
+	Lease(owner string) (*net.IP, error)
+	LeaseIPv6(owner string) (*net.IP, error)
+	Reserve(ip net.IP, owner string) error
+	Release(ip *net.IP, owner string) error
+	DualStack() bool
+	LeaseSubnet(owner string) (*net.IPNet, error)
+	ReserveSubnet(ipn *net.IPNet, owner string) error
+	ReleaseSubnet(ipn *net.IPNet, owner string) error
+	Subnets() bool
+	LeasePort(owner string) (uint16, error)
+	ReservePort(port uint16, owner string) error
+	ReleasePort(port uint16, owner string) error
+	NodePorts() bool
+	Collect(owners map[string][]string) (int, error)
+	Utilization() (*types.IPAMUtilization, error)
 }
@@ -20,161 +20,685 @@ package local
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 )
 
 const (
-	logIPAMPrefix         = "controller:ipam:>"
-	IPAMLeaseNotAvailable = "IPAMLeaseNotAvailable"
-	defaultCIDR           = "172.17.0.0/16"
+	logLevel                = 3
+	logIPAMPrefix           = "controller:ipam:>"
+	IPAMLeaseNotAvailable   = "IPAMLeaseNotAvailable"
+	IPAMLeaseConflict       = "IPAMLeaseConflict"
+	IPAMIPv6NotEnabled      = "IPAMIPv6NotEnabled"
+	IPAMSubnetsNotEnabled   = "IPAMSubnetsNotEnabled"
+	IPAMSubnetOutOfRange    = "IPAMSubnetOutOfRange"
+	IPAMNodePortsNotEnabled = "IPAMNodePortsNotEnabled"
+	IPAMNodePortOutOfRange  = "IPAMNodePortOutOfRange"
+	defaultCIDR             = "172.17.0.0/16"
+	defaultSubnetPrefix     = 24
+	defaultSubnetPrefixV6   = 64
+	collectGracePeriod      = time.Minute
+	legacyLeasesSystemName  = "ipam"
 )
 
-// IPAM - IP address management
+// Config of IPAM pools, optional ranges are disabled if empty
+type Config struct {
+	// CIDR - services IPv4 range, default range is used if empty
+	CIDR string
+	// CIDRv6 - services IPv6 range, enables dual stack leases
+	CIDRv6 string
+	// Subnets - cluster range, enables nodes pod subnets leases
+	Subnets string
+	// SubnetPrefix - prefix length of node pod subnet, default depends on cluster range family
+	SubnetPrefix int
+	// NodePorts - ports range like 30000-32767, enables services exposure on all nodes
+	NodePorts string
+}
+
+// IPAM - IP address management.
+// Every lease is stored as separate key and is created only if key does not exist,
+// so address can not be leased twice by several controllers.
+// Local state is only a hint of leased addresses to skip them quickly
 type IPAM struct {
-	leased    map[string]bool
-	released  map[string]bool
-	available int
-	reserved  int
-	storage   storage.Storage
+	lock   sync.Mutex
+	v4     *pool
+	v6     *pool
+	subnet *pool
+	ports  *portPool
+	model  *distribution.IPAM
 }
 
-// Lease IP from range
-func (i *IPAM) Lease() (*net.IP, error) {
+// Lease IPv4 from range
+func (i *IPAM) Lease(owner string) (*net.IP, error) {
+	ipn, err := i.lease(i.v4, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &ipn.IP, nil
+}
 
-	var (
-		lease string
-	)
+// LeaseIPv6 leases IP from IPv6 range if dual stack is enabled
+func (i *IPAM) LeaseIPv6(owner string) (*net.IP, error) {
+	if i.v6 == nil {
+		return nil, errors.New(IPAMIPv6NotEnabled)
+	}
+	ipn, err := i.lease(i.v6, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &ipn.IP, nil
+}
 
-	// Check available leases count
-	if i.available == 0 {
-		return nil, errors.New(IPAMLeaseNotAvailable)
+// Reserve marks IP as leased by owner, IP out of services ranges is not tracked
+func (i *IPAM) Reserve(ip net.IP, owner string) error {
+	for _, p := range i.addrPools() {
+		if p.contains(ip) {
+			return i.reserve(p, &net.IPNet{IP: ip, Mask: net.CIDRMask(p.prefix, p.prefix)}, owner)
+		}
 	}
+	return nil
+}
 
-	// Get first map element
-	for i := range i.released {
-		lease = i
-		break
+// Release IP leased by owner
+func (i *IPAM) Release(ip *net.IP, owner string) error {
+	for _, p := range i.addrPools() {
+		if p.contains(*ip) {
+			return i.release(p, &net.IPNet{IP: *ip}, owner)
+		}
 	}
+	return nil
+}
+
+// DualStack checks if IPv6 range is configured
+func (i *IPAM) DualStack() bool {
+	return i.v6 != nil
+}
 
-	// Mark lease IP as leased
-	delete(i.released, lease)
-	i.available--
-	// Find new lease for next reservation
+// LeaseSubnet leases pod subnet for node from cluster range
+func (i *IPAM) LeaseSubnet(owner string) (*net.IPNet, error) {
+	if i.subnet == nil {
+		return nil, errors.New(IPAMSubnetsNotEnabled)
+	}
+	return i.lease(i.subnet, owner)
+}
 
-	// Decrease available count
-	i.leased[lease] = true
-	i.reserved++
+// ReserveSubnet marks subnet as leased by owner, subnet should match cluster range and prefix
+func (i *IPAM) ReserveSubnet(ipn *net.IPNet, owner string) error {
 
-	ip := net.ParseIP(lease)
+	if i.subnet == nil {
+		return errors.New(IPAMSubnetsNotEnabled)
+	}
 
-	if err := i.save(); err != nil {
-		return nil, err
+	if ones, _ := ipn.Mask.Size(); ones != i.subnet.prefix {
+		return errors.New(IPAMSubnetOutOfRange)
 	}
 
-	return &ip, nil
+	if _, ok := i.subnet.index(ipn.IP); !ok {
+		return errors.New(IPAMSubnetOutOfRange)
+	}
+
+	return i.reserve(i.subnet, ipn, owner)
 }
 
-// release IP
-func (i *IPAM) Release(ip *net.IP) error {
+// ReleaseSubnet releases node pod subnet leased by owner
+func (i *IPAM) ReleaseSubnet(ipn *net.IPNet, owner string) error {
+	if i.subnet == nil || !i.subnet.contains(ipn.IP) {
+		return nil
+	}
+	return i.release(i.subnet, ipn, owner)
+}
 
-	var (
-		lease = ip.String()
-	)
+// Subnets checks if nodes subnets range is configured
+func (i *IPAM) Subnets() bool {
+	return i.subnet != nil
+}
 
-	// Mark IP as released
-	delete(i.leased, lease)
-	i.reserved--
-	// Add IP as released and increase available count
-	i.released[lease] = true
-	i.available++
+// LeasePort leases port exposed on all nodes from node ports range
+func (i *IPAM) LeasePort(owner string) (uint16, error) {
 
-	return i.save()
+	if i.ports == nil {
+		return 0, errors.New(IPAMNodePortsNotEnabled)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	var reloaded = false
+
+	for {
+
+		port := i.ports.candidate()
+		if port == 0 {
+
+			// leases can be released by another controller, refresh state before giving up
+			if reloaded {
+				return 0, errors.New(IPAMLeaseNotAvailable)
+			}
+
+			if err := i.loadPorts(); err != nil {
+				return 0, err
+			}
+
+			reloaded = true
+			continue
+		}
+
+		err := i.model.LeasePut(newPortLease(i.ports, port, owner))
+		if err == nil {
+			i.ports.mark(port, owner)
+			return uint16(port), nil
+		}
+
+		if !errors.Storage().IsErrEntityExists(err) {
+			return 0, err
+		}
+
+		// port is leased by another controller
+		i.ports.mark(port, types.EmptyString)
+	}
 }
 
-// Available ips count
-func (i *IPAM) Available() int {
-	return i.available
+// ReservePort marks node port as leased by owner, port should be in node ports range
+func (i *IPAM) ReservePort(port uint16, owner string) error {
+
+	if i.ports == nil {
+		return errors.New(IPAMNodePortsNotEnabled)
+	}
+
+	if !i.ports.contains(int(port)) {
+		return errors.New(IPAMNodePortOutOfRange)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	err := i.model.LeasePut(newPortLease(i.ports, int(port), owner))
+	if err == nil {
+		i.ports.mark(int(port), owner)
+		return nil
+	}
+
+	if !errors.Storage().IsErrEntityExists(err) {
+		return err
+	}
+
+	l, err := i.model.LeaseGet(i.ports.name, strconv.Itoa(int(port)))
+	if err != nil {
+		return err
+	}
+
+	// lease was removed right after conflict, retry is left to the caller
+	if l == nil {
+		return errors.New(IPAMLeaseConflict)
+	}
+
+	i.ports.mark(int(port), l.Owner)
+
+	if l.Owner != owner {
+		log.V(logLevel).Errorf("%s port %s is already leased by %s", logIPAMPrefix, l.Addr, l.Owner)
+		return errors.New(IPAMLeaseConflict)
+	}
+
+	return nil
 }
 
-// Reserved ips count
-func (i *IPAM) Reserved() int {
-	return i.reserved
+// ReleasePort releases node port leased by owner
+func (i *IPAM) ReleasePort(port uint16, owner string) error {
+
+	if i.ports == nil || !i.ports.contains(int(port)) {
+		return nil
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if err := i.del(i.ports.name, strconv.Itoa(int(port)), owner); err != nil {
+		return err
+	}
+
+	i.ports.release(int(port))
+	return nil
 }
 
-func (i *IPAM) save() error {
+// NodePorts checks if node ports range is configured
+func (i *IPAM) NodePorts() bool {
+	return i.ports != nil
+}
 
-	var (
-		ips = make([]string, 0)
-	)
+// Collect releases leases which are not used by owners.
+// Owners map contains addresses and node ports in use by every existing owner,
+// leases created recently are skipped, because owner can be not stored yet
+func (i *IPAM) Collect(owners map[string][]string) (int, error) {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	var count = 0
+
+	for _, p := range i.pools() {
+
+		leases, n, err := i.collect(p.name, owners)
+		count += n
+		if err != nil {
+			return count, err
+		}
+
+		p.load(leases)
+	}
+
+	if i.ports != nil {
+
+		leases, n, err := i.collect(i.ports.name, owners)
+		count += n
+		if err != nil {
+			return count, err
+		}
+
+		i.ports.load(leases)
+	}
+
+	return count, nil
+}
+
+// Utilization returns usage of pools stored leases
+func (i *IPAM) Utilization() (*types.IPAMUtilization, error) {
+	return i.model.Utilization()
+}
+
+// collect removes unused leases of pool and returns leases left
+func (i *IPAM) collect(name string, owners map[string][]string) (*types.IPAMLeaseMap, int, error) {
+
+	var count = 0
+
+	leases, err := i.model.LeaseMap(name)
+	if err != nil {
+		return nil, count, err
+	}
+
+	for key, l := range leases.Items {
+
+		if time.Since(l.Created) < collectGracePeriod || inUse(owners, l) {
+			continue
+		}
+
+		log.V(logLevel).Debugf("%s collect lease %s of %s", logIPAMPrefix, l.Addr, l.Owner)
+
+		// lease can be released and leased again by another controller after it was read
+		if err := i.model.LeaseDel(l); err != nil {
+			if errors.Storage().IsErrEntityConflict(err) {
+				log.V(logLevel).Debugf("%s lease %s was changed, skip collect", logIPAMPrefix, l.Addr)
+				continue
+			}
+			return nil, count, err
+		}
+
+		delete(leases.Items, key)
+		count++
+	}
+
+	return leases, count, nil
+}
+
+func (i *IPAM) lease(p *pool, owner string) (*net.IPNet, error) {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	var reloaded = false
+
+	for {
+
+		ipn := p.candidate()
+		if ipn == nil {
+
+			// leases can be released by another controller, refresh state before giving up
+			if reloaded {
+				return nil, errors.New(IPAMLeaseNotAvailable)
+			}
+
+			if err := i.load(p); err != nil {
+				return nil, err
+			}
+
+			reloaded = true
+			continue
+		}
+
+		err := i.model.LeasePut(newLease(p, ipn, owner))
+		if err == nil {
+			p.mark(ipn.IP, owner)
+			return ipn, nil
+		}
+
+		if !errors.Storage().IsErrEntityExists(err) {
+			return nil, err
+		}
+
+		// address is leased by another controller
+		p.mark(ipn.IP, types.EmptyString)
+	}
+}
+
+func (i *IPAM) reserve(p *pool, ipn *net.IPNet, owner string) error {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	err := i.model.LeasePut(newLease(p, ipn, owner))
+	if err == nil {
+		p.mark(ipn.IP, owner)
+		return nil
+	}
+
+	if !errors.Storage().IsErrEntityExists(err) {
+		return err
+	}
 
-	for ip := range i.leased {
-		ips = append(ips, ip)
+	l, err := i.model.LeaseGet(p.name, p.format(ipn))
+	if err != nil {
+		return err
+	}
+
+	// lease was removed right after conflict, retry is left to the caller
+	if l == nil {
+		return errors.New(IPAMLeaseConflict)
+	}
+
+	p.mark(ipn.IP, l.Owner)
+
+	if l.Owner != owner {
+		log.V(logLevel).Errorf("%s %s is already leased by %s", logIPAMPrefix, l.Addr, l.Owner)
+		return errors.New(IPAMLeaseConflict)
 	}
 
-	opts := storage.GetOpts()
-	opts.Force = true
-	return i.storage.Set(context.Background(), i.storage.Collection().System(), "ipam", &ips, opts)
+	return nil
+}
+
+func (i *IPAM) release(p *pool, ipn *net.IPNet, owner string) error {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if err := i.del(p.name, p.format(ipn), owner); err != nil {
+		return err
+	}
+
+	p.release(ipn.IP)
+	return nil
+}
+
+// del removes stored lease only if address is leased by owner,
+// lease is compared by revision, so lease taken by another owner after read is kept
+func (i *IPAM) del(pool, addr, owner string) error {
+
+	l, err := i.model.LeaseGet(pool, addr)
+	if err != nil {
+		return err
+	}
+
+	if l == nil {
+		return nil
+	}
+
+	if l.Owner != owner {
+		log.V(logLevel).Errorf("%s %s is leased by %s, it can not be released by %s", logIPAMPrefix, l.Addr, l.Owner, owner)
+		return errors.New(IPAMLeaseConflict)
+	}
+
+	if err := i.model.LeaseDel(l); err != nil {
+		if errors.Storage().IsErrEntityConflict(err) {
+			return errors.New(IPAMLeaseConflict)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// load replaces local state of pool with stored leases
+func (i *IPAM) load(p *pool) error {
+
+	leases, err := i.model.LeaseMap(p.name)
+	if err != nil {
+		return err
+	}
+
+	p.load(leases)
+	return nil
+}
+
+// loadPorts replaces local state of node ports pool with stored leases
+func (i *IPAM) loadPorts() error {
+
+	leases, err := i.model.LeaseMap(i.ports.name)
+	if err != nil {
+		return err
+	}
+
+	i.ports.load(leases)
+	return nil
+}
+
+func (i *IPAM) addrPools() []*pool {
+	if i.v6 == nil {
+		return []*pool{i.v4}
+	}
+	return []*pool{i.v4, i.v6}
+}
+
+func (i *IPAM) pools() []*pool {
+	if i.subnet == nil {
+		return i.addrPools()
+	}
+	return append(i.addrPools(), i.subnet)
+}
+
+// register stores pools configuration to be available for utilization requests
+func (i *IPAM) register() error {
+
+	names := map[string]*pool{
+		types.IPAMPoolService:     i.v4,
+		types.IPAMPoolServiceIPv6: i.v6,
+		types.IPAMPoolSubnet:      i.subnet,
+	}
+
+	for name, p := range names {
+
+		// leases of disabled pool are kept to be restored if pool is enabled again
+		if p == nil {
+			if err := i.model.PoolDel(name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pl := new(types.IPAMPool)
+		pl.Name = p.name
+		pl.CIDR = p.network.String()
+		pl.Prefix = p.prefix
+		pl.Size = p.size
+
+		if err := i.model.PoolSet(pl); err != nil {
+			return err
+		}
+	}
+
+	if i.ports == nil {
+		return i.model.PoolDel(types.IPAMPoolNodePort)
+	}
+
+	pl := new(types.IPAMPool)
+	pl.Name = i.ports.name
+	pl.CIDR = i.ports.format()
+	pl.Size = i.ports.size
+
+	return i.model.PoolSet(pl)
+}
+
+// migrate imports leases of endpoints stored by previous IPAM version,
+// which kept leased addresses list in system collection without owners
+func (i *IPAM) migrate(stg storage.Storage) error {
+
+	ips := make([]string, 0)
+
+	err := stg.Get(context.Background(), stg.Collection().System(), legacyLeasesSystemName, &ips, nil)
+	if err != nil {
+		if errors.Storage().IsErrEntityNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	log.V(logLevel).Debugf("%s import %d legacy leases", logIPAMPrefix, len(ips))
+
+	em := distribution.NewEndpointModel(context.Background(), stg)
+	el, err := em.List(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range el.Items {
+		for _, addr := range e.Spec.GetIPs() {
+
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+
+			if err := i.Reserve(ip, e.SelfLink().String()); err != nil {
+				log.Errorf("%s import lease %s err: %s", logIPAMPrefix, addr, err.Error())
+			}
+		}
+	}
+
+	return stg.Del(context.Background(), stg.Collection().System(), legacyLeasesSystemName)
+}
+
+func newLease(p *pool, ipn *net.IPNet, owner string) *types.IPAMLease {
+	l := new(types.IPAMLease)
+	l.Pool = p.name
+	l.Addr = p.format(ipn)
+	l.Owner = owner
+	l.Created = time.Now()
+	return l
+}
+
+func newPortLease(p *portPool, port int, owner string) *types.IPAMLease {
+	l := new(types.IPAMLease)
+	l.Pool = p.name
+	l.Addr = strconv.Itoa(port)
+	l.Owner = owner
+	l.Created = time.Now()
+	return l
+}
+
+func inUse(owners map[string][]string, l *types.IPAMLease) bool {
+
+	addrs, ok := owners[l.Owner]
+	if !ok {
+		return false
+	}
+
+	for _, addr := range addrs {
+		if addr == l.Addr {
+			return true
+		}
+	}
+
+	return false
 }
 
 // New IPAM object initializing and returning
-func New(cidr string) (*IPAM, error) {
+func New(cfg Config) (*IPAM, error) {
 
 	var (
-		skip = true
+		err  error
 		ipam = new(IPAM)
 		stg  = envs.Get().GetStorage()
 	)
 
-	ipam.storage = envs.Get().GetStorage()
-	ipam.leased = make(map[string]bool, 0)
-	ipam.released = make(map[string]bool, 0)
+	ipam.model = distribution.NewIPAMModel(context.Background(), stg)
 
-	if cidr == "" {
-		cidr = defaultCIDR
+	if cfg.CIDR == "" {
+		cfg.CIDR = defaultCIDR
 	}
 
 	// Get IP range by network CIDR
-	ip, ipnet, err := net.ParseCIDR(cidr)
+	ipam.v4, err = newPool(types.IPAMPoolService, cfg.CIDR, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
-		if skip {
-			// remove network address and broadcast address
-			skip = false
-			continue
+	if ipam.v4.ipv6() {
+		return nil, fmt.Errorf("services cidr %s is not IPv4 network", cfg.CIDR)
+	}
+
+	if cfg.CIDRv6 != "" {
+		ipam.v6, err = newPool(types.IPAMPoolServiceIPv6, cfg.CIDRv6, 0)
+		if err != nil {
+			return nil, err
 		}
 
-		ipam.released[ip.String()] = true
-		ipam.available++
+		if !ipam.v6.ipv6() {
+			return nil, fmt.Errorf("services cidr %s is not IPv6 network", cfg.CIDRv6)
+		}
 	}
 
-	ips := make([]string, 0)
+	if cfg.Subnets != "" {
 
-	// Get IP list from database storage
-	err = stg.Get(context.Background(), stg.Collection().System(), "ipam", &ips, nil)
-	if err != nil {
-		if !errors.Storage().IsErrEntityNotFound(err) {
-			log.Errorf("%s get context error: %s", logIPAMPrefix, err.Error())
+		if cfg.SubnetPrefix == 0 {
+			cfg.SubnetPrefix = defaultSubnetPrefix
+			if ip, _, err := net.ParseCIDR(cfg.Subnets); err == nil && ip.To4() == nil {
+				cfg.SubnetPrefix = defaultSubnetPrefixV6
+			}
+		}
+
+		ipam.subnet, err = newPool(types.IPAMPoolSubnet, cfg.Subnets, cfg.SubnetPrefix)
+		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Mark IPs as leased
-	for _, item := range ips {
-		if _, ok := ipam.released[item]; ok {
-			delete(ipam.released, item)
-			ipam.available--
-			ipam.leased[item] = true
-			ipam.reserved++
+	if cfg.NodePorts != "" {
+		ipam.ports, err = newPortPool(types.IPAMPoolNodePort, cfg.NodePorts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ipam.register(); err != nil {
+		log.Errorf("%s register pools error: %s", logIPAMPrefix, err.Error())
+		return nil, err
+	}
+
+	for _, p := range ipam.pools() {
+		if err := ipam.load(p); err != nil {
+			log.Errorf("%s get leases error: %s", logIPAMPrefix, err.Error())
+			return nil, err
 		}
 	}
 
+	if ipam.ports != nil {
+		if err := ipam.loadPorts(); err != nil {
+			log.Errorf("%s get leases error: %s", logIPAMPrefix, err.Error())
+			return nil, err
+		}
+	}
+
+	if err := ipam.migrate(stg); err != nil {
+		log.Errorf("%s import leases error: %s", logIPAMPrefix, err.Error())
+		return nil, err
+	}
+
 	return ipam, nil
 }
@@ -0,0 +1,403 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package local
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	v := viper.New()
+	v.SetDefault("storage.driver", "mock")
+
+	stg, _ := storage.Get(v)
+	envs.Get().SetStorage(stg)
+}
+
+// cleanup removes stored leases of all pools
+func cleanup(t *testing.T) {
+	im := distribution.NewIPAMModel(context.Background(), envs.Get().GetStorage())
+	for _, name := range []string{types.IPAMPoolService, types.IPAMPoolServiceIPv6, types.IPAMPoolSubnet, types.IPAMPoolNodePort} {
+		leases, err := im.LeaseMap(name)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, l := range leases.Items {
+			assert.NoError(t, im.LeaseDel(l))
+		}
+	}
+}
+
+func TestIPAMLease(t *testing.T) {
+
+	defer cleanup(t)
+
+	ipm, err := New(Config{CIDR: "10.0.0.0/30"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, ipm.DualStack(), "dual stack should be disabled without ipv6 range")
+	assert.False(t, ipm.Subnets(), "subnets should be disabled without cluster range")
+
+	u, err := ipm.Utilization()
+	if assert.NoError(t, err) && assert.Contains(t, u.Pools, types.IPAMPoolService) {
+		assert.Equal(t, 3, u.Pools[types.IPAMPoolService].Available, "available count different")
+		assert.NotContains(t, u.Pools, types.IPAMPoolServiceIPv6, "disabled pool should not be registered")
+	}
+
+	leases := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		ip, err := ipm.Lease("ns:svc")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NotNil(t, ip.To4(), "lease should be ipv4 address")
+		assert.NotEqual(t, "10.0.0.0", ip.String(), "network address should not be leased")
+		leases[ip.String()] = true
+	}
+
+	assert.Len(t, leases, 3, "leases should be unique")
+
+	u, err = ipm.Utilization()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, u.Pools[types.IPAMPoolService].Available, "available count different")
+		assert.Equal(t, 3, u.Pools[types.IPAMPoolService].Leased, "leased count different")
+	}
+
+	_, err = ipm.Lease("ns:svc")
+	assert.EqualError(t, err, IPAMLeaseNotAvailable)
+
+	_, err = ipm.LeaseIPv6("ns:svc")
+	assert.EqualError(t, err, IPAMIPv6NotEnabled)
+
+	ip := net.ParseIP("10.0.0.2")
+	assert.NoError(t, ipm.Release(&ip, "ns:svc"))
+
+	lease, err := ipm.Lease("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "10.0.0.2", lease.String(), "released ip should be leased again")
+	}
+}
+
+func TestIPAMConflict(t *testing.T) {
+
+	defer cleanup(t)
+
+	// controllers share storage, but not local state
+	first, err := New(Config{CIDR: "10.0.2.0/29"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := New(Config{CIDR: "10.0.2.0/29"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	leases := make(map[string]bool)
+	for i := 0; i < 7; i++ {
+
+		ipm := first
+		if i%2 == 1 {
+			ipm = second
+		}
+
+		ip, err := ipm.Lease("ns:svc")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.False(t, leases[ip.String()], "ip %s leased twice", ip.String())
+		leases[ip.String()] = true
+	}
+
+	_, err = second.Lease("ns:svc")
+	assert.EqualError(t, err, IPAMLeaseNotAvailable)
+
+	// address released by another controller is found after state refresh
+	ip := net.ParseIP("10.0.2.5")
+	assert.NoError(t, first.Release(&ip, "ns:svc"))
+
+	lease, err := second.Lease("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "10.0.2.5", lease.String(), "released ip should be leased again")
+	}
+
+	assert.NoError(t, first.Reserve(net.ParseIP("10.0.2.5"), "ns:svc"), "owner should be able to reserve own ip")
+	assert.EqualError(t, first.Reserve(net.ParseIP("10.0.2.5"), "ns:other"), IPAMLeaseConflict)
+	assert.NoError(t, first.Reserve(net.ParseIP("10.10.0.1"), "ns:other"), "ip out of range should not be tracked")
+}
+
+func TestIPAMDualStack(t *testing.T) {
+
+	defer cleanup(t)
+
+	ipm, err := New(Config{CIDR: "10.0.1.0/24", CIDRv6: "fd00:ff::/64"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, ipm.DualStack(), "dual stack should be enabled with ipv6 range")
+
+	ip, err := ipm.Lease("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "10.0.1.1", ip.String())
+	}
+
+	ip6, err := ipm.LeaseIPv6("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "fd00:ff::1", ip6.String())
+	}
+
+	// leases are restored from storage
+	restored, err := New(Config{CIDR: "10.0.1.0/24", CIDRv6: "fd00:ff::/64"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ip6, err = restored.LeaseIPv6("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "fd00:ff::2", ip6.String(), "restored lease should not be leased again")
+	}
+
+	assert.NoError(t, restored.Release(ip6, "ns:svc"))
+
+	u, err := restored.Utilization()
+	if assert.NoError(t, err) && assert.Contains(t, u.Pools, types.IPAMPoolServiceIPv6) {
+		assert.Equal(t, 1, u.Pools[types.IPAMPoolServiceIPv6].Leased, "leased count different")
+	}
+
+	_, err = New(Config{CIDR: "10.0.1.0/24", CIDRv6: "10.0.2.0/24"})
+	assert.Error(t, err, "ipv4 range should not be accepted as ipv6 range")
+
+	_, err = New(Config{CIDR: "fd00:ff::/64"})
+	assert.Error(t, err, "ipv6 range should not be accepted as ipv4 range")
+}
+
+func TestIPAMSubnets(t *testing.T) {
+
+	defer cleanup(t)
+
+	ipm, err := New(Config{Subnets: "10.100.0.0/22", SubnetPrefix: 24})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, ipm.Subnets(), "subnets should be enabled with cluster range")
+
+	_, reported, _ := net.ParseCIDR("10.100.2.0/24")
+	assert.NoError(t, ipm.ReserveSubnet(reported, "node-2"))
+	assert.EqualError(t, ipm.ReserveSubnet(reported, "node-3"), IPAMLeaseConflict)
+
+	_, outside, _ := net.ParseCIDR("10.200.0.0/24")
+	assert.EqualError(t, ipm.ReserveSubnet(outside, "node-3"), IPAMSubnetOutOfRange)
+
+	_, wide, _ := net.ParseCIDR("10.100.0.0/23")
+	assert.EqualError(t, ipm.ReserveSubnet(wide, "node-3"), IPAMSubnetOutOfRange)
+
+	subnets := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		ipn, err := ipm.LeaseSubnet("node-1")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NotEqual(t, reported.String(), ipn.String(), "reserved subnet should not be leased")
+		subnets[ipn.String()] = true
+	}
+
+	assert.Len(t, subnets, 3, "subnets should be unique")
+	assert.Contains(t, subnets, "10.100.0.0/24", "first subnet of range should be leased")
+
+	_, err = ipm.LeaseSubnet("node-1")
+	assert.EqualError(t, err, IPAMLeaseNotAvailable)
+
+	assert.EqualError(t, ipm.ReleaseSubnet(reported, "node-3"), IPAMLeaseConflict)
+	assert.NoError(t, ipm.ReleaseSubnet(reported, "node-2"))
+
+	ipn, err := ipm.LeaseSubnet("node-3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, reported.String(), ipn.String(), "released subnet should be leased again")
+	}
+}
+
+func TestIPAMCollect(t *testing.T) {
+
+	defer cleanup(t)
+
+	ipm, err := New(Config{CIDR: "10.0.3.0/24"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	im := distribution.NewIPAMModel(context.Background(), envs.Get().GetStorage())
+
+	for addr, owner := range map[string]string{"10.0.3.1": "ns:used", "10.0.3.2": "ns:removed", "10.0.3.3": "ns:used"} {
+		l := &types.IPAMLease{Pool: types.IPAMPoolService, Addr: addr, Owner: owner, Created: time.Now().Add(-time.Hour)}
+		if !assert.NoError(t, im.LeasePut(l)) {
+			return
+		}
+	}
+
+	// lease of endpoint which is not stored yet
+	ip, err := ipm.Lease("ns:new")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	count, err := ipm.Collect(map[string][]string{"ns:used": {"10.0.3.1"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 2, count, "collected leases count different")
+
+	leases, err := im.LeaseMap(types.IPAMPoolService)
+	if assert.NoError(t, err) {
+		assert.Len(t, leases.Items, 2, "leases count different")
+		assert.Contains(t, leases.Items, "10.0.3.1", "used lease should be kept")
+		assert.Contains(t, leases.Items, ip.String(), "recent lease should be kept")
+		assert.NotContains(t, leases.Items, "10.0.3.3", "lease of address not used by owner should be collected")
+	}
+
+	assert.NoError(t, ipm.Reserve(net.ParseIP("10.0.3.2"), "ns:svc"), "collected ip should be available")
+}
+
+func TestIPAMReleaseStale(t *testing.T) {
+
+	defer cleanup(t)
+
+	first, err := New(Config{CIDR: "10.0.5.0/30"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := New(Config{CIDR: "10.0.5.0/30"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ip, err := first.Lease("ns:old")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, first.Release(ip, "ns:old"))
+	assert.NoError(t, second.Reserve(*ip, "ns:new"))
+
+	// stale release of address leased again by another owner should keep lease
+	assert.EqualError(t, first.Release(ip, "ns:old"), IPAMLeaseConflict)
+
+	im := distribution.NewIPAMModel(context.Background(), envs.Get().GetStorage())
+
+	l, err := im.LeaseGet(types.IPAMPoolService, ip.String())
+	if assert.NoError(t, err) && assert.NotNil(t, l, "lease should be kept") {
+		assert.Equal(t, "ns:new", l.Owner, "lease owner different")
+	}
+
+	// lease read before it was changed should not be removed
+	stale := *l
+	stg := envs.Get().GetStorage()
+	l.Created = time.Now().Add(-time.Hour)
+	if !assert.NoError(t, stg.Set(context.Background(), stg.Collection().IPAM().Lease(l.Pool), types.IPAMLeaseKey(l.Addr), l, nil)) {
+		return
+	}
+
+	assert.True(t, errors.Storage().IsErrEntityConflict(im.LeaseDel(&stale)), "stale lease should not be removed")
+
+	l, err = im.LeaseGet(types.IPAMPoolService, ip.String())
+	if assert.NoError(t, err) {
+		assert.NotNil(t, l, "lease should be kept")
+	}
+}
+
+func TestIPAMNodePorts(t *testing.T) {
+
+	defer cleanup(t)
+
+	disabled, err := New(Config{CIDR: "10.0.4.0/24"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, disabled.NodePorts(), "node ports should be disabled without range")
+	_, err = disabled.LeasePort("ns:svc")
+	assert.EqualError(t, err, IPAMNodePortsNotEnabled)
+
+	_, err = New(Config{CIDR: "10.0.4.0/24", NodePorts: "30010-30000"})
+	assert.Error(t, err, "reversed ports range should not be accepted")
+
+	ipm, err := New(Config{CIDR: "10.0.4.0/24", NodePorts: "30000-30002"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, ipm.NodePorts(), "node ports should be enabled with range")
+
+	assert.NoError(t, ipm.ReservePort(30001, "ns:fixed"))
+	assert.NoError(t, ipm.ReservePort(30001, "ns:fixed"), "port reserve by the same owner should succeed")
+	assert.EqualError(t, ipm.ReservePort(30001, "ns:other"), IPAMLeaseConflict)
+	assert.EqualError(t, ipm.ReservePort(31000, "ns:other"), IPAMNodePortOutOfRange)
+
+	ports := make(map[uint16]bool)
+	for i := 0; i < 2; i++ {
+		port, err := ipm.LeasePort("ns:svc")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NotEqual(t, uint16(30001), port, "reserved port should not be leased")
+		ports[port] = true
+	}
+
+	assert.Len(t, ports, 2, "ports should be unique")
+
+	// another controller should see ports leased by first one
+	second, err := New(Config{CIDR: "10.0.4.0/24", NodePorts: "30000-30002"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = second.LeasePort("ns:svc")
+	assert.EqualError(t, err, IPAMLeaseNotAvailable)
+
+	assert.EqualError(t, ipm.ReleasePort(30001, "ns:svc"), IPAMLeaseConflict)
+	assert.NoError(t, ipm.ReleasePort(30001, "ns:fixed"))
+
+	port, err := second.LeasePort("ns:svc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint16(30001), port, "released port should be leased again")
+	}
+
+	u, err := ipm.Utilization()
+	if assert.NoError(t, err) && assert.Contains(t, u.Pools, types.IPAMPoolNodePort) {
+		assert.Equal(t, "30000-30002", u.Pools[types.IPAMPoolNodePort].CIDR)
+		assert.Equal(t, 3, u.Pools[types.IPAMPoolNodePort].Leased)
+	}
+}
@@ -0,0 +1,205 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package local
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// maxPoolSize limits count of networks tracked in large (IPv6) ranges
+const maxPoolSize = 1 << 24
+
+// pool allocates networks of fixed prefix length from range without enumerating it,
+// single addresses are networks with full prefix length.
+// Candidates are searched from cursor of last allocated network
+type pool struct {
+	name    string
+	network *net.IPNet
+	prefix  int
+	first   int
+	size    int
+	next    int
+	leased  map[string]string
+}
+
+func newPool(name, cidr string, prefix int) (*pool, error) {
+
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if prefix == 0 {
+		prefix = bits
+	}
+
+	if prefix < ones || prefix > bits {
+		return nil, fmt.Errorf("prefix /%d is out of network %s", prefix, cidr)
+	}
+
+	p := new(pool)
+	p.name = name
+	p.network = ipnet
+	p.prefix = prefix
+	p.leased = make(map[string]string, 0)
+
+	count := maxPoolSize
+	if prefix-ones < 24 {
+		count = 1 << uint(prefix-ones)
+	}
+
+	// network address is not leased as single address
+	if prefix == bits {
+		p.first = 1
+	}
+
+	p.size = count - p.first
+	p.next = p.first
+
+	return p, nil
+}
+
+func (p *pool) ipv6() bool {
+	return p.network.IP.To4() == nil
+}
+
+// single checks if pool leases single addresses instead of networks
+func (p *pool) single() bool {
+	_, bits := p.network.Mask.Size()
+	return p.prefix == bits
+}
+
+func (p *pool) contains(ip net.IP) bool {
+	if (ip.To4() == nil) != p.ipv6() {
+		return false
+	}
+	return p.network.Contains(ip)
+}
+
+// addr returns network by index in pool
+func (p *pool) addr(idx int) *net.IPNet {
+
+	_, bits := p.network.Mask.Size()
+
+	n := new(big.Int).SetBytes(p.network.IP)
+	n.Add(n, new(big.Int).Lsh(big.NewInt(int64(idx)), uint(bits-p.prefix)))
+
+	b := n.Bytes()
+	ip := make(net.IP, len(p.network.IP))
+	copy(ip[len(ip)-len(b):], b)
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(p.prefix, bits)}
+}
+
+// index returns index of network in pool, ip should be network address
+func (p *pool) index(ip net.IP) (int, bool) {
+
+	if !p.contains(ip) {
+		return 0, false
+	}
+
+	if !p.ipv6() {
+		ip = ip.To4()
+	}
+
+	_, bits := p.network.Mask.Size()
+
+	off := new(big.Int).SetBytes(ip)
+	off.Sub(off, new(big.Int).SetBytes(p.network.IP))
+
+	rest := new(big.Int).Mod(off, new(big.Int).Lsh(big.NewInt(1), uint(bits-p.prefix)))
+	if rest.Sign() != 0 {
+		return 0, false
+	}
+
+	off.Rsh(off, uint(bits-p.prefix))
+	if !off.IsInt64() {
+		return 0, false
+	}
+
+	idx := int(off.Int64())
+	if idx < p.first || idx >= p.first+p.size {
+		return 0, false
+	}
+
+	return idx, true
+}
+
+// format returns lease address, single addresses are stored without prefix
+func (p *pool) format(ipn *net.IPNet) string {
+	if p.single() {
+		return ipn.IP.String()
+	}
+	return ipn.String()
+}
+
+// candidate returns next network which is not leased and moves cursor after it
+func (p *pool) candidate() *net.IPNet {
+
+	if len(p.leased) >= p.size {
+		return nil
+	}
+
+	for n := 0; n < p.size; n++ {
+
+		idx := p.next
+
+		p.next++
+		if p.next >= p.first+p.size {
+			p.next = p.first
+		}
+
+		ipn := p.addr(idx)
+		if _, ok := p.leased[ipn.IP.String()]; ok {
+			continue
+		}
+
+		return ipn
+	}
+
+	return nil
+}
+
+func (p *pool) mark(ip net.IP, owner string) bool {
+	idx, ok := p.index(ip)
+	if !ok {
+		return false
+	}
+	p.leased[p.addr(idx).IP.String()] = owner
+	return true
+}
+
+func (p *pool) release(ip net.IP) {
+	if idx, ok := p.index(ip); ok {
+		delete(p.leased, p.addr(idx).IP.String())
+	}
+}
+
+// load replaces leased networks with stored leases
+func (p *pool) load(leases *types.IPAMLeaseMap) {
+	p.leased = make(map[string]string, len(leases.Items))
+	for key, l := range leases.Items {
+		p.mark(net.ParseIP(key), l.Owner)
+	}
+}
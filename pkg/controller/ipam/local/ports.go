@@ -0,0 +1,123 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package local
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// portPool allocates ports from range, candidates are searched from cursor of last allocated port
+type portPool struct {
+	name   string
+	first  int
+	size   int
+	next   int
+	leased map[int]string
+}
+
+// newPortPool returns pool of ports range declared as "first-last"
+func newPortPool(name, rng string) (*portPool, error) {
+
+	parts := strings.Split(rng, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid ports range %s", rng)
+	}
+
+	first, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ports range %s", rng)
+	}
+
+	last, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ports range %s", rng)
+	}
+
+	if first == 0 || last < first {
+		return nil, fmt.Errorf("invalid ports range %s", rng)
+	}
+
+	p := new(portPool)
+	p.name = name
+	p.first = int(first)
+	p.size = int(last-first) + 1
+	p.next = p.first
+	p.leased = make(map[int]string, 0)
+
+	return p, nil
+}
+
+func (p *portPool) contains(port int) bool {
+	return port >= p.first && port < p.first+p.size
+}
+
+// format returns ports range as it is declared
+func (p *portPool) format() string {
+	return fmt.Sprintf("%d-%d", p.first, p.first+p.size-1)
+}
+
+// candidate returns next port which is not leased and moves cursor after it
+func (p *portPool) candidate() int {
+
+	if len(p.leased) >= p.size {
+		return 0
+	}
+
+	for n := 0; n < p.size; n++ {
+
+		port := p.next
+
+		p.next++
+		if p.next >= p.first+p.size {
+			p.next = p.first
+		}
+
+		if _, ok := p.leased[port]; ok {
+			continue
+		}
+
+		return port
+	}
+
+	return 0
+}
+
+func (p *portPool) mark(port int, owner string) {
+	if p.contains(port) {
+		p.leased[port] = owner
+	}
+}
+
+func (p *portPool) release(port int) {
+	delete(p.leased, port)
+}
+
+// load replaces leased ports with stored leases
+func (p *portPool) load(leases *types.IPAMLeaseMap) {
+	p.leased = make(map[int]string, len(leases.Items))
+	for key, l := range leases.Items {
+		if port, err := strconv.Atoi(key); err == nil {
+			p.mark(port, l.Owner)
+		}
+	}
+}
@@ -20,9 +20,12 @@ package cluster
 
 import (
 	"context"
+	"net"
+
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
 )
 
 type NodeLease struct {
@@ -57,6 +60,11 @@ func handleNodeLease(cs *ClusterState, nl *NodeLease) error {
 
 	for _, n := range cs.node.list {
 
+		// skip nodes under disk pressure
+		if n.Status.Pressure.Disk {
+			continue
+		}
+
 		// check selectors first
 
 		if nl.Request.Selector.Node != types.EmptyString {
@@ -161,3 +169,88 @@ func handleNodeRelease(cs *ClusterState, nl *NodeLease) error {
 	nm := distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
 	return nm.Set(n)
 }
+
+// nodeSubnetProvision leases pod subnet for node from cluster CIDR.
+// Subnet configured on node is reserved if it is in cluster CIDR and is not used by another node,
+// otherwise previously allocated subnet is kept. Node does not create pods until allocated subnet is configured
+func nodeSubnetProvision(cs *ClusterState, n *types.Node) error {
+
+	ipm := cs.IPAM()
+	if ipm == nil || !ipm.Subnets() {
+		return nil
+	}
+
+	owner := n.SelfLink().String()
+	if cidr, ok := cs.node.subnets[owner]; ok && cidr == n.Spec.Network.CIDR &&
+		(n.Meta.CIDR == types.EmptyString || n.Meta.CIDR == cidr) {
+		return nil
+	}
+
+	for _, cidr := range []string{n.Meta.CIDR, n.Spec.Network.CIDR} {
+
+		if cidr == types.EmptyString {
+			continue
+		}
+
+		_, ipn, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+
+		if err := ipm.ReserveSubnet(ipn, owner); err != nil {
+			log.V(logLevel).Debugf("%s:subnet:> reserve subnet %s for node %s err: %s", logPrefix, cidr, owner, err.Error())
+			continue
+		}
+
+		return nodeSubnetSet(cs, n, ipn)
+	}
+
+	ipn, err := ipm.LeaseSubnet(owner)
+	if err != nil {
+		log.Errorf("%s:subnet:> lease subnet for node %s err: %s", logPrefix, owner, err.Error())
+		return err
+	}
+
+	return nodeSubnetSet(cs, n, ipn)
+}
+
+func nodeSubnetSet(cs *ClusterState, n *types.Node, ipn *net.IPNet) error {
+
+	cs.node.subnets[n.SelfLink().String()] = ipn.String()
+
+	if n.Spec.Network.CIDR == ipn.String() {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:subnet:> allocate subnet %s for node %s", logPrefix, ipn.String(), n.SelfLink().String())
+
+	// previous subnet is not used after node is configured with another one
+	if _, prev, err := net.ParseCIDR(n.Spec.Network.CIDR); err == nil {
+		if err := cs.IPAM().ReleaseSubnet(prev, n.SelfLink().String()); err != nil {
+			log.Errorf("%s:subnet:> release subnet %s of node %s err: %s", logPrefix, prev.String(), n.SelfLink().String(), err.Error())
+		}
+	}
+
+	n.Spec.Network.CIDR = ipn.String()
+
+	nm := distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
+	return nm.Set(n)
+}
+
+// nodeSubnetRelease releases pod subnet of removed node
+func nodeSubnetRelease(cs *ClusterState, n *types.Node) error {
+
+	ipm := cs.IPAM()
+	if ipm == nil || !ipm.Subnets() || n.Spec.Network.CIDR == types.EmptyString {
+		return nil
+	}
+
+	delete(cs.node.subnets, n.SelfLink().String())
+
+	_, ipn, err := net.ParseCIDR(n.Spec.Network.CIDR)
+	if err != nil {
+		return nil
+	}
+
+	return ipm.ReleaseSubnet(ipn, n.SelfLink().String())
+}
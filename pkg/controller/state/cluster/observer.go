@@ -62,6 +62,7 @@ type ClusterState struct {
 		lease    chan *NodeLease
 		release  chan *NodeLease
 		list     map[string]*types.Node
+		subnets  map[string]string
 	}
 }
 
@@ -79,6 +80,9 @@ func (cs *ClusterState) Observe() {
 		case n := <-cs.node.observer:
 			log.V(7).Debugf("node: %s", n.Meta.Name)
 			cs.node.list[n.SelfLink().String()] = n
+			if err := nodeSubnetProvision(cs, n); err != nil {
+				log.Errorf("%s", err.Error())
+			}
 			_ = clusterStatusState(cs)
 			break
 		case v := <-cs.volume.observer:
@@ -116,7 +120,7 @@ func (cs *ClusterState) Loop() error {
 
 	// Get all nodes in cluster
 	nm := distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
-	nl, err := nm.List()
+	nl, err := nm.List(nil)
 	if err != nil {
 		return err
 	}
@@ -130,7 +134,7 @@ func (cs *ClusterState) Loop() error {
 
 	// Get all ingress servers in cluster
 	im := distribution.NewIngressModel(context.Background(), envs.Get().GetStorage())
-	il, err := im.List()
+	il, err := im.List(nil)
 	if err != nil {
 		return err
 	}
@@ -142,7 +146,7 @@ func (cs *ClusterState) Loop() error {
 
 	// Get all routes in cluster
 	rm := distribution.NewRouteModel(context.Background(), envs.Get().GetStorage())
-	rl, err := rm.List()
+	rl, err := rm.List(nil)
 	if err != nil {
 		return err
 	}
@@ -319,6 +323,9 @@ func (cs *ClusterState) SetNode(n *types.Node) {
 
 func (cs *ClusterState) DelNode(n *types.Node) {
 	delete(cs.node.list, n.SelfLink().String())
+	if err := nodeSubnetRelease(cs, n); err != nil {
+		log.Errorf("%s", err.Error())
+	}
 }
 
 func (cs *ClusterState) SetIngress(i *types.Ingress) {
@@ -447,6 +454,7 @@ func NewClusterState() *ClusterState {
 
 	cs.node.observer = make(chan *types.Node)
 	cs.node.list = make(map[string]*types.Node)
+	cs.node.subnets = make(map[string]string)
 
 	cs.node.lease = make(chan *NodeLease)
 	cs.node.release = make(chan *NodeLease)
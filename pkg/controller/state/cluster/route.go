@@ -137,7 +137,7 @@ func routeUpdate(v *types.Route, timestamp time.Time) error {
 
 	if timestamp.Before(v.Meta.Updated) {
 		vm := distribution.NewRouteModel(context.Background(), envs.Get().GetStorage())
-		if _, err := vm.Set(v); err != nil {
+		if _, err := vm.Set(v, nil); err != nil {
 			log.Errorf("%s", err.Error())
 			return err
 		}
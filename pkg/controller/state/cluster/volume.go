@@ -149,7 +149,7 @@ func volumeUpdate(v *types.Volume, timestamp time.Time) error {
 
 	if timestamp.Before(v.Meta.Updated) {
 		vm := distribution.NewVolumeModel(context.Background(), envs.Get().GetStorage())
-		if err := vm.Update(v); err != nil {
+		if err := vm.Update(v, nil); err != nil {
 			log.Errorf("%s", err.Error())
 			return err
 		}
@@ -305,6 +305,21 @@ func volumeRemove(cs *ClusterState, volume *types.Volume) (err error) {
 	volume.Meta.Node = types.EmptyString
 	volume.Meta.Updated = time.Now()
 
+	nm := distribution.NewNamespaceModel(context.Background(), envs.Get().GetStorage())
+	ns, e := nm.Get(volume.Meta.Namespace)
+	if e != nil {
+		log.Errorf("%s:> namespace fetch err: %s", logPrefixVolume, e.Error())
+	}
+
+	if ns != nil {
+		allocated := ns.Status.Resources.Allocated
+		ns.ReleaseStorage(volume.Spec.Capacity.Storage)
+
+		if e := nm.Allocate(ns, ns.Status.Resources.Allocated.Diff(allocated)); e != nil {
+			log.Errorf("%s:> namespace update err: %s", logPrefixVolume, e.Error())
+		}
+	}
+
 	if err = vm.Remove(volume); err != nil {
 		log.Errorf("%s", err.Error())
 		return err
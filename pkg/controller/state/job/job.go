@@ -120,7 +120,7 @@ func handleJobStateDestroy(js *JobState, job *types.Job) (err error) {
 		job.Status.State = types.StateDestroyed
 		job.Meta.Updated = time.Now()
 
-		if err := jm.Set(job); err != nil {
+		if err := jm.Set(job, nil); err != nil {
 			return err
 		}
 
@@ -182,9 +182,10 @@ func handleJobStateDestroyed(js *JobState, job *types.Job) (err error) {
 		log.Errorf("%s:> namespace fetch err: %s", logJobPrefix, err.Error())
 	}
 	if ns != nil {
+		allocated := ns.Status.Resources.Allocated
 		ns.ReleaseResources(job.Spec.GetResourceRequest())
 
-		if err := nm.Update(ns); err != nil {
+		if err := nm.Allocate(ns, ns.Status.Resources.Allocated.Diff(allocated)); err != nil {
 			log.Errorf("%s:> namespace update err: %s", logJobPrefix, err.Error())
 		}
 	}
@@ -211,7 +212,7 @@ func jobTaskProvision(js *JobState) error {
 		log.Debugf("%s:jobTaskProvision:> there are no jobs in queue: %d", logJobPrefix, len(js.task.queue))
 		if js.job.Status.State != types.StateWaiting {
 			js.job.Status.State = types.StateWaiting
-			if err := jm.Set(js.job); err != nil {
+			if err := jm.Set(js.job, nil); err != nil {
 				log.Errorf("%s:jobTaskProvision:> set job to waiting state err: %s", logJobPrefix, err.Error())
 				return err
 			}
@@ -251,10 +252,42 @@ func jobTaskProvision(js *JobState) error {
 
 	if js.job.Status.State != types.StateRunning {
 		js.job.Status.State = types.StateRunning
-		if err := jm.Set(js.job); err != nil {
+		if err := jm.Set(js.job, nil); err != nil {
 			log.Errorf("%s:jobTaskProvision:> set job to running state err: %s", logJobPrefix, err.Error())
 		}
 	}
 
 	return nil
 }
+
+// jobResourcesUpdate recalculates job allocated resources by queued and active tasks
+func jobResourcesUpdate(js *JobState) error {
+
+	if js.job == nil {
+		return nil
+	}
+
+	var allocated types.ResourceItem
+
+	for _, tasks := range []map[string]*types.Task{js.task.queue, js.task.active} {
+		for _, t := range tasks {
+			rr := t.Spec.GetResourceRequest()
+			allocated.RAM += rr.Limits.RAM
+			allocated.CPU += rr.Limits.CPU
+		}
+	}
+
+	if js.job.Status.Resources.Allocated == allocated {
+		return nil
+	}
+
+	js.job.Status.Resources.Allocated = allocated
+
+	jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+	if err := jm.Set(js.job, nil); err != nil {
+		log.Errorf("%s:jobResourcesUpdate:> set job allocated resources err: %s", logJobPrefix, err.Error())
+		return err
+	}
+
+	return nil
+}
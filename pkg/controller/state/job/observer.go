@@ -92,7 +92,7 @@ func (js *JobState) Restore() error {
 
 	// Get all pods
 	pm := distribution.NewPodModel(context.Background(), stg)
-	pl, err := pm.ListByJob(js.job.Meta.Namespace, js.job.Meta.Name)
+	pl, err := pm.ListByJob(js.job.Meta.Namespace, js.job.Meta.Name, nil)
 	if err != nil {
 		log.Errorf("%s:restore:> get pod map error: %v", logPrefix, err)
 		return err
@@ -111,7 +111,7 @@ func (js *JobState) Restore() error {
 
 	// Get all tasks
 	tm := distribution.NewTaskModel(context.Background(), stg)
-	tl, err := tm.ListByJob(js.job.Meta.Namespace, js.job.Meta.Name)
+	tl, err := tm.ListByJob(js.job.Meta.Namespace, js.job.Meta.Name, nil)
 	if err != nil {
 		log.Errorf("%s:restore:> get task map error: %v", logPrefix, err)
 		return err
@@ -161,6 +161,9 @@ func podCreate(t *types.Task) (*types.Pod, error) {
 	pod.Meta.SetDefault()
 	pod.Meta.Name = strings.Split(generator.GetUUIDV4(), "-")[4][5:]
 	pod.Meta.Namespace = t.Meta.Namespace
+	for k, v := range t.Meta.Labels {
+		pod.Meta.Labels[k] = v
+	}
 	sl, _ := types.NewPodSelfLink(types.KindTask, t.SelfLink().String(), pod.Meta.Name)
 	pod.Meta.SelfLink = *sl
 	pod.Status.SetCreated()
@@ -220,7 +220,7 @@ func taskCheckSelectors(_ *JobState, task *types.Task) (err error) {
 
 		var node string
 
-		vl, err := vm.ListByNamespace(task.Meta.Namespace)
+		vl, err := vm.ListByNamespace(task.Meta.Namespace, nil)
 		if err != nil {
 			log.V(logLevel).Errorf("%s:check_selectors:> create task, volume list err: %s", logPrefix, err.Error())
 			return err
@@ -291,6 +291,9 @@ func taskCreate(job *types.Job, mf *types.TaskManifest) (*types.Task, error) {
 	task.Meta.SetDefault()
 	task.Meta.Namespace = job.Meta.Namespace
 	task.Meta.Job = job.SelfLink().String()
+	for k, v := range job.Meta.Labels {
+		task.Meta.Labels[k] = v
+	}
 
 	if mf != nil {
 		mf.SetTaskMeta(task)
@@ -350,6 +353,10 @@ func taskQueue(js *JobState, task *types.Task) error {
 
 	js.task.queue[task.SelfLink().String()] = task
 
+	if err := jobResourcesUpdate(js); err != nil {
+		return err
+	}
+
 	if err := jobTaskProvision(js); err != nil {
 		log.Errorf("%s:taskQueue:> job task queue pop err: %s", logTaskPrefix, err.Error())
 		return err
@@ -536,6 +543,10 @@ func taskFinish(js *JobState, task *types.Task) (err error) {
 	delete(js.task.queue, task.SelfLink().String())
 	delete(js.task.active, task.SelfLink().String())
 
+	if err := jobResourcesUpdate(js); err != nil {
+		return err
+	}
+
 	for {
 		if len(js.task.finished) > 5 {
 			var t *types.Task
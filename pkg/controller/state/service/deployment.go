@@ -122,7 +122,7 @@ func handleDeploymentStateCreated(ss *ServiceState, d *types.Deployment) error {
 	if !check {
 		d.Status.State = types.StateWaiting
 		dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
-		if err := dm.Update(d); err != nil {
+		if err := dm.Update(d, nil); err != nil {
 			log.Errorf("%s:> handle deployment create, deps update: %s, err: %s", logDeploymentPrefix, d.SelfLink(), err.Error())
 			return err
 		}
@@ -133,7 +133,7 @@ func handleDeploymentStateCreated(ss *ServiceState, d *types.Deployment) error {
 		d.Status.State = types.StateError
 		d.Status.Message = err.Error()
 		dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
-		if err := dm.Update(d); err != nil {
+		if err := dm.Update(d, nil); err != nil {
 			log.Errorf("%s:> handle deployment create, deps update: %s, err: %s", logDeploymentPrefix, d.SelfLink(), err.Error())
 			return err
 		}
@@ -289,7 +289,7 @@ func handleDeploymentStateDestroyed(ss *ServiceState, d *types.Deployment) error
 
 		d.Status.State = types.StateDestroy
 		dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
-		return dm.Update(d)
+		return dm.Update(d, nil)
 	}
 
 	if err := deploymentRemove(d); err != nil {
@@ -350,7 +350,7 @@ func deploymentCheckDependencies(ss *ServiceState, d *types.Deployment) (bool, e
 
 	if len(volumesRequiredList) != 0 {
 
-		vl, err := vm.ListByNamespace(d.Meta.Namespace)
+		vl, err := vm.ListByNamespace(d.Meta.Namespace, nil)
 		if err != nil {
 			log.Errorf("%s:> service check deps err: %s", logServicePrefix, err.Error())
 			return false, err
@@ -382,7 +382,7 @@ func deploymentCheckDependencies(ss *ServiceState, d *types.Deployment) (bool, e
 
 	if len(secretsRequiredList) != 0 {
 
-		sl, err := sm.List(d.Meta.Namespace)
+		sl, err := sm.List(d.Meta.Namespace, nil)
 		if err != nil {
 			log.Errorf("%s:> service check deps err: %s", logServicePrefix, err.Error())
 			return false, err
@@ -414,7 +414,7 @@ func deploymentCheckDependencies(ss *ServiceState, d *types.Deployment) (bool, e
 
 	if len(configsRequiredList) != 0 {
 
-		cl, err := cm.List(d.Meta.Namespace)
+		cl, err := cm.List(d.Meta.Namespace, nil)
 		if err != nil {
 			log.Errorf("%s:> service check deps err: %s", logServicePrefix, err.Error())
 			return false, err
@@ -473,7 +473,7 @@ func deploymentCheckSelectors(ss *ServiceState, d *types.Deployment) (err error)
 
 		var node string
 
-		vl, err := vm.ListByNamespace(d.Meta.Namespace)
+		vl, err := vm.ListByNamespace(d.Meta.Namespace, nil)
 		if err != nil {
 			log.V(logLevel).Errorf("%s:create:> create deployment, volume list err: %s", logPrefix, err.Error())
 			return err
@@ -670,7 +670,7 @@ func deploymentCreate(svc *types.Service, version int) (*types.Deployment, error
 func deploymentUpdate(d *types.Deployment, timestamp time.Time) error {
 	if timestamp.Before(d.Meta.Updated) {
 		dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
-		if err := dm.Update(d); err != nil {
+		if err := dm.Update(d, nil); err != nil {
 			log.Errorf("%s", err.Error())
 			return err
 		}
@@ -738,7 +738,7 @@ func deploymentScale(d *types.Deployment, replicas int) error {
 	d.Status.State = types.StateProvision
 	d.Spec.Replicas = replicas
 	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
-	return dm.Update(d)
+	return dm.Update(d, nil)
 }
 
 func deploymentStatusState(d *types.Deployment, pl map[string]*types.Pod) (err error) {
@@ -22,6 +22,7 @@ import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -35,7 +36,7 @@ func testDeploymentObserver(t *testing.T, name, werr string, wst *ServiceState,
 
 	stg := envs.Get().GetStorage()
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(local.Config{})
 	envs.Get().SetIPAM(ipm)
 
 	err = stg.Del(ctx, stg.Collection().Deployment(), "")
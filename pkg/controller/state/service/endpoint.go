@@ -20,12 +20,14 @@ package service
 
 import (
 	"context"
+	"net"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
 )
 
 const logEndpointPrefix = "state:observer:endpoint"
@@ -57,6 +59,64 @@ func endpointEqual(e *types.Endpoint, svc *types.Service) bool {
 		return false
 	}
 
+	if e.Spec.Strategy.Route != svc.Spec.Network.Strategy.Route {
+		return false
+	}
+
+	if e.Spec.Strategy.Affinity != svc.Spec.Network.Strategy.Affinity {
+		return false
+	}
+
+	if !e.Spec.Strategy.WeightsEqual(svc.Spec.Network.Strategy) {
+		return false
+	}
+
+	if e.Spec.Headless != svc.Spec.Network.Headless {
+		return false
+	}
+
+	if len(e.Spec.PortNames) != len(svc.Spec.Network.PortNames) {
+		return false
+	}
+
+	for p, n := range svc.Spec.Network.PortNames {
+		if e.Spec.PortNames[p] != n {
+			return false
+		}
+	}
+
+	if e.Spec.External != svc.Spec.Network.IsExternal() {
+		return false
+	}
+
+	if e.Spec.ExternalName != svc.Spec.Network.ExternalName {
+		return false
+	}
+
+	if !endpointUpstreamsEqual(e.Spec.Upstreams, svc.Spec.Network.ExternalIPs) {
+		return false
+	}
+
+	// every port of exposed service should have node port
+	if svc.Spec.Network.Expose {
+		if len(e.Spec.NodePorts) != len(svc.Spec.Network.Ports) {
+			return false
+		}
+
+		for p := range svc.Spec.Network.Ports {
+			if _, ok := e.Spec.NodePorts[p]; !ok {
+				return false
+			}
+		}
+	} else if len(e.Spec.NodePorts) > 0 {
+		return false
+	}
+
+	// endpoint created before dual stack was enabled needs IPv6 lease
+	if e.Spec.IsProxied() && e.Spec.IPv6 == types.EmptyString && envs.Get().GetIPAM().DualStack() {
+		return false
+	}
+
 	return true
 }
 
@@ -92,7 +152,8 @@ func endpointRestore(ss *ServiceState) error {
 
 func endpointProvision(ss *ServiceState, svc *types.Service) error {
 
-	if len(svc.Spec.Network.Ports) == 0 {
+	// external name endpoint is resolved by discovery and needs no ports
+	if len(svc.Spec.Network.Ports) == 0 && svc.Spec.Network.ExternalName == types.EmptyString {
 
 		if ss.endpoint.endpoint != nil {
 			if err := endpointDel(ss); err != nil {
@@ -133,22 +194,31 @@ func endpointAdd(ss *ServiceState, svc *types.Service) error {
 		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
 	)
 
-	if svc.Spec.Network.IP == types.EmptyString {
-		ip, err := envs.Get().GetIPAM().Lease()
-		if err != nil {
-			log.Errorf("%s", err.Error())
-			return err
-		}
-		svc.Spec.Network.IP = ip.String()
+	if err := endpointLeaseIP(svc); err != nil {
+		return err
+	}
+
+	nodePorts, err := endpointNodePorts(svc, nil)
+	if err != nil {
+		return err
 	}
 
 	opts := types.EndpointCreateOptions{
 		IP:            svc.Spec.Network.IP,
+		IPv6:          svc.Spec.Network.IPv6,
 		Ports:         svc.Spec.Network.Ports,
 		Policy:        svc.Spec.Network.Policy,
 		BindStrategy:  svc.Spec.Network.Strategy.Bind,
 		RouteStrategy: svc.Spec.Network.Strategy.Route,
+		Affinity:      svc.Spec.Network.Strategy.Affinity,
+		Weights:       svc.Spec.Network.Strategy.Weights,
 		Domain:        svc.Meta.Endpoint,
+		NodePorts:     nodePorts,
+		Headless:      svc.Spec.Network.Headless,
+		PortNames:     svc.Spec.Network.PortNames,
+		External:      svc.Spec.Network.IsExternal(),
+		ExternalName:  svc.Spec.Network.ExternalName,
+		Upstreams:     svc.Spec.Network.ExternalIPs,
 	}
 
 	ss.endpoint.endpoint, err = em.Create(svc.Meta.Namespace, svc.Meta.Name, &opts)
@@ -165,26 +235,171 @@ func endpointAdd(ss *ServiceState, svc *types.Service) error {
 
 }
 
+// endpointLeaseIP leases service virtual addresses,
+// headless and external name services have no virtual addresses
+func endpointLeaseIP(svc *types.Service) error {
+
+	if !svc.Spec.Network.IsProxied() {
+		svc.Spec.Network.IP = types.EmptyString
+		svc.Spec.Network.IPv6 = types.EmptyString
+		return nil
+	}
+
+	owner := types.NewEndpointSelfLink(svc.Meta.Namespace, svc.Meta.Name).String()
+
+	if svc.Spec.Network.IP == types.EmptyString {
+		ip, err := envs.Get().GetIPAM().Lease(owner)
+		if err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+		svc.Spec.Network.IP = ip.String()
+	} else {
+		// address set in service spec should not be leased to another endpoint
+		if err := envs.Get().GetIPAM().Reserve(net.ParseIP(svc.Spec.Network.IP), owner); err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+	}
+
+	return endpointLeaseIPv6(svc)
+}
+
+// endpointLeaseIPv6 leases service IPv6 address if dual stack is enabled
+func endpointLeaseIPv6(svc *types.Service) error {
+
+	if svc.Spec.Network.IPv6 != types.EmptyString || !envs.Get().GetIPAM().DualStack() {
+		return nil
+	}
+
+	ip, err := envs.Get().GetIPAM().LeaseIPv6(types.NewEndpointSelfLink(svc.Meta.Namespace, svc.Meta.Name).String())
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		return err
+	}
+
+	svc.Spec.Network.IPv6 = ip.String()
+	return nil
+}
+
+// endpointNodePorts leases node ports for ports of exposed service,
+// ports leased before are kept
+func endpointNodePorts(svc *types.Service, current map[uint16]uint16) (map[uint16]uint16, error) {
+
+	var (
+		owner     = types.NewEndpointSelfLink(svc.Meta.Namespace, svc.Meta.Name).String()
+		nodePorts = make(map[uint16]uint16, 0)
+	)
+
+	if svc.Spec.Network.Expose {
+		for p := range svc.Spec.Network.Ports {
+
+			if np, ok := current[p]; ok {
+				nodePorts[p] = np
+				continue
+			}
+
+			// ports leased before failure are reclaimed by ipam collector
+			np, err := envs.Get().GetIPAM().LeasePort(owner)
+			if err != nil {
+				log.Errorf("%s> lease node port error: %s", logEndpointPrefix, err.Error())
+				return nil, err
+			}
+
+			nodePorts[p] = np
+		}
+	}
+
+	if len(nodePorts) == 0 {
+		return nil, nil
+	}
+
+	return nodePorts, nil
+}
+
+// endpointReleaseNodePorts releases node ports which are not exposed by endpoint anymore,
+// it should be called after endpoint is updated, so ports are not leased to others while still in use
+func endpointReleaseNodePorts(e *types.Endpoint, current map[uint16]uint16) {
+
+	for p, np := range current {
+		if _, ok := e.Spec.NodePorts[p]; ok {
+			continue
+		}
+
+		if err := envs.Get().GetIPAM().ReleasePort(np, e.SelfLink().String()); err != nil {
+			log.Errorf("%s> release node port error: %s", logEndpointPrefix, err.Error())
+		}
+	}
+}
+
 func endpointSet(ss *ServiceState, svc *types.Service) error {
 
 	var (
-		err error
-		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
+		em       = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
+		current  = ss.endpoint.endpoint
+		released bool
 	)
 
+	switch true {
+	case !svc.Spec.Network.IsProxied() && current.Spec.IsProxied():
+		// virtual addresses are not used by endpoint anymore, they are released after update
+		released = true
+		if err := endpointLeaseIP(svc); err != nil {
+			return err
+		}
+	case svc.Spec.Network.IsProxied() && !current.Spec.IsProxied():
+		svc.Spec.Network.IPv6 = types.EmptyString
+		if err := endpointLeaseIP(svc); err != nil {
+			return err
+		}
+	case svc.Spec.Network.IsProxied():
+		if err := endpointLeaseIPv6(svc); err != nil {
+			return err
+		}
+	}
+
+	nodePorts, err := endpointNodePorts(svc, current.Spec.NodePorts)
+	if err != nil {
+		return err
+	}
+
 	opts := types.EndpointUpdateOptions{
+		IP:            &svc.Spec.Network.IP,
+		IPv6:          &svc.Spec.Network.IPv6,
 		Ports:         svc.Spec.Network.Ports,
 		Policy:        svc.Spec.Network.Policy,
 		BindStrategy:  svc.Spec.Network.Strategy.Bind,
 		RouteStrategy: svc.Spec.Network.Strategy.Route,
+		Affinity:      svc.Spec.Network.Strategy.Affinity,
+		Weights:       svc.Spec.Network.Strategy.Weights,
+		NodePorts:     nodePorts,
+		Headless:      svc.Spec.Network.Headless,
+		PortNames:     svc.Spec.Network.PortNames,
+		External:      svc.Spec.Network.IsExternal(),
+		ExternalName:  svc.Spec.Network.ExternalName,
+		Upstreams:     svc.Spec.Network.ExternalIPs,
+	}
+
+	// endpoint is updated in copy, so current endpoint is kept if update fails
+	endpoint := new(types.Endpoint)
+	if err := compare.Copy(current, endpoint); err != nil {
+		return err
 	}
+	endpoint.System = current.System
 
-	ss.endpoint.endpoint, err = em.Update(ss.endpoint.endpoint, &opts)
+	endpoint, err = em.Update(endpoint, &opts)
 	if err != nil {
 		log.Errorf("%s> set endpoint error: %s", logPrefix, err.Error())
 		return err
 	}
 
+	ss.endpoint.endpoint = endpoint
+
+	if released {
+		endpointReleaseIPs(current)
+	}
+	endpointReleaseNodePorts(endpoint, current.Spec.NodePorts)
+
 	return nil
 }
 
@@ -196,6 +411,14 @@ func endpointDel(ss *ServiceState) error {
 			log.Errorf("%s> del endpoint error: %s", logEndpointPrefix, err.Error())
 			return err
 		}
+
+		endpointReleaseIPs(ss.endpoint.endpoint)
+
+		for _, np := range ss.endpoint.endpoint.Spec.NodePorts {
+			if err := envs.Get().GetIPAM().ReleasePort(np, ss.endpoint.endpoint.SelfLink().String()); err != nil {
+				log.Errorf("%s> release node port error: %s", logEndpointPrefix, err.Error())
+			}
+		}
 	}
 
 	ss.endpoint.endpoint = nil
@@ -203,10 +426,27 @@ func endpointDel(ss *ServiceState) error {
 	return nil
 }
 
+// endpointReleaseIPs releases endpoint virtual addresses,
+// leases left after failure are reclaimed by ipam collector
+func endpointReleaseIPs(e *types.Endpoint) {
+	for _, addr := range e.Spec.GetIPs() {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if err := envs.Get().GetIPAM().Release(&ip, e.SelfLink().String()); err != nil {
+			log.Errorf("%s> release endpoint ip error: %s", logEndpointPrefix, err.Error())
+		}
+	}
+}
+
 func endpointCheck(ss *ServiceState) error {
 
 	if ss.deployment.active != nil {
-		if ss.deployment.active.Status.State == types.StateReady {
+		// degraded deployment still serves traffic with ready pods,
+		// failed pods should be removed from upstreams
+		switch ss.deployment.active.Status.State {
+		case types.StateReady, types.StateDegradation:
 			if err := endpointManifestProvision(ss); err != nil {
 				return err
 			}
@@ -222,6 +462,10 @@ func endpointManifestSpecEqual(e *types.Endpoint, m *types.EndpointManifest) boo
 		return false
 	}
 
+	if e.Spec.IPv6 != m.IPv6 {
+		return false
+	}
+
 	if e.Spec.Domain != m.Domain {
 		return false
 	}
@@ -238,6 +482,38 @@ func endpointManifestSpecEqual(e *types.Endpoint, m *types.EndpointManifest) boo
 		return false
 	}
 
+	if e.Spec.Strategy.Affinity != m.Strategy.Affinity {
+		return false
+	}
+
+	if !e.Spec.Strategy.WeightsEqual(m.Strategy) {
+		return false
+	}
+
+	if e.Spec.Headless != m.Headless {
+		return false
+	}
+
+	if len(e.Spec.PortNames) != len(m.PortNames) {
+		return false
+	}
+
+	for p, n := range e.Spec.PortNames {
+		if m.PortNames[p] != n {
+			return false
+		}
+	}
+
+	if len(e.Spec.NodePorts) != len(m.NodePorts) {
+		return false
+	}
+
+	for p, np := range e.Spec.NodePorts {
+		if m.NodePorts[p] != np {
+			return false
+		}
+	}
+
 	for p, mp := range e.Spec.PortMap {
 		if _, ok := m.PortMap[p]; !ok {
 			return false
@@ -248,42 +524,31 @@ func endpointManifestSpecEqual(e *types.Endpoint, m *types.EndpointManifest) boo
 		}
 	}
 
-	if len(e.Spec.Upstreams) != len(m.Upstreams) {
+	if e.Spec.External != m.External {
 		return false
 	}
 
-	for _, u := range e.Spec.Upstreams {
-		var f = false
-		for _, mu := range m.Upstreams {
-			if u == mu {
-				f = true
-				break
-			}
-		}
-		if !f {
-			return false
-		}
+	if e.Spec.ExternalName != m.ExternalName {
+		return false
 	}
 
 	return true
 }
 
-func endpointManifestUpstreamsEqual(m *types.EndpointManifest, pl map[string]*types.Pod) bool {
+// endpointUpstreamsEqual checks both lists have the same addresses in any order
+func endpointUpstreamsEqual(a, b []string) bool {
 
-	var (
-		ips = endpointManifestGetUpstreams(pl)
-		ups = make(map[string]bool)
-	)
+	var ups = make(map[string]bool)
 
-	if len(m.Upstreams) != len(ips) {
+	if len(a) != len(b) {
 		return false
 	}
 
-	for _, ip := range m.Upstreams {
+	for _, ip := range a {
 		ups[ip] = true
 	}
 
-	for _, ip := range ips {
+	for _, ip := range b {
 		if _, ok := ups[ip]; !ok {
 			return false
 		}
@@ -299,16 +564,7 @@ func endpointManifestProvision(ss *ServiceState) error {
 	}
 
 	if ss.endpoint.manifest != nil {
-
-		var pl = make(map[string]*types.Pod)
-
-		if ss.deployment.active != nil {
-			if _, ok := ss.pod.list[ss.deployment.active.SelfLink().String()]; ok {
-				pl = ss.pod.list[ss.deployment.active.SelfLink().String()]
-			}
-		}
-
-		if !endpointManifestSpecEqual(ss.endpoint.endpoint, ss.endpoint.manifest) || !endpointManifestUpstreamsEqual(ss.endpoint.manifest, pl) {
+		if !endpointManifestSpecEqual(ss.endpoint.endpoint, ss.endpoint.manifest) || !endpointUpstreamsEqual(ss.endpoint.manifest.Upstreams, endpointManifestUpstreams(ss)) {
 			if err := endpointManifestSet(ss); err != nil {
 				return err
 			}
@@ -330,7 +586,6 @@ func endpointManifestAdd(ss *ServiceState) error {
 	var (
 		err error
 		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
-		pl  = make(map[string]*types.Pod)
 	)
 
 	if ss.endpoint.endpoint == nil {
@@ -338,12 +593,6 @@ func endpointManifestAdd(ss *ServiceState) error {
 		return nil
 	}
 
-	if ss.deployment.active != nil {
-		if _, ok := ss.pod.list[ss.deployment.active.SelfLink().String()]; ok {
-			pl = ss.pod.list[ss.deployment.active.SelfLink().String()]
-		}
-	}
-
 	epm, err := em.ManifestGet(ss.endpoint.endpoint.SelfLink().String())
 	if err != nil {
 		return err
@@ -352,7 +601,7 @@ func endpointManifestAdd(ss *ServiceState) error {
 	if epm == nil {
 		ss.endpoint.manifest = &types.EndpointManifest{}
 		ss.endpoint.manifest.EndpointSpec = ss.endpoint.endpoint.Spec
-		ss.endpoint.manifest.Upstreams = endpointManifestGetUpstreams(pl)
+		ss.endpoint.manifest.Upstreams = endpointManifestUpstreams(ss)
 
 		if err = em.ManifestAdd(ss.endpoint.endpoint.SelfLink().String(), ss.endpoint.manifest); err != nil {
 			log.Errorf("%s> add endpoint manifest error: %s", logPrefix, err.Error())
@@ -362,12 +611,13 @@ func endpointManifestAdd(ss *ServiceState) error {
 		return nil
 	}
 
-	if endpointManifestSpecEqual(ss.endpoint.endpoint, epm) {
+	if endpointManifestSpecEqual(ss.endpoint.endpoint, epm) && endpointUpstreamsEqual(epm.Upstreams, endpointManifestUpstreams(ss)) {
+		ss.endpoint.manifest = epm
 		return nil
 	}
 
 	epm.EndpointSpec = ss.endpoint.endpoint.Spec
-	epm.Upstreams = endpointManifestGetUpstreams(pl)
+	epm.Upstreams = endpointManifestUpstreams(ss)
 
 	if err = em.ManifestSet(ss.endpoint.endpoint.SelfLink().String(), epm); err != nil {
 		log.Errorf("%s> update endpoint manifest error: %s", logPrefix, err.Error())
@@ -383,7 +633,6 @@ func endpointManifestSet(ss *ServiceState) error {
 	var (
 		err error
 		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
-		pl  = make(map[string]*types.Pod)
 	)
 
 	if ss.endpoint.endpoint == nil {
@@ -391,14 +640,8 @@ func endpointManifestSet(ss *ServiceState) error {
 		return nil
 	}
 
-	if ss.deployment.active != nil {
-		if _, ok := ss.pod.list[ss.deployment.active.SelfLink().String()]; ok {
-			pl = ss.pod.list[ss.deployment.active.SelfLink().String()]
-		}
-	}
-
 	ss.endpoint.manifest.EndpointSpec = ss.endpoint.endpoint.Spec
-	ss.endpoint.manifest.Upstreams = endpointManifestGetUpstreams(pl)
+	ss.endpoint.manifest.Upstreams = endpointManifestUpstreams(ss)
 
 	if err = em.ManifestSet(ss.endpoint.endpoint.SelfLink().String(), ss.endpoint.manifest); err != nil {
 		log.Errorf("%s> update endpoint manifest error: %s", logPrefix, err.Error())
@@ -408,6 +651,26 @@ func endpointManifestSet(ss *ServiceState) error {
 	return nil
 }
 
+// endpointManifestUpstreamRelease removes pod from endpoint manifest upstreams
+func endpointManifestUpstreamRelease(ss *ServiceState, p *types.Pod) error {
+
+	if ss.endpoint.endpoint == nil || ss.endpoint.manifest == nil {
+		return nil
+	}
+
+	if p.Status.Network.PodIP == types.EmptyString && p.Status.Network.PodIPv6 == types.EmptyString {
+		return nil
+	}
+
+	for _, ip := range ss.endpoint.manifest.Upstreams {
+		if ip == p.Status.Network.PodIP || ip == p.Status.Network.PodIPv6 {
+			return endpointManifestSet(ss)
+		}
+	}
+
+	return nil
+}
+
 func endpointManifestDel(ss *ServiceState) error {
 
 	em := distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
@@ -424,15 +687,62 @@ func endpointManifestDel(ss *ServiceState) error {
 	return nil
 }
 
+// endpointManifestUpstreams returns external addresses of external endpoint
+// or ready pods addresses of active deployment
+func endpointManifestUpstreams(ss *ServiceState) []string {
+
+	if ss.endpoint.endpoint != nil && ss.endpoint.endpoint.Spec.External {
+		ips := make([]string, len(ss.endpoint.endpoint.Spec.Upstreams))
+		copy(ips, ss.endpoint.endpoint.Spec.Upstreams)
+		return ips
+	}
+
+	var pl = make(map[string]*types.Pod)
+
+	if ss.deployment.active != nil {
+		if _, ok := ss.pod.list[ss.deployment.active.SelfLink().String()]; ok {
+			pl = ss.pod.list[ss.deployment.active.SelfLink().String()]
+		}
+	}
+
+	return endpointManifestGetUpstreams(pl)
+}
+
 func endpointManifestGetUpstreams(pl map[string]*types.Pod) []string {
 
 	ips := make([]string, 0)
 
 	for _, p := range pl {
-		if p.Status.State == types.StateReady && p.Status.Network.PodIP != types.EmptyString {
+		if !endpointUpstreamReady(p) {
+			continue
+		}
+
+		// dual stack pods are upstreams for both endpoint addresses
+		if p.Status.Network.PodIP != types.EmptyString {
 			ips = append(ips, p.Status.Network.PodIP)
 		}
+
+		if p.Status.Network.PodIPv6 != types.EmptyString {
+			ips = append(ips, p.Status.Network.PodIPv6)
+		}
 	}
 
 	return ips
 }
+
+// endpointUpstreamReady checks pod can receive traffic:
+// pod should be ready and all service containers should pass readiness checks
+func endpointUpstreamReady(p *types.Pod) bool {
+
+	if p.Status.State != types.StateReady {
+		return false
+	}
+
+	for _, c := range p.Status.Runtime.Services {
+		if !c.Ready {
+			return false
+		}
+	}
+
+	return true
+}
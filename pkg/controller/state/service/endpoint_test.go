@@ -0,0 +1,78 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointManifestGetUpstreams(t *testing.T) {
+
+	svc := getServiceAsset(types.StateReady, types.EmptyString)
+	d := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+
+	ready := getPodAsset(d, types.StateReady, types.EmptyString)
+	ready.Status.Network.PodIP = "10.0.0.2"
+	ready.Status.Runtime.Services = map[string]*types.PodContainer{"c1": {ID: "c1", Ready: true}}
+
+	unready := getPodAsset(d, types.StateReady, types.EmptyString)
+	unready.Status.Network.PodIP = "10.0.0.3"
+	unready.Status.Runtime.Services = map[string]*types.PodContainer{
+		"c1": {ID: "c1", Ready: true},
+		"c2": {ID: "c2", Ready: false},
+	}
+
+	failed := getPodAsset(d, types.StateError, types.EmptyString)
+	failed.Status.Network.PodIP = "10.0.0.4"
+
+	pl := map[string]*types.Pod{
+		ready.SelfLink().String():   ready,
+		unready.SelfLink().String(): unready,
+		failed.SelfLink().String():  failed,
+	}
+
+	assert.Equal(t, []string{"10.0.0.2"}, endpointManifestGetUpstreams(pl), "unready pods should not be upstreams")
+}
+
+func TestEndpointManifestUpstreamsExternal(t *testing.T) {
+
+	svc := getServiceAsset(types.StateReady, types.EmptyString)
+
+	ss := new(ServiceState)
+	ss.service = svc
+	ss.pod.list = make(map[string]map[string]*types.Pod)
+	ss.endpoint.endpoint = new(types.Endpoint)
+	ss.endpoint.endpoint.Spec.External = true
+	ss.endpoint.endpoint.Spec.Upstreams = []string{"192.0.2.10", "192.0.2.11"}
+
+	assert.Equal(t, []string{"192.0.2.10", "192.0.2.11"}, endpointManifestUpstreams(ss), "external addresses should be upstreams")
+
+	ss.endpoint.endpoint.Spec.External = false
+	assert.Empty(t, endpointManifestUpstreams(ss), "endpoint without active deployment should have no upstreams")
+}
+
+func TestEndpointUpstreamsEqual(t *testing.T) {
+	assert.True(t, endpointUpstreamsEqual([]string{"192.0.2.10", "192.0.2.11"}, []string{"192.0.2.11", "192.0.2.10"}))
+	assert.True(t, endpointUpstreamsEqual(nil, []string{}))
+	assert.False(t, endpointUpstreamsEqual([]string{"192.0.2.10"}, []string{"192.0.2.11"}))
+	assert.False(t, endpointUpstreamsEqual([]string{"192.0.2.10"}, nil))
+}
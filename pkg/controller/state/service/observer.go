@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
@@ -54,6 +55,8 @@ type ServiceState struct {
 	}
 	pod struct {
 		list map[string]map[string]*types.Pod
+		// released keeps time pods were removed from endpoint upstreams
+		released map[string]time.Time
 	}
 
 	observers struct {
@@ -78,7 +81,7 @@ func (ss *ServiceState) Restore() error {
 
 	// Get all pods
 	pm := distribution.NewPodModel(context.Background(), stg)
-	pl, err := pm.ListByService(ss.service.Meta.Namespace, ss.service.Meta.Name)
+	pl, err := pm.ListByService(ss.service.Meta.Namespace, ss.service.Meta.Name, nil)
 	if err != nil {
 		log.Errorf("%s:restore:> get pod map error: %v", logPrefix, err)
 		return err
@@ -99,7 +102,7 @@ func (ss *ServiceState) Restore() error {
 
 	// Get all deployments
 	dm := distribution.NewDeploymentModel(context.Background(), stg)
-	dl, err := dm.ListByService(ss.service.Meta.Namespace, ss.service.Meta.Name)
+	dl, err := dm.ListByService(ss.service.Meta.Namespace, ss.service.Meta.Name, nil)
 	if err != nil {
 		log.Errorf("%s:restore:> get deployment map error: %v", logPrefix, err)
 		return err
@@ -261,6 +264,8 @@ func (ss *ServiceState) SetPod(p *types.Pod) {
 }
 
 func (ss *ServiceState) DelPod(p *types.Pod) {
+	delete(ss.pod.released, p.SelfLink().String())
+
 	_, sl := p.SelfLink().Parent()
 
 	if sl == nil {
@@ -335,6 +340,7 @@ func NewServiceState(cs *cluster.ClusterState, s *types.Service) *ServiceState {
 
 	ss.deployment.list = make(map[string]*types.Deployment)
 	ss.pod.list = make(map[string]map[string]*types.Pod)
+	ss.pod.released = make(map[string]time.Time)
 
 	go ss.Observe()
 
@@ -107,7 +107,8 @@ func PodObserve(ss *ServiceState, p *types.Pod) error {
 	}
 
 	if ss.deployment.active != nil {
-		if ss.deployment.active.SelfLink().String() == d.SelfLink().String() && d.Status.State == types.StateReady {
+		if ss.deployment.active.SelfLink().String() == d.SelfLink().String() &&
+			(d.Status.State == types.StateReady || d.Status.State == types.StateDegradation) {
 			if err := endpointCheck(ss); err != nil {
 				return err
 			}
@@ -191,6 +192,9 @@ func podCreate(d *types.Deployment) (*types.Pod, error) {
 	pod.Meta.SetDefault()
 	pod.Meta.Name = strings.Split(generator.GetUUIDV4(), "-")[4][5:]
 	pod.Meta.Namespace = d.Meta.Namespace
+	for k, v := range d.Meta.Labels {
+		pod.Meta.Labels[k] = v
+	}
 	sl, _ := types.NewPodSelfLink(types.KindDeployment, d.SelfLink().String(), pod.Meta.Name)
 	pod.Meta.SelfLink = *sl
 	pod.Status.SetCreated()
@@ -274,6 +278,29 @@ func podDestroy(ss *ServiceState, p *types.Pod) (err error) {
 		return nil
 	}
 
+	// remove pod from endpoint upstreams before it starts terminating,
+	// and wait until nodes proxies and dns stop routing requests to it
+	released, ok := ss.pod.released[p.SelfLink().String()]
+	if !ok {
+		p.Status.State = types.StateDestroy
+		if err = endpointManifestUpstreamRelease(ss, p); err != nil {
+			return err
+		}
+
+		released = time.Now()
+		ss.pod.released[p.SelfLink().String()] = released
+		p.Meta.Updated = released
+	}
+
+	if delay := envs.Get().GetUpstreamReleaseDelay() - time.Since(released); delay > 0 && p.Meta.Node != types.EmptyString {
+		time.AfterFunc(delay, func() {
+			ss.SetPod(p)
+		})
+		return nil
+	}
+
+	delete(ss.pod.released, p.SelfLink().String())
+
 	p.Spec.State.Destroy = true
 	if err = podManifestSet(p); err != nil {
 		if errors.Storage().IsErrEntityNotFound(err) {
@@ -22,9 +22,12 @@ import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func testPodObserver(t *testing.T, name, werr string, wst *ServiceState, state *ServiceState, p *types.Pod) {
@@ -36,7 +39,7 @@ func testPodObserver(t *testing.T, name, werr string, wst *ServiceState, state *
 
 	stg := envs.Get().GetStorage()
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(local.Config{})
 	envs.Get().SetIPAM(ipm)
 
 	err = stg.Del(ctx, stg.Collection().Deployment(), "")
@@ -875,3 +878,45 @@ func TestHandlePodStateDestroyed(t *testing.T) {
 		testPodObserver(t, tt.name, tt.want.err, tt.want.state, tt.args.state, tt.args.pod)
 	}
 }
+
+func TestPodDestroyUpstreamReleaseDelay(t *testing.T) {
+
+	envs.Get().SetUpstreamReleaseDelay(time.Hour)
+	defer envs.Get().SetUpstreamReleaseDelay(0)
+
+	svc := getServiceAsset(types.StateReady, types.EmptyString)
+	dp := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+	pod := getPodAsset(dp, types.StateReady, types.EmptyString)
+	pod.Meta.Node = "node"
+
+	ss := getServiceStateAsset(svc)
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	if _, err := pm.Put(pod); !assert.NoError(t, err, "pod put") {
+		return
+	}
+
+	if err := podManifestPut(pod); !assert.NoError(t, err, "pod manifest put") {
+		return
+	}
+
+	err := podDestroy(ss, pod)
+	if !assert.NoError(t, err, "pod destroy") {
+		return
+	}
+
+	assert.Equal(t, types.StateDestroy, pod.Status.State, "pod state should be destroy")
+	assert.False(t, pod.Spec.State.Destroy, "pod should not be terminated before delay")
+	assert.Contains(t, ss.pod.released, pod.SelfLink().String(), "pod upstream release time not saved")
+
+	// upstream release delay is passed
+	ss.pod.released[pod.SelfLink().String()] = time.Now().Add(-time.Hour)
+
+	err = podDestroy(ss, pod)
+	if !assert.NoError(t, err, "pod destroy") {
+		return
+	}
+
+	assert.True(t, pod.Spec.State.Destroy, "pod should be terminated after delay")
+	assert.NotContains(t, ss.pod.released, pod.SelfLink().String(), "pod upstream release time not removed")
+}
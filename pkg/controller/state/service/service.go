@@ -256,9 +256,10 @@ func handleServiceStateDestroyed(ss *ServiceState, svc *types.Service) (err erro
 	}
 
 	if ns != nil {
+		allocated := ns.Status.Resources.Allocated
 		ns.ReleaseResources(svc.Spec.GetResourceRequest())
 
-		if err := nm.Update(ns); err != nil {
+		if err := nm.Allocate(ns, ns.Status.Resources.Allocated.Diff(allocated)); err != nil {
 			log.Errorf("%s:> namespece update err: %s", logServicePrefix, err.Error())
 		}
 	}
@@ -286,9 +287,20 @@ func serviceEndpointProvision(ss *ServiceState, svc *types.Service) error {
 		return err
 	}
 
+	// external endpoint has no deployment, manifest upstreams are taken from endpoint spec,
+	// endpoint switched back from external gets pods upstreams
+	if (ss.endpoint.endpoint != nil && ss.endpoint.endpoint.Spec.External) ||
+		(ss.endpoint.manifest != nil && ss.endpoint.manifest.External) {
+		if err := endpointManifestProvision(ss); err != nil {
+			log.Errorf("%s:> endpoint manifest provision err: %s", logServicePrefix, err.Error())
+			return err
+		}
+	}
+
 	if ss.endpoint.endpoint != nil {
 		svc.Meta.Endpoint = ss.endpoint.endpoint.Spec.Domain
 		svc.Meta.IP = ss.endpoint.endpoint.Spec.IP
+		svc.Meta.NodePorts = ss.endpoint.endpoint.Spec.NodePorts
 	}
 
 	return nil
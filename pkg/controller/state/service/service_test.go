@@ -25,6 +25,7 @@ import (
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/storage"
@@ -40,7 +41,7 @@ func init() {
 	stg, _ := storage.Get(v)
 	envs.Get().SetStorage(stg)
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(local.Config{})
 	envs.Get().SetIPAM(ipm)
 }
 
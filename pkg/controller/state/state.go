@@ -21,6 +21,7 @@ package state
 import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/job"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
@@ -30,7 +31,10 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/log"
 )
 
-const logLevel = 3
+const (
+	logLevel            = 3
+	ipamCollectInterval = 5 * time.Minute
+)
 
 type State struct {
 	Cluster *cluster.ClusterState
@@ -105,7 +109,7 @@ func (s *State) Loop() {
 		return
 	}
 
-	ns, err := nm.List()
+	ns, err := nm.List(nil)
 	if err != nil {
 		log.Errorf("%s", err.Error())
 		return
@@ -113,7 +117,7 @@ func (s *State) Loop() {
 
 	for _, n := range ns.Items {
 		log.V(logLevel).Debugf("\n\nrestore namespace: %s", n.SelfLink())
-		ss, err := sm.List(n.SelfLink().String())
+		ss, err := sm.List(n.SelfLink().String(), nil)
 		if err != nil {
 			log.Errorf("%s", err.Error())
 			return
@@ -131,7 +135,7 @@ func (s *State) Loop() {
 			}
 		}
 
-		js, err := jm.ListByNamespace(n.SelfLink().String())
+		js, err := jm.ListByNamespace(n.SelfLink().String(), nil)
 		if err != nil {
 			log.Errorf("%s", err.Error())
 			return
@@ -149,7 +153,7 @@ func (s *State) Loop() {
 			}
 		}
 
-		vl, err := vm.ListByNamespace(n.SelfLink().String())
+		vl, err := vm.ListByNamespace(n.SelfLink().String(), nil)
 		if err != nil {
 			log.Errorf("%s", err.Error())
 			return
@@ -171,6 +175,7 @@ func (s *State) Loop() {
 	go s.watchVolumes(context.Background(), &vr.Storage.Revision)
 	go s.watchSecrets(context.Background(), &scr.Storage.Revision)
 	go s.watchConfigs(context.Background(), &cr.Storage.Revision)
+	go s.collectIPAM(context.Background())
 
 	log.Info("finish services restore\n\n")
 }
@@ -586,6 +591,72 @@ func (s *State) watchConfigs(ctx context.Context, rev *int64) {
 	}
 }
 
+// collectIPAM periodically releases IPAM leases of removed endpoints and nodes
+func (s *State) collectIPAM(ctx context.Context) {
+
+	ticker := time.NewTicker(ipamCollectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+
+			ipm := envs.Get().GetIPAM()
+			if ipm == nil {
+				continue
+			}
+
+			owners, err := ipamOwners(ctx)
+			if err != nil {
+				log.Errorf("ipam collect err: %v", err)
+				continue
+			}
+
+			count, err := ipm.Collect(owners)
+			if err != nil {
+				log.Errorf("ipam collect err: %v", err)
+				continue
+			}
+
+			if count > 0 {
+				log.V(logLevel).Debugf("ipam collect: %d leases released", count)
+			}
+		}
+	}
+}
+
+// ipamOwners returns addresses in use by endpoints and pod subnets in use by nodes
+func ipamOwners(ctx context.Context) (map[string][]string, error) {
+
+	var (
+		owners = make(map[string][]string)
+		em     = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
+		nm     = distribution.NewNodeModel(ctx, envs.Get().GetStorage())
+	)
+
+	el, err := em.List(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range el.Items {
+		owners[e.SelfLink().String()] = append(e.Spec.GetIPs(), e.Spec.GetNodePorts()...)
+	}
+
+	nl, err := nm.List(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range nl.Items {
+		owners[n.SelfLink().String()] = []string{n.Spec.Network.CIDR}
+	}
+
+	return owners, nil
+}
+
 func NewState() *State {
 	var state = new(State)
 	state.Cluster = cluster.NewClusterState()
@@ -26,14 +26,15 @@ import (
 
 const (
 	logLevel          = 7
-	defaultExpireTime = 24 // 24 hours
+	defaultExpireTime = 24 * time.Hour
 )
 
 type Cache struct {
 	endpoints *EndpointCache
+	responses *ResponseCache
 }
 
-func New(ttl time.Duration) *Cache {
+func New(ttl, negative time.Duration, size int) *Cache {
 	log.V(logLevel).Debug("Cache: initialization cache storage")
 
 	var duration = ttl
@@ -42,7 +43,8 @@ func New(ttl time.Duration) *Cache {
 	}
 
 	return &Cache{
-		endpoints: NewEndpointCache(duration * time.Minute),
+		endpoints: NewEndpointCache(duration, negative),
+		responses: NewResponseCache(size),
 	}
 }
 
@@ -50,3 +52,8 @@ func New(ttl time.Duration) *Cache {
 func (s *Cache) Endpoint() *EndpointCache {
 	return s.endpoints
 }
+
+// Return forwarded responses storage
+func (s *Cache) Response() *ResponseCache {
+	return s.responses
+}
@@ -20,38 +20,93 @@ package cache
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
+// EndpointEntry describes cached domain: addresses of domain
+// or external name domain is alias of
+type EndpointEntry struct {
+	Addresses []string
+	// Addresses are pods addresses of headless endpoint
+	Headless bool
+	// External name of domain, domain has no addresses
+	Alias string
+}
+
+// EndpointCache keeps addresses of cluster domains, entries are updated by endpoints watch
+// and expire after ttl only if watch events are missed
 type EndpointCache struct {
-	mutex sync.RWMutex
-	ttl   time.Duration
-	items map[string]*Item
+	hits     uint64
+	misses   uint64
+	mutex    sync.RWMutex
+	ttl      time.Duration
+	negative time.Duration
+	items    map[string]*Item
 }
 
 func (ec *EndpointCache) Set(key string, data []string) {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
 
-	item := &Item{data: data}
+	item := &Item{data: itemData(data)}
+	item.setExpireTime(ec.ttl)
+	ec.items[key] = item
+}
+
+// SetHeadless stores pods addresses of headless endpoint domain
+func (ec *EndpointCache) SetHeadless(key string, data []string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	item := &Item{data: itemData(data), headless: true}
 	item.setExpireTime(ec.ttl)
 	ec.items[key] = item
 }
 
-func (ec *EndpointCache) Get(key string) (data []string) {
+// SetAlias stores external name of endpoint domain
+func (ec *EndpointCache) SetAlias(key string, name string) {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
 
+	item := &Item{data: make([]string, 0), alias: name}
+	item.setExpireTime(ec.ttl)
+	ec.items[key] = item
+}
+
+// SetNegative stores unknown domain, so storage is not requested on every query of it
+func (ec *EndpointCache) SetNegative(key string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	item := &Item{negative: true}
+	item.setExpireTime(ec.negative)
+	ec.items[key] = item
+}
+
+// Lookup returns cached domain entry,
+// ok is true for known domains and for unknown domains stored as negative entries,
+// entry of negative entries is nil, known domain without addresses has empty list
+func (ec *EndpointCache) Lookup(key string) (entry *EndpointEntry, ok bool) {
+	ec.mutex.RLock()
 	item, ok := ec.items[key]
+	ec.mutex.RUnlock()
+
 	if !ok || item.expired() {
-		data = nil
-		return
+		atomic.AddUint64(&ec.misses, 1)
+		return nil, false
 	}
 
-	item.setExpireTime(ec.ttl)
-	data = item.data
+	atomic.AddUint64(&ec.hits, 1)
 
-	return
+	if item.negative {
+		return nil, true
+	}
+
+	// expiry is not refreshed on lookup, so entry of missed watch event is requested from storage again
+	return &EndpointEntry{Addresses: item.data, Headless: item.headless, Alias: item.alias}, true
 }
 
 func (ec *EndpointCache) Del(key string) {
@@ -62,6 +117,8 @@ func (ec *EndpointCache) Del(key string) {
 }
 
 func (ec *EndpointCache) Clear() {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
 	ec.items = make(map[string]*Item, 0)
 }
 
@@ -71,6 +128,22 @@ func (ec *EndpointCache) Count() int {
 	return len(ec.items)
 }
 
+// Stats returns cache lookups stats
+func (ec *EndpointCache) Stats() types.DiscoveryDNSCacheStats {
+
+	stats := types.DiscoveryDNSCacheStats{
+		Hits:    atomic.LoadUint64(&ec.hits),
+		Misses:  atomic.LoadUint64(&ec.misses),
+		Entries: ec.Count(),
+	}
+
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+
+	return stats
+}
+
 func (ec *EndpointCache) cleanup() {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
@@ -98,10 +171,19 @@ func (ec *EndpointCache) cleanerTimer() {
 	})()
 }
 
-func NewEndpointCache(duration time.Duration) *EndpointCache {
+// itemData keeps known domain without addresses apart from negative entry
+func itemData(data []string) []string {
+	if data == nil {
+		return make([]string, 0)
+	}
+	return data
+}
+
+func NewEndpointCache(duration, negative time.Duration) *EndpointCache {
 	cache := &EndpointCache{
-		ttl:   duration,
-		items: make(map[string]*Item, 0),
+		ttl:      duration,
+		negative: negative,
+		items:    make(map[string]*Item, 0),
 	}
 	cache.cleanerTimer()
 	return cache
@@ -0,0 +1,104 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointCacheLookup(t *testing.T) {
+
+	ec := NewEndpointCache(time.Minute, time.Minute)
+
+	ec.Set("web.demo.lb.local", []string{"172.0.0.2"})
+	ec.SetHeadless("db.demo.lb.local", nil)
+	ec.SetNegative("missing.demo.lb.local")
+
+	entry, ok := ec.Lookup("web.demo.lb.local")
+	assert.True(t, ok, "known domain should be found")
+	assert.False(t, entry.Headless)
+	assert.Equal(t, []string{"172.0.0.2"}, entry.Addresses)
+
+	entry, ok = ec.Lookup("db.demo.lb.local")
+	assert.True(t, ok, "headless domain without pods should be found")
+	assert.True(t, entry.Headless)
+	assert.NotNil(t, entry.Addresses, "headless domain without pods should not be negative entry")
+
+	entry, ok = ec.Lookup("missing.demo.lb.local")
+	assert.True(t, ok, "negative entry should be found")
+	assert.Nil(t, entry, "negative entry should have no addresses")
+
+	_, ok = ec.Lookup("api.demo.lb.local")
+	assert.False(t, ok, "unknown domain should not be found")
+
+	// watch event replaces negative entry
+	ec.Set("missing.demo.lb.local", []string{"172.0.0.3"})
+	entry, _ = ec.Lookup("missing.demo.lb.local")
+	assert.Equal(t, []string{"172.0.0.3"}, entry.Addresses)
+
+	stats := ec.Stats()
+	assert.Equal(t, uint64(4), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, 0.8, stats.HitRatio)
+}
+
+func TestEndpointCacheAlias(t *testing.T) {
+
+	ec := NewEndpointCache(time.Minute, time.Minute)
+	ec.SetAlias("mail.demo.lb.local", "mail.example.com")
+
+	entry, ok := ec.Lookup("mail.demo.lb.local")
+	assert.True(t, ok, "alias domain should be found")
+	assert.Equal(t, "mail.example.com", entry.Alias)
+	assert.Empty(t, entry.Addresses, "alias domain should have no addresses")
+	assert.NotNil(t, entry.Addresses, "alias domain should not be negative entry")
+}
+
+func TestEndpointCacheNegativeExpire(t *testing.T) {
+
+	ec := NewEndpointCache(time.Minute, time.Millisecond)
+	ec.SetNegative("missing.demo.lb.local")
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := ec.Lookup("missing.demo.lb.local")
+	assert.False(t, ok, "expired negative entry should not be found")
+}
+
+func TestEndpointCacheExpire(t *testing.T) {
+
+	ec := NewEndpointCache(20*time.Millisecond, time.Minute)
+	ec.Set("web.demo.lb.local", []string{"172.0.0.2"})
+
+	// lookups should not keep entry of missed watch event alive
+	for i := 0; i < 3; i++ {
+		_, ok := ec.Lookup("web.demo.lb.local")
+		assert.True(t, ok, "entry should be found before ttl")
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok := ec.Lookup("web.demo.lb.local")
+	assert.False(t, ok, "expired entry should not be found")
+}
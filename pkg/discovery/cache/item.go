@@ -25,8 +25,11 @@ import (
 
 type Item struct {
 	sync.RWMutex
-	data    []string
-	expires *time.Time
+	data     []string
+	headless bool
+	negative bool
+	alias    string
+	expires  *time.Time
 }
 
 func (item *Item) setExpireTime(duration time.Duration) {
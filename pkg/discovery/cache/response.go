@@ -0,0 +1,219 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cache
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const defaultResponseCacheSize = 10000
+
+// ResponseCache keeps forwarded queries responses until their records TTLs expire
+type ResponseCache struct {
+	mutex sync.RWMutex
+	size  int
+	items map[string]*response
+}
+
+type response struct {
+	msg     *dns.Msg
+	stored  time.Time
+	expires time.Time
+}
+
+// Get returns cached response for query with records TTLs decreased by time spent in cache
+func (rc *ResponseCache) Get(r *dns.Msg) *dns.Msg {
+
+	key := responseKey(r)
+	if key == "" {
+		return nil
+	}
+
+	rc.mutex.RLock()
+	item, ok := rc.items[key]
+	rc.mutex.RUnlock()
+
+	if !ok || item.expires.Before(time.Now()) {
+		return nil
+	}
+
+	msg := item.msg.Copy()
+	msg.Id = r.Id
+
+	elapsed := uint32(time.Since(item.stored) / time.Second)
+	for _, section := range [][]dns.RR{msg.Answer, msg.Ns, msg.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if rr.Header().Ttl > elapsed {
+				rr.Header().Ttl -= elapsed
+			} else {
+				rr.Header().Ttl = 0
+			}
+		}
+	}
+
+	return msg
+}
+
+// Set stores response for its TTL, responses without records and failed responses are not cached
+func (rc *ResponseCache) Set(m *dns.Msg) {
+
+	key := responseKey(m)
+	if key == "" || m.Truncated {
+		return
+	}
+
+	ttl := responseTTL(m)
+	if ttl == 0 {
+		return
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if _, ok := rc.items[key]; !ok && len(rc.items) >= rc.size {
+		rc.evict()
+	}
+
+	now := time.Now()
+	rc.items[key] = &response{msg: m.Copy(), stored: now, expires: now.Add(ttl)}
+}
+
+func (rc *ResponseCache) Clear() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	rc.items = make(map[string]*response, 0)
+}
+
+func (rc *ResponseCache) Count() int {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+	return len(rc.items)
+}
+
+// evict removes expired responses, any response is removed if cache is still full
+func (rc *ResponseCache) evict() {
+
+	now := time.Now()
+	for key, item := range rc.items {
+		if item.expires.Before(now) {
+			delete(rc.items, key)
+		}
+	}
+
+	for key := range rc.items {
+		if len(rc.items) < rc.size {
+			return
+		}
+		delete(rc.items, key)
+	}
+}
+
+func (rc *ResponseCache) cleanup() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	now := time.Now()
+	for key, item := range rc.items {
+		if item.expires.Before(now) {
+			delete(rc.items, key)
+		}
+	}
+}
+
+func (rc *ResponseCache) cleanerTimer() {
+	ticker := time.Tick(time.Minute)
+	go (func() {
+		for {
+			select {
+			case <-ticker:
+				rc.cleanup()
+			}
+		}
+	})()
+}
+
+// responseKey returns cache key of message question, DNSSEC responses are cached separately
+func responseKey(m *dns.Msg) string {
+
+	if len(m.Question) != 1 {
+		return ""
+	}
+
+	q := m.Question[0]
+
+	do := false
+	if opt := m.IsEdns0(); opt != nil {
+		do = opt.Do()
+	}
+
+	return fmt.Sprintf("%s/%d/%d/%t", strings.ToLower(q.Name), q.Qtype, q.Qclass, do)
+}
+
+// responseTTL returns the lowest TTL of response records,
+// negative responses are cached for SOA minimum TTL as described in RFC 2308
+func responseTTL(m *dns.Msg) time.Duration {
+
+	if m.Rcode != dns.RcodeSuccess && m.Rcode != dns.RcodeNameError {
+		return 0
+	}
+
+	var ttl uint32 = math.MaxUint32
+
+	for _, section := range [][]dns.RR{m.Answer, m.Ns} {
+		for _, rr := range section {
+			if rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+			}
+			if soa, ok := rr.(*dns.SOA); ok && soa.Minttl < ttl {
+				ttl = soa.Minttl
+			}
+		}
+	}
+
+	if ttl == math.MaxUint32 {
+		return 0
+	}
+
+	return time.Duration(ttl) * time.Second
+}
+
+func NewResponseCache(size int) *ResponseCache {
+
+	if size <= 0 {
+		size = defaultResponseCacheSize
+	}
+
+	rc := &ResponseCache{
+		size:  size,
+		items: make(map[string]*response, 0),
+	}
+
+	rc.cleanerTimer()
+
+	return rc
+}
@@ -0,0 +1,85 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+
+	rc := NewResponseCache(2)
+
+	r := new(dns.Msg)
+	r.SetQuestion("example.com.", dns.TypeA)
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	a, _ := dns.NewRR("example.com. 60 IN A 10.0.0.1")
+	m.Answer = append(m.Answer, a)
+
+	rc.Set(m)
+
+	q := new(dns.Msg)
+	q.SetQuestion("EXAMPLE.com.", dns.TypeA)
+
+	resp := rc.Get(q)
+	if assert.NotNil(t, resp, "response should be cached") {
+		assert.Equal(t, q.Id, resp.Id, "response id should match query")
+		assert.True(t, resp.Answer[0].Header().Ttl <= 60)
+	}
+
+	q.SetQuestion("example.com.", dns.TypeAAAA)
+	assert.Nil(t, rc.Get(q), "response of other type should not be returned")
+
+	// expired responses are not returned
+	rc.items[responseKey(r)].expires = time.Now().Add(-time.Second)
+	assert.Nil(t, rc.Get(r), "expired response should not be returned")
+
+	// failed responses are not cached
+	rc.Clear()
+	m.SetRcode(r, dns.RcodeServerFailure)
+	rc.Set(m)
+	assert.Equal(t, 0, rc.Count(), "failed response should not be cached")
+}
+
+func TestResponseTTL(t *testing.T) {
+
+	r := new(dns.Msg)
+	r.SetQuestion("missing.example.com.", dns.TypeA)
+
+	m := new(dns.Msg)
+	m.SetRcode(r, dns.RcodeNameError)
+	assert.Equal(t, time.Duration(0), responseTTL(m), "response without records should not be cached")
+
+	soa, _ := dns.NewRR("example.com. 3600 IN SOA ns.example.com. admin.example.com. 1 7200 3600 1209600 300")
+	m.Ns = append(m.Ns, soa)
+	assert.Equal(t, 300*time.Second, responseTTL(m), "negative response should be cached for SOA minimum")
+
+	m = new(dns.Msg)
+	m.SetReply(r)
+	a, _ := dns.NewRR("missing.example.com. 60 IN A 10.0.0.1")
+	b, _ := dns.NewRR("missing.example.com. 30 IN A 10.0.0.2")
+	m.Answer = append(m.Answer, a, b)
+	assert.Equal(t, 30*time.Second, responseTTL(m), "response should be cached for the lowest ttl")
+}
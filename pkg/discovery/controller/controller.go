@@ -86,6 +86,9 @@ func (c *Controller) Sync(ctx context.Context) error {
 		opts.IP = status.IP
 		opts.Port = status.Port
 		opts.Online = status.Online
+		opts.DNS = envs.Get().GetState().DNS().Stats()
+		opts.DNS.CacheEntries = envs.Get().GetCache().Response().Count()
+		opts.DNS.Endpoints = envs.Get().GetCache().Endpoint().Stats()
 
 		_, err := envs.Get().GetClient().SetStatus(ctx, opts)
 		if err != nil {
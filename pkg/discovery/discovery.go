@@ -28,7 +28,9 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
 	"github.com/lastbackend/lastbackend/pkg/discovery/controller"
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
+	"github.com/lastbackend/lastbackend/pkg/discovery/forward"
 	"github.com/lastbackend/lastbackend/pkg/discovery/runtime"
+	"github.com/lastbackend/lastbackend/pkg/discovery/runtime/endpoint"
 	"github.com/lastbackend/lastbackend/pkg/discovery/state"
 	l "github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
@@ -72,7 +74,25 @@ func Daemon(v *viper.Viper) bool {
 		log.Fatalf("Cannot initialize storage: %s", err.Error())
 	}
 	env.SetStorage(stg)
-	env.SetCache(cache.New(v.GetDuration("dns.ttl")))
+	env.SetCache(cache.New(v.GetDuration("dns.ttl"), v.GetDuration("dns.negative_ttl"), v.GetInt("dns.cache_size")))
+	env.SetRecordTTL(v.GetDuration("dns.record_ttl"))
+	env.SetHeadlessTTL(v.GetDuration("dns.headless_ttl"))
+
+	fwd, err := forward.New(forward.Opts{
+		Upstreams: v.GetStringSlice("dns.upstreams"),
+		Stubs:     v.GetStringSlice("dns.stub_zones"),
+		Timeout:   v.GetDuration("dns.forward_timeout"),
+	})
+	if err != nil {
+		log.Fatalf("Cannot initialize dns forwarding: %s", err.Error())
+	}
+	env.SetForwarder(fwd)
+
+	// watch without revision sends all endpoints as events, so cache is warmed by watch itself
+	if err := endpoint.Restore(context.Background()); err != nil {
+		log.Errorf("Restore endpoints cache err: %s", err.Error())
+		go endpoint.Watch(context.Background(), nil, nil)
+	}
 
 	if v.IsSet("api") {
 
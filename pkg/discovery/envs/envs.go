@@ -19,8 +19,11 @@
 package envs
 
 import (
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/api/client/types"
 	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
+	"github.com/lastbackend/lastbackend/pkg/discovery/forward"
 	"github.com/lastbackend/lastbackend/pkg/discovery/state"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 )
@@ -32,6 +35,11 @@ type Env struct {
 	storage storage.Storage
 	cache   *cache.Cache
 	state   *state.State
+	forward *forward.Forwarder
+	ttl     struct {
+		record   time.Duration
+		headless time.Duration
+	}
 }
 
 func Get() *Env {
@@ -62,6 +70,30 @@ func (c *Env) GetState() *state.State {
 	return c.state
 }
 
+func (c *Env) SetForwarder(f *forward.Forwarder) {
+	c.forward = f
+}
+
+func (c *Env) GetForwarder() *forward.Forwarder {
+	return c.forward
+}
+
+func (c *Env) SetRecordTTL(ttl time.Duration) {
+	c.ttl.record = ttl
+}
+
+func (c *Env) GetRecordTTL() time.Duration {
+	return c.ttl.record
+}
+
+func (c *Env) SetHeadlessTTL(ttl time.Duration) {
+	c.ttl.headless = ttl
+}
+
+func (c *Env) GetHeadlessTTL() time.Duration {
+	return c.ttl.headless
+}
+
 func (c *Env) SetClient(client types.DiscoveryClientV1) {
 	c.client = client
 }
@@ -0,0 +1,176 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package forward
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/miekg/dns"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "dns:forward"
+
+	defaultTimeout = 2 * time.Second
+	resolvConf     = "/etc/resolv.conf"
+)
+
+// Forwarder sends queries of non-cluster domains to upstreams,
+// queries of stub zones are sent to zone servers instead
+type Forwarder struct {
+	upstreams []*Upstream
+	stubs     map[string][]*Upstream
+}
+
+type Opts struct {
+	// Upstreams list, resolv.conf nameservers are used if list is empty
+	Upstreams []string
+	// Stub zones list: zone=upstream, zone with several servers is repeated
+	Stubs []string
+	// Upstream query timeout
+	Timeout time.Duration
+}
+
+// Exchange forwards query to the first upstream answering it, upstreams are tried in order
+func (f *Forwarder) Exchange(r *dns.Msg) (*dns.Msg, error) {
+
+	if len(r.Question) == 0 {
+		return nil, errors.New("query has no question")
+	}
+
+	upstreams := f.Upstreams(r.Question[0].Name)
+	if len(upstreams) == 0 {
+		return nil, fmt.Errorf("no upstreams for %s", r.Question[0].Name)
+	}
+
+	var err error
+
+	for _, u := range upstreams {
+
+		resp, e := u.Exchange(r)
+		if e != nil {
+			log.V(logLevel).Errorf("%s:> exchange with %s err: %v", logPrefix, u, e)
+			err = e
+			continue
+		}
+
+		// upstream failure should not hide answer of the next upstream
+		if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+			log.V(logLevel).Debugf("%s:> upstream %s answered %s", logPrefix, u, dns.RcodeToString[resp.Rcode])
+			err = fmt.Errorf("upstream %s answered %s", u, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, err
+}
+
+// Upstreams returns servers of the longest stub zone domain belongs to or default upstreams
+func (f *Forwarder) Upstreams(name string) []*Upstream {
+
+	name = strings.ToLower(dns.Fqdn(name))
+
+	var zone string
+	for z := range f.stubs {
+		if dns.IsSubDomain(z, name) && len(z) > len(zone) {
+			zone = z
+		}
+	}
+
+	if zone != "" {
+		return f.stubs[zone]
+	}
+
+	return f.upstreams
+}
+
+func New(opts Opts) (*Forwarder, error) {
+
+	var (
+		f       = &Forwarder{stubs: make(map[string][]*Upstream, 0)}
+		timeout = opts.Timeout
+	)
+
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	servers := opts.Upstreams
+	if len(servers) == 0 {
+		servers = resolvConfServers()
+	}
+
+	for _, s := range servers {
+		u, err := ParseUpstream(s, timeout)
+		if err != nil {
+			return nil, err
+		}
+		f.upstreams = append(f.upstreams, u)
+	}
+
+	for _, s := range opts.Stubs {
+
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("stub zone %s: should be declared as zone=upstream", s)
+		}
+
+		u, err := ParseUpstream(kv[1], timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		zone := strings.ToLower(dns.Fqdn(kv[0]))
+		f.stubs[zone] = append(f.stubs[zone], u)
+	}
+
+	log.V(logLevel).Debugf("%s:> upstreams: %v, stub zones: %d", logPrefix, f.upstreams, len(f.stubs))
+
+	return f, nil
+}
+
+// resolvConfServers returns node nameservers, loopback servers are skipped as they may point to discovery itself
+func resolvConfServers() []string {
+
+	servers := make([]string, 0)
+
+	cfg, err := dns.ClientConfigFromFile(resolvConf)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:> read %s err: %v", logPrefix, resolvConf, err)
+		return servers
+	}
+
+	for _, s := range cfg.Servers {
+		ip := net.ParseIP(s)
+		if ip == nil || ip.IsLoopback() {
+			continue
+		}
+		servers = append(servers, net.JoinHostPort(s, cfg.Port))
+	}
+
+	return servers
+}
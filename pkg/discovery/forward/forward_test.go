@@ -0,0 +1,146 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package forward
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUpstream(t *testing.T) {
+
+	tests := []struct {
+		upstream   string
+		net        string
+		addr       string
+		serverName string
+		err        bool
+	}{
+		{"8.8.8.8", UpstreamUDP, "8.8.8.8:53", "", false},
+		{"tcp://8.8.8.8:5353", UpstreamTCP, "8.8.8.8:5353", "", false},
+		{"tls://1.1.1.1#cloudflare-dns.com", UpstreamTLS, "1.1.1.1:853", "cloudflare-dns.com", false},
+		{"tls://[2606:4700::1111]", UpstreamTLS, "[2606:4700::1111]:853", "2606:4700::1111", false},
+		{"https://1.1.1.1", "", "", "", true},
+		{"dns.google", "", "", "", true},
+	}
+
+	for _, tc := range tests {
+		u, err := ParseUpstream(tc.upstream, time.Second)
+		if tc.err {
+			assert.Error(t, err, "upstream should not be parsed: %s", tc.upstream)
+			continue
+		}
+		if !assert.NoError(t, err, tc.upstream) {
+			continue
+		}
+		assert.Equal(t, tc.net, u.Net, "net mismatch: %s", tc.upstream)
+		assert.Equal(t, tc.addr, u.Addr, "addr mismatch: %s", tc.upstream)
+		assert.Equal(t, tc.serverName, u.ServerName, "server name mismatch: %s", tc.upstream)
+	}
+}
+
+func TestForwarderUpstreams(t *testing.T) {
+
+	f, err := New(Opts{
+		Upstreams: []string{"8.8.8.8"},
+		Stubs:     []string{"corp.internal=10.0.0.10", "corp.internal=10.0.0.11", "eu.corp.internal=10.1.0.10"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	addrs := func(name string) []string {
+		list := make([]string, 0)
+		for _, u := range f.Upstreams(name) {
+			list = append(list, u.Addr)
+		}
+		return list
+	}
+
+	assert.Equal(t, []string{"8.8.8.8:53"}, addrs("example.com."))
+	assert.Equal(t, []string{"10.0.0.10:53", "10.0.0.11:53"}, addrs("dc.corp.internal."))
+	assert.Equal(t, []string{"10.1.0.10:53"}, addrs("dc.EU.corp.internal"))
+	assert.Equal(t, []string{"8.8.8.8:53"}, addrs("corp.internal.example.com."))
+
+	_, err = New(Opts{Stubs: []string{"corp.internal"}})
+	assert.Error(t, err, "stub zone without upstream should not be parsed")
+}
+
+func TestForwarderExchange(t *testing.T) {
+
+	failed, err := serve(dns.RcodeServerFailure)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer failed.Shutdown()
+
+	answered, err := serve(dns.RcodeSuccess)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer answered.Shutdown()
+
+	f, err := New(Opts{Upstreams: []string{failed.PacketConn.LocalAddr().String(), answered.PacketConn.LocalAddr().String()}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r := new(dns.Msg)
+	r.SetQuestion("example.com.", dns.TypeA)
+
+	resp, err := f.Exchange(r)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, dns.RcodeSuccess, resp.Rcode, "failed upstream answer should be skipped")
+	if assert.Len(t, resp.Answer, 1) {
+		assert.Equal(t, "10.0.0.1", resp.Answer[0].(*dns.A).A.String())
+	}
+}
+
+// serve starts UDP DNS server answering every query with rcode
+func serve(rcode int) (*dns.Server, error) {
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, NotifyStartedFunc: func() { close(started) }}
+	server.Handler = dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetRcode(r, rcode)
+		if rcode == dns.RcodeSuccess {
+			rr, _ := dns.NewRR(r.Question[0].Name + " 60 IN A 10.0.0.1")
+			m.Answer = append(m.Answer, rr)
+		}
+		w.WriteMsg(m)
+	})
+
+	go server.ActivateAndServe()
+	<-started
+
+	return server, nil
+}
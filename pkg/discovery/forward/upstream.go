@@ -0,0 +1,127 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package forward
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	UpstreamUDP = "udp"
+	UpstreamTCP = "tcp"
+	UpstreamTLS = "tcp-tls"
+
+	upstreamPort    = "53"
+	upstreamPortTLS = "853"
+)
+
+// Upstream is DNS server queries are forwarded to
+type Upstream struct {
+	// Net is transport: udp, tcp or tcp-tls for DNS over TLS
+	Net string
+	// Addr is server IP address and port
+	Addr string
+	// ServerName is verified in server certificate for DNS over TLS
+	ServerName string
+
+	client *dns.Client
+}
+
+// ParseUpstream parses upstream declaration: [udp|tcp|tls://]ip[:port][#server name],
+// upstream is IP address, so forwarding does not depend on other resolvers
+func ParseUpstream(s string, timeout time.Duration) (*Upstream, error) {
+
+	var (
+		u    = &Upstream{Net: UpstreamUDP}
+		port = upstreamPort
+		addr = strings.TrimSpace(s)
+	)
+
+	if i := strings.Index(addr, "://"); i >= 0 {
+		switch strings.ToLower(addr[:i]) {
+		case "udp", "dns":
+		case "tcp":
+			u.Net = UpstreamTCP
+		case "tls":
+			u.Net = UpstreamTLS
+			port = upstreamPortTLS
+		default:
+			return nil, fmt.Errorf("upstream %s: unsupported protocol %s", s, addr[:i])
+		}
+		addr = addr[i+3:]
+	}
+
+	if i := strings.Index(addr, "#"); i >= 0 {
+		u.ServerName = addr[i+1:]
+		addr = addr[:i]
+	}
+
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, p = strings.Trim(addr, "[]"), port
+	}
+
+	if net.ParseIP(host) == nil {
+		return nil, fmt.Errorf("upstream %s: invalid ip address %s", s, host)
+	}
+
+	u.Addr = net.JoinHostPort(host, p)
+
+	u.client = &dns.Client{Net: u.Net, Timeout: timeout}
+	if u.Net == UpstreamTLS {
+		if u.ServerName == "" {
+			u.ServerName = host
+		}
+		u.client.TLSConfig = &tls.Config{ServerName: u.ServerName}
+	}
+
+	return u, nil
+}
+
+// Exchange sends query to upstream, truncated UDP response is requested again over TCP
+func (u *Upstream) Exchange(r *dns.Msg) (*dns.Msg, error) {
+
+	resp, _, err := u.client.Exchange(r, u.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Truncated && u.Net == UpstreamUDP {
+		c := &dns.Client{Net: UpstreamTCP, Timeout: u.client.Timeout}
+		resp, _, err = c.Exchange(r, u.Addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (u *Upstream) String() string {
+	if u.Net == UpstreamTLS {
+		return fmt.Sprintf("tls://%s#%s", u.Addr, u.ServerName)
+	}
+	return fmt.Sprintf("%s://%s", u.Net, u.Addr)
+}
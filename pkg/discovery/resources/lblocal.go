@@ -21,24 +21,30 @@ package resources
 import (
 	"context"
 	"net"
-	"regexp"
+	"strings"
 	"time"
 
+	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/util"
+	"github.com/lastbackend/lastbackend/pkg/util/network"
 	"github.com/miekg/dns"
 )
 
+const lbLocalSuffix = ".lb.local"
+
 func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 
 	log.V(logLevel).Debugf("%s:lb.local:> dns request `lb.local.`", logPrefix)
 
+	envs.Get().GetState().DNS().Query()
+	envs.Get().GetState().DNS().Local()
+
 	var (
-		err error
-		v4  bool
-		rr  dns.RR
+		rr dns.RR
 	)
 
 	m := new(dns.Msg)
@@ -53,7 +59,11 @@ func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 
 		for _, q := range m.Question {
 
-			switch r.Question[0].Qtype {
+			if q.Name[len(q.Name)-1:] != "." {
+				q.Name += "."
+			}
+
+			switch q.Qtype {
 			case dns.TypeTXT:
 				log.V(logLevel).Debugf("%s:lb.local:> get txt type query", logPrefix)
 				t := new(dns.TXT)
@@ -61,81 +71,70 @@ func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 				m.Authoritative = true
 				m.Answer = append(m.Answer, t)
 				m.Extra = append(m.Extra, rr)
+			case dns.TypeSRV:
+				log.V(logLevel).Debugf("%s:lb.local:> get SRV type query", logPrefix)
+
+				answer, extra := lbLocalSRV(em, q.Name)
+				if len(answer) > 0 {
+					m.Authoritative = true
+				}
+
+				m.Answer = append(m.Answer, answer...)
+				m.Extra = append(m.Extra, extra...)
 			default:
 				log.V(logLevel).Debugf("%s:lb.local:> get unknown query type", logPrefix)
 				fallthrough
 			case dns.TypeAAAA, dns.TypeA:
 				log.V(logLevel).Debugf("%s:lb.local:> get A or AAAA type query", logPrefix)
 
-				if q.Name[len(q.Name)-1:] != "." {
-					q.Name += "."
-				}
-
 				log.V(logLevel).Debugf("%s:lb.local:> find ip addresses for domain: %s", logPrefix, q.Name)
 
 				// GenerateConfig A and AAAA records
-				ips := make([]net.IP, 0)
-
-				endpoint := util.Trim(q.Name, `.`)
-				item := envs.Get().GetCache().Endpoint().Get(endpoint)
-
-				if item != nil {
-					data := util.RemoveDuplicates(item)
-					ips, err = util.ConvertStringIPToNetIP(data)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				} else {
-
-					rg, _ := regexp.Compile("^(.+)\\.(.+)\\.lb\\.local$")
-					match := rg.FindStringSubmatch(endpoint)
-
-					if len(match) != 0 {
-
-						log.V(logLevel).Debugf("%s:lb.local:> find endpoint %s:%s", logPrefix, match[2], match[1])
-
-						e, err := em.Get(match[2], match[1])
-						if err != nil {
-							log.V(logLevel).Errorf("%s:lb.local:> get endpoint `%s` err: %v", endpoint, logPrefix, err)
-						}
-
-						if e != nil {
-							envs.Get().GetCache().Endpoint().Set(endpoint, []string{e.Spec.IP})
+				entry, found, err := lbLocalLookup(em, util.Trim(q.Name, `.`))
+				if err != nil {
+					// storage failure is not an answer, client should retry or ask another server
+					envs.Get().GetState().DNS().Fail()
+					m.Rcode = dns.RcodeServerFailure
+					continue
+				}
 
-							ips, err = util.ConvertStringIPToNetIP([]string{e.Spec.IP})
-							if err != nil {
-								log.Error(err)
-								return
-							}
-						}
+				if !found {
+					m.Authoritative = true
+					m.Rcode = dns.RcodeNameError
+					continue
+				}
 
-						if e == nil {
-							break
-						}
+				// external name service domain is answered with CNAME record and records of external name
+				if entry.Alias != types.EmptyString {
+					m.Authoritative = true
+					m.RecursionAvailable = true
+					m.Answer = append(m.Answer, lbLocalAliasRecord(q.Name, entry.Alias, lbLocalTTL(false)))
+					if q.Qtype != dns.TypeCNAME {
+						m.Answer = append(m.Answer, lbLocalAlias(em, entry.Alias, q.Qtype)...)
 					}
+					continue
+				}
 
+				ips, err := util.ConvertStringIPToNetIP(entry.Addresses)
+				if err != nil {
+					log.Error(err)
+					return
 				}
 
 				log.V(logLevel).Debugf("%s:lb.local:> ips list: %s for %s", logPrefix, ips, q.Name)
 
+				ttl := lbLocalTTL(entry.Headless)
 				for _, ip := range ips {
-					v4 = ip.To4() != nil
-
-					if v4 {
-						rr = new(dns.A)
-						rr.(*dns.A).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.A).A = ip.To4()
-					} else {
-						rr = new(dns.AAAA)
-						rr.(*dns.AAAA).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.AAAA).AAAA = ip
+
+					// dual stack endpoint answers A with IPv4 and AAAA with IPv6 address
+					if (q.Qtype == dns.TypeA && ip.To4() == nil) || (q.Qtype == dns.TypeAAAA && ip.To4() != nil) {
+						continue
 					}
 
 					m.Authoritative = true
 					m.RecursionAvailable = true
 					m.RecursionDesired = true
-					m.Answer = append(m.Answer, rr)
+					m.Answer = append(m.Answer, lbLocalAddressRecord(q.Name, ip, ttl))
 				}
 			}
 		}
@@ -157,3 +156,298 @@ func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 		log.V(logLevel).Errorf("%s:lb.local:> write message err: %v", logPrefix, err)
 	}
 }
+
+// lbLocalLookup returns addresses of domain, checks they are pods addresses and domain exists:
+// service domain is resolved to virtual addresses or to ready pods addresses for headless service,
+// external name service domain is alias of external name,
+// pod domain is resolved to pod address if pod is upstream of service,
+// error is returned on storage failure, it is not cached as unknown domain
+func lbLocalLookup(em *distribution.Endpoint, domain string) (*cache.EndpointEntry, bool, error) {
+
+	ec := envs.Get().GetCache().Endpoint()
+
+	if entry, ok := ec.Lookup(domain); ok {
+		if entry == nil {
+			return nil, false, nil
+		}
+		entry.Addresses = util.RemoveDuplicates(entry.Addresses)
+		return entry, true, nil
+	}
+
+	pod, e, err := lbLocalEndpoint(em, domain)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if e == nil {
+		if pod == types.EmptyString {
+			ec.SetNegative(domain)
+		}
+		return nil, false, nil
+	}
+
+	if pod == types.EmptyString {
+		switch true {
+		case e.Spec.ExternalName != types.EmptyString:
+			ec.SetAlias(domain, e.Spec.ExternalName)
+			return &cache.EndpointEntry{Alias: e.Spec.ExternalName}, true, nil
+		case !e.Spec.Headless:
+			ec.Set(domain, e.Spec.GetIPs())
+			return &cache.EndpointEntry{Addresses: e.Spec.GetIPs()}, true, nil
+		}
+
+		upstreams := lbLocalUpstreams(em, e)
+		ec.SetHeadless(domain, upstreams)
+		return &cache.EndpointEntry{Addresses: upstreams, Headless: true}, true, nil
+	}
+
+	// external addresses have no pods domains
+	if e.Spec.External {
+		return nil, false, nil
+	}
+
+	// pod records are not cached as pods come and go without endpoint changes
+	ip := lbLocalPodIP(pod)
+	for _, u := range lbLocalUpstreams(em, e) {
+		if ip.Equal(net.ParseIP(u)) {
+			return &cache.EndpointEntry{Addresses: []string{u}, Headless: true}, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// lbLocalAlias returns records of external name service domain is alias of,
+// cluster domain is resolved locally, other domains are resolved by upstreams
+func lbLocalAlias(em *distribution.Endpoint, name string, qtype uint16) []dns.RR {
+
+	var (
+		answer = make([]dns.RR, 0)
+		fqdn   = dns.Fqdn(name)
+	)
+
+	if !strings.HasSuffix(util.Trim(name, `.`), lbLocalSuffix) {
+		r := new(dns.Msg)
+		r.SetQuestion(fqdn, qtype)
+
+		m := forwardExchange(r)
+		if m.Rcode != dns.RcodeSuccess {
+			return answer
+		}
+
+		return append(answer, m.Answer...)
+	}
+
+	// alias of another alias is not followed to avoid lookup loops
+	entry, found, err := lbLocalLookup(em, util.Trim(name, `.`))
+	if err != nil || !found || entry.Alias != types.EmptyString {
+		return answer
+	}
+
+	ttl := lbLocalTTL(entry.Headless)
+	for _, addr := range entry.Addresses {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+
+		if (qtype == dns.TypeA && ip.To4() == nil) || (qtype == dns.TypeAAAA && ip.To4() != nil) {
+			continue
+		}
+
+		answer = append(answer, lbLocalAddressRecord(fqdn, ip, ttl))
+	}
+
+	return answer
+}
+
+// lbLocalSRV returns records of named service port: _<port name>._<protocol>.<service>.<namespace>.lb.local,
+// service records point to service domain, headless service records point to every pod domain
+func lbLocalSRV(em *distribution.Endpoint, name string) ([]dns.RR, []dns.RR) {
+
+	var (
+		answer = make([]dns.RR, 0)
+		extra  = make([]dns.RR, 0)
+	)
+
+	labels := strings.SplitN(util.Trim(name, `.`), ".", 3)
+	if len(labels) != 3 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return answer, extra
+	}
+
+	port, proto := labels[0][1:], strings.ToLower(labels[1][1:])
+
+	pod, e, _ := lbLocalEndpoint(em, labels[2])
+	if e == nil || pod != types.EmptyString {
+		return answer, extra
+	}
+
+	// SRV target should not be an alias, external name is the target
+	var host = dns.Fqdn(e.Spec.Domain)
+	if e.Spec.ExternalName != types.EmptyString {
+		host = dns.Fqdn(e.Spec.ExternalName)
+	}
+
+	var upstreams []string
+	if e.Spec.Headless {
+		upstreams = lbLocalUpstreams(em, e)
+	}
+
+	ttl := lbLocalTTL(e.Spec.Headless)
+
+	for p, pn := range e.Spec.PortNames {
+
+		if pn != port {
+			continue
+		}
+
+		pm, ok := e.Spec.PortMap[p]
+		if !ok {
+			continue
+		}
+
+		target, tp, err := network.ParsePortMap(pm)
+		if err != nil {
+			continue
+		}
+
+		// port mapped with any protocol serves both tcp and udp
+		if tp != proto && tp != "*" {
+			continue
+		}
+
+		if !e.Spec.Headless {
+			answer = append(answer, lbLocalServiceRecord(name, host, p, ttl))
+			for _, ip := range e.Spec.GetIPs() {
+				extra = append(extra, lbLocalAddressRecord(host, net.ParseIP(ip), ttl))
+			}
+			continue
+		}
+
+		for _, u := range upstreams {
+			host := dns.Fqdn(lbLocalPodName(u) + "." + e.Spec.Domain)
+			answer = append(answer, lbLocalServiceRecord(name, host, target, ttl))
+			extra = append(extra, lbLocalAddressRecord(host, net.ParseIP(u), ttl))
+		}
+	}
+
+	return answer, extra
+}
+
+// lbLocalEndpoint returns endpoint of domain: <service>.<namespace>.lb.local
+// or <pod>.<service>.<namespace>.lb.local, pod name is returned if domain is pod domain
+func lbLocalEndpoint(em *distribution.Endpoint, domain string) (string, *types.Endpoint, error) {
+
+	pod, service, namespace := lbLocalName(domain)
+	if service == types.EmptyString {
+		return pod, nil, nil
+	}
+
+	log.V(logLevel).Debugf("%s:lb.local:> find endpoint %s:%s", logPrefix, namespace, service)
+
+	e, err := em.Get(namespace, service)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:lb.local:> get endpoint `%s` err: %v", logPrefix, domain, err)
+		return pod, nil, err
+	}
+
+	return pod, e, nil
+}
+
+// lbLocalUpstreams returns ready pods addresses of endpoint
+func lbLocalUpstreams(em *distribution.Endpoint, e *types.Endpoint) []string {
+
+	m, err := em.ManifestGet(e.SelfLink().String())
+	if err != nil {
+		log.V(logLevel).Errorf("%s:lb.local:> get endpoint `%s` manifest err: %v", logPrefix, e.SelfLink().String(), err)
+		return nil
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	return m.Upstreams
+}
+
+// lbLocalName splits domain into pod, service and namespace names,
+// first label is pod name if it is pod address with dashes instead of separators
+func lbLocalName(domain string) (string, string, string) {
+
+	if !strings.HasSuffix(domain, lbLocalSuffix) {
+		return types.EmptyString, types.EmptyString, types.EmptyString
+	}
+
+	labels := strings.Split(strings.TrimSuffix(domain, lbLocalSuffix), ".")
+
+	switch true {
+	case len(labels) > 2 && lbLocalPodIP(labels[0]) != nil:
+		return labels[0], labels[1], strings.Join(labels[2:], ".")
+	case len(labels) > 1:
+		return types.EmptyString, labels[0], strings.Join(labels[1:], ".")
+	}
+
+	return types.EmptyString, types.EmptyString, types.EmptyString
+}
+
+// lbLocalPodName returns pod domain label: 10-0-0-1 for 10.0.0.1 and fd00--1 for fd00::1
+func lbLocalPodName(ip string) string {
+	if strings.Contains(ip, ":") {
+		return strings.Replace(ip, ":", "-", -1)
+	}
+	return strings.Replace(ip, ".", "-", -1)
+}
+
+// lbLocalPodIP parses pod address from pod domain label
+func lbLocalPodIP(name string) net.IP {
+
+	if ip := net.ParseIP(strings.Replace(name, "-", ".", -1)); ip != nil && ip.To4() != nil {
+		return ip
+	}
+
+	if ip := net.ParseIP(strings.Replace(name, "-", ":", -1)); ip != nil && ip.To4() == nil {
+		return ip
+	}
+
+	return nil
+}
+
+// lbLocalTTL returns answer records ttl in seconds, headless records change with pods and live shorter
+func lbLocalTTL(headless bool) uint32 {
+	if headless {
+		return uint32(envs.Get().GetHeadlessTTL() / time.Second)
+	}
+	return uint32(envs.Get().GetRecordTTL() / time.Second)
+}
+
+func lbLocalAddressRecord(name string, ip net.IP, ttl uint32) dns.RR {
+
+	if ip.To4() != nil {
+		rr := new(dns.A)
+		rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl}
+		rr.A = ip.To4()
+		return rr
+	}
+
+	rr := new(dns.AAAA)
+	rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: ttl}
+	rr.AAAA = ip
+	return rr
+}
+
+func lbLocalAliasRecord(name, target string, ttl uint32) dns.RR {
+	rr := new(dns.CNAME)
+	rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: ttl}
+	rr.Target = dns.Fqdn(target)
+	return rr
+}
+
+func lbLocalServiceRecord(name, target string, port uint16, ttl uint32) dns.RR {
+	rr := new(dns.SRV)
+	rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: ttl}
+	rr.Priority = 0
+	rr.Weight = 10
+	rr.Port = port
+	rr.Target = target
+	return rr
+}
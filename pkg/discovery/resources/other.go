@@ -21,71 +21,41 @@ package resources
 import (
 	"time"
 
+	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/miekg/dns"
-	"net"
 )
 
+// other forwards queries of non-cluster domains to upstreams, responses are cached for their TTLs
 func other(w dns.ResponseWriter, r *dns.Msg) {
 
 	log.V(logLevel).Debugf("%s:other:> dns request `.`", logPrefix)
 
-	var (
-		v4 bool
-		rr dns.RR
-	)
+	envs.Get().GetState().DNS().Query()
+	forward(w, r)
+}
 
-	m := new(dns.Msg)
-	m.SetReply(r)
-	m.Compress = false
+func forward(w dns.ResponseWriter, r *dns.Msg) {
+
+	var m *dns.Msg
 
 	switch r.Opcode {
 	case dns.OpcodeQuery:
 		log.V(logLevel).Debugf("%s:other:> dns.OpcodeQuery", logPrefix)
+		m = forwardExchange(r)
+	default:
+		log.V(logLevel).Debugf("%s:other:> dns.OpcodeUpdate", logPrefix)
+		m = new(dns.Msg)
+		m.SetRcode(r, dns.RcodeNotImplemented)
+	}
 
-		for _, q := range m.Question {
-
-			switch r.Question[0].Qtype {
-			case dns.TypeTXT:
-				log.V(logLevel).Debugf("%s:other:> get txt type query", logPrefix)
-				t := new(dns.TXT)
-				t.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 0}
-				m.Answer = append(m.Answer, t)
-				m.Extra = append(m.Extra, rr)
-			default:
-				log.V(logLevel).Debugf("%s:other:> get unknown query type", logPrefix)
-				fallthrough
-			case dns.TypeAAAA, dns.TypeA:
-				log.V(logLevel).Debugf("%s:other:> get A or AAAA type query", logPrefix)
-
-				if q.Name[len(q.Name)-1:] != "." {
-					q.Name += "."
-				}
-
-				// GenerateConfig A and AAAA records
-				ips := make([]net.IP, 0)
-
-				log.V(logLevel).Debugf("%s:other:> ips list: %#v for %s", logPrefix, ips, q.Name)
-
-				for _, ip := range ips {
-					v4 = ip.To4() != nil
-
-					if v4 {
-						rr = new(dns.A)
-						rr.(*dns.A).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.A).A = ip.To4()
-					} else {
-						rr = new(dns.AAAA)
-						rr.(*dns.AAAA).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.AAAA).AAAA = ip
-					}
-
-					m.Answer = append(m.Answer, rr)
-				}
-			}
+	// upstream response may not fit into client UDP buffer, client retries truncated query over TCP
+	if w.RemoteAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+		if opt := r.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
 		}
-	case dns.OpcodeUpdate:
-		log.V(logLevel).Debugf("%s:other:> dns.OpcodeUpdate", logPrefix)
+		m.Truncate(size)
 	}
 
 	if r.IsTsig() != nil {
@@ -102,3 +72,41 @@ func other(w dns.ResponseWriter, r *dns.Msg) {
 		log.V(logLevel).Errorf("%s:other:> write message err: %v", logPrefix, err)
 	}
 }
+
+// forwardExchange returns cached response of query or forwards query to upstreams,
+// server failure response is returned if no upstream answered
+func forwardExchange(r *dns.Msg) *dns.Msg {
+
+	var (
+		stats = envs.Get().GetState().DNS()
+		cache = envs.Get().GetCache().Response()
+		fwd   = envs.Get().GetForwarder()
+	)
+
+	if m := cache.Get(r); m != nil {
+		log.V(logLevel).Debugf("%s:other:> response found in cache", logPrefix)
+		stats.CacheHit()
+		return m
+	}
+
+	if fwd == nil {
+		m := new(dns.Msg)
+		m.SetRcode(r, dns.RcodeServerFailure)
+		return m
+	}
+
+	stats.Forward()
+
+	m, err := fwd.Exchange(r)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:other:> forward query err: %v", logPrefix, err)
+		stats.Fail()
+
+		m = new(dns.Msg)
+		m.SetRcode(r, dns.RcodeServerFailure)
+		return m
+	}
+
+	cache.Set(m)
+	return m
+}
@@ -0,0 +1,197 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package resources
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util"
+	"github.com/miekg/dns"
+)
+
+const (
+	ptrSuffixIPv4 = ".in-addr.arpa"
+	ptrSuffixIPv6 = ".ip6.arpa"
+)
+
+func ptr(w dns.ResponseWriter, r *dns.Msg) {
+
+	log.V(logLevel).Debugf("%s:ptr:> dns request `arpa.`", logPrefix)
+
+	envs.Get().GetState().DNS().Query()
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Compress = false
+
+	em := distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
+
+	switch r.Opcode {
+	case dns.OpcodeQuery:
+		log.V(logLevel).Debugf("%s:ptr:> dns.OpcodeQuery", logPrefix)
+
+		for _, q := range m.Question {
+
+			if q.Qtype != dns.TypePTR {
+				log.V(logLevel).Debugf("%s:ptr:> get unknown query type", logPrefix)
+				continue
+			}
+
+			if q.Name[len(q.Name)-1:] != "." {
+				q.Name += "."
+			}
+
+			ip := ptrAddress(q.Name)
+			if ip == nil {
+				log.V(logLevel).Debugf("%s:ptr:> invalid reverse domain: %s", logPrefix, q.Name)
+				continue
+			}
+
+			names, headless := ptrLookup(em, ip)
+
+			log.V(logLevel).Debugf("%s:ptr:> names list: %s for %s", logPrefix, names, q.Name)
+
+			ttl := lbLocalTTL(headless)
+			for _, name := range names {
+				rr := new(dns.PTR)
+				rr.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: ttl}
+				rr.Ptr = name
+
+				m.Authoritative = true
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+	case dns.OpcodeUpdate:
+		log.V(logLevel).Debugf("%s:ptr:> dns.OpcodeUpdate", logPrefix)
+	}
+
+	// reverse domains of addresses outside of cluster are resolved by upstreams
+	if r.Opcode == dns.OpcodeQuery && len(m.Answer) == 0 {
+		forward(w, r)
+		return
+	}
+
+	envs.Get().GetState().DNS().Local()
+
+	if r.IsTsig() != nil {
+		if w.TsigStatus() == nil {
+			m.SetTsig(r.Extra[len(r.Extra)-1].(*dns.TSIG).Hdr.Name, dns.HmacMD5, 300, time.Now().Unix())
+		} else {
+			log.V(logLevel).Errorf("%s:ptr:> tsig status err: %s", logPrefix, w.TsigStatus())
+		}
+	}
+
+	log.V(logLevel).Debugf("%s:ptr:> send message info  %#v", logPrefix, m)
+
+	if err := w.WriteMsg(m); err != nil {
+		log.V(logLevel).Errorf("%s:ptr:> write message err: %v", logPrefix, err)
+	}
+}
+
+// ptrLookup returns domains of address: endpoint domain for endpoint address
+// or pod domains of every service pod serves, external upstreams are skipped
+func ptrLookup(em *distribution.Endpoint, ip net.IP) ([]string, bool) {
+
+	names := make([]string, 0)
+
+	el, err := em.List(nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ptr:> get endpoints err: %v", logPrefix, err)
+		return names, false
+	}
+
+	for _, e := range el.Items {
+		for _, addr := range e.Spec.GetIPs() {
+			if ip.Equal(net.ParseIP(addr)) {
+				return append(names, dns.Fqdn(e.Spec.Domain)), false
+			}
+		}
+	}
+
+	mf, err := em.ManifestMap()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ptr:> get endpoints manifests err: %v", logPrefix, err)
+		return names, true
+	}
+
+	for _, m := range mf.Items {
+		// external addresses are not pods and are resolved by upstreams
+		if m.External {
+			continue
+		}
+
+		for _, u := range m.Upstreams {
+			if ip.Equal(net.ParseIP(u)) {
+				names = append(names, dns.Fqdn(lbLocalPodName(u)+"."+m.Domain))
+			}
+		}
+	}
+
+	return names, true
+}
+
+// ptrAddress parses address from reverse domain: 1.0.0.10.in-addr.arpa or nibbles of IPv6 address in ip6.arpa
+func ptrAddress(name string) net.IP {
+
+	name = strings.ToLower(util.Trim(name, `.`))
+
+	switch true {
+	case strings.HasSuffix(name, ptrSuffixIPv4):
+		labels := strings.Split(strings.TrimSuffix(name, ptrSuffixIPv4), ".")
+		if len(labels) != net.IPv4len {
+			return nil
+		}
+
+		for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+			labels[i], labels[j] = labels[j], labels[i]
+		}
+
+		ip := net.ParseIP(strings.Join(labels, "."))
+		if ip == nil || ip.To4() == nil {
+			return nil
+		}
+		return ip
+	case strings.HasSuffix(name, ptrSuffixIPv6):
+		labels := strings.Split(strings.TrimSuffix(name, ptrSuffixIPv6), ".")
+		if len(labels) != net.IPv6len*2 {
+			return nil
+		}
+
+		var addr string
+		for i := len(labels) - 1; i >= 0; i-- {
+			if len(labels[i]) != 1 {
+				return nil
+			}
+			addr += labels[i]
+			if i%4 == 0 && i != 0 {
+				addr += ":"
+			}
+		}
+
+		return net.ParseIP(addr)
+	}
+
+	return nil
+}
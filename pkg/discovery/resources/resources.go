@@ -28,5 +28,8 @@ const (
 )
 
 var Map = map[string]dns.HandlerFunc{
-	"lb.local.": lbLocal,
+	".":             other,
+	"lb.local.":     lbLocal,
+	"in-addr.arpa.": ptr,
+	"ip6.arpa.":     ptr,
 }
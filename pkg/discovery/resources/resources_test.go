@@ -0,0 +1,69 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package resources
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLbLocalName(t *testing.T) {
+
+	tests := []struct {
+		domain    string
+		pod       string
+		service   string
+		namespace string
+	}{
+		{"web.demo.lb.local", "", "web", "demo"},
+		{"web.demo.team.lb.local", "", "web", "demo.team"},
+		{"10-0-0-2.web.demo.lb.local", "10-0-0-2", "web", "demo"},
+		{"fd00--2.web.demo.lb.local", "fd00--2", "web", "demo"},
+		{"demo.lb.local", "", "", ""},
+		{"web.demo.example.com", "", "", ""},
+	}
+
+	for _, tc := range tests {
+		pod, service, namespace := lbLocalName(tc.domain)
+		assert.Equal(t, tc.pod, pod, "pod name mismatch: %s", tc.domain)
+		assert.Equal(t, tc.service, service, "service name mismatch: %s", tc.domain)
+		assert.Equal(t, tc.namespace, namespace, "namespace name mismatch: %s", tc.domain)
+	}
+}
+
+func TestLbLocalPodName(t *testing.T) {
+	for _, ip := range []string{"10.0.0.2", "fd00::2"} {
+		assert.True(t, net.ParseIP(ip).Equal(lbLocalPodIP(lbLocalPodName(ip))), "pod address mismatch: %s", ip)
+	}
+}
+
+func TestPtrAddress(t *testing.T) {
+
+	for _, ip := range []string{"10.0.0.2", "fd00::2"} {
+		name, err := dns.ReverseAddr(ip)
+		assert.NoError(t, err)
+		assert.True(t, net.ParseIP(ip).Equal(ptrAddress(name)), "reverse address mismatch: %s", name)
+	}
+
+	assert.Nil(t, ptrAddress("0.10.in-addr.arpa."), "partial reverse domain should not be parsed")
+	assert.Nil(t, ptrAddress("web.demo.lb.local."), "domain should not be parsed")
+}
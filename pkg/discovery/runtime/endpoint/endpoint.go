@@ -20,6 +20,8 @@ package endpoint
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
@@ -32,44 +34,215 @@ const (
 	logPrefix = "runtime:endpoint"
 )
 
-func Watch(ctx context.Context) {
+const (
+	watchRetryMin = time.Second
+	watchRetryMax = 30 * time.Second
+)
+
+// Restore warms endpoint cache with all endpoints domains before queries are served
+// and starts watching endpoints changes from the same storage revision
+func Restore(ctx context.Context) error {
+
+	domains := make(map[string]string)
+
+	rev, mrev, err := restore(ctx, domains)
+	if err != nil {
+		return err
+	}
+
+	go watch(ctx, rev, mrev, domains)
+
+	return nil
+}
 
-	log.V(logLevel).Debugf("%s:restore:> watch change endpoint start", logPrefix)
+// Watch updates or evicts endpoint cache entries on endpoints and manifests changes,
+// watch is restarted with backoff after storage errors and cache is restored before restart
+func Watch(ctx context.Context, rev, mrev *int64) {
+	watch(ctx, rev, mrev, make(map[string]string))
+}
+
+func watch(ctx context.Context, rev, mrev *int64, domains map[string]string) {
+
+	var delay = watchRetryMin
+
+	for {
+
+		started := time.Now()
+
+		err := watchEvents(ctx, rev, mrev, domains)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > watchRetryMax {
+			delay = watchRetryMin
+		}
+
+		log.Errorf("%s:watch:> watch endpoints err: %v, restart in %s", logPrefix, err, delay.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if delay *= 2; delay > watchRetryMax {
+			delay = watchRetryMax
+		}
+
+		// events are missed while watch is restarted, so cache is restored from storage
+		r, mr, err := restore(ctx, domains)
+		if err != nil {
+			continue
+		}
+
+		rev, mrev = r, mr
+	}
+}
+
+// restore lists endpoints and manifests to cache and returns storage revisions to watch changes from
+func restore(ctx context.Context, domains map[string]string) (*int64, *int64, error) {
+
+	log.V(logLevel).Debugf("%s:restore:> restore endpoints cache", logPrefix)
 
 	var (
 		em    = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
 		cache = envs.Get().GetCache().Endpoint()
-		event = make(chan types.EndpointEvent)
 	)
 
+	el, err := em.List(nil)
+	if err != nil {
+		log.Errorf("%s:restore:> get endpoints list err: %v", logPrefix, err)
+		return nil, nil, err
+	}
+
+	ml, err := em.ManifestMap()
+	if err != nil {
+		log.Errorf("%s:restore:> get endpoints manifests err: %v", logPrefix, err)
+		return nil, nil, err
+	}
+
+	cache.Clear()
+
+	for k := range domains {
+		delete(domains, k)
+	}
+
+	for _, e := range el.Items {
+		setEndpoint(e)
+		domains[e.SelfLink().String()] = e.Spec.Domain
+	}
+
+	for _, m := range ml.Items {
+		if !m.Headless {
+			continue
+		}
+		cache.SetHeadless(m.Domain, m.Upstreams)
+	}
+
+	log.V(logLevel).Debugf("%s:restore:> restored %d endpoints domains", logPrefix, cache.Count())
+
+	return &el.Storage.Revision, &ml.Storage.Revision, nil
+}
+
+// watchEvents applies endpoints and manifests events to cache until one of watches is stopped
+func watchEvents(ctx context.Context, rev, mrev *int64, domains map[string]string) error {
+
+	log.V(logLevel).Debugf("%s:watch:> watch change endpoint start", logPrefix)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		em       = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
+		cache    = envs.Get().GetCache().Endpoint()
+		event    = make(chan types.EndpointEvent)
+		manifest = make(chan types.EndpointManifestEvent)
+		done     = make(chan error, 2)
+	)
+
+	go func() {
+		done <- em.ManifestWatch(manifest, mrev)
+	}()
+
 	go func() {
-		for {
-			select {
-			case e := <-event:
-				{
+		done <- em.Watch(event, rev)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-done:
+			if err == nil {
+				err = errors.New("watch is closed")
+			}
+			return err
+		case e := <-event:
+			{
 
-					if e.Data == nil {
-						continue
+				// deleted endpoint data is not available if previous revision is compacted,
+				// domain is evicted by endpoint self link then
+				if e.Data == nil {
+					if domain, ok := domains[e.SelfLink]; ok && e.Action == types.EventActionDelete {
+						cache.Del(domain)
+						delete(domains, e.SelfLink)
 					}
+					continue
+				}
+
+				endpoint := e.Data
 
-					endpoint := e.Data
-
-					switch e.Action {
-					case types.EventActionCreate:
-						fallthrough
-					case types.EventActionUpdate:
-						cache.Del(endpoint.Spec.Domain)
-						envs.Get().GetCache().Endpoint().Set(endpoint.Spec.Domain, []string{endpoint.Spec.IP})
-						continue
-					case types.EventActionDelete:
-						cache.Del(endpoint.Spec.Domain)
-						continue
+				switch e.Action {
+				case types.EventActionCreate:
+					fallthrough
+				case types.EventActionUpdate:
+					if domain, ok := domains[endpoint.SelfLink().String()]; ok {
+						cache.Del(domain)
 					}
+					cache.Del(endpoint.Spec.Domain)
+					setEndpoint(endpoint)
+					domains[endpoint.SelfLink().String()] = endpoint.Spec.Domain
+					continue
+				case types.EventActionDelete:
+					cache.Del(endpoint.Spec.Domain)
+					delete(domains, endpoint.SelfLink().String())
+					continue
+				}
 
+			}
+		case e := <-manifest:
+			{
+
+				if e.Data == nil || !e.Data.Headless {
+					continue
+				}
+
+				switch e.Action {
+				case types.EventActionCreate:
+					fallthrough
+				case types.EventActionUpdate:
+					cache.SetHeadless(e.Data.Domain, e.Data.Upstreams)
+					continue
+				case types.EventActionDelete:
+					cache.Del(e.Data.Domain)
+					continue
 				}
 			}
 		}
-	}()
+	}
+}
+
+// setEndpoint stores endpoint domain addresses or external name,
+// headless endpoint domain is resolved to pods addresses from manifest
+func setEndpoint(e *types.Endpoint) {
+
+	cache := envs.Get().GetCache().Endpoint()
 
-	go em.Watch(event, nil)
+	switch true {
+	case e.Spec.ExternalName != types.EmptyString:
+		cache.SetAlias(e.Spec.Domain, e.Spec.ExternalName)
+	case !e.Spec.Headless:
+		cache.Set(e.Spec.Domain, e.Spec.GetIPs())
+	}
 }
@@ -19,11 +19,14 @@
 package state
 
 import (
+	"sync/atomic"
+
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type State struct {
 	discovery *DiscoveryState
+	dns       *DNSState
 }
 
 type DiscoveryState struct {
@@ -31,14 +34,59 @@ type DiscoveryState struct {
 	Status types.DiscoveryStatus
 }
 
+// DNSState counts DNS server queries, counters are updated concurrently by handlers
+type DNSState struct {
+	queries   uint64
+	local     uint64
+	forwarded uint64
+	failed    uint64
+	hits      uint64
+}
+
 func (s *State) Discovery() *DiscoveryState {
 	return s.discovery
 }
 
+func (s *State) DNS() *DNSState {
+	return s.dns
+}
+
+func (s *DNSState) Query() {
+	atomic.AddUint64(&s.queries, 1)
+}
+
+func (s *DNSState) Local() {
+	atomic.AddUint64(&s.local, 1)
+}
+
+func (s *DNSState) Forward() {
+	atomic.AddUint64(&s.forwarded, 1)
+}
+
+func (s *DNSState) Fail() {
+	atomic.AddUint64(&s.failed, 1)
+}
+
+func (s *DNSState) CacheHit() {
+	atomic.AddUint64(&s.hits, 1)
+}
+
+// Stats returns counters snapshot
+func (s *DNSState) Stats() types.DiscoveryDNSStats {
+	return types.DiscoveryDNSStats{
+		Queries:   atomic.LoadUint64(&s.queries),
+		Local:     atomic.LoadUint64(&s.local),
+		Forwarded: atomic.LoadUint64(&s.forwarded),
+		Failed:    atomic.LoadUint64(&s.failed),
+		CacheHits: atomic.LoadUint64(&s.hits),
+	}
+}
+
 func New() *State {
 
 	state := State{
 		discovery: new(DiscoveryState),
+		dns:       new(DNSState),
 	}
 
 	return &state
@@ -68,7 +68,7 @@ func (n *Config) Get(namespace, name string) (*types.Config, error) {
 	return item, nil
 }
 
-func (n *Config) List(filter string) (*types.ConfigList, error) {
+func (n *Config) List(filter string, opts *types.ListOptions) (*types.ConfigList, error) {
 
 	var f string
 
@@ -79,7 +79,7 @@ func (n *Config) List(filter string) (*types.ConfigList, error) {
 		f = n.storage.Filter().Config().ByNamespace(filter)
 	}
 
-	err := n.storage.List(n.context, n.storage.Collection().Config(), f, list, nil)
+	err := n.storage.List(n.context, n.storage.Collection().Config(), f, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> get configs list by namespace err: %s", logConfigPrefix, err)
 		return list, err
@@ -107,12 +107,12 @@ func (n *Config) Create(namespace *types.Namespace, config *types.Config) (*type
 	return config, nil
 }
 
-func (n *Config) Update(config *types.Config) (*types.Config, error) {
+func (n *Config) Update(config *types.Config, opts *types.UpdateOptions) (*types.Config, error) {
 
 	log.V(logLevel).Debugf("%s:update:> update config %s", logConfigPrefix, config.Meta.Name)
 
 	if err := n.storage.Set(n.context, n.storage.Collection().Config(),
-		config.SelfLink().String(), config, nil); err != nil {
+		config.SelfLink().String(), config, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update config err: %s", logConfigPrefix, err)
 		return nil, err
 	}
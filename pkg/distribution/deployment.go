@@ -123,14 +123,14 @@ func (d *Deployment) Insert(dep *types.Deployment) error {
 }
 
 // ListByService - list of deployments by service
-func (d *Deployment) ListByNamespace(namespace string) (*types.DeploymentList, error) {
+func (d *Deployment) ListByNamespace(namespace string, opts *types.ListOptions) (*types.DeploymentList, error) {
 
 	log.V(logLevel).Debugf("%s:listbynamespace:> in namespace: %s", namespace)
 
 	q := d.storage.Filter().Deployment().ByNamespace(namespace)
 	dl := types.NewDeploymentList()
 
-	err := d.storage.List(d.context, d.storage.Collection().Deployment(), q, dl, nil)
+	err := d.storage.List(d.context, d.storage.Collection().Deployment(), q, dl, listOpts(opts))
 	if err != nil {
 		log.Errorf("%s:listbynamespace:> in namespace: %s err: %v", logDeploymentPrefix, namespace, err)
 		return nil, err
@@ -140,14 +140,14 @@ func (d *Deployment) ListByNamespace(namespace string) (*types.DeploymentList, e
 }
 
 // ListByService - list of deployments by service
-func (d *Deployment) ListByService(namespace, service string) (*types.DeploymentList, error) {
+func (d *Deployment) ListByService(namespace, service string, opts *types.ListOptions) (*types.DeploymentList, error) {
 
 	log.V(logLevel).Debugf("%s:listbyservice:> in namespace: %s and service %s", logDeploymentPrefix, namespace, service)
 
 	q := d.storage.Filter().Deployment().ByService(namespace, service)
 	dl := types.NewDeploymentList()
 
-	err := d.storage.List(d.context, d.storage.Collection().Deployment(), q, dl, nil)
+	err := d.storage.List(d.context, d.storage.Collection().Deployment(), q, dl, listOpts(opts))
 	if err != nil {
 		log.Errorf("%s:listbyservice:> in namespace: %s and service %s err: %v", logDeploymentPrefix, namespace, service, err)
 		return nil, err
@@ -157,12 +157,12 @@ func (d *Deployment) ListByService(namespace, service string) (*types.Deployment
 }
 
 // Update deployment
-func (d *Deployment) Update(dt *types.Deployment) error {
+func (d *Deployment) Update(dt *types.Deployment, opts *types.UpdateOptions) error {
 
 	log.V(logLevel).Debugf("%s:update:> update deployment %s", logDeploymentPrefix, dt.Meta.Name)
 
 	if err := d.storage.Set(d.context, d.storage.Collection().Deployment(),
-		dt.SelfLink().String(), dt, nil); err != nil {
+		dt.SelfLink().String(), dt, updateOpts(opts)); err != nil {
 		log.Errorf("%s:update:> update for deployment %s err: %v", logDeploymentPrefix, dt.Meta.Name, err)
 		return err
 	}
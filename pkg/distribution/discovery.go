@@ -38,10 +38,10 @@ type Discovery struct {
 	storage storage.Storage
 }
 
-func (n *Discovery) List() (*types.DiscoveryList, error) {
+func (n *Discovery) List(opts *types.ListOptions) (*types.DiscoveryList, error) {
 	list := types.NewDiscoveryList()
 
-	if err := n.storage.List(n.context, n.storage.Collection().Discovery().Info(), "", list, nil); err != nil {
+	if err := n.storage.List(n.context, n.storage.Collection().Discovery().Info(), "", list, listOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:list:> get discovery list err: %v", logDiscoveryPrefix, err)
 		return nil, err
 	}
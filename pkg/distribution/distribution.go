@@ -18,4 +18,39 @@
 
 package distribution
 
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	stypes "github.com/lastbackend/lastbackend/pkg/storage/types"
+)
+
 const logLevel = 4
+
+func updateOpts(opts *types.UpdateOptions) *stypes.Opts {
+
+	if opts == nil || opts.Revision == nil {
+		return nil
+	}
+
+	o := storage.GetOpts()
+	o.Rev = opts.Revision
+
+	return o
+}
+
+func listOpts(opts *types.ListOptions) *stypes.Opts {
+
+	if opts == nil {
+		return nil
+	}
+
+	o := storage.GetOpts()
+	o.Limit = opts.Limit
+	o.Continue = opts.Continue
+
+	if !opts.Selector.Empty() {
+		o.Match = opts.Selector.Match
+	}
+
+	return o
+}
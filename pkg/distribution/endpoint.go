@@ -59,12 +59,26 @@ func (e *Endpoint) Get(namespace, service string) (*types.Endpoint, error) {
 	return item, nil
 }
 
-func (e *Endpoint) ListByNamespace(namespace string) (*types.EndpointList, error) {
+func (e *Endpoint) List(opts *types.ListOptions) (*types.EndpointList, error) {
+	log.V(logLevel).Debugf("%s:list:> get endpoints list", logEndpointPrefix)
+
+	list := types.NewEndpointList()
+
+	err := e.storage.List(e.context, e.storage.Collection().Endpoint(), "", list, listOpts(opts))
+	if err != nil {
+		log.Errorf("%s:list:> get endpoints list err: %v", logEndpointPrefix, err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (e *Endpoint) ListByNamespace(namespace string, opts *types.ListOptions) (*types.EndpointList, error) {
 	log.V(logLevel).Debugf("%s:listbynamespace:> in namespace: %s", namespace)
 
 	list := types.NewEndpointList()
 
-	err := e.storage.List(e.context, e.storage.Collection().Endpoint(), e.storage.Filter().Endpoint().ByNamespace(namespace), list, nil)
+	err := e.storage.List(e.context, e.storage.Collection().Endpoint(), e.storage.Filter().Endpoint().ByNamespace(namespace), list, listOpts(opts))
 	if err != nil {
 		log.Errorf("%s:listbynamespace:> in namespace: %s err: %v", logEndpointPrefix, namespace, err)
 		return nil, err
@@ -90,9 +104,18 @@ func (e *Endpoint) Create(namespace, service string, opts *types.EndpointCreateO
 
 	endpoint.Spec.Policy = opts.Policy
 	endpoint.Spec.Strategy.Route = opts.RouteStrategy
+	endpoint.Spec.Strategy.Affinity = opts.Affinity
+	endpoint.Spec.Strategy.Weights = opts.Weights
 	endpoint.Spec.Strategy.Bind = opts.BindStrategy
+	endpoint.Spec.NodePorts = opts.NodePorts
+	endpoint.Spec.Headless = opts.Headless
+	endpoint.Spec.PortNames = opts.PortNames
+	endpoint.Spec.External = opts.External
+	endpoint.Spec.ExternalName = opts.ExternalName
+	endpoint.Spec.Upstreams = opts.Upstreams
 
 	endpoint.Spec.IP = opts.IP
+	endpoint.Spec.IPv6 = opts.IPv6
 	endpoint.Spec.Domain = opts.Domain
 
 	if err := e.storage.Put(e.context, e.storage.Collection().Endpoint(), endpoint.SelfLink().String(), endpoint, nil); err != nil {
@@ -117,9 +140,21 @@ func (e *Endpoint) Update(endpoint *types.Endpoint, opts *types.EndpointUpdateOp
 		endpoint.Spec.IP = *opts.IP
 	}
 
+	if opts.IPv6 != nil {
+		endpoint.Spec.IPv6 = *opts.IPv6
+	}
+
 	endpoint.Spec.Policy = opts.Policy
 	endpoint.Spec.Strategy.Route = opts.RouteStrategy
+	endpoint.Spec.Strategy.Affinity = opts.Affinity
+	endpoint.Spec.Strategy.Weights = opts.Weights
 	endpoint.Spec.Strategy.Bind = opts.BindStrategy
+	endpoint.Spec.NodePorts = opts.NodePorts
+	endpoint.Spec.Headless = opts.Headless
+	endpoint.Spec.PortNames = opts.PortNames
+	endpoint.Spec.External = opts.External
+	endpoint.Spec.ExternalName = opts.ExternalName
+	endpoint.Spec.Upstreams = opts.Upstreams
 
 	if err := e.storage.Set(e.context, e.storage.Collection().Endpoint(),
 		endpoint.SelfLink().String(), endpoint, nil); err != nil {
@@ -156,7 +191,7 @@ func (e *Endpoint) Watch(ch chan types.EndpointEvent, rev *int64) error {
 
 	log.V(logLevel).Debugf("%s:watch:> watch endpoint", logEndpointPrefix)
 
-	done := make(chan bool)
+	done := make(chan bool, 1)
 	watcher := storage.NewWatcher()
 
 	go func() {
@@ -166,13 +201,20 @@ func (e *Endpoint) Watch(ch chan types.EndpointEvent, rev *int64) error {
 				done <- true
 				return
 			case e := <-watcher:
-				if e.Data == nil {
-					continue
-				}
 
 				res := types.EndpointEvent{}
 				res.Action = e.Action
 				res.Name = e.Name
+				res.SelfLink = e.SelfLink
+
+				// delete event has no data if previous revision is compacted,
+				// event is sent without data so endpoint is evicted by self link
+				if data, ok := e.Data.([]byte); e.Data == nil || (ok && len(data) == 0) {
+					if e.Action == types.EventActionDelete {
+						ch <- res
+					}
+					continue
+				}
 
 				endpoint := new(types.Endpoint)
 
@@ -189,6 +231,7 @@ func (e *Endpoint) Watch(ch chan types.EndpointEvent, rev *int64) error {
 	}()
 
 	opts := storage.GetOpts()
+	opts.Rev = rev
 	if err := e.storage.Watch(e.context, e.storage.Collection().Endpoint(), watcher, opts); err != nil {
 		return err
 	}
@@ -136,6 +136,14 @@ func (e *err) NotFound(err ...error) *Err {
 	}
 }
 
+func (e *err) Conflict(err ...error) *Err {
+	return &Err{
+		Code:   http.StatusText(http.StatusConflict),
+		origin: getError(joinNameAndMessage(e.s, "version conflict"), err...),
+		http:   HTTP.getConflict(e.s),
+	}
+}
+
 func (e *err) InternalServerError(err ...error) *Err {
 	return &Err{
 		Code:   http.StatusText(http.StatusInternalServerError),
@@ -48,6 +48,14 @@ func (Http) BadRequest(w http.ResponseWriter, msg ...string) {
 	HTTP.getBadRequest(msg...).send(w)
 }
 
+func (Http) Conflict(w http.ResponseWriter, args ...string) {
+	HTTP.getConflict(args...).send(w)
+}
+
+func (Http) Gone(w http.ResponseWriter, msg ...string) {
+	HTTP.getGone(msg...).send(w)
+}
+
 func (Http) NotFound(w http.ResponseWriter, args ...string) {
 	HTTP.getNotFound(args...).send(w)
 }
@@ -127,6 +135,27 @@ func (Http) getBadRequest(msg ...string) *Http {
 	return getHttpError(http.StatusBadRequest, msg...)
 }
 
+func (Http) getGone(msg ...string) *Http {
+	return getHttpError(http.StatusGone, msg...)
+}
+
+func (Http) getConflict(args ...string) *Http {
+	message := "Conflict"
+	for i, a := range args {
+		switch i {
+		case 0:
+			message = fmt.Sprintf("%s version conflict", toUpperFirstChar(a))
+		default:
+			panic("Wrong parameter count: (is allowed from 0 to 1)")
+		}
+	}
+	return &Http{
+		Code:    http.StatusConflict,
+		Status:  http.StatusText(http.StatusConflict),
+		Message: message,
+	}
+}
+
 func (Http) getNotFound(args ...string) *Http {
 	message := "Not Found"
 	for i, a := range args {
@@ -31,6 +31,10 @@ const (
 	ErrStructArgIsInvalid    = "input structure is invalid"
 	ErrStructOutIsInvalid    = "output structure is invalid"
 	ErrStructOutIsNotPointer = "output structure is not pointer"
+	ErrContinueIsInvalid     = "continue token is invalid"
+	ErrContinueIsExpired     = "continue token is expired, list should be requested again"
+	ErrEntityConflict        = "entity revision conflict"
+	ErrSchemaIsNewer         = "entity schema version is newer than supported"
 )
 
 type storage struct{}
@@ -94,6 +98,38 @@ func (storage) NewErrStructOutIsNotPointer() error {
 	return errors.New(ErrStructOutIsNotPointer)
 }
 
+func (storage) IsErrContinueIsInvalid(err error) bool {
+	return err.Error() == ErrContinueIsInvalid
+}
+
+func (storage) NewErrContinueIsInvalid() error {
+	return errors.New(ErrContinueIsInvalid)
+}
+
+func (storage) IsErrContinueIsExpired(err error) bool {
+	return err.Error() == ErrContinueIsExpired
+}
+
+func (storage) NewErrContinueIsExpired() error {
+	return errors.New(ErrContinueIsExpired)
+}
+
 func Storage() storage {
 	return storage{}
 }
+
+func (storage) IsErrEntityConflict(err error) bool {
+	return err.Error() == ErrEntityConflict
+}
+
+func (storage) NewErrEntityConflict() error {
+	return errors.New(ErrEntityConflict)
+}
+
+func (storage) IsErrSchemaIsNewer(err error) bool {
+	return err.Error() == ErrSchemaIsNewer
+}
+
+func (storage) NewErrSchemaIsNewer() error {
+	return errors.New(ErrSchemaIsNewer)
+}
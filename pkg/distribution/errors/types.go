@@ -26,3 +26,17 @@ const ResourcesRamLimitIsRequired = "resources ram limit is required"
 const ResourcesCpuLimitIsRequired = "resources cpu limit is required"
 const ResourcesRamLimitExceeded = "resources ram limit exceeded"
 const ResourcesCpuLimitExceeded = "resources cpu limit exceeded"
+const ResourcesStorageLimitExceeded = "resources storage limit exceeded"
+const ResourcesLimitsLessThanAllocated = "resources limits are less than allocated"
+
+// IsResourcesLimitExceeded checks error is caused by exceeded namespace resources limit
+func IsResourcesLimitExceeded(err error) bool {
+	switch err.Error() {
+	case ResourcesRamLimitExceeded, ResourcesCpuLimitExceeded, ResourcesStorageLimitExceeded:
+		return true
+	}
+	return false
+}
+
+const PrivilegedContainersDenied = "privileged containers are not allowed in namespace"
+const PrivilegedCapabilitiesDenied = "privileged capabilities are not allowed in namespace"
@@ -36,10 +36,10 @@ type Exporter struct {
 	storage storage.Storage
 }
 
-func (n *Exporter) List() (*types.ExporterList, error) {
+func (n *Exporter) List(opts *types.ListOptions) (*types.ExporterList, error) {
 	list := types.NewExporterList()
 
-	if err := n.storage.List(n.context, n.storage.Collection().Exporter().Info(), "", list, nil); err != nil {
+	if err := n.storage.List(n.context, n.storage.Collection().Exporter().Info(), "", list, listOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:list:> get exporter list err: %v", logExporterPrefix, err)
 		return nil, err
 	}
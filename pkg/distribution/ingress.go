@@ -36,10 +36,10 @@ type Ingress struct {
 	storage storage.Storage
 }
 
-func (n *Ingress) List() (*types.IngressList, error) {
+func (n *Ingress) List(opts *types.ListOptions) (*types.IngressList, error) {
 	list := types.NewIngressList()
 
-	if err := n.storage.List(n.context, n.storage.Collection().Ingress().Info(), "", list, nil); err != nil {
+	if err := n.storage.List(n.context, n.storage.Collection().Ingress().Info(), "", list, listOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:list:> get ingress list err: %v", logIngressPrefix, err)
 		return nil, err
 	}
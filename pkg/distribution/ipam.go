@@ -0,0 +1,182 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logIPAMPrefix = "distribution:ipam"
+)
+
+type IPAM struct {
+	context context.Context
+	storage storage.Storage
+}
+
+// PoolMap returns all configured IPAM pools
+func (i *IPAM) PoolMap() (*types.IPAMPoolMap, error) {
+	log.V(logLevel).Debugf("%s:poolmap:> get pools", logIPAMPrefix)
+
+	pools := types.NewIPAMPoolMap()
+
+	if err := i.storage.Map(i.context, i.storage.Collection().IPAM().Pool(), types.EmptyString, pools, nil); err != nil {
+		log.V(logLevel).Errorf("%s:poolmap:> get pools err: %v", logIPAMPrefix, err)
+		return nil, err
+	}
+
+	return pools, nil
+}
+
+// PoolSet stores pool configuration
+func (i *IPAM) PoolSet(pool *types.IPAMPool) error {
+	log.V(logLevel).Debugf("%s:poolset:> set pool %s: %s", logIPAMPrefix, pool.Name, pool.CIDR)
+
+	opts := storage.GetOpts()
+	opts.Force = true
+
+	if err := i.storage.Set(i.context, i.storage.Collection().IPAM().Pool(), pool.Name, pool, opts); err != nil {
+		log.V(logLevel).Errorf("%s:poolset:> set pool %s err: %v", logIPAMPrefix, pool.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+// PoolDel removes pool configuration
+func (i *IPAM) PoolDel(name string) error {
+	log.V(logLevel).Debugf("%s:pooldel:> remove pool %s", logIPAMPrefix, name)
+
+	err := i.storage.Del(i.context, i.storage.Collection().IPAM().Pool(), name)
+	if err != nil && !errors.Storage().IsErrEntityNotFound(err) {
+		log.V(logLevel).Errorf("%s:pooldel:> remove pool %s err: %v", logIPAMPrefix, name, err)
+		return err
+	}
+
+	return nil
+}
+
+// LeaseMap returns all leases of pool
+func (i *IPAM) LeaseMap(pool string) (*types.IPAMLeaseMap, error) {
+	log.V(logLevel).Debugf("%s:leasemap:> get leases of pool %s", logIPAMPrefix, pool)
+
+	leases := types.NewIPAMLeaseMap()
+
+	if err := i.storage.Map(i.context, i.storage.Collection().IPAM().Lease(pool), types.EmptyString, leases, nil); err != nil {
+		log.V(logLevel).Errorf("%s:leasemap:> get leases of pool %s err: %v", logIPAMPrefix, pool, err)
+		return nil, err
+	}
+
+	return leases, nil
+}
+
+// LeaseGet returns lease of address in pool or nil if address is not leased
+func (i *IPAM) LeaseGet(pool, addr string) (*types.IPAMLease, error) {
+	log.V(logLevel).Debugf("%s:leaseget:> get lease %s in pool %s", logIPAMPrefix, addr, pool)
+
+	lease := new(types.IPAMLease)
+
+	err := i.storage.Get(i.context, i.storage.Collection().IPAM().Lease(pool), types.IPAMLeaseKey(addr), lease, nil)
+	if err != nil {
+		if errors.Storage().IsErrEntityNotFound(err) {
+			return nil, nil
+		}
+		log.V(logLevel).Errorf("%s:leaseget:> get lease %s err: %v", logIPAMPrefix, addr, err)
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// LeasePut stores lease only if address is not leased yet,
+// storage returns entity exists error if lease is already taken
+func (i *IPAM) LeasePut(lease *types.IPAMLease) error {
+	log.V(logLevel).Debugf("%s:leaseput:> put lease %s in pool %s", logIPAMPrefix, lease.Addr, lease.Pool)
+
+	if err := i.storage.Put(i.context, i.storage.Collection().IPAM().Lease(lease.Pool),
+		types.IPAMLeaseKey(lease.Addr), lease, nil); err != nil {
+		if !errors.Storage().IsErrEntityExists(err) {
+			log.V(logLevel).Errorf("%s:leaseput:> put lease %s err: %v", logIPAMPrefix, lease.Addr, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// LeaseDel removes lease only if it was not changed since it was read,
+// storage returns entity conflict error if address was leased again
+func (i *IPAM) LeaseDel(lease *types.IPAMLease) error {
+	log.V(logLevel).Debugf("%s:leasedel:> remove lease %s from pool %s", logIPAMPrefix, lease.Addr, lease.Pool)
+
+	err := i.storage.DelRev(i.context, i.storage.Collection().IPAM().Lease(lease.Pool),
+		types.IPAMLeaseKey(lease.Addr), lease.Storage.Revision)
+	if err != nil && !errors.Storage().IsErrEntityNotFound(err) {
+		if !errors.Storage().IsErrEntityConflict(err) {
+			log.V(logLevel).Errorf("%s:leasedel:> remove lease %s err: %v", logIPAMPrefix, lease.Addr, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Utilization returns count of leased and available addresses in every pool
+func (i *IPAM) Utilization() (*types.IPAMUtilization, error) {
+	log.V(logLevel).Debugf("%s:utilization:> get pools utilization", logIPAMPrefix)
+
+	pools, err := i.PoolMap()
+	if err != nil {
+		return nil, err
+	}
+
+	u := types.NewIPAMUtilization()
+
+	for name, p := range pools.Items {
+
+		leases, err := i.LeaseMap(name)
+		if err != nil {
+			return nil, err
+		}
+
+		pu := new(types.IPAMPoolUtilization)
+		pu.CIDR = p.CIDR
+		pu.Prefix = p.Prefix
+		pu.Size = p.Size
+		pu.Leased = len(leases.Items)
+		pu.Available = p.Size - pu.Leased
+		if pu.Available < 0 {
+			pu.Available = 0
+		}
+
+		u.Pools[name] = pu
+	}
+
+	return u, nil
+}
+
+func NewIPAMModel(ctx context.Context, stg storage.Storage) *IPAM {
+	return &IPAM{ctx, stg}
+}
@@ -73,12 +73,12 @@ func (j *Job) Get(selflink string) (*types.Job, error) {
 }
 
 // ListByNamespace jobs
-func (j *Job) ListByNamespace(namespace string) (*types.JobList, error) {
+func (j *Job) ListByNamespace(namespace string, opts *types.ListOptions) (*types.JobList, error) {
 	log.V(logLevel).Debugf("%s:list:> by namespace %s", logJobPrefix, namespace)
 	jobs := types.NewJobList()
 
 	q := j.storage.Filter().Job().ByNamespace(namespace)
-	err := j.storage.List(j.context, j.storage.Collection().Job(), q, jobs, nil)
+	err := j.storage.List(j.context, j.storage.Collection().Job(), q, jobs, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> by namespace %s err: %v", logJobPrefix, namespace, err)
 		return nil, err
@@ -104,13 +104,13 @@ func (j *Job) Create(job *types.Job) (*types.Job, error) {
 }
 
 // Update job
-func (j *Job) Set(job *types.Job) error {
+func (j *Job) Set(job *types.Job, opts *types.UpdateOptions) error {
 
 	job.Meta.Updated = time.Now()
 	log.V(logLevel).Debugf("%s:update:> update job %s", logJobPrefix, job.Meta.Name)
 
 	if err := j.storage.Set(j.context, j.storage.Collection().Job(),
-		job.SelfLink().String(), job, nil); err != nil {
+		job.SelfLink().String(), job, updateOpts(opts)); err != nil {
 		log.Errorf("%s:update:> update for job %s err: %v", logJobPrefix, job.Meta.Name, err)
 		return err
 	}
@@ -31,6 +31,8 @@ const (
 	logNamespacePrefix  = "distribution:namespace"
 	defaultNamespaceRam = "2GB"
 	defaultNamespaceCPU = "200m"
+	// namespaceAllocateRetries - count of allocation retries on concurrent namespace changes
+	namespaceAllocateRetries = 10
 )
 
 type Namespace struct {
@@ -47,13 +49,13 @@ func (n *NM) Set(Namespace) error {
 	return nil
 }
 
-func (n *Namespace) List() (*types.NamespaceList, error) {
+func (n *Namespace) List(opts *types.ListOptions) (*types.NamespaceList, error) {
 
 	log.V(logLevel).Debugf("%s:list:> get namespaces list", logNamespacePrefix)
 
 	var list = types.NewNamespaceList()
 
-	err := n.storage.List(n.context, n.storage.Collection().Namespace(), "", list, nil)
+	err := n.storage.List(n.context, n.storage.Collection().Namespace(), "", list, listOpts(opts))
 
 	if err != nil {
 		log.Info(err.Error())
@@ -105,12 +107,12 @@ func (n *Namespace) Create(ns *types.Namespace) (*types.Namespace, error) {
 	return ns, nil
 }
 
-func (n *Namespace) Update(namespace *types.Namespace) error {
+func (n *Namespace) Update(namespace *types.Namespace, opts *types.UpdateOptions) error {
 
 	log.V(logLevel).Debugf("%s:update:> update Namespace %#v", logNamespacePrefix, namespace)
 
 	if err := n.storage.Set(n.context, n.storage.Collection().Namespace(),
-		namespace.SelfLink().String(), namespace, nil); err != nil {
+		namespace.SelfLink().String(), namespace, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> namespace update err: %v", logNamespacePrefix, err)
 		return err
 	}
@@ -118,6 +120,45 @@ func (n *Namespace) Update(namespace *types.Namespace) error {
 	return nil
 }
 
+// Allocate stores change of namespace resources allocation with compare and swap on namespace revision.
+// Namespace should have change already applied. If namespace was changed since it was read,
+// change is applied to namespace fetched again and is checked against namespace limits,
+// so allocations of concurrent requests are not lost. Namespace is replaced with the stored one
+func (n *Namespace) Allocate(ns *types.Namespace, change types.ResourceItem) error {
+
+	log.V(logLevel).Debugf("%s:allocate:> allocate resources in namespace %s", logNamespacePrefix, ns.Meta.Name)
+
+	for i := 0; ; i++ {
+
+		err := n.Update(ns, &types.UpdateOptions{Revision: &ns.Storage.Revision})
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Storage().IsErrEntityConflict(err) || i == namespaceAllocateRetries {
+			log.V(logLevel).Errorf("%s:allocate:> allocate resources in namespace %s err: %v", logNamespacePrefix, ns.Meta.Name, err)
+			return err
+		}
+
+		log.V(logLevel).Debugf("%s:allocate:> namespace %s changed, retry allocation", logNamespacePrefix, ns.Meta.Name)
+
+		fresh, err := n.Get(ns.Meta.Name)
+		if err != nil {
+			return err
+		}
+
+		if fresh == nil {
+			return errors.Storage().NewErrEntityNotFound()
+		}
+
+		if err := fresh.AllocateChange(change); err != nil {
+			return err
+		}
+
+		*ns = *fresh
+	}
+}
+
 func (n *Namespace) Remove(ns *types.Namespace) error {
 
 	log.V(logLevel).Debugf("%s:remove:> remove namespace %s", logNamespacePrefix, ns.Meta.Name)
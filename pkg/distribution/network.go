@@ -96,6 +96,39 @@ func (s *Network) Set(net *types.Network) (*types.Network, error) {
 	return net, nil
 }
 
+// Driver returns cluster network driver.
+// Network driver of the first connected node is stored as cluster network driver
+func (s *Network) Driver(driver string) (string, error) {
+
+	net, err := s.Get()
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	if net == nil {
+		net = new(types.Network)
+		net.Spec.Type = driver
+
+		if _, err := s.Put(net); err != nil {
+			if errors.Storage().IsErrEntityExists(err) {
+				return s.Driver(driver)
+			}
+			return types.EmptyString, err
+		}
+
+		return driver, nil
+	}
+
+	if net.Spec.Type == types.EmptyString {
+		net.Spec.Type = driver
+		if _, err := s.Set(net); err != nil {
+			return types.EmptyString, err
+		}
+	}
+
+	return net.Spec.Type, nil
+}
+
 // Remove network from storage
 func (s *Network) Del(net *types.Network) error {
 
@@ -312,6 +345,18 @@ func (s *Network) SubnetEqual(snet *types.Subnet, spec types.SubnetSpec) bool {
 		return false
 	}
 
+	if snet.Spec.Key != spec.Key {
+		return false
+	}
+
+	if snet.Spec.KeyNext != spec.KeyNext {
+		return false
+	}
+
+	if snet.Spec.CIDRv6 != spec.CIDRv6 {
+		return false
+	}
+
 	return true
 }
 
@@ -38,12 +38,12 @@ type Node struct {
 	storage storage.Storage
 }
 
-func (n *Node) List() (*types.NodeList, error) {
+func (n *Node) List(opts *types.ListOptions) (*types.NodeList, error) {
 	log.V(logLevel).Debugf("%s:list:> get nodes list", logNodePrefix)
 
 	nodes := types.NewNodeList()
 
-	err := n.storage.List(n.context, n.storage.Collection().Node().Info(), "", nodes, nil)
+	err := n.storage.List(n.context, n.storage.Collection().Node().Info(), "", nodes, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:list:> get nodes list err: %v", logNodePrefix, err)
 		return nil, err
@@ -86,13 +86,13 @@ func (p *Pod) Put(pod *types.Pod) (*types.Pod, error) {
 }
 
 // ListByNamespace returns pod list in selected namespace
-func (p *Pod) ListByNamespace(namespace string) (*types.PodList, error) {
+func (p *Pod) ListByNamespace(namespace string, opts *types.ListOptions) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbynamespace:> get pod list by namespace %s", logPodPrefix, namespace)
 
 	list := types.NewPodList()
 	filter := p.storage.Filter().Pod().ByNamespace(namespace)
 
-	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, nil)
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:listbynamespace:> get pod list by deployment id `%s` err: %v", logPodPrefix, namespace, err)
 		return nil, err
@@ -102,13 +102,13 @@ func (p *Pod) ListByNamespace(namespace string) (*types.PodList, error) {
 }
 
 // ListByService returns pod list in selected service
-func (p *Pod) ListByService(namespace, service string) (*types.PodList, error) {
+func (p *Pod) ListByService(namespace, service string, opts *types.ListOptions) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbyservice:> get pod list by service id %s/%s", logPodPrefix, namespace, service)
 
 	list := types.NewPodList()
 	filter := p.storage.Filter().Pod().ByService(namespace, service)
 
-	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, nil)
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:listbyservice:> get pod list by service id `%s` err: %v", logPodPrefix, namespace, service, err)
 		return nil, err
@@ -118,13 +118,13 @@ func (p *Pod) ListByService(namespace, service string) (*types.PodList, error) {
 }
 
 // ListByDeployment returns pod list in selected deployment
-func (p *Pod) ListByDeployment(namespace, service, deployment string) (*types.PodList, error) {
+func (p *Pod) ListByDeployment(namespace, service, deployment string, opts *types.ListOptions) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbydeployment:> get pod list by id %s/%s/%s", logPodPrefix, namespace, service, deployment)
 
 	list := types.NewPodList()
 	filter := p.storage.Filter().Pod().ByDeployment(namespace, service, deployment)
 
-	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, nil)
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:listbydeployment:> get pod list by deployment id `%s/%s/%s` err: %v",
 			logPodPrefix, namespace, service, deployment, err)
@@ -135,13 +135,13 @@ func (p *Pod) ListByDeployment(namespace, service, deployment string) (*types.Po
 }
 
 // ListByJob returns pod list in selected job
-func (p *Pod) ListByJob(namespace, job string) (*types.PodList, error) {
+func (p *Pod) ListByJob(namespace, job string, opts *types.ListOptions) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbyjob:> get pod list by id %s/%s", logPodPrefix, namespace, job)
 
 	list := types.NewPodList()
 	filter := p.storage.Filter().Pod().ByJob(namespace, job)
 
-	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, nil)
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:listbyjob:> get pod list by deployment id `%s/%s` err: %v",
 			logPodPrefix, namespace, job, err)
@@ -152,13 +152,13 @@ func (p *Pod) ListByJob(namespace, job string) (*types.PodList, error) {
 }
 
 // ListByTask returns pod list in selected task
-func (p *Pod) ListByTask(namespace, job, task string) (*types.PodList, error) {
+func (p *Pod) ListByTask(namespace, job, task string, opts *types.ListOptions) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbytask:> get pod list by id %s/%s/%s", logPodPrefix, namespace, job, task)
 
 	list := types.NewPodList()
 	filter := p.storage.Filter().Pod().ByTask(namespace, job, task)
 
-	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, nil)
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Debugf("%s:listbytask:> get pod list by deployment id `%s/%s/%s` err: %v",
 			logPodPrefix, namespace, job, task, err)
@@ -0,0 +1,182 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logPolicyPrefix = "distribution:policy"
+)
+
+type NetworkPolicy struct {
+	context context.Context
+	storage storage.Storage
+}
+
+func (n *NetworkPolicy) Runtime() (*types.System, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get network policy runtime info", logPolicyPrefix)
+	runtime, err := n.storage.Info(n.context, n.storage.Collection().Policy(), "")
+	if err != nil {
+		log.V(logLevel).Errorf("%s:get:> get runtime info error: %s", logPolicyPrefix, err)
+		return &runtime.System, err
+	}
+	return &runtime.System, nil
+}
+
+func (n *NetworkPolicy) Get(namespace, name string) (*types.NetworkPolicy, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get network policy %s/%s", logPolicyPrefix, namespace, name)
+
+	item := new(types.NetworkPolicy)
+
+	err := n.storage.Get(n.context, n.storage.Collection().Policy(), types.NewNetworkPolicySelfLink(namespace, name).String(), &item, nil)
+	if err != nil {
+
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> in namespace %s by name %s not found", logPolicyPrefix, namespace, name)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> in namespace %s by name %s error: %s", logPolicyPrefix, namespace, name, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (n *NetworkPolicy) List(filter string, opts *types.ListOptions) (*types.NetworkPolicyList, error) {
+
+	var f string
+
+	log.V(logLevel).Debugf("%s:list:> get network policies list by namespace", logPolicyPrefix)
+
+	list := types.NewNetworkPolicyList()
+	if filter != types.EmptyString {
+		f = n.storage.Filter().Policy().ByNamespace(filter)
+	}
+
+	err := n.storage.List(n.context, n.storage.Collection().Policy(), f, list, listOpts(opts))
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get network policies list by namespace err: %s", logPolicyPrefix, err)
+		return list, err
+	}
+
+	log.V(logLevel).Debugf("%s:list:> get network policies list by namespace result: %d", logPolicyPrefix, len(list.Items))
+
+	return list, nil
+}
+
+func (n *NetworkPolicy) Create(namespace *types.Namespace, policy *types.NetworkPolicy) (*types.NetworkPolicy, error) {
+
+	log.V(logLevel).Debugf("%s:create:> create network policy %s", logPolicyPrefix, policy.Meta.Name)
+
+	policy.Meta.Namespace = namespace.Meta.Name
+	policy.Meta.SelfLink = *types.NewNetworkPolicySelfLink(namespace.Meta.Name, policy.Meta.Name)
+
+	if err := n.storage.Put(n.context, n.storage.Collection().Policy(),
+		policy.SelfLink().String(), policy, nil); err != nil {
+		log.V(logLevel).Errorf("%s:create:> insert network policy err: %v", logPolicyPrefix, err)
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (n *NetworkPolicy) Update(policy *types.NetworkPolicy, opts *types.UpdateOptions) (*types.NetworkPolicy, error) {
+
+	log.V(logLevel).Debugf("%s:update:> update network policy %s", logPolicyPrefix, policy.Meta.Name)
+
+	if err := n.storage.Set(n.context, n.storage.Collection().Policy(),
+		policy.SelfLink().String(), policy, updateOpts(opts)); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update network policy err: %s", logPolicyPrefix, err)
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (n *NetworkPolicy) Remove(policy *types.NetworkPolicy) error {
+
+	log.V(logLevel).Debugf("%s:remove:> remove network policy %s", logPolicyPrefix, policy.SelfLink().String())
+
+	if err := n.storage.Del(n.context, n.storage.Collection().Policy(),
+		policy.SelfLink().String()); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove network policy err: %s", logPolicyPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *NetworkPolicy) Watch(ch chan types.NetworkPolicyEvent, rev *int64) error {
+
+	log.V(logLevel).Debugf("%s:watch:> watch network policies", logPolicyPrefix)
+
+	done := make(chan bool)
+	watcher := storage.NewWatcher()
+
+	go func() {
+		for {
+			select {
+			case <-n.context.Done():
+				done <- true
+				return
+			case e := <-watcher:
+				if e.Data == nil {
+					continue
+				}
+
+				res := types.NetworkPolicyEvent{}
+				res.Action = e.Action
+				res.Name = e.Name
+
+				policy := new(types.NetworkPolicy)
+
+				if err := json.Unmarshal(e.Data.([]byte), policy); err != nil {
+					log.Errorf("%s:> parse data err: %v", logPolicyPrefix, err)
+					continue
+				}
+
+				res.Data = policy
+
+				ch <- res
+			}
+		}
+	}()
+
+	opts := storage.GetOpts()
+	opts.Rev = rev
+	if err := n.storage.Watch(n.context, n.storage.Collection().Policy(), watcher, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewNetworkPolicyModel(ctx context.Context, stg storage.Storage) *NetworkPolicy {
+	return &NetworkPolicy{ctx, stg}
+}
@@ -50,14 +50,14 @@ func (r *Route) Runtime() (*types.System, error) {
 	return &runtime.System, nil
 }
 
-func (r *Route) List() (*types.RouteList, error) {
+func (r *Route) List(opts *types.ListOptions) (*types.RouteList, error) {
 
 	log.V(logLevel).Debugf("%s:listspec:> list specs", logRoutePrefix)
 
 	list := types.NewRouteList()
 
 	//TODO: change map to list
-	err := r.storage.List(r.context, r.storage.Collection().Route(), types.EmptyString, list, nil)
+	err := r.storage.List(r.context, r.storage.Collection().Route(), types.EmptyString, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:listbynamespace:> list route err: %v", logRoutePrefix, err)
 		return list, err
@@ -66,13 +66,13 @@ func (r *Route) List() (*types.RouteList, error) {
 	return list, nil
 }
 
-func (r *Route) ListByNamespace(namespace string) (*types.RouteList, error) {
+func (r *Route) ListByNamespace(namespace string, opts *types.ListOptions) (*types.RouteList, error) {
 
 	log.V(logLevel).Debug("%s:listbynamespace:> list route", logRoutePrefix)
 
 	list := types.NewRouteList()
 
-	err := r.storage.List(r.context, r.storage.Collection().Route(), r.storage.Filter().Route().ByNamespace(namespace), list, nil)
+	err := r.storage.List(r.context, r.storage.Collection().Route(), r.storage.Filter().Route().ByNamespace(namespace), list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:listbynamespace:> list route err: %v", logRoutePrefix, err)
 		return list, err
@@ -119,12 +119,12 @@ func (r *Route) Add(namespace *types.Namespace, route *types.Route) (*types.Rout
 	return route, nil
 }
 
-func (r *Route) Set(route *types.Route) (*types.Route, error) {
+func (r *Route) Set(route *types.Route, opts *types.UpdateOptions) (*types.Route, error) {
 
 	log.V(logLevel).Debugf("%s:update:> update route %s", logRoutePrefix, route.Meta.Name)
 
 	if err := r.storage.Set(r.context, r.storage.Collection().Route(),
-		route.SelfLink().String(), route, nil); err != nil {
+		route.SelfLink().String(), route, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update route err: %v", logRoutePrefix, err)
 		return nil, err
 	}
@@ -69,7 +69,7 @@ func (n *Secret) Get(namespace, name string) (*types.Secret, error) {
 	return item, nil
 }
 
-func (n *Secret) List(filter string) (*types.SecretList, error) {
+func (n *Secret) List(filter string, opts *types.ListOptions) (*types.SecretList, error) {
 
 	var f string
 
@@ -80,7 +80,7 @@ func (n *Secret) List(filter string) (*types.SecretList, error) {
 		f = n.storage.Filter().Secret().ByNamespace(filter)
 	}
 
-	err := n.storage.List(n.context, n.storage.Collection().Secret(), f, list, nil)
+	err := n.storage.List(n.context, n.storage.Collection().Secret(), f, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> get secrets list by namespace err: %s", logSecretPrefix, err)
 		return list, err
@@ -108,12 +108,12 @@ func (n *Secret) Create(namespace *types.Namespace, secret *types.Secret) (*type
 	return secret, nil
 }
 
-func (n *Secret) Update(secret *types.Secret) (*types.Secret, error) {
+func (n *Secret) Update(secret *types.Secret, opts *types.UpdateOptions) (*types.Secret, error) {
 
 	log.V(logLevel).Debugf("%s:update:> update secret %s", logSecretPrefix, secret.Meta.Name)
 
 	if err := n.storage.Set(n.context, n.storage.Collection().Secret(),
-		secret.SelfLink().String(), secret, nil); err != nil {
+		secret.SelfLink().String(), secret, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update secret err: %s", logSecretPrefix, err)
 		return nil, err
 	}
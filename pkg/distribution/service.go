@@ -74,14 +74,14 @@ func (s *Service) Get(namespace, name string) (*types.Service, error) {
 }
 
 // List method return map of services in selected namespace
-func (s *Service) List(namespace string) (*types.ServiceList, error) {
+func (s *Service) List(namespace string, opts *types.ListOptions) (*types.ServiceList, error) {
 
 	log.V(logLevel).Debugf("%s:list:> by namespace %s", logServicePrefix, namespace)
 
 	list := types.NewServiceList()
 	q := s.storage.Filter().Service().ByNamespace(namespace)
 
-	err := s.storage.List(s.context, s.storage.Collection().Service(), q, list, nil)
+	err := s.storage.List(s.context, s.storage.Collection().Service(), q, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> by namespace %s err: %v", logServicePrefix, namespace, err)
 		return nil, err
@@ -118,12 +118,12 @@ func (s *Service) Create(namespace *types.Namespace, svc *types.Service) (*types
 }
 
 // Update service in namespace
-func (s *Service) Update(service *types.Service) (*types.Service, error) {
+func (s *Service) Update(service *types.Service, opts *types.UpdateOptions) (*types.Service, error) {
 
 	log.V(logLevel).Debugf("%s:update:> %#v -> %#v", logServicePrefix, service)
 
 	if err := s.storage.Set(s.context, s.storage.Collection().Service(),
-		service.SelfLink().String(), service, nil); err != nil {
+		service.SelfLink().String(), service, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update service spec err: %v", logServicePrefix, err)
 		return nil, err
 	}
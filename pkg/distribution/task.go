@@ -68,12 +68,12 @@ func (t *Task) Get(selflink string) (*types.Task, error) {
 	return task, nil
 }
 
-func (t *Task) ListByNamespace(namespace string) (*types.TaskList, error) {
+func (t *Task) ListByNamespace(namespace string, opts *types.ListOptions) (*types.TaskList, error) {
 	log.V(logLevel).Debugf("%s:list:> by namespace %s", logTaskPrefix, namespace)
 	tasks := types.NewTaskList()
 
 	q := t.storage.Filter().Task().ByNamespace(namespace)
-	err := t.storage.List(t.context, t.storage.Collection().Task(), q, tasks, nil)
+	err := t.storage.List(t.context, t.storage.Collection().Task(), q, tasks, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> by namespace %s err: %v", logTaskPrefix, namespace, err)
 		return nil, err
@@ -84,12 +84,12 @@ func (t *Task) ListByNamespace(namespace string) (*types.TaskList, error) {
 	return tasks, nil
 }
 
-func (t *Task) ListByJob(namespace, job string) (*types.TaskList, error) {
+func (t *Task) ListByJob(namespace, job string, opts *types.ListOptions) (*types.TaskList, error) {
 	log.V(logLevel).Debugf("%s:list:> by namespace %s", logTaskPrefix, namespace)
 	tasks := types.NewTaskList()
 
 	q := t.storage.Filter().Task().ByJob(namespace, job)
-	err := t.storage.List(t.context, t.storage.Collection().Task(), q, tasks, nil)
+	err := t.storage.List(t.context, t.storage.Collection().Task(), q, tasks, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> by namespace %s err: %v", logTaskPrefix, namespace, err)
 		return nil, err
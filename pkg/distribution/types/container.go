@@ -35,6 +35,7 @@ const (
 	ContainerTypeRuntime        = "LBC:Container"
 	ContainerTypeRuntimeService = "service"
 	ContainerTypeRuntimeTask    = "task"
+	ContainerTypeRuntimeInit    = "init"
 )
 
 type Container struct {
@@ -91,6 +92,8 @@ type NetworkSettings struct {
 	Gateway string `json:"gateway"`
 	// Container ip address
 	IPAddress string `json:"ip"`
+	// Container ipv6 address
+	IPv6Address string `json:"ipv6,omitempty"`
 	// Container ports mapping
 	Ports []*SpecTemplateContainerPort `json:"ports"`
 }
@@ -63,6 +63,34 @@ type DiscoveryStatus struct {
 	Port   uint16 `json:"port"`
 	Ready  bool   `json:"ready"`
 	Online bool   `json:"online"`
+	// DNS server queries and cache stats
+	DNS DiscoveryDNSStats `json:"dns"`
+}
+
+// swagger:model types_discovery_dns_stats
+type DiscoveryDNSStats struct {
+	// Queries received by DNS server
+	Queries uint64 `json:"queries"`
+	// Queries answered from cluster zones
+	Local uint64 `json:"local"`
+	// Queries forwarded to upstreams, forwarded queries are cache misses
+	Forwarded uint64 `json:"forwarded"`
+	// Forwarded queries failed on all upstreams
+	Failed uint64 `json:"failed"`
+	// Queries answered from responses cache
+	CacheHits uint64 `json:"cache_hits"`
+	// Responses kept in cache
+	CacheEntries int `json:"cache_entries"`
+	// Cluster domains addresses cache
+	Endpoints DiscoveryDNSCacheStats `json:"endpoints"`
+}
+
+// swagger:model types_discovery_dns_cache_stats
+type DiscoveryDNSCacheStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	Entries  int     `json:"entries"`
+	HitRatio float64 `json:"hit_ratio"`
 }
 
 // swagger:ignore
@@ -18,9 +18,23 @@
 
 package types
 
+import "strconv"
+
 const (
 	// EndpointSpecRouteStrategyRR - round robin balancing strategy type
 	EndpointSpecRouteStrategyRR = "rr"
+	// EndpointSpecRouteStrategyWRR - weighted round robin balancing strategy type
+	EndpointSpecRouteStrategyWRR = "wrr"
+	// EndpointSpecRouteStrategyLC - least connection balancing strategy type
+	EndpointSpecRouteStrategyLC = "lc"
+	// EndpointSpecRouteStrategySH - source ip hashing balancing strategy type
+	EndpointSpecRouteStrategySH = "sh"
+	// EndpointSpecAffinityMax - max session affinity timeout in seconds
+	EndpointSpecAffinityMax = 86400
+	// EndpointSpecWeightDefault - weight of upstream without weight set
+	EndpointSpecWeightDefault = 1
+	// EndpointSpecWeightMax - max upstream weight supported by proxy
+	EndpointSpecWeightMax = 65535
 	// EndpointSpecBindStrategyDefault - default scheduling endpoint across all nodes
 	EndpointSpecBindStrategyDefault = "default"
 )
@@ -65,18 +79,30 @@ type EndpointStatus struct {
 // swagger:model types_endpoint_spec
 type EndpointSpec struct {
 	// Upstream state
-	State     string               `json:"state"`
+	State string `json:"state"`
+	// External endpoint points to addresses or name outside of cluster
 	External  bool                 `json:"external"`
 	IP        string               `json:"ip"`
+	IPv6      string               `json:"ipv6,omitempty"`
 	Domain    string               `json:"domain"`
 	PortMap   map[uint16]string    `json:"port_map"`
 	Strategy  EndpointSpecStrategy `json:"strategy"`
 	Policy    string               `json:"policy"`
 	Upstreams []string             `json:"upstreams"`
+	// Ports exposed on all nodes: endpoint port > node port
+	NodePorts map[uint16]uint16 `json:"node_ports,omitempty"`
+	// Headless endpoint has no virtual IP, upstreams are resolved instead
+	Headless bool `json:"headless,omitempty"`
+	// Ports names published in SRV records: endpoint port > name
+	PortNames map[uint16]string `json:"port_names,omitempty"`
+	// External DNS name endpoint domain is alias of
+	ExternalName string `json:"external_name,omitempty"`
 }
 
 type EndpointState struct {
 	EndpointSpec
+	// Upstreams connections, collected from proxy
+	Stats map[string]*EndpointUpstreamStats `json:"stats,omitempty"`
 }
 
 // EndpointSpecStrategy describes route and bind
@@ -84,6 +110,17 @@ type EndpointState struct {
 type EndpointSpecStrategy struct {
 	Route string `json:"route"`
 	Bind  string `json:"bind"`
+	// Session affinity timeout in seconds, disabled if 0
+	Affinity int `json:"affinity,omitempty"`
+	// Upstreams weights: upstream address > weight, upstreams without weight get default weight
+	Weights map[string]int `json:"weights,omitempty"`
+}
+
+// swagger:ignore
+// EndpointUpstreamStats describes upstream connections
+type EndpointUpstreamStats struct {
+	Active   int `json:"active"`
+	Inactive int `json:"inactive"`
 }
 
 // swagger:ignore
@@ -99,23 +136,116 @@ func (e *Endpoint) SelfLink() *EndpointSelfLink {
 	return &e.Meta.SelfLink
 }
 
+// swagger:ignore
+// GetIPs returns endpoint IPv4 and IPv6 addresses, headless endpoint has no addresses
+func (s *EndpointSpec) GetIPs() []string {
+	ips := make([]string, 0)
+	if s.IP != EmptyString {
+		ips = append(ips, s.IP)
+	}
+	if s.IPv6 != EmptyString {
+		ips = append(ips, s.IPv6)
+	}
+	return ips
+}
+
+// swagger:ignore
+// IsProxied checks endpoint traffic is balanced by proxy:
+// headless and external name endpoints are resolved by discovery only
+func (s *EndpointSpec) IsProxied() bool {
+	return !s.Headless && s.ExternalName == EmptyString
+}
+
+// swagger:ignore
+// GetNodePorts returns ports exposed on all nodes as they are leased
+func (s *EndpointSpec) GetNodePorts() []string {
+	ports := make([]string, 0, len(s.NodePorts))
+	for _, np := range s.NodePorts {
+		ports = append(ports, strconv.Itoa(int(np)))
+	}
+	return ports
+}
+
+// swagger:ignore
+// GetRoute returns route strategy, round robin is used by default
+func (s EndpointSpecStrategy) GetRoute() string {
+	if s.Route == EmptyString {
+		return EndpointSpecRouteStrategyRR
+	}
+	return s.Route
+}
+
+// swagger:ignore
+// GetWeight returns weight of upstream, default weight is used if it is not set
+func (s EndpointSpecStrategy) GetWeight(upstream string) int {
+	if w, ok := s.Weights[upstream]; ok {
+		return w
+	}
+	return EndpointSpecWeightDefault
+}
+
+// swagger:ignore
+// WeightsEqual checks upstreams weights are the same, default weight is equal to not set one
+func (s EndpointSpecStrategy) WeightsEqual(st EndpointSpecStrategy) bool {
+	for up := range s.Weights {
+		if s.GetWeight(up) != st.GetWeight(up) {
+			return false
+		}
+	}
+	for up := range st.Weights {
+		if s.GetWeight(up) != st.GetWeight(up) {
+			return false
+		}
+	}
+	return true
+}
+
+// swagger:ignore
+// IsValidRouteStrategy checks route strategy is supported by proxy
+func IsValidRouteStrategy(route string) bool {
+	switch route {
+	case EmptyString, EndpointSpecRouteStrategyRR, EndpointSpecRouteStrategyWRR,
+		EndpointSpecRouteStrategyLC, EndpointSpecRouteStrategySH:
+		return true
+	}
+	return false
+}
+
 // swagger:ignore
 type EndpointCreateOptions struct {
 	IP            string            `json:"ip"`
+	IPv6          string            `json:"ipv6"`
 	Domain        string            `json:"domain"`
 	Ports         map[uint16]string `json:"ports"`
 	RouteStrategy string            `json:"route_strategy"`
+	Affinity      int               `json:"affinity"`
+	Weights       map[string]int    `json:"weights"`
 	Policy        string            `json:"policy"`
 	BindStrategy  string            `json:"bind_strategy"`
+	NodePorts     map[uint16]uint16 `json:"node_ports"`
+	Headless      bool              `json:"headless"`
+	PortNames     map[uint16]string `json:"port_names"`
+	External      bool              `json:"external"`
+	ExternalName  string            `json:"external_name"`
+	Upstreams     []string          `json:"upstreams"`
 }
 
 // swagger:ignore
 type EndpointUpdateOptions struct {
 	IP            *string           `json:"ip"`
+	IPv6          *string           `json:"ipv6"`
 	Ports         map[uint16]string `json:"ports"`
 	RouteStrategy string            `json:"route_strategy"`
+	Affinity      int               `json:"affinity"`
+	Weights       map[string]int    `json:"weights"`
 	Policy        string            `json:"policy"`
 	BindStrategy  string            `json:"bind_strategy"`
+	NodePorts     map[uint16]uint16 `json:"node_ports"`
+	Headless      bool              `json:"headless"`
+	PortNames     map[uint16]string `json:"port_names"`
+	External      bool              `json:"external"`
+	ExternalName  string            `json:"external_name"`
+	Upstreams     []string          `json:"upstreams"`
 }
 
 func NewEndpointList() *EndpointList {
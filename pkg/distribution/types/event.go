@@ -80,6 +80,11 @@ type ConfigEvent struct {
 	Data *Config
 }
 
+type NetworkPolicyEvent struct {
+	event
+	Data *NetworkPolicy
+}
+
 type RouteEvent struct {
 	event
 	Data *Route
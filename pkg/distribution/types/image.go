@@ -18,7 +18,10 @@
 
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Image struct {
 	Meta   ImageMeta
@@ -31,6 +34,8 @@ type ImageMeta struct {
 	Digest string `json:"digest"`
 	Name string   `json:"name"`
 	Tags []string `json:"tags"`
+	// Created is image build time
+	Created time.Time `json:"created"`
 }
 
 type ImageStatus struct {
@@ -0,0 +1,106 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// IPAMPoolService is a pool of services IPv4 addresses
+	IPAMPoolService = "service"
+	// IPAMPoolServiceIPv6 is a pool of services IPv6 addresses
+	IPAMPoolServiceIPv6 = "service_v6"
+	// IPAMPoolSubnet is a pool of nodes pod subnets
+	IPAMPoolSubnet = "subnet"
+	// IPAMPoolNodePort is a pool of ports exposed on all nodes
+	IPAMPoolNodePort = "node_port"
+)
+
+// IPAMPool describes address range leases are allocated from,
+// every lease of pool is a network with pool prefix length.
+// Node ports pool stores ports range as CIDR, leases are port numbers
+type IPAMPool struct {
+	System
+	Name   string `json:"name"`
+	CIDR   string `json:"cidr"`
+	Prefix int    `json:"prefix"`
+	Size   int    `json:"size"`
+}
+
+type IPAMPoolMap struct {
+	System
+	Items map[string]*IPAMPool
+}
+
+// IPAMLease is an address or subnet allocated from pool,
+// leases are stored by key one per address to prevent double allocation
+type IPAMLease struct {
+	System
+	Pool    string    `json:"pool"`
+	Addr    string    `json:"addr"`
+	Owner   string    `json:"owner"`
+	Created time.Time `json:"created"`
+}
+
+type IPAMLeaseMap struct {
+	System
+	Items map[string]*IPAMLease
+}
+
+// IPAMUtilization is an usage of IPAM pools
+type IPAMUtilization struct {
+	Pools map[string]*IPAMPoolUtilization `json:"pools"`
+}
+
+type IPAMPoolUtilization struct {
+	CIDR      string `json:"cidr"`
+	Prefix    int    `json:"prefix"`
+	Size      int    `json:"size"`
+	Leased    int    `json:"leased"`
+	Available int    `json:"available"`
+}
+
+// IPAMLeaseKey returns storage key of lease,
+// subnet leases are stored by network address because prefix is the same in pool
+func IPAMLeaseKey(addr string) string {
+	if i := strings.Index(addr, "/"); i > 0 {
+		return addr[:i]
+	}
+	return addr
+}
+
+func NewIPAMPoolMap() *IPAMPoolMap {
+	dm := new(IPAMPoolMap)
+	dm.Items = make(map[string]*IPAMPool)
+	return dm
+}
+
+func NewIPAMLeaseMap() *IPAMLeaseMap {
+	dm := new(IPAMLeaseMap)
+	dm.Items = make(map[string]*IPAMLease)
+	return dm
+}
+
+func NewIPAMUtilization() *IPAMUtilization {
+	u := new(IPAMUtilization)
+	u.Pools = make(map[string]*IPAMPoolUtilization)
+	return u
+}
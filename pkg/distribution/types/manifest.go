@@ -21,24 +21,30 @@ package types
 import (
 	"github.com/lastbackend/lastbackend/pkg/util/compare"
 	"github.com/lastbackend/lastbackend/pkg/util/resource"
+	"reflect"
 	"strings"
 	"time"
 )
 
 type NodeManifest struct {
-	Meta      NodeManifestMeta             `json:"meta"`
-	Resolvers map[string]*ResolverManifest `json:"resolvers"`
-	Exporter  *ExporterManifest            `json:"exporter"`
-	Secrets   map[string]*SecretManifest   `json:"secrets"`
-	Configs   map[string]*ConfigManifest   `json:"configs"`
-	Endpoints map[string]*EndpointManifest `json:"endpoint"`
-	Network   map[string]*SubnetManifest   `json:"network"`
-	Pods      map[string]*PodManifest      `json:"pods"`
-	Volumes   map[string]*VolumeManifest   `json:"volumes"`
+	Meta      NodeManifestMeta                  `json:"meta"`
+	Resolvers map[string]*ResolverManifest      `json:"resolvers"`
+	Exporter  *ExporterManifest                 `json:"exporter"`
+	Secrets   map[string]*SecretManifest        `json:"secrets"`
+	Configs   map[string]*ConfigManifest        `json:"configs"`
+	Endpoints map[string]*EndpointManifest      `json:"endpoint"`
+	Network   map[string]*SubnetManifest        `json:"network"`
+	Pods      map[string]*PodManifest           `json:"pods"`
+	Volumes   map[string]*VolumeManifest        `json:"volumes"`
+	Policies  map[string]*NetworkPolicyManifest `json:"policies"`
 }
 
 type NodeManifestMeta struct {
 	Initial bool `json:"initial"`
+	// Pod subnet allocated for node from cluster CIDR
+	Subnet string `json:"subnet,omitempty"`
+	// Node next network key accepted by all nodes, node switches to it
+	NetworkKey string `json:"network_key,omitempty"`
 }
 
 type ResolverManifest struct {
@@ -160,12 +166,15 @@ type ManifestSpecRuntimeTaskCommand struct {
 }
 
 type ManifestSpecTemplate struct {
-	Containers []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Volumes    []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Containers  []ManifestSpecTemplateContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Volumes     []ManifestSpecTemplateVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Termination int                             `json:"termination,omitempty" yaml:"termination,omitempty"`
 }
 
 type ManifestSpecTemplateContainer struct {
 	Name          string                                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Role          string                                 `json:"role,omitempty" yaml:"role,omitempty"`
+	Order         int                                    `json:"order,omitempty" yaml:"order,omitempty"`
 	Command       string                                 `json:"command,omitempty" yaml:"command,omitempty"`
 	Workdir       string                                 `json:"workdir,omitempty" yaml:"workdir,omitempty"`
 	Entrypoint    string                                 `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
@@ -177,6 +186,8 @@ type ManifestSpecTemplateContainer struct {
 	Resources     ManifestSpecTemplateContainerResources `json:"resources,omitempty" yaml:"resources,omitempty"`
 	RestartPolicy ManifestSpecTemplateRestartPolicy      `json:"restart,omitempty" yaml:"restart,omitempty"`
 	Security      ManifestSpecSecurity                   `json:"security,omitempty" yaml:"security,omitempty"`
+	Probes        ManifestSpecTemplateContainerProbes    `json:"probes,omitempty" yaml:"probes,omitempty"`
+	Lifecycle     ManifestSpecTemplateContainerLifecycle `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
 }
 
 type ManifestSpecTemplateContainerEnv struct {
@@ -282,7 +293,67 @@ type ManifestSpecTemplateRestartPolicy struct {
 }
 
 type ManifestSpecSecurity struct {
-	Privileged bool `json:"privileged"`
+	Privileged      bool                             `json:"privileged"`
+	User            int                              `json:"user,omitempty" yaml:"user,omitempty"`
+	Group           int                              `json:"group,omitempty" yaml:"group,omitempty"`
+	Groups          []int                            `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Capabilities    ManifestSpecSecurityCapabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	ReadOnlyRootfs  bool                             `json:"readonly_rootfs,omitempty" yaml:"readonly_rootfs,omitempty"`
+	NoNewPrivileges bool                             `json:"no_new_privileges,omitempty" yaml:"no_new_privileges,omitempty"`
+	Seccomp         string                           `json:"seccomp,omitempty" yaml:"seccomp,omitempty"`
+	AppArmor        string                           `json:"apparmor,omitempty" yaml:"apparmor,omitempty"`
+	SELinux         ManifestSpecSecuritySELinux      `json:"selinux,omitempty" yaml:"selinux,omitempty"`
+}
+
+type ManifestSpecSecurityCapabilities struct {
+	Add  []string `json:"add,omitempty" yaml:"add,omitempty"`
+	Drop []string `json:"drop,omitempty" yaml:"drop,omitempty"`
+}
+
+type ManifestSpecSecuritySELinux struct {
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
+	Role  string `json:"role,omitempty" yaml:"role,omitempty"`
+	Type  string `json:"type,omitempty" yaml:"type,omitempty"`
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbes struct {
+	Ready ManifestSpecTemplateContainerProbe `json:"ready,omitempty" yaml:"ready,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbe struct {
+	Exec             ManifestSpecTemplateContainerHookExec    `json:"exec,omitempty" yaml:"exec,omitempty"`
+	Socket           ManifestSpecTemplateContainerProbeSocket `json:"socket,omitempty" yaml:"socket,omitempty"`
+	HTTP             ManifestSpecTemplateContainerHookHTTP    `json:"http,omitempty" yaml:"http,omitempty"`
+	InitialDelay     int                                      `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+	Timeout          int                                      `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Period           int                                      `json:"period,omitempty" yaml:"period,omitempty"`
+	ThresholdSuccess int                                      `json:"threshold_success,omitempty" yaml:"threshold_success,omitempty"`
+	ThresholdFailure int                                      `json:"threshold_failure,omitempty" yaml:"threshold_failure,omitempty"`
+	StartupTimeout   int                                      `json:"startup_timeout,omitempty" yaml:"startup_timeout,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbeSocket struct {
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+type ManifestSpecTemplateContainerLifecycle struct {
+	PreStop ManifestSpecTemplateContainerHook `json:"pre_stop,omitempty" yaml:"pre_stop,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHook struct {
+	Exec ManifestSpecTemplateContainerHookExec `json:"exec,omitempty" yaml:"exec,omitempty"`
+	HTTP ManifestSpecTemplateContainerHookHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookExec struct {
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+type ManifestSpecTemplateContainerHookHTTP struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
 }
 
 func (m ManifestSpecSelector) GetSpec() SpecSelector {
@@ -307,6 +378,8 @@ func (m ManifestSpecTemplate) GetSpec() SpecTemplate {
 		s.Volumes = append(s.Volumes, &sp)
 	}
 
+	s.Termination = m.Termination
+
 	return s
 }
 
@@ -387,9 +460,50 @@ func (m ManifestSpecTemplateVolume) GetSpec() SpecTemplateVolume {
 	return s
 }
 
+func (m ManifestSpecTemplateContainerProbe) GetSpec() SpecTemplateContainerProbe {
+	p := SpecTemplateContainerProbe{}
+
+	p.Exec.Command = m.Exec.Command
+	p.Socket.Protocol = m.Socket.Protocol
+	p.Socket.Port = m.Socket.Port
+	p.HTTP.Path = m.HTTP.Path
+	p.HTTP.Port = m.HTTP.Port
+	p.InitialDelaySeconds = m.InitialDelay
+	p.TimeoutSeconds = m.Timeout
+	p.PeriodSeconds = m.Period
+	p.ThresholdSuccess = m.ThresholdSuccess
+	p.ThresholdFailure = m.ThresholdFailure
+	p.StartupTimeoutSeconds = m.StartupTimeout
+
+	return p
+}
+
+func (m ManifestSpecSecurity) GetSpec() SpecTemplateContainerSecurity {
+	s := SpecTemplateContainerSecurity{}
+
+	s.Privileged = m.Privileged
+	s.User = m.User
+	s.Group = m.Group
+	s.Groups = m.Groups
+	s.Capabilities.Add = m.Capabilities.Add
+	s.Capabilities.Drop = m.Capabilities.Drop
+	s.ReadOnlyRootfs = m.ReadOnlyRootfs
+	s.NoNewPrivileges = m.NoNewPrivileges
+	s.LinuxOptions.Seccomp = m.Seccomp
+	s.LinuxOptions.AppArmor = m.AppArmor
+	s.LinuxOptions.User = m.SELinux.User
+	s.LinuxOptions.Role = m.SELinux.Role
+	s.LinuxOptions.Type = m.SELinux.Type
+	s.LinuxOptions.Level = m.SELinux.Level
+
+	return s
+}
+
 func (m ManifestSpecTemplateContainer) GetSpec() SpecTemplateContainer {
 	s := SpecTemplateContainer{}
 	s.Name = m.Name
+	s.Role = m.Role
+	s.Order = m.Order
 
 	s.RestartPolicy.Policy = m.RestartPolicy.Policy
 	s.RestartPolicy.Attempt = m.RestartPolicy.Attempt
@@ -430,7 +544,11 @@ func (m ManifestSpecTemplateContainer) GetSpec() SpecTemplateContainer {
 	s.Image.Secret.Name = m.Image.Secret.Name
 	s.Image.Secret.Key = m.Image.Secret.Key
 
-	s.Security.Privileged = m.Security.Privileged
+	s.Security = m.Security.GetSpec()
+	s.Probes.ReadProbe = m.Probes.Ready.GetSpec()
+	s.Lifecycle.PreStop.Exec.Command = m.Lifecycle.PreStop.Exec.Command
+	s.Lifecycle.PreStop.HTTP.Path = m.Lifecycle.PreStop.HTTP.Path
+	s.Lifecycle.PreStop.HTTP.Port = m.Lifecycle.PreStop.HTTP.Port
 
 	if m.Resources.Request.RAM != EmptyString {
 		s.Resources.Request.RAM, _ = resource.DecodeMemoryResource(m.Resources.Request.RAM)
@@ -643,6 +761,11 @@ func (m ManifestSpecSelector) SetSpecSelector(ss *SpecSelector) {
 
 func (m ManifestSpecTemplate) SetSpecTemplate(st *SpecTemplate) error {
 
+	if m.Termination != 0 && st.Termination != m.Termination {
+		st.Termination = m.Termination
+		st.Updated = time.Now()
+	}
+
 	for _, c := range m.Containers {
 
 		var (
@@ -703,8 +826,34 @@ func (m ManifestSpecTemplate) SetSpecTemplate(st *SpecTemplate) error {
 			st.Updated = time.Now()
 		}
 
-		if spec.Security.Privileged != c.Security.Privileged {
-			spec.Security.Privileged = c.Security.Privileged
+		security := c.Security.GetSpec()
+		if !reflect.DeepEqual(spec.Security, security) {
+			spec.Security = security
+			st.Updated = time.Now()
+		}
+
+		if spec.Role != c.Role || spec.Order != c.Order {
+			spec.Role = c.Role
+			spec.Order = c.Order
+			st.Updated = time.Now()
+		}
+
+		probe := c.Probes.Ready.GetSpec()
+		if len(probe.Exec.Command) == 0 && len(spec.Probes.ReadProbe.Exec.Command) == 0 {
+			probe.Exec = spec.Probes.ReadProbe.Exec
+		}
+
+		if !reflect.DeepEqual(spec.Probes.ReadProbe, probe) {
+			spec.Probes.ReadProbe = probe
+			st.Updated = time.Now()
+		}
+
+		if strings.Join(spec.Lifecycle.PreStop.Exec.Command, " ") != strings.Join(c.Lifecycle.PreStop.Exec.Command, " ") ||
+			spec.Lifecycle.PreStop.HTTP.Path != c.Lifecycle.PreStop.HTTP.Path ||
+			spec.Lifecycle.PreStop.HTTP.Port != c.Lifecycle.PreStop.HTTP.Port {
+			spec.Lifecycle.PreStop.Exec.Command = c.Lifecycle.PreStop.Exec.Command
+			spec.Lifecycle.PreStop.HTTP.Path = c.Lifecycle.PreStop.HTTP.Path
+			spec.Lifecycle.PreStop.HTTP.Port = c.Lifecycle.PreStop.HTTP.Port
 			st.Updated = time.Now()
 		}
 
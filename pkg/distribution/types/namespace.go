@@ -20,7 +20,10 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
+	"strings"
 )
 
 // swagger:ignore
@@ -37,6 +40,7 @@ type NamespaceList struct {
 
 // swagger:ignore
 type Namespace struct {
+	System
 	Meta   NamespaceMeta   `json:"meta"`
 	Status NamespaceStatus `json:"status"`
 	Spec   NamespaceSpec   `json:"spec"`
@@ -62,9 +66,28 @@ type NamespaceMeta struct {
 
 // swagger:ignore
 type NamespaceSpec struct {
-	Resources ResourceRequest `json:"resources"`
-	Env       NamespaceEnvs   `json:"env"`
-	Domain    NamespaceDomain `json:"domain"`
+	Resources ResourceRequest   `json:"resources"`
+	Env       NamespaceEnvs     `json:"env"`
+	Domain    NamespaceDomain   `json:"domain"`
+	Security  NamespaceSecurity `json:"security"`
+	Quotas    NamespaceQuotas   `json:"quotas"`
+}
+
+// swagger:ignore
+type NamespaceQuotas struct {
+	// Max count of objects in namespace, 0 means unlimited
+	Services int `json:"services"`
+	Jobs     int `json:"jobs"`
+	Routes   int `json:"routes"`
+	Volumes  int `json:"volumes"`
+}
+
+// swagger:ignore
+type NamespaceSecurity struct {
+	// Reject pods with privileged containers
+	DenyPrivileged bool `json:"deny_privileged"`
+	// Deny pods traffic not allowed by network policies
+	DenyNetwork bool `json:"deny_network"`
 }
 
 type NamespaceStatus struct {
@@ -122,6 +145,15 @@ type ResourceItem struct {
 	Storage int64 `json:"storage"`
 }
 
+// Diff returns change of resources from rr to r
+func (r ResourceItem) Diff(rr ResourceItem) ResourceItem {
+	return ResourceItem{
+		RAM:     r.RAM - rr.RAM,
+		CPU:     r.CPU - rr.CPU,
+		Storage: r.Storage - rr.Storage,
+	}
+}
+
 func (n *Namespace) SelfLink() *NamespaceSelfLink {
 	return &n.Meta.SelfLink
 }
@@ -165,6 +197,29 @@ type NamespaceRemoveOptions struct {
 	Force bool `json:"force"`
 }
 
+const (
+	ApplyActionCreate    = "create"
+	ApplyActionUpdate    = "update"
+	ApplyActionDelete    = "delete"
+	ApplyActionUnchanged = "unchanged"
+)
+
+// swagger:ignore
+type NamespaceApplyPlan struct {
+	Configs  map[string]*NamespaceApplyStep
+	Secrets  map[string]*NamespaceApplyStep
+	Volumes  map[string]*NamespaceApplyStep
+	Services map[string]*NamespaceApplyStep
+	Jobs     map[string]*NamespaceApplyStep
+	Routes   map[string]*NamespaceApplyStep
+}
+
+// swagger:ignore
+type NamespaceApplyStep struct {
+	Action  string
+	Changes []*compare.Change
+}
+
 // swagger:ignore
 type NamespaceResourcesOptions struct {
 	Request *ResourceRequestItemOption `json:"request"`
@@ -178,6 +233,56 @@ type ResourceRequestItemOption struct {
 	Storage *string `json:"storage"`
 }
 
+func NewNamespaceApplyPlan() *NamespaceApplyPlan {
+	p := new(NamespaceApplyPlan)
+	p.Configs = make(map[string]*NamespaceApplyStep, 0)
+	p.Secrets = make(map[string]*NamespaceApplyStep, 0)
+	p.Volumes = make(map[string]*NamespaceApplyStep, 0)
+	p.Services = make(map[string]*NamespaceApplyStep, 0)
+	p.Jobs = make(map[string]*NamespaceApplyStep, 0)
+	p.Routes = make(map[string]*NamespaceApplyStep, 0)
+	return p
+}
+
+// privilegedCapabilities - capabilities which give container the same control over node as privileged mode
+var privilegedCapabilities = map[string]struct{}{
+	"ALL":             {},
+	"SYS_ADMIN":       {},
+	"SYS_MODULE":      {},
+	"SYS_RAWIO":       {},
+	"SYS_PTRACE":      {},
+	"SYS_BOOT":        {},
+	"SYS_TIME":        {},
+	"NET_ADMIN":       {},
+	"DAC_READ_SEARCH": {},
+	"MAC_ADMIN":       {},
+	"MAC_OVERRIDE":    {},
+	"BPF":             {},
+	"PERFMON":         {},
+}
+
+// ValidateSecurity checks spec template against namespace security policy
+func (n *Namespace) ValidateSecurity(spec SpecTemplate) error {
+
+	if !n.Spec.Security.DenyPrivileged {
+		return nil
+	}
+
+	for _, c := range spec.Containers {
+		if c.Security.Privileged {
+			return errors.New(errors.PrivilegedContainersDenied)
+		}
+
+		for _, cp := range c.Security.Capabilities.Add {
+			if _, ok := privilegedCapabilities[strings.TrimPrefix(strings.ToUpper(cp), "CAP_")]; ok {
+				return errors.New(errors.PrivilegedCapabilitiesDenied)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (n *Namespace) AllocateResources(resources ResourceRequest) error {
 
 	var (
@@ -229,6 +334,108 @@ func (n *Namespace) AllocateResources(resources ResourceRequest) error {
 	return nil
 }
 
+// AllocateStorage reserves volume storage in namespace storage limit
+func (n *Namespace) AllocateStorage(storage int64) error {
+
+	available := n.Spec.Resources.Limits.Storage
+	allocated := n.Status.Resources.Allocated.Storage
+
+	if available > 0 && (available-allocated-storage) < 0 {
+		return errors.New(errors.ResourcesStorageLimitExceeded)
+	}
+
+	n.Status.Resources.Allocated.Storage = allocated + storage
+	return nil
+}
+
+func (n *Namespace) ReleaseStorage(storage int64) {
+
+	allocated := n.Status.Resources.Allocated.Storage - storage
+	if allocated < 0 {
+		allocated = 0
+	}
+
+	n.Status.Resources.Allocated.Storage = allocated
+}
+
+// AllocateChange applies change of allocated resources to namespace,
+// increased resources are checked against namespace limits, released resources are not
+func (n *Namespace) AllocateChange(change ResourceItem) error {
+
+	var (
+		limits    = n.Spec.Resources.Limits
+		allocated = n.Status.Resources.Allocated
+	)
+
+	allocated.RAM += change.RAM
+	allocated.CPU += change.CPU
+	allocated.Storage += change.Storage
+
+	switch true {
+	case change.RAM > 0 && limits.RAM > 0 && allocated.RAM > limits.RAM:
+		return errors.New(errors.ResourcesRamLimitExceeded)
+	case change.CPU > 0 && limits.CPU > 0 && allocated.CPU > limits.CPU:
+		return errors.New(errors.ResourcesCpuLimitExceeded)
+	case change.Storage > 0 && limits.Storage > 0 && allocated.Storage > limits.Storage:
+		return errors.New(errors.ResourcesStorageLimitExceeded)
+	}
+
+	if allocated.RAM < 0 {
+		allocated.RAM = 0
+	}
+
+	if allocated.CPU < 0 {
+		allocated.CPU = 0
+	}
+
+	if allocated.Storage < 0 {
+		allocated.Storage = 0
+	}
+
+	n.Status.Resources.Allocated = allocated
+	return nil
+}
+
+// ValidateResources checks that namespace limits cover already allocated resources
+func (n *Namespace) ValidateResources() error {
+
+	var (
+		limits    = n.Spec.Resources.Limits
+		allocated = n.Status.Resources.Allocated
+	)
+
+	if (limits.RAM > 0 && limits.RAM < allocated.RAM) ||
+		(limits.CPU > 0 && limits.CPU < allocated.CPU) ||
+		(limits.Storage > 0 && limits.Storage < allocated.Storage) {
+		return errors.New(errors.ResourcesLimitsLessThanAllocated)
+	}
+
+	return nil
+}
+
+// ValidateQuota checks that one more object of kind can be created in namespace
+func (n *Namespace) ValidateQuota(kind string, count int) error {
+
+	var quota int
+
+	switch kind {
+	case KindService:
+		quota = n.Spec.Quotas.Services
+	case KindJob:
+		quota = n.Spec.Quotas.Jobs
+	case KindRoute:
+		quota = n.Spec.Quotas.Routes
+	case KindVolume:
+		quota = n.Spec.Quotas.Volumes
+	}
+
+	if quota > 0 && count >= quota {
+		return errors.New(fmt.Sprintf("%s quota exceeded: %d of %d", kind, count, quota))
+	}
+
+	return nil
+}
+
 func (n *Namespace) ReleaseResources(resources ResourceRequest) {
 
 	var (
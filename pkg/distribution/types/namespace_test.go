@@ -0,0 +1,147 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespace_ValidateSecurity(t *testing.T) {
+
+	container := func(privileged bool, caps ...string) SpecTemplateContainer {
+		c := SpecTemplateContainer{}
+		c.Security.Privileged = privileged
+		c.Security.Capabilities.Add = caps
+		return c
+	}
+
+	var tests = []struct {
+		name      string
+		deny      bool
+		container SpecTemplateContainer
+		err       string
+	}{
+		{
+			name:      "check privileged container allowed",
+			container: container(true, "SYS_ADMIN"),
+		},
+		{
+			name:      "check privileged container denied",
+			deny:      true,
+			container: container(true),
+			err:       "privileged containers are not allowed in namespace",
+		},
+		{
+			name:      "check all capabilities denied",
+			deny:      true,
+			container: container(false, "ALL"),
+			err:       "privileged capabilities are not allowed in namespace",
+		},
+		{
+			name:      "check prefixed capability denied",
+			deny:      true,
+			container: container(false, "NET_BIND_SERVICE", "cap_sys_admin"),
+			err:       "privileged capabilities are not allowed in namespace",
+		},
+		{
+			name:      "check unprivileged capability allowed",
+			deny:      true,
+			container: container(false, "NET_BIND_SERVICE", "CHOWN"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			ns := new(Namespace)
+			ns.Spec.Security.DenyPrivileged = tc.deny
+
+			spec := SpecTemplate{}
+			spec.Containers = append(spec.Containers, &tc.container)
+
+			err := ns.ValidateSecurity(spec)
+			if tc.err == EmptyString {
+				assert.NoError(t, err)
+				return
+			}
+
+			if assert.Error(t, err) {
+				assert.Equal(t, tc.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestNamespace_AllocateChange(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		allocated ResourceItem
+		change    ResourceItem
+		expect    ResourceItem
+		err       string
+	}{
+		{
+			name:      "check allocation in limits",
+			allocated: ResourceItem{RAM: 256, CPU: 100, Storage: 512},
+			change:    ResourceItem{RAM: 256, CPU: 100, Storage: 512},
+			expect:    ResourceItem{RAM: 512, CPU: 200, Storage: 1024},
+		},
+		{
+			name:      "check ram limit exceeded",
+			allocated: ResourceItem{RAM: 768},
+			change:    ResourceItem{RAM: 512},
+			expect:    ResourceItem{RAM: 768},
+			err:       "resources ram limit exceeded",
+		},
+		{
+			name:      "check storage limit exceeded",
+			allocated: ResourceItem{Storage: 1024},
+			change:    ResourceItem{Storage: 1},
+			expect:    ResourceItem{Storage: 1024},
+			err:       "resources storage limit exceeded",
+		},
+		{
+			name:      "check release is not checked against limits",
+			allocated: ResourceItem{RAM: 2048, CPU: 100},
+			change:    ResourceItem{RAM: -512, CPU: -200},
+			expect:    ResourceItem{RAM: 1536},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			ns := new(Namespace)
+			ns.Spec.Resources.Limits = ResourceItem{RAM: 1024, CPU: 1000, Storage: 1024}
+			ns.Status.Resources.Allocated = tc.allocated
+
+			err := ns.AllocateChange(tc.change)
+			if tc.err == EmptyString {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Equal(t, tc.err, err.Error())
+			}
+
+			assert.Equal(t, tc.expect, ns.Status.Resources.Allocated)
+		})
+	}
+}
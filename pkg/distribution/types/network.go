@@ -36,6 +36,8 @@ type NetworkStatus struct {
 }
 
 type NetworkSpec struct {
+	// Cluster network driver, all nodes should use it
+	Type string `json:"type"`
 }
 
 type NetworkState struct {
@@ -79,12 +81,18 @@ type SubnetSpec struct {
 	Type string `json:"type"`
 	// Node Subnet subnet info
 	CIDR string `json:"cidr"`
+	// Node Subnet IPv6 subnet info, empty if node network is not dual stack
+	CIDRv6 string `json:"cidr_v6,omitempty"`
 	// Node Subnet interface
 	IFace NetworkInterface `json:"iface"`
 	// Node Public IP
 	Addr string `json:"addr"`
 	// Node Internal IP
 	IP string `json:"ip"`
+	// Node network public key for encrypted overlay
+	Key string `json:"key,omitempty"`
+	// Node network public key announced for rotation, node switches to it when all nodes accept it
+	KeyNext string `json:"key_next,omitempty"`
 }
 
 func SubnetGetNameFromCIDR(CIDR string) string {
@@ -98,6 +106,8 @@ func SubnetSpecEqual(n *SubnetSpec, nt *SubnetSpec) bool {
 		return false
 	case n.CIDR == nt.CIDR:
 		return false
+	case n.CIDRv6 == nt.CIDRv6:
+		return false
 	case n.IFace.Index == nt.IFace.Index:
 		return false
 	case n.IFace.Name == nt.IFace.Name:
@@ -108,10 +118,35 @@ func SubnetSpecEqual(n *SubnetSpec, nt *SubnetSpec) bool {
 		return false
 	case n.Addr == nt.Addr:
 		return false
+	case n.Key == nt.Key:
+		return false
+	case n.KeyNext == nt.KeyNext:
+		return false
 	}
 	return true
 }
 
+// SubnetKeyNextAccepted checks announced next key of subnet is accepted by all other online nodes
+func SubnetKeyNextAccepted(snet *Subnet, nodes []*Node) bool {
+
+	if snet.Spec.KeyNext == EmptyString {
+		return false
+	}
+
+	for _, n := range nodes {
+
+		if n.SelfLink().String() == snet.Meta.Node || !n.Status.Online {
+			continue
+		}
+
+		if n.Status.Network.Keys[snet.Spec.CIDR] != snet.Spec.KeyNext {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s Subnet) SelfLink() *SubnetSelfLink {
 	return &s.Meta.SelfLink
 }
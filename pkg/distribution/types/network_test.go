@@ -189,7 +189,51 @@ func TestNotEqual(t *testing.T) {
 		Addr: "10.0.0.1",
 	}
 
+	assets["key"] = &types.SubnetSpec{
+		Type: "vxlan",
+		CIDR: "10.0.0.0/24",
+		IFace: types.NetworkInterface{
+			Index: 1,
+			Name:  "lb.1",
+			Addr:  "10.0.0.1",
+			HAddr: "b6:3c:b9:62:e8:fe",
+		},
+		Addr: "10.0.0.0",
+		Key:  "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=",
+	}
+
 	for attr, asset := range assets {
 		assert.Equal(t, false, types.SubnetSpecEqual(network, asset), attr)
 	}
 }
+
+func TestSubnetKeyNextAccepted(t *testing.T) {
+
+	var node = func(name string, online bool, keys map[string]string) *types.Node {
+		n := new(types.Node)
+		n.Meta.Name = name
+		n.Meta.SelfLink = *types.NewNodeSelfLink(name)
+		n.Status.Online = online
+		n.Status.Network.Keys = keys
+		return n
+	}
+
+	var snet = new(types.Subnet)
+	snet.Meta.Node = types.NewNodeSelfLink("node-1").String()
+	snet.Spec.CIDR = "10.0.1.0/24"
+	snet.Spec.Key = "current"
+
+	nodes := []*types.Node{
+		node("node-1", true, nil),
+		node("node-2", true, map[string]string{"10.0.1.0/24": "next"}),
+		node("node-3", false, nil),
+	}
+
+	assert.False(t, types.SubnetKeyNextAccepted(snet, nodes), "subnet without next key")
+
+	snet.Spec.KeyNext = "next"
+	assert.True(t, types.SubnetKeyNextAccepted(snet, nodes), "next key accepted by online nodes")
+
+	nodes = append(nodes, node("node-4", true, map[string]string{"10.0.1.0/24": "previous"}))
+	assert.False(t, types.SubnetKeyNextAccepted(snet, nodes), "next key is not accepted by node-4")
+}
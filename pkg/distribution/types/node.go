@@ -20,6 +20,9 @@ package types
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strconv"
 )
 
 // swagger:ignore
@@ -85,6 +88,12 @@ func (m *NodeMeta) Set(meta *NodeUpdateMetaOptions) {
 	if meta.CIDR != nil {
 		m.CIDR = *meta.CIDR
 	}
+	if meta.NetworkKey != nil {
+		m.NetworkKey = *meta.NetworkKey
+	}
+	if meta.API != nil {
+		m.API = *meta.API
+	}
 
 }
 
@@ -101,6 +110,18 @@ type NodeInfo struct {
 	ExternalIP string `json:"external_ip"`
 	InternalIP string `json:"internal_ip"`
 	CIDR       string `json:"cidr"`
+	// NetworkKey - node public key in encrypted overlay network
+	NetworkKey string `json:"network_key,omitempty"`
+	// API - node http server endpoint used by cluster api to reach node
+	API NodeAPI `json:"api"`
+}
+
+// swagger:model types_node_api
+type NodeAPI struct {
+	// Address - host:port of node http server
+	Address string `json:"address"`
+	// Scheme - http or https
+	Scheme string `json:"scheme"`
 }
 
 // swagger:model types_node_status
@@ -113,6 +134,20 @@ type NodeStatus struct {
 	Capacity NodeResources `json:"capacity"`
 	// Node Allocated
 	Allocated NodeResources `json:"allocated"`
+	// Node pressure conditions
+	Pressure NodeStatusPressure `json:"pressure"`
+	// Node network state
+	Network NodeStatusNetwork `json:"network"`
+}
+
+type NodeStatusNetwork struct {
+	// Next keys of cluster subnets accepted by node during keys rotation
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+type NodeStatusPressure struct {
+	// Disk usage is over image gc high threshold
+	Disk bool `json:"disk"`
 }
 
 type NodeStatusState struct {
@@ -132,7 +167,14 @@ type NodeStatusInterfaceState struct {
 // swagger:ignore
 // swagger:model types_node_spec
 type NodeSpec struct {
-	Security NodeSecurity `json:"security"`
+	Security NodeSecurity    `json:"security"`
+	Network  NodeSpecNetwork `json:"network"`
+}
+
+// NodeSpecNetwork - node network allocated by controller
+type NodeSpecNetwork struct {
+	// Pod subnet leased from cluster CIDR
+	CIDR string `json:"cidr"`
 }
 
 type NodeSecurity struct {
@@ -197,13 +239,15 @@ type NodeUpdateMetaOptions struct {
 }
 
 type NodeUpdateInfoOptions struct {
-	Hostname     *string `json:"hostname"`
-	Architecture *string `json:"architecture"`
-	OSName       *string `json:"os_name"`
-	OSType       *string `json:"os_type"`
-	ExternalIP   *string `json:"external_ip"`
-	InternalIP   *string `json:"internal_ip"`
-	CIDR         *string `json:"cidr"`
+	Hostname     *string  `json:"hostname"`
+	Architecture *string  `json:"architecture"`
+	OSName       *string  `json:"os_name"`
+	OSType       *string  `json:"os_type"`
+	ExternalIP   *string  `json:"external_ip"`
+	InternalIP   *string  `json:"internal_ip"`
+	CIDR         *string  `json:"cidr"`
+	NetworkKey   *string  `json:"network_key"`
+	API          *NodeAPI `json:"api"`
 }
 
 func (o *NodeUpdateInfoOptions) Set(i NodeInfo) {
@@ -214,6 +258,8 @@ func (o *NodeUpdateInfoOptions) Set(i NodeInfo) {
 	o.ExternalIP = &i.ExternalIP
 	o.InternalIP = &i.InternalIP
 	o.CIDR = &i.CIDR
+	o.NetworkKey = &i.NetworkKey
+	o.API = &i.API
 }
 
 // swagger:ignore
@@ -229,6 +275,25 @@ func (n *Node) SelfLink() *NodeSelfLink {
 	return &n.Meta.SelfLink
 }
 
+// APIEndpoint returns url of node http server published in node meta.
+// Nodes connected before publishing it are reached on external ip and default port.
+func (n *Node) APIEndpoint() string {
+	var (
+		scheme  = n.Meta.API.Scheme
+		address = n.Meta.API.Address
+	)
+
+	if scheme == EmptyString {
+		scheme = "http"
+	}
+
+	if address == EmptyString {
+		address = net.JoinHostPort(n.Meta.ExternalIP, strconv.Itoa(DEFAULT_NODE_PORT))
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, address)
+}
+
 func NewNodeList() *NodeList {
 	dm := new(NodeList)
 	dm.Items = make([]*Node, 0)
@@ -103,6 +103,7 @@ type PodStatus struct {
 }
 
 type PodStatusRuntime struct {
+	Init     []*PodContainer                   `json:"init" yaml:"init"`
 	Services map[string]*PodContainer          `json:"containers" yaml:"containers"`
 	Pipeline map[string]*PodStatusPipelineStep `json:"pipeline" yaml:"pipeline"`
 }
@@ -132,6 +133,8 @@ type PodNetwork struct {
 	HostIP string `json:"host_ip" yaml:"host_ip"`
 	// Pod IP
 	PodIP string `json:"pod_ip" yaml:"pod_ip"`
+	// Pod IPv6, set if container runtime network is dual stack
+	PodIPv6 string `json:"pod_ipv6,omitempty" yaml:"pod_ipv6,omitempty"`
 }
 
 // PodContainer is a container of the pod
@@ -334,6 +337,8 @@ func (s *PodSpec) SetSpecTemplate(selflink string, template SpecTemplate) {
 	for _, v := range template.Volumes {
 		s.Template.Volumes = append(s.Template.Volumes, v)
 	}
+
+	s.Template.Termination = template.Termination
 }
 
 func (s *PodSpec) SetSpecSelector(selector SpecSelector) {
@@ -366,6 +371,7 @@ func NewPodStatus() *PodStatus {
 	status := PodStatus{
 		Steps: make(PodSteps, 0),
 		Runtime: PodStatusRuntime{
+			Init:     make([]*PodContainer, 0),
 			Services: make(map[string]*PodContainer, 0),
 			Pipeline: make(map[string]*PodStatusPipelineStep, 0),
 		},
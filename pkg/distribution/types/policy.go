@@ -0,0 +1,281 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	NetworkPolicyProtocolTCP = "tcp"
+	NetworkPolicyProtocolUDP = "udp"
+)
+
+// swagger:ignore
+// swagger:model types_network_policy
+type NetworkPolicy struct {
+	System
+	Meta NetworkPolicyMeta `json:"meta" yaml:"meta"`
+	Spec NetworkPolicySpec `json:"spec" yaml:"spec"`
+}
+
+// swagger:ignore
+type NetworkPolicyList struct {
+	System
+	Items []*NetworkPolicy
+}
+
+// swagger:ignore
+type NetworkPolicyMap struct {
+	System
+	Items map[string]*NetworkPolicy
+}
+
+// swagger:ignore
+// swagger:model types_network_policy_meta
+type NetworkPolicyMeta struct {
+	Meta      `yaml:",inline"`
+	Namespace string                `json:"namespace"`
+	SelfLink  NetworkPolicySelfLink `json:"self_link"`
+}
+
+// NetworkPolicySpec selects pods by labels and allows traffic matched by rules.
+// Ingress of selected pods is always isolated, egress is isolated if egress rules are set.
+type NetworkPolicySpec struct {
+	// Pods labels, empty selector selects all pods in namespace
+	Selector map[string]string `json:"selector" yaml:"selector"`
+	// Allowed incoming traffic
+	Ingress []*NetworkPolicyRule `json:"ingress" yaml:"ingress"`
+	// Allowed outgoing traffic
+	Egress []*NetworkPolicyRule `json:"egress" yaml:"egress"`
+}
+
+// NetworkPolicyRule matches peers by namespaces, pods labels and networks.
+// Rule without namespaces matches pods in namespace of policy,
+// rule without namespaces, labels and networks matches any peer.
+type NetworkPolicyRule struct {
+	Namespaces []string             `json:"namespaces" yaml:"namespaces"`
+	Labels     map[string]string    `json:"labels" yaml:"labels"`
+	Networks   []string             `json:"networks" yaml:"networks"`
+	Ports      []*NetworkPolicyPort `json:"ports" yaml:"ports"`
+}
+
+type NetworkPolicyPort struct {
+	Port     uint16 `json:"port" yaml:"port"`
+	Protocol string `json:"protocol" yaml:"protocol"`
+}
+
+// NetworkPolicyManifest is distributed to nodes per namespace
+// with pods addresses, nodes resolve it into traffic rules
+type NetworkPolicyManifest struct {
+	System
+	State    string                        `json:"state"`
+	Deny     bool                          `json:"deny"`
+	Policies map[string]*NetworkPolicySpec `json:"policies"`
+	Pods     map[string]*NetworkPolicyPod  `json:"pods"`
+}
+
+type NetworkPolicyPod struct {
+	IP     string            `json:"ip"`
+	IPv6   string            `json:"ipv6,omitempty"`
+	Labels map[string]string `json:"labels"`
+}
+
+// NetworkPolicyRules are traffic rules of pod resolved from network policies.
+// Only traffic matched by rules is allowed in isolated direction
+type NetworkPolicyRules struct {
+	IP             string
+	IPv6           string
+	IsolateIngress bool
+	IsolateEgress  bool
+	Ingress        []*NetworkPolicyTraffic
+	Egress         []*NetworkPolicyTraffic
+}
+
+type NetworkPolicyTraffic struct {
+	// Peers addresses and networks, nil means any peer
+	Peers []string
+	// Ports, empty means any port
+	Ports []*NetworkPolicyPort
+}
+
+func (p *NetworkPolicy) SelfLink() *NetworkPolicySelfLink {
+	return &p.Meta.SelfLink
+}
+
+// Selects checks if pod labels match policy selector
+func (s *NetworkPolicySpec) Selects(labels map[string]string) bool {
+	return networkPolicyLabelsMatch(s.Selector, labels)
+}
+
+// Any checks if rule matches any peer
+func (r *NetworkPolicyRule) Any() bool {
+	return len(r.Namespaces) == 0 && len(r.Labels) == 0 && len(r.Networks) == 0
+}
+
+func (m *NetworkPolicyManifest) Set(policies []*NetworkPolicy) {
+	m.Policies = make(map[string]*NetworkPolicySpec, 0)
+	for _, p := range policies {
+		spec := p.Spec
+		m.Policies[p.Meta.Name] = &spec
+	}
+}
+
+// ResolveNetworkPolicies resolves namespaces manifests into traffic rules of pods.
+// Rules are returned by pod name only for pods with isolated ingress or egress.
+func ResolveNetworkPolicies(manifests map[string]*NetworkPolicyManifest) map[string]*NetworkPolicyRules {
+
+	var rules = make(map[string]*NetworkPolicyRules, 0)
+
+	for namespace, m := range manifests {
+
+		if m == nil || m.State == StateDestroy {
+			continue
+		}
+
+		names := make([]string, 0)
+		for name := range m.Policies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for pod, p := range m.Pods {
+
+			if p.IP == EmptyString && p.IPv6 == EmptyString {
+				continue
+			}
+
+			r := &NetworkPolicyRules{
+				IP:             p.IP,
+				IPv6:           p.IPv6,
+				IsolateIngress: m.Deny,
+				IsolateEgress:  m.Deny,
+				Ingress:        make([]*NetworkPolicyTraffic, 0),
+				Egress:         make([]*NetworkPolicyTraffic, 0),
+			}
+
+			for _, name := range names {
+
+				spec := m.Policies[name]
+				if !spec.Selects(p.Labels) {
+					continue
+				}
+
+				r.IsolateIngress = true
+				if len(spec.Egress) > 0 {
+					r.IsolateEgress = true
+				}
+
+				r.Ingress = append(r.Ingress, resolveNetworkPolicyRules(manifests, namespace, spec.Ingress)...)
+				r.Egress = append(r.Egress, resolveNetworkPolicyRules(manifests, namespace, spec.Egress)...)
+			}
+
+			if r.IsolateIngress || r.IsolateEgress {
+				rules[pod] = r
+			}
+		}
+	}
+
+	return rules
+}
+
+func resolveNetworkPolicyRules(manifests map[string]*NetworkPolicyManifest, namespace string, rules []*NetworkPolicyRule) []*NetworkPolicyTraffic {
+
+	var traffic = make([]*NetworkPolicyTraffic, 0)
+
+	for _, rule := range rules {
+
+		t := new(NetworkPolicyTraffic)
+		t.Ports = rule.Ports
+
+		if rule.Any() {
+			traffic = append(traffic, t)
+			continue
+		}
+
+		t.Peers = make([]string, 0)
+		t.Peers = append(t.Peers, rule.Networks...)
+
+		if len(rule.Namespaces) != 0 || len(rule.Labels) != 0 {
+
+			namespaces := rule.Namespaces
+			if len(namespaces) == 0 {
+				namespaces = []string{namespace}
+			}
+
+			for _, ns := range namespaces {
+				m, ok := manifests[ns]
+				if !ok || m == nil || m.State == StateDestroy {
+					continue
+				}
+
+				for _, p := range m.Pods {
+					if !networkPolicyLabelsMatch(rule.Labels, p.Labels) {
+						continue
+					}
+					if p.IP != EmptyString {
+						t.Peers = append(t.Peers, p.IP)
+					}
+					if p.IPv6 != EmptyString {
+						t.Peers = append(t.Peers, p.IPv6)
+					}
+				}
+			}
+		}
+
+		// rule matches no peers at the moment
+		if len(t.Peers) == 0 {
+			continue
+		}
+
+		sort.Strings(t.Peers)
+		traffic = append(traffic, t)
+	}
+
+	return traffic
+}
+
+func networkPolicyLabelsMatch(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *NetworkPolicyPort) GetProtocol() string {
+	if p.Protocol == EmptyString {
+		return NetworkPolicyProtocolTCP
+	}
+	return strings.ToLower(p.Protocol)
+}
+
+func NewNetworkPolicyList() *NetworkPolicyList {
+	dm := new(NetworkPolicyList)
+	dm.Items = make([]*NetworkPolicy, 0)
+	return dm
+}
+
+func NewNetworkPolicyMap() *NetworkPolicyMap {
+	dm := new(NetworkPolicyMap)
+	dm.Items = make(map[string]*NetworkPolicy)
+	return dm
+}
@@ -0,0 +1,105 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types_test
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveNetworkPolicies(t *testing.T) {
+
+	var manifests = map[string]*types.NetworkPolicyManifest{
+		"demo": {
+			Policies: map[string]*types.NetworkPolicySpec{
+				"web": {
+					Selector: map[string]string{"app": "web"},
+					Ingress: []*types.NetworkPolicyRule{
+						{
+							Labels: map[string]string{"app": "proxy"},
+							Ports:  []*types.NetworkPolicyPort{{Port: 80}},
+						},
+						{
+							Namespaces: []string{"ops"},
+						},
+					},
+				},
+			},
+			Pods: map[string]*types.NetworkPolicyPod{
+				"web":   {IP: "10.0.0.2", Labels: map[string]string{"app": "web"}},
+				"proxy": {IP: "10.0.0.3", IPv6: "fd00::3", Labels: map[string]string{"app": "proxy"}},
+				"new":   {Labels: map[string]string{"app": "proxy"}},
+			},
+		},
+		"ops": {
+			Deny: true,
+			Policies: map[string]*types.NetworkPolicySpec{
+				"dns": {
+					Egress: []*types.NetworkPolicyRule{
+						{Ports: []*types.NetworkPolicyPort{{Port: 53, Protocol: types.NetworkPolicyProtocolUDP}}},
+					},
+				},
+			},
+			Pods: map[string]*types.NetworkPolicyPod{
+				"monitor": {IP: "10.0.1.2"},
+			},
+		},
+	}
+
+	rules := types.ResolveNetworkPolicies(manifests)
+
+	if !assert.Len(t, rules, 2, "rules count different") {
+		return
+	}
+
+	_, ok := rules["proxy"]
+	assert.False(t, ok, "pod without policies should not be isolated")
+
+	web := rules["web"]
+	if assert.NotNil(t, web) {
+		assert.Equal(t, "10.0.0.2", web.IP)
+		assert.True(t, web.IsolateIngress, "ingress should be isolated")
+		assert.False(t, web.IsolateEgress, "egress should not be isolated")
+		if assert.Len(t, web.Ingress, 2) {
+			assert.Equal(t, []string{"10.0.0.3", "fd00::3"}, web.Ingress[0].Peers)
+			assert.Equal(t, uint16(80), web.Ingress[0].Ports[0].Port)
+			assert.Equal(t, types.NetworkPolicyProtocolTCP, web.Ingress[0].Ports[0].GetProtocol())
+			assert.Equal(t, []string{"10.0.1.2"}, web.Ingress[1].Peers)
+		}
+	}
+
+	monitor := rules["monitor"]
+	if assert.NotNil(t, monitor) {
+		assert.True(t, monitor.IsolateIngress, "ingress should be isolated")
+		assert.True(t, monitor.IsolateEgress, "egress should be isolated")
+		assert.Len(t, monitor.Ingress, 0)
+		if assert.Len(t, monitor.Egress, 1) {
+			assert.Nil(t, monitor.Egress[0].Peers, "rule without peers should allow any peer")
+			assert.Equal(t, types.NetworkPolicyProtocolUDP, monitor.Egress[0].Ports[0].GetProtocol())
+		}
+	}
+
+	manifests["ops"].State = types.StateDestroy
+	rules = types.ResolveNetworkPolicies(manifests)
+	if assert.Len(t, rules, 1) && assert.NotNil(t, rules["web"]) {
+		assert.Len(t, rules["web"].Ingress, 1, "destroyed namespace peers should be skipped")
+	}
+}
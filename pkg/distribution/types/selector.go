@@ -0,0 +1,251 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	SelectorOperatorEqual     = "="
+	SelectorOperatorNotEqual  = "!="
+	SelectorOperatorExists    = "exists"
+	SelectorOperatorNotExists = "!exists"
+)
+
+// Selector is used to filter objects by labels and fields
+// Label selector example: app=web,tier!=db,env,!canary
+// Field selector example: meta.name=demo,status.state!=error
+type Selector struct {
+	Labels []SelectorRequirement
+	Fields []SelectorRequirement
+}
+
+type SelectorRequirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// ParseSelector creates selector from label and field selectors strings
+func ParseSelector(labels, fields string) (*Selector, error) {
+
+	var (
+		s   = new(Selector)
+		err error
+	)
+
+	if s.Labels, err = parseSelectorRequirements(labels, true); err != nil {
+		return nil, fmt.Errorf("invalid label selector: %s", err.Error())
+	}
+
+	if s.Fields, err = parseSelectorRequirements(fields, false); err != nil {
+		return nil, fmt.Errorf("invalid field selector: %s", err.Error())
+	}
+
+	return s, nil
+}
+
+func (s *Selector) Empty() bool {
+	return s == nil || (len(s.Labels) == 0 && len(s.Fields) == 0)
+}
+
+// Match checks if object satisfies all selector requirements.
+// Fields are addressed by json path of object, labels are taken from meta.labels
+func (s *Selector) Match(obj interface{}) bool {
+
+	if s.Empty() {
+		return true
+	}
+
+	if obj == nil {
+		return false
+	}
+
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		return false
+	}
+
+	var data = make(map[string]interface{})
+
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return false
+	}
+
+	labels := make(map[string]string)
+	if v, ok := selectorLookup(data, "meta.labels"); ok {
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, l := range m {
+				labels[k] = fmt.Sprint(l)
+			}
+		}
+	}
+
+	for _, r := range s.Labels {
+		v, ok := labels[r.Key]
+		if !r.match(v, ok) {
+			return false
+		}
+	}
+
+	for _, r := range s.Fields {
+		v, ok := selectorLookup(data, r.Key)
+
+		var value string
+		if ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+
+		if !r.match(value, ok && v != nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter removes items not matched by selector from list.
+// List should be a pointer to struct with Items slice, like *PodList
+func (s *Selector) Filter(list interface{}) {
+
+	if s.Empty() || list == nil {
+		return
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	items := v.Elem().FieldByName("Items")
+	if !items.IsValid() || items.Kind() != reflect.Slice {
+		return
+	}
+
+	filtered := reflect.MakeSlice(items.Type(), 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		if s.Match(items.Index(i).Interface()) {
+			filtered = reflect.Append(filtered, items.Index(i))
+		}
+	}
+
+	items.Set(filtered)
+}
+
+func (r SelectorRequirement) match(value string, exists bool) bool {
+	switch r.Operator {
+	case SelectorOperatorExists:
+		return exists
+	case SelectorOperatorNotExists:
+		return !exists
+	case SelectorOperatorNotEqual:
+		return !exists || value != r.Value
+	default:
+		return exists && value == r.Value
+	}
+}
+
+func (r SelectorRequirement) String() string {
+	switch r.Operator {
+	case SelectorOperatorExists:
+		return r.Key
+	case SelectorOperatorNotExists:
+		return "!" + r.Key
+	default:
+		return r.Key + r.Operator + r.Value
+	}
+}
+
+func parseSelectorRequirements(selector string, existence bool) ([]SelectorRequirement, error) {
+
+	var requirements = make([]SelectorRequirement, 0)
+
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return requirements, nil
+	}
+
+	for _, part := range strings.Split(selector, ",") {
+
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty requirement in `%s`", selector)
+		}
+
+		var r SelectorRequirement
+
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			r = SelectorRequirement{Key: kv[0], Operator: SelectorOperatorNotEqual, Value: kv[1]}
+		case strings.Contains(part, "=="):
+			kv := strings.SplitN(part, "==", 2)
+			r = SelectorRequirement{Key: kv[0], Operator: SelectorOperatorEqual, Value: kv[1]}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(part, "=", 2)
+			r = SelectorRequirement{Key: kv[0], Operator: SelectorOperatorEqual, Value: kv[1]}
+		case !existence:
+			return nil, fmt.Errorf("operator is required in `%s`", part)
+		case strings.HasPrefix(part, "!"):
+			r = SelectorRequirement{Key: strings.TrimPrefix(part, "!"), Operator: SelectorOperatorNotExists}
+		default:
+			r = SelectorRequirement{Key: part, Operator: SelectorOperatorExists}
+		}
+
+		r.Key = strings.TrimSpace(r.Key)
+		r.Value = strings.TrimSpace(r.Value)
+
+		if r.Key == "" {
+			return nil, fmt.Errorf("key is required in `%s`", part)
+		}
+
+		if strings.ContainsAny(r.Key, "=! ") || strings.ContainsAny(r.Value, "=! ") {
+			return nil, fmt.Errorf("invalid requirement `%s`", part)
+		}
+
+		requirements = append(requirements, r)
+	}
+
+	return requirements, nil
+}
+
+func selectorLookup(data map[string]interface{}, path string) (interface{}, bool) {
+
+	var current interface{} = data
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
@@ -0,0 +1,146 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+
+	var tests = []struct {
+		name    string
+		labels  string
+		fields  string
+		want    *Selector
+		wantErr bool
+	}{
+		{
+			name: "check empty selector",
+			want: &Selector{Labels: []SelectorRequirement{}, Fields: []SelectorRequirement{}},
+		},
+		{
+			name:   "check label and field selector",
+			labels: "app=web,tier!=db,env,!canary",
+			fields: "status.state==error",
+			want: &Selector{
+				Labels: []SelectorRequirement{
+					{Key: "app", Operator: SelectorOperatorEqual, Value: "web"},
+					{Key: "tier", Operator: SelectorOperatorNotEqual, Value: "db"},
+					{Key: "env", Operator: SelectorOperatorExists},
+					{Key: "canary", Operator: SelectorOperatorNotExists},
+				},
+				Fields: []SelectorRequirement{
+					{Key: "status.state", Operator: SelectorOperatorEqual, Value: "error"},
+				},
+			},
+		},
+		{
+			name:    "check field selector without operator",
+			fields:  "status.state",
+			wantErr: true,
+		},
+		{
+			name:    "check selector with empty key",
+			labels:  "app=web,=db",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSelector(tc.labels, tc.fields)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSelectorFilter(t *testing.T) {
+
+	p1 := new(Pod)
+	p1.Meta.Name = "web"
+	p1.Meta.Labels = map[string]string{"app": "web", "tier": "frontend"}
+	p1.Status.State = StateReady
+
+	p2 := new(Pod)
+	p2.Meta.Name = "db"
+	p2.Meta.Labels = map[string]string{"app": "web", "tier": "db"}
+	p2.Status.State = StateError
+
+	p3 := new(Pod)
+	p3.Meta.Name = "worker"
+	p3.Status.State = StateError
+
+	var tests = []struct {
+		name   string
+		labels string
+		fields string
+		want   []string
+	}{
+		{
+			name: "check empty selector",
+			want: []string{"web", "db", "worker"},
+		},
+		{
+			name:   "check label selector",
+			labels: "app=web,tier!=db",
+			want:   []string{"web"},
+		},
+		{
+			name:   "check label existence selector",
+			labels: "!app",
+			want:   []string{"worker"},
+		},
+		{
+			name:   "check field selector",
+			fields: "status.state=error",
+			want:   []string{"db", "worker"},
+		},
+		{
+			name:   "check label and field selector",
+			labels: "app",
+			fields: "status.state=error,meta.name!=worker",
+			want:   []string{"db"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := ParseSelector(tc.labels, tc.fields)
+			assert.NoError(t, err)
+
+			list := &PodList{Items: []*Pod{p1, p2, p3}}
+			s.Filter(list)
+
+			got := make([]string, 0)
+			for _, p := range list.Items {
+				got = append(got, p.Meta.Name)
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -686,6 +686,82 @@ func NewConfigSelfLink(namespace, config string) *ConfigSelfLink {
 	return sl
 }
 
+type NetworkPolicySelfLink struct {
+	string
+	SelfLink
+	parent SelfLinkParent
+	name   string
+}
+
+func (sl *NetworkPolicySelfLink) Parse(selflink string) error {
+
+	parts := strings.Split(selflink, ":")
+
+	sl.string = selflink
+	if len(parts) < 2 {
+		sl.parent = SelfLinkParent{
+			Kind:     KindNamespace,
+			SelfLink: NewNamespaceSelfLink(DefaultNamespace),
+		}
+		sl.name = parts[0]
+		return nil
+	}
+
+	sl.parent = SelfLinkParent{
+		Kind:     KindNamespace,
+		SelfLink: NewNamespaceSelfLink(parts[0]),
+	}
+
+	sl.name = parts[1]
+	return nil
+}
+
+func (sl *NetworkPolicySelfLink) String() string {
+	return sl.string
+}
+
+func (sl *NetworkPolicySelfLink) Parent() (string, SelfLink) {
+	return sl.parent.Kind, sl.parent.SelfLink
+}
+
+func (sl *NetworkPolicySelfLink) Namespace() *NamespaceSelfLink {
+	return sl.parent.SelfLink.(*NamespaceSelfLink)
+}
+
+func (sl *NetworkPolicySelfLink) Name() string {
+	return sl.name
+}
+
+func (sl NetworkPolicySelfLink) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString("\"")
+	buffer.WriteString(sl.string)
+	buffer.WriteString("\"")
+	return buffer.Bytes(), nil
+}
+
+func (sl *NetworkPolicySelfLink) UnmarshalJSON(b []byte) error {
+	var link string
+	if err := json.Unmarshal(b, &link); err != nil {
+		return err
+	}
+
+	return sl.Parse(link)
+}
+
+func NewNetworkPolicySelfLink(namespace, policy string) *NetworkPolicySelfLink {
+
+	sl := new(NetworkPolicySelfLink)
+
+	link := fmt.Sprintf("%s:%s", namespace, policy)
+
+	sl.string = link
+	sl.parent.Kind = KindNamespace
+	sl.parent.SelfLink = NewNamespaceSelfLink(namespace)
+	sl.name = policy
+
+	return sl
+}
+
 type SecretSelfLink struct {
 	string
 	SelfLink
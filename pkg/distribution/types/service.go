@@ -46,6 +46,8 @@ type ServiceMeta struct {
 	SelfLink  ServiceSelfLink `json:"self_link"`
 	Endpoint  string          `json:"endpoint"`
 	IP        string          `json:"ip"`
+	// Ports exposed on all nodes: service port > node port
+	NodePorts map[uint16]uint16 `json:"node_ports,omitempty"`
 }
 
 type ServiceEndpoint struct {
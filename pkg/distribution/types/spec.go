@@ -28,6 +28,7 @@ import (
 
 const ContainerRolePrimary = "primary"
 const ContainerRoleSlave = "slave"
+const ContainerRoleInit = "init"
 
 // SpecState is a state of the spec
 // swagger:model types_spec_state
@@ -61,7 +62,7 @@ type SpecTemplate struct {
 	Volumes SpecTemplateVolumeList `json:"volumes" yaml:"volumes"`
 	// Template main container
 	Containers SpecTemplateContainers `json:"containers" yaml:"containers"`
-	// Termination period
+	// Termination period in seconds
 	Termination int `json:"termination" yaml:"termination"`
 	// Spec updated time
 	Updated time.Time `json:"updated" yaml:"updated"`
@@ -71,9 +72,20 @@ type SpecTemplate struct {
 // swagger:model types_spec_template_network
 type SpecNetwork struct {
 	IP       string               `json:"ip"`
+	IPv6     string               `json:"ipv6,omitempty"`
 	Ports    map[uint16]string    `json:"ports"`
 	Strategy EndpointSpecStrategy `json:"strategy"`
 	Policy   string               `json:"policy"`
+	// Expose service ports on all nodes
+	Expose bool `json:"expose,omitempty"`
+	// Headless service has no virtual IP, pods IPs are resolved instead
+	Headless bool `json:"headless,omitempty"`
+	// Ports names published in SRV records: port > name
+	PortNames map[uint16]string `json:"port_names,omitempty"`
+	// External DNS name service domain is alias of
+	ExternalName string `json:"external_name,omitempty"`
+	// External addresses service ports are forwarded to
+	ExternalIPs []string `json:"external_ips,omitempty"`
 	// Spec updated time
 	Updated time.Time `json:"updated"`
 }
@@ -158,6 +170,8 @@ type SpecTemplateContainer struct {
 	Name string `json:"name" yaml:"name"`
 	// Template container role
 	Role string `json:"role" yaml:"role"`
+	// Template container startup order
+	Order int `json:"order" yaml:"order"`
 	// Automatically remove container when it exits
 	AutoRemove bool `json:"autoremove" yaml:"autoremove"`
 	// Labels list
@@ -176,6 +190,8 @@ type SpecTemplateContainer struct {
 	Volumes SpecTemplateContainerVolumes `json:"volumes" yaml:"volumes"`
 	// Template container probes
 	Probes SpecTemplateContainerProbes `json:"probes" yaml:"probes"`
+	// Template container lifecycle hooks
+	Lifecycle SpecTemplateContainerLifecycle `json:"lifecycle" yaml:"lifecycle"`
 	// Template container security
 	Security SpecTemplateContainerSecurity `json:"security" yaml:"security"`
 	// Subnet container settings
@@ -251,7 +267,7 @@ type SpecTemplateContainerPort struct {
 	// Host port
 	HostPort uint16 `json:"host_port"`
 	// Host port
-	HostIP string `json:"host_port"`
+	HostIP string `json:"host_ip"`
 	// Binding protocol
 	Protocol string `json:"protocol"`
 }
@@ -352,11 +368,49 @@ type SpecTemplateContainerProbe struct {
 		Port     int    `json:"port"`
 	} `json:"socket"`
 
+	// Send http GET request to container, status below 400 is success
+	HTTP struct {
+		Path string `json:"path"`
+		Port int    `json:"port"`
+	} `json:"http"`
+
 	InitialDelaySeconds int `json:"initial_delay"`
 	TimeoutSeconds      int `json:"timeout_seconds"`
 	PeriodSeconds       int `json:"period_seconds"`
 	ThresholdSuccess    int `json:"threshold_success"`
 	ThresholdFailure    int `json:"threshold_failure"`
+	// Seconds to wait for container to become ready after start
+	StartupTimeoutSeconds int `json:"startup_timeout"`
+}
+
+// IsSet checks if any probe check is configured
+func (p SpecTemplateContainerProbe) IsSet() bool {
+	return len(p.Exec.Command) != 0 || p.Socket.Port != 0 || p.HTTP.Port != 0
+}
+
+// swagger:model types_spec_template_container_lifecycle
+type SpecTemplateContainerLifecycle struct {
+	// Hook is called before container is stopped
+	PreStop SpecTemplateContainerHook `json:"pre_stop" yaml:"pre_stop"`
+}
+
+// swagger:model types_spec_template_container_hook
+type SpecTemplateContainerHook struct {
+	// Exec command in container
+	Exec SpecTemplateContainerHookExec `json:"exec" yaml:"exec"`
+	// Send http GET request to container
+	HTTP SpecTemplateContainerHookHTTP `json:"http" yaml:"http"`
+}
+
+// swagger:model types_spec_template_container_hook_exec
+type SpecTemplateContainerHookExec struct {
+	Command []string `json:"command" yaml:"command"`
+}
+
+// swagger:model types_spec_template_container_hook_http
+type SpecTemplateContainerHookHTTP struct {
+	Path string `json:"path" yaml:"path"`
+	Port int    `json:"port" yaml:"port"`
 }
 
 // swagger:model types_spec_template_container_security
@@ -367,11 +421,35 @@ type SpecTemplateContainerSecurity struct {
 	LinuxOptions SpecTemplateContainerSecurityLinuxOptions `json:"linux_options"`
 	// Run container as particular user
 	User int `json:"user"`
+	// Run container as particular group
+	Group int `json:"group"`
+	// Supplemental groups of container process
+	Groups []int `json:"groups"`
+	// Linux capabilities to add and drop
+	Capabilities SpecTemplateContainerSecurityCapabilities `json:"capabilities"`
+	// Mount container root filesystem as read only
+	ReadOnlyRootfs bool `json:"readonly_rootfs"`
+	// Do not allow container process to gain new privileges
+	NoNewPrivileges bool `json:"no_new_privileges"`
 }
 
 // swagger:model types_spec_template_container_security_linux
 type SpecTemplateContainerSecurityLinuxOptions struct {
+	// SELinux label options
+	User  string `json:"user"`
+	Role  string `json:"role"`
+	Type  string `json:"type"`
 	Level string `json:"level"`
+	// Seccomp profile: unconfined, runtime/default or localhost/<path to profile on node>
+	Seccomp string `json:"seccomp"`
+	// AppArmor profile: unconfined, runtime/default or localhost/<profile name>
+	AppArmor string `json:"apparmor"`
+}
+
+// swagger:model types_spec_template_container_security_capabilities
+type SpecTemplateContainerSecurityCapabilities struct {
+	Add  []string `json:"add"`
+	Drop []string `json:"drop"`
 }
 
 // swagger:model types_spec_template_container_network
@@ -462,6 +540,16 @@ func (s *SpecTemplateContainerEnvs) ToLinuxFormat() []string {
 	return env
 }
 
+// IsExternal checks service network points outside of cluster
+func (n SpecNetwork) IsExternal() bool {
+	return n.ExternalName != EmptyString || len(n.ExternalIPs) > 0
+}
+
+// IsProxied checks service traffic is balanced by proxy on virtual addresses
+func (n SpecNetwork) IsProxied() bool {
+	return !n.Headless && n.ExternalName == EmptyString
+}
+
 func (ss *SpecSelector) SetDefault() {
 	if ss.Node != EmptyString {
 		ss.Node = EmptyString
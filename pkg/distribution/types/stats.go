@@ -0,0 +1,152 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import "time"
+
+// swagger:ignore
+// swagger:model types_node_stats
+type NodeStats struct {
+	StatsUsage
+	// Node name
+	Node string `json:"node"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+	// Node pods stats
+	Pods map[string]*PodStats `json:"pods"`
+	// Node endpoints stats
+	Endpoints map[string]*EndpointStats `json:"endpoints"`
+}
+
+// swagger:ignore
+// swagger:model types_pod_stats
+type PodStats struct {
+	StatsUsage
+	// Pod selflink
+	Pod string `json:"pod"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+	// Pod containers stats
+	Containers map[string]*ContainerStats `json:"containers"`
+}
+
+// swagger:ignore
+// swagger:model types_container_stats
+type ContainerStats struct {
+	StatsUsage
+	// Container ID on host
+	ID string `json:"id"`
+	// Container name
+	Name string `json:"name"`
+	// Stats collection time
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// swagger:ignore
+// swagger:model types_endpoint_stats
+type EndpointStats struct {
+	// Endpoint selflink
+	Endpoint string `json:"endpoint"`
+	// Endpoint route strategy
+	Route string `json:"route"`
+	// Endpoint upstreams connections
+	Upstreams map[string]*EndpointUpstreamStats `json:"upstreams"`
+}
+
+type StatsUsage struct {
+	// CPU usage
+	CPU StatsCPU `json:"cpu"`
+	// Memory usage
+	Memory StatsMemory `json:"memory"`
+	// Network IO, received and transmitted bytes
+	Network StatsIO `json:"network"`
+	// Block IO, read and written bytes
+	Block StatsIO `json:"block"`
+}
+
+type StatsCPU struct {
+	// Total consumed CPU time in nanoseconds
+	Total uint64 `json:"total"`
+	// CPU usage percent of node CPU
+	Percent float64 `json:"percent"`
+}
+
+type StatsMemory struct {
+	// Resident memory in bytes
+	RSS uint64 `json:"rss"`
+	// Memory limit in bytes
+	Limit uint64 `json:"limit"`
+}
+
+type StatsIO struct {
+	Read  uint64 `json:"read"`
+	Write uint64 `json:"write"`
+}
+
+func NewNodeStats(node string) *NodeStats {
+	s := new(NodeStats)
+	s.Node = node
+	s.Timestamp = time.Now().UTC()
+	s.Pods = make(map[string]*PodStats, 0)
+	s.Endpoints = make(map[string]*EndpointStats, 0)
+	return s
+}
+
+func NewPodStats(pod string) *PodStats {
+	s := new(PodStats)
+	s.Pod = pod
+	s.Timestamp = time.Now().UTC()
+	s.Containers = make(map[string]*ContainerStats, 0)
+	return s
+}
+
+// AddContainer adds container stats into pod usage.
+// Pod containers share network namespace, so network IO is taken once
+func (s *PodStats) AddContainer(c *ContainerStats) {
+	s.Containers[c.ID] = c
+
+	s.CPU.Total += c.CPU.Total
+	s.CPU.Percent += c.CPU.Percent
+	s.Memory.RSS += c.Memory.RSS
+	s.Memory.Limit += c.Memory.Limit
+	s.Block.Read += c.Block.Read
+	s.Block.Write += c.Block.Write
+
+	if c.Network.Read > s.Network.Read {
+		s.Network.Read = c.Network.Read
+	}
+
+	if c.Network.Write > s.Network.Write {
+		s.Network.Write = c.Network.Write
+	}
+}
+
+// AddPod adds pod stats into node usage
+func (s *NodeStats) AddPod(p *PodStats) {
+	s.Pods[p.Pod] = p
+
+	s.CPU.Total += p.CPU.Total
+	s.CPU.Percent += p.CPU.Percent
+	s.Memory.RSS += p.Memory.RSS
+	s.Memory.Limit += p.Memory.Limit
+	s.Network.Read += p.Network.Read
+	s.Network.Write += p.Network.Write
+	s.Block.Read += p.Block.Read
+	s.Block.Write += p.Block.Write
+}
@@ -18,11 +18,43 @@
 
 package types
 
+import "strconv"
+
 type System struct {
 	Storage SystemStorage `json:"-"`
 }
 
+// ResourceVersion returns storage revision of object as opaque version string
+// used for optimistic concurrency control on updates
+func (s System) ResourceVersion() string {
+	if s.Storage.Revision == 0 {
+		return ""
+	}
+	return strconv.FormatInt(s.Storage.Revision, 10)
+}
+
 type SystemStorage struct {
 	Revision int64  `json:"-"`
 	Key      string `json:"-"`
+	// Continue token to request the next list page, empty on the last page
+	Continue string `json:"-"`
+	// Total count of items in the listed collection
+	Total int64 `json:"-"`
+}
+
+// UpdateOptions is used to update object in storage
+type UpdateOptions struct {
+	// Revision of object expected in storage, update fails with conflict
+	// if object was changed since this revision. Nil means unconditional update
+	Revision *int64
+}
+
+// ListOptions is used to request a page of objects list from storage
+type ListOptions struct {
+	// Limit of items in page, 0 means no limit
+	Limit int64
+	// Continue token returned with the previous page
+	Continue string
+	// Selector filters objects before paging
+	Selector *Selector
 }
@@ -24,6 +24,8 @@ const (
 	DEFAULT_NAMESPACE = "default"
 	SYSTEM_NAMESPACE  = "system"
 
+	DEFAULT_NODE_PORT = 2969
+
 	DEFAULT_RESOURCE_LIMITS_RAM = "128mib"
 	DEFAULT_RESOURCE_LIMITS_CPU = "0.1"
 
@@ -42,6 +44,7 @@ const (
 	KindEndpoint   = "endpoint"
 	KindConfig     = "config"
 	KindVolume     = "volume"
+	KindPolicy     = "policy"
 )
 
 type Vault struct {
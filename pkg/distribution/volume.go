@@ -70,12 +70,12 @@ func (v *Volume) Get(namespace, name string) (*types.Volume, error) {
 	return item, nil
 }
 
-func (v *Volume) ListByNamespace(namespace string) (*types.VolumeList, error) {
+func (v *Volume) ListByNamespace(namespace string, opts *types.ListOptions) (*types.VolumeList, error) {
 	log.V(logLevel).Debugf("%s:list:> get volumes list", logVolumePrefix)
 
 	list := types.NewVolumeList()
 	filter := v.storage.Filter().Volume().ByNamespace(namespace)
-	err := v.storage.List(v.context, v.storage.Collection().Volume(), filter, list, nil)
+	err := v.storage.List(v.context, v.storage.Collection().Volume(), filter, list, listOpts(opts))
 	if err != nil {
 		log.V(logLevel).Error("%s:list:> get volumes list err: %v", logVolumePrefix, err)
 		return list, err
@@ -103,11 +103,11 @@ func (v *Volume) Create(namespace *types.Namespace, vol *types.Volume) (*types.V
 	return vol, nil
 }
 
-func (v *Volume) Update(volume *types.Volume) error {
+func (v *Volume) Update(volume *types.Volume, opts *types.UpdateOptions) error {
 	log.V(logLevel).Debugf("%s:update:> update volume %s", logVolumePrefix, volume.Meta.Name)
 
 	if err := v.storage.Set(v.context, v.storage.Collection().Volume(),
-		volume.SelfLink().String(), volume, nil); err != nil {
+		volume.SelfLink().String(), volume, updateOpts(opts)); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update volume err: %v", logVolumePrefix, err)
 		return err
 	}
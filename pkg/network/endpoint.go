@@ -40,8 +40,9 @@ func (n *Network) EndpointManage(ctx context.Context, key string, manifest *type
 
 	state := n.state.Endpoints().GetEndpoint(key)
 
+	// headless and external name endpoints have no virtual address to be served by proxy
 	if state != nil {
-		if manifest.State == types.StateDestroy {
+		if manifest.State == types.StateDestroy || !manifest.IsProxied() {
 			n.EndpointDestroy(ctx, key, state)
 			n.state.Endpoints().DelEndpoint(key)
 			return nil
@@ -61,7 +62,7 @@ func (n *Network) EndpointManage(ctx context.Context, key string, manifest *type
 		return nil
 	}
 
-	if manifest.State == types.StateDestroy {
+	if manifest.State == types.StateDestroy || !manifest.IsProxied() {
 		return nil
 	}
 
@@ -105,6 +106,45 @@ func (n *Network) EndpointDestroy(ctx context.Context, endpoint string, state *t
 	return cpi.Destroy(ctx, state)
 }
 
+// EndpointStats returns connections of endpoints upstreams collected from proxy
+func (n *Network) EndpointStats(ctx context.Context) (map[string]*types.EndpointStats, error) {
+	log.V(logLevel).Debugf("%s stats", logEndpointPrefix)
+
+	info, err := n.cpi.Info(ctx)
+	if err != nil {
+		log.Errorf("%s stats error: %s", logEndpointPrefix, err.Error())
+		return nil, err
+	}
+
+	stats := make(map[string]*types.EndpointStats, 0)
+	for key, endpoint := range n.state.Endpoints().GetEndpoints() {
+
+		if endpoint == nil {
+			continue
+		}
+
+		es := new(types.EndpointStats)
+		es.Endpoint = key
+		es.Route = endpoint.Strategy.GetRoute()
+		es.Upstreams = make(map[string]*types.EndpointUpstreamStats, 0)
+
+		for _, ip := range endpoint.GetIPs() {
+			st, ok := info[ip]
+			if !ok || st == nil {
+				continue
+			}
+
+			for host, us := range st.Stats {
+				es.Upstreams[host] = us
+			}
+		}
+
+		stats[key] = es
+	}
+
+	return stats, nil
+}
+
 func endpointEqual(manifest *types.EndpointManifest, state *types.EndpointState) bool {
 
 	if state.IP != manifest.IP {
@@ -112,13 +152,18 @@ func endpointEqual(manifest *types.EndpointManifest, state *types.EndpointState)
 		return false
 	}
 
-	if manifest.Strategy.Route != manifest.Strategy.Route {
+	if state.IPv6 != manifest.IPv6 {
+		log.V(logLevel).Debugf("%s ipv6 not match %s != %s", logEndpointPrefix, manifest.IPv6, state.IPv6)
+		return false
+	}
+
+	if manifest.Strategy.GetRoute() != state.Strategy.GetRoute() {
 		log.V(logLevel).Debugf("%s route strategy not match %s != %s", logEndpointPrefix, manifest.Strategy.Route, state.Strategy.Route)
 		return false
 	}
 
-	if manifest.Strategy.Bind != manifest.Strategy.Bind {
-		log.V(logLevel).Debugf("%s bind strategy not match %s != %s", logEndpointPrefix, manifest.Strategy.Bind, state.Strategy.Bind)
+	if manifest.Strategy.Affinity != state.Strategy.Affinity {
+		log.V(logLevel).Debugf("%s affinity not match %d != %d", logEndpointPrefix, manifest.Strategy.Affinity, state.Strategy.Affinity)
 		return false
 	}
 
@@ -142,6 +187,18 @@ func endpointEqual(manifest *types.EndpointManifest, state *types.EndpointState)
 		}
 	}
 
+	if len(manifest.NodePorts) != len(state.NodePorts) {
+		log.V(logLevel).Debugf("%s node ports count changed %d != %d", logEndpointPrefix, len(manifest.NodePorts), len(state.NodePorts))
+		return false
+	}
+
+	for port, np := range manifest.NodePorts {
+		if state.NodePorts[port] != np {
+			log.V(logLevel).Debugf("%s node port not match %d != %d", logEndpointPrefix, np, state.NodePorts[port])
+			return false
+		}
+	}
+
 	if len(manifest.Upstreams) != len(state.Upstreams) {
 		log.V(logLevel).Debugf("%s upstreams count changed %d != %d", logEndpointPrefix, len(manifest.Upstreams), len(state.Upstreams))
 		return false
@@ -173,5 +230,14 @@ func endpointEqual(manifest *types.EndpointManifest, state *types.EndpointState)
 		}
 	}
 
+	// weights are applied to proxied upstreams only
+	for _, up := range manifest.Upstreams {
+		if manifest.Strategy.GetWeight(up) != state.Strategy.GetWeight(up) {
+			log.V(logLevel).Debugf("%s upstream %s weight not match %d != %d", logEndpointPrefix, up,
+				manifest.Strategy.GetWeight(up), state.Strategy.GetWeight(up))
+			return false
+		}
+	}
+
 	return true
 }
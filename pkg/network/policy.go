@@ -0,0 +1,43 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package network
+
+import (
+	"context"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/network/state"
+)
+
+func (n *Network) Policies() *state.PolicyState {
+	return n.state.Policies()
+}
+
+// PolicySync resolves network policies into pods traffic rules and passes them to CNI
+func (n *Network) PolicySync(ctx context.Context) error {
+
+	rules := types.ResolveNetworkPolicies(n.state.Policies().GetPolicies())
+
+	if err := n.cni.Policy(ctx, rules); err != nil {
+		log.Errorf("Can not enforce network policies: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
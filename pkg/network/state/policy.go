@@ -0,0 +1,58 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package state
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"sync"
+)
+
+const logPolicyPrefix = "state:policy:>"
+
+type PolicyState struct {
+	lock     sync.RWMutex
+	policies map[string]*types.NetworkPolicyManifest
+}
+
+func (p *PolicyState) GetPolicies() map[string]*types.NetworkPolicyManifest {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	policies := make(map[string]*types.NetworkPolicyManifest, 0)
+	for namespace, m := range p.policies {
+		policies[namespace] = m
+	}
+
+	return policies
+}
+
+func (p *PolicyState) SetPolicy(namespace string, m *types.NetworkPolicyManifest) {
+	log.V(logLevel).Debugf("%s set network policy: %s", logPolicyPrefix, namespace)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.policies[namespace] = m
+}
+
+func (p *PolicyState) DelPolicy(namespace string) {
+	log.V(logLevel).Debugf("%s del network policy: %s", logPolicyPrefix, namespace)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.policies, namespace)
+}
@@ -28,6 +28,7 @@ type State struct {
 	subnets   *SubnetState
 	endpoints *EndpointState
 	resolvers *ResolverState
+	policies  *PolicyState
 }
 
 func (s *State) Subnets() *SubnetState {
@@ -42,6 +43,10 @@ func (s *State) Resolvers () *ResolverState {
 	return s.resolvers
 }
 
+func (s *State) Policies() *PolicyState {
+	return s.policies
+}
+
 func New() *State {
 
 	state := State{
@@ -54,6 +59,9 @@ func New() *State {
 		resolvers: &ResolverState{
 			resolvers: make(map[string]*types.ResolverManifest, 0),
 		},
+		policies: &PolicyState{
+			policies: make(map[string]*types.NetworkPolicyManifest, 0),
+		},
 	}
 
 	return &state
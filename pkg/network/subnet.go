@@ -23,6 +23,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/network/state"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cni"
 )
 
 const logLevel = 3
@@ -67,8 +68,20 @@ func (n *Network) SubnetManage(ctx context.Context, cidr string, sn *types.Subne
 		}
 
 		log.Debugf("check subnet manifest: %s", cidr)
-		// TODO: check if network manifest changes
-		// if changes then update routes and interfaces
+		// subnet is replaced when node key changes or next key is announced in encrypted network
+		// or node network gets or loses IPv6 subnet
+		if state.Key == sn.Key && state.KeyNext == sn.KeyNext && state.CIDRv6 == sn.CIDRv6 {
+			return nil
+		}
+
+		log.Debugf("replace changed subnet: %s", cidr)
+		st, err := n.cni.Replace(ctx, &state, sn)
+		if err != nil {
+			log.Errorf("Can not replace network subnet: %s", err.Error())
+			return err
+		}
+
+		n.state.Subnets().SetSubnet(cidr, st)
 		return nil
 	}
 
@@ -87,6 +100,17 @@ func (n *Network) SubnetManage(ctx context.Context, cidr string, sn *types.Subne
 	return nil
 }
 
+// KeyCommit switches node network to next key accepted by all nodes
+func (n *Network) KeyCommit(ctx context.Context, key string) error {
+
+	r, ok := n.cni.(cni.KeyRotator)
+	if !ok {
+		return nil
+	}
+
+	return r.CommitKey(ctx, key)
+}
+
 func (n *Network) SubnetDestroy(ctx context.Context, cidr string) error {
 
 	sn := n.state.Subnets().GetSubnet(cidr)
@@ -40,6 +40,7 @@ const (
 type Controller struct {
 	ctx     context.Context
 	runtime *runtime.Runtime
+	connect *request.NodeConnectOptions
 	cache   struct {
 		lock      sync.RWMutex
 		resources types.NodeStatus
@@ -68,7 +69,7 @@ func (c *Controller) Connect(v *viper.Viper) error {
 	opts := v1.Request().Node().NodeConnectOptions()
 	opts.Info = envs.Get().GetState().Node().Info
 
-	opts.Status = envs.Get().GetState().Node().Status
+	opts.Status = envs.Get().GetState().Node().GetStatus()
 	var network = envs.Get().GetNet()
 	if network != nil {
 		opts.Network = *envs.Get().GetNet().Info(c.ctx)
@@ -102,6 +103,8 @@ func (c *Controller) Connect(v *viper.Viper) error {
 		}
 	}
 
+	c.connect = opts
+
 	for {
 		log.V(logLevel).Debugf("%s:connect:> establish connection", logPrefix)
 		if err := envs.Get().GetNodeClient().Connect(c.ctx, opts); err == nil {
@@ -120,12 +123,20 @@ func (c *Controller) Sync() error {
 	ticker := time.NewTicker(time.Second * 5)
 
 	for range ticker.C {
+
+		if err := c.syncNetworkKey(); err != nil {
+			log.Errorf("%s network key publish err: %s", logPrefix, err.Error())
+		}
+
 		opts := new(request.NodeStatusOptions)
 		opts.Pods = make(map[string]*request.NodePodStatusOptions)
 		opts.Volumes = make(map[string]*request.NodeVolumeStatusOptions)
 
-		opts.Resources.Capacity = envs.Get().GetState().Node().Status.Capacity
-		opts.Resources.Allocated = envs.Get().GetState().Node().Status.Allocated
+		status := envs.Get().GetState().Node().GetStatus()
+		opts.Resources.Capacity = status.Capacity
+		opts.Resources.Allocated = status.Allocated
+		opts.Pressure = status.Pressure
+		opts.Network = getNetworkOptions()
 
 		c.cache.lock.Lock()
 		var i = 0
@@ -183,6 +194,34 @@ func (c *Controller) Sync() error {
 	return nil
 }
 
+// syncNetworkKey publishes node network key after it was rotated by network driver
+func (c *Controller) syncNetworkKey() error {
+
+	var network = envs.Get().GetNet()
+	if network == nil || c.connect == nil {
+		return nil
+	}
+
+	info := network.Info(c.ctx)
+	if info.Key == c.connect.Network.Key && info.KeyNext == c.connect.Network.KeyNext {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:sync:> publish changed network key", logPrefix)
+
+	opts := *c.connect
+	opts.Network = *info
+	opts.Info.NetworkKey = info.Key
+
+	if err := envs.Get().GetNodeClient().Connect(c.ctx, &opts); err != nil {
+		return err
+	}
+
+	envs.Get().GetState().Node().Info.NetworkKey = info.Key
+	c.connect = &opts
+	return nil
+}
+
 func (c *Controller) Subscribe() {
 	var (
 		pods    = make(chan string)
@@ -218,6 +257,25 @@ func (c *Controller) Subscribe() {
 	<-done
 }
 
+// getNetworkOptions reports next keys of cluster subnets accepted by node during keys rotation
+func getNetworkOptions() types.NodeStatusNetwork {
+
+	opts := types.NodeStatusNetwork{Keys: make(map[string]string)}
+
+	network := envs.Get().GetNet()
+	if network == nil {
+		return opts
+	}
+
+	for cidr, sn := range network.Subnets().GetSubnets() {
+		if sn.KeyNext != types.EmptyString {
+			opts.Keys[cidr] = sn.KeyNext
+		}
+	}
+
+	return opts
+}
+
 func getPodOptions(p *types.PodStatus) *request.NodePodStatusOptions {
 	opts := v1.Request().Node().NodePodStatusOptions()
 	opts.State = p.State
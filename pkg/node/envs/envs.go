@@ -62,6 +62,11 @@ type config struct {
 			Docker struct {
 				Version string `mapstructure:"version" json:"version" yaml:"version"`
 			} `mapstructure:"docker" json:"docker" yaml:"docker"`
+			Containerd struct {
+				Host    string `mapstructure:"host" json:"host" yaml:"host"`
+				LogDir  string `mapstructure:"log_dir" json:"log_dir" yaml:"log_dir"`
+				Handler string `mapstructure:"handler" json:"handler" yaml:"handler"`
+			} `mapstructure:"containerd" json:"containerd" yaml:"containerd"`
 		} `mapstructure:"cri" json:"cri" yaml:"cri"`
 		Csi struct {
 			Dir struct {
@@ -73,6 +78,15 @@ type config struct {
 			Docker struct {
 				Version string `mapstructure:"version" json:"version" yaml:"version"`
 			} `mapstructure:"docker" json:"docker" yaml:"docker"`
+			Containerd struct {
+				Host string `mapstructure:"host" json:"host" yaml:"host"`
+			} `mapstructure:"containerd" json:"containerd" yaml:"containerd"`
+			GC struct {
+				Path          string `mapstructure:"path" json:"path" yaml:"path"`
+				Period        int    `mapstructure:"period" json:"period" yaml:"period"`
+				HighThreshold int    `mapstructure:"high_threshold" json:"high_threshold" yaml:"high_threshold"`
+				LowThreshold  int    `mapstructure:"low_threshold" json:"low_threshold" yaml:"low_threshold"`
+			} `mapstructure:"gc" json:"gc" yaml:"gc"`
 		} `mapstructure:"iri" json:"iri" yaml:"iri"`
 		ExtraHosts []string `mapstructure:"extra_hosts" json:"extra_hosts" yaml:"extra_hosts"`
 	} `mapstructure:"container" json:"container" yaml:"container"`
@@ -19,8 +19,12 @@
 package node
 
 import (
-	"github.com/lastbackend/lastbackend/pkg/log"
+	"encoding/json"
 	"net/http"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/runtime"
 )
 
 const logLevel = 2
@@ -29,3 +33,29 @@ func NodeGetH(w http.ResponseWriter, r *http.Request) {
 
 	log.V(logLevel).Debug("Handler: Node: list node")
 }
+
+// NodeStatsH handler returns resources usage of all node pods
+func NodeStatsH(w http.ResponseWriter, r *http.Request) {
+
+	log.V(logLevel).Debug("node:http:node:stats:> get node stats")
+
+	stats, err := runtime.NodeStats(r.Context())
+	if err != nil {
+		log.Errorf("node:http:node:stats:> get node stats err: %s", err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := json.Marshal(stats)
+	if err != nil {
+		log.Errorf("node:http:node:stats:> convert struct to json err: %s", err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.Errorf("node:http:node:stats:> write response err: %s", err.Error())
+		return
+	}
+}
@@ -25,4 +25,5 @@ import (
 
 var Routes = []http.Route{
 	{Path: "/", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeGetH},
+	{Path: "/stats", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeStatsH},
 }
@@ -19,6 +19,7 @@
 package pod
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -71,3 +72,37 @@ func PodLogsH(w http.ResponseWriter, r *http.Request) {
 
 	return
 }
+
+// PodStatsH handler returns pod containers resources usage
+func PodStatsH(w http.ResponseWriter, r *http.Request) {
+
+	log.V(logLevel).Debug("node:http:pod:stats:> get pod stats")
+
+	var pod = mux.Vars(r)["pod"]
+
+	if envs.Get().GetState().Pods().GetPod(pod) == nil {
+		log.Errorf("node:http:pod:stats:> pod not found")
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	stats, err := runtime.PodStats(r.Context(), pod)
+	if err != nil {
+		log.Errorf("node:http:pod:stats:> get pod stats err: %s", err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := json.Marshal(stats)
+	if err != nil {
+		log.Errorf("node:http:pod:stats:> convert struct to json err: %s", err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.Errorf("node:http:pod:stats:> write response err: %s", err.Error())
+		return
+	}
+}
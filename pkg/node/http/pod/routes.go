@@ -26,4 +26,5 @@ import (
 var Routes = []http.Route{
 	{Path: "/pod/{pod}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodGetH},
 	{Path: "/pod/{pod}/{container}/logs", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodLogsH},
+	{Path: "/pod/{pod}/stats", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodStatsH},
 }
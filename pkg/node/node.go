@@ -19,8 +19,10 @@
 package node
 
 import (
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/lastbackend/lastbackend/pkg/api/client"
@@ -110,6 +112,7 @@ func Daemon(v *viper.Viper) {
 	}
 
 	st.Node().Info = runtime.NodeInfo()
+	st.Node().Info.API = nodeAPI(v, st.Node().Info.ExternalIP)
 	st.Node().Status = runtime.NodeStatus()
 
 	if err := r.Restore(); err != nil {
@@ -118,6 +121,7 @@ func Daemon(v *viper.Viper) {
 	}
 	r.Subscribe()
 	r.Loop()
+	r.GC()
 
 	c, err := exporter.NewExporter(st.Node().Info.Hostname, types.EmptyString)
 	if err != nil {
@@ -203,3 +207,34 @@ func Daemon(v *viper.Viper) {
 
 	return
 }
+
+// nodeAPI returns node http server endpoint published to cluster api.
+// Advertise address takes precedence, wildcard bind address is replaced by node external ip.
+func nodeAPI(v *viper.Viper, ip string) types.NodeAPI {
+
+	api := types.NodeAPI{Scheme: "http"}
+
+	host := v.GetString("server.advertise")
+	if host == types.EmptyString {
+		host = v.GetString("server.host")
+	}
+	if host == types.EmptyString || net.ParseIP(host).IsUnspecified() {
+		host = ip
+	}
+
+	port := v.GetInt("server.port")
+	if port == 0 {
+		port = types.DEFAULT_NODE_PORT
+	}
+
+	api.Address = net.JoinHostPort(host, strconv.Itoa(port))
+
+	// node http server is started with tls only when all tls files are set
+	if v.GetString("server.tls.ca") != types.EmptyString &&
+		v.GetString("server.tls.cert") != types.EmptyString &&
+		v.GetString("server.tls.key") != types.EmptyString {
+		api.Scheme = "https"
+	}
+
+	return api
+}
@@ -152,7 +152,7 @@ func containerManifestCreate(ctx context.Context, pod string, spec *types.SpecTe
 		name = parts[len(parts)-1]
 	}
 
-	mf.Name = fmt.Sprintf("%s-%s", name, spec.Name)
+	mf.Name = containerNameCreate(name, spec.Name)
 	mf.Labels = make(map[string]string, 0)
 	for n, v := range spec.Labels {
 		mf.Labels[n] = v
@@ -259,3 +259,7 @@ func containerManifestCreate(ctx context.Context, pod string, spec *types.SpecTe
 
 	return mf, nil
 }
+
+func containerNameCreate(pod, spec string) string {
+	return fmt.Sprintf("%s-%s", pod, spec)
+}
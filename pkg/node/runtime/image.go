@@ -21,12 +21,26 @@ package runtime
 import (
 	"encoding/base64"
 	"fmt"
+	"github.com/docker/distribution/reference"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/node/envs"
 	"golang.org/x/net/context"
+	"sort"
 	"strings"
+	"time"
+)
+
+const (
+	logImagePrefix = "node:runtime:image:>"
+
+	defaultImageGCPeriod        = 300
+	defaultImageGCHighThreshold = 85
+	defaultImageGCLowThreshold  = 80
+	// images used or pulled recently are not removed,
+	// they can be in use by pods which are not started yet
+	defaultImageGCMinAge = 2 * time.Minute
 )
 
 func ImagePull(ctx context.Context, namespace string, image *types.SpecTemplateContainerImage) error {
@@ -85,8 +99,11 @@ func ImagePull(ctx context.Context, namespace string, image *types.SpecTemplateC
 		return err
 	}
 
+	envs.Get().GetState().Images().SetUsed(mf.Name)
+
 	if img != nil {
 		envs.Get().GetState().Images().AddImage(img.SelfLink(), img)
+		envs.Get().GetState().Images().SetUsed(img.Meta.ID)
 	}
 
 	return nil
@@ -118,3 +135,211 @@ func ImageRestore(ctx context.Context) error {
 
 	return nil
 }
+
+// ImageGC removes unused images, least recently used first, when disk usage
+// is over high threshold, until it is lower than low threshold.
+// Node disk pressure status is updated after each run
+func ImageGC(ctx context.Context) error {
+
+	var (
+		cfg   = envs.Get().GetConfig().Container.Iri.GC
+		high  = defaultImageGCHighThreshold
+		low   = defaultImageGCLowThreshold
+		state = envs.Get().GetState()
+		path  = cfg.Path
+	)
+
+	if path == types.EmptyString {
+		root, err := envs.Get().GetCII().Root(ctx)
+		if err != nil {
+			log.Errorf("%s can not get images root path: %s", logImagePrefix, err.Error())
+			return err
+		}
+		path = root
+	}
+
+	if cfg.HighThreshold > 0 {
+		high = cfg.HighThreshold
+	}
+
+	if cfg.LowThreshold > 0 {
+		low = cfg.LowThreshold
+	}
+
+	used, total, err := NodeDiskUsage(path)
+	if err != nil {
+		log.Errorf("%s can not get disk usage: %s", logImagePrefix, err.Error())
+		return err
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s disk usage %d%% of %s", logImagePrefix, used*100/total, path)
+
+	if int(used*100/total) < high {
+		state.Node().SetDiskPressure(false)
+		return nil
+	}
+
+	images, err := envs.Get().GetCII().List(ctx)
+	if err != nil {
+		log.Errorf("%s can not get images list: %s", logImagePrefix, err.Error())
+		return err
+	}
+
+	candidates := imageGCCandidates(images, imagesInUse(), state.Images().GetUsed, time.Now())
+
+	for _, i := range imageGCSelect(candidates, used, total, high, low) {
+
+		log.V(logLevel).Debugf("%s remove unused image %s (%d bytes)", logImagePrefix, i.Meta.ID, i.Status.Size)
+
+		if err := envs.Get().GetCII().Remove(ctx, i.Meta.ID); err != nil {
+			log.Warnf("%s can not remove image %s: %s", logImagePrefix, i.Meta.ID, err.Error())
+			continue
+		}
+
+		for _, t := range i.Meta.Tags {
+			state.Images().DelImage(t)
+			state.Images().DelUsed(t)
+		}
+		state.Images().DelUsed(i.Meta.ID)
+	}
+
+	used, total, err = NodeDiskUsage(path)
+	if err != nil {
+		log.Errorf("%s can not get disk usage: %s", logImagePrefix, err.Error())
+		return err
+	}
+
+	usage := int(used * 100 / total)
+	state.Node().SetDiskPressure(usage >= high)
+
+	if usage >= high {
+		log.Warnf("%s node is under disk pressure: usage %d%% of %s", logImagePrefix, usage, path)
+	}
+
+	return nil
+}
+
+// imageGCCandidates returns images not used by pods, least recently used first.
+// Images never used since node start are ordered by their created time,
+// images used or pulled recently are skipped
+func imageGCCandidates(images []*types.Image, inuse map[string]bool, lastUsed func(link string) time.Time, now time.Time) []*types.Image {
+
+	var (
+		candidates = make([]*types.Image, 0)
+		last       = make(map[string]time.Time, 0)
+	)
+
+	for _, i := range images {
+
+		if imageInUse(i, inuse) {
+			continue
+		}
+
+		t := lastUsed(i.Meta.ID)
+		for _, tag := range i.Meta.Tags {
+			if u := lastUsed(tag); u.After(t) {
+				t = u
+			}
+		}
+
+		if t.IsZero() {
+			t = i.Meta.Created
+		}
+
+		if now.Sub(t) < defaultImageGCMinAge {
+			continue
+		}
+
+		last[i.Meta.ID] = t
+		candidates = append(candidates, i)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return last[candidates[i].Meta.ID].Before(last[candidates[j].Meta.ID])
+	})
+
+	return candidates
+}
+
+// imageGCSelect returns first candidates to remove to lower disk usage under low threshold.
+// Nothing is selected if usage is lower than high threshold
+func imageGCSelect(candidates []*types.Image, used, total uint64, high, low int) []*types.Image {
+
+	var selected = make([]*types.Image, 0)
+
+	if total == 0 || int(used*100/total) < high {
+		return selected
+	}
+
+	var (
+		target = int64(used) - int64(total)*int64(low)/100
+		freed  int64
+	)
+
+	for _, i := range candidates {
+
+		if freed >= target {
+			break
+		}
+
+		selected = append(selected, i)
+		freed += i.Status.Size
+	}
+
+	return selected
+}
+
+// imagesInUse returns normalized names of images used by local pods containers
+func imagesInUse() map[string]bool {
+
+	var inuse = make(map[string]bool, 0)
+
+	for _, p := range envs.Get().GetState().Pods().GetPods() {
+		for _, c := range p.Runtime.Services {
+			inuse[imageReference(c.Image.Name)] = true
+		}
+		for _, c := range p.Runtime.Init {
+			inuse[imageReference(c.Image.Name)] = true
+		}
+	}
+
+	return inuse
+}
+
+// imageInUse checks if image is used by its id, tags or digest.
+// Names in inuse map should be normalized by imageReference
+func imageInUse(i *types.Image, inuse map[string]bool) bool {
+
+	if inuse[i.Meta.ID] || inuse[imageReference(i.Meta.Digest)] {
+		return true
+	}
+
+	for _, t := range i.Meta.Tags {
+		if inuse[imageReference(t)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imageReference returns fully qualified image reference,
+// so nginx, nginx:latest and docker.io/library/nginx:latest are the same image.
+// Image ids and invalid references are returned as is
+func imageReference(name string) string {
+
+	if name == types.EmptyString || strings.HasPrefix(name, "sha256:") {
+		return name
+	}
+
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return name
+	}
+
+	return reference.TagNameOnly(named).String()
+}
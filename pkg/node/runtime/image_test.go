@@ -0,0 +1,139 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func getImageAsset(id string, size int64, created time.Time, tags ...string) *types.Image {
+	i := new(types.Image)
+	i.Meta.ID = id
+	i.Meta.Tags = tags
+	i.Meta.Created = created
+	i.Status.Size = size
+	return i
+}
+
+func imageIDs(images []*types.Image) []string {
+	ids := make([]string, 0)
+	for _, i := range images {
+		ids = append(ids, i.Meta.ID)
+	}
+	return ids
+}
+
+func TestImageInUse(t *testing.T) {
+
+	var (
+		nginx = getImageAsset("sha256:1", 0, time.Time{}, "nginx:1.17")
+		redis = getImageAsset("sha256:2", 0, time.Time{}, "redis:latest")
+		local = getImageAsset("sha256:3", 0, time.Time{}, "registry.local:5000/app:v1")
+	)
+
+	tests := []struct {
+		name  string
+		image *types.Image
+		inuse string
+		want  bool
+	}{
+		{"checking image in use by tag", nginx, "nginx:1.17", true},
+		{"checking image in use by full reference", nginx, "docker.io/library/nginx:1.17", true},
+		{"checking image in use by other tag", nginx, "nginx:1.16", false},
+		{"checking image in use without latest tag", redis, "redis", true},
+		{"checking image in use by library path", redis, "library/redis", true},
+		{"checking image in use by id", redis, "sha256:2", true},
+		{"checking image in use from private registry", local, "registry.local:5000/app:v1", true},
+		{"checking image not in use from other registry", local, "app:v1", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inuse := map[string]bool{imageReference(tc.inuse): true}
+			assert.Equal(t, tc.want, imageInUse(tc.image, inuse), "image in use mismatch")
+		})
+	}
+}
+
+func TestImageGCCandidates(t *testing.T) {
+
+	var (
+		now  = time.Now()
+		used = map[string]time.Time{
+			"sha256:1":   now.Add(-time.Hour),
+			"redis:5":    now.Add(-3 * time.Hour),
+			"sha256:4":   now.Add(-time.Minute),
+			"nginx:1.17": now.Add(-2 * time.Hour),
+		}
+		lastUsed = func(link string) time.Time {
+			return used[link]
+		}
+	)
+
+	images := []*types.Image{
+		getImageAsset("sha256:1", 10, now.Add(-24*time.Hour), "app:v1"),
+		getImageAsset("sha256:2", 10, now.Add(-24*time.Hour), "redis:5"),
+		getImageAsset("sha256:3", 10, now.Add(-48*time.Hour), "old:v1"),
+		getImageAsset("sha256:4", 10, now.Add(-48*time.Hour), "recent:v1"),
+		getImageAsset("sha256:5", 10, now.Add(-72*time.Hour), "nginx:1.17"),
+		getImageAsset("sha256:6", 10, now.Add(-96*time.Hour), "postgres:11"),
+	}
+
+	inuse := map[string]bool{imageReference("docker.io/library/postgres:11"): true}
+
+	candidates := imageGCCandidates(images, inuse, lastUsed, now)
+
+	// never used image is ordered by created time, recently used and in use images are skipped
+	assert.Equal(t, []string{"sha256:3", "sha256:2", "sha256:5", "sha256:1"}, imageIDs(candidates), "candidates order mismatch")
+}
+
+func TestImageGCSelect(t *testing.T) {
+
+	candidates := []*types.Image{
+		getImageAsset("sha256:1", 10, time.Time{}),
+		getImageAsset("sha256:2", 10, time.Time{}),
+		getImageAsset("sha256:3", 10, time.Time{}),
+	}
+
+	tests := []struct {
+		name  string
+		used  uint64
+		total uint64
+		want  []string
+	}{
+		{"checking usage under high threshold", 84, 100, []string{}},
+		{"checking usage over high threshold", 90, 100, []string{"sha256:1"}},
+		{"checking usage on high threshold", 85, 100, []string{"sha256:1"}},
+		{"checking usage far over high threshold", 99, 100, []string{"sha256:1", "sha256:2"}},
+		{"checking full filesystem", 100, 100, []string{"sha256:1", "sha256:2"}},
+		{"checking all candidates", 120, 120, []string{"sha256:1", "sha256:2", "sha256:3"}},
+		{"checking empty filesystem", 0, 0, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			selected := imageGCSelect(candidates, tc.used, tc.total, 85, 80)
+			assert.Equal(t, tc.want, imageIDs(selected), "selected images mismatch")
+		})
+	}
+}
@@ -0,0 +1,158 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/envs"
+)
+
+const (
+	logInitPrefix = "node:runtime:init"
+)
+
+// initExecute runs init container and waits until it exits.
+// Container is removed after exit, its state is kept in pod status
+func initExecute(ctx context.Context, pod string, m *types.ContainerManifest, ps *types.PodStatus) error {
+
+	var (
+		err error
+		c   = new(types.PodContainer)
+	)
+
+	log.V(logLevel).Debugf("%s init container %s start", logInitPrefix, m.Name)
+
+	m.Labels[types.ContainerTypeRuntime] = types.ContainerTypeRuntimeInit
+	m.RestartPolicy.Policy = "no"
+
+	c.Name = m.Name
+	c.Image = types.PodContainerImage{Name: m.Image}
+	ps.Runtime.Init = append(ps.Runtime.Init, c)
+
+	setError := func(err error) error {
+		c.State.Error = types.PodContainerStateError{
+			Error:   true,
+			Message: err.Error(),
+			Exit: types.PodContainerStateExit{
+				Timestamp: time.Now().UTC(),
+			},
+		}
+		envs.Get().GetState().Pods().SetPod(pod, ps)
+		return err
+	}
+
+	//========================================================================================
+	// create container ======================================================================
+	//========================================================================================
+
+	c.ID, err = envs.Get().GetCRI().Create(ctx, m)
+	if err != nil {
+		log.Errorf("%s can-not create container: %s", logInitPrefix, err)
+		return setError(err)
+	}
+
+	c.State.Created = types.PodContainerStateCreated{
+		Created: time.Now().UTC(),
+	}
+
+	envs.Get().GetState().Pods().SetPod(pod, ps)
+	log.V(logLevel).Debugf("%s container created: %s", logInitPrefix, c.ID)
+
+	//========================================================================================
+	// start container =======================================================================
+	//========================================================================================
+
+	if err := envs.Get().GetCRI().Start(ctx, c.ID); err != nil {
+		log.Errorf("%s can-not start container: %s", logInitPrefix, err)
+		initFinish(c)
+		return setError(err)
+	}
+
+	c.State.Started = types.PodContainerStateStarted{
+		Started:   true,
+		Timestamp: time.Now().UTC(),
+	}
+
+	envs.Get().GetState().Pods().SetPod(pod, ps)
+
+	//========================================================================================
+	// wait container ========================================================================
+	//========================================================================================
+
+	log.V(logLevel).Debugf("%s container wait: %s", logInitPrefix, c.ID)
+	if err := envs.Get().GetCRI().Wait(ctx, c.ID); err != nil {
+		log.Errorf("%s can-not wait container: %s", logInitPrefix, err)
+		initFinish(c)
+		return setError(err)
+	}
+
+	info, err := envs.Get().GetCRI().Inspect(ctx, c.ID)
+	if err != nil {
+		log.Errorf("%s can-not inspect container: %s", logInitPrefix, err)
+		initFinish(c)
+		return setError(err)
+	}
+
+	initFinish(c)
+
+	c.State.Started.Started = false
+	c.State.Stopped = types.PodContainerStateStopped{
+		Stopped: true,
+		Exit: types.PodContainerStateExit{
+			Code:      info.ExitCode,
+			Timestamp: time.Now().UTC(),
+		},
+	}
+
+	if info.ExitCode != 0 {
+		msg := fmt.Sprintf("init container %s exited with code %d", m.Name, info.ExitCode)
+		if info.Error != types.EmptyString {
+			msg = fmt.Sprintf("%s: %s", msg, info.Error)
+		}
+
+		c.State.Error = types.PodContainerStateError{
+			Error:   true,
+			Message: msg,
+			Exit:    c.State.Stopped.Exit,
+		}
+
+		envs.Get().GetState().Pods().SetPod(pod, ps)
+		return errors.New(msg)
+	}
+
+	c.Ready = true
+	envs.Get().GetState().Pods().SetPod(pod, ps)
+
+	log.V(logLevel).Debugf("%s init container %s finished", logInitPrefix, m.Name)
+	return nil
+}
+
+func initFinish(c *types.PodContainer) {
+
+	log.V(logLevel).Debugf("%s container remove: %s", logInitPrefix, c.ID)
+	if err := envs.Get().GetCRI().Remove(context.Background(), c.ID, true, true); err != nil {
+		log.Warnf("%s can-not remove container %s: %s", logInitPrefix, c.ID, err.Error())
+	}
+}
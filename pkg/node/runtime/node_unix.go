@@ -63,6 +63,7 @@ func NodeInfo() types.NodeInfo {
 		nt := net.Info(context.Background())
 		info.InternalIP = nt.IP
 		info.CIDR = nt.CIDR
+		info.NetworkKey = nt.Key
 	}
 
 	return info
@@ -118,3 +119,19 @@ func NodeAllocation() types.NodeResources {
 		Containers: s.GetContainersCount(),
 	}
 }
+
+// NodeDiskUsage returns used and total space in bytes of filesystem
+// where given path is located
+func NodeDiskUsage(path string) (uint64, uint64, error) {
+
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	total := stat.Blocks * uint64(stat.Bsize)
+	free := stat.Bfree * uint64(stat.Bsize)
+
+	return total - free, total, nil
+}
@@ -64,6 +64,7 @@ func NodeInfo() types.NodeInfo {
 		nt := net.Info(context.Background())
 		info.InternalIP = nt.IP
 		info.CIDR = nt.CIDR
+		info.NetworkKey = nt.Key
 	}
 
 	return info
@@ -127,3 +128,21 @@ func NodeAllocation() types.NodeResources {
 		Containers: s.GetContainersCount(),
 	}
 }
+
+// NodeDiskUsage returns used and total space in bytes of filesystem
+// where given path is located
+func NodeDiskUsage(path string) (uint64, uint64, error) {
+
+	var available, total, free uint64
+
+	h := syscall.MustLoadDLL("kernel32.dll")
+	c := h.MustFindProc("GetDiskFreeSpaceExW")
+
+	r, _, err := c.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(path))),
+		uintptr(unsafe.Pointer(&available)), uintptr(unsafe.Pointer(&total)), uintptr(unsafe.Pointer(&free)))
+	if r == 0 {
+		return 0, 0, err
+	}
+
+	return total - free, total, nil
+}
@@ -26,7 +26,9 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
@@ -41,6 +43,7 @@ import (
 const (
 	logPodPrefix               = "node:runtime:pod:>"
 	defaultRootLocalStorgePath = "/var/lib/lastbackend/runtime/"
+	defaultTerminationPeriod   = 10
 
 	BUFFER_SIZE = 1024
 )
@@ -85,12 +88,24 @@ func PodManage(ctx context.Context, key string, manifest *types.PodManifest) err
 			return nil
 		}
 
-		log.V(logLevel).Debugf("%s pod found > destroy it: %s", logPodPrefix, key)
+		if p.State == types.StateDestroy {
+			log.V(logLevel).Debugf("%s pod is already terminating: %s", logPodPrefix, key)
+			return nil
+		}
 
-		PodDestroy(ctx, key, p)
+		log.V(logLevel).Debugf("%s pod found > destroy it: %s", logPodPrefix, key)
 
-		p.SetDestroyed()
+		p.SetDestroy()
 		envs.Get().GetState().Pods().SetPod(key, p)
+
+		// terminate pod in background, containers have termination grace period to stop
+		go func() {
+			PodDestroy(context.Background(), key, manifest, p)
+
+			p.SetDestroyed()
+			envs.Get().GetState().Pods().SetPod(key, p)
+		}()
+
 		return nil
 	}
 
@@ -107,7 +122,7 @@ func PodManage(ctx context.Context, key string, manifest *types.PodManifest) err
 
 		switch true {
 		case !PodSpecCheck(ctx, key, manifest) || len(manifest.Runtime.Tasks) > 0:
-			PodDestroy(ctx, key, p)
+			PodDestroy(ctx, key, manifest, p)
 			break
 		case !PodVolumesCheck(ctx, key, manifest.Template.Volumes):
 			log.Debugf("%s volumes data changed: %s", logPodPrefix, key)
@@ -171,6 +186,13 @@ func PodManage(ctx context.Context, key string, manifest *types.PodManifest) err
 
 	log.V(logLevel).Debugf("%s pod not found > create it: %s", logPodPrefix, key)
 
+	if err := podSubnetCheck(ctx); err != nil {
+		status := types.NewPodStatus()
+		status.SetError(err)
+		envs.Get().GetState().Pods().AddPod(key, status)
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	envs.Get().GetState().Tasks().AddTask(key, &types.NodeTask{Cancel: cancel})
 
@@ -188,6 +210,23 @@ func PodManage(ctx context.Context, key string, manifest *types.PodManifest) err
 	return nil
 }
 
+// podSubnetCheck returns error if pod subnet allocated for node is not configured on container network,
+// addresses of pods in another subnet are routed to other nodes
+func podSubnetCheck(ctx context.Context) error {
+
+	network := envs.Get().GetNet()
+	subnet := envs.Get().GetState().Node().GetSubnet()
+	if network == nil || subnet == types.EmptyString {
+		return nil
+	}
+
+	if cidr := network.Info(ctx).CIDR; cidr != subnet {
+		return fmt.Errorf("pod subnet %s is allocated for node, but %s is configured", subnet, cidr)
+	}
+
+	return nil
+}
+
 func PodRestart(ctx context.Context, key string) error {
 
 	pod := envs.Get().GetState().Pods().GetPod(key)
@@ -331,7 +370,7 @@ func PodCreate(ctx context.Context, key string, manifest *types.PodManifest) (*t
 
 	var (
 		primary  string
-		services = make([]*types.ContainerManifest, 0)
+		services = make(types.SpecTemplateContainers, 0)
 	)
 
 	if len(manifest.Runtime.Services) == 0 {
@@ -342,13 +381,10 @@ func PodCreate(ctx context.Context, key string, manifest *types.PodManifest) (*t
 			manifest.Template.Containers = append(manifest.Template.Containers, tpl)
 		} else {
 			for _, s := range manifest.Template.Containers {
-				m, err := containerManifestCreate(ctx, key, s)
-				if err != nil {
-					log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
-					return setError(err)
+				if s.Role == types.ContainerRoleInit {
+					continue
 				}
-
-				services = append(services, m)
+				services = append(services, s)
 			}
 		}
 	}
@@ -358,24 +394,49 @@ func PodCreate(ctx context.Context, key string, manifest *types.PodManifest) (*t
 		for _, name := range manifest.Runtime.Services {
 			for _, s := range manifest.Template.Containers {
 
-				if s.Name != name {
+				if s.Name != name || s.Role == types.ContainerRoleInit {
 					continue
 				}
 
-				m, err := containerManifestCreate(ctx, key, s)
-				if err != nil {
-					log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
-					return setError(err)
-				}
-
-				services = append(services, m)
+				services = append(services, s)
 			}
 		}
 
 	}
 
+	// start services in ascending order, declaration order is kept for equal values
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Order < services[j].Order
+	})
+
+	// run init containers one by one before services,
+	// pod network is not created yet, so they use default network
+	for _, s := range manifest.Template.Containers {
+
+		if s.Role != types.ContainerRoleInit {
+			continue
+		}
+
+		m, err := containerManifestCreate(ctx, key, s)
+		if err != nil {
+			log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
+			return setError(err)
+		}
+
+		if err := initExecute(ctx, key, m, status); err != nil {
+			log.Errorf("%s init container failed: %s", logPodPrefix, err.Error())
+			return setError(err)
+		}
+	}
+
 	// run services
-	for _, svc := range services {
+	for _, s := range services {
+
+		svc, err := containerManifestCreate(ctx, key, s)
+		if err != nil {
+			log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
+			return setError(err)
+		}
 
 		if primary != types.EmptyString {
 			svc.Network.Mode = fmt.Sprintf("container:%s", primary)
@@ -384,7 +445,12 @@ func PodCreate(ctx context.Context, key string, manifest *types.PodManifest) (*t
 			svc.ExtraHosts = util.RemoveDuplicates(append(svc.ExtraHosts, envs.Get().GetConfig().Container.ExtraHosts...))
 		}
 
-		if err := serviceStart(ctx, key, svc, status); err != nil {
+		var probe *types.SpecTemplateContainerProbe
+		if s.Probes.ReadProbe.IsSet() {
+			probe = &s.Probes.ReadProbe
+		}
+
+		if err := serviceStart(ctx, key, svc, probe, status); err != nil {
 			log.Errorf("%s can not start service: %s", logPodPrefix, err.Error())
 			return status, err
 		}
@@ -563,8 +629,46 @@ func PodExit(ctx context.Context, pod string, status *types.PodStatus, clean boo
 	}
 }
 
-func PodDestroy(ctx context.Context, pod string, status *types.PodStatus) {
+// PodStop calls pre-stop hooks and stops all pod containers in parallel
+// within pod termination grace period
+func PodStop(ctx context.Context, pod string, manifest *types.PodManifest, status *types.PodStatus) {
+	log.V(logLevel).Debugf("%s stop pod: %s", logPodPrefix, pod)
+
+	var (
+		grace = time.Duration(defaultTerminationPeriod) * time.Second
+		hooks = make(map[string]types.SpecTemplateContainerHook, 0)
+		wg    sync.WaitGroup
+	)
+
+	if manifest != nil {
+		if manifest.Template.Termination > 0 {
+			grace = time.Duration(manifest.Template.Termination) * time.Second
+		}
+
+		for _, s := range manifest.Template.Containers {
+			hooks[containerNameCreate(getPodName(pod), s.Name)] = s.Lifecycle.PreStop
+		}
+	}
+
+	for _, c := range status.Runtime.Services {
+
+		if c.State.Stopped.Stopped {
+			continue
+		}
+
+		wg.Add(1)
+		go func(c *types.PodContainer) {
+			defer wg.Done()
+			serviceStop(ctx, c, hooks[c.Name], status.Network.PodIP, grace)
+		}(c)
+	}
+
+	wg.Wait()
+}
+
+func PodDestroy(ctx context.Context, pod string, manifest *types.PodManifest, status *types.PodStatus) {
 	log.V(logLevel).Debugf("%s try to remove pod: %s", logPodPrefix, pod)
+	PodStop(ctx, pod, manifest, status)
 	PodClean(ctx, status)
 	envs.Get().GetState().Pods().DelPod(pod)
 	for _, v := range status.Volumes {
@@ -603,6 +707,14 @@ func PodRestore(ctx context.Context) error {
 			continue
 		}
 
+		if c.Labels[types.ContainerTypeRuntime] == types.ContainerTypeRuntimeInit {
+			log.V(logLevel).Debugf("%s pod [%s] > remove init container %s", logPodPrefix, c.Pod, c.ID)
+			if err := envs.Get().GetCRI().Remove(ctx, c.ID, true, true); err != nil {
+				log.Warnf("%s can-not remove init container %s: %s", logPodPrefix, c.ID, err)
+			}
+			continue
+		}
+
 		log.V(logLevel).Debugf("%s pod [%s] > container restore %s", logPodPrefix, c.Pod, c.ID)
 
 		status := envs.Get().GetState().Pods().GetPod(c.Pod)
@@ -679,6 +791,7 @@ func PodRestore(ctx context.Context) error {
 		cs.Ready = true
 		status.Runtime.Services[cs.ID] = cs
 		status.Network.PodIP = c.Network.IPAddress
+		status.Network.PodIPv6 = c.Network.IPv6Address
 
 		log.V(logLevel).Debugf("%s container restored %s", logPodPrefix, c.ID)
 		envs.Get().GetState().Pods().SetPod(key, status)
@@ -772,6 +885,10 @@ func PodSpecCheck(ctx context.Context, key string, manifest *types.PodManifest)
 	var specc = make(map[string]*types.ContainerManifest, 0)
 
 	for _, c := range manifest.Template.Containers {
+		if c.Role == types.ContainerRoleInit {
+			continue
+		}
+
 		mf, err := containerManifestCreate(ctx, key, c)
 		if err != nil {
 			return false
@@ -1202,3 +1319,8 @@ func getPodNamespace(key string) string {
 
 	return namespace
 }
+
+func getPodName(key string) string {
+	parts := strings.Split(key, ":")
+	return parts[len(parts)-1]
+}
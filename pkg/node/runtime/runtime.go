@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
@@ -211,6 +212,11 @@ func (r *Runtime) Loop() {
 
 				if spec.Meta.Initial {
 
+					envs.Get().GetState().Node().SetSubnet(spec.Meta.Subnet)
+					if err := podSubnetCheck(ctx); err != nil {
+						log.Errorf("%s:> pods are not created: %s", logNodeRuntimePrefix, err.Error())
+					}
+
 					if network != nil {
 
 						log.V(logLevel).Debugf("%s:> clean up endpoints", logNodeRuntimePrefix)
@@ -233,8 +239,9 @@ func (r *Runtime) Loop() {
 
 					for k := range pods {
 						if _, ok := spec.Pods[k]; !ok {
-							if !envs.Get().GetState().Pods().IsLocal(k) {
-								PodDestroy(context.Background(), k, pods[k])
+							if !envs.Get().GetState().Pods().IsLocal(k) && pods[k].State != types.StateDestroy {
+								pods[k].SetDestroy()
+								go PodDestroy(context.Background(), k, nil, pods[k])
 							}
 						}
 					}
@@ -250,6 +257,15 @@ func (r *Runtime) Loop() {
 						}
 					}
 
+					if network != nil {
+						log.V(logLevel).Debugf("%s:> clean up network policies", logNodeRuntimePrefix)
+						for ns := range network.Policies().GetPolicies() {
+							if _, ok := spec.Policies[ns]; !ok {
+								network.Policies().DelPolicy(ns)
+							}
+						}
+					}
+
 					if network != nil {
 						log.V(logLevel).Debugf("%s:> clean up subnets", logNodeRuntimePrefix)
 						nets := network.Subnets().GetSubnets()
@@ -289,6 +305,13 @@ func (r *Runtime) Loop() {
 					}
 				}
 
+				if network != nil && spec.Meta.NetworkKey != types.EmptyString {
+					log.V(logLevel).Debugf("%s:> switch network to next key", logNodeRuntimePrefix)
+					if err := network.KeyCommit(ctx, spec.Meta.NetworkKey); err != nil {
+						log.Errorf("Network key commit err: %s", err.Error())
+					}
+				}
+
 				log.V(logLevel).Debugf("%s:> update secrets %d", logNodeRuntimePrefix, len(spec.Secrets))
 				for s, spec := range spec.Secrets {
 					log.V(logLevel).Debugf("secret: %s > %s", s, spec.State)
@@ -320,6 +343,21 @@ func (r *Runtime) Loop() {
 					}
 				}
 
+				if network != nil && (spec.Meta.Initial || len(spec.Policies) != 0) {
+					log.V(logLevel).Debugf("%s:> provision network policies", logNodeRuntimePrefix)
+					for ns, m := range spec.Policies {
+						if m.State == types.StateDestroy {
+							network.Policies().DelPolicy(ns)
+							continue
+						}
+						network.Policies().SetPolicy(ns, m)
+					}
+
+					if err := network.PolicySync(ctx); err != nil {
+						log.Errorf("Network policies sync err: %s", err.Error())
+					}
+				}
+
 				log.V(logLevel).Debugf("%s:> provision volumes", logNodeRuntimePrefix)
 				for v, spec := range spec.Volumes {
 					log.V(logLevel).Debugf("volume: %v", v)
@@ -343,6 +381,35 @@ func (r *Runtime) Subscribe() {
 	}()
 }
 
+// GC runtime method starts images garbage collection loop
+func (r *Runtime) GC() {
+
+	log.V(logLevel).Debugf("%s:gc:> start images garbage collection", logNodeRuntimePrefix)
+
+	period := defaultImageGCPeriod
+	if envs.Get().GetConfig().Container.Iri.GC.Period > 0 {
+		period = envs.Get().GetConfig().Container.Iri.GC.Period
+	}
+
+	go func(ctx context.Context) {
+
+		ticker := time.NewTicker(time.Duration(period) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			if err := ImageGC(ctx); err != nil {
+				log.Errorf("%s:gc:> images garbage collection err: %s", logNodeRuntimePrefix, err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}(r.ctx)
+}
+
 func (r *Runtime) Stop() {
 	r.cancel()
 }
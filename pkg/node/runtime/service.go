@@ -20,17 +20,28 @@ package runtime
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/node/envs"
-	"time"
 )
 
 const (
 	logServicePrefix = "node:runtime:service"
+
+	defaultProbeTimeout          = 1
+	defaultProbePeriod           = 10
+	defaultProbeThresholdSuccess = 1
+	defaultProbeStartupTimeout   = 300
 )
 
-func serviceStart(ctx context.Context, pod string, m *types.ContainerManifest, status *types.PodStatus) error {
+func serviceStart(ctx context.Context, pod string, m *types.ContainerManifest, probe *types.SpecTemplateContainerProbe, status *types.PodStatus) error {
 
 	var (
 		err error
@@ -104,7 +115,6 @@ func serviceStart(ctx context.Context, pod string, m *types.ContainerManifest, s
 		return err
 	}
 
-	c.Ready = true
 	c.State.Started = types.PodContainerStateStarted{
 		Started:   true,
 		Timestamp: time.Now().UTC(),
@@ -125,11 +135,237 @@ func serviceStart(ctx context.Context, pod string, m *types.ContainerManifest, s
 		status.Network.PodIP = info.Network.IPAddress
 	}
 
+	if status.Network.PodIPv6 == "" {
+		status.Network.PodIPv6 = info.Network.IPv6Address
+	}
+
+	if probe != nil {
+
+		envs.Get().GetState().Pods().SetPod(pod, status)
+
+		if err := serviceReady(ctx, c.ID, m.Name, status.Network.PodIP, *probe); err != nil {
+			switch err {
+			case context.Canceled:
+				log.Errorf("%s stop waiting container ready err: %s", logServicePrefix, err.Error())
+				return nil
+			}
+
+			log.Errorf("%s container not ready: %s", logServicePrefix, err)
+
+			c.State.Error = types.PodContainerStateError{
+				Error:   true,
+				Message: err.Error(),
+				Exit: types.PodContainerStateExit{
+					Timestamp: time.Now().UTC(),
+				},
+			}
+			return err
+		}
+	}
+
+	c.Ready = true
+
 	envs.Get().GetState().Pods().SetPod(pod, status)
 	return nil
 }
 
-func serviceStop() error {
+// serviceReady blocks until container passes the readiness probe,
+// so containers with greater startup order are started after it.
+// Failed checks are retried until container startup timeout expires
+func serviceReady(ctx context.Context, id, name, ip string, probe types.SpecTemplateContainerProbe) error {
+
+	var (
+		timeout = defaultProbeTimeout
+		period  = defaultProbePeriod
+		success = defaultProbeThresholdSuccess
+		startup = defaultProbeStartupTimeout
+		passed  int
+		err     error
+	)
+
+	if probe.TimeoutSeconds > 0 {
+		timeout = probe.TimeoutSeconds
+	}
+
+	if probe.PeriodSeconds > 0 {
+		period = probe.PeriodSeconds
+	}
+
+	if probe.ThresholdSuccess > 0 {
+		success = probe.ThresholdSuccess
+	}
+
+	if probe.StartupTimeoutSeconds > 0 {
+		startup = probe.StartupTimeoutSeconds
+	}
+
+	if ip == types.EmptyString {
+		ip = "127.0.0.1"
+	}
+
+	log.V(logLevel).Debugf("%s wait container %s ready in %d seconds", logServicePrefix, name, startup)
+
+	sctx, cancel := context.WithTimeout(ctx, time.Duration(startup)*time.Second)
+	defer cancel()
+
+	delay := time.Duration(probe.InitialDelaySeconds) * time.Second
+	for {
+
+		select {
+		case <-sctx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == nil {
+				err = sctx.Err()
+			}
+			return fmt.Errorf("container %s is not ready in %d seconds: %s", name, startup, err.Error())
+		case <-time.After(delay):
+		}
+
+		delay = time.Duration(period) * time.Second
+
+		if err = serviceProbe(sctx, id, ip, probe, time.Duration(timeout)*time.Second); err != nil {
+			log.V(logLevel).Debugf("%s container %s not ready: %s", logServicePrefix, name, err.Error())
+			passed = 0
+			continue
+		}
+
+		passed++
+		if passed >= success {
+			log.V(logLevel).Debugf("%s container %s ready", logServicePrefix, name)
+			return nil
+		}
+	}
+}
+
+// serviceProbe runs single probe check: exec command, http request or socket connect
+func serviceProbe(ctx context.Context, id, ip string, probe types.SpecTemplateContainerProbe, timeout time.Duration) error {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case len(probe.Exec.Command) != 0:
+		code, err := envs.Get().GetCRI().Exec(ctx, id, probe.Exec.Command, &timeout)
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			return fmt.Errorf("command exited with code %d", code)
+		}
+
+		return nil
+	case probe.HTTP.Port != 0:
+		return serviceHTTPGet(ctx, ip, probe.HTTP.Port, probe.HTTP.Path)
+	default:
+		protocol := "tcp"
+		if probe.Socket.Protocol != types.EmptyString {
+			protocol = probe.Socket.Protocol
+		}
+
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, protocol, net.JoinHostPort(ip, strconv.Itoa(probe.Socket.Port)))
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
+
+// serviceStop calls container pre-stop hook and stops container
+// within termination grace period, after that container is killed
+func serviceStop(ctx context.Context, c *types.PodContainer, hook types.SpecTemplateContainerHook, ip string, grace time.Duration) error {
+
+	var deadline = time.Now().Add(grace)
+
+	if len(hook.Exec.Command) != 0 || hook.HTTP.Port != 0 {
+		hctx, cancel := context.WithDeadline(ctx, deadline)
+		if err := serviceHook(hctx, c.ID, ip, hook); err != nil {
+			log.Warnf("%s pre-stop hook failed for container %s: %s", logServicePrefix, c.ID, err.Error())
+		}
+		cancel()
+	}
+
+	timeout := time.Until(deadline)
+	if timeout < 0 {
+		timeout = 0
+	}
+
+	log.V(logLevel).Debugf("%s stop container %s with timeout %s", logServicePrefix, c.ID, timeout.String())
+
+	if err := envs.Get().GetCRI().Stop(ctx, c.ID, &timeout); err != nil {
+		log.Warnf("%s can-not stop container %s: %s", logServicePrefix, c.ID, err.Error())
+		return err
+	}
+
+	c.Ready = false
+	c.State.Stopped = types.PodContainerStateStopped{
+		Stopped: true,
+		Exit: types.PodContainerStateExit{
+			Timestamp: time.Now().UTC(),
+		},
+	}
+
+	return nil
+}
+
+func serviceHook(ctx context.Context, id, ip string, hook types.SpecTemplateContainerHook) error {
+
+	if len(hook.Exec.Command) != 0 {
+		log.V(logLevel).Debugf("%s exec pre-stop hook in container %s", logServicePrefix, id)
+
+		code, err := envs.Get().GetCRI().Exec(ctx, id, hook.Exec.Command, nil)
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			return fmt.Errorf("command exited with code %d", code)
+		}
+	}
+
+	if hook.HTTP.Port != 0 {
+
+		if ip == types.EmptyString {
+			ip = "127.0.0.1"
+		}
+
+		log.V(logLevel).Debugf("%s call pre-stop hook on port %d for container %s", logServicePrefix, hook.HTTP.Port, id)
+
+		if err := serviceHTTPGet(ctx, ip, hook.HTTP.Port, hook.HTTP.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serviceHTTPGet sends http GET request to container, response status below 400 is success
+func serviceHTTPGet(ctx context.Context, ip string, port int, path string) error {
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(ip, strconv.Itoa(port)),
+		Path:   path,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http request returned status %d", res.StatusCode)
+	}
 
 	return nil
 }
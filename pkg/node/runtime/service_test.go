@@ -0,0 +1,98 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceReady(t *testing.T) {
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	p, _ := strconv.Atoi(port)
+
+	var tests = []struct {
+		name    string
+		probe   func(*types.SpecTemplateContainerProbe)
+		wantErr bool
+	}{
+		{
+			name: "check http probe is retried after failure",
+			probe: func(pr *types.SpecTemplateContainerProbe) {
+				pr.HTTP.Path = "/healthz"
+				pr.HTTP.Port = p
+			},
+		},
+		{
+			name: "check socket probe",
+			probe: func(pr *types.SpecTemplateContainerProbe) {
+				pr.Socket.Port = p
+			},
+		},
+		{
+			name: "check http probe fails after startup timeout",
+			probe: func(pr *types.SpecTemplateContainerProbe) {
+				pr.HTTP.Path = "/unknown"
+				pr.HTTP.Port = p
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			probe := types.SpecTemplateContainerProbe{}
+			probe.PeriodSeconds = 1
+			probe.StartupTimeoutSeconds = 2
+			tc.probe(&probe)
+
+			err := serviceReady(context.Background(), "id", "test", host, probe)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
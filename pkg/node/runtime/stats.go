@@ -0,0 +1,86 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/envs"
+)
+
+const logStatsPrefix = "node:runtime:stats:>"
+
+// PodStats collects resources usage of all pod containers
+func PodStats(ctx context.Context, key string) (*types.PodStats, error) {
+
+	p := envs.Get().GetState().Pods().GetPod(key)
+	if p == nil {
+		return nil, errors.New("pod not found")
+	}
+
+	stats := types.NewPodStats(key)
+
+	for _, c := range p.Runtime.Services {
+
+		if c.State.Stopped.Stopped {
+			continue
+		}
+
+		cs, err := envs.Get().GetCRI().Stats(ctx, c.ID)
+		if err != nil {
+			log.Warnf("%s can not get container %s stats: %s", logStatsPrefix, c.ID, err.Error())
+			continue
+		}
+
+		stats.AddContainer(cs)
+	}
+
+	return stats, nil
+}
+
+// NodeStats collects resources usage of all local pods and endpoints connections
+func NodeStats(ctx context.Context) (*types.NodeStats, error) {
+
+	stats := types.NewNodeStats(envs.Get().GetState().Node().Info.Hostname)
+
+	for key := range envs.Get().GetState().Pods().GetPods() {
+
+		ps, err := PodStats(ctx, key)
+		if err != nil {
+			log.Warnf("%s can not get pod %s stats: %s", logStatsPrefix, key, err.Error())
+			continue
+		}
+
+		stats.AddPod(ps)
+	}
+
+	if network := envs.Get().GetNet(); network != nil {
+		endpoints, err := network.EndpointStats(ctx)
+		if err != nil {
+			log.Warnf("%s can not get endpoints stats: %s", logStatsPrefix, err.Error())
+			return stats, nil
+		}
+		stats.Endpoints = endpoints
+	}
+
+	return stats, nil
+}
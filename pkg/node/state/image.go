@@ -23,6 +23,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"strings"
 	"sync"
+	"time"
 )
 
 const logImagePrefix = "state:images:>"
@@ -30,6 +31,7 @@ const logImagePrefix = "state:images:>"
 type ImageState struct {
 	lock   sync.RWMutex
 	images map[string]*types.Image
+	used   map[string]time.Time
 }
 
 func (s *ImageState) GetImages() map[string]*types.Image {
@@ -88,3 +90,24 @@ func (s *ImageState) DelImage(link string) {
 		delete(s.images, link)
 	}
 }
+
+func (s *ImageState) SetUsed(link string) {
+	log.V(logLevel).Debugf("%s set image used: %s", logImagePrefix, link)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.used[link] = time.Now()
+}
+
+func (s *ImageState) GetUsed(link string) time.Time {
+	log.V(logLevel).Debugf("%s get image used: %s", logImagePrefix, link)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.used[link]
+}
+
+func (s *ImageState) DelUsed(link string) {
+	log.V(logLevel).Debugf("%s del image used: %s", logImagePrefix, link)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.used, link)
+}
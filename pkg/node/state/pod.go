@@ -205,6 +205,8 @@ func state(s *types.PodStatus) {
 	switch s.State {
 	case types.StateExited:
 		return
+	case types.StateDestroy:
+		return
 	case types.StateDestroyed:
 		return
 	case types.StateError:
@@ -19,6 +19,9 @@
 package state
 
 import (
+	"sync"
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
@@ -73,8 +76,35 @@ func (s *State) Configs() *ConfigState {
 }
 
 type NodeState struct {
+	lock   sync.RWMutex
 	Info   types.NodeInfo
 	Status types.NodeStatus
+	// Subnet is pod subnet allocated for node by controller
+	subnet string
+}
+
+func (s *NodeState) GetSubnet() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.subnet
+}
+
+func (s *NodeState) SetSubnet(cidr string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subnet = cidr
+}
+
+func (s *NodeState) GetStatus() types.NodeStatus {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.Status
+}
+
+func (s *NodeState) SetDiskPressure(pressure bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Status.Pressure.Disk = pressure
 }
 
 func New() *State {
@@ -89,6 +119,7 @@ func New() *State {
 		},
 		images: &ImageState{
 			images: make(map[string]*types.Image, 0),
+			used:   make(map[string]time.Time, 0),
 		},
 		networks: &NetworkState{
 			subnets: make(map[string]types.NetworkState, 0),
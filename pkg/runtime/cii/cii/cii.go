@@ -22,14 +22,16 @@ import (
 	"fmt"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cii"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cii/containerd"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cii/docker"
 	"github.com/spf13/viper"
 )
 
 const (
-	logLevel     = 5
-	dockerDriver = "docker"
-	runcDriver   = "runc"
+	logLevel         = 5
+	dockerDriver     = "docker"
+	containerdDriver = "containerd"
+	runcDriver       = "runc"
 )
 
 func New(v *viper.Viper) (cii.CII, error) {
@@ -48,6 +50,12 @@ func New(v *viper.Viper) (cii.CII, error) {
 		}
 
 		return docker.New(cfg)
+	case containerdDriver:
+		log.V(logLevel).Debugf("Use containerd runtime interface for cii")
+		cfg := containerd.Config{}
+		cfg.Host = v.GetString("container.iri.containerd.host")
+
+		return containerd.New(cfg)
 	default:
 		return nil, fmt.Errorf("image runtime <%s> interface not supported", v.GetString("container.iri.type"))
 	}
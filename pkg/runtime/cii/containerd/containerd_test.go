@@ -0,0 +1,234 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeImages struct {
+	sync.Mutex
+
+	images map[string]*ctrd.Image
+	auth   *ctrd.AuthConfig
+}
+
+func (f *fakeImages) ListImages(ctx context.Context, in *ctrd.ListImagesRequest) (*ctrd.ListImagesResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	res := new(ctrd.ListImagesResponse)
+	for _, i := range f.images {
+		res.Images = append(res.Images, i)
+	}
+	return res, nil
+}
+
+func (f *fakeImages) ImageStatus(ctx context.Context, in *ctrd.ImageStatusRequest) (*ctrd.ImageStatusResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	for _, i := range f.images {
+		if i.Id == in.Image.Image || (len(i.RepoTags) > 0 && i.RepoTags[0] == in.Image.Image) {
+			res := &ctrd.ImageStatusResponse{Image: i}
+			if in.Verbose {
+				res.Info = map[string]string{
+					"info": `{"imageSpec":{"config":{"Env":["PATH=/bin"],"Cmd":["redis-server"],"WorkingDir":"/data"}}}`,
+				}
+			}
+			return res, nil
+		}
+	}
+	return new(ctrd.ImageStatusResponse), nil
+}
+
+func (f *fakeImages) PullImage(ctx context.Context, in *ctrd.PullImageRequest) (*ctrd.PullImageResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	if in.Image.Image == "private:latest" && (in.Auth == nil || in.Auth.Password != "secret") {
+		return nil, status.Error(codes.Unauthenticated, "pull access denied")
+	}
+	f.auth = in.Auth
+	id := fmt.Sprintf("sha256:%d", len(f.images)+1)
+	f.images[id] = &ctrd.Image{Id: id, RepoTags: []string{in.Image.Image}, RepoDigests: []string{"redis@sha256:digest"}, Size_: 1024}
+	return &ctrd.PullImageResponse{ImageRef: id}, nil
+}
+
+func (f *fakeImages) RemoveImage(ctx context.Context, in *ctrd.RemoveImageRequest) (*ctrd.RemoveImageResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	for id, i := range f.images {
+		if id == in.Image.Image || (len(i.RepoTags) > 0 && i.RepoTags[0] == in.Image.Image) {
+			delete(f.images, id)
+		}
+	}
+	return new(ctrd.RemoveImageResponse), nil
+}
+
+func (f *fakeImages) ImageFsInfo(ctx context.Context, in *ctrd.ImageFsInfoRequest) (*ctrd.ImageFsInfoResponse, error) {
+	return &ctrd.ImageFsInfoResponse{
+		ImageFilesystems: []*ctrd.FilesystemUsage{{FsId: &ctrd.FilesystemIdentifier{Mountpoint: "/var/lib/containerd"}}},
+	}, nil
+}
+
+func serve(t *testing.T) (*Runtime, *fakeImages, func()) {
+
+	dir, err := ioutil.TempDir("", "containerd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sock := path.Join(dir, "containerd.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeImages{images: make(map[string]*ctrd.Image)}
+	srv := grpc.NewServer()
+	ctrd.RegisterImageServiceServer(srv, fake)
+	go srv.Serve(l)
+
+	r, err := New(Config{Host: fmt.Sprintf("unix://%s", sock)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r, fake, func() {
+		r.client.Close()
+		srv.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestImagePull(t *testing.T) {
+
+	r, fake, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	auth, err := r.Auth(ctx, &types.SecretAuthData{Username: "demo", Password: "secret"})
+	if !assert.NoError(t, err, "auth should be encoded") {
+		return
+	}
+
+	js, err := base64.URLEncoding.DecodeString(auth)
+	if !assert.NoError(t, err, "auth should be base64 encoded") {
+		return
+	}
+
+	config := types.AuthConfig{}
+	if !assert.NoError(t, json.Unmarshal(js, &config), "auth should be json encoded") {
+		return
+	}
+	assert.Equal(t, "demo", config.Username, "auth username")
+
+	tests := []struct {
+		name    string
+		spec    *types.ImageManifest
+		wantErr bool
+	}{
+		{
+			name: "public image",
+			spec: &types.ImageManifest{Name: "redis:latest"},
+		},
+		{
+			name: "private image with auth",
+			spec: &types.ImageManifest{Name: "private:latest", Auth: auth},
+		},
+		{
+			name:    "private image without auth",
+			spec:    &types.ImageManifest{Name: "private:latest"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			img, err := r.Pull(ctx, tc.spec, nil)
+			if tc.wantErr {
+				assert.Error(t, err, "error should be not nil")
+				return
+			}
+
+			if !assert.NoError(t, err, "error should be nil") {
+				return
+			}
+
+			assert.Equal(t, []string{tc.spec.Name}, img.Meta.Tags, "image tags")
+			assert.Equal(t, "redis@sha256:digest", img.Meta.Digest, "image digest")
+			assert.Equal(t, int64(1024), img.Status.Size, "image size")
+			assert.Equal(t, []string{"redis-server"}, img.Status.Container.Exec.Command, "image command")
+			assert.Equal(t, "/data", img.Status.Container.Exec.Workdir, "image workdir")
+
+			if tc.spec.Auth != types.EmptyString {
+				assert.Equal(t, "secret", fake.auth.Password, "auth password")
+			}
+		})
+	}
+}
+
+func TestImageListRemove(t *testing.T) {
+
+	r, _, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	for _, name := range []string{"redis:latest", "nginx:latest"} {
+		if _, err := r.Pull(ctx, &types.ImageManifest{Name: name}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	images, err := r.List(ctx)
+	if !assert.NoError(t, err, "list images") {
+		return
+	}
+	assert.Len(t, images, 2, "images count")
+
+	if !assert.NoError(t, r.Remove(ctx, "redis:latest"), "remove image") {
+		return
+	}
+
+	images, err = r.List(ctx)
+	if !assert.NoError(t, err, "list images") {
+		return
+	}
+	assert.Len(t, images, 1, "images count after remove")
+
+	_, err = r.Inspect(ctx, "redis:latest")
+	assert.Error(t, err, "removed image should not be found")
+}
@@ -0,0 +1,41 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+// Subscribe returns images channel, cri plugin does not provide image events,
+// so channel is only closed when context is done
+func (r *Runtime) Subscribe(ctx context.Context) (chan *types.Image, error) {
+
+	log.V(logLevel).Debugf("%s:subscribe:> image events are not supported, skip", logPrefix)
+	var cs = make(chan *types.Image)
+
+	go func() {
+		<-ctx.Done()
+		close(cs)
+	}()
+
+	return cs, nil
+}
@@ -0,0 +1,217 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "runtime:containerd"
+)
+
+var ErrNotSupported = errors.New("operation not supported by containerd runtime")
+
+func (r *Runtime) Auth(ctx context.Context, secret *types.SecretAuthData) (string, error) {
+
+	config := types.AuthConfig{
+		Username: secret.Username,
+		Password: secret.Password,
+	}
+
+	js, err := json.Marshal(config)
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	return base64.URLEncoding.EncodeToString(js), nil
+}
+
+func (r *Runtime) Pull(ctx context.Context, spec *types.ImageManifest, out io.Writer) (*types.Image, error) {
+
+	log.V(logLevel).Debugf("%s:pull:> image pull: %s", logPrefix, spec.Name)
+
+	req := &ctrd.PullImageRequest{
+		Image: &ctrd.ImageSpec{Image: spec.Name},
+	}
+
+	if spec.Auth != types.EmptyString {
+
+		js, err := base64.URLEncoding.DecodeString(spec.Auth)
+		if err != nil {
+			return nil, err
+		}
+
+		config := types.AuthConfig{}
+		if err := json.Unmarshal(js, &config); err != nil {
+			return nil, err
+		}
+
+		req.Auth = &ctrd.AuthConfig{
+			Username:      config.Username,
+			Password:      config.Password,
+			Auth:          config.Auth,
+			ServerAddress: config.ServerAddress,
+		}
+	}
+
+	res, err := r.client.Image.PullImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if out != nil {
+		status := fmt.Sprintf("{\"status\":\"Downloaded image for %s\",\"id\":\"%s\"}\n", spec.Name, res.ImageRef)
+		if _, err := out.Write([]byte(status)); err != nil {
+			return nil, err
+		}
+
+		if f, ok := out.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	return r.Inspect(ctx, spec.Name)
+}
+
+// Push is not supported by cri plugin
+func (r *Runtime) Push(ctx context.Context, spec *types.ImageManifest, out io.Writer) (*types.Image, error) {
+	return nil, ErrNotSupported
+}
+
+// Build is not supported by cri plugin
+func (r *Runtime) Build(ctx context.Context, stream io.Reader, spec *types.SpecBuildImage, out io.Writer) (*types.Image, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *Runtime) Remove(ctx context.Context, ID string) error {
+	log.V(logLevel).Debugf("%s:remove:> image remove: %s", logPrefix, ID)
+	_, err := r.client.Image.RemoveImage(ctx, &ctrd.RemoveImageRequest{
+		Image: &ctrd.ImageSpec{Image: ID},
+	})
+	return err
+}
+
+func (r *Runtime) List(ctx context.Context) ([]*types.Image, error) {
+
+	var images = make([]*types.Image, 0)
+
+	res, err := r.client.Image.ListImages(ctx, new(ctrd.ListImagesRequest))
+	if err != nil {
+		return images, err
+	}
+
+	for _, i := range res.Images {
+
+		if len(i.RepoTags) == 0 {
+			continue
+		}
+
+		img, err := r.Inspect(ctx, i.Id)
+		if err != nil {
+			return images, err
+		}
+
+		images = append(images, img)
+	}
+
+	return images, nil
+}
+
+func (r *Runtime) Inspect(ctx context.Context, id string) (*types.Image, error) {
+
+	res, err := r.client.Image.ImageStatus(ctx, &ctrd.ImageStatusRequest{
+		Image:   &ctrd.ImageSpec{Image: id},
+		Verbose: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Image == nil {
+		return nil, fmt.Errorf("image %s not found", id)
+	}
+
+	info := res.Image
+
+	image := new(types.Image)
+	image.Meta.ID = info.Id
+
+	if len(info.RepoDigests) > 0 {
+		image.Meta.Digest = info.RepoDigests[0]
+	}
+
+	image.Meta.Tags = info.RepoTags
+	image.Status.Size = int64(info.Size_)
+	image.Status.VirtualSize = int64(info.Size_)
+
+	if v, ok := res.Info["info"]; ok {
+		var verbose struct {
+			ImageSpec struct {
+				Created time.Time `json:"created"`
+				Config  struct {
+					Env        []string `json:"Env"`
+					Entrypoint []string `json:"Entrypoint"`
+					Cmd        []string `json:"Cmd"`
+					WorkingDir string   `json:"WorkingDir"`
+				} `json:"config"`
+			} `json:"imageSpec"`
+		}
+
+		if err := json.Unmarshal([]byte(v), &verbose); err != nil {
+			log.Warnf("%s:inspect:> can not parse image %s info: %v", logPrefix, id, err)
+		} else {
+			image.Meta.Created = verbose.ImageSpec.Created
+			image.Status.Container.Envs = verbose.ImageSpec.Config.Env
+			image.Status.Container.Exec.Command = verbose.ImageSpec.Config.Cmd
+			image.Status.Container.Exec.Entrypoint = verbose.ImageSpec.Config.Entrypoint
+			image.Status.Container.Exec.Workdir = verbose.ImageSpec.Config.WorkingDir
+		}
+	}
+
+	return image, nil
+}
+
+func (r *Runtime) Root(ctx context.Context) (string, error) {
+
+	res, err := r.client.Image.ImageFsInfo(ctx, new(ctrd.ImageFsInfoRequest))
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	for _, fs := range res.ImageFilesystems {
+		if fs.FsId != nil && fs.FsId.Mountpoint != types.EmptyString {
+			return fs.FsId.Mountpoint, nil
+		}
+	}
+
+	return types.EmptyString, errors.New("image filesystem is not reported by runtime")
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"io"
 	"net/http"
+	"time"
 )
 
 const (
@@ -323,6 +324,7 @@ func (r *Runtime) Inspect(ctx context.Context, id string) (*types.Image, error)
 	}
 
 	image.Meta.Tags = info.RepoTags
+	image.Meta.Created, _ = time.Parse(time.RFC3339Nano, info.Created)
 	image.Status.Size = info.Size
 	image.Status.VirtualSize = info.VirtualSize
 
@@ -338,3 +340,11 @@ func (r *Runtime) Inspect(ctx context.Context, id string) (*types.Image, error)
 
 	return image, err
 }
+
+func (r *Runtime) Root(ctx context.Context) (string, error) {
+	info, err := r.client.Info(ctx)
+	if err != nil {
+		return types.EmptyString, err
+	}
+	return info.DockerRootDir, nil
+}
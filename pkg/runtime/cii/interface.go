@@ -34,4 +34,6 @@ type CII interface {
 	List(ctx context.Context) ([]*types.Image, error)
 	Inspect(ctx context.Context, id string) (*types.Image, error)
 	Subscribe(ctx context.Context) (chan *types.Image, error)
+	// Root returns path of filesystem where images are stored by runtime
+	Root(ctx context.Context) (string, error)
 }
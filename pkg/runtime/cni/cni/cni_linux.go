@@ -23,6 +23,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/runtime/cni"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cni/local"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cni/vxlan"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cni/wireguard"
 	"github.com/spf13/viper"
 )
 
@@ -30,6 +31,9 @@ func New(v *viper.Viper) (cni.CNI, error) {
 	switch v.GetString("network.cni.type") {
 	case "vxlan":
 		return vxlan.New(v.GetString("network.interface"))
+	case "wireguard":
+		return wireguard.New(v.GetString("network.interface"),
+			v.GetString("network.cni.wireguard.key"), v.GetDuration("network.cni.wireguard.rotation"))
 	default:
 		return local.New()
 	}
@@ -29,4 +29,11 @@ type CNI interface {
 	Destroy(ctx context.Context, network *types.NetworkState) error
 	Replace(ctx context.Context, state *types.NetworkState, manifest *types.SubnetManifest) (*types.NetworkState, error)
 	Subnets(ctx context.Context) (map[string]*types.NetworkState, error)
+	Policy(ctx context.Context, rules map[string]*types.NetworkPolicyRules) error
+}
+
+// KeyRotator is implemented by network drivers rotating node key in two phases:
+// next key is announced in network state first and node switches to it when all nodes accept it
+type KeyRotator interface {
+	CommitKey(ctx context.Context, key string) error
 }
@@ -94,7 +94,11 @@ func (n *Network) Info(ctx context.Context) *types.NetworkState {
 }
 
 func (n *Network) Create(ctx context.Context, network *types.SubnetManifest) (*types.NetworkState, error) {
-	return n.Info(ctx), nil
+	state := n.Info(ctx)
+	// keep remote subnet attributes to not replace subnet on each sync
+	state.Key = network.Key
+	state.CIDRv6 = network.CIDRv6
+	return state, nil
 }
 
 func (n *Network) Destroy(ctx context.Context, network *types.NetworkState) error {
@@ -108,3 +112,7 @@ func (n *Network) Replace(ctx context.Context, state *types.NetworkState, manife
 func (n *Network) Subnets(ctx context.Context) (map[string]*types.NetworkState, error) {
 	return nil, nil
 }
+
+func (n *Network) Policy(ctx context.Context, rules map[string]*types.NetworkPolicyRules) error {
+	return nil
+}
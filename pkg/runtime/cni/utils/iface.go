@@ -124,6 +124,29 @@ func GetIfaceIP4Addr(iface *net.Interface) (net.IP, error) {
 	return nil, errors.New("No IPv4 address found for given interface")
 }
 
+// GetIfaceIP6Net returns global IPv6 network of interface,
+// nil network is returned if interface has no IPv6 address
+func GetIfaceIP6Net(iface *net.Interface) (*net.IPNet, error) {
+	link := &netlink.Device{
+		LinkAttrs: netlink.LinkAttrs{
+			Index: iface.Index,
+		},
+	}
+
+	addrs, err := netlink.AddrList(link, syscall.AF_INET6)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if addr.IP.To4() == nil && addr.IP.IsGlobalUnicast() {
+			return &net.IPNet{IP: addr.IP.Mask(addr.Mask), Mask: addr.Mask}, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func getIfaceAddrs(iface *net.Interface) ([]netlink.Addr, error) {
 	link := &netlink.Device{
 		netlink.LinkAttrs{
@@ -56,6 +56,22 @@ func MasqRules(ip net.IP, subnet string) []IPTablesRule {
 	}
 }
 
+// NodePortMasqRules masquerades ipvs connections to node address,
+// so replies of upstreams on other nodes are returned through this node
+func NodePortMasqRules(ip net.IP) []IPTablesRule {
+	return []IPTablesRule{
+		{"nat", "POSTROUTING", []string{"-m", "ipvs", "--vaddr", fmt.Sprintf("%s/32", ip.String()), "--vdir", "ORIGINAL", "-j", "MASQUERADE"}},
+	}
+}
+
+// ExternalMasqRules masquerades ipvs connections leaving node through interface,
+// so replies of external upstreams are returned through this node
+func ExternalMasqRules(iface string) []IPTablesRule {
+	return []IPTablesRule{
+		{"nat", "POSTROUTING", []string{"-o", iface, "-m", "ipvs", "--ipvs", "--vdir", "ORIGINAL", "-j", "MASQUERADE"}},
+	}
+}
+
 func ForwardRules(rg string) []IPTablesRule {
 	return []IPTablesRule{
 		// These rules allow traffic to be forwarded if it is to or from the network range.
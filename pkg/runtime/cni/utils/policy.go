@@ -0,0 +1,242 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+const (
+	PolicyChain        = "LB-POLICY"
+	PolicyEgressChain  = "LB-POLICY-EGRESS"
+	PolicyIngressChain = "LB-POLICY-INGRESS"
+
+	// bridge netfilter passes traffic between containers of the same bridge through iptables,
+	// without it traffic of pods on the same node bypasses policies chains
+	bridgeNetfilterSysctl  = "/proc/sys/net/bridge/bridge-nf-call-iptables"
+	bridgeNetfilter6Sysctl = "/proc/sys/net/bridge/bridge-nf-call-ip6tables"
+)
+
+// PolicyRules generates network policies chains rules for IPv4 or IPv6 addresses of pods.
+// Traffic of isolated pods not allowed by rules is dropped,
+// allowed traffic returns to FORWARD chain
+func PolicyRules(rules map[string]*types.NetworkPolicyRules, ipv6 bool) []IPTablesRule {
+
+	var items = []IPTablesRule{
+		// Allow replies for connections allowed before
+		{"filter", PolicyChain, []string{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}},
+		{"filter", PolicyChain, []string{"-j", PolicyEgressChain}},
+		{"filter", PolicyChain, []string{"-j", PolicyIngressChain}},
+	}
+
+	pods := make([]string, 0)
+	for pod := range rules {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	for _, pod := range pods {
+		r := rules[pod]
+
+		ip := r.IP
+		if ipv6 {
+			ip = r.IPv6
+		}
+
+		if ip == types.EmptyString {
+			continue
+		}
+
+		if r.IsolateEgress {
+			items = append(items, policyTrafficRules(PolicyEgressChain, "-s", "-d", ip, policyTraffic(r.Egress, ipv6))...)
+		}
+
+		if r.IsolateIngress {
+			items = append(items, policyTrafficRules(PolicyIngressChain, "-d", "-s", ip, policyTraffic(r.Ingress, ipv6))...)
+		}
+	}
+
+	return items
+}
+
+// policyTraffic filters traffic peers by address family,
+// traffic without peers of family is skipped as it matches nothing
+func policyTraffic(traffic []*types.NetworkPolicyTraffic, ipv6 bool) []*types.NetworkPolicyTraffic {
+
+	var items = make([]*types.NetworkPolicyTraffic, 0)
+
+	for _, t := range traffic {
+
+		if t.Peers == nil {
+			items = append(items, t)
+			continue
+		}
+
+		peers := make([]string, 0)
+		for _, addr := range t.Peers {
+			if policyPeerIPv6(addr) == ipv6 {
+				peers = append(peers, addr)
+			}
+		}
+
+		if len(peers) == 0 {
+			continue
+		}
+
+		items = append(items, &types.NetworkPolicyTraffic{Peers: peers, Ports: t.Ports})
+	}
+
+	return items
+}
+
+func policyPeerIPv6(addr string) bool {
+	if ip, _, err := net.ParseCIDR(addr); err == nil {
+		return ip.To4() == nil
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.To4() == nil
+}
+
+func policyTrafficRules(chain, self, peer, ip string, traffic []*types.NetworkPolicyTraffic) []IPTablesRule {
+
+	var items = make([]IPTablesRule, 0)
+
+	for _, t := range traffic {
+
+		peers := t.Peers
+		if peers == nil {
+			peers = []string{types.EmptyString}
+		}
+
+		ports := t.Ports
+		if len(ports) == 0 {
+			ports = []*types.NetworkPolicyPort{nil}
+		}
+
+		for _, addr := range peers {
+			for _, port := range ports {
+
+				spec := []string{self, ip}
+
+				if addr != types.EmptyString {
+					spec = append(spec, peer, addr)
+				}
+
+				if port != nil {
+					spec = append(spec, "-p", port.GetProtocol(), "--dport", fmt.Sprintf("%d", port.Port))
+				}
+
+				items = append(items, IPTablesRule{"filter", chain, append(spec, "-j", "RETURN")})
+			}
+		}
+	}
+
+	return append(items, IPTablesRule{"filter", chain, []string{self, ip, "-j", "DROP"}})
+}
+
+// PolicyRestore generates iptables-restore input which replaces network policies chains content,
+// declared chains are created or flushed and filled with rules in one transaction
+func PolicyRestore(rules []IPTablesRule) []byte {
+
+	var buf bytes.Buffer
+
+	buf.WriteString("*filter\n")
+	for _, chain := range []string{PolicyChain, PolicyEgressChain, PolicyIngressChain} {
+		fmt.Fprintf(&buf, ":%s - [0:0]\n", chain)
+	}
+
+	for _, r := range rules {
+		fmt.Fprintf(&buf, "-A %s %s\n", r.chain, strings.Join(r.rulespec, " "))
+	}
+
+	buf.WriteString("COMMIT\n")
+	return buf.Bytes()
+}
+
+// SyncPolicy applies pods traffic rules with iptables and ip6tables.
+// Missing ip6tables is an error only if there are pods with IPv6 address
+func SyncPolicy(rules map[string]*types.NetworkPolicyRules) error {
+
+	if len(rules) != 0 {
+		if err := ioutil.WriteFile(bridgeNetfilterSysctl, []byte("1"), 0644); err != nil {
+			return fmt.Errorf("failed to enable bridge netfilter, br_netfilter module should be loaded: %v", err)
+		}
+		// ipv6 bridge netfilter is enabled with the same module
+		ioutil.WriteFile(bridgeNetfilter6Sysctl, []byte("1"), 0644)
+	}
+
+	if err := SyncPolicyIPTables(iptables.ProtocolIPv4, PolicyRules(rules, false)); err != nil {
+		return err
+	}
+
+	if err := SyncPolicyIPTables(iptables.ProtocolIPv6, PolicyRules(rules, true)); err != nil {
+		for _, r := range rules {
+			if r.IPv6 != types.EmptyString {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SyncPolicyIPTables replaces network policies chains content with rules atomically,
+// so isolated pods traffic is never passed while rules are reloaded,
+// and ensures that FORWARD chain passes traffic to policies chain first
+func SyncPolicyIPTables(proto iptables.Protocol, rules []IPTablesRule) error {
+
+	ipt, err := iptables.NewWithProtocol(proto)
+	if err != nil {
+		return fmt.Errorf("iptables binary was not found: %v", err)
+	}
+
+	restore := "iptables-restore"
+	if proto == iptables.ProtocolIPv6 {
+		restore = "ip6tables-restore"
+	}
+
+	// noflush keeps chains which are not declared in input
+	cmd := exec.Command(restore, "--noflush")
+	cmd.Stdin = bytes.NewReader(PolicyRestore(rules))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore policies chains: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	exists, err := ipt.Exists("filter", "FORWARD", "-j", PolicyChain)
+	if err != nil {
+		return fmt.Errorf("failed to check rule existence: %v", err)
+	}
+
+	if !exists {
+		if err := ipt.Insert("filter", "FORWARD", 1, "-j", PolicyChain); err != nil {
+			return fmt.Errorf("failed to insert IPTables rule: %v", err)
+		}
+	}
+
+	return nil
+}
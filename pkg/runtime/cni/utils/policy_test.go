@@ -0,0 +1,135 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyRules(t *testing.T) {
+
+	var rules = map[string]*types.NetworkPolicyRules{
+		"web": {
+			IP:             "10.0.0.2",
+			IsolateIngress: true,
+			Ingress: []*types.NetworkPolicyTraffic{
+				{
+					Peers: []string{"10.0.0.3", "10.0.1.0/24"},
+					Ports: []*types.NetworkPolicyPort{{Port: 80}},
+				},
+			},
+		},
+		"monitor": {
+			IP:            "10.0.1.2",
+			IsolateEgress: true,
+			Egress: []*types.NetworkPolicyTraffic{
+				{Ports: []*types.NetworkPolicyPort{{Port: 53, Protocol: types.NetworkPolicyProtocolUDP}}},
+			},
+		},
+	}
+
+	var expected = []string{
+		"filter LB-POLICY -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+		"filter LB-POLICY -j LB-POLICY-EGRESS",
+		"filter LB-POLICY -j LB-POLICY-INGRESS",
+		"filter LB-POLICY-EGRESS -s 10.0.1.2 -p udp --dport 53 -j RETURN",
+		"filter LB-POLICY-EGRESS -s 10.0.1.2 -j DROP",
+		"filter LB-POLICY-INGRESS -d 10.0.0.2 -s 10.0.0.3 -p tcp --dport 80 -j RETURN",
+		"filter LB-POLICY-INGRESS -d 10.0.0.2 -s 10.0.1.0/24 -p tcp --dport 80 -j RETURN",
+		"filter LB-POLICY-INGRESS -d 10.0.0.2 -j DROP",
+	}
+
+	var got = make([]string, 0)
+	for _, r := range PolicyRules(rules, false) {
+		got = append(got, strings.Join(append([]string{r.table, r.chain}, r.rulespec...), " "))
+	}
+
+	assert.Equal(t, expected, got)
+
+	rules["web"].IPv6 = "fd00::2"
+	rules["web"].Ingress[0].Peers = append(rules["web"].Ingress[0].Peers, "fd00::3", "fd00:1::/64")
+
+	expected = []string{
+		"filter LB-POLICY -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+		"filter LB-POLICY -j LB-POLICY-EGRESS",
+		"filter LB-POLICY -j LB-POLICY-INGRESS",
+		"filter LB-POLICY-INGRESS -d fd00::2 -s fd00::3 -p tcp --dport 80 -j RETURN",
+		"filter LB-POLICY-INGRESS -d fd00::2 -s fd00:1::/64 -p tcp --dport 80 -j RETURN",
+		"filter LB-POLICY-INGRESS -d fd00::2 -j DROP",
+	}
+
+	got = make([]string, 0)
+	for _, r := range PolicyRules(rules, true) {
+		got = append(got, strings.Join(append([]string{r.table, r.chain}, r.rulespec...), " "))
+	}
+
+	assert.Equal(t, expected, got, "ipv6 rules should include only pods and peers with ipv6 addresses")
+}
+
+func TestPolicyRestore(t *testing.T) {
+
+	// pods on the same node share bridge subnet, their traffic is filtered by the same chains
+	var rules = map[string]*types.NetworkPolicyRules{
+		"db": {
+			IP:             "10.0.0.3",
+			IsolateIngress: true,
+			Ingress: []*types.NetworkPolicyTraffic{
+				{
+					Peers: []string{"10.0.0.2"},
+					Ports: []*types.NetworkPolicyPort{{Port: 5432}},
+				},
+			},
+		},
+	}
+
+	var expected = strings.Join([]string{
+		"*filter",
+		":LB-POLICY - [0:0]",
+		":LB-POLICY-EGRESS - [0:0]",
+		":LB-POLICY-INGRESS - [0:0]",
+		"-A LB-POLICY -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+		"-A LB-POLICY -j LB-POLICY-EGRESS",
+		"-A LB-POLICY -j LB-POLICY-INGRESS",
+		"-A LB-POLICY-INGRESS -d 10.0.0.3 -s 10.0.0.2 -p tcp --dport 5432 -j RETURN",
+		"-A LB-POLICY-INGRESS -d 10.0.0.3 -j DROP",
+		"COMMIT",
+		"",
+	}, "\n")
+
+	assert.Equal(t, expected, string(PolicyRestore(PolicyRules(rules, false))),
+		"traffic of same node pod should be dropped unless it is allowed")
+
+	expected = strings.Join([]string{
+		"*filter",
+		":LB-POLICY - [0:0]",
+		":LB-POLICY-EGRESS - [0:0]",
+		":LB-POLICY-INGRESS - [0:0]",
+		"-A LB-POLICY -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+		"-A LB-POLICY -j LB-POLICY-EGRESS",
+		"-A LB-POLICY -j LB-POLICY-INGRESS",
+		"COMMIT",
+		"",
+	}, "\n")
+
+	assert.Equal(t, expected, string(PolicyRestore(PolicyRules(nil, false))), "chains should be flushed without rules")
+}
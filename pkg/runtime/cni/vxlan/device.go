@@ -160,6 +160,51 @@ func (d *Device) SetIP(nt net.IPNet) error {
 	return nil
 }
 
+// SetIPv6 sets IPv6 network address to device as gateway for remote nodes routes
+func (d *Device) SetIPv6(nt net.IPNet) error {
+
+	log.V(logLevel).Debug("Set IPv6 for device")
+
+	ipn := net.IPNet{
+		IP:   nt.IP.Mask(nt.Mask),
+		Mask: net.CIDRMask(128, 128),
+	}
+
+	// address is used as gateway only, duplicate address detection is not needed
+	addr := netlink.Addr{IPNet: &ipn, Flags: syscall.IFA_F_NODAD}
+
+	existingAddrs, err := netlink.AddrList(d.link, netlink.FAMILY_V6)
+	if err != nil {
+		return fmt.Errorf("can not get addr list: %s", err.Error())
+	}
+
+	var exists = false
+	for _, a := range existingAddrs {
+
+		if !a.IP.IsGlobalUnicast() {
+			continue
+		}
+
+		if a.Equal(addr) {
+			exists = true
+			continue
+		}
+
+		if err := netlink.AddrDel(d.link, &a); err != nil {
+			return fmt.Errorf("failed to remove IP address %s from %s: %s", a.IPNet.String(), d.link.Attrs().Name, err)
+		}
+	}
+
+	if !exists {
+		if err := netlink.AddrAdd(d.link, &addr); err != nil {
+			return fmt.Errorf("failed to add IP address %s to %s: %s", ipn.String(), d.link.Attrs().Name, err)
+		}
+	}
+
+	log.V(logLevel).Debugf("Link IPv6 for device: %s", addr.IP)
+	return nil
+}
+
 func (d *Device) SetUp() error {
 	log.V(logLevel).Debug("Set vxlan interface up")
 	if err := netlink.LinkSetUp(d.link); err != nil {
@@ -44,7 +44,9 @@ type Network struct {
 	Device   *Device
 	Network  *net.IPNet
 	CIDR     *net.IPNet
-	IP       net.IP
+	// CIDRv6 is container IPv6 subnet, nil if container network is not dual stack
+	CIDRv6 *net.IPNet
+	IP     net.IP
 }
 
 type NetworkInterface struct {
@@ -147,6 +149,13 @@ func (n *Network) SetSubnetFromDevice(name string) error {
 		Mask: net.CIDRMask(8, 32),
 	}
 
+	// IPv6 subnet is set if docker is started with fixed-cidr-v6
+	n.CIDRv6, err = utils.GetIfaceIP6Net(iface)
+	if err != nil {
+		log.Errorf("Can not locate docker interface ipv6: %s", err.Error())
+		return err
+	}
+
 	return nil
 }
 
@@ -165,7 +174,15 @@ func (n *Network) AddInterface() error {
 		return err
 	}
 
-	return n.Device.SetIP(*n.CIDR)
+	if err := n.Device.SetIP(*n.CIDR); err != nil {
+		return err
+	}
+
+	if n.CIDRv6 == nil {
+		return nil
+	}
+
+	return n.Device.SetIPv6(*n.CIDRv6)
 }
 
 func (n *Network) Info(ctx context.Context) *types.NetworkState {
@@ -181,6 +198,9 @@ func (n *Network) Info(ctx context.Context) *types.NetworkState {
 	}
 	state.Addr = n.ExtIface.IfaceAddr.String()
 	state.IP = n.IntIface.IfaceAddr.String()
+	if n.CIDRv6 != nil {
+		state.CIDRv6 = n.CIDRv6.String()
+	}
 	return &state
 }
 
@@ -255,10 +275,16 @@ func (n *Network) Create(ctx context.Context, network *types.SubnetManifest) (*t
 		return nil, err
 	}
 
+	if err := n.addRouteIPv6(network, lladdr); err != nil {
+		log.Errorf("Add xvlan ipv6 route err: %s", err.Error())
+		return nil, err
+	}
+
 	state := types.NetworkState{}
 
 	state.Type = NetworkType
 	state.CIDR = n.CIDR.String()
+	state.CIDRv6 = network.CIDRv6
 	state.IFace = types.NetworkInterface{
 		Index: n.Device.GetIndex(),
 		Name:  n.Device.GetName(),
@@ -270,6 +296,45 @@ func (n *Network) Create(ctx context.Context, network *types.SubnetManifest) (*t
 	return &state, nil
 }
 
+// addRouteIPv6 routes remote node IPv6 subnet if both nodes have dual stack network,
+// remote subnet network address is used as gateway the same way as for IPv4
+func (n *Network) addRouteIPv6(network *types.SubnetManifest, lladdr net.HardwareAddr) error {
+
+	if n.CIDRv6 == nil || network.CIDRv6 == types.EmptyString {
+		return nil
+	}
+
+	_, ipn, err := net.ParseCIDR(network.CIDRv6)
+	if err != nil {
+		log.Errorf("Can-not parse subnet %v: %s", network.CIDRv6, err.Error())
+		return err
+	}
+
+	log.V(logLevel).Debugf("Add new NDP record to %v :> %v", lladdr, ipn.IP)
+	if err := n.Device.AddARP(lladdr, ipn.IP); err != nil {
+		log.Errorf("Can not add NDP record: %s", err.Error())
+		return err
+	}
+
+	route := netlink.Route{
+		LinkIndex: n.Device.link.Attrs().Index,
+		Scope:     netlink.SCOPE_UNIVERSE,
+		Dst:       ipn,
+		Gw:        ipn.IP,
+	}
+	route.SetFlag(syscall.RTNH_F_ONLINK)
+
+	log.V(logLevel).Debugf("Add new route record for %v :> %v", network.CIDRv6, ipn.IP)
+	if err := netlink.RouteReplace(&route); err != nil {
+		if err := n.Device.DelARP(lladdr, ipn.IP); err != nil {
+			log.Errorf("Can not del NDP record: %s", err.Error())
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (n *Network) Replace(ctx context.Context, state *types.NetworkState, manifest *types.SubnetManifest) (*types.NetworkState, error) {
 
 	if state != nil {
@@ -342,9 +407,57 @@ func (n *Network) Subnets(ctx context.Context) (map[string]*types.NetworkState,
 		subnets[r.Dst.String()] = &sn
 	}
 
+	if err := n.subnetsIPv6(subnets); err != nil {
+		return subnets, err
+	}
+
 	for r, sn := range subnets {
 		log.V(logLevel).Debugf("SubnetSpec [%s]: %v", r, sn)
 	}
 
 	return subnets, nil
 }
+
+// subnetsIPv6 restores IPv6 subnets of remote nodes,
+// IPv6 route is matched with IPv4 subnet by gateway hardware address
+func (n *Network) subnetsIPv6(subnets map[string]*types.NetworkState) error {
+
+	var neighs = make(map[string]string)
+
+	ndps, err := netlink.NeighList(n.Device.link.Index, netlink.FAMILY_V6)
+	if err != nil {
+		log.Errorf("Can not get ndp records: %s", err.Error())
+		return err
+	}
+
+	for _, ndp := range ndps {
+		neighs[ndp.IP.String()] = ndp.HardwareAddr.String()
+	}
+
+	routes, err := netlink.RouteList(n.Device.link, netlink.FAMILY_V6)
+	if err != nil {
+		log.Errorf("Can not get routes: %s", err.Error())
+		return err
+	}
+
+	for _, r := range routes {
+
+		if r.Dst == nil || r.Gw == nil {
+			continue
+		}
+
+		for _, sn := range subnets {
+			if sn.IFace.HAddr != types.EmptyString && sn.IFace.HAddr == neighs[r.Gw.String()] {
+				sn.CIDRv6 = r.Dst.String()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Policy enforces network policies rules with iptables
+func (n *Network) Policy(ctx context.Context, rules map[string]*types.NetworkPolicyRules) error {
+	log.V(logLevel).Debugf("Sync network policies rules for %d pods", len(rules))
+	return utils.SyncPolicy(rules)
+}
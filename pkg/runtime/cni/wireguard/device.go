@@ -0,0 +1,208 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/vishvananda/netlink"
+)
+
+const logLevel = 3
+
+const DeviceDefaultName = "lb.wg"
+const DeviceDefaultPort = 51820
+const DeviceLinkType = "wireguard"
+
+// DeviceKeepAlive keeps NAT mappings to peers open
+const DeviceKeepAlive = 25 * time.Second
+
+type Device struct {
+	link netlink.Link
+	port int
+}
+
+type DeviceCreateOpts struct {
+	name string
+	port int
+	key  Key
+}
+
+func NewDevice(opts DeviceCreateOpts) (*Device, error) {
+
+	d := new(Device)
+	d.port = opts.port
+	d.link = &netlink.GenericLink{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: opts.name,
+		},
+		LinkType: DeviceLinkType,
+	}
+
+	if err := d.Create(); err != nil {
+		return d, err
+	}
+
+	if err := d.SetKey(opts.key); err != nil {
+		return d, err
+	}
+
+	if err := d.SetUp(); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+func (d *Device) Create() error {
+
+	log.V(logLevel).Debug("Create new wireguard interface")
+
+	err := netlink.LinkAdd(d.link)
+	if err != nil && err != syscall.EEXIST {
+		return fmt.Errorf("can not create wireguard device %s: %v", d.link.Attrs().Name, err)
+	}
+
+	if err == syscall.EEXIST {
+		log.V(logLevel).Debugf("Device already exists: %s", d.link.Attrs().Name)
+	}
+
+	link, err := netlink.LinkByName(d.link.Attrs().Name)
+	if err != nil {
+		return fmt.Errorf("can't locate created wireguard device %s: %v", d.link.Attrs().Name, err)
+	}
+
+	if link.Type() != DeviceLinkType {
+		return fmt.Errorf("device %s is not wireguard device: %s", d.link.Attrs().Name, link.Type())
+	}
+
+	d.link = link
+	return nil
+}
+
+// SetKey sets device private key and listen port
+func (d *Device) SetKey(key Key) error {
+	log.V(logLevel).Debug("Set wireguard device private key")
+	return ConfigureDevice(d.GetName(), DeviceConfig{PrivateKey: &key, ListenPort: &d.port})
+}
+
+func (d *Device) SetIP(ip net.IP) error {
+
+	log.V(logLevel).Debugf("Set IP for device: %s", ip.String())
+
+	addr := netlink.Addr{IPNet: &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}}
+
+	existingAddrs, err := netlink.AddrList(d.link, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("can not get addr list: %v", err)
+	}
+
+	for _, a := range existingAddrs {
+		if a.Equal(addr) {
+			return nil
+		}
+		if err := netlink.AddrDel(d.link, &a); err != nil {
+			return fmt.Errorf("failed to remove IP address %s from %s: %v", a.IPNet.String(), d.GetName(), err)
+		}
+	}
+
+	if err := netlink.AddrAdd(d.link, &addr); err != nil {
+		return fmt.Errorf("failed to add IP address %s to %s: %v", ip.String(), d.GetName(), err)
+	}
+
+	return nil
+}
+
+func (d *Device) SetUp() error {
+	log.V(logLevel).Debug("Set wireguard interface up")
+	if err := netlink.LinkSetUp(d.link); err != nil {
+		return fmt.Errorf("failed to set interface %s to UP state: %v", d.GetName(), err)
+	}
+
+	return nil
+}
+
+// SetPeer adds peer or replaces peer endpoint and allowed ips
+func (d *Device) SetPeer(key Key, endpoint *net.UDPAddr, allowed ...*net.IPNet) error {
+
+	ips := make([]net.IPNet, 0)
+	for _, ipn := range allowed {
+		ips = append(ips, *ipn)
+	}
+
+	log.V(logLevel).Debugf("Set wireguard peer %s: %v > %s", key.String(), ips, endpoint.String())
+	return ConfigureDevice(d.GetName(), DeviceConfig{
+		Peers: []Peer{{
+			PublicKey:  key,
+			Endpoint:   endpoint,
+			AllowedIPs: ips,
+			KeepAlive:  DeviceKeepAlive,
+		}},
+	})
+}
+
+func (d *Device) DelPeer(key Key) error {
+	log.V(logLevel).Debugf("Del wireguard peer %s", key.String())
+	return ConfigureDevice(d.GetName(), DeviceConfig{
+		Peers: []Peer{{PublicKey: key, Remove: true}},
+	})
+}
+
+func (d *Device) Peers() ([]Peer, error) {
+	info, err := GetDevice(d.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return info.Peers, nil
+}
+
+// AddRoute routes subnet traffic through device
+func (d *Device) AddRoute(ipn *net.IPNet) error {
+	log.V(logLevel).Debugf("Add wireguard route: %s", ipn.String())
+	return netlink.RouteReplace(&netlink.Route{
+		LinkIndex: d.GetIndex(),
+		Scope:     netlink.SCOPE_LINK,
+		Dst:       ipn,
+	})
+}
+
+func (d *Device) DelRoute(ipn *net.IPNet) error {
+	log.V(logLevel).Debugf("Del wireguard route: %s", ipn.String())
+	err := netlink.RouteDel(&netlink.Route{
+		LinkIndex: d.GetIndex(),
+		Scope:     netlink.SCOPE_LINK,
+		Dst:       ipn,
+	})
+	if err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+func (d *Device) GetName() string {
+	return d.link.Attrs().Name
+}
+
+func (d *Device) GetIndex() int {
+	return d.link.Attrs().Index
+}
@@ -0,0 +1,124 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+const KeyLen = 32
+
+// Key is a curve25519 key used by WireGuard
+type Key [KeyLen]byte
+
+// GenerateKey generates new private key
+func GenerateKey() (Key, error) {
+
+	var k Key
+
+	if _, err := rand.Read(k[:]); err != nil {
+		return k, fmt.Errorf("can not generate key: %v", err)
+	}
+
+	// clamp key as described in curve25519 paper
+	k[0] &= 248
+	k[31] = (k[31] & 127) | 64
+
+	return k, nil
+}
+
+// ParseKey parses base64 encoded key
+func ParseKey(s string) (Key, error) {
+
+	var k Key
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return k, fmt.Errorf("invalid key: %v", err)
+	}
+
+	if len(b) != KeyLen {
+		return k, fmt.Errorf("invalid key length: %d", len(b))
+	}
+
+	copy(k[:], b)
+	return k, nil
+}
+
+// PublicKey returns public key for private key
+func (k Key) PublicKey() Key {
+	var (
+		pub  [KeyLen]byte
+		priv = [KeyLen]byte(k)
+	)
+	curve25519.ScalarBaseMult(&pub, &priv)
+	return Key(pub)
+}
+
+func (k Key) IsZero() bool {
+	var z Key
+	return k == z
+}
+
+func (k Key) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// LoadKey reads private key from file, new key is generated and saved if file does not exist
+func LoadKey(path string) (Key, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		return ParseKey(string(data))
+	}
+
+	if !os.IsNotExist(err) {
+		return Key{}, fmt.Errorf("can not read key file: %v", err)
+	}
+
+	k, err := GenerateKey()
+	if err != nil {
+		return k, err
+	}
+
+	return k, SaveKey(path, k)
+}
+
+// SaveKey writes private key to file readable only by owner
+func SaveKey(path string, k Key) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("can not create key directory: %v", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(k.String()), 0600); err != nil {
+		return fmt.Errorf("can not write key file: %v", err)
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,84 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPublicKey(t *testing.T) {
+
+	// RFC 7748 section 6.1 test vector
+	priv, err := ParseKey("dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo=")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=", priv.PublicKey().String())
+}
+
+func TestParseKey(t *testing.T) {
+
+	k, err := GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, k.IsZero(), "key should not be empty")
+
+	p, err := ParseKey(k.String())
+	assert.NoError(t, err)
+	assert.Equal(t, k, p, "parsed key different")
+
+	_, err = ParseKey("invalid")
+	assert.Error(t, err)
+
+	_, err = ParseKey("dGVzdA==")
+	assert.Error(t, err, "short key should be rejected")
+}
+
+func TestLoadKey(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "wireguard")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "network", "wireguard.key")
+
+	k1, err := LoadKey(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	st, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), st.Mode().Perm(), "key file should be readable only by owner")
+	}
+
+	k2, err := LoadKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, k1, k2, "stored key should be loaded")
+}
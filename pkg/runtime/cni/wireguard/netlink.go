@@ -0,0 +1,478 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// WireGuard generic netlink interface, see include/uapi/linux/wireguard.h
+const (
+	genlName    = "wireguard"
+	genlVersion = 1
+)
+
+const (
+	cmdGetDevice uint8 = iota
+	cmdSetDevice
+)
+
+const (
+	deviceAttrUnspec = iota
+	deviceAttrIfIndex
+	deviceAttrIfName
+	deviceAttrPrivateKey
+	deviceAttrPublicKey
+	deviceAttrFlags
+	deviceAttrListenPort
+	deviceAttrFwMark
+	deviceAttrPeers
+)
+
+const (
+	peerAttrUnspec = iota
+	peerAttrPublicKey
+	peerAttrPresharedKey
+	peerAttrFlags
+	peerAttrEndpoint
+	peerAttrKeepAlive
+	peerAttrLastHandshake
+	peerAttrRxBytes
+	peerAttrTxBytes
+	peerAttrAllowedIPs
+	peerAttrProtocolVersion
+)
+
+const (
+	allowedIPAttrUnspec = iota
+	allowedIPAttrFamily
+	allowedIPAttrAddr
+	allowedIPAttrCIDR
+)
+
+const (
+	deviceFlagReplacePeers = 1 << 0
+
+	peerFlagRemove            = 1 << 0
+	peerFlagReplaceAllowedIPs = 1 << 1
+)
+
+// peersPerMessage limits peers count in one netlink message to stay under message size limit
+const peersPerMessage = 16
+
+// Peer describes WireGuard device peer
+type Peer struct {
+	PublicKey  Key
+	Endpoint   *net.UDPAddr
+	AllowedIPs []net.IPNet
+	KeepAlive  time.Duration
+	// LastHandshake is time of last completed handshake with peer, zero if there was no handshake
+	LastHandshake time.Time
+	// Remove peer from device
+	Remove bool
+}
+
+// DeviceConfig describes WireGuard device changes,
+// nil and zero values are not changed on device
+type DeviceConfig struct {
+	PrivateKey   *Key
+	ListenPort   *int
+	ReplacePeers bool
+	Peers        []Peer
+}
+
+// DeviceInfo describes current WireGuard device state
+type DeviceInfo struct {
+	Index      int
+	Name       string
+	PrivateKey Key
+	PublicKey  Key
+	ListenPort int
+	Peers      []Peer
+}
+
+var familyID uint16
+
+func getFamilyID() (uint16, error) {
+
+	if familyID != 0 {
+		return familyID, nil
+	}
+
+	fam, err := netlink.GenlFamilyGet(genlName)
+	if err != nil {
+		return 0, fmt.Errorf("wireguard netlink family not found: %v", err)
+	}
+
+	familyID = fam.ID
+	return familyID, nil
+}
+
+// ConfigureDevice applies configuration to WireGuard device
+func ConfigureDevice(name string, cfg DeviceConfig) error {
+
+	fid, err := getFamilyID()
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range encodeDeviceConfig(name, cfg) {
+
+		req := nl.NewNetlinkRequest(int(fid), unix.NLM_F_ACK)
+		req.AddRawData(msg)
+
+		if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+			return fmt.Errorf("can not configure wireguard device %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetDevice returns WireGuard device state
+func GetDevice(name string) (*DeviceInfo, error) {
+
+	fid, err := getFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	req := nl.NewNetlinkRequest(int(fid), unix.NLM_F_DUMP)
+	req.AddRawData(encodeDeviceGet(name))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("can not get wireguard device %s: %v", name, err)
+	}
+
+	return decodeDevice(msgs)
+}
+
+func genlHeader(cmd uint8) []byte {
+	return []byte{cmd, genlVersion, 0, 0}
+}
+
+func encodeDeviceGet(name string) []byte {
+	msg := genlHeader(cmdGetDevice)
+	return append(msg, nl.NewRtAttr(deviceAttrIfName, nl.ZeroTerminated(name)).Serialize()...)
+}
+
+// encodeDeviceConfig splits configuration into messages with limited peers count
+func encodeDeviceConfig(name string, cfg DeviceConfig) [][]byte {
+
+	var msgs = make([][]byte, 0)
+
+	for i := 0; i == 0 || i < len(cfg.Peers); i += peersPerMessage {
+
+		msg := genlHeader(cmdSetDevice)
+		msg = append(msg, nl.NewRtAttr(deviceAttrIfName, nl.ZeroTerminated(name)).Serialize()...)
+
+		// device options are set only with first message
+		if i == 0 {
+
+			if cfg.PrivateKey != nil {
+				msg = append(msg, nl.NewRtAttr(deviceAttrPrivateKey, cfg.PrivateKey[:]).Serialize()...)
+			}
+
+			if cfg.ListenPort != nil {
+				msg = append(msg, nl.NewRtAttr(deviceAttrListenPort, nl.Uint16Attr(uint16(*cfg.ListenPort))).Serialize()...)
+			}
+
+			if cfg.ReplacePeers {
+				msg = append(msg, nl.NewRtAttr(deviceAttrFlags, nl.Uint32Attr(deviceFlagReplacePeers)).Serialize()...)
+			}
+		}
+
+		end := i + peersPerMessage
+		if end > len(cfg.Peers) {
+			end = len(cfg.Peers)
+		}
+
+		if end > i {
+			peers := nl.NewRtAttr(deviceAttrPeers|unix.NLA_F_NESTED, nil)
+			for j, p := range cfg.Peers[i:end] {
+				peers.AddChild(encodePeer(j, p))
+			}
+			msg = append(msg, peers.Serialize()...)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs
+}
+
+func encodePeer(index int, p Peer) *nl.RtAttr {
+
+	attr := nl.NewRtAttr(index|unix.NLA_F_NESTED, nil)
+	nl.NewRtAttrChild(attr, peerAttrPublicKey, p.PublicKey[:])
+
+	if p.Remove {
+		nl.NewRtAttrChild(attr, peerAttrFlags, nl.Uint32Attr(peerFlagRemove))
+		return attr
+	}
+
+	nl.NewRtAttrChild(attr, peerAttrFlags, nl.Uint32Attr(peerFlagReplaceAllowedIPs))
+
+	if p.Endpoint != nil {
+		nl.NewRtAttrChild(attr, peerAttrEndpoint, encodeSockaddr(p.Endpoint))
+	}
+
+	if p.KeepAlive > 0 {
+		nl.NewRtAttrChild(attr, peerAttrKeepAlive, nl.Uint16Attr(uint16(p.KeepAlive/time.Second)))
+	}
+
+	ips := nl.NewRtAttrChild(attr, peerAttrAllowedIPs|unix.NLA_F_NESTED, nil)
+	for i, ipn := range p.AllowedIPs {
+
+		ip := nl.NewRtAttrChild(ips, i|unix.NLA_F_NESTED, nil)
+		ones, _ := ipn.Mask.Size()
+
+		if ip4 := ipn.IP.To4(); ip4 != nil {
+			nl.NewRtAttrChild(ip, allowedIPAttrFamily, nl.Uint16Attr(unix.AF_INET))
+			nl.NewRtAttrChild(ip, allowedIPAttrAddr, []byte(ip4))
+		} else {
+			nl.NewRtAttrChild(ip, allowedIPAttrFamily, nl.Uint16Attr(unix.AF_INET6))
+			nl.NewRtAttrChild(ip, allowedIPAttrAddr, []byte(ipn.IP.To16()))
+		}
+
+		nl.NewRtAttrChild(ip, allowedIPAttrCIDR, nl.Uint8Attr(uint8(ones)))
+	}
+
+	return attr
+}
+
+// encodeSockaddr encodes address as sockaddr_in or sockaddr_in6 structure
+func encodeSockaddr(addr *net.UDPAddr) []byte {
+
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		b := make([]byte, unix.SizeofSockaddrInet4)
+		nl.NativeEndian().PutUint16(b[0:2], unix.AF_INET)
+		binary.BigEndian.PutUint16(b[2:4], uint16(addr.Port))
+		copy(b[4:8], ip4)
+		return b
+	}
+
+	b := make([]byte, unix.SizeofSockaddrInet6)
+	nl.NativeEndian().PutUint16(b[0:2], unix.AF_INET6)
+	binary.BigEndian.PutUint16(b[2:4], uint16(addr.Port))
+	copy(b[8:24], addr.IP.To16())
+	return b
+}
+
+func decodeSockaddr(b []byte) *net.UDPAddr {
+
+	if len(b) < 2 {
+		return nil
+	}
+
+	switch nl.NativeEndian().Uint16(b[0:2]) {
+	case unix.AF_INET:
+		if len(b) < 8 {
+			return nil
+		}
+		return &net.UDPAddr{
+			IP:   net.IPv4(b[4], b[5], b[6], b[7]),
+			Port: int(binary.BigEndian.Uint16(b[2:4])),
+		}
+	case unix.AF_INET6:
+		if len(b) < 24 {
+			return nil
+		}
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, b[8:24])
+		return &net.UDPAddr{
+			IP:   ip,
+			Port: int(binary.BigEndian.Uint16(b[2:4])),
+		}
+	}
+
+	return nil
+}
+
+// decodeTimespec decodes __kernel_timespec structure, zero timespec is decoded as zero time
+func decodeTimespec(b []byte) time.Time {
+
+	if len(b) < 16 {
+		return time.Time{}
+	}
+
+	sec := int64(nl.NativeEndian().Uint64(b[0:8]))
+	nsec := int64(nl.NativeEndian().Uint64(b[8:16]))
+
+	if sec == 0 && nsec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, nsec)
+}
+
+// decodeDevice merges dump messages into device state,
+// peers of large devices are split by kernel into several messages
+func decodeDevice(msgs [][]byte) (*DeviceInfo, error) {
+
+	var info = new(DeviceInfo)
+
+	for _, msg := range msgs {
+
+		if len(msg) < nl.SizeofGenlmsg {
+			return nil, fmt.Errorf("invalid wireguard netlink message")
+		}
+
+		attrs, err := nl.ParseRouteAttr(msg[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range attrs {
+			switch a.Attr.Type &^ unix.NLA_F_NESTED {
+			case deviceAttrIfIndex:
+				info.Index = int(nl.NativeEndian().Uint32(a.Value))
+			case deviceAttrIfName:
+				info.Name = nl.BytesToString(a.Value)
+			case deviceAttrPrivateKey:
+				copy(info.PrivateKey[:], a.Value)
+			case deviceAttrPublicKey:
+				copy(info.PublicKey[:], a.Value)
+			case deviceAttrListenPort:
+				info.ListenPort = int(nl.NativeEndian().Uint16(a.Value))
+			case deviceAttrPeers:
+				peers, err := decodePeers(a.Value)
+				if err != nil {
+					return nil, err
+				}
+				info.Peers = mergePeers(info.Peers, peers)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func decodePeers(b []byte) ([]Peer, error) {
+
+	var peers = make([]Peer, 0)
+
+	items, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+
+		attrs, err := nl.ParseRouteAttr(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		var p = Peer{}
+
+		for _, a := range attrs {
+			switch a.Attr.Type &^ unix.NLA_F_NESTED {
+			case peerAttrPublicKey:
+				copy(p.PublicKey[:], a.Value)
+			case peerAttrEndpoint:
+				p.Endpoint = decodeSockaddr(a.Value)
+			case peerAttrKeepAlive:
+				p.KeepAlive = time.Duration(nl.NativeEndian().Uint16(a.Value)) * time.Second
+			case peerAttrLastHandshake:
+				p.LastHandshake = decodeTimespec(a.Value)
+			case peerAttrAllowedIPs:
+				ips, err := decodeAllowedIPs(a.Value)
+				if err != nil {
+					return nil, err
+				}
+				p.AllowedIPs = ips
+			}
+		}
+
+		peers = append(peers, p)
+	}
+
+	return peers, nil
+}
+
+func decodeAllowedIPs(b []byte) ([]net.IPNet, error) {
+
+	var ips = make([]net.IPNet, 0)
+
+	items, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+
+		attrs, err := nl.ParseRouteAttr(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			family uint16
+			ip     net.IP
+			ones   int
+		)
+
+		for _, a := range attrs {
+			switch a.Attr.Type &^ unix.NLA_F_NESTED {
+			case allowedIPAttrFamily:
+				family = nl.NativeEndian().Uint16(a.Value)
+			case allowedIPAttrAddr:
+				ip = make(net.IP, len(a.Value))
+				copy(ip, a.Value)
+			case allowedIPAttrCIDR:
+				ones = int(a.Value[0])
+			}
+		}
+
+		bits := 8 * net.IPv6len
+		if family == syscall.AF_INET {
+			bits = 8 * net.IPv4len
+		}
+
+		ips = append(ips, net.IPNet{IP: ip, Mask: net.CIDRMask(ones, bits)})
+	}
+
+	return ips, nil
+}
+
+// mergePeers joins peer allowed ips split between dump messages
+func mergePeers(peers, items []Peer) []Peer {
+
+	for _, item := range items {
+
+		if l := len(peers); l > 0 && peers[l-1].PublicKey == item.PublicKey {
+			peers[l-1].AllowedIPs = append(peers[l-1].AllowedIPs, item.AllowedIPs...)
+			continue
+		}
+
+		peers = append(peers, item)
+	}
+
+	return peers
+}
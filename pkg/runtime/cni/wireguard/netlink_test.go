@@ -0,0 +1,224 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+)
+
+func TestEncodeDeviceConfig(t *testing.T) {
+
+	priv, _ := GenerateKey()
+	port := DeviceDefaultPort
+
+	_, ipn4, _ := net.ParseCIDR("10.0.1.0/24")
+	_, ipn6, _ := net.ParseCIDR("fd00:1::/64")
+
+	peers := []Peer{
+		{
+			PublicKey:  priv.PublicKey(),
+			Endpoint:   &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: DeviceDefaultPort},
+			AllowedIPs: []net.IPNet{*ipn4, *ipn6},
+			KeepAlive:  DeviceKeepAlive,
+		},
+		{
+			PublicKey:  priv,
+			Endpoint:   &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 51821},
+			AllowedIPs: []net.IPNet{},
+		},
+	}
+
+	msgs := encodeDeviceConfig("lb.wg", DeviceConfig{PrivateKey: &priv, ListenPort: &port, Peers: peers})
+	if !assert.Len(t, msgs, 1) {
+		return
+	}
+
+	// set and get device commands share attributes layout
+	info, err := decodeDevice(msgs)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "lb.wg", info.Name)
+	assert.Equal(t, priv, info.PrivateKey)
+	assert.Equal(t, DeviceDefaultPort, info.ListenPort)
+
+	if !assert.Len(t, info.Peers, 2) {
+		return
+	}
+
+	assert.Equal(t, peers[0].PublicKey, info.Peers[0].PublicKey)
+	assert.Equal(t, "192.168.1.2:51820", info.Peers[0].Endpoint.String())
+	assert.Equal(t, DeviceKeepAlive, info.Peers[0].KeepAlive)
+	if assert.Len(t, info.Peers[0].AllowedIPs, 2) {
+		assert.Equal(t, ipn4.String(), info.Peers[0].AllowedIPs[0].String())
+		assert.Equal(t, ipn6.String(), info.Peers[0].AllowedIPs[1].String())
+	}
+
+	assert.Equal(t, "[2001:db8::2]:51821", info.Peers[1].Endpoint.String())
+	assert.Len(t, info.Peers[1].AllowedIPs, 0)
+}
+
+func TestEncodeDeviceConfigSplit(t *testing.T) {
+
+	priv, _ := GenerateKey()
+
+	var peers = make([]Peer, 0)
+	for i := 0; i < 2*peersPerMessage+1; i++ {
+		k, _ := GenerateKey()
+		peers = append(peers, Peer{PublicKey: k, Remove: true})
+	}
+
+	msgs := encodeDeviceConfig("lb.wg", DeviceConfig{PrivateKey: &priv, ReplacePeers: true, Peers: peers})
+	if !assert.Len(t, msgs, 3) {
+		return
+	}
+
+	first, err := decodeDevice(msgs[:1])
+	if assert.NoError(t, err) {
+		assert.Equal(t, priv, first.PrivateKey)
+		assert.Len(t, first.Peers, peersPerMessage)
+	}
+
+	last, err := decodeDevice(msgs[2:])
+	if assert.NoError(t, err) {
+		assert.True(t, last.PrivateKey.IsZero(), "private key should be set only with first message")
+		assert.Len(t, last.Peers, 1)
+	}
+
+	all, err := decodeDevice(msgs)
+	if assert.NoError(t, err) {
+		assert.Len(t, all.Peers, len(peers))
+	}
+}
+
+// TestDevice runs device management in new network namespace,
+// it requires root privileges and wireguard kernel module
+func TestDevice(t *testing.T) {
+
+	if os.Geteuid() != 0 {
+		t.Skip("test requires root privileges")
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origin, err := netns.Get()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer origin.Close()
+
+	ns, err := netns.New()
+	if err != nil {
+		t.Skipf("can not create network namespace: %s", err.Error())
+	}
+	defer func() {
+		netns.Set(origin)
+		ns.Close()
+	}()
+
+	probe := &netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Name: "wgprobe"}, LinkType: DeviceLinkType}
+	if err := netlink.LinkAdd(probe); err != nil {
+		t.Skipf("wireguard is not supported: %s", err.Error())
+	}
+	netlink.LinkDel(probe)
+
+	priv, _ := GenerateKey()
+	peer, _ := GenerateKey()
+
+	d, err := NewDevice(DeviceCreateOpts{name: DeviceDefaultName, port: DeviceDefaultPort, key: priv})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	info, err := GetDevice(DeviceDefaultName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, priv.PublicKey(), info.PublicKey)
+		assert.Equal(t, DeviceDefaultPort, info.ListenPort)
+	}
+
+	assert.NoError(t, d.SetIP(net.ParseIP("10.0.0.1")))
+
+	_, ipn, _ := net.ParseCIDR("10.0.1.0/24")
+	endpoint := &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: DeviceDefaultPort}
+
+	if !assert.NoError(t, d.SetPeer(peer.PublicKey(), endpoint, ipn)) {
+		return
+	}
+	assert.NoError(t, d.AddRoute(ipn))
+
+	peers, err := d.Peers()
+	if assert.NoError(t, err) && assert.Len(t, peers, 1) {
+		assert.Equal(t, peer.PublicKey(), peers[0].PublicKey)
+		assert.Equal(t, endpoint.String(), peers[0].Endpoint.String())
+		assert.Equal(t, DeviceKeepAlive, peers[0].KeepAlive)
+		if assert.Len(t, peers[0].AllowedIPs, 1) {
+			assert.Equal(t, ipn.String(), peers[0].AllowedIPs[0].String())
+		}
+	}
+
+	routes, err := netlink.RouteList(d.link, netlink.FAMILY_V4)
+	if assert.NoError(t, err) {
+		var found bool
+		for _, r := range routes {
+			if r.Dst != nil && r.Dst.String() == ipn.String() {
+				found = true
+			}
+		}
+		assert.True(t, found, "route to peer subnet not found")
+	}
+
+	// key rotation keeps peers
+	rotated, _ := GenerateKey()
+	assert.NoError(t, d.SetKey(rotated))
+
+	info, err = GetDevice(DeviceDefaultName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, rotated.PublicKey(), info.PublicKey)
+		assert.Len(t, info.Peers, 1)
+	}
+
+	assert.NoError(t, d.DelRoute(ipn))
+	assert.NoError(t, d.DelPeer(peer.PublicKey()))
+
+	peers, err = d.Peers()
+	if assert.NoError(t, err) {
+		assert.Len(t, peers, 0)
+	}
+}
+
+func TestDecodeTimespec(t *testing.T) {
+
+	b := make([]byte, 16)
+	assert.True(t, decodeTimespec(b).IsZero(), "zero timespec")
+
+	nl.NativeEndian().PutUint64(b[0:8], 1500000000)
+	nl.NativeEndian().PutUint64(b[8:16], 500)
+	assert.Equal(t, time.Unix(1500000000, 500), decodeTimespec(b))
+}
@@ -0,0 +1,684 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cni"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cni/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const NetworkType = "wireguard"
+const DefaultContainerDevice = "docker0"
+const DefaultKeyPath = "/var/lib/lastbackend/network/wireguard.key"
+
+// keyPromoteInterval is interval of peers checks for handshake with next key
+const keyPromoteInterval = time.Second
+
+type Network struct {
+	cni.CNI
+
+	lock sync.RWMutex
+
+	ExtIface *NetworkInterface
+	IntIface *NetworkInterface
+	Device   *Device
+	Network  *net.IPNet
+	CIDR     *net.IPNet
+	// CIDRv6 is container IPv6 subnet, nil if container network is not dual stack
+	CIDRv6 *net.IPNet
+
+	key     Key
+	keyPath string
+	// next is private key announced to peers, node switches to it when all peers accept it
+	next Key
+	// pending maps peers next public keys to current ones,
+	// peer traffic is moved to next key after first handshake with it
+	pending map[Key]Key
+}
+
+type NetworkInterface struct {
+	Iface     *net.Interface
+	IfaceAddr net.IP
+}
+
+// New creates WireGuard encrypted overlay network.
+// Node private key is stored in key path and is replaced by new one every rotation interval if it is set
+func New(iface, keyPath string, rotation time.Duration) (*Network, error) {
+
+	var (
+		nt  = new(Network)
+		err error
+	)
+
+	nt.ExtIface = new(NetworkInterface)
+
+	if iface == types.EmptyString {
+		log.Debug("Add network to default interface")
+		if nt.ExtIface.Iface, nt.ExtIface.IfaceAddr, err = utils.GetDefaultInterface(); err != nil {
+			log.Errorf("Can not get default interface: %s", err.Error())
+			return nt, err
+		}
+	} else {
+		log.Debugf("Add network to interface: %s", iface)
+		if nt.ExtIface.Iface, nt.ExtIface.IfaceAddr, err = utils.GetIfaceByName(iface); err != nil {
+			log.Errorf("Can not get interface [%s]: %s", iface, err.Error())
+			return nt, err
+		}
+	}
+
+	if nt.ExtIface.Iface == nil || nt.ExtIface.IfaceAddr == nil {
+		return nt, errors.New(fmt.Sprintf("can not initialize external ineterface for WireGuard: %s", iface))
+	}
+
+	log.Debugf("external interface: %s:%s", nt.ExtIface.Iface.Name, nt.ExtIface.IfaceAddr.String())
+
+	if err := nt.SetSubnetFromDevice(DefaultContainerDevice); err != nil {
+		log.Errorf("Can not set subnet: %s", err.Error())
+		return nil, err
+	}
+
+	nt.keyPath = keyPath
+	if nt.keyPath == types.EmptyString {
+		nt.keyPath = DefaultKeyPath
+	}
+
+	if nt.key, err = LoadKey(nt.keyPath); err != nil {
+		log.Errorf("Can not load key: %s", err.Error())
+		return nil, err
+	}
+
+	// rotation started before restart is continued with stored next key
+	if _, err := os.Stat(nt.nextKeyPath()); err == nil {
+		if nt.next, err = LoadKey(nt.nextKeyPath()); err != nil {
+			log.Errorf("Can not load next key: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	nt.pending = make(map[Key]Key)
+
+	if err := nt.AddInterface(); err != nil {
+		log.Errorf("Can not add interface: %s", err.Error())
+		return nt, err
+	}
+
+	// Add forward rules for network range
+	go utils.SetupAndEnsureIPTables(utils.ForwardRules(nt.Network.String()), 5)
+
+	if rotation > 0 {
+		go nt.rotate(rotation)
+	}
+
+	go nt.promote(keyPromoteInterval)
+
+	return nt, nil
+}
+
+func (n *Network) SetSubnetFromDevice(name string) error {
+
+	iface, _, err := utils.GetIfaceByName(name)
+	if err != nil {
+		log.Errorf("Can not find interface by name %s", name)
+		return err
+	}
+
+	if iface == nil {
+		log.Errorf("Can not find interface by name %s", name)
+		return errors.New("can not find interface")
+	}
+
+	addrs, err := netlink.AddrList(&netlink.Device{
+		LinkAttrs: netlink.LinkAttrs{
+			Index: iface.Index,
+		},
+	}, syscall.AF_INET)
+	if err != nil {
+		log.Errorf("Can not locate docker interface ips: %s", err.Error())
+		return err
+	}
+
+	if len(addrs) == 0 {
+		return errors.New("docker interface has not IP address")
+	}
+
+	n.IntIface = new(NetworkInterface)
+	n.IntIface.Iface = iface
+	n.IntIface.IfaceAddr = addrs[0].IP
+
+	sip := make(net.IP, net.IPv4len)
+	copy(sip, addrs[0].IP.To4())
+	sip[3] = byte(0)
+
+	smk := make(net.IPMask, len(addrs[0].Mask))
+	copy(smk, addrs[0].Mask)
+
+	n.CIDR = &net.IPNet{
+		IP:   sip,
+		Mask: smk,
+	}
+
+	n.Network = &net.IPNet{
+		IP:   sip.Mask(sip.DefaultMask()),
+		Mask: net.CIDRMask(8, 32),
+	}
+
+	// IPv6 subnet is set if docker is started with fixed-cidr-v6
+	n.CIDRv6, err = utils.GetIfaceIP6Net(iface)
+	if err != nil {
+		log.Errorf("Can not locate docker interface ipv6: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (n *Network) AddInterface() error {
+
+	var err error
+
+	if n.Device, err = NewDevice(DeviceCreateOpts{
+		name: DeviceDefaultName,
+		port: DeviceDefaultPort,
+		key:  n.key,
+	}); err != nil {
+		log.Errorf("Can not create wireguard interface: %s", err.Error())
+		return err
+	}
+
+	return n.Device.SetIP(n.IntIface.IfaceAddr)
+}
+
+// PublicKey returns node public key
+func (n *Network) PublicKey() Key {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.key.PublicKey()
+}
+
+// RotateKey starts node key pair rotation.
+// New key is announced as next key first, peers add it alongside current one
+// and node switches to it in CommitKey only when all nodes accept it, so overlay traffic is not dropped
+func (n *Network) RotateKey() error {
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.next.IsZero() {
+		log.V(logLevel).Debug("Wireguard key rotation is in progress")
+		return nil
+	}
+
+	log.V(logLevel).Debug("Rotate wireguard key")
+
+	key, err := GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	if err := SaveKey(n.nextKeyPath(), key); err != nil {
+		return err
+	}
+
+	n.next = key
+
+	log.Infof("Wireguard key rotation started, next public key: %s", key.PublicKey().String())
+	return nil
+}
+
+// CommitKey switches node to next key after it is accepted by all nodes
+func (n *Network) CommitKey(ctx context.Context, key string) error {
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.next.IsZero() || n.next.PublicKey().String() != key {
+		return nil
+	}
+
+	log.V(logLevel).Debug("Commit wireguard key")
+
+	if err := SaveKey(n.keyPath, n.next); err != nil {
+		return err
+	}
+
+	if err := n.Device.SetKey(n.next); err != nil {
+		return err
+	}
+
+	if err := os.Remove(n.nextKeyPath()); err != nil && !os.IsNotExist(err) {
+		log.Errorf("Can not remove next key file: %s", err.Error())
+	}
+
+	n.key = n.next
+	n.next = Key{}
+
+	log.Infof("Wireguard key rotated, new public key: %s", n.key.PublicKey().String())
+	return nil
+}
+
+func (n *Network) nextKeyPath() string {
+	return n.keyPath + ".next"
+}
+
+func (n *Network) rotate(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := n.RotateKey(); err != nil {
+			log.Errorf("Can not rotate wireguard key: %s", err.Error())
+		}
+	}
+}
+
+func (n *Network) Info(ctx context.Context) *types.NetworkState {
+	state := types.NetworkState{}
+
+	state.Type = NetworkType
+	state.CIDR = n.CIDR.String()
+	state.IFace = n.iface()
+	state.Addr = n.ExtIface.IfaceAddr.String()
+	state.IP = n.IntIface.IfaceAddr.String()
+	state.Key = n.PublicKey().String()
+	state.KeyNext = n.nextPublicKey()
+	if n.CIDRv6 != nil {
+		state.CIDRv6 = n.CIDRv6.String()
+	}
+	return &state
+}
+
+func (n *Network) nextPublicKey() string {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if n.next.IsZero() {
+		return types.EmptyString
+	}
+	return n.next.PublicKey().String()
+}
+
+// promote moves peers traffic to their next keys after handshake with next key is completed,
+// handshake means peer switched to next key and current key can not be used anymore
+func (n *Network) promote(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+
+		n.lock.RLock()
+		pending := len(n.pending)
+		n.lock.RUnlock()
+
+		if pending == 0 {
+			continue
+		}
+
+		peers, err := n.Device.Peers()
+		if err != nil {
+			log.Errorf("Can not get wireguard peers: %s", err.Error())
+			continue
+		}
+
+		n.lock.Lock()
+		for next, key := range promotePeers(peers, n.pending) {
+
+			if err := n.Device.SetPeer(next, key.Endpoint, peerAllowedIPs(key)...); err != nil {
+				log.Errorf("Can not set wireguard peer: %s", err.Error())
+				continue
+			}
+
+			if err := n.Device.DelPeer(key.PublicKey); err != nil {
+				log.Errorf("Can not del wireguard peer: %s", err.Error())
+			}
+
+			delete(n.pending, next)
+			log.V(logLevel).Debugf("Wireguard peer %s switched to next key %s", key.PublicKey.String(), next.String())
+		}
+		n.lock.Unlock()
+	}
+}
+
+func (n *Network) Create(ctx context.Context, network *types.SubnetManifest) (*types.NetworkState, error) {
+
+	log.V(logLevel).Debugf("Connect to node to network: %v > %v", network.CIDR, network.Addr)
+
+	if n.CIDR.String() == network.CIDR {
+		log.V(logLevel).Debug("Skip local network provision")
+		return n.Info(ctx), nil
+	}
+
+	if network.Type != NetworkType {
+		return nil, fmt.Errorf("subnet %s uses %s network driver", network.CIDR, network.Type)
+	}
+
+	key, err := ParseKey(network.Key)
+	if err != nil {
+		log.Errorf("Can-not parse subnet %v key: %s", network.CIDR, err.Error())
+		return nil, err
+	}
+
+	_, ipn, err := net.ParseCIDR(network.CIDR)
+	if err != nil {
+		log.Errorf("Can-not parse subnet %v: %s", network.CIDR, err.Error())
+		return nil, err
+	}
+
+	ip := net.ParseIP(network.Addr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid subnet %s address: %s", network.CIDR, network.Addr)
+	}
+
+	var allowed = []*net.IPNet{ipn}
+
+	// IPv6 subnet is routed if both nodes have dual stack network
+	if n.CIDRv6 != nil && network.CIDRv6 != types.EmptyString {
+		_, ipn6, err := net.ParseCIDR(network.CIDRv6)
+		if err != nil {
+			log.Errorf("Can-not parse subnet %v: %s", network.CIDRv6, err.Error())
+			return nil, err
+		}
+		allowed = append(allowed, ipn6)
+	}
+
+	if err := n.Device.SetPeer(key, &net.UDPAddr{IP: ip, Port: DeviceDefaultPort}, allowed...); err != nil {
+		log.Errorf("Can not set wireguard peer: %s", err.Error())
+		return nil, err
+	}
+
+	for _, route := range allowed {
+		if err := n.Device.AddRoute(route); err != nil {
+			log.Errorf("Add wireguard route err: %s", err.Error())
+			log.V(logLevel).Debug("Clean up added before peer")
+
+			if err := n.Device.DelPeer(key); err != nil {
+				log.Errorf("Can not del wireguard peer: %s", err.Error())
+			}
+
+			return nil, err
+		}
+	}
+
+	state := types.NetworkState{}
+
+	state.Type = NetworkType
+	state.CIDR = ipn.String()
+	state.CIDRv6 = network.CIDRv6
+	state.IFace = n.iface()
+	state.Addr = network.Addr
+	state.Key = key.String()
+
+	n.lock.Lock()
+	delete(n.pending, key)
+	n.lock.Unlock()
+
+	// next key is added as peer without allowed ips, peer traffic is moved to it after handshake
+	if network.KeyNext != types.EmptyString {
+
+		next, err := ParseKey(network.KeyNext)
+		if err != nil {
+			log.Errorf("Can-not parse subnet %v next key: %s", network.CIDR, err.Error())
+			return nil, err
+		}
+
+		if err := n.Device.SetPeer(next, &net.UDPAddr{IP: ip, Port: DeviceDefaultPort}); err != nil {
+			log.Errorf("Can not set wireguard peer: %s", err.Error())
+			return nil, err
+		}
+
+		n.lock.Lock()
+		n.pending[next] = key
+		n.lock.Unlock()
+
+		state.KeyNext = next.String()
+	}
+
+	return &state, nil
+}
+
+func (n *Network) Destroy(ctx context.Context, network *types.NetworkState) error {
+
+	if network == nil || network.CIDR == n.CIDR.String() {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("Disconnect node from network: %v > %v", network.CIDR, network.Addr)
+
+	for _, cidr := range []string{network.CIDR, network.CIDRv6} {
+		if _, ipn, err := net.ParseCIDR(cidr); err == nil {
+			if err := n.Device.DelRoute(ipn); err != nil {
+				log.Errorf("Del wireguard route err: %s", err.Error())
+				return err
+			}
+		}
+	}
+
+	for _, k := range []string{network.Key, network.KeyNext} {
+		if key, err := ParseKey(k); err == nil {
+			if err := n.delPeer(key); err != nil {
+				log.Errorf("Can not del wireguard peer: %s", err.Error())
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *Network) delPeer(key Key) error {
+
+	if err := n.Device.DelPeer(key); err != nil {
+		return err
+	}
+
+	n.lock.Lock()
+	delete(n.pending, key)
+	n.lock.Unlock()
+
+	return nil
+}
+
+func (n *Network) Replace(ctx context.Context, state *types.NetworkState, manifest *types.SubnetManifest) (*types.NetworkState, error) {
+
+	// subnet peer is updated in place, so traffic is not dropped while node key is rotated
+	if state != nil && manifest != nil && state.CIDR == manifest.CIDR && state.CIDR != n.CIDR.String() {
+		return n.update(ctx, state, manifest)
+	}
+
+	if state != nil {
+		if err := n.Destroy(ctx, state); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifest == nil {
+		return nil, nil
+	}
+
+	state, err := n.Create(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// update sets manifest peers first, allowed ips are moved to manifest key atomically,
+// and removes peers with keys and route which are not in manifest anymore
+func (n *Network) update(ctx context.Context, state *types.NetworkState, manifest *types.SubnetManifest) (*types.NetworkState, error) {
+
+	st, err := n.Create(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.CIDRv6 != manifest.CIDRv6 {
+		if _, ipn, err := net.ParseCIDR(state.CIDRv6); err == nil {
+			if err := n.Device.DelRoute(ipn); err != nil {
+				log.Errorf("Del wireguard route err: %s", err.Error())
+				return nil, err
+			}
+		}
+	}
+
+	for _, key := range staleKeys(state, manifest) {
+		if err := n.delPeer(key); err != nil {
+			log.Errorf("Can not del wireguard peer: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	return st, nil
+}
+
+func (n *Network) Subnets(ctx context.Context) (map[string]*types.NetworkState, error) {
+
+	log.V(logLevel).Debug("Get current subnets list")
+
+	var subnets = make(map[string]*types.NetworkState)
+
+	peers, err := n.Device.Peers()
+	if err != nil {
+		log.Errorf("Can not get wireguard peers: %s", err.Error())
+		return subnets, err
+	}
+
+	for _, p := range peers {
+
+		if len(p.AllowedIPs) == 0 {
+			continue
+		}
+
+		sn := types.NetworkState{}
+		sn.Type = NetworkType
+		sn.IFace = n.iface()
+		sn.Key = p.PublicKey.String()
+
+		if p.Endpoint != nil {
+			sn.Addr = p.Endpoint.IP.String()
+		}
+
+		for _, ipn := range p.AllowedIPs {
+			switch {
+			case ipn.IP.To4() != nil && sn.CIDR == types.EmptyString:
+				sn.CIDR = ipn.String()
+			case ipn.IP.To4() == nil && sn.CIDRv6 == types.EmptyString:
+				sn.CIDRv6 = ipn.String()
+			}
+		}
+
+		if sn.CIDR == types.EmptyString {
+			continue
+		}
+
+		subnets[sn.CIDR] = &sn
+	}
+
+	for r, sn := range subnets {
+		log.V(logLevel).Debugf("SubnetSpec [%s]: %v", r, sn)
+	}
+
+	return subnets, nil
+}
+
+// Policy enforces network policies rules with iptables
+func (n *Network) Policy(ctx context.Context, rules map[string]*types.NetworkPolicyRules) error {
+	log.V(logLevel).Debugf("Sync network policies rules for %d pods", len(rules))
+	return utils.SyncPolicy(rules)
+}
+
+func (n *Network) iface() types.NetworkInterface {
+	return types.NetworkInterface{
+		Index: n.Device.GetIndex(),
+		Name:  n.Device.GetName(),
+		Addr:  n.IntIface.IfaceAddr.String(),
+	}
+}
+
+// staleKeys returns state peers keys which are not used in manifest
+func staleKeys(state *types.NetworkState, manifest *types.SubnetManifest) []Key {
+
+	var keys = make([]Key, 0)
+
+	for _, k := range []string{state.Key, state.KeyNext} {
+
+		if k == types.EmptyString || k == manifest.Key || k == manifest.KeyNext {
+			continue
+		}
+
+		key, err := ParseKey(k)
+		if err != nil {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// promotePeers returns current peers by next keys for pending peers which completed handshake with next key
+func promotePeers(peers []Peer, pending map[Key]Key) map[Key]Peer {
+
+	var (
+		index   = make(map[Key]Peer)
+		promote = make(map[Key]Peer)
+	)
+
+	for _, p := range peers {
+		index[p.PublicKey] = p
+	}
+
+	for next, key := range pending {
+
+		np, ok := index[next]
+		if !ok || np.LastHandshake.IsZero() {
+			continue
+		}
+
+		if p, ok := index[key]; ok {
+			promote[next] = p
+		}
+	}
+
+	return promote
+}
+
+func peerAllowedIPs(p Peer) []*net.IPNet {
+
+	var ips = make([]*net.IPNet, 0)
+
+	for i := range p.AllowedIPs {
+		ips = append(ips, &p.AllowedIPs[i])
+	}
+
+	return ips
+}
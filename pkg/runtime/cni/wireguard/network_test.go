@@ -0,0 +1,80 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package wireguard
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleKeys(t *testing.T) {
+
+	current, _ := GenerateKey()
+	next, _ := GenerateKey()
+
+	state := new(types.NetworkState)
+	state.Key = current.PublicKey().String()
+	state.KeyNext = next.PublicKey().String()
+
+	// next key is announced, both keys are kept
+	manifest := new(types.SubnetManifest)
+	manifest.Key = current.PublicKey().String()
+	manifest.KeyNext = next.PublicKey().String()
+	assert.Len(t, staleKeys(state, manifest), 0)
+
+	// node switched to next key, current key is removed
+	manifest.Key = next.PublicKey().String()
+	manifest.KeyNext = types.EmptyString
+	assert.Equal(t, []Key{current.PublicKey()}, staleKeys(state, manifest))
+}
+
+func TestPromotePeers(t *testing.T) {
+
+	current, _ := GenerateKey()
+	next, _ := GenerateKey()
+	other, _ := GenerateKey()
+
+	_, ipn, _ := net.ParseCIDR("10.0.1.0/24")
+	endpoint := &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: DeviceDefaultPort}
+
+	peers := []Peer{
+		{PublicKey: current.PublicKey(), Endpoint: endpoint, AllowedIPs: []net.IPNet{*ipn}, LastHandshake: time.Now()},
+		{PublicKey: next.PublicKey(), Endpoint: endpoint},
+	}
+
+	pending := map[Key]Key{
+		next.PublicKey():  current.PublicKey(),
+		other.PublicKey(): current.PublicKey(),
+	}
+
+	assert.Len(t, promotePeers(peers, pending), 0, "peer without handshake with next key is not promoted")
+
+	peers[1].LastHandshake = time.Now()
+
+	promote := promotePeers(peers, pending)
+	if assert.Len(t, promote, 1) {
+		p := promote[next.PublicKey()]
+		assert.Equal(t, current.PublicKey(), p.PublicKey)
+		assert.Equal(t, []*net.IPNet{ipn}, peerAllowedIPs(p))
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
+	"io/ioutil"
 	"net"
 	"os/exec"
 	"strings"
@@ -79,7 +80,7 @@ func (p *Proxy) Create(ctx context.Context, manifest *types.EndpointManifest) (*
 		csvcs = make([]*Service, 0)
 	)
 
-	svcs, err := specToServices(manifest)
+	svcs, err := specToServices(manifest, p.dest.external)
 	if err != nil {
 		log.Errorf("%s can not get services from manifest: %s", logIPVSPrefix, err.Error())
 		return nil, err
@@ -96,9 +97,10 @@ func (p *Proxy) Create(ctx context.Context, manifest *types.EndpointManifest) (*
 
 	for _, svc := range svcs {
 
+		// dual stack endpoint may have no upstreams of one address family
 		if len(svc.dest) == 0 {
-			log.V(logLevel).Debugf("%s skip creating service, destinations not exists", logIPVSPrefix)
-			return nil, nil
+			log.V(logLevel).Debugf("%s skip creating service %s, destinations not exists", logIPVSPrefix, svc.srvc.Address.String())
+			continue
 		}
 
 		log.V(logLevel).Debugf("%s create new service: %s", logIPVSPrefix, svc.srvc.Address.String())
@@ -118,21 +120,15 @@ func (p *Proxy) Create(ctx context.Context, manifest *types.EndpointManifest) (*
 		csvcs = append(csvcs, svc)
 	}
 
-	log.Debugf("%s check ip %s is binded to link %s", logIPVSPrefix, manifest.IP, p.link.Attrs().Name)
-
-	var dest net.IP
-
-	if manifest.External {
-		dest = p.dest.external
-	} else {
-		dest = p.dest.internal
+	if len(csvcs) == 0 {
+		log.V(logLevel).Debugf("%s skip creating services, destinations not exists", logIPVSPrefix)
+		return nil, nil
 	}
 
-	if err := p.addIpBindToLink(manifest.IP, dest); err != nil {
-		log.Warnf("%s failed bind ip to link err: %s", logIPVSPrefix, err.Error())
-	}
+	log.Debugf("%s check ip %s is binded to link %s", logIPVSPrefix, manifest.IP, p.link.Attrs().Name)
+	p.bindEndpoint(&manifest.EndpointSpec)
 
-	state, err := p.getStateByIP(ctx, manifest.IP)
+	state, err := p.getStateByIP(ctx, manifest.IP, manifest.IPv6, manifest.NodePorts)
 	if err != nil {
 		log.Errorf("%s get state by ip err: %s", logIPVSPrefix, err.Error())
 		return nil, err
@@ -156,7 +152,7 @@ func (p *Proxy) Destroy(ctx context.Context, state *types.EndpointState) error {
 	mf.EndpointSpec = state.EndpointSpec
 	mf.Upstreams = state.Upstreams
 
-	svcs, err := specToServices(&mf)
+	svcs, err := specToServices(&mf, p.dest.external)
 	if err != nil {
 		return err
 	}
@@ -166,6 +162,11 @@ func (p *Proxy) Destroy(ctx context.Context, state *types.EndpointState) error {
 			log.Errorf("%s can not delete service: %s", logIPVSPrefix, err.Error())
 		}
 
+		// node address is not bound to ipvs link
+		if svc.srvc.Address.Equal(p.dest.external) {
+			continue
+		}
+
 		if err := p.delIpBindToLink(svc.srvc.Address.String()); err != nil {
 			log.Errorf("%s can not unbind ip from link: %s", logIPVSPrefix, err.Error())
 		}
@@ -177,7 +178,7 @@ func (p *Proxy) Destroy(ctx context.Context, state *types.EndpointState) error {
 // Update proxy rules
 func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *types.EndpointManifest) (*types.EndpointState, error) {
 
-	psvc, err := specToServices(spec)
+	psvc, err := specToServices(spec, p.dest.external)
 	if err != nil {
 		log.Errorf("%s can not convert spec to services: %s", logIPVSPrefix, err.Error())
 		return state, err
@@ -187,7 +188,7 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 	mf.EndpointSpec = state.EndpointSpec
 	mf.Upstreams = state.Upstreams
 
-	csvc, err := specToServices(&mf)
+	csvc, err := specToServices(&mf, p.dest.external)
 	if err != nil {
 		log.Errorf("%s can not convert state to services: %s", logIPVSPrefix, err.Error())
 		return state, err
@@ -215,14 +216,32 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 				log.Errorf("%s can not create service: %s", logIPVSPrefix, err.Error())
 			}
 		} else {
+			// apply scheduler and session affinity changes
+			if !serviceSchedEqual(csvc[id].srvc, svc.srvc) {
+				log.Debugf("%s update service %s scheduler: %s", logIPVSPrefix, id, svc.srvc.SchedName)
+				if err := p.ipvs.UpdateService(svc.srvc); err != nil {
+					log.Errorf("%s can not update service: %s", logIPVSPrefix, err.Error())
+				}
+			}
+
 			// check service upstreams for removing
 			for did, dest := range csvc[id].dest {
 				log.Debugf("%s check service %s old backend exists %s", logIPVSPrefix, id, did)
-				if _, ok := svc.dest[did]; !ok {
+				nd, ok := svc.dest[did]
+				if !ok {
 					log.Debugf("%s service %s backend delete %s", logIPVSPrefix, id, did)
 					if err := p.ipvs.DelDestination(svc.srvc, dest); err != nil {
 						log.Errorf("%s can not remove backend: %s", logIPVSPrefix, err.Error())
 					}
+					continue
+				}
+
+				// apply upstream weight changes
+				if nd.Weight != dest.Weight {
+					log.Debugf("%s service %s backend %s weight update: %d", logIPVSPrefix, id, did, nd.Weight)
+					if err := p.ipvs.UpdateDestination(svc.srvc, nd); err != nil {
+						log.Errorf("%s can not update backend: %s", logIPVSPrefix, err.Error())
+					}
 				}
 			}
 		}
@@ -247,6 +266,26 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 	}
 
 	log.Debugf("Check ip %s is binded to link %s", spec.IP, p.link.Attrs().Name)
+	p.bindEndpoint(&spec.EndpointSpec)
+
+	// unbind IPv6 address removed from endpoint
+	if state.IPv6 != types.EmptyString && state.IPv6 != spec.IPv6 {
+		if err := p.delIpBindToLink(state.IPv6); err != nil {
+			log.Errorf("%s can not unbind ip from link: %s", logIPVSPrefix, err.Error())
+		}
+	}
+
+	st, err := p.getStateByIP(ctx, spec.IP, spec.IPv6, spec.NodePorts)
+	if err != nil {
+		log.Errorf("%s get state by ip err: %s", logIPVSPrefix, err.Error())
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// bindEndpoint binds endpoint IPv4 and IPv6 addresses to link
+func (p *Proxy) bindEndpoint(spec *types.EndpointSpec) {
 
 	var dest net.IP
 
@@ -260,17 +299,19 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 		log.Warnf("%s failed bind ip to link err: %s", logIPVSPrefix, err.Error())
 	}
 
-	st, err := p.getStateByIP(ctx, spec.IP)
-	if err != nil {
-		log.Errorf("%s get state by ip err: %s", logIPVSPrefix, err.Error())
-		return nil, err
+	if spec.IPv6 == types.EmptyString {
+		return
 	}
 
-	return st, nil
+	if err := p.addIpBindToLink(spec.IPv6, nil); err != nil {
+		log.Warnf("%s failed bind ipv6 to link err: %s", logIPVSPrefix, err.Error())
+	}
 }
 
-// getStateByIp returns current proxy state filtered by endpoint ip
-func (p *Proxy) getStateByIP(ctx context.Context, ip string) (*types.EndpointState, error) {
+// getStateByIp returns current proxy state filtered by endpoint ip,
+// services of endpoint IPv6 address are merged into the same state,
+// node ports are taken from services of node address
+func (p *Proxy) getStateByIP(ctx context.Context, ip, ip6 string, nodePorts map[uint16]uint16) (*types.EndpointState, error) {
 
 	state, err := p.getState(ctx)
 	if err != nil {
@@ -278,7 +319,54 @@ func (p *Proxy) getStateByIP(ctx context.Context, ip string) (*types.EndpointSta
 		return nil, err
 	}
 
-	return state[ip], nil
+	st := state[ip]
+
+	if hst, ok := state[p.dest.external.String()]; ok && st != nil {
+		for port, np := range nodePorts {
+			if _, ok := hst.PortMap[np]; !ok {
+				continue
+			}
+			if st.NodePorts == nil {
+				st.NodePorts = make(map[uint16]uint16, 0)
+			}
+			st.NodePorts[port] = np
+		}
+	}
+
+	if ip6 == types.EmptyString {
+		return st, nil
+	}
+
+	st6, ok := state[ip6]
+	if !ok {
+		return st, nil
+	}
+
+	if st == nil {
+		st = st6
+		st.IP = ip
+		st.IPv6 = ip6
+		return st, nil
+	}
+
+	st.IPv6 = ip6
+	st.Upstreams = append(st.Upstreams, st6.Upstreams...)
+	for host, w := range st6.Strategy.Weights {
+		if st.Strategy.Weights == nil {
+			st.Strategy.Weights = make(map[string]int, 0)
+		}
+		st.Strategy.Weights[host] = w
+	}
+	for host, us := range st6.Stats {
+		st.Stats[host] = us
+	}
+	for port, pm := range st6.PortMap {
+		if _, ok := st.PortMap[port]; !ok {
+			st.PortMap[port] = pm
+		}
+	}
+
+	return st, nil
 }
 
 // getStateByIp returns current proxy state
@@ -311,6 +399,12 @@ func (p *Proxy) getState(ctx context.Context) (map[string]*types.EndpointState,
 			endpoint.IP = host
 			endpoint.PortMap = make(map[uint16]string)
 			endpoint.Upstreams = make([]string, 0)
+			endpoint.Stats = make(map[string]*types.EndpointUpstreamStats, 0)
+		}
+
+		endpoint.Strategy.Route = svc.SchedName
+		if svc.Flags&ipvsSvcFlagPersistent != 0 {
+			endpoint.Strategy.Affinity = int(svc.Timeout)
 		}
 
 		var prt uint16
@@ -346,6 +440,22 @@ func (p *Proxy) getState(ctx context.Context) (map[string]*types.EndpointState,
 			if !f {
 				endpoint.Upstreams = append(endpoint.Upstreams, dest.Address.String())
 			}
+
+			if dest.Weight != types.EndpointSpecWeightDefault {
+				if endpoint.Strategy.Weights == nil {
+					endpoint.Strategy.Weights = make(map[string]int, 0)
+				}
+				endpoint.Strategy.Weights[dest.Address.String()] = dest.Weight
+			}
+
+			// connections are summed across all upstream ports
+			us, ok := endpoint.Stats[dest.Address.String()]
+			if !ok {
+				us = new(types.EndpointUpstreamStats)
+				endpoint.Stats[dest.Address.String()] = us
+			}
+			us.Active += dest.ActiveConnections
+			us.Inactive += dest.InactiveConnections
 		}
 
 		if prt != 0 {
@@ -378,13 +488,13 @@ func (p *Proxy) getState(ctx context.Context) (map[string]*types.EndpointState,
 func (p *Proxy) addIpBindToLink(ip string, dest net.IP) error {
 
 	ipn := net.ParseIP(ip)
-	addr, err := netlink.ParseAddr(fmt.Sprintf("%s/32", ipn.String()))
+	addr, err := netlink.ParseAddr(ipHostCIDR(ipn))
 	if err != nil {
 		log.Errorf("%s can not parse IP %s; %s", logIPVSPrefix, ip, err.Error())
 		return err
 	}
 
-	addrs, err := netlink.AddrList(p.link, netlink.FAMILY_V4)
+	addrs, err := netlink.AddrList(p.link, ipFamily(ipn))
 	if err != nil {
 		log.Errorf("%s can not fetch IPs: %s", logIPVSPrefix, err.Error())
 		return err
@@ -398,9 +508,18 @@ func (p *Proxy) addIpBindToLink(ip string, dest net.IP) error {
 		}
 	}
 	if !exists {
+		if ipn.To4() == nil {
+			// skip duplicate address detection, address is local for ipvs only
+			addr.Flags = unix.IFA_F_NODAD
+		}
 		netlink.AddrAdd(p.link, addr)
 	}
 
+	// IPv6 local route source is kept as is, route source is replaced for IPv4 only
+	if ipn.To4() == nil {
+		return nil
+	}
+
 	routes, err := netlink.RouteGet(ipn)
 	if err != nil {
 		log.Errorf("%s can not get routes for ip", logIPVSPrefix)
@@ -429,13 +548,13 @@ func (p *Proxy) addIpBindToLink(ip string, dest net.IP) error {
 func (p *Proxy) delIpBindToLink(ip string) error {
 
 	ipn := net.ParseIP(ip)
-	addr, err := netlink.ParseAddr(fmt.Sprintf("%s/32", ipn.String()))
+	addr, err := netlink.ParseAddr(ipHostCIDR(ipn))
 	if err != nil {
 		log.Errorf("%s can not parse IP %s; %s", logIPVSPrefix, ip, err.Error())
 		return err
 	}
 
-	addrs, err := netlink.AddrList(p.link, netlink.FAMILY_V4)
+	addrs, err := netlink.AddrList(p.link, ipFamily(ipn))
 	if err != nil {
 		log.Errorf("%s can not fetch IPs:%s", logIPVSPrefix, err.Error())
 		return err
@@ -469,6 +588,11 @@ func New(v *viper.Viper) (*Proxy, error) {
 
 	prx.ipvs = handler
 
+	// connections tracking is required to masquerade traffic of node ports
+	if err := ioutil.WriteFile(ipvsConntrackSysctl, []byte("1"), 0644); err != nil {
+		log.Warnf("%s can not enable ipvs connections tracking: %s", logIPVSPrefix, err.Error())
+	}
+
 	links, err := netlink.LinkList()
 	if err != nil {
 		return nil, err
@@ -518,10 +642,12 @@ func New(v *viper.Viper) (*Proxy, error) {
 
 	if eiface == types.EmptyString {
 		log.Debugf("%s find default interface to traffic route by name", logIPVSPrefix)
-		_, prx.dest.external, err = utils.GetDefaultInterface()
+		var iface *net.Interface
+		iface, prx.dest.external, err = utils.GetDefaultInterface()
 		if err != nil {
 			return nil, err
 		}
+		eiface = iface.Name
 
 		log.Debugf("%s external route ip net: %s", logIPVSPrefix, prx.dest.external.String())
 	} else {
@@ -545,39 +671,120 @@ func New(v *viper.Viper) (*Proxy, error) {
 
 	log.Debugf("%s internal route ip net: %s", logIPVSPrefix, prx.dest.internal.String())
 
+	// replies of node ports upstreams on other nodes and of external upstreams
+	// should be returned through this node
+	rules := append(utils.NodePortMasqRules(prx.dest.external), utils.ExternalMasqRules(eiface)...)
+	go utils.SetupAndEnsureIPTables(rules, 5)
+
 	// TODO: Check ipvs proxy mode is available on host
 	return prx, nil
 }
 
-func specToServices(spec *types.EndpointManifest) (map[string]*Service, error) {
+func ipFamily(ip net.IP) int {
+	if ip.To4() == nil {
+		return nl.FAMILY_V6
+	}
+	return nl.FAMILY_V4
+}
+
+func ipHostCIDR(ip net.IP) string {
+	if ip.To4() == nil {
+		return fmt.Sprintf("%s/128", ip.String())
+	}
+	return fmt.Sprintf("%s/32", ip.String())
+}
+
+// specToServices returns services for endpoint IPv4 and IPv6 addresses,
+// services get upstreams of the same address family only.
+// Exposed ports are served on node address if it is set
+func specToServices(spec *types.EndpointManifest, host net.IP) (map[string]*Service, error) {
 
 	var svcs = make(map[string]*Service, 0)
 
+	for _, ip := range []string{spec.IP, spec.IPv6} {
+		if ip == types.EmptyString {
+			continue
+		}
+
+		if err := specAddressToServices(svcs, spec, ip); err != nil {
+			return svcs, err
+		}
+	}
+
+	if host == nil || len(spec.NodePorts) == 0 {
+		return svcs, nil
+	}
+
+	// node port services have endpoint upstreams and ports
+	mf := types.EndpointManifest{}
+	mf.Strategy = spec.Strategy
+	mf.Upstreams = spec.Upstreams
+	mf.PortMap = make(map[uint16]string, 0)
+
+	for port, np := range spec.NodePorts {
+		if pm, ok := spec.PortMap[port]; ok {
+			mf.PortMap[np] = pm
+		}
+	}
+
+	if err := specAddressToServices(svcs, &mf, host.String()); err != nil {
+		return svcs, err
+	}
+
+	return svcs, nil
+}
+
+func specAddressToServices(svcs map[string]*Service, spec *types.EndpointManifest, ip string) error {
+
+	var (
+		addr   = net.ParseIP(ip)
+		family = ipFamily(addr)
+	)
+
+	if addr == nil {
+		return errors.New("Invalid endpoint ip")
+	}
+
 	for ext, pm := range spec.PortMap {
 
 		port, proto, err := network.ParsePortMap(pm)
 		if err != nil {
 			err = errors.New("Invalid port map declaration")
-			return svcs, err
+			return err
 		}
 
 		svc := new(Service)
 		svc.srvc = &libipvs.Service{
-			Address:       net.ParseIP(spec.IP),
+			Address:       addr,
 			Port:          ext,
-			AddressFamily: nl.FAMILY_V4,
-			SchedName:     "rr",
+			AddressFamily: uint16(family),
+			SchedName:     spec.Strategy.GetRoute(),
+		}
+
+		// session affinity keeps client connections on the same upstream until timeout expires
+		if spec.Strategy.Affinity > 0 {
+			svc.srvc.Flags = ipvsSvcFlagPersistent
+			svc.srvc.Timeout = uint32(spec.Strategy.Affinity)
+			svc.srvc.Netmask = ipvsSvcNetmaskIPv4
+			if family == nl.FAMILY_V6 {
+				svc.srvc.Netmask = ipvsSvcNetmaskIPv6
+			}
 		}
 		svc.dest = make(map[string]*libipvs.Destination, 0)
 
 		for _, host := range spec.Upstreams {
-			log.Debugf("%s: add new destination to spec for: %s", logIPVSPrefix, host)
 
 			dest := new(libipvs.Destination)
 
 			dest.Address = net.ParseIP(host)
+			if dest.Address == nil || ipFamily(dest.Address) != family {
+				continue
+			}
+
+			log.Debugf("%s: add new destination to spec for: %s", logIPVSPrefix, host)
+			dest.AddressFamily = uint16(family)
 			dest.Port = port
-			dest.Weight = 1
+			dest.Weight = spec.Strategy.GetWeight(host)
 			svc.dest[fmt.Sprintf("%s_%d", dest.Address.String(), dest.Port)] = dest
 			log.Debugf("%s: added new destination %s_%d", logIPVSPrefix, dest.Address.String(), dest.Port)
 		}
@@ -585,22 +792,42 @@ func specToServices(spec *types.EndpointManifest) (map[string]*Service, error) {
 		switch proto {
 		case "tcp":
 			svc.srvc.Protocol = syscall.IPPROTO_TCP
-			svcs[fmt.Sprintf("%s_%d_%d_%s", spec.IP, svc.srvc.Port, port, proxyTCPProto)] = svc
+			svcs[fmt.Sprintf("%s_%d_%d_%s", ip, svc.srvc.Port, port, proxyTCPProto)] = svc
 			break
 		case "udp":
 			svc.srvc.Protocol = syscall.IPPROTO_UDP
-			svcs[fmt.Sprintf("%s_%d_%d_%s", spec.IP, svc.srvc.Port, port, proxyUDPProto)] = svc
+			svcs[fmt.Sprintf("%s_%d_%d_%s", ip, svc.srvc.Port, port, proxyUDPProto)] = svc
 			break
 		case "*":
 			svcc := *svc
+			srvc := *svc.srvc
+			svcc.srvc = &srvc
 			svc.srvc.Protocol = syscall.IPPROTO_TCP
 			svcc.srvc.Protocol = syscall.IPPROTO_UDP
 
-			svcs[fmt.Sprintf("%s_%d_%d_%s", spec.IP, svc.srvc.Port, port, proxyTCPProto)] = svc
-			svcs[fmt.Sprintf("%s_%d_%d_%s", spec.IP, svcc.srvc.Port, port, proxyUDPProto)] = &svcc
+			svcs[fmt.Sprintf("%s_%d_%d_%s", ip, svc.srvc.Port, port, proxyTCPProto)] = svc
+			svcs[fmt.Sprintf("%s_%d_%d_%s", ip, svcc.srvc.Port, port, proxyUDPProto)] = &svcc
 			break
 		}
 	}
 
-	return svcs, nil
+	return nil
+}
+
+// serviceSchedEqual compares service scheduler and session affinity settings
+func serviceSchedEqual(c, n *libipvs.Service) bool {
+
+	if c.SchedName != n.SchedName {
+		return false
+	}
+
+	if c.Flags&ipvsSvcFlagPersistent != n.Flags&ipvsSvcFlagPersistent {
+		return false
+	}
+
+	if n.Flags&ipvsSvcFlagPersistent != 0 && c.Timeout != n.Timeout {
+		return false
+	}
+
+	return true
 }
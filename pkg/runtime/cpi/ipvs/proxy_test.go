@@ -0,0 +1,188 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+//go:build linux
+// +build linux
+
+package ipvs
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink/nl"
+)
+
+func TestSpecToServices(t *testing.T) {
+
+	spec := new(types.EndpointManifest)
+	spec.IP = "172.0.0.2"
+	spec.IPv6 = "fd00:ff::2"
+	spec.PortMap = map[uint16]string{80: "8080/tcp"}
+	spec.Upstreams = []string{"10.0.0.2", "fd00::2", "10.0.0.3"}
+
+	svcs, err := specToServices(spec, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Len(t, svcs, 2, "services count different") {
+		return
+	}
+
+	v4, ok := svcs["172.0.0.2_80_8080_tcp"]
+	if assert.True(t, ok, "ipv4 service not found") {
+		assert.Equal(t, uint16(nl.FAMILY_V4), v4.srvc.AddressFamily)
+		assert.Len(t, v4.dest, 2, "ipv4 service should have ipv4 upstreams only")
+		assert.Contains(t, v4.dest, "10.0.0.2_8080")
+		assert.Contains(t, v4.dest, "10.0.0.3_8080")
+	}
+
+	v6, ok := svcs["fd00:ff::2_80_8080_tcp"]
+	if assert.True(t, ok, "ipv6 service not found") {
+		assert.Equal(t, uint16(nl.FAMILY_V6), v6.srvc.AddressFamily)
+		assert.Len(t, v6.dest, 1, "ipv6 service should have ipv6 upstreams only")
+		assert.Contains(t, v6.dest, "fd00::2_8080")
+	}
+
+	spec.IPv6 = types.EmptyString
+	svcs, err = specToServices(spec, nil)
+	if assert.NoError(t, err) {
+		assert.Len(t, svcs, 1, "ipv4 only endpoint should have ipv4 services")
+	}
+}
+
+func TestSpecToServicesStrategy(t *testing.T) {
+
+	spec := new(types.EndpointManifest)
+	spec.IP = "172.0.0.2"
+	spec.IPv6 = "fd00:ff::2"
+	spec.PortMap = map[uint16]string{80: "8080/*"}
+	spec.Upstreams = []string{"10.0.0.2", "fd00::2"}
+
+	svcs, err := specToServices(spec, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for id, svc := range svcs {
+		assert.Equal(t, types.EndpointSpecRouteStrategyRR, svc.srvc.SchedName, "default scheduler should be round robin: %s", id)
+		assert.Equal(t, uint32(0), svc.srvc.Flags&ipvsSvcFlagPersistent, "affinity should be disabled by default: %s", id)
+	}
+
+	tcp, udp := svcs["172.0.0.2_80_8080_tcp"], svcs["172.0.0.2_80_8080_udp"]
+	if assert.NotNil(t, tcp) && assert.NotNil(t, udp) {
+		assert.Equal(t, uint16(syscall.IPPROTO_TCP), tcp.srvc.Protocol, "tcp service protocol mismatch")
+		assert.Equal(t, uint16(syscall.IPPROTO_UDP), udp.srvc.Protocol, "udp service protocol mismatch")
+	}
+
+	spec.Strategy.Route = types.EndpointSpecRouteStrategySH
+	spec.Strategy.Affinity = 300
+
+	svcs, err = specToServices(spec, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	v4, ok := svcs["172.0.0.2_80_8080_tcp"]
+	if assert.True(t, ok, "ipv4 service not found") {
+		assert.Equal(t, types.EndpointSpecRouteStrategySH, v4.srvc.SchedName)
+		assert.Equal(t, uint32(ipvsSvcFlagPersistent), v4.srvc.Flags)
+		assert.Equal(t, uint32(300), v4.srvc.Timeout)
+		assert.Equal(t, uint32(ipvsSvcNetmaskIPv4), v4.srvc.Netmask)
+	}
+
+	v6, ok := svcs["fd00:ff::2_80_8080_tcp"]
+	if assert.True(t, ok, "ipv6 service not found") {
+		assert.Equal(t, uint32(ipvsSvcNetmaskIPv6), v6.srvc.Netmask)
+	}
+
+	// state services are compared with spec services to apply scheduler changes
+	spec.Strategy.Affinity = 0
+	nsvcs, err := specToServices(spec, nil)
+	if assert.NoError(t, err) {
+		assert.False(t, serviceSchedEqual(v4.srvc, nsvcs["172.0.0.2_80_8080_tcp"].srvc), "affinity change not detected")
+	}
+
+	spec.Strategy.Route = types.EndpointSpecRouteStrategyLC
+	lsvcs, err := specToServices(spec, nil)
+	if assert.NoError(t, err) {
+		assert.False(t, serviceSchedEqual(nsvcs["172.0.0.2_80_8080_tcp"].srvc, lsvcs["172.0.0.2_80_8080_tcp"].srvc), "scheduler change not detected")
+		assert.True(t, serviceSchedEqual(lsvcs["172.0.0.2_80_8080_tcp"].srvc, lsvcs["172.0.0.2_80_8080_tcp"].srvc))
+	}
+}
+
+func TestSpecToServicesWeights(t *testing.T) {
+
+	spec := new(types.EndpointManifest)
+	spec.IP = "172.0.0.2"
+	spec.PortMap = map[uint16]string{80: "8080/tcp"}
+	spec.NodePorts = map[uint16]uint16{80: 30001}
+	spec.Upstreams = []string{"10.0.0.2", "10.0.0.3"}
+	spec.Strategy.Route = types.EndpointSpecRouteStrategyWRR
+	spec.Strategy.Weights = map[string]int{"10.0.0.2": 5}
+
+	svcs, err := specToServices(spec, net.ParseIP("192.168.0.10"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, id := range []string{"172.0.0.2_80_8080_tcp", "192.168.0.10_30001_8080_tcp"} {
+		svc, ok := svcs[id]
+		if !assert.True(t, ok, "service not found: %s", id) {
+			continue
+		}
+
+		assert.Equal(t, types.EndpointSpecRouteStrategyWRR, svc.srvc.SchedName, "scheduler mismatch: %s", id)
+		assert.Equal(t, 5, svc.dest["10.0.0.2_8080"].Weight, "upstream weight mismatch: %s", id)
+		assert.Equal(t, types.EndpointSpecWeightDefault, svc.dest["10.0.0.3_8080"].Weight, "default weight mismatch: %s", id)
+	}
+}
+
+func TestSpecToServicesNodePorts(t *testing.T) {
+
+	spec := new(types.EndpointManifest)
+	spec.IP = "172.0.0.2"
+	spec.PortMap = map[uint16]string{80: "8080/tcp", 1883: "1883/tcp"}
+	spec.NodePorts = map[uint16]uint16{1883: 30001}
+	spec.Upstreams = []string{"10.0.0.2", "10.0.0.3"}
+	spec.Strategy.Route = types.EndpointSpecRouteStrategyLC
+
+	svcs, err := specToServices(spec, nil)
+	if assert.NoError(t, err) {
+		assert.Len(t, svcs, 2, "node ports should not be served without node address")
+	}
+
+	svcs, err = specToServices(spec, net.ParseIP("192.168.0.10"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, svcs, 3, "services count different")
+
+	np, ok := svcs["192.168.0.10_30001_1883_tcp"]
+	if assert.True(t, ok, "node port service not found") {
+		assert.Equal(t, uint16(30001), np.srvc.Port)
+		assert.Equal(t, types.EndpointSpecRouteStrategyLC, np.srvc.SchedName, "node port service should have endpoint scheduler")
+		assert.Len(t, np.dest, 2, "node port service should have endpoint upstreams")
+		assert.Contains(t, np.dest, "10.0.0.2_1883")
+	}
+}
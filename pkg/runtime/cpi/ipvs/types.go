@@ -23,4 +23,14 @@ package ipvs
 const (
 	proxyTCPProto = "tcp"
 	proxyUDPProto = "udp"
+
+	// ipvsSvcFlagPersistent - IP_VS_SVC_F_PERSISTENT service flag, enables session affinity
+	ipvsSvcFlagPersistent = 0x1
+	// ipvsSvcNetmaskIPv4 - persistence granularity for IPv4 clients, each client address is tracked
+	ipvsSvcNetmaskIPv4 = 0xffffffff
+	// ipvsSvcNetmaskIPv6 - persistence granularity for IPv6 clients, set as prefix length
+	ipvsSvcNetmaskIPv6 = 128
+
+	// ipvsConntrackSysctl - enables connections tracking of ipvs traffic
+	ipvsConntrackSysctl = "/proc/sys/net/ipv4/vs/conntrack"
 )
\ No newline at end of file
@@ -0,0 +1,213 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+)
+
+const (
+	defaultCPUPeriod = 100000
+	nanoCPUs         = 1e9
+
+	networkModeHost = "host"
+)
+
+func GetSandboxConfig(root string, manifest *types.ContainerManifest) *ctrd.PodSandboxConfig {
+
+	var (
+		pod             = manifest.Labels[types.ContainerTypeLBC]
+		namespace, name = podName(pod)
+	)
+
+	cfg := &ctrd.PodSandboxConfig{
+		Metadata: &ctrd.PodSandboxMetadata{
+			Name:      name,
+			Namespace: namespace,
+			Uid:       pod,
+		},
+		Hostname:     manifest.Network.Hostname,
+		LogDirectory: path.Join(root, strings.Replace(pod, ":", "-", -1)),
+		Labels: map[string]string{
+			types.ContainerTypeLBC: pod,
+		},
+		Linux: &ctrd.LinuxPodSandboxConfig{
+			SecurityContext: &ctrd.LinuxSandboxSecurityContext{
+				NamespaceOptions: &ctrd.NamespaceOption{
+					Network: ctrd.NamespaceModePod,
+					Pid:     ctrd.NamespaceModeContainer,
+				},
+				Privileged: manifest.Security.Privileged,
+			},
+		},
+	}
+
+	if manifest.Network.Mode == networkModeHost {
+		cfg.Linux.SecurityContext.NamespaceOptions.Network = ctrd.NamespaceModeNode
+	}
+
+	if len(manifest.DNS.Server) != 0 || len(manifest.DNS.Search) != 0 || len(manifest.DNS.Options) != 0 {
+		cfg.DnsConfig = &ctrd.DNSConfig{
+			Servers:  manifest.DNS.Server,
+			Searches: manifest.DNS.Search,
+			Options:  manifest.DNS.Options,
+		}
+	}
+
+	for _, p := range manifest.Ports {
+
+		pm := &ctrd.PortMapping{
+			Protocol:      ctrd.ProtocolTCP,
+			ContainerPort: int32(p.ContainerPort),
+			HostPort:      int32(p.HostPort),
+			HostIp:        p.HostIP,
+		}
+
+		switch strings.ToLower(p.Protocol) {
+		case "udp":
+			pm.Protocol = ctrd.ProtocolUDP
+		case "sctp":
+			pm.Protocol = ctrd.ProtocolSCTP
+		}
+
+		cfg.PortMappings = append(cfg.PortMappings, pm)
+	}
+
+	return cfg
+}
+
+func GetContainerConfig(manifest *types.ContainerManifest) *ctrd.ContainerConfig {
+
+	cfg := &ctrd.ContainerConfig{
+		Metadata: &ctrd.ContainerMetadata{
+			Name: manifest.Name,
+		},
+		Image: &ctrd.ImageSpec{
+			Image: manifest.Image,
+		},
+		Command:    manifest.Exec.Entrypoint,
+		Args:       manifest.Exec.Command,
+		WorkingDir: manifest.Exec.Workdir,
+		Labels:     manifest.Labels,
+		LogPath:    fmt.Sprintf("%s.log", manifest.Name),
+		Linux: &ctrd.LinuxContainerConfig{
+			Resources: &ctrd.LinuxContainerResources{
+				MemoryLimitInBytes: manifest.Resources.Limits.RAM,
+			},
+			SecurityContext: GetSecurityContext(manifest.Security),
+		},
+	}
+
+	if manifest.Resources.Limits.CPU > 0 {
+		cfg.Linux.Resources.CpuPeriod = defaultCPUPeriod
+		cfg.Linux.Resources.CpuQuota = manifest.Resources.Limits.CPU * defaultCPUPeriod / nanoCPUs
+	}
+
+	for _, e := range manifest.Envs {
+		parts := strings.SplitN(e, "=", 2)
+		kv := &ctrd.KeyValue{Key: parts[0]}
+		if len(parts) == 2 {
+			kv.Value = parts[1]
+		}
+		cfg.Envs = append(cfg.Envs, kv)
+	}
+
+	for _, b := range manifest.Binds {
+		parts := strings.Split(b, ":")
+		if len(parts) < 2 {
+			continue
+		}
+
+		m := &ctrd.Mount{
+			HostPath:      parts[0],
+			ContainerPath: parts[1],
+		}
+
+		if len(parts) > 2 && parts[2] == "ro" {
+			m.Readonly = true
+		}
+
+		cfg.Mounts = append(cfg.Mounts, m)
+	}
+
+	return cfg
+}
+
+// GetSecurityContext converts container security options to CRI security context,
+// seccomp and apparmor profiles use the same notation as CRI and are passed as is
+func GetSecurityContext(security types.SpecTemplateContainerSecurity) *ctrd.LinuxContainerSecurityContext {
+
+	sc := &ctrd.LinuxContainerSecurityContext{
+		Privileged:         security.Privileged,
+		ReadonlyRootfs:     security.ReadOnlyRootfs,
+		NoNewPrivs:         security.NoNewPrivileges,
+		SeccompProfilePath: security.LinuxOptions.Seccomp,
+		ApparmorProfile:    security.LinuxOptions.AppArmor,
+	}
+
+	if security.User != 0 || security.Group != 0 {
+		sc.RunAsUser = &ctrd.Int64Value{Value: int64(security.User)}
+	}
+
+	if security.Group != 0 {
+		sc.RunAsGroup = &ctrd.Int64Value{Value: int64(security.Group)}
+	}
+
+	for _, g := range security.Groups {
+		sc.SupplementalGroups = append(sc.SupplementalGroups, int64(g))
+	}
+
+	if len(security.Capabilities.Add) != 0 || len(security.Capabilities.Drop) != 0 {
+		sc.Capabilities = &ctrd.Capability{
+			AddCapabilities:  security.Capabilities.Add,
+			DropCapabilities: security.Capabilities.Drop,
+		}
+	}
+
+	opts := security.LinuxOptions
+	if opts.User != types.EmptyString || opts.Role != types.EmptyString || opts.Type != types.EmptyString || opts.Level != types.EmptyString {
+		sc.SelinuxOptions = &ctrd.SELinuxOption{
+			User:  opts.User,
+			Role:  opts.Role,
+			Type:  opts.Type,
+			Level: opts.Level,
+		}
+	}
+
+	return sc
+}
+
+func podName(pod string) (string, string) {
+
+	parts := strings.Split(pod, ":")
+
+	switch len(parts) {
+	case 0:
+		return types.SYSTEM_NAMESPACE, types.EmptyString
+	case 1:
+		return types.SYSTEM_NAMESPACE, parts[0]
+	default:
+		return parts[0], parts[len(parts)-1]
+	}
+}
@@ -0,0 +1,405 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+	"github.com/lastbackend/lastbackend/pkg/util/generator"
+)
+
+const (
+	waitInterval = time.Second
+	taskNameSize = 12
+)
+
+var ErrNotSupported = errors.New("operation not supported by containerd runtime")
+
+func (r *Runtime) List(ctx context.Context, all bool) ([]*types.Container, error) {
+	var cl = make([]*types.Container, 0)
+
+	filter := new(ctrd.ContainerFilter)
+	if !all {
+		filter.State = &ctrd.ContainerStateValue{State: ctrd.ContainerRunning}
+	}
+
+	res, err := r.client.Runtime.ListContainers(ctx, &ctrd.ListContainersRequest{Filter: filter})
+	if err != nil {
+		return cl, err
+	}
+
+	for _, item := range res.Containers {
+
+		c, err := r.Inspect(ctx, item.Id)
+		if err != nil {
+			log.Errorf("%s:list:> can not inspect container err: %v", logPrefix, err)
+			continue
+		}
+
+		if c == nil {
+			continue
+		}
+
+		cl = append(cl, c)
+	}
+
+	return cl, nil
+}
+
+func (r *Runtime) Create(ctx context.Context, manifest *types.ContainerManifest) (string, error) {
+
+	if manifest.Labels == nil {
+		manifest.Labels = make(map[string]string, 0)
+	}
+
+	if len(manifest.Name) == 0 {
+		manifest.Name = generator.GenerateRandomString(taskNameSize)
+	}
+
+	sandbox, cfg, err := r.sandbox(ctx, manifest)
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	c, err := r.client.Runtime.CreateContainer(ctx, &ctrd.CreateContainerRequest{
+		PodSandboxId:  sandbox,
+		Config:        GetContainerConfig(manifest),
+		SandboxConfig: cfg,
+	})
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	return c.ContainerId, nil
+}
+
+func (r *Runtime) Start(ctx context.Context, ID string) error {
+	_, err := r.client.Runtime.StartContainer(ctx, &ctrd.StartContainerRequest{ContainerId: ID})
+	return err
+}
+
+// Restart is not supported by cri plugin: exited containers can not be started again
+func (r *Runtime) Restart(ctx context.Context, ID string, timeout *time.Duration) error {
+	return ErrNotSupported
+}
+
+func (r *Runtime) Stop(ctx context.Context, ID string, timeout *time.Duration) error {
+
+	t := defaultStopTimeout
+	if timeout != nil {
+		t = *timeout
+	}
+
+	_, err := r.client.Runtime.StopContainer(ctx, &ctrd.StopContainerRequest{
+		ContainerId: ID,
+		Timeout:     int64(t.Seconds()),
+	})
+	return err
+}
+
+func (r *Runtime) Exec(ctx context.Context, ID string, cmd []string, timeout *time.Duration) (int, error) {
+
+	req := &ctrd.ExecSyncRequest{
+		ContainerId: ID,
+		Cmd:         cmd,
+	}
+
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+		req.Timeout = int64(timeout.Seconds())
+	}
+
+	resp, err := r.client.Runtime.ExecSync(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(resp.ExitCode), nil
+}
+
+// Stats returns container resources usage snapshot. Memory limit is taken from
+// container resources, because CRI container stats do not contain it.
+// CRI does not report network and block IO counters for containers,
+// so network and block stats are always empty for containerd runtime
+func (r *Runtime) Stats(ctx context.Context, ID string) (*types.ContainerStats, error) {
+
+	resp, err := r.client.Runtime.ContainerStats(ctx, &ctrd.ContainerStatsRequest{ContainerId: ID})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := new(types.ContainerStats)
+	stats.ID = ID
+	stats.Timestamp = time.Now().UTC()
+
+	if resp.Stats == nil {
+		return stats, nil
+	}
+
+	if resp.Stats.Attributes != nil && resp.Stats.Attributes.Metadata != nil {
+		stats.Name = resp.Stats.Attributes.Metadata.Name
+	}
+
+	if resp.Stats.Cpu != nil {
+		stats.Timestamp = time.Unix(0, resp.Stats.Cpu.Timestamp).UTC()
+		if resp.Stats.Cpu.UsageCoreNanoSeconds != nil {
+			stats.CPU.Total = resp.Stats.Cpu.UsageCoreNanoSeconds.Value
+		}
+	}
+
+	if resp.Stats.Memory != nil && resp.Stats.Memory.RssBytes != nil {
+		stats.Memory.RSS = resp.Stats.Memory.RssBytes.Value
+	}
+
+	limit, err := r.memoryLimit(ctx, ID)
+	if err != nil {
+		log.Warnf("%s:stats:> can not get container %s memory limit: %v", logPrefix, ID, err)
+	}
+	stats.Memory.Limit = limit
+
+	return stats, nil
+}
+
+// memoryLimit returns container memory limit from container status resources,
+// runtimes which do not report resources in status have it in verbose container config
+func (r *Runtime) memoryLimit(ctx context.Context, ID string) (uint64, error) {
+
+	res, err := r.client.Runtime.ContainerStatus(ctx, &ctrd.ContainerStatusRequest{
+		ContainerId: ID,
+		Verbose:     true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if res.Status != nil && res.Status.Resources != nil && res.Status.Resources.Linux != nil {
+		if res.Status.Resources.Linux.MemoryLimitInBytes > 0 {
+			return uint64(res.Status.Resources.Linux.MemoryLimitInBytes), nil
+		}
+	}
+
+	v, ok := res.Info["info"]
+	if !ok {
+		return 0, nil
+	}
+
+	var verbose struct {
+		Config ctrd.ContainerConfig `json:"config"`
+	}
+
+	if err := json.Unmarshal([]byte(v), &verbose); err != nil {
+		return 0, err
+	}
+
+	if verbose.Config.Linux == nil || verbose.Config.Linux.Resources == nil {
+		return 0, nil
+	}
+
+	if verbose.Config.Linux.Resources.MemoryLimitInBytes > 0 {
+		return uint64(verbose.Config.Linux.Resources.MemoryLimitInBytes), nil
+	}
+
+	return 0, nil
+}
+
+func (r *Runtime) Pause(ctx context.Context, ID string) error {
+	return ErrNotSupported
+}
+
+func (r *Runtime) Resume(ctx context.Context, ID string) error {
+	return ErrNotSupported
+}
+
+func (r *Runtime) Remove(ctx context.Context, ID string, clean bool, force bool) error {
+
+	sandbox, err := r.containerSandbox(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	if force {
+		if err := r.Stop(ctx, ID, new(time.Duration)); err != nil {
+			log.Warnf("%s:remove:> can not stop container %s: %v", logPrefix, ID, err)
+		}
+	}
+
+	if _, err := r.client.Runtime.RemoveContainer(ctx, &ctrd.RemoveContainerRequest{ContainerId: ID}); err != nil {
+		return err
+	}
+
+	if sandbox == types.EmptyString {
+		return nil
+	}
+
+	return r.sandboxClean(ctx, sandbox)
+}
+
+func (r *Runtime) Inspect(ctx context.Context, ID string) (*types.Container, error) {
+
+	log.V(logLevel).Debugf("%s:inspect:> container inspect: %s", logPrefix, ID)
+
+	res, err := r.client.Runtime.ContainerStatus(ctx, &ctrd.ContainerStatusRequest{
+		ContainerId: ID,
+		Verbose:     true,
+	})
+	if err != nil {
+		log.Errorf("%s:inspect:> container inspect err: %v", logPrefix, err)
+		return nil, err
+	}
+
+	if res.Status == nil {
+		return nil, fmt.Errorf("container %s not found", ID)
+	}
+
+	info := res.Status
+
+	c := &types.Container{
+		ID:       info.Id,
+		Error:    info.Message,
+		ExitCode: int(info.ExitCode),
+		Labels:   info.Labels,
+		Envs:     make([]string, 0),
+		Binds:    make([]string, 0),
+	}
+
+	if info.Metadata != nil {
+		c.Name = info.Metadata.Name
+	}
+
+	if info.Image != nil {
+		c.Image = info.Image.Image
+	}
+
+	switch info.State {
+	case ctrd.ContainerCreated:
+		c.State = types.StateCreated
+		c.Status = types.StateCreated
+	case ctrd.ContainerRunning:
+		c.State = types.StateStarted
+		c.Status = types.StatusRunning
+	case ctrd.ContainerExited:
+		c.State = types.StatusStopped
+		c.Status = types.StateExited
+	default:
+		c.State = types.StateError
+		c.Status = types.StateError
+	}
+
+	if info.CreatedAt != 0 {
+		c.Created = time.Unix(0, info.CreatedAt).UTC()
+	}
+
+	if info.StartedAt != 0 {
+		c.Started = time.Unix(0, info.StartedAt).UTC()
+	}
+
+	for _, m := range info.Mounts {
+		bind := fmt.Sprintf("%s:%s", m.HostPath, m.ContainerPath)
+		if m.Readonly {
+			bind = fmt.Sprintf("%s:ro", bind)
+		}
+		c.Binds = append(c.Binds, bind)
+	}
+
+	if v, ok := res.Info["info"]; ok {
+		var verbose struct {
+			SandboxID string               `json:"sandboxID"`
+			Config    ctrd.ContainerConfig `json:"config"`
+		}
+
+		if err := json.Unmarshal([]byte(v), &verbose); err != nil {
+			log.Warnf("%s:inspect:> can not parse container %s info: %v", logPrefix, ID, err)
+		} else {
+			for _, e := range verbose.Config.Envs {
+				c.Envs = append(c.Envs, fmt.Sprintf("%s=%s", e.Key, e.Value))
+			}
+
+			c.Exec.Entrypoint = verbose.Config.Command
+			c.Exec.Command = verbose.Config.Args
+			c.Exec.Workdir = verbose.Config.WorkingDir
+
+			if verbose.SandboxID != types.EmptyString {
+				c.Network.IPAddress, c.Network.IPv6Address = r.sandboxIP(ctx, verbose.SandboxID)
+			}
+		}
+	}
+
+	c.Network.Ports = make([]*types.SpecTemplateContainerPort, 0)
+
+	meta, ok := info.Labels[types.ContainerTypeLBC]
+	if ok {
+		c.Pod = meta
+	}
+
+	return c, nil
+}
+
+func (r *Runtime) Wait(ctx context.Context, ID string) error {
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := r.client.Runtime.ContainerStatus(ctx, &ctrd.ContainerStatusRequest{ContainerId: ID})
+		if err != nil {
+			return err
+		}
+
+		if res.Status == nil || res.Status.State == ctrd.ContainerExited {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Copy is not supported by cri plugin, use binds to pass files into containers
+func (r *Runtime) Copy(ctx context.Context, ID, path string, content io.Reader) error {
+	return ErrNotSupported
+}
+
+func (r *Runtime) containerSandbox(ctx context.Context, ID string) (string, error) {
+
+	res, err := r.client.Runtime.ListContainers(ctx, &ctrd.ListContainersRequest{
+		Filter: &ctrd.ContainerFilter{Id: ID},
+	})
+	if err != nil {
+		return types.EmptyString, err
+	}
+
+	if len(res.Containers) == 0 {
+		return types.EmptyString, nil
+	}
+
+	return res.Containers[0].PodSandboxId, nil
+}
@@ -0,0 +1,665 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRuntime struct {
+	sync.Mutex
+
+	index      int
+	sandboxes  map[string]*ctrd.PodSandbox
+	containers map[string]*ctrd.ContainerStatus
+	parents    map[string]string
+	events     chan *ctrd.ContainerEventResponse
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{
+		sandboxes:  make(map[string]*ctrd.PodSandbox),
+		containers: make(map[string]*ctrd.ContainerStatus),
+		parents:    make(map[string]string),
+		events:     make(chan *ctrd.ContainerEventResponse, 10),
+	}
+}
+
+func (f *fakeRuntime) id(prefix string) string {
+	f.index++
+	return fmt.Sprintf("%s-%d", prefix, f.index)
+}
+
+func (f *fakeRuntime) Version(ctx context.Context, in *ctrd.VersionRequest) (*ctrd.VersionResponse, error) {
+	return &ctrd.VersionResponse{RuntimeName: "containerd", RuntimeApiVersion: "v1"}, nil
+}
+
+func (f *fakeRuntime) RunPodSandbox(ctx context.Context, in *ctrd.RunPodSandboxRequest) (*ctrd.RunPodSandboxResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	id := f.id("sandbox")
+	f.sandboxes[id] = &ctrd.PodSandbox{Id: id, Metadata: in.Config.Metadata, Labels: in.Config.Labels, State: ctrd.SandboxReady}
+	return &ctrd.RunPodSandboxResponse{PodSandboxId: id}, nil
+}
+
+func (f *fakeRuntime) StopPodSandbox(ctx context.Context, in *ctrd.StopPodSandboxRequest) (*ctrd.StopPodSandboxResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	if sb, ok := f.sandboxes[in.PodSandboxId]; ok {
+		sb.State = ctrd.SandboxNotReady
+	}
+	return new(ctrd.StopPodSandboxResponse), nil
+}
+
+func (f *fakeRuntime) RemovePodSandbox(ctx context.Context, in *ctrd.RemovePodSandboxRequest) (*ctrd.RemovePodSandboxResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.sandboxes, in.PodSandboxId)
+	return new(ctrd.RemovePodSandboxResponse), nil
+}
+
+func (f *fakeRuntime) PodSandboxStatus(ctx context.Context, in *ctrd.PodSandboxStatusRequest) (*ctrd.PodSandboxStatusResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	sb, ok := f.sandboxes[in.PodSandboxId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "sandbox not found")
+	}
+	return &ctrd.PodSandboxStatusResponse{Status: &ctrd.PodSandboxStatus{
+		Id:      sb.Id,
+		State:   sb.State,
+		Labels:  sb.Labels,
+		Network: &ctrd.PodSandboxNetworkStatus{Ip: "10.0.0.2", AdditionalIps: []*ctrd.PodIP{{Ip: "fd00::2"}}},
+	}}, nil
+}
+
+func (f *fakeRuntime) ListPodSandbox(ctx context.Context, in *ctrd.ListPodSandboxRequest) (*ctrd.ListPodSandboxResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	res := new(ctrd.ListPodSandboxResponse)
+	for _, sb := range f.sandboxes {
+		if in.Filter != nil && in.Filter.State != nil && in.Filter.State.State != sb.State {
+			continue
+		}
+		if in.Filter != nil && !match(sb.Labels, in.Filter.LabelSelector) {
+			continue
+		}
+		res.Items = append(res.Items, sb)
+	}
+	return res, nil
+}
+
+func (f *fakeRuntime) CreateContainer(ctx context.Context, in *ctrd.CreateContainerRequest) (*ctrd.CreateContainerResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	if _, ok := f.sandboxes[in.PodSandboxId]; !ok {
+		return nil, status.Error(codes.NotFound, "sandbox not found")
+	}
+	if in.SandboxConfig == nil {
+		return nil, status.Error(codes.InvalidArgument, "sandbox config is required")
+	}
+	id := f.id("container")
+	f.containers[id] = &ctrd.ContainerStatus{
+		Id:        id,
+		Metadata:  in.Config.Metadata,
+		Image:     in.Config.Image,
+		Labels:    in.Config.Labels,
+		Mounts:    in.Config.Mounts,
+		State:     ctrd.ContainerCreated,
+		CreatedAt: time.Now().UnixNano(),
+		LogPath:   path.Join(in.SandboxConfig.LogDirectory, in.Config.LogPath),
+	}
+	if in.Config.Linux != nil && in.Config.Linux.Resources != nil {
+		f.containers[id].Resources = &ctrd.ContainerResources{Linux: in.Config.Linux.Resources}
+	}
+	f.parents[id] = in.PodSandboxId
+	return &ctrd.CreateContainerResponse{ContainerId: id}, nil
+}
+
+func (f *fakeRuntime) StartContainer(ctx context.Context, in *ctrd.StartContainerRequest) (*ctrd.StartContainerResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	c, ok := f.containers[in.ContainerId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "container not found")
+	}
+	c.State = ctrd.ContainerRunning
+	c.StartedAt = time.Now().UnixNano()
+	f.events <- &ctrd.ContainerEventResponse{ContainerId: c.Id, ContainerEventType: ctrd.ContainerStartedEvent}
+	return new(ctrd.StartContainerResponse), nil
+}
+
+func (f *fakeRuntime) StopContainer(ctx context.Context, in *ctrd.StopContainerRequest) (*ctrd.StopContainerResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	c, ok := f.containers[in.ContainerId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "container not found")
+	}
+	c.State = ctrd.ContainerExited
+	c.ExitCode = 137
+	return new(ctrd.StopContainerResponse), nil
+}
+
+func (f *fakeRuntime) RemoveContainer(ctx context.Context, in *ctrd.RemoveContainerRequest) (*ctrd.RemoveContainerResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.containers, in.ContainerId)
+	delete(f.parents, in.ContainerId)
+	f.events <- &ctrd.ContainerEventResponse{ContainerId: in.ContainerId, ContainerEventType: ctrd.ContainerDeletedEvent}
+	return new(ctrd.RemoveContainerResponse), nil
+}
+
+func (f *fakeRuntime) ListContainers(ctx context.Context, in *ctrd.ListContainersRequest) (*ctrd.ListContainersResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	res := new(ctrd.ListContainersResponse)
+	for id, c := range f.containers {
+		if in.Filter != nil {
+			if in.Filter.Id != "" && in.Filter.Id != id {
+				continue
+			}
+			if in.Filter.PodSandboxId != "" && in.Filter.PodSandboxId != f.parents[id] {
+				continue
+			}
+			if in.Filter.State != nil && in.Filter.State.State != c.State {
+				continue
+			}
+		}
+		res.Containers = append(res.Containers, &ctrd.Container{Id: id, PodSandboxId: f.parents[id], State: c.State, Labels: c.Labels})
+	}
+	return res, nil
+}
+
+func (f *fakeRuntime) ContainerStatus(ctx context.Context, in *ctrd.ContainerStatusRequest) (*ctrd.ContainerStatusResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	c, ok := f.containers[in.ContainerId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "container not found")
+	}
+	res := &ctrd.ContainerStatusResponse{Status: c}
+	if in.Verbose {
+		res.Info = map[string]string{
+			"info": fmt.Sprintf(`{"sandboxID":%q,"config":{"envs":[{"key":"NAME","value":"demo"}],"args":["run"]}}`, f.parents[c.Id]),
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeRuntime) ExecSync(ctx context.Context, in *ctrd.ExecSyncRequest) (*ctrd.ExecSyncResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	c, ok := f.containers[in.ContainerId]
+	if !ok || c.State != ctrd.ContainerRunning {
+		return nil, status.Error(codes.NotFound, "container not running")
+	}
+	if len(in.Cmd) > 0 && in.Cmd[0] == "false" {
+		return &ctrd.ExecSyncResponse{ExitCode: 1}, nil
+	}
+	return new(ctrd.ExecSyncResponse), nil
+}
+
+func (f *fakeRuntime) ContainerStats(ctx context.Context, in *ctrd.ContainerStatsRequest) (*ctrd.ContainerStatsResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	c, ok := f.containers[in.ContainerId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "container not found")
+	}
+	return &ctrd.ContainerStatsResponse{
+		Stats: &ctrd.ContainerStats{
+			Attributes: &ctrd.ContainerAttributes{Id: c.Id, Metadata: c.Metadata},
+			Cpu:        &ctrd.CpuUsage{Timestamp: time.Now().UnixNano(), UsageCoreNanoSeconds: &ctrd.UInt64Value{Value: 1000}},
+			Memory:     &ctrd.MemoryUsage{Timestamp: time.Now().UnixNano(), WorkingSetBytes: &ctrd.UInt64Value{Value: 4096}, RssBytes: &ctrd.UInt64Value{Value: 2048}},
+		},
+	}, nil
+}
+
+func (f *fakeRuntime) GetContainerEvents(in *ctrd.GetEventsRequest, stream ctrd.RuntimeService_GetContainerEventsServer) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-f.events:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func match(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func serve(t *testing.T) (*Runtime, *fakeRuntime, func()) {
+
+	dir, err := ioutil.TempDir("", "containerd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sock := path.Join(dir, "containerd.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := newFakeRuntime()
+	srv := grpc.NewServer()
+	ctrd.RegisterRuntimeServiceServer(srv, fake)
+	go srv.Serve(l)
+
+	r, err := New(Config{Host: fmt.Sprintf("unix://%s", sock), LogDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r, fake, func() {
+		r.client.Close()
+		srv.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func getManifest(pod, name string) *types.ContainerManifest {
+	m := new(types.ContainerManifest)
+	m.Name = name
+	m.Image = "redis:latest"
+	m.Labels = map[string]string{types.ContainerTypeLBC: pod}
+	m.Envs = []string{"NAME=demo"}
+	m.Exec.Command = []string{"run"}
+	m.Binds = []string{"/tmp/data:/data:ro"}
+	return m
+}
+
+func TestContainerLifecycle(t *testing.T) {
+
+	r, fake, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	primary, err := r.Create(ctx, getManifest("test:svc:dp:pod", "pod-primary"))
+	if !assert.NoError(t, err, "create primary container") {
+		return
+	}
+
+	secondary, err := r.Create(ctx, getManifest("test:svc:dp:pod", "pod-secondary"))
+	if !assert.NoError(t, err, "create secondary container") {
+		return
+	}
+
+	assert.Len(t, fake.sandboxes, 1, "containers of one pod should share sandbox")
+	assert.Equal(t, fake.parents[primary], fake.parents[secondary], "containers sandbox mismatch")
+
+	if !assert.NoError(t, r.Start(ctx, primary), "start container") {
+		return
+	}
+
+	c, err := r.Inspect(ctx, primary)
+	if !assert.NoError(t, err, "inspect container") {
+		return
+	}
+
+	assert.Equal(t, "pod-primary", c.Name, "container name")
+	assert.Equal(t, "redis:latest", c.Image, "container image")
+	assert.Equal(t, types.StateStarted, c.State, "container state")
+	assert.Equal(t, "test:svc:dp:pod", c.Pod, "container pod")
+	assert.Equal(t, []string{"NAME=demo"}, c.Envs, "container envs")
+	assert.Equal(t, []string{"run"}, c.Exec.Command, "container command")
+	assert.Equal(t, []string{"/tmp/data:/data:ro"}, c.Binds, "container binds")
+	assert.Equal(t, "10.0.0.2", c.Network.IPAddress, "container ip")
+	assert.Equal(t, "fd00::2", c.Network.IPv6Address, "container ipv6")
+
+	running, err := r.List(ctx, false)
+	if !assert.NoError(t, err, "list running containers") {
+		return
+	}
+	assert.Len(t, running, 1, "running containers count")
+
+	all, err := r.List(ctx, true)
+	if !assert.NoError(t, err, "list all containers") {
+		return
+	}
+	assert.Len(t, all, 2, "containers count")
+
+	if !assert.NoError(t, r.Stop(ctx, primary, nil), "stop container") {
+		return
+	}
+
+	if !assert.NoError(t, r.Wait(ctx, primary), "wait container") {
+		return
+	}
+
+	c, err = r.Inspect(ctx, primary)
+	if !assert.NoError(t, err, "inspect stopped container") {
+		return
+	}
+	assert.Equal(t, types.StatusStopped, c.State, "stopped container state")
+	assert.Equal(t, 137, c.ExitCode, "stopped container exit code")
+
+	if !assert.NoError(t, r.Remove(ctx, primary, true, true), "remove primary container") {
+		return
+	}
+	assert.Len(t, fake.sandboxes, 1, "sandbox should be kept while pod has containers")
+
+	if !assert.NoError(t, r.Remove(ctx, secondary, true, true), "remove secondary container") {
+		return
+	}
+	assert.Len(t, fake.sandboxes, 0, "sandbox should be removed with last container")
+
+	assert.Equal(t, ErrNotSupported, r.Pause(ctx, primary), "pause should not be supported")
+}
+
+func TestContainerExec(t *testing.T) {
+
+	r, _, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	id, err := r.Create(ctx, getManifest("test:svc:dp:pod", "pod-primary"))
+	if !assert.NoError(t, err, "create container") {
+		return
+	}
+
+	if !assert.NoError(t, r.Start(ctx, id), "start container") {
+		return
+	}
+
+	timeout := time.Second
+
+	tests := []struct {
+		name    string
+		id      string
+		cmd     []string
+		code    int
+		wantErr bool
+	}{
+		{
+			name: "command succeeded",
+			id:   id,
+			cmd:  []string{"true"},
+			code: 0,
+		},
+		{
+			name: "command failed",
+			id:   id,
+			cmd:  []string{"false"},
+			code: 1,
+		},
+		{
+			name:    "container not found",
+			id:      "unknown",
+			cmd:     []string{"true"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := r.Exec(ctx, tc.id, tc.cmd, &timeout)
+			if tc.wantErr {
+				assert.Error(t, err, "exec should fail")
+				return
+			}
+
+			if !assert.NoError(t, err, "exec command") {
+				return
+			}
+
+			assert.Equal(t, tc.code, code, "exit code")
+		})
+	}
+}
+
+func TestContainerLogs(t *testing.T) {
+
+	r, _, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	id, err := r.Create(ctx, getManifest("test:svc:dp:logs", "logs"))
+	if !assert.NoError(t, err, "create container") {
+		return
+	}
+
+	res, err := r.client.Runtime.ContainerStatus(ctx, &ctrd.ContainerStatusRequest{ContainerId: id})
+	if !assert.NoError(t, err, "container status") {
+		return
+	}
+
+	lines := "2019-10-06T00:17:09.669794202Z stdout F first line\n" +
+		"2019-10-06T00:17:10.669794202Z stderr F error line\n" +
+		"2019-10-06T00:17:11.669794202Z stdout P partial \n" +
+		"2019-10-06T00:17:11.669794203Z stdout F line\n"
+
+	if err := os.MkdirAll(path.Dir(res.Status.LogPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(res.Status.LogPath, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		stdout bool
+		stderr bool
+		want   string
+	}{
+		{
+			name:   "stdout and stderr",
+			stdout: true,
+			stderr: true,
+			want: "2019-10-06T00:17:09.669794202Z first line\n" +
+				"2019-10-06T00:17:10.669794202Z error line\n" +
+				"2019-10-06T00:17:11.669794202Z partial " +
+				"2019-10-06T00:17:11.669794203Z line\n",
+		},
+		{
+			name:   "stderr only",
+			stderr: true,
+			want:   "2019-10-06T00:17:10.669794202Z error line\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			rc, err := r.Logs(ctx, id, tc.stdout, tc.stderr, false)
+			if !assert.NoError(t, err, "logs stream") {
+				return
+			}
+			defer rc.Close()
+
+			data, err := ioutil.ReadAll(rc)
+			if !assert.NoError(t, err, "logs read") {
+				return
+			}
+
+			var got string
+			for len(data) >= logPrefixLen {
+				size := binary.BigEndian.Uint32(data[logSizeIndex:logPrefixLen])
+				got += string(data[logPrefixLen : logPrefixLen+size])
+				data = data[logPrefixLen+size:]
+			}
+
+			assert.Equal(t, tc.want, got, "logs mismatch")
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+
+	r, _, stop := serve(t)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs := make(chan *types.Container)
+	go r.Subscribe(ctx, cs)
+
+	id, err := r.Create(ctx, getManifest("test:svc:dp:events", "events"))
+	if !assert.NoError(t, err, "create container") {
+		return
+	}
+
+	if !assert.NoError(t, r.Start(ctx, id), "start container") {
+		return
+	}
+
+	select {
+	case c := <-cs:
+		assert.Equal(t, id, c.ID, "started container id")
+		assert.Equal(t, types.StateStarted, c.State, "started container state")
+	case <-time.After(5 * time.Second):
+		t.Fatal("start event timeout")
+	}
+
+	if !assert.NoError(t, r.Remove(ctx, id, true, true), "remove container") {
+		return
+	}
+
+	select {
+	case c := <-cs:
+		assert.Equal(t, id, c.ID, "removed container id")
+		assert.Equal(t, types.StateDestroyed, c.State, "removed container state")
+	case <-time.After(5 * time.Second):
+		t.Fatal("remove event timeout")
+	}
+}
+
+func TestContainerStats(t *testing.T) {
+
+	r, _, stop := serve(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	m := getManifest("test:svc:dp:pod", "pod-primary")
+	m.Resources.Limits.RAM = 512 * 1024 * 1024
+
+	id, err := r.Create(ctx, m)
+	if !assert.NoError(t, err, "create container") {
+		return
+	}
+
+	stats, err := r.Stats(ctx, id)
+	if !assert.NoError(t, err, "get container stats") {
+		return
+	}
+
+	assert.Equal(t, id, stats.ID, "container id")
+	assert.Equal(t, "pod-primary", stats.Name, "container name")
+	assert.Equal(t, uint64(1000), stats.CPU.Total, "cpu total")
+	assert.Equal(t, uint64(2048), stats.Memory.RSS, "memory rss")
+	assert.Equal(t, uint64(512*1024*1024), stats.Memory.Limit, "memory limit")
+
+	_, err = r.Stats(ctx, "unknown")
+	assert.Error(t, err, "stats for unknown container should fail")
+}
+
+func TestGetSecurityContext(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		security types.SpecTemplateContainerSecurity
+		want     *ctrd.LinuxContainerSecurityContext
+	}{
+		{
+			name:     "default security context",
+			security: types.SpecTemplateContainerSecurity{},
+			want:     &ctrd.LinuxContainerSecurityContext{},
+		},
+		{
+			name: "group without user runs as root user",
+			security: types.SpecTemplateContainerSecurity{
+				Group: 1000,
+			},
+			want: &ctrd.LinuxContainerSecurityContext{
+				RunAsUser:  &ctrd.Int64Value{Value: 0},
+				RunAsGroup: &ctrd.Int64Value{Value: 1000},
+			},
+		},
+		{
+			name: "full security context",
+			security: types.SpecTemplateContainerSecurity{
+				User:   1000,
+				Group:  1000,
+				Groups: []int{10, 20},
+				Capabilities: types.SpecTemplateContainerSecurityCapabilities{
+					Add:  []string{"NET_ADMIN"},
+					Drop: []string{"ALL"},
+				},
+				ReadOnlyRootfs:  true,
+				NoNewPrivileges: true,
+				LinuxOptions: types.SpecTemplateContainerSecurityLinuxOptions{
+					Level:    "s0:c123,c456",
+					Seccomp:  "runtime/default",
+					AppArmor: "localhost/app",
+				},
+			},
+			want: &ctrd.LinuxContainerSecurityContext{
+				RunAsUser:          &ctrd.Int64Value{Value: 1000},
+				RunAsGroup:         &ctrd.Int64Value{Value: 1000},
+				SupplementalGroups: []int64{10, 20},
+				Capabilities: &ctrd.Capability{
+					AddCapabilities:  []string{"NET_ADMIN"},
+					DropCapabilities: []string{"ALL"},
+				},
+				ReadonlyRootfs:     true,
+				NoNewPrivs:         true,
+				SelinuxOptions:     &ctrd.SELinuxOption{Level: "s0:c123,c456"},
+				SeccompProfilePath: "runtime/default",
+				ApparmorProfile:    "localhost/app",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, GetSecurityContext(tc.security), "security context")
+		})
+	}
+}
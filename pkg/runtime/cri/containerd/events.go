@@ -0,0 +1,79 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"io"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r *Runtime) Subscribe(ctx context.Context, container chan *types.Container) error {
+
+	log.Debugf("%s:subscribe:> create new event listener subscribe", logPrefix)
+
+	stream, err := r.client.Runtime.GetContainerEvents(ctx, new(ctrd.GetEventsRequest))
+	if err != nil {
+		log.Errorf("%s:subscribe:> can not subscribe to containerd events err: %v", logPrefix, err)
+		return err
+	}
+
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF || status.Code(err) == codes.Canceled {
+				log.Warnf("%s:subscribe:> event stream closed: %v", logPrefix, err)
+				return nil
+			}
+			log.Errorf("%s:subscribe:> event listening err: %v", logPrefix, err)
+			return err
+		}
+
+		if len(e.ContainerId) == 0 {
+			continue
+		}
+
+		log.Debugf("%s:subscribe:> event type: %d container: %s", logPrefix, e.ContainerEventType, e.ContainerId)
+
+		if e.ContainerEventType == ctrd.ContainerDeletedEvent {
+			c := new(types.Container)
+			c.ID = e.ContainerId
+			c.State = types.StateDestroyed
+			container <- c
+			continue
+		}
+
+		c, err := r.Inspect(ctx, e.ContainerId)
+		if err != nil {
+			log.Errorf("%s:subscribe:> container inspect err: %v", logPrefix, err)
+			continue
+		}
+
+		if c == nil {
+			continue
+		}
+
+		container <- c
+	}
+}
@@ -0,0 +1,146 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hpcloud/tail"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+)
+
+const (
+	// These parameters should be equal to docker stdcopy format
+	// so consumers are able to read logs the same way for all runtimes
+	logStreamStdout = 0x1
+	logStreamStderr = 0x2
+	logPrefixLen    = 8
+	logSizeIndex    = 4
+
+	logTagPartial = "P"
+)
+
+func (r *Runtime) Logs(ctx context.Context, ID string, stdout, stderr, follow bool) (io.ReadCloser, error) {
+
+	res, err := r.client.Runtime.ContainerStatus(ctx, &ctrd.ContainerStatusRequest{ContainerId: ID})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status == nil || len(res.Status.LogPath) == 0 {
+		return nil, fmt.Errorf("container %s log path not found", ID)
+	}
+
+	t, err := tail.TailFile(res.Status.LogPath, tail.Config{
+		Follow:    follow,
+		ReOpen:    follow,
+		MustExist: true,
+		Logger:    tail.DiscardingLogger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer t.Cleanup()
+
+		for {
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				pw.CloseWithError(ctx.Err())
+				return
+			case line, ok := <-t.Lines:
+
+				if !ok {
+					pw.Close()
+					return
+				}
+
+				if line.Err != nil {
+					log.Errorf("%s:logs:> read container %s log err: %v", logPrefix, ID, line.Err)
+					continue
+				}
+
+				stream, data, ok := parseLogLine(line.Text)
+				if !ok {
+					continue
+				}
+
+				if (stream == logStreamStdout && !stdout) || (stream == logStreamStderr && !stderr) {
+					continue
+				}
+
+				if _, err := pw.Write(frame(stream, data)); err != nil {
+					t.Stop()
+					return
+				}
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+// parseLogLine parses cri log format line: "<timestamp> <stream> <tag> <message>"
+// and returns stream type and "<timestamp> <message>" data
+func parseLogLine(line string) (byte, string, bool) {
+
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) < 3 {
+		return 0, "", false
+	}
+
+	var stream byte
+	switch parts[1] {
+	case "stdout":
+		stream = logStreamStdout
+	case "stderr":
+		stream = logStreamStderr
+	default:
+		return 0, "", false
+	}
+
+	var msg string
+	if len(parts) == 4 {
+		msg = parts[3]
+	}
+
+	data := fmt.Sprintf("%s %s", parts[0], msg)
+	if parts[2] != logTagPartial {
+		data += "\n"
+	}
+
+	return stream, data, true
+}
+
+func frame(stream byte, data string) []byte {
+	buf := make([]byte, logPrefixLen+len(data))
+	buf[0] = stream
+	binary.BigEndian.PutUint32(buf[logSizeIndex:logPrefixLen], uint32(len(data)))
+	copy(buf[logPrefixLen:], data)
+	return buf
+}
@@ -0,0 +1,74 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"sync"
+	"time"
+
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+)
+
+const (
+	logLevel  = 5
+	logPrefix = "runtime:containerd"
+
+	defaultLogDir      = "/var/log/lastbackend/pods"
+	defaultStopTimeout = 10 * time.Second
+)
+
+type Runtime struct {
+	client *ctrd.Client
+
+	logs    string
+	handler string
+
+	lock      sync.Mutex
+	sandboxes map[string]*ctrd.PodSandboxConfig
+}
+
+type Config struct {
+	Host    string
+	LogDir  string
+	Handler string
+	Timeout time.Duration
+}
+
+func New(cfg Config) (*Runtime, error) {
+
+	var (
+		err error
+		r   = new(Runtime)
+	)
+
+	r.client, err = ctrd.New(cfg.Host, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logs = defaultLogDir
+	if len(cfg.LogDir) != 0 {
+		r.logs = cfg.LogDir
+	}
+
+	r.handler = cfg.Handler
+	r.sandboxes = make(map[string]*ctrd.PodSandboxConfig, 0)
+
+	return r, nil
+}
@@ -0,0 +1,149 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	ctrd "github.com/lastbackend/lastbackend/pkg/util/containerd"
+)
+
+// sandbox returns ready pod sandbox for container manifest or creates new one.
+// All containers of the same pod share one sandbox, so network namespace is shared
+// the same way as docker "container:<primary>" network mode does.
+func (r *Runtime) sandbox(ctx context.Context, manifest *types.ContainerManifest) (string, *ctrd.PodSandboxConfig, error) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	pod := manifest.Labels[types.ContainerTypeLBC]
+
+	res, err := r.client.Runtime.ListPodSandbox(ctx, &ctrd.ListPodSandboxRequest{
+		Filter: &ctrd.PodSandboxFilter{
+			State:         &ctrd.PodSandboxStateValue{State: ctrd.SandboxReady},
+			LabelSelector: map[string]string{types.ContainerTypeLBC: pod},
+		},
+	})
+	if err != nil {
+		return types.EmptyString, nil, err
+	}
+
+	if len(res.Items) != 0 {
+		cfg, ok := r.sandboxes[pod]
+		if !ok {
+			cfg = GetSandboxConfig(r.logs, manifest)
+			r.sandboxes[pod] = cfg
+		}
+		return res.Items[0].Id, cfg, nil
+	}
+
+	cfg := GetSandboxConfig(r.logs, manifest)
+
+	log.V(logLevel).Debugf("%s:sandbox:> create sandbox for pod %s", logPrefix, pod)
+
+	sb, err := r.client.Runtime.RunPodSandbox(ctx, &ctrd.RunPodSandboxRequest{
+		Config:         cfg,
+		RuntimeHandler: r.handler,
+	})
+	if err != nil {
+		return types.EmptyString, nil, err
+	}
+
+	r.sandboxes[pod] = cfg
+
+	return sb.PodSandboxId, cfg, nil
+}
+
+// sandboxClean stops and removes pod sandbox if there are no containers left in it
+func (r *Runtime) sandboxClean(ctx context.Context, id string) error {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	res, err := r.client.Runtime.ListContainers(ctx, &ctrd.ListContainersRequest{
+		Filter: &ctrd.ContainerFilter{PodSandboxId: id},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(res.Containers) != 0 {
+		return nil
+	}
+
+	st, err := r.client.Runtime.PodSandboxStatus(ctx, &ctrd.PodSandboxStatusRequest{PodSandboxId: id})
+	if err != nil {
+		return err
+	}
+
+	log.V(logLevel).Debugf("%s:sandbox:> remove empty sandbox %s", logPrefix, id)
+
+	if _, err := r.client.Runtime.StopPodSandbox(ctx, &ctrd.StopPodSandboxRequest{PodSandboxId: id}); err != nil {
+		return err
+	}
+
+	if _, err := r.client.Runtime.RemovePodSandbox(ctx, &ctrd.RemovePodSandboxRequest{PodSandboxId: id}); err != nil {
+		return err
+	}
+
+	if st.Status != nil {
+		delete(r.sandboxes, st.Status.Labels[types.ContainerTypeLBC])
+	}
+
+	return nil
+}
+
+// sandboxIP returns sandbox IPv4 and IPv6 addresses,
+// dual stack sandbox reports second address in additional ips
+func (r *Runtime) sandboxIP(ctx context.Context, id string) (string, string) {
+
+	var ip, ip6 string
+
+	st, err := r.client.Runtime.PodSandboxStatus(ctx, &ctrd.PodSandboxStatusRequest{PodSandboxId: id})
+	if err != nil {
+		log.Warnf("%s:sandbox:> can not get sandbox %s status: %v", logPrefix, id, err)
+		return ip, ip6
+	}
+
+	if st.Status == nil || st.Status.Network == nil {
+		return ip, ip6
+	}
+
+	ips := []string{st.Status.Network.Ip}
+	for _, a := range st.Status.Network.AdditionalIps {
+		ips = append(ips, a.Ip)
+	}
+
+	for _, a := range ips {
+		addr := net.ParseIP(a)
+		switch {
+		case addr == nil:
+			continue
+		case addr.To4() != nil && ip == types.EmptyString:
+			ip = a
+		case addr.To4() == nil && ip6 == types.EmptyString:
+			ip6 = a
+		}
+	}
+
+	return ip, ip6
+}
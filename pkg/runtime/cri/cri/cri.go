@@ -22,14 +22,16 @@ import (
 	"fmt"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cri"
+	"github.com/lastbackend/lastbackend/pkg/runtime/cri/containerd"
 	"github.com/lastbackend/lastbackend/pkg/runtime/cri/docker"
 	"github.com/spf13/viper"
 )
 
 const (
-	logLevel     = 5
-	dockerDriver = "docker"
-	runcDriver   = "runc"
+	logLevel         = 5
+	dockerDriver     = "docker"
+	containerdDriver = "containerd"
+	runcDriver       = "runc"
 )
 
 func New(v *viper.Viper) (cri.CRI, error) {
@@ -40,6 +42,7 @@ func New(v *viper.Viper) (cri.CRI, error) {
 		cfg := docker.Config{}
 		cfg.Host = v.GetString("container.cri.docker.host")
 		cfg.Version = v.GetString("container.cri.docker.version")
+		cfg.SeccompProfileRoot = v.GetString("container.cri.docker.seccomp_profile_root")
 
 		if v.IsSet("container.cri.docker.tls.verify") && v.GetBool("container.cri.docker.tls.verify") {
 			cfg.TLS = new(docker.TLSConfig)
@@ -49,6 +52,15 @@ func New(v *viper.Viper) (cri.CRI, error) {
 		}
 
 		return docker.New(cfg)
+	case containerdDriver:
+		log.V(logLevel).Debugf("Use containerd runtime interface for cri")
+
+		cfg := containerd.Config{}
+		cfg.Host = v.GetString("container.cri.containerd.host")
+		cfg.LogDir = v.GetString("container.cri.containerd.log_dir")
+		cfg.Handler = v.GetString("container.cri.containerd.handler")
+
+		return containerd.New(cfg)
 	default:
 		return nil, fmt.Errorf("container runtime <%s> interface not supported", v.GetString("container.cri.type"))
 	}
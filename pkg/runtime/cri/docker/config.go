@@ -26,7 +26,16 @@ import (
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/go-connections/nat"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
+	"strings"
+)
+
+const (
+	securityProfileUnconfined     = "unconfined"
+	securityProfileRuntimeDefault = "runtime/default"
+	securityProfileLocalhost      = "localhost/"
 )
 
 func GetConfig(manifest *types.ContainerManifest) *container.Config {
@@ -41,7 +50,16 @@ func GetConfig(manifest *types.ContainerManifest) *container.Config {
 		ports[port] = struct{}{}
 	}
 
+	var user string
+	if manifest.Security.User != 0 || manifest.Security.Group != 0 {
+		user = strconv.Itoa(manifest.Security.User)
+		if manifest.Security.Group != 0 {
+			user = fmt.Sprintf("%s:%d", user, manifest.Security.Group)
+		}
+	}
+
 	return &container.Config{
+		User:         user,
 		Hostname:     manifest.Network.Hostname,
 		Domainname:   manifest.Network.Domain,
 		Env:          manifest.Envs,
@@ -55,7 +73,9 @@ func GetConfig(manifest *types.ContainerManifest) *container.Config {
 	}
 }
 
-func GetHostConfig(manifest *types.ContainerManifest) *container.HostConfig {
+// GetHostConfig converts container manifest to docker host config,
+// localhost seccomp profiles are looked up in seccomp profile root directory
+func GetHostConfig(manifest *types.ContainerManifest, seccompRoot string) (*container.HostConfig, error) {
 
 	rPolicy := container.RestartPolicy{
 		Name:              manifest.RestartPolicy.Policy,
@@ -99,6 +119,16 @@ func GetHostConfig(manifest *types.ContainerManifest) *container.HostConfig {
 
 	}
 
+	opts, err := GetSecurityOpt(manifest.Security, seccompRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	for _, g := range manifest.Security.Groups {
+		groups = append(groups, strconv.Itoa(g))
+	}
+
 	cfg := container.HostConfig{
 		Binds:           manifest.Binds,
 		LogConfig:       logC,
@@ -112,6 +142,11 @@ func GetHostConfig(manifest *types.ContainerManifest) *container.HostConfig {
 		Mounts:          mounts,
 		Links:           links,
 		Privileged:      manifest.Security.Privileged,
+		CapAdd:          strslice.StrSlice(manifest.Security.Capabilities.Add),
+		CapDrop:         strslice.StrSlice(manifest.Security.Capabilities.Drop),
+		GroupAdd:        groups,
+		ReadonlyRootfs:  manifest.Security.ReadOnlyRootfs,
+		SecurityOpt:     opts,
 		ExtraHosts:      manifest.ExtraHosts,
 		PublishAllPorts: manifest.PublishAllPorts,
 		AutoRemove:      manifest.AutoRemove,
@@ -123,7 +158,60 @@ func GetHostConfig(manifest *types.ContainerManifest) *container.HostConfig {
 		cfg.DNSSearch = make([]string, 0)
 	}
 
-	return &cfg
+	return &cfg, nil
+}
+
+// GetSecurityOpt converts container security options to docker security options,
+// localhost seccomp profile is read from seccomp profile root directory and passed to docker as json
+func GetSecurityOpt(security types.SpecTemplateContainerSecurity, seccompRoot string) ([]string, error) {
+
+	var (
+		opts  = make([]string, 0)
+		linux = security.LinuxOptions
+	)
+
+	if security.NoNewPrivileges {
+		opts = append(opts, "no-new-privileges")
+	}
+
+	switch {
+	case linux.Seccomp == types.EmptyString, linux.Seccomp == securityProfileRuntimeDefault:
+	case linux.Seccomp == securityProfileUnconfined:
+		opts = append(opts, fmt.Sprintf("seccomp=%s", securityProfileUnconfined))
+	case strings.HasPrefix(linux.Seccomp, securityProfileLocalhost):
+		profile, err := seccompProfile(seccompRoot, strings.TrimPrefix(linux.Seccomp, securityProfileLocalhost))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, fmt.Sprintf("seccomp=%s", profile))
+	default:
+		return nil, fmt.Errorf("unsupported seccomp profile: %s", linux.Seccomp)
+	}
+
+	switch {
+	case linux.AppArmor == types.EmptyString, linux.AppArmor == securityProfileRuntimeDefault:
+	case linux.AppArmor == securityProfileUnconfined:
+		opts = append(opts, fmt.Sprintf("apparmor=%s", securityProfileUnconfined))
+	case strings.HasPrefix(linux.AppArmor, securityProfileLocalhost):
+		opts = append(opts, fmt.Sprintf("apparmor=%s", strings.TrimPrefix(linux.AppArmor, securityProfileLocalhost)))
+	default:
+		return nil, fmt.Errorf("unsupported apparmor profile: %s", linux.AppArmor)
+	}
+
+	if linux.User != types.EmptyString {
+		opts = append(opts, fmt.Sprintf("label=user:%s", linux.User))
+	}
+	if linux.Role != types.EmptyString {
+		opts = append(opts, fmt.Sprintf("label=role:%s", linux.Role))
+	}
+	if linux.Type != types.EmptyString {
+		opts = append(opts, fmt.Sprintf("label=type:%s", linux.Type))
+	}
+	if linux.Level != types.EmptyString {
+		opts = append(opts, fmt.Sprintf("label=level:%s", linux.Level))
+	}
+
+	return opts, nil
 }
 
 func GetNetworkConfig(manifest *types.ContainerManifest) *network.NetworkingConfig {
@@ -132,3 +220,29 @@ func GetNetworkConfig(manifest *types.ContainerManifest) *network.NetworkingConf
 
 	return cfg
 }
+
+// seccompProfile reads localhost seccomp profile from profile root directory,
+// profile path is relative to the root and can not leave it
+func seccompProfile(root, name string) ([]byte, error) {
+
+	if root == types.EmptyString {
+		return nil, fmt.Errorf("seccomp profile root is not configured")
+	}
+
+	if name == types.EmptyString || filepath.IsAbs(name) {
+		return nil, fmt.Errorf("invalid seccomp profile path: %s", name)
+	}
+
+	for _, p := range strings.Split(filepath.ToSlash(name), "/") {
+		if p == ".." {
+			return nil, fmt.Errorf("invalid seccomp profile path: %s", name)
+		}
+	}
+
+	profile, err := ioutil.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		return nil, fmt.Errorf("can not read seccomp profile: %s", err.Error())
+	}
+
+	return profile, nil
+}
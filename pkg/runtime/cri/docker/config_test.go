@@ -0,0 +1,91 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSecurityOptSeccompLocalhost(t *testing.T) {
+
+	root, err := ioutil.TempDir("", "seccomp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(root)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "profile.json"), []byte(`{"defaultAction":"SCMP_ACT_ALLOW"}`), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(root), "outside.json"), []byte(`{}`), 0600))
+	defer os.Remove(filepath.Join(filepath.Dir(root), "outside.json"))
+
+	var tests = []struct {
+		name    string
+		root    string
+		profile string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "check profile inside root",
+			root:    root,
+			profile: "localhost/profile.json",
+			want:    []string{`seccomp={"defaultAction":"SCMP_ACT_ALLOW"}`},
+		},
+		{
+			name:    "check profile leaving root",
+			root:    root,
+			profile: "localhost/../outside.json",
+			wantErr: true,
+		},
+		{
+			name:    "check absolute profile path",
+			root:    root,
+			profile: "localhost/" + filepath.Join(root, "profile.json"),
+			wantErr: true,
+		},
+		{
+			name:    "check profile root not configured",
+			profile: "localhost/profile.json",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			security := types.SpecTemplateContainerSecurity{}
+			security.LinuxOptions.Seccomp = tc.profile
+
+			opts, err := GetSecurityOpt(security, tc.root)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, opts)
+		})
+	}
+}
@@ -20,7 +20,9 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"io/ioutil"
 	"strconv"
 	"strings"
 	"time"
@@ -61,10 +63,15 @@ func (r *Runtime) List(ctx context.Context, all bool) ([]*types.Container, error
 
 func (r *Runtime) Create(ctx context.Context, manifest *types.ContainerManifest) (string, error) {
 
+	hc, err := GetHostConfig(manifest, r.seccompRoot)
+	if err != nil {
+		return "", err
+	}
+
 	c, err := r.client.ContainerCreate(
 		ctx,
 		GetConfig(manifest),
-		GetHostConfig(manifest),
+		hc,
 		GetNetworkConfig(manifest),
 		manifest.Name,
 	)
@@ -143,6 +150,7 @@ func (r *Runtime) Inspect(ctx context.Context, ID string) (*types.Container, err
 
 	c.Network.Gateway = info.NetworkSettings.Gateway
 	c.Network.IPAddress = info.NetworkSettings.IPAddress
+	c.Network.IPv6Address = info.NetworkSettings.GlobalIPv6Address
 
 	c.Network.Ports = make([]*types.SpecTemplateContainerPort, 0)
 	for key, val := range info.HostConfig.PortBindings {
@@ -204,6 +212,114 @@ func (r *Runtime) Wait(ctx context.Context, ID string) error {
 	}
 }
 
+// Exec runs command in container and waits until it exits, exit code is returned
+func (r *Runtime) Exec(ctx context.Context, ID string, cmd []string, timeout *time.Duration) (int, error) {
+
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	exec, err := r.client.ContainerExecCreate(ctx, ID, docker.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := r.client.ContainerExecAttach(ctx, exec.ID, docker.ExecStartCheck{})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(ioutil.Discard, resp.Reader)
+		done <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	info, err := r.client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.ExitCode, nil
+}
+
+// Stats returns container resources usage snapshot
+func (r *Runtime) Stats(ctx context.Context, ID string) (*types.ContainerStats, error) {
+
+	res, err := r.client.ContainerStats(ctx, ID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	info := new(docker.StatsJSON)
+	if err := json.NewDecoder(res.Body).Decode(info); err != nil {
+		return nil, err
+	}
+
+	stats := new(types.ContainerStats)
+	stats.ID = ID
+	stats.Name = strings.Replace(info.Name, "/", "", 1)
+	stats.Timestamp = info.Read
+
+	stats.CPU.Total = info.CPUStats.CPUUsage.TotalUsage
+
+	var (
+		cpuDelta    = float64(info.CPUStats.CPUUsage.TotalUsage) - float64(info.PreCPUStats.CPUUsage.TotalUsage)
+		systemDelta = float64(info.CPUStats.SystemUsage) - float64(info.PreCPUStats.SystemUsage)
+		cpus        = float64(info.CPUStats.OnlineCPUs)
+	)
+
+	if cpus == 0 {
+		cpus = float64(len(info.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPU.Percent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	stats.Memory.Limit = info.MemoryStats.Limit
+	if rss, ok := info.MemoryStats.Stats["rss"]; ok {
+		stats.Memory.RSS = rss
+	} else if inactive, ok := info.MemoryStats.Stats["inactive_file"]; ok && inactive < info.MemoryStats.Usage {
+		stats.Memory.RSS = info.MemoryStats.Usage - inactive
+	} else {
+		stats.Memory.RSS = info.MemoryStats.Usage
+	}
+
+	for _, n := range info.Networks {
+		stats.Network.Read += n.RxBytes
+		stats.Network.Write += n.TxBytes
+	}
+
+	for _, b := range info.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(b.Op) {
+		case "read":
+			stats.Block.Read += b.Value
+		case "write":
+			stats.Block.Write += b.Value
+		}
+	}
+
+	return stats, nil
+}
+
 // Copy - https://docs.docker.com/engine/api/v1.29/#operation/PutContainerArchive
 func (r *Runtime) Copy(ctx context.Context, ID, path string, content io.Reader) error {
 	return r.client.CopyToContainer(ctx, ID, path, content, docker.CopyToContainerOptions{
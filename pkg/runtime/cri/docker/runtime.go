@@ -31,13 +31,16 @@ const (
 )
 
 type Runtime struct {
-	client *client.Client
+	client      *client.Client
+	seccompRoot string
 }
 
 type Config struct {
 	Host    string
 	Version string
 	TLS     *TLSConfig
+	// SeccompProfileRoot - directory with localhost seccomp profiles
+	SeccompProfileRoot string
 }
 
 type TLSConfig struct {
@@ -54,6 +57,8 @@ func New(cfg Config) (*Runtime, error) {
 		clientOptions = make([]client.Opt, 0)
 	)
 
+	r.seccompRoot = cfg.SeccompProfileRoot
+
 	host := client.DefaultDockerHost
 	if len(cfg.Host) != 0 {
 		host = cfg.Host
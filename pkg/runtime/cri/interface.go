@@ -39,5 +39,7 @@ type CRI interface {
 	Logs(ctx context.Context, ID string, stdout, stderr, follow bool) (io.ReadCloser, error)
 	Copy(ctx context.Context, ID, path string, content io.Reader) error
 	Wait(ctx context.Context, ID string) error
+	Exec(ctx context.Context, ID string, cmd []string, timeout *time.Duration) (int, error)
+	Stats(ctx context.Context, ID string) (*types.ContainerStats, error)
 	Subscribe(ctx context.Context, container chan *types.Container) error
 }
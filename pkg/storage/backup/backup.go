@@ -0,0 +1,266 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "storage:backup"
+)
+
+// Version of archive format, archives with greater version can not be restored
+const Version = 1
+
+var (
+	ErrKeyIsRequired      = errors.New("encryption key is required")
+	ErrKeyIsInvalid       = errors.New("encryption key is invalid")
+	ErrVersionUnsupported = errors.New("archive version is not supported")
+)
+
+// Archive is a snapshot of cluster state
+type Archive struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	// Salt is used to derive secrets encryption key
+	Salt        []byte        `json:"salt,omitempty"`
+	Collections []*Collection `json:"collections"`
+}
+
+type Collection struct {
+	Kind      string  `json:"kind"`
+	Encrypted bool    `json:"encrypted,omitempty"`
+	Items     []*Item `json:"items"`
+}
+
+type Item struct {
+	Name string          `json:"name"`
+	Data json.RawMessage `json:"data,omitempty"`
+	// Cipher is an encrypted data of item in encrypted collection
+	Cipher []byte `json:"cipher,omitempty"`
+}
+
+type kind struct {
+	name       string
+	collection func(c types.Collection) string
+	encrypted  bool
+}
+
+// kinds are collections included into archive, in order they are restored:
+// objects are created after objects they depend on.
+// Runtime objects like pods, endpoints and manifests are not included,
+// because they are created by controllers from restored objects
+var kinds = []kind{
+	{name: "node", collection: func(c types.Collection) string { return c.Node().Info() }},
+	{name: "namespace", collection: func(c types.Collection) string { return c.Namespace() }},
+	{name: "secret", collection: func(c types.Collection) string { return c.Secret() }, encrypted: true},
+	{name: "config", collection: func(c types.Collection) string { return c.Config() }},
+	{name: "volume", collection: func(c types.Collection) string { return c.Volume() }},
+	{name: "service", collection: func(c types.Collection) string { return c.Service() }},
+	{name: "job", collection: func(c types.Collection) string { return c.Job() }},
+	{name: "route", collection: func(c types.Collection) string { return c.Route() }},
+	{name: "policy", collection: func(c types.Collection) string { return c.Policy() }},
+}
+
+type items struct {
+	Items map[string]json.RawMessage
+}
+
+// Backup exports cluster state from storage into archive.
+// Secrets are encrypted with key in archive
+func Backup(ctx context.Context, stg storage.Storage, w io.Writer, key string) error {
+
+	log.V(logLevel).Debugf("%s:backup:> create archive", logPrefix)
+
+	if key == "" {
+		return ErrKeyIsRequired
+	}
+
+	var (
+		archive = new(Archive)
+		err     error
+	)
+
+	archive.Version = Version
+	archive.Created = time.Now()
+
+	archive.Salt, err = newSalt()
+	if err != nil {
+		return err
+	}
+
+	enc, err := newEncryptor(key, archive.Salt)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range kinds {
+
+		list := &items{Items: make(map[string]json.RawMessage)}
+		if err := stg.Map(ctx, k.collection(stg.Collection()), "", list, nil); err != nil {
+			log.V(logLevel).Errorf("%s:backup:> get %s list err: %v", logPrefix, k.name, err)
+			return err
+		}
+
+		c := &Collection{Kind: k.name, Encrypted: k.encrypted, Items: make([]*Item, 0)}
+
+		for name, data := range list.Items {
+
+			item := &Item{Name: name}
+
+			if k.encrypted {
+				if item.Cipher, err = enc.encrypt(data); err != nil {
+					return err
+				}
+			} else {
+				item.Data = data
+			}
+
+			c.Items = append(c.Items, item)
+		}
+
+		sort.Slice(c.Items, func(i, j int) bool {
+			return c.Items[i].Name < c.Items[j].Name
+		})
+
+		log.V(logLevel).Debugf("%s:backup:> %s: %d items", logPrefix, k.name, len(c.Items))
+		archive.Collections = append(archive.Collections, c)
+	}
+
+	gz := gzip.NewWriter(w)
+
+	if err := json.NewEncoder(gz).Encode(archive); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// Restore imports cluster state from archive into empty storage.
+// Collections are restored in dependency order, unknown collections are skipped
+func Restore(ctx context.Context, stg storage.Storage, r io.Reader, key string) error {
+
+	log.V(logLevel).Debugf("%s:restore:> restore archive", logPrefix)
+
+	archive, err := read(r)
+	if err != nil {
+		return err
+	}
+
+	collections := make(map[string]*Collection)
+	for _, c := range archive.Collections {
+		collections[c.Kind] = c
+	}
+
+	var enc *encryptor
+
+	for _, c := range archive.Collections {
+
+		if !c.Encrypted || enc != nil {
+			continue
+		}
+
+		if key == "" {
+			return ErrKeyIsRequired
+		}
+
+		if enc, err = newEncryptor(key, archive.Salt); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range kinds {
+
+		list := &items{Items: make(map[string]json.RawMessage)}
+		if err := stg.Map(ctx, k.collection(stg.Collection()), "", list, nil); err != nil {
+			log.V(logLevel).Errorf("%s:restore:> get %s list err: %v", logPrefix, k.name, err)
+			return err
+		}
+
+		if len(list.Items) > 0 {
+			return fmt.Errorf("storage is not empty: %s collection has %d items", k.name, len(list.Items))
+		}
+	}
+
+	// decrypt all items before changes to not restore archive partially with invalid key
+	for _, c := range archive.Collections {
+
+		if !c.Encrypted {
+			continue
+		}
+
+		for _, item := range c.Items {
+			if item.Data, err = enc.decrypt(item.Cipher); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, k := range kinds {
+
+		c, ok := collections[k.name]
+		if !ok {
+			continue
+		}
+
+		for _, item := range c.Items {
+			if err := stg.Put(ctx, k.collection(stg.Collection()), item.Name, item.Data, nil); err != nil {
+				log.V(logLevel).Errorf("%s:restore:> create %s %s err: %v", logPrefix, k.name, item.Name, err)
+				return err
+			}
+		}
+
+		log.V(logLevel).Debugf("%s:restore:> %s: %d items", logPrefix, k.name, len(c.Items))
+	}
+
+	return nil
+}
+
+func read(r io.Reader) (*Archive, error) {
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	archive := new(Archive)
+	if err := json.NewDecoder(gz).Decode(archive); err != nil {
+		return nil, err
+	}
+
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, ErrVersionUnsupported
+	}
+
+	return archive, nil
+}
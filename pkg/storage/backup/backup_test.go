@@ -0,0 +1,195 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package backup_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/storage/backup"
+	"github.com/lastbackend/lastbackend/pkg/storage/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	key    = "secret key"
+	secret = "s3cr3t-token"
+)
+
+func TestBackupRestore(t *testing.T) {
+
+	var (
+		ctx = context.Background()
+		src = getStorage(t)
+		dst = getStorage(t)
+		buf = new(bytes.Buffer)
+	)
+
+	ns := getNamespace("demo")
+	svc := getService("demo", "web")
+	sct := getSecret("demo", "token")
+	pod := getPod("demo", "web", "pod")
+
+	assert.NoError(t, src.Put(ctx, src.Collection().Namespace(), ns.SelfLink().String(), ns, nil))
+	assert.NoError(t, src.Put(ctx, src.Collection().Service(), svc.SelfLink().String(), svc, nil))
+	assert.NoError(t, src.Put(ctx, src.Collection().Secret(), sct.SelfLink().String(), sct, nil))
+	assert.NoError(t, src.Put(ctx, src.Collection().Pod(), pod.SelfLink().String(), pod, nil))
+
+	if !assert.NoError(t, backup.Backup(ctx, src, buf, key)) {
+		return
+	}
+
+	// secrets data should not be stored in archive as plain text
+	gz, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	data, err := ioutil.ReadAll(gz)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Contains(t, string(data), "demo:web", "service is not exported")
+	assert.NotContains(t, string(data), base64.StdEncoding.EncodeToString([]byte(secret)), "secret is not encrypted")
+
+	if !assert.NoError(t, backup.Restore(ctx, dst, bytes.NewReader(buf.Bytes()), key)) {
+		return
+	}
+
+	outNs := new(types.Namespace)
+	if assert.NoError(t, dst.Get(ctx, dst.Collection().Namespace(), ns.SelfLink().String(), outNs, nil)) {
+		assert.Equal(t, ns.Meta.Name, outNs.Meta.Name, "namespace is not restored")
+	}
+
+	outSvc := new(types.Service)
+	if assert.NoError(t, dst.Get(ctx, dst.Collection().Service(), svc.SelfLink().String(), outSvc, nil)) {
+		assert.Equal(t, svc.Spec.Replicas, outSvc.Spec.Replicas, "service is not restored")
+	}
+
+	outSct := new(types.Secret)
+	if assert.NoError(t, dst.Get(ctx, dst.Collection().Secret(), sct.SelfLink().String(), outSct, nil)) {
+		assert.Equal(t, sct.Spec.Data, outSct.Spec.Data, "secret is not restored")
+	}
+
+	// runtime objects are not restored
+	err = dst.Get(ctx, dst.Collection().Pod(), pod.SelfLink().String(), new(types.Pod), nil)
+	assert.Error(t, err, "pod is restored")
+}
+
+func TestRestoreErrors(t *testing.T) {
+
+	var (
+		ctx = context.Background()
+		src = getStorage(t)
+		buf = new(bytes.Buffer)
+	)
+
+	ns := getNamespace("demo")
+	sct := getSecret("demo", "token")
+
+	assert.NoError(t, src.Put(ctx, src.Collection().Namespace(), ns.SelfLink().String(), ns, nil))
+	assert.NoError(t, src.Put(ctx, src.Collection().Secret(), sct.SelfLink().String(), sct, nil))
+
+	assert.Equal(t, backup.ErrKeyIsRequired, backup.Backup(ctx, src, buf, ""))
+
+	if !assert.NoError(t, backup.Backup(ctx, src, buf, key)) {
+		return
+	}
+
+	t.Run("without key", func(t *testing.T) {
+		err := backup.Restore(ctx, getStorage(t), bytes.NewReader(buf.Bytes()), "")
+		assert.Equal(t, backup.ErrKeyIsRequired, err)
+	})
+
+	t.Run("with invalid key", func(t *testing.T) {
+		dst := getStorage(t)
+
+		err := backup.Restore(ctx, dst, bytes.NewReader(buf.Bytes()), "invalid")
+		assert.Equal(t, backup.ErrKeyIsInvalid, err)
+
+		// nothing should be restored with invalid key
+		err = dst.Get(ctx, dst.Collection().Namespace(), ns.SelfLink().String(), new(types.Namespace), nil)
+		assert.Error(t, err, "namespace is restored")
+	})
+
+	t.Run("into not empty storage", func(t *testing.T) {
+		err := backup.Restore(ctx, src, bytes.NewReader(buf.Bytes()), key)
+		assert.Error(t, err, "restored into not empty storage")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		arc := new(bytes.Buffer)
+		gz := gzip.NewWriter(arc)
+		gz.Write([]byte(`{"version":100,"collections":[]}`))
+		gz.Close()
+
+		err := backup.Restore(ctx, getStorage(t), arc, key)
+		assert.Equal(t, backup.ErrVersionUnsupported, err)
+	})
+}
+
+func getStorage(t *testing.T) storage.Storage {
+	stg, err := mock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stg
+}
+
+func getNamespace(name string) *types.Namespace {
+	n := new(types.Namespace)
+	n.Meta.Name = name
+	n.Meta.SelfLink = *types.NewNamespaceSelfLink(name)
+	return n
+}
+
+func getService(namespace, name string) *types.Service {
+	s := new(types.Service)
+	s.Meta.Name = name
+	s.Meta.Namespace = namespace
+	s.Meta.SelfLink = *types.NewServiceSelfLink(namespace, name)
+	s.Spec.Replicas = 2
+	return s
+}
+
+func getSecret(namespace, name string) *types.Secret {
+	s := new(types.Secret)
+	s.Meta.Name = name
+	s.Meta.Namespace = namespace
+	s.Meta.SelfLink = *types.NewSecretSelfLink(namespace, name)
+	s.Spec.Type = types.KindSecretOpaque
+	s.Spec.Data = map[string][]byte{"token": []byte(secret)}
+	return s
+}
+
+func getPod(namespace, service, name string) *types.Pod {
+	p := new(types.Pod)
+	p.Meta.Name = name
+	p.Meta.Namespace = namespace
+	sl, _ := types.NewPodSelfLink(types.KindDeployment, types.NewDeploymentSelfLink(namespace, service, "v1").String(), name)
+	p.Meta.SelfLink = *sl
+	return p
+}
@@ -0,0 +1,89 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const saltSize = 32
+
+// encryptor encrypts archive items with AES-GCM,
+// encryption key is derived from user key with scrypt
+type encryptor struct {
+	aead cipher.AEAD
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func newEncryptor(key string, salt []byte) (*encryptor, error) {
+
+	dk, err := scrypt.Key([]byte(key), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(dk)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptor{aead: aead}, nil
+}
+
+func (c *encryptor) encrypt(data []byte) ([]byte, error) {
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func (c *encryptor) decrypt(data []byte) ([]byte, error) {
+
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, ErrKeyIsInvalid
+	}
+
+	buf, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, ErrKeyIsInvalid
+	}
+
+	return buf, nil
+}
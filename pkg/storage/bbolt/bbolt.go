@@ -0,0 +1,435 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+	"github.com/lastbackend/lastbackend/pkg/util/converter"
+)
+
+const (
+	logLevel     = 6
+	logPrefix    = "storage:bbolt"
+	keySeparator = "/"
+)
+
+// Config of embedded storage
+type Config struct {
+	// Path to database file
+	Path string `json:"path" yaml:"path"`
+	// Prefix of all storage keys
+	Prefix string `json:"prefix" yaml:"prefix"`
+	// History is a count of changes kept for watch requests with revision
+	History int64 `json:"history" yaml:"history"`
+}
+
+type Storage struct {
+	path  string
+	store *store
+}
+
+// database file can be opened only once, so stores are shared inside process
+var stores = struct {
+	sync.Mutex
+	items map[string]*shared
+}{items: make(map[string]*shared)}
+
+type shared struct {
+	store *store
+	count int
+}
+
+func New(config *Config) (*Storage, error) {
+
+	log.V(logLevel).Debug("Bbolt: define storage")
+
+	if config.Path == "" {
+		return nil, errors.New("database file path not set")
+	}
+
+	stores.Lock()
+	defer stores.Unlock()
+
+	s := new(Storage)
+	s.path = config.Path
+
+	if item, ok := stores.items[config.Path]; ok {
+		item.count++
+		s.store = item.store
+		return s, nil
+	}
+
+	st, err := newStore(config)
+	if err != nil {
+		log.Errorf("%s: store initialize err: %v", logPrefix, err)
+		return nil, err
+	}
+
+	stores.items[config.Path] = &shared{store: st, count: 1}
+	s.store = st
+
+	return s, nil
+}
+
+// Close releases database file when it is not used by other storages
+func (s *Storage) Close() error {
+
+	stores.Lock()
+	defer stores.Unlock()
+
+	item, ok := stores.items[s.path]
+	if !ok || item.store != s.store {
+		return nil
+	}
+
+	item.count--
+	if item.count > 0 {
+		return nil
+	}
+
+	delete(stores.items, s.path)
+	return s.store.close()
+}
+
+func (s Storage) Info(ctx context.Context, collection string, name string) (*types.System, error) {
+
+	r := new(types.System)
+
+	rev, err := s.store.info()
+	if err != nil {
+		return r, err
+	}
+
+	r.Storage.Revision = rev
+	return r, nil
+}
+
+func (s Storage) Get(ctx context.Context, collection string, name string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	r, err := s.store.get(keyCreate(collection, name))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(r.Value, obj); err != nil {
+		log.V(logLevel).Errorf("%s:get:> decode data err: %v", logPrefix, err)
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), r.Mod)
+	return nil
+}
+
+func (s Storage) List(ctx context.Context, collection string, query string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	v, err := converter.EnforcePtr(obj)
+	if err != nil {
+		return errors.New(types.ErrStructOutIsNotPointer)
+	}
+
+	f := v.FieldByName("Items")
+	if f.Kind() != reflect.Slice {
+		return errors.New(types.ErrStructOutIsInvalid)
+	}
+
+	if !f.CanSet() {
+		return nil
+	}
+
+	// matched records are paged after decoding, so all records are listed
+	lo := opts
+	if opts != nil && opts.Match != nil {
+		lo = nil
+	}
+
+	prefix := keyCreate(collection, query)
+
+	keys, records, page, err := s.store.list(prefix, lo)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> request err: %v", logPrefix, err)
+		return err
+	}
+
+	items := reflect.MakeSlice(f.Type(), 0, len(records))
+
+	for _, r := range records {
+
+		item := reflect.New(f.Type().Elem())
+		if err := json.Unmarshal(r.Value, item.Interface()); err != nil {
+			log.V(logLevel).Errorf("%s:list:> decode data err: %v", logPrefix, err)
+			return err
+		}
+
+		setRevision(item, r.Mod)
+		items = reflect.Append(items, item.Elem())
+	}
+
+	f.Set(items)
+
+	if opts != nil && opts.Match != nil {
+		page.Storage.Total, page.Storage.Continue, err = types.MatchPage(f, keys, prefix, page.Storage.Revision, opts)
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> match page err: %v", logPrefix, err)
+			return err
+		}
+	}
+
+	setPage(v, page)
+
+	return nil
+}
+
+func (s Storage) Map(ctx context.Context, collection string, query string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	v, err := converter.EnforcePtr(obj)
+	if err != nil {
+		return errors.New(types.ErrStructOutIsNotPointer)
+	}
+
+	f := v.FieldByName("Items")
+	if f.Kind() != reflect.Map {
+		return errors.New(types.ErrStructOutIsInvalid)
+	}
+
+	if !f.CanSet() {
+		return nil
+	}
+
+	keys, records, page, err := s.store.list(keyCreate(collection, query), opts)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:map:> request err: %v", logPrefix, err)
+		return err
+	}
+
+	items := reflect.MakeMap(f.Type())
+
+	for i, r := range records {
+
+		item := reflect.New(f.Type().Elem())
+		if err := json.Unmarshal(r.Value, item.Interface()); err != nil {
+			log.V(logLevel).Errorf("%s:map:> decode data err: %v", logPrefix, err)
+			return err
+		}
+
+		setRevision(item, r.Mod)
+
+		ks := strings.Split(keys[i], keySeparator)
+		items.SetMapIndex(reflect.ValueOf(ks[len(ks)-1]), item.Elem())
+	}
+
+	f.Set(items)
+	setPage(v, page)
+
+	return nil
+}
+
+func (s Storage) Put(ctx context.Context, collection string, name string, obj interface{}, opts *types.Opts) error {
+
+	if opts == nil {
+		opts = new(types.Opts)
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> encode data err: %v", logPrefix, err)
+		return err
+	}
+
+	rev, err := s.store.put(keyCreate(collection, name), data, opts.Ttl)
+	if err != nil {
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), rev)
+	return nil
+}
+
+func (s Storage) Set(ctx context.Context, collection string, name string, obj interface{}, opts *types.Opts) error {
+
+	var (
+		rev   *int64
+		force bool
+		ttl   uint64
+	)
+
+	if opts != nil {
+		rev = opts.Rev
+		force = opts.Force
+		ttl = opts.Ttl
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> encode data err: %v", logPrefix, err)
+		return err
+	}
+
+	mod, err := s.store.set(keyCreate(collection, name), data, ttl, force, rev)
+	if err != nil {
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), mod)
+	return nil
+}
+
+func (s Storage) Del(ctx context.Context, collection string, name string) error {
+
+	if name == "" {
+		return s.store.del(keyCreate(collection, ""), true, nil)
+	}
+
+	return s.store.del(keyCreate(collection, name), false, nil)
+}
+
+// DelRev removes object only if it was not changed since revision
+func (s Storage) DelRev(ctx context.Context, collection string, name string, rev int64) error {
+	return s.store.del(keyCreate(collection, name), false, &rev)
+}
+
+func (s Storage) Watch(ctx context.Context, collection string, event chan *types.WatcherEvent, opts *types.Opts) error {
+
+	log.V(logLevel).Debugf("%s:> watch %s", logPrefix, collection)
+
+	const filter = `\b.+\/(.+)\b`
+
+	var rev *int64
+	if opts != nil {
+		rev = opts.Rev
+	}
+
+	watcher, err := s.store.watch(collection, rev)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:> watch err: %v", logPrefix, err)
+		return err
+	}
+	defer s.store.unwatch(watcher)
+
+	r, _ := regexp.Compile(filter)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.V(logLevel).Debugf("%s:> the user interrupted watch", logPrefix)
+			return nil
+		case <-watcher.notify:
+
+			for _, res := range watcher.pop() {
+
+				key := path.Join(string(s.store.root), res.Key)
+
+				keys := r.FindStringSubmatch(key)
+				if len(keys) == 0 {
+					continue
+				}
+
+				e := new(types.WatcherEvent)
+				e.Action = res.Type
+				e.SelfLink = keys[1]
+				e.Storage.Key = key
+				e.Storage.Revision = res.Rev
+				e.Data = res.Object
+
+				match := strings.Split(key, ":")
+
+				if len(match) > 0 {
+					e.Name = match[len(match)-1]
+				} else {
+					e.Name = keys[0]
+				}
+
+				select {
+				case event <- e:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (s Storage) Filter() types.Filter {
+	return new(Filter)
+}
+
+func (s Storage) Key() types.Key {
+	return new(Key)
+}
+
+func (s Storage) Collection() types.Collection {
+	return new(Collection)
+}
+
+func setPage(v reflect.Value, page types.System) {
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(page.System) {
+		return
+	}
+
+	f.Set(reflect.ValueOf(page.System))
+}
+
+// setRevision sets storage revision into System field of object if it exists
+func setRevision(v reflect.Value, rev int64) {
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(types.System{}.System) {
+		return
+	}
+
+	f.FieldByName("Storage").FieldByName("Revision").SetInt(rev)
+}
+
+func keyCreate(val ...string) string {
+	return strings.Join(val, keySeparator)
+}
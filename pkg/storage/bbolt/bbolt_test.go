@@ -0,0 +1,354 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package bbolt_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	dtypes "github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/storage/bbolt"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_Get(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageGetAssets(t, stg)
+}
+
+func TestStorage_List(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageListAssets(t, stg)
+}
+
+func TestStorage_Page(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StoragePageAssets(t, stg)
+}
+
+func TestStorage_Map(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageMapAssets(t, stg)
+}
+
+func TestStorage_Put(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StoragePutAssets(t, stg)
+}
+
+func TestStorage_Set(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageSetAssets(t, stg)
+}
+
+func TestStorage_Revision(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageRevisionAssets(t, stg)
+}
+
+func TestStorage_Del(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageDelAssets(t, stg)
+}
+
+func TestStorage_DelRev(t *testing.T) {
+	stg, done := getStorage(t)
+	defer done()
+	storage.StorageDelRevAssets(t, stg)
+}
+
+func TestStorage_Watch(t *testing.T) {
+
+	stg, done := getStorage(t)
+	defer done()
+
+	type obj struct {
+		dtypes.System
+		Name string `json:"name"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	item := &obj{Name: "demo"}
+	if !assert.NoError(t, stg.Put(ctx, stg.Collection().Test(), "demo", item, nil)) {
+		return
+	}
+
+	rev := item.Storage.Revision
+
+	item.Name = "test"
+	if !assert.NoError(t, stg.Set(ctx, stg.Collection().Test(), "demo", item, nil)) {
+		return
+	}
+
+	// changes made after revision should be sent first
+	event := make(chan *types.WatcherEvent)
+	opts := storage.GetOpts()
+	opts.Rev = &rev
+
+	go stg.Watch(ctx, stg.Collection().Test(), event, opts)
+
+	e := receive(t, event)
+	if !assert.NotNil(t, e, "event not received") {
+		return
+	}
+
+	assert.Equal(t, types.STORAGEUPDATEEVENT, e.Action, "event action different")
+	assert.Equal(t, "demo", e.SelfLink, "event selflink different")
+	assert.Equal(t, item.Storage.Revision, e.Storage.Revision, "event revision different")
+
+	if !assert.NoError(t, stg.Del(ctx, stg.Collection().Test(), "demo")) {
+		return
+	}
+
+	e = receive(t, event)
+	if !assert.NotNil(t, e, "event not received") {
+		return
+	}
+
+	assert.Equal(t, types.STORAGEDELETEEVENT, e.Action, "event action different")
+	assert.Equal(t, "demo", e.SelfLink, "event selflink different")
+}
+
+func TestStorage_Ttl(t *testing.T) {
+
+	stg, done := getStorage(t)
+	defer done()
+
+	type obj struct {
+		Name string `json:"name"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := stg.Info(ctx, stg.Collection().Test(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// watch from current revision to receive all following changes
+	event := make(chan *types.WatcherEvent)
+	wopts := storage.GetOpts()
+	wopts.Rev = &info.Storage.Revision
+
+	go stg.Watch(ctx, stg.Collection().Test(), event, wopts)
+
+	opts := storage.GetOpts()
+	opts.Ttl = 1
+
+	if !assert.NoError(t, stg.Put(ctx, stg.Collection().Test(), "lead", &obj{Name: "lead"}, opts)) {
+		return
+	}
+
+	e := receive(t, event)
+	if !assert.NotNil(t, e, "event not received") {
+		return
+	}
+	assert.Equal(t, types.STORAGECREATEEVENT, e.Action, "event action different")
+
+	// ttl key exists until it is expired
+	err = stg.Put(ctx, stg.Collection().Test(), "lead", &obj{Name: "lead"}, opts)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityExists, err.Error(), "err message different")
+	}
+
+	e = receive(t, event)
+	if !assert.NotNil(t, e, "event not received") {
+		return
+	}
+	assert.Equal(t, types.STORAGEDELETEEVENT, e.Action, "event action different")
+
+	err = stg.Get(ctx, stg.Collection().Test(), "lead", new(obj), nil)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityNotFound, err.Error(), "err message different")
+	}
+
+	assert.NoError(t, stg.Put(ctx, stg.Collection().Test(), "lead", &obj{Name: "lead"}, opts))
+}
+
+func TestStorage_Reopen(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "bbolt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	type obj struct {
+		dtypes.System
+		Name string `json:"name"`
+	}
+
+	var (
+		ctx = context.Background()
+		cfg = &bbolt.Config{Path: filepath.Join(dir, "storage.db")}
+	)
+
+	stg, err := bbolt.New(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	item := &obj{Name: "demo"}
+	if !assert.NoError(t, stg.Put(ctx, stg.Collection().Test(), "demo", item, nil)) {
+		return
+	}
+
+	if !assert.NoError(t, stg.Close()) {
+		return
+	}
+
+	// data and revisions should be kept after database is reopened
+	stg, err = bbolt.New(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stg.Close()
+
+	out := new(obj)
+	if !assert.NoError(t, stg.Get(ctx, stg.Collection().Test(), "demo", out, nil)) {
+		return
+	}
+
+	assert.Equal(t, item.Name, out.Name, "object received error")
+	assert.Equal(t, item.Storage.Revision, out.Storage.Revision, "revision different")
+
+	info, err := stg.Info(ctx, stg.Collection().Test(), "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, item.Storage.Revision, info.Storage.Revision, "revision different")
+	}
+}
+
+func TestStorage_Shared(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "bbolt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	type obj struct {
+		dtypes.System
+		Name string `json:"name"`
+	}
+
+	var (
+		ctx = context.Background()
+		cfg = &bbolt.Config{Path: filepath.Join(dir, "storage.db")}
+	)
+
+	// components in one process open the same path and share database handle
+	first, err := bbolt.New(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := bbolt.New(cfg)
+	if !assert.NoError(t, err, "second storage in process should share database file") {
+		first.Close()
+		return
+	}
+
+	event := make(chan *types.WatcherEvent)
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go second.Watch(wctx, second.Collection().Test(), event, nil)
+	time.Sleep(100 * time.Millisecond)
+
+	if !assert.NoError(t, first.Put(ctx, first.Collection().Test(), "demo", &obj{Name: "demo"}, nil)) {
+		return
+	}
+
+	if e := receive(t, event); assert.NotNil(t, e, "change should be sent to watcher of another storage") {
+		assert.Equal(t, "demo", e.SelfLink)
+	}
+
+	// database stays open while it is used by another storage
+	assert.NoError(t, first.Close())
+
+	out := new(obj)
+	if assert.NoError(t, second.Get(ctx, second.Collection().Test(), "demo", out, nil)) {
+		assert.Equal(t, "demo", out.Name)
+	}
+
+	assert.NoError(t, second.Close())
+
+	// database file locked by another process can not be opened
+	db, err := bolt.Open(cfg.Path, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	started := time.Now()
+	_, err = bbolt.New(cfg)
+	assert.Error(t, err, "database locked by another process should not be opened")
+	assert.True(t, time.Since(started) < 3*time.Second, "locked database should fail fast")
+}
+
+func receive(t *testing.T, event chan *types.WatcherEvent) *types.WatcherEvent {
+	select {
+	case e := <-event:
+		return e
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+func getStorage(t *testing.T) (*bbolt.Storage, func()) {
+
+	dir, err := ioutil.TempDir("", "bbolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := new(bbolt.Config)
+	cfg.Path = filepath.Join(dir, "storage.db")
+	cfg.Prefix = "lstbknd"
+
+	stg, err := bbolt.New(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return stg, func() {
+		stg.Close()
+		os.RemoveAll(dir)
+	}
+}
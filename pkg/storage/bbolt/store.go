@@ -0,0 +1,710 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package bbolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+)
+
+const (
+	defaultPrefix  = "lastbackend"
+	defaultHistory = 10000
+	expireInterval = time.Second
+	// lockTimeout limits waiting for database file locked by another process
+	lockTimeout = time.Second
+)
+
+var (
+	kvBucket   = []byte("kv")
+	ttlBucket  = []byte("ttl")
+	logBucket  = []byte("log")
+	metaBucket = []byte("meta")
+
+	revisionKey = []byte("revision")
+
+	errLocked = errors.New("database file is locked by another process: bbolt storage can not be shared between processes, " +
+		"run api, controller and discovery with standalone command")
+)
+
+// record is a value stored with key and its runtime information
+type record struct {
+	Value  []byte `json:"value"`
+	Create int64  `json:"create"`
+	Mod    int64  `json:"mod"`
+	// Expire is a unix time in nanoseconds after which record is removed, 0 means no expiration
+	Expire int64 `json:"expire,omitempty"`
+}
+
+func (r *record) expired(now int64) bool {
+	return r.Expire > 0 && r.Expire <= now
+}
+
+// change is a watch log entry, stored by revision
+type change struct {
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type store struct {
+	db   *bolt.DB
+	root []byte
+	// history is a count of changes kept in watch log
+	history int64
+
+	// lock serializes changes and dispatching them to watchers
+	lock     sync.Mutex
+	watchers map[*watcher]bool
+
+	done chan struct{}
+}
+
+func newStore(config *Config) (*store, error) {
+
+	db, err := bolt.Open(config.Path, 0600, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+
+	s := new(store)
+	s.db = db
+	s.root = []byte(config.Prefix)
+	s.history = config.History
+	s.watchers = make(map[*watcher]bool)
+	s.done = make(chan struct{})
+
+	if len(s.root) == 0 {
+		s.root = []byte(defaultPrefix)
+	}
+
+	if s.history <= 0 {
+		s.history = defaultHistory
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(s.root)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range [][]byte{kvBucket, ttlBucket, logBucket, metaBucket} {
+			if _, err := root.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	go s.expireLoop()
+
+	return s, nil
+}
+
+func (s *store) close() error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	close(s.done)
+	return s.db.Close()
+}
+
+func (s *store) bucket(tx *bolt.Tx, name []byte) *bolt.Bucket {
+	return tx.Bucket(s.root).Bucket(name)
+}
+
+func (s *store) revision(tx *bolt.Tx) int64 {
+	v := s.bucket(tx, metaBucket).Get(revisionKey)
+	if v == nil {
+		return 0
+	}
+	return btoi(v)
+}
+
+func (s *store) info() (int64, error) {
+
+	var rev int64
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		rev = s.revision(tx)
+		return nil
+	})
+
+	return rev, err
+}
+
+func (s *store) get(key string) (*record, error) {
+
+	var r *record
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		r, err = getRecord(s.bucket(tx, kvBucket), key, time.Now().UnixNano())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r == nil {
+		return nil, errors.New(types.ErrEntityNotFound)
+	}
+
+	return r, nil
+}
+
+// list returns sorted keys with prefix and their records.
+// If limit or continue is set in opts, only records of requested page are returned
+func (s *store) list(prefix string, opts *types.Opts) ([]string, []*record, types.System, error) {
+
+	var (
+		keys    = make([]string, 0)
+		records = make([]*record, 0)
+		page    types.System
+	)
+
+	paged := opts != nil && (opts.Limit > 0 || opts.Continue != "")
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		var (
+			now   = time.Now().UnixNano()
+			c     = s.bucket(tx, kvBucket).Cursor()
+			p     = []byte(prefix)
+			start = p
+			more  bool
+		)
+
+		if paged && opts.Continue != "" {
+			cnt, err := types.DecodeContinue(opts.Continue)
+			if err != nil {
+				return err
+			}
+
+			if !strings.HasPrefix(cnt.Key, prefix) {
+				return errors.New(types.ErrContinueIsInvalid)
+			}
+
+			start = []byte(cnt.Key + "\x00")
+		}
+
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+
+			r := new(record)
+			if err := json.Unmarshal(v, r); err != nil {
+				return err
+			}
+
+			if r.expired(now) {
+				continue
+			}
+
+			page.Storage.Total++
+
+			if bytes.Compare(k, start) < 0 {
+				continue
+			}
+
+			if paged && opts.Limit > 0 && int64(len(keys)) >= opts.Limit {
+				more = true
+				continue
+			}
+
+			keys = append(keys, string(k))
+			records = append(records, r)
+		}
+
+		page.Storage.Revision = s.revision(tx)
+
+		if more {
+			cnt := types.Continue{Rev: s.revision(tx), Key: keys[len(keys)-1]}
+			page.Storage.Continue = cnt.Encode()
+		}
+
+		return nil
+	})
+
+	return keys, records, page, err
+}
+
+// put creates new record, it fails if record already exists
+func (s *store) put(key string, value []byte, ttl uint64) (int64, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var ch *change
+	var rev int64
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+
+		kv := s.bucket(tx, kvBucket)
+
+		prev, err := getRecord(kv, key, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+
+		if prev != nil {
+			return errors.New(types.ErrEntityExists)
+		}
+
+		rev = s.next(tx)
+		ch = &change{Type: types.STORAGECREATEEVENT, Key: key, Value: value}
+
+		return s.write(tx, key, &record{Value: value, Create: rev, Mod: rev}, ttl, ch, rev)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.dispatch(rev, ch)
+	return rev, nil
+}
+
+// set updates existing record. If force is set, record is created when not exists.
+// If rev is set, record is updated only if it was not changed since this revision
+func (s *store) set(key string, value []byte, ttl uint64, force bool, expected *int64) (int64, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var ch *change
+	var rev int64
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+
+		kv := s.bucket(tx, kvBucket)
+
+		prev, err := getRecord(kv, key, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+
+		if !force {
+			if prev == nil {
+				return errors.New(types.ErrEntityNotFound)
+			}
+
+			if expected != nil && prev.Mod != *expected {
+				return errors.New(types.ErrEntityConflict)
+			}
+		}
+
+		rev = s.next(tx)
+		r := &record{Value: value, Create: rev, Mod: rev}
+		ch = &change{Type: types.STORAGECREATEEVENT, Key: key, Value: value}
+
+		if prev != nil {
+			r.Create = prev.Create
+			ch.Type = types.STORAGEUPDATEEVENT
+		}
+
+		return s.write(tx, key, r, ttl, ch, rev)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.dispatch(rev, ch)
+	return rev, nil
+}
+
+// del removes record by key, or all records with prefix if prefix flag is set.
+// If expected revision is set, record is removed only if it was not changed since this revision
+func (s *store) del(key string, prefix bool, expected *int64) error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		changes = make(map[int64]*change)
+		revs    = make([]int64, 0)
+	)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+
+		var (
+			kv   = s.bucket(tx, kvBucket)
+			keys = make([][]byte, 0)
+			now  = time.Now().UnixNano()
+		)
+
+		if prefix {
+			p := []byte(key)
+			c := kv.Cursor()
+			for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+				keys = append(keys, append([]byte{}, k...))
+			}
+		} else {
+			keys = append(keys, []byte(key))
+		}
+
+		for _, k := range keys {
+
+			r, err := getRecord(kv, string(k), 0)
+			if err != nil {
+				return err
+			}
+
+			if expected != nil {
+				if r == nil || r.expired(now) {
+					return errors.New(types.ErrEntityNotFound)
+				}
+
+				if r.Mod != *expected {
+					return errors.New(types.ErrEntityConflict)
+				}
+			}
+
+			if r == nil {
+				continue
+			}
+
+			if err := s.remove(tx, k, r); err != nil {
+				return err
+			}
+
+			// expired records are removed silently, like they are already removed by ttl
+			if r.expired(now) {
+				continue
+			}
+
+			rev := s.next(tx)
+			ch := &change{Type: types.STORAGEDELETEEVENT, Key: string(k), Value: r.Value}
+			if err := s.log(tx, rev, ch); err != nil {
+				return err
+			}
+
+			changes[rev] = ch
+			revs = append(revs, rev)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revs {
+		s.dispatch(rev, changes[rev])
+	}
+
+	return nil
+}
+
+// expire removes records with expired ttl
+func (s *store) expire() error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		changes = make(map[int64]*change)
+		revs    = make([]int64, 0)
+	)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+
+		var (
+			kv   = s.bucket(tx, kvBucket)
+			ttl  = s.bucket(tx, ttlBucket)
+			now  = time.Now().UnixNano()
+			keys = make([][]byte, 0)
+		)
+
+		c := ttl.Cursor()
+		for k, _ := c.First(); k != nil && btoi(k[:8]) <= now; k, _ = c.Next() {
+			keys = append(keys, append([]byte{}, k[8:]...))
+		}
+
+		for _, k := range keys {
+
+			r, err := getRecord(kv, string(k), 0)
+			if err != nil {
+				return err
+			}
+
+			if r == nil {
+				continue
+			}
+
+			if err := s.remove(tx, k, r); err != nil {
+				return err
+			}
+
+			rev := s.next(tx)
+			ch := &change{Type: types.STORAGEDELETEEVENT, Key: string(k), Value: r.Value}
+			if err := s.log(tx, rev, ch); err != nil {
+				return err
+			}
+
+			changes[rev] = ch
+			revs = append(revs, rev)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revs {
+		s.dispatch(rev, changes[rev])
+	}
+
+	return nil
+}
+
+func (s *store) expireLoop() {
+
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.expire(); err != nil {
+				log.Errorf("%s:expire:> remove expired records err: %v", logPrefix, err)
+			}
+		}
+	}
+}
+
+// watch registers watcher for changes of records with prefix.
+// If rev is set, changes made after this revision are sent first
+func (s *store) watch(prefix string, rev *int64) (*watcher, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	w := newWatcher(prefix)
+
+	if rev != nil {
+		err := s.db.View(func(tx *bolt.Tx) error {
+			return s.replay(tx, w, *rev)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.watchers[w] = true
+	return w, nil
+}
+
+func (s *store) unwatch(w *watcher) {
+	s.lock.Lock()
+	delete(s.watchers, w)
+	s.lock.Unlock()
+}
+
+// replay sends changes made after revision to watcher.
+// If changes are already removed from watch log, current state of records changed after revision is sent
+func (s *store) replay(tx *bolt.Tx, w *watcher, rev int64) error {
+
+	c := s.bucket(tx, logBucket).Cursor()
+
+	if k, _ := c.First(); k == nil || btoi(k) > rev+1 {
+
+		if rev >= s.revision(tx) {
+			return nil
+		}
+
+		var (
+			now = time.Now().UnixNano()
+			p   = []byte(w.prefix)
+			kc  = s.bucket(tx, kvBucket).Cursor()
+		)
+
+		for k, v := kc.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = kc.Next() {
+			r := new(record)
+			if err := json.Unmarshal(v, r); err != nil {
+				return err
+			}
+
+			if r.expired(now) || r.Mod <= rev {
+				continue
+			}
+
+			w.push(&types.Event{Type: types.STORAGECREATEEVENT, Key: string(k), Rev: r.Mod, Object: r.Value})
+		}
+
+		return nil
+	}
+
+	for k, v := c.Seek(itob(rev + 1)); k != nil; k, v = c.Next() {
+		ch := new(change)
+		if err := json.Unmarshal(v, ch); err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(ch.Key, w.prefix) {
+			w.push(&types.Event{Type: ch.Type, Key: ch.Key, Rev: btoi(k), Object: ch.Value})
+		}
+	}
+
+	return nil
+}
+
+func (s *store) dispatch(rev int64, ch *change) {
+	for w := range s.watchers {
+		if strings.HasPrefix(ch.Key, w.prefix) {
+			w.push(&types.Event{Type: ch.Type, Key: ch.Key, Rev: rev, Object: ch.Value})
+		}
+	}
+}
+
+// next increments and returns store revision
+func (s *store) next(tx *bolt.Tx) int64 {
+	rev := s.revision(tx) + 1
+	s.bucket(tx, metaBucket).Put(revisionKey, itob(rev))
+	return rev
+}
+
+func (s *store) write(tx *bolt.Tx, key string, r *record, ttl uint64, ch *change, rev int64) error {
+
+	var (
+		kv = s.bucket(tx, kvBucket)
+		tb = s.bucket(tx, ttlBucket)
+	)
+
+	prev, err := getRecord(kv, key, 0)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil && prev.Expire > 0 {
+		if err := tb.Delete(ttlKey(prev.Expire, []byte(key))); err != nil {
+			return err
+		}
+	}
+
+	if ttl > 0 {
+		r.Expire = time.Now().Add(time.Duration(ttl) * time.Second).UnixNano()
+		if err := tb.Put(ttlKey(r.Expire, []byte(key)), nil); err != nil {
+			return err
+		}
+	}
+
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if err := kv.Put([]byte(key), buf); err != nil {
+		return err
+	}
+
+	return s.log(tx, rev, ch)
+}
+
+func (s *store) remove(tx *bolt.Tx, key []byte, r *record) error {
+
+	if r.Expire > 0 {
+		if err := s.bucket(tx, ttlBucket).Delete(ttlKey(r.Expire, key)); err != nil {
+			return err
+		}
+	}
+
+	return s.bucket(tx, kvBucket).Delete(key)
+}
+
+// log appends change into watch log and removes changes out of history
+func (s *store) log(tx *bolt.Tx, rev int64, ch *change) error {
+
+	b := s.bucket(tx, logBucket)
+
+	buf, err := json.Marshal(ch)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(itob(rev), buf); err != nil {
+		return err
+	}
+
+	keys := make([][]byte, 0)
+
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && btoi(k) <= rev-s.history; k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRecord returns record by key, expired records are skipped if now is set
+func getRecord(b *bolt.Bucket, key string, now int64) (*record, error) {
+
+	v := b.Get([]byte(key))
+	if v == nil {
+		return nil, nil
+	}
+
+	r := new(record)
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, err
+	}
+
+	if now > 0 && r.expired(now) {
+		return nil, nil
+	}
+
+	return r, nil
+}
+
+func ttlKey(expire int64, key []byte) []byte {
+	return append(itob(expire), key...)
+}
+
+func itob(v int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+func btoi(b []byte) int64 {
+	return int64(binary.BigEndian.Uint64(b))
+}
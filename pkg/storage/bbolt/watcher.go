@@ -0,0 +1,66 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package bbolt
+
+import (
+	"sync"
+
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+)
+
+// watcher receives store changes with key prefix.
+// Events are queued without limit, so dispatching changes never blocks store writes
+type watcher struct {
+	prefix string
+
+	lock   sync.Mutex
+	queue  []*types.Event
+	notify chan struct{}
+}
+
+func newWatcher(prefix string) *watcher {
+	w := new(watcher)
+	w.prefix = prefix
+	w.queue = make([]*types.Event, 0)
+	w.notify = make(chan struct{}, 1)
+	return w
+}
+
+func (w *watcher) push(e *types.Event) {
+
+	w.lock.Lock()
+	w.queue = append(w.queue, e)
+	w.lock.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns all queued events
+func (w *watcher) pop() []*types.Event {
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	items := w.queue
+	w.queue = make([]*types.Event, 0)
+	return items
+}
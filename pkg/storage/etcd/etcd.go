@@ -87,12 +87,7 @@ func (s Storage) List(ctx context.Context, collection string, query string, obj
 		return errors.New(types.ErrStructOutIsNil)
 	}
 
-	var rev *int64
-	if opts != nil {
-		rev = opts.Rev
-	}
-
-	return s.client.store.List(ctx, keyCreate(collection, query), "", obj, rev)
+	return s.client.store.List(ctx, keyCreate(collection, query), "", obj, opts)
 }
 
 func (s Storage) Map(ctx context.Context, collection string, query string, obj interface{}, opts *types.Opts) error {
@@ -103,12 +98,7 @@ func (s Storage) Map(ctx context.Context, collection string, query string, obj i
 
 	q := ".*/(.*)$"
 
-	var rev *int64
-	if opts != nil {
-		rev = opts.Rev
-	}
-
-	return s.client.store.Map(ctx, keyCreate(collection, query), q, obj, rev)
+	return s.client.store.Map(ctx, keyCreate(collection, query), q, obj, opts)
 }
 
 func (s Storage) Put(ctx context.Context, collection string, name string, obj interface{}, opts *types.Opts) error {
@@ -148,6 +138,11 @@ func (s Storage) Del(ctx context.Context, collection string, name string) error
 	return s.client.store.Del(ctx, key)
 }
 
+// DelRev removes object only if it was not changed since revision
+func (s Storage) DelRev(ctx context.Context, collection string, name string, rev int64) error {
+	return s.client.store.DelRev(ctx, keyCreate(collection, name), rev)
+}
+
 func (s Storage) Watch(ctx context.Context, collection string, event chan *types.WatcherEvent, opts *types.Opts) error {
 
 	log.V(logLevel).Debug("%s:> watch %s", logPrefix, collection)
@@ -54,6 +54,10 @@ func (Filter) Config() types.ConfigFilter {
 	return new(ConfigFilter)
 }
 
+func (Filter) Policy() types.PolicyFilter {
+	return new(PolicyFilter)
+}
+
 func (Filter) Secret() types.SecretFilter {
 	return new(SecretFilter)
 }
@@ -159,6 +163,12 @@ func (ConfigFilter) ByNamespace(namespace string) string {
 	return byNamespace(namespace)
 }
 
+type PolicyFilter struct{}
+
+func (PolicyFilter) ByNamespace(namespace string) string {
+	return byNamespace(namespace)
+}
+
 type VolumeFilter struct{}
 
 func (VolumeFilter) ByNamespace(namespace string) string {
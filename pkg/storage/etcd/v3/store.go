@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
 	"github.com/coreos/etcd/etcdserver/etcdserverpb"
 	"github.com/coreos/etcd/mvcc/mvccpb"
 	"github.com/lastbackend/lastbackend/pkg/log"
@@ -154,7 +155,7 @@ func (s *dbstore) Get(ctx context.Context, key string, outPtr interface{}, rev *
 		return err
 	}
 
-	if err := setEntityRuntimeInfo(outPtr, getRuntimeFromResponse(res.Header)); err != nil {
+	if err := setEntityRuntimeInfo(outPtr, getRuntimeFromValue(res.Kvs[0])); err != nil {
 		log.V(logLevel).Errorf("%s:get:> can not set runtime info err: %v", logPrefix, err)
 		return err
 	}
@@ -162,20 +163,22 @@ func (s *dbstore) Get(ctx context.Context, key string, outPtr interface{}, rev *
 	return nil
 }
 
-func (s *dbstore) List(ctx context.Context, key, keyRegexFilter string, listOutPtr interface{}, rev *int64) error {
+func (s *dbstore) List(ctx context.Context, key, keyRegexFilter string, listOutPtr interface{}, opts *types.Opts) error {
 
 	key = path.Join(s.pathPrefix, key)
 
 	log.V(logLevel).Debugf("%s:list:> key: %s with filter: %s", logPrefix, key, keyRegexFilter)
 
-	getResp, err := s.client.KV.Get(ctx, key, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortDescend))
+	getResp, runtime, err := s.getRange(ctx, key, opts)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:list:> request err: %v", logPrefix, err)
 		return err
 	}
 
 	r, _ := regexp.Compile(keyRegexFilter)
-	items := make(map[string]*mvccpb.KeyValue)
+	items := make([]*mvccpb.KeyValue, 0, len(getResp.Kvs))
+	keys := make([]string, 0, len(getResp.Kvs))
+	index := make(map[string]int)
 
 	for _, kv := range getResp.Kvs {
 
@@ -186,7 +189,15 @@ func (s *dbstore) List(ctx context.Context, key, keyRegexFilter string, listOutP
 			continue
 		}
 
-		items[node] = kv
+		if i, ok := index[node]; ok {
+			items[i] = kv
+			keys[i] = string(kv.Key)
+			continue
+		}
+
+		index[node] = len(items)
+		items = append(items, kv)
+		keys = append(keys, string(kv.Key))
 	}
 
 	if err := decodeList(s.codec, items, listOutPtr); err != nil {
@@ -194,7 +205,29 @@ func (s *dbstore) List(ctx context.Context, key, keyRegexFilter string, listOutP
 		return err
 	}
 
-	if err := setEntityRuntimeInfo(listOutPtr, getRuntimeFromResponse(getResp.Header)); err != nil {
+	// total of keys range counts keys not matched by filter,
+	// so filtered list total is known only if all keys are listed
+	if keyRegexFilter != "" && (opts == nil || opts.Match == nil) {
+		runtime.Storage.Total = 0
+		if opts == nil || (opts.Limit <= 0 && opts.Continue == "") {
+			runtime.Storage.Total = int64(len(items))
+		}
+	}
+
+	if opts != nil && opts.Match != nil {
+		v, err := converter.EnforcePtr(listOutPtr)
+		if err != nil {
+			return errors.New(types.ErrStructOutIsInvalid)
+		}
+
+		runtime.Storage.Total, runtime.Storage.Continue, err = types.MatchPage(v.FieldByName("Items"), keys, key, runtime.Storage.Revision, opts)
+		if err != nil {
+			log.V(logLevel).Errorf("%s:list:> match page err: %v", logPrefix, err)
+			return err
+		}
+	}
+
+	if err := setEntityRuntimeInfo(listOutPtr, runtime); err != nil {
 		log.V(logLevel).Errorf("%s:get:> can not set runtime info err: %v", logPrefix, err)
 		return err
 	}
@@ -202,13 +235,13 @@ func (s *dbstore) List(ctx context.Context, key, keyRegexFilter string, listOutP
 	return nil
 }
 
-func (s *dbstore) Map(ctx context.Context, key, keyRegexFilter string, mapOutPtr interface{}, rev *int64) error {
+func (s *dbstore) Map(ctx context.Context, key, keyRegexFilter string, mapOutPtr interface{}, opts *types.Opts) error {
 
 	key = path.Join(s.pathPrefix, key)
 
 	log.V(logLevel).Debugf("%s:map:> key: %s with filter: %s", logPrefix, key, keyRegexFilter)
 
-	getResp, err := s.client.KV.Get(ctx, key, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortDescend))
+	getResp, runtime, err := s.getRange(ctx, key, opts)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:map:> request err: %v", logPrefix, err)
 		return err
@@ -236,7 +269,7 @@ func (s *dbstore) Map(ctx context.Context, key, keyRegexFilter string, mapOutPtr
 		return err
 	}
 
-	if err := setEntityRuntimeInfo(mapOutPtr, getRuntimeFromResponse(getResp.Header)); err != nil {
+	if err := setEntityRuntimeInfo(mapOutPtr, runtime); err != nil {
 		log.V(logLevel).Errorf("%s:get:> can not set runtime info err: %v", logPrefix, err)
 		return err
 	}
@@ -244,6 +277,98 @@ func (s *dbstore) Map(ctx context.Context, key, keyRegexFilter string, mapOutPtr
 	return nil
 }
 
+// getRange returns all key-values with key prefix sorted by key.
+// If limit or continue is set in opts, keys are returned page by page,
+// all pages are read at the revision of the first page.
+// If match is set in opts, all keys are returned at this revision to be matched and paged after decoding
+func (s *dbstore) getRange(ctx context.Context, key string, opts *types.Opts) (*clientv3.GetResponse, types.System, error) {
+
+	var runtime types.System
+
+	if opts == nil || (opts.Limit <= 0 && opts.Continue == "") {
+		getResp, err := s.client.KV.Get(ctx, key, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+		if err != nil {
+			return nil, runtime, err
+		}
+
+		runtime = getRuntimeFromResponse(getResp.Header)
+		runtime.Storage.Total = getResp.Count
+		return getResp, runtime, nil
+	}
+
+	var (
+		start = key
+		rev   int64
+	)
+
+	if opts.Continue != "" {
+		c, err := types.DecodeContinue(opts.Continue)
+		if err != nil {
+			return nil, runtime, err
+		}
+
+		if !strings.HasPrefix(c.Key, key) {
+			return nil, runtime, errors.New(types.ErrContinueIsInvalid)
+		}
+
+		start = c.Key + "\x00"
+		rev = c.Rev
+	}
+
+	if opts.Match != nil {
+		start = key
+	}
+
+	rangeOpts := []clientv3.OpOption{
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(key)),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+	}
+
+	if opts.Limit > 0 && opts.Match == nil {
+		rangeOpts = append(rangeOpts, clientv3.WithLimit(opts.Limit))
+	}
+
+	if rev > 0 {
+		rangeOpts = append(rangeOpts, clientv3.WithRev(rev))
+	}
+
+	getResp, err := s.client.KV.Get(ctx, start, rangeOpts...)
+	if err != nil {
+		if err == rpctypes.ErrCompacted {
+			return nil, runtime, errors.New(types.ErrContinueIsExpired)
+		}
+		return nil, runtime, err
+	}
+
+	if rev == 0 {
+		rev = getResp.Header.Revision
+	}
+
+	if opts.Match != nil {
+		runtime = getRuntimeFromResponse(getResp.Header)
+		runtime.Storage.Revision = rev
+		return getResp, runtime, nil
+	}
+
+	countResp, err := s.client.KV.Get(ctx, key, clientv3.WithPrefix(), clientv3.WithCountOnly(), clientv3.WithRev(rev))
+	if err != nil {
+		if err == rpctypes.ErrCompacted {
+			return nil, runtime, errors.New(types.ErrContinueIsExpired)
+		}
+		return nil, runtime, err
+	}
+
+	runtime = getRuntimeFromResponse(getResp.Header)
+	runtime.Storage.Total = countResp.Count
+
+	if getResp.More && len(getResp.Kvs) > 0 {
+		c := types.Continue{Rev: rev, Key: string(getResp.Kvs[len(getResp.Kvs)-1].Key)}
+		runtime.Storage.Continue = c.Encode()
+	}
+
+	return getResp, runtime, nil
+}
+
 func (s *dbstore) Set(ctx context.Context, key string, obj, outPtr interface{}, ttl uint64, force bool, rev *int64) error {
 
 	key = path.Join(s.pathPrefix, key)
@@ -264,27 +389,39 @@ func (s *dbstore) Set(ctx context.Context, key string, obj, outPtr interface{},
 	txn := s.client.KV.Txn(ctx)
 
 	if !force {
-		rv := int64(0)
 		if rev != nil {
-			rv = *rev
+			// compare and swap: update only if entity was not changed since provided revision
+			txn = txn.If(clientv3.Compare(clientv3.ModRevision(key), "=", *rev))
+		} else {
+			txn = txn.If(clientv3.Compare(clientv3.ModRevision(key), "!=", 0))
 		}
+	}
 
-		txn = txn.If(clientv3.Compare(clientv3.ModRevision(key), "!=", rv))
+	txn = txn.Then(clientv3.OpPut(key, string(data), opts...))
+
+	if !force && rev != nil {
+		txn = txn.Else(clientv3.OpGet(key, clientv3.WithCountOnly()))
 	}
 
-	txnResp, err := txn.
-		Then(clientv3.OpPut(key, string(data), opts...)).
-		Commit()
+	txnResp, err := txn.Commit()
 
 	if err != nil {
 		log.V(logLevel).Errorf("%s:update:> request err: %v", logPrefix, err)
 		return err
 	}
 	if !txnResp.Succeeded {
+		if rev != nil && len(txnResp.Responses) > 0 {
+			if r := txnResp.Responses[0].GetResponseRange(); r != nil && r.Count > 0 {
+				return errors.New(types.ErrEntityConflict)
+			}
+		}
 		return errors.New(types.ErrEntityNotFound)
 	}
 	if validator.IsNil(outPtr) {
 		log.V(logLevel).Warnf("%s:Update: output struct is nil", logPrefix)
+		if reflect.TypeOf(obj).Kind() == reflect.Ptr {
+			return setEntityRuntimeInfo(obj, getRuntimeFromResponse(txnResp.Header))
+		}
 		return nil
 	}
 	if outPtr != nil {
@@ -318,6 +455,33 @@ func (s *dbstore) Del(ctx context.Context, key string) error {
 	return nil
 }
 
+func (s *dbstore) DelRev(ctx context.Context, key string, rev int64) error {
+
+	key = path.Join(s.pathPrefix, key)
+
+	log.V(logLevel).Debugf("%s:delete:> key: %s rev: %d", logPrefix, key, rev)
+
+	txnResp, err := s.client.KV.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", rev)).
+		Then(clientv3.OpDelete(key)).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:delete:> request err: %v", logPrefix, err)
+		return err
+	}
+
+	if txnResp.Succeeded {
+		return nil
+	}
+
+	if len(txnResp.Responses[0].GetResponseRange().Kvs) == 0 {
+		return errors.New(types.ErrEntityNotFound)
+	}
+
+	return errors.New(types.ErrEntityConflict)
+}
+
 func (s *dbstore) Begin(ctx context.Context) store.TX {
 
 	log.V(logLevel).Debugf("%s:begin:> start transaction", logPrefix)
@@ -361,6 +525,13 @@ func setEntityRuntimeInfo(out interface{}, runtime types.System) error {
 
 func setValueRuntimeInfo(v reflect.Value, runtime types.System) error {
 
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
 	if v.Kind() != reflect.Struct {
 		return nil
 	}
@@ -399,7 +570,7 @@ func decode(s serializer.Codec, value []byte, out interface{}) error {
 	return serializer.Decode(s, value, out)
 }
 
-func decodeList(codec serializer.Codec, items map[string]*mvccpb.KeyValue, listOut interface{}) error {
+func decodeList(codec serializer.Codec, items []*mvccpb.KeyValue, listOut interface{}) error {
 	v, err := converter.EnforcePtr(listOut)
 	if err != nil {
 		return errors.New(types.ErrStructOutIsInvalid)
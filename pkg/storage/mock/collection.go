@@ -27,6 +27,7 @@ const (
 	namespaceCollection  = "namespace"
 	secretCollection     = "secret"
 	configCollection     = "config"
+	policyCollection     = "policy"
 	endpointCollection   = "endpoint"
 	serviceCollection    = "service"
 	deploymentCollection = "deployment"
@@ -48,6 +49,10 @@ const (
 	jobCollection  = "job"
 	taskCollection = "task"
 
+	ipamCollection  = "ipam"
+	leaseCollection = "lease"
+	poolCollection  = "pool"
+
 	systemCollection = "system"
 	testCollection   = "test"
 
@@ -61,6 +66,8 @@ type ManifestCollection struct{}
 
 type NodeCollection struct{}
 
+type IPAMCollection struct{}
+
 type DiscoveryCollection struct{}
 
 type IngressCollection struct{}
@@ -81,6 +88,10 @@ func (Collection) Config() string {
 	return configCollection
 }
 
+func (Collection) Policy() string {
+	return policyCollection
+}
+
 func (Collection) Endpoint() string {
 	return endpointCollection
 }
@@ -137,6 +148,10 @@ func (Collection) Subnet() string {
 	return subnetCollection
 }
 
+func (Collection) IPAM() types.IPAMCollection {
+	return new(IPAMCollection)
+}
+
 func (Collection) Manifest() types.ManifestCollection {
 	return new(ManifestCollection)
 }
@@ -193,6 +208,14 @@ func (ManifestCollection) Route(ingress string) string {
 	return fmt.Sprintf("%s/%s/%s/%s", manifestCollection, ingressCollection, ingress, routeCollection)
 }
 
+func (IPAMCollection) Pool() string {
+	return fmt.Sprintf("%s/%s", ipamCollection, poolCollection)
+}
+
+func (IPAMCollection) Lease(pool string) string {
+	return fmt.Sprintf("%s/%s/%s", ipamCollection, leaseCollection, pool)
+}
+
 func (NodeCollection) Info() string {
 	return fmt.Sprintf("%s/%s", nodeCollection, infoColletion)
 }
@@ -58,6 +58,10 @@ func (Filter) Config() types.ConfigFilter {
 	return new(ConfigFilter)
 }
 
+func (Filter) Policy() types.PolicyFilter {
+	return new(PolicyFilter)
+}
+
 func (Filter) Volume() types.VolumeFilter {
 	return new(VolumeFilter)
 }
@@ -159,6 +163,12 @@ func (ConfigFilter) ByNamespace(namespace string) string {
 	return byNamespace(namespace)
 }
 
+type PolicyFilter struct{}
+
+func (PolicyFilter) ByNamespace(namespace string) string {
+	return byNamespace(namespace)
+}
+
 type VolumeFilter struct{}
 
 func (VolumeFilter) ByNamespace(namespace string) string {
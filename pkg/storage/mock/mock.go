@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"reflect"
+	"sort"
 
 	"encoding/json"
 
@@ -38,6 +39,11 @@ type Storage struct {
 	lock     sync.RWMutex
 	store    map[string]map[string][]byte
 	watchers map[chan *types.WatcherEvent]string
+
+	// revision is incremented on every write, like an etcd revision,
+	// revisions stores the last modification revision for every key
+	revision  int64
+	revisions map[string]map[string]int64
 }
 
 func (s *Storage) Info(ctx context.Context, collection string, name string) (*types.System, error) {
@@ -64,6 +70,8 @@ func (s *Storage) Get(ctx context.Context, collection string, name string, obj i
 		return err
 	}
 
+	setRevision(reflect.ValueOf(obj), s.revisions[collection][name])
+
 	return nil
 }
 
@@ -83,20 +91,25 @@ func (s *Storage) List(ctx context.Context, collection string, q string, obj int
 	}
 
 	collection = fmt.Sprintf("%s/%s", s.root, collection)
-	buffer := []byte("[")
-	current := 0
 
-	for k, item := range s.store[collection] {
-		if strings.HasPrefix(k, q) {
+	// matched objects are paged after decoding, so all keys are listed
+	ko := opts
+	if opts != nil && opts.Match != nil {
+		ko = nil
+	}
 
-			if current > 0 {
-				buffer = append(buffer, []byte(",")...)
-			}
+	keys, page, err := s.keys(collection, q, ko)
+	if err != nil {
+		return err
+	}
 
-			buffer = append(buffer, item...)
-			current++
+	buffer := []byte("[")
 
+	for i, k := range keys {
+		if i > 0 {
+			buffer = append(buffer, []byte(",")...)
 		}
+		buffer = append(buffer, s.store[collection][k]...)
 	}
 
 	buffer = append(buffer, []byte("]")...)
@@ -120,6 +133,20 @@ func (s *Storage) List(ctx context.Context, collection string, q string, obj int
 	}
 
 	f.Set(reflect.ValueOf(items).Elem())
+
+	for i, k := range keys {
+		setRevision(f.Index(i), s.revisions[collection][k])
+	}
+
+	if opts != nil && opts.Match != nil {
+		page.Storage.Total, page.Storage.Continue, err = types.MatchPage(f, keys, q, 0, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	setPage(v, page)
+
 	return nil
 }
 
@@ -140,25 +167,25 @@ func (s *Storage) Map(ctx context.Context, collection string, q string, obj inte
 
 	collection = fmt.Sprintf("%s/%s", s.root, collection)
 
-	buffer := []byte("{")
-	current := 0
-
-	for k, item := range s.store[collection] {
-		if strings.HasPrefix(k, q) {
+	keys, page, err := s.keys(collection, q, opts)
+	if err != nil {
+		return err
+	}
 
-			ks := strings.Split(k, "/")
+	buffer := []byte("{")
 
-			if current > 0 {
-				buffer = append(buffer, []byte(",")...)
-			}
+	for i, k := range keys {
 
-			buffer = append(buffer, []byte("\"")...)
-			buffer = append(buffer, []byte(ks[len(ks)-1])...)
-			buffer = append(buffer, []byte("\":")...)
-			buffer = append(buffer, item...)
-			current++
+		ks := strings.Split(k, "/")
 
+		if i > 0 {
+			buffer = append(buffer, []byte(",")...)
 		}
+
+		buffer = append(buffer, []byte("\"")...)
+		buffer = append(buffer, []byte(ks[len(ks)-1])...)
+		buffer = append(buffer, []byte("\":")...)
+		buffer = append(buffer, s.store[collection][k]...)
 	}
 
 	buffer = append(buffer, []byte("}")...)
@@ -182,6 +209,12 @@ func (s *Storage) Map(ctx context.Context, collection string, q string, obj inte
 	}
 
 	f.Set(reflect.ValueOf(items).Elem())
+	setPage(v, page)
+
+	for _, k := range keys {
+		ks := strings.Split(k, "/")
+		setRevision(f.MapIndex(reflect.ValueOf(ks[len(ks)-1])), s.revisions[collection][k])
+	}
 
 	return nil
 }
@@ -211,6 +244,9 @@ func (s *Storage) Put(ctx context.Context, collection string, name string, obj i
 	}
 
 	s.store[collection][name] = b
+	s.revision++
+	s.revisions[collection][name] = s.revision
+	setRevision(reflect.ValueOf(obj), s.revision)
 
 	s.dispatch(collection, name, types.STORAGECREATEEVENT, b)
 	return nil
@@ -231,12 +267,21 @@ func (s *Storage) Set(ctx context.Context, collection string, name string, obj i
 		}
 	}
 
+	if opts != nil && !opts.Force && opts.Rev != nil {
+		if s.revisions[collection][name] != *opts.Rev {
+			return errors.New(types.ErrEntityConflict)
+		}
+	}
+
 	b, err := json.Marshal(obj)
 	if err != nil {
 		return err
 	}
 
 	s.store[collection][name] = b
+	s.revision++
+	s.revisions[collection][name] = s.revision
+	setRevision(reflect.ValueOf(obj), s.revision)
 
 	s.dispatch(collection, name, types.STORAGEUPDATEEVENT, b)
 	return nil
@@ -251,11 +296,39 @@ func (s *Storage) Del(ctx context.Context, collection string, name string) error
 	collection = fmt.Sprintf("%s/%s", s.root, collection)
 	if name == "" {
 		s.store[collection] = make(map[string][]byte)
+		s.revisions[collection] = make(map[string]int64)
 		return nil
 	}
 
 	bt := s.store[collection][name]
 	delete(s.store[collection], name)
+	delete(s.revisions[collection], name)
+
+	s.dispatch(collection, name, types.STORAGEDELETEEVENT, bt)
+
+	return nil
+}
+
+// DelRev removes object only if it was not changed since revision
+func (s *Storage) DelRev(ctx context.Context, collection string, name string, rev int64) error {
+	s.check(collection)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	collection = fmt.Sprintf("%s/%s", s.root, collection)
+
+	bt, ok := s.store[collection][name]
+	if !ok {
+		return errors.New(types.ErrEntityNotFound)
+	}
+
+	if s.revisions[collection][name] != rev {
+		return errors.New(types.ErrEntityConflict)
+	}
+
+	delete(s.store[collection], name)
+	delete(s.revisions[collection], name)
 
 	s.dispatch(collection, name, types.STORAGEDELETEEVENT, bt)
 
@@ -296,7 +369,7 @@ func (s *Storage) dispatch(collection, name, action string, b []byte) {
 			e.Action = action
 			e.SelfLink = name
 			e.Storage.Key = fmt.Sprintf("%s/%s", strings.TrimPrefix(collection, s.root), name)
-			e.Storage.Revision = 0
+			e.Storage.Revision = s.revision
 			e.Data = b
 
 			match := strings.Split(name, ":")
@@ -312,6 +385,82 @@ func (s *Storage) dispatch(collection, name, action string, b []byte) {
 	}
 }
 
+// keys returns sorted keys with prefix in collection.
+// If limit or continue is set in opts, only keys of requested page are returned
+func (s *Storage) keys(collection, q string, opts *types.Opts) ([]string, types.System, error) {
+
+	var (
+		page types.System
+		keys = make([]string, 0)
+	)
+
+	for k := range s.store[collection] {
+		if strings.HasPrefix(k, q) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	page.Storage.Total = int64(len(keys))
+
+	if opts == nil || (opts.Limit <= 0 && opts.Continue == "") {
+		return keys, page, nil
+	}
+
+	if opts.Continue != "" {
+		c, err := types.DecodeContinue(opts.Continue)
+		if err != nil {
+			return nil, page, err
+		}
+
+		if !strings.HasPrefix(c.Key, q) {
+			return nil, page, errors.New(types.ErrContinueIsInvalid)
+		}
+
+		keys = keys[sort.SearchStrings(keys, c.Key+"\x00"):]
+	}
+
+	if opts.Limit > 0 && int64(len(keys)) > opts.Limit {
+		keys = keys[:opts.Limit]
+		c := types.Continue{Key: keys[len(keys)-1]}
+		page.Storage.Continue = c.Encode()
+	}
+
+	return keys, page, nil
+}
+
+func setPage(v reflect.Value, page types.System) {
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(page.System) {
+		return
+	}
+
+	f.Set(reflect.ValueOf(page.System))
+}
+
+// setRevision sets storage revision into System field of object if it exists
+func setRevision(v reflect.Value, rev int64) {
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(types.System{}.System) {
+		return
+	}
+
+	f.FieldByName("Storage").FieldByName("Revision").SetInt(rev)
+}
+
 func (s *Storage) check(kind string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -321,6 +470,10 @@ func (s *Storage) check(kind string) {
 	if _, ok := s.store[collection]; !ok {
 		s.store[collection] = make(map[string][]byte)
 	}
+
+	if _, ok := s.revisions[collection]; !ok {
+		s.revisions[collection] = make(map[string]int64)
+	}
 }
 
 func New() (*Storage, error) {
@@ -328,6 +481,7 @@ func New() (*Storage, error) {
 
 	db.root = "lastbackend"
 	db.store = make(map[string]map[string][]byte)
+	db.revisions = make(map[string]map[string]int64)
 	db.watchers = make(map[chan *types.WatcherEvent]string, 0)
 
 	return db, nil
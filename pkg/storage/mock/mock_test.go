@@ -38,6 +38,12 @@ func TestStorage_List(t *testing.T) {
 	storage.StorageListAssets(t, stg)
 }
 
+func TestStorage_Page(t *testing.T) {
+	stg, err := mock.New()
+	assert.NoError(t, err, "storage initialize err")
+	storage.StoragePageAssets(t, stg)
+}
+
 func TestStorage_Map(t *testing.T) {
 	stg, err := mock.New()
 	assert.NoError(t, err, "storage initialize err")
@@ -56,8 +62,20 @@ func TestStorage_Set(t *testing.T) {
 	storage.StorageSetAssets(t, stg)
 }
 
+func TestStorage_Revision(t *testing.T) {
+	stg, err := mock.New()
+	assert.NoError(t, err, "storage initialize err")
+	storage.StorageRevisionAssets(t, stg)
+}
+
 func TestStorage_Del(t *testing.T) {
 	stg, err := mock.New()
 	assert.NoError(t, err, "storage initialize err")
 	storage.StorageDelAssets(t, stg)
 }
+
+func TestStorage_DelRev(t *testing.T) {
+	stg, err := mock.New()
+	assert.NoError(t, err, "storage initialize err")
+	storage.StorageDelRevAssets(t, stg)
+}
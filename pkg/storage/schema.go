@@ -0,0 +1,549 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	derrors "github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	dtypes "github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage/schema"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+	"github.com/lastbackend/lastbackend/pkg/util/converter"
+)
+
+const (
+	logLevel        = 3
+	logSchemaPrefix = "storage:schema"
+	schemaName      = "schema"
+)
+
+// schemaStorage tags stored objects with schema version
+// and upgrades objects of previous versions on read
+type schemaStorage struct {
+	storage Storage
+	schema  *schema.Schema
+}
+
+// raw is an object data passed to storage driver without decoding
+type raw struct {
+	dtypes.System
+	data []byte
+}
+
+type rawList struct {
+	dtypes.System
+	Items []*raw
+}
+
+type rawMap struct {
+	dtypes.System
+	Items map[string]*raw
+}
+
+func newRawMap() *rawMap {
+	return &rawMap{Items: make(map[string]*raw)}
+}
+
+// schemaInfo is a schema version of all objects in storage, it is set by bulk migration
+type schemaInfo struct {
+	Version int `json:"version"`
+}
+
+func (r *raw) MarshalJSON() ([]byte, error) {
+	return r.data, nil
+}
+
+func (r *raw) UnmarshalJSON(data []byte) error {
+	r.data = append([]byte{}, data...)
+	return nil
+}
+
+// WithSchema returns storage which stores objects with schema version
+func WithSchema(stg Storage, s *schema.Schema) Storage {
+	return &schemaStorage{storage: stg, schema: s}
+}
+
+func (s *schemaStorage) Info(ctx context.Context, collection, name string) (*types.System, error) {
+	return s.storage.Info(ctx, collection, name)
+}
+
+func (s *schemaStorage) Get(ctx context.Context, collection, name string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	item := new(raw)
+	if err := s.storage.Get(ctx, collection, name, item, opts); err != nil {
+		return err
+	}
+
+	data, _, err := s.schema.Upgrade(collection, item.data)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), item.Storage.Revision)
+	return nil
+}
+
+func (s *schemaStorage) List(ctx context.Context, collection, q string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	v, err := converter.EnforcePtr(obj)
+	if err != nil {
+		return errors.New(types.ErrStructOutIsNotPointer)
+	}
+
+	f := v.FieldByName("Items")
+	if f.Kind() != reflect.Slice {
+		return errors.New(types.ErrStructOutIsInvalid)
+	}
+
+	// objects are matched after upgrade to current schema version
+	if opts != nil && opts.Match != nil {
+		o, match := *opts, opts.Match
+		o.Match = func(obj interface{}) bool {
+			i, ok := obj.(*raw)
+			if !ok {
+				return false
+			}
+
+			item, err := s.decode(collection, i, f.Type().Elem())
+			if err != nil {
+				return false
+			}
+
+			return match(item.Interface())
+		}
+		opts = &o
+	}
+
+	list := new(rawList)
+	if err := s.storage.List(ctx, collection, q, list, opts); err != nil {
+		return err
+	}
+
+	items := reflect.MakeSlice(f.Type(), 0, len(list.Items))
+
+	for _, i := range list.Items {
+
+		item, err := s.decode(collection, i, f.Type().Elem())
+		if err != nil {
+			return err
+		}
+
+		items = reflect.Append(items, item)
+	}
+
+	if f.CanSet() {
+		f.Set(items)
+	}
+
+	setSystem(v, list.System)
+	return nil
+}
+
+func (s *schemaStorage) Map(ctx context.Context, collection, q string, obj interface{}, opts *types.Opts) error {
+
+	if reflect.ValueOf(obj).IsNil() {
+		return errors.New(types.ErrStructOutIsNil)
+	}
+
+	v, err := converter.EnforcePtr(obj)
+	if err != nil {
+		return errors.New(types.ErrStructOutIsNotPointer)
+	}
+
+	f := v.FieldByName("Items")
+	if f.Kind() != reflect.Map {
+		return errors.New(types.ErrStructOutIsInvalid)
+	}
+
+	list := newRawMap()
+	if err := s.storage.Map(ctx, collection, q, list, opts); err != nil {
+		return err
+	}
+
+	items := reflect.MakeMap(f.Type())
+
+	for key, i := range list.Items {
+
+		item, err := s.decode(collection, i, f.Type().Elem())
+		if err != nil {
+			return err
+		}
+
+		items.SetMapIndex(reflect.ValueOf(key), item)
+	}
+
+	if f.CanSet() {
+		f.Set(items)
+	}
+
+	setSystem(v, list.System)
+	return nil
+}
+
+func (s *schemaStorage) Put(ctx context.Context, collection, name string, obj interface{}, opts *types.Opts) error {
+
+	item, err := s.encode(collection, obj)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.Put(ctx, collection, name, item, opts); err != nil {
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), item.Storage.Revision)
+	return nil
+}
+
+func (s *schemaStorage) Set(ctx context.Context, collection, name string, obj interface{}, opts *types.Opts) error {
+
+	item, err := s.encode(collection, obj)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.Set(ctx, collection, name, item, opts); err != nil {
+		return err
+	}
+
+	setRevision(reflect.ValueOf(obj), item.Storage.Revision)
+	return nil
+}
+
+func (s *schemaStorage) Del(ctx context.Context, collection, name string) error {
+	return s.storage.Del(ctx, collection, name)
+}
+
+func (s *schemaStorage) DelRev(ctx context.Context, collection, name string, rev int64) error {
+	return s.storage.DelRev(ctx, collection, name, rev)
+}
+
+func (s *schemaStorage) Watch(ctx context.Context, collection string, event chan *types.WatcherEvent, opts *types.Opts) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watcher := make(chan *types.WatcherEvent)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-watcher:
+
+				if data, ok := e.Data.([]byte); ok {
+					upgraded, _, err := s.schema.Upgrade(collection, data)
+					if err != nil {
+						log.Errorf("%s:watch:> upgrade %s err: %v", logSchemaPrefix, e.Storage.Key, err)
+						continue
+					}
+					e.Data = upgraded
+				}
+
+				select {
+				case event <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return s.storage.Watch(ctx, collection, watcher, opts)
+}
+
+func (s *schemaStorage) Collection() types.Collection {
+	return s.storage.Collection()
+}
+
+func (s *schemaStorage) Filter() types.Filter {
+	return s.storage.Filter()
+}
+
+func (s *schemaStorage) encode(collection string, obj interface{}) (*raw, error) {
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	item := new(raw)
+	if item.data, err = s.schema.Encode(collection, data); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *schemaStorage) decode(collection string, i *raw, t reflect.Type) (reflect.Value, error) {
+
+	data, _, err := s.schema.Upgrade(collection, i.data)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	item := reflect.New(t)
+	if err := json.Unmarshal(data, item.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	setRevision(item, i.Storage.Revision)
+	return item.Elem(), nil
+}
+
+// version returns schema version of storage set by bulk migration, 0 means migration was not performed
+func (s *schemaStorage) version(ctx context.Context) (int, error) {
+
+	info := new(schemaInfo)
+
+	if err := s.storage.Get(ctx, s.Collection().System(), schemaName, info, nil); err != nil {
+		if derrors.Storage().IsErrEntityNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return info.Version, nil
+}
+
+// collections returns all collections with stored objects except system collection
+func (s *schemaStorage) collections(ctx context.Context) ([]string, error) {
+
+	c := s.Collection()
+
+	collections := []string{
+		c.Namespace(), c.Service(), c.Deployment(), c.Pod(), c.Endpoint(),
+		c.Config(), c.Policy(), c.Secret(), c.Volume(), c.Route(), c.Job(), c.Task(), c.Subnet(),
+		c.Node().Info(), c.Node().Status(),
+		c.Ingress().Info(), c.Ingress().Status(),
+		c.Discovery().Info(), c.Discovery().Status(),
+		c.Exporter().Info(), c.Exporter().Status(),
+		c.Manifest().Subnet(), c.Manifest().Endpoint(), c.Manifest().Secret(),
+		c.IPAM().Pool(),
+	}
+
+	nodes := newRawMap()
+	if err := s.storage.Map(ctx, c.Node().Info(), "", nodes, nil); err != nil {
+		return nil, err
+	}
+
+	for node := range nodes.Items {
+		collections = append(collections, c.Manifest().Pod(node), c.Manifest().Volume(node))
+	}
+
+	ingress := newRawMap()
+	if err := s.storage.Map(ctx, c.Ingress().Info(), "", ingress, nil); err != nil {
+		return nil, err
+	}
+
+	for name := range ingress.Items {
+		collections = append(collections, c.Manifest().Route(name))
+	}
+
+	pools := newRawMap()
+	if err := s.storage.Map(ctx, c.IPAM().Pool(), "", pools, nil); err != nil {
+		return nil, err
+	}
+
+	for name := range pools.Items {
+		collections = append(collections, c.IPAM().Lease(name))
+	}
+
+	return collections, nil
+}
+
+// migrate upgrades stored object, objects changed during migration are skipped,
+// because they are already stored with the current schema version
+func (s *schemaStorage) migrate(ctx context.Context, collection, name string, item *raw) (bool, error) {
+
+	data, upgraded, err := s.schema.Upgrade(collection, item.data)
+	if err != nil || !upgraded {
+		return false, err
+	}
+
+	rev := item.Storage.Revision
+	opts := GetOpts()
+	opts.Rev = &rev
+
+	if err := s.storage.Set(ctx, collection, name, &raw{data: data}, opts); err != nil {
+		if derrors.Storage().IsErrEntityConflict(err) || derrors.Storage().IsErrEntityNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Migrate upgrades all stored objects to the current schema version
+// and returns count of upgraded objects
+func Migrate(ctx context.Context, stg Storage) (int, error) {
+
+	s, ok := stg.(*schemaStorage)
+	if !ok {
+		return 0, errors.New("storage schema is not supported")
+	}
+
+	if err := CheckSchema(ctx, stg); err != nil {
+		return 0, err
+	}
+
+	collections, err := s.collections(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	for _, c := range collections {
+
+		items := newRawMap()
+		if err := s.storage.Map(ctx, c, "", items, nil); err != nil {
+			return count, err
+		}
+
+		for name, item := range items.Items {
+
+			ok, err := s.migrate(ctx, c, name, item)
+			if err != nil {
+				log.Errorf("%s:migrate:> upgrade %s/%s err: %v", logSchemaPrefix, c, name, err)
+				return count, err
+			}
+
+			if ok {
+				count++
+			}
+		}
+
+		log.V(logLevel).Debugf("%s:migrate:> collection %s migrated", logSchemaPrefix, c)
+	}
+
+	// cluster and network are single objects stored without name
+	for _, c := range []string{s.Collection().Cluster(), s.Collection().Network()} {
+
+		item := new(raw)
+		if err := s.storage.Get(ctx, c, "", item, nil); err != nil {
+			if derrors.Storage().IsErrEntityNotFound(err) {
+				continue
+			}
+			return count, err
+		}
+
+		ok, err := s.migrate(ctx, c, "", item)
+		if err != nil {
+			log.Errorf("%s:migrate:> upgrade %s err: %v", logSchemaPrefix, c, err)
+			return count, err
+		}
+
+		if ok {
+			count++
+		}
+	}
+
+	opts := GetOpts()
+	opts.Force = true
+
+	info := &schemaInfo{Version: s.schema.Version()}
+	if err := s.storage.Set(ctx, s.Collection().System(), schemaName, info, opts); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// CheckSchema returns error if storage contains objects of newer schema version,
+// which can not be handled safely
+func CheckSchema(ctx context.Context, stg Storage) error {
+
+	s, ok := stg.(*schemaStorage)
+	if !ok {
+		return nil
+	}
+
+	version, err := s.version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version > s.schema.Version() {
+		return errors.New(types.ErrSchemaIsNewer)
+	}
+
+	if version < s.schema.Version() {
+		log.Warnf("%s:> storage schema version %d is older than %d, run migration to upgrade stored objects",
+			logSchemaPrefix, version, s.schema.Version())
+	}
+
+	return nil
+}
+
+// setRevision sets storage revision into System field of object if it exists
+func setRevision(v reflect.Value, rev int64) {
+
+	if rev == 0 {
+		return
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(dtypes.System{}) {
+		return
+	}
+
+	f.FieldByName("Storage").FieldByName("Revision").SetInt(rev)
+}
+
+// setSystem sets storage runtime info of list into System field of object if it exists
+func setSystem(v reflect.Value, system dtypes.System) {
+
+	f := v.FieldByName("System")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(system) {
+		return
+	}
+
+	f.Set(reflect.ValueOf(system))
+}
@@ -0,0 +1,28 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package schema
+
+// Version is a current schema version of stored distribution types.
+// Version 1 objects are stored without schema version,
+// since version 2 container port host port and host ip are stored in host_port and host_ip fields.
+// Version 1 container ports have neither of them, both fields were dropped by duplicated host_port tag,
+// so there is nothing to migrate
+const Version = 2
+
+var migrations = []*Migration{}
@@ -0,0 +1,180 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+)
+
+// VersionKey is a name of field with schema version in every stored object
+const VersionKey = "schema_version"
+
+// Objects stored without schema version are treated as version 1
+const initialVersion = 1
+
+// Migration upgrades stored objects from Version to the next version
+type Migration struct {
+	// Version of objects the migration is applied to
+	Version int
+	// Collections the migration is applied to, nested collections are matched too.
+	// Empty list means all collections
+	Collections []string
+	// Migrate changes decoded object in place
+	Migrate func(obj map[string]interface{}) error
+}
+
+type Schema struct {
+	version    int
+	migrations []*Migration
+}
+
+// New returns schema of version with migrations from previous versions
+func New(version int, migrations ...*Migration) *Schema {
+	s := new(Schema)
+	s.version = version
+	s.migrations = migrations
+	return s
+}
+
+// Default returns current schema of distribution types
+func Default() *Schema {
+	return New(Version, migrations...)
+}
+
+func (s *Schema) Version() int {
+	return s.version
+}
+
+// Encode tags object data with schema version before it is stored.
+// Data already tagged with schema version is upgraded to the current version
+func (s *Schema) Encode(collection string, data []byte) ([]byte, error) {
+
+	fields, ok := object(data)
+	if !ok {
+		return data, nil
+	}
+
+	if _, ok := fields[VersionKey]; ok {
+		data, _, err := s.Upgrade(collection, data)
+		return data, err
+	}
+
+	fields[VersionKey] = version(s.version)
+	return json.Marshal(fields)
+}
+
+// Upgrade applies migrations to object data stored in collection
+// and returns data of the current schema version.
+// Upgraded flag is false if data was already of the current version.
+// Data of newer version than schema version can not be upgraded
+func (s *Schema) Upgrade(collection string, data []byte) ([]byte, bool, error) {
+
+	fields, ok := object(data)
+	if !ok {
+		return data, false, nil
+	}
+
+	v := initialVersion
+
+	if raw, ok := fields[VersionKey]; ok {
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if v > s.version {
+		return nil, false, errors.New(types.ErrSchemaIsNewer)
+	}
+
+	if v == s.version {
+		return data, false, nil
+	}
+
+	obj := make(map[string]interface{})
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&obj); err != nil {
+		return nil, false, err
+	}
+
+	for ; v < s.version; v++ {
+		for _, m := range s.migrations {
+
+			if m.Version != v || !m.match(collection) {
+				continue
+			}
+
+			if err := m.Migrate(obj); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	obj[VersionKey] = s.version
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (m *Migration) match(collection string) bool {
+
+	if len(m.Collections) == 0 {
+		return true
+	}
+
+	for _, c := range m.Collections {
+		if collection == c || strings.HasPrefix(collection, c+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// object decodes top level fields of data if it is JSON object
+func object(data []byte) (map[string]json.RawMessage, bool) {
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, false
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false
+	}
+
+	return fields, true
+}
+
+func version(v int) json.RawMessage {
+	buf, _ := json.Marshal(v)
+	return buf
+}
@@ -0,0 +1,166 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/storage/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Encode(t *testing.T) {
+
+	s := schema.New(2)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+		err  string
+	}{
+		{
+			name: "object without version",
+			data: `{"name":"demo"}`,
+			want: `{"name":"demo","schema_version":2}`,
+		},
+		{
+			name: "object with current version",
+			data: `{"name":"demo","schema_version":2}`,
+			want: `{"name":"demo","schema_version":2}`,
+		},
+		{
+			name: "object with newer version",
+			data: `{"name":"demo","schema_version":3}`,
+			err:  errors.ErrSchemaIsNewer,
+		},
+		{
+			name: "not object",
+			data: `"demo"`,
+			want: `"demo"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			data, err := s.Encode("test", []byte(tt.data))
+			if tt.err != "" {
+				if assert.Error(t, err, "expected err") {
+					assert.Equal(t, tt.err, err.Error(), "err message different")
+				}
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tt.want, string(data), "data different")
+			}
+		})
+	}
+}
+
+func TestSchema_Upgrade(t *testing.T) {
+
+	var applied []string
+
+	migration := func(name string) func(obj map[string]interface{}) error {
+		return func(obj map[string]interface{}) error {
+			applied = append(applied, name)
+			obj[name] = true
+			return nil
+		}
+	}
+
+	s := schema.New(3,
+		&schema.Migration{Version: 1, Migrate: migration("v1")},
+		&schema.Migration{Version: 1, Collections: []string{"service"}, Migrate: migration("v1-service")},
+		&schema.Migration{Version: 2, Migrate: migration("v2")},
+	)
+
+	tests := []struct {
+		name       string
+		collection string
+		data       string
+		want       string
+		applied    []string
+		upgraded   bool
+		err        string
+	}{
+		{
+			name:       "object without version",
+			collection: "service",
+			data:       `{"name":"demo"}`,
+			want:       `{"name":"demo","v1":true,"v1-service":true,"v2":true,"schema_version":3}`,
+			applied:    []string{"v1", "v1-service", "v2"},
+			upgraded:   true,
+		},
+		{
+			name:       "object in nested collection",
+			collection: "service/demo",
+			data:       `{"name":"demo","schema_version":1}`,
+			want:       `{"name":"demo","v1":true,"v1-service":true,"v2":true,"schema_version":3}`,
+			applied:    []string{"v1", "v1-service", "v2"},
+			upgraded:   true,
+		},
+		{
+			name:       "object of previous version",
+			collection: "pod",
+			data:       `{"name":"demo","schema_version":2}`,
+			want:       `{"name":"demo","v2":true,"schema_version":3}`,
+			applied:    []string{"v2"},
+			upgraded:   true,
+		},
+		{
+			name:       "object of current version",
+			collection: "pod",
+			data:       `{"name":"demo","schema_version":3}`,
+			want:       `{"name":"demo","schema_version":3}`,
+		},
+		{
+			name:       "object of newer version",
+			collection: "pod",
+			data:       `{"name":"demo","schema_version":4}`,
+			err:        errors.ErrSchemaIsNewer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			applied = nil
+
+			data, upgraded, err := s.Upgrade(tt.collection, []byte(tt.data))
+			if tt.err != "" {
+				if assert.Error(t, err, "expected err") {
+					assert.Equal(t, tt.err, err.Error(), "err message different")
+				}
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.JSONEq(t, tt.want, string(data), "data different")
+			assert.Equal(t, tt.upgraded, upgraded, "upgraded flag different")
+			assert.Equal(t, tt.applied, applied, "applied migrations different")
+		})
+	}
+}
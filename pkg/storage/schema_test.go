@@ -0,0 +1,209 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	dtypes "github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/storage/mock"
+	"github.com/lastbackend/lastbackend/pkg/storage/schema"
+	"github.com/lastbackend/lastbackend/pkg/storage/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaStorage(t *testing.T) {
+
+	assets := map[string]func(t *testing.T, stg storage.Storage){
+		"get":      storage.StorageGetAssets,
+		"list":     storage.StorageListAssets,
+		"page":     storage.StoragePageAssets,
+		"map":      storage.StorageMapAssets,
+		"put":      storage.StoragePutAssets,
+		"set":      storage.StorageSetAssets,
+		"revision": storage.StorageRevisionAssets,
+		"del":      storage.StorageDelAssets,
+		"delrev":   storage.StorageDelRevAssets,
+	}
+
+	for name, fn := range assets {
+		t.Run(name, func(t *testing.T) {
+			stg, _ := getSchemaStorage(t)
+			fn(t, stg)
+		})
+	}
+}
+
+func TestSchemaStorage_Upgrade(t *testing.T) {
+
+	var (
+		ctx      = context.Background()
+		stg, drv = getSchemaStorage(t)
+	)
+
+	type obj struct {
+		dtypes.System
+		Name    string `json:"name"`
+		Migrate bool   `json:"migrate"`
+	}
+
+	type list struct {
+		dtypes.System
+		Items []*obj
+	}
+
+	// objects stored before schema versioning
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Test(), "legacy", json.RawMessage(`{"name":"legacy"}`), nil))
+
+	item := &obj{Name: "demo"}
+	if !assert.NoError(t, stg.Put(ctx, stg.Collection().Test(), "demo", item, nil)) {
+		return
+	}
+
+	assert.NotZero(t, item.Storage.Revision, "revision is not set")
+
+	stored := make(map[string]interface{})
+	if assert.NoError(t, drv.Get(ctx, drv.Collection().Test(), "demo", &stored, nil)) {
+		assert.Equal(t, float64(2), stored[schema.VersionKey], "object is not tagged with schema version")
+	}
+
+	out := new(obj)
+	if assert.NoError(t, stg.Get(ctx, stg.Collection().Test(), "legacy", out, nil)) {
+		assert.True(t, out.Migrate, "object is not upgraded on get")
+		assert.NotZero(t, out.Storage.Revision, "revision is not set")
+	}
+
+	items := new(list)
+	if assert.NoError(t, stg.List(ctx, stg.Collection().Test(), "", items, nil)) && assert.Len(t, items.Items, 2) {
+		for _, i := range items.Items {
+			assert.Equal(t, i.Name == "legacy", i.Migrate, "object is not upgraded on list")
+			assert.NotZero(t, i.Storage.Revision, "revision is not set")
+		}
+	}
+
+	// objects written by newer version can not be handled
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Test(), "newer", json.RawMessage(`{"name":"newer","schema_version":3}`), nil))
+
+	err := stg.Get(ctx, stg.Collection().Test(), "newer", new(obj), nil)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrSchemaIsNewer, err.Error(), "err message different")
+	}
+}
+
+func TestSchemaStorage_Watch(t *testing.T) {
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		stg, drv    = getSchemaStorage(t)
+		event       = make(chan *types.WatcherEvent)
+	)
+
+	defer cancel()
+
+	go stg.Watch(ctx, stg.Collection().Root(), event, nil)
+
+	// wait for watcher registration
+	time.Sleep(50 * time.Millisecond)
+
+	go drv.Put(ctx, drv.Collection().Test(), "legacy", json.RawMessage(`{"name":"legacy"}`), nil)
+
+	select {
+	case e := <-event:
+		out := make(map[string]interface{})
+		if assert.NoError(t, json.Unmarshal(e.Data.([]byte), &out)) {
+			assert.Equal(t, true, out["migrate"], "event data is not upgraded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("event not received")
+	}
+}
+
+func TestSchemaStorage_Migrate(t *testing.T) {
+
+	var (
+		ctx      = context.Background()
+		stg, drv = getSchemaStorage(t)
+	)
+
+	svc := json.RawMessage(`{"meta":{"name":"demo"}}`)
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Service(), "demo:demo", svc, nil))
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Node().Info(), "node", json.RawMessage(`{"meta":{"name":"node"}}`), nil))
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Manifest().Pod("node"), "demo:pod", json.RawMessage(`{"state":"running"}`), nil))
+	assert.NoError(t, drv.Put(ctx, drv.Collection().Cluster(), "", json.RawMessage(`{"meta":{"name":"cluster"}}`), nil))
+
+	assert.NoError(t, storage.CheckSchema(ctx, stg))
+
+	count, err := storage.Migrate(ctx, stg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 4, count, "upgraded objects count different")
+
+	stored := make(map[string]interface{})
+	if assert.NoError(t, drv.Get(ctx, drv.Collection().Manifest().Pod("node"), "demo:pod", &stored, nil)) {
+		assert.Equal(t, float64(2), stored[schema.VersionKey], "object is not migrated")
+		assert.Equal(t, true, stored["migrate"], "object is not migrated")
+	}
+
+	// objects of current version are not changed
+	count, err = storage.Migrate(ctx, stg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, count, "upgraded objects count different")
+	}
+
+	// storage migrated by newer version can not be used
+	opts := storage.GetOpts()
+	opts.Force = true
+
+	assert.NoError(t, drv.Set(ctx, drv.Collection().System(), "schema", json.RawMessage(`{"version":3}`), opts))
+
+	err = storage.CheckSchema(ctx, stg)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrSchemaIsNewer, err.Error(), "err message different")
+	}
+
+	_, err = storage.Migrate(ctx, stg)
+	assert.Error(t, err, "expected err")
+}
+
+// getSchemaStorage returns storage with schema of version 2,
+// where objects of version 1 are marked with migrate field
+func getSchemaStorage(t *testing.T) (storage.Storage, storage.Storage) {
+
+	drv, err := mock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := schema.New(2, &schema.Migration{
+		Version: 1,
+		Migrate: func(obj map[string]interface{}) error {
+			obj["migrate"] = true
+			return nil
+		},
+	})
+
+	return storage.WithSchema(drv, s), drv
+}
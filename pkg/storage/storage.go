@@ -21,9 +21,11 @@ package storage
 import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/storage/bbolt"
 	"github.com/lastbackend/lastbackend/pkg/storage/etcd"
 	v3 "github.com/lastbackend/lastbackend/pkg/storage/etcd/v3"
 	"github.com/lastbackend/lastbackend/pkg/storage/mock"
+	"github.com/lastbackend/lastbackend/pkg/storage/schema"
 	"github.com/lastbackend/lastbackend/pkg/storage/types"
 	"github.com/spf13/viper"
 )
@@ -60,6 +62,7 @@ type Storage interface {
 	Put(ctx context.Context, collection, name string, obj interface{}, opts *types.Opts) error
 	Set(ctx context.Context, collection, name string, obj interface{}, opts *types.Opts) error
 	Del(ctx context.Context, collection, name string) error
+	DelRev(ctx context.Context, collection, name string, rev int64) error
 	Watch(ctx context.Context, collection string, event chan *types.WatcherEvent, opts *types.Opts) error
 	Collection() types.Collection
 	Filter() types.Filter
@@ -71,9 +74,23 @@ func Get(v *viper.Viper) (Storage, error) {
 		return nil, errors.New("storage driver not set")
 	}
 
+	var (
+		stg Storage
+		err error
+	)
+
 	switch v.GetString("storage.driver") {
 	case "mock":
-		return mock.New()
+		stg, err = mock.New()
+	case "bbolt":
+
+		config := new(bbolt.Config)
+
+		config.Path = v.GetString("storage.bbolt.path")
+		config.Prefix = v.GetString("storage.bbolt.prefix")
+		config.History = v.GetInt64("storage.bbolt.history")
+
+		stg, err = bbolt.New(config)
 	default:
 
 		config := new(v3.Config)
@@ -85,8 +102,23 @@ func Get(v *viper.Viper) (Storage, error) {
 		config.TLS.Cert = v.GetString("storage.etcd.tls.cert")
 		config.TLS.Key = v.GetString("storage.etcd.tls.key")
 
-		return etcd.New(config)
+		stg, err = etcd.New(config)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return WithSchema(stg, schema.Default()), nil
+}
+
+// CheckShared returns error if storage driver can not be used by components
+// running as separate processes: embedded database file is locked by one process
+func CheckShared(v *viper.Viper) error {
+	if v.GetString("storage.driver") == "bbolt" {
+		return errors.New("bbolt storage can not be shared between processes, run components with standalone command")
 	}
+	return nil
 }
 
 func GetOpts() *types.Opts {
@@ -244,6 +244,108 @@ func StorageListAssets(t *testing.T, stg Storage) {
 
 }
 
+func StoragePageAssets(t *testing.T, stg Storage) {
+
+	var ctx = context.Background()
+
+	type obj struct {
+		types.System
+		Name string `json:"name"`
+	}
+
+	type objl struct {
+		types.System
+		Items []*obj
+	}
+
+	err := stg.Del(ctx, stg.Collection().Test(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		err = stg.Put(ctx, stg.Collection().Test(), name, &obj{Name: name}, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	var (
+		opts  = GetOpts()
+		pages = [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	)
+
+	opts.Limit = 2
+
+	for i, page := range pages {
+
+		out := new(objl)
+		err := stg.List(ctx, stg.Collection().Test(), "", out, opts)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		names := make([]string, 0)
+		for _, o := range out.Items {
+			names = append(names, o.Name)
+		}
+
+		assert.Equal(t, page, names, "page items are different")
+		assert.Equal(t, int64(5), out.Storage.Total, "total count is different")
+
+		if i == len(pages)-1 {
+			assert.Empty(t, out.Storage.Continue, "continue token on the last page")
+			break
+		}
+
+		if !assert.NotEmpty(t, out.Storage.Continue, "continue token is empty") {
+			return
+		}
+
+		opts.Continue = out.Storage.Continue
+	}
+
+	match := GetOpts()
+	match.Limit = 2
+	match.Match = func(o interface{}) bool {
+		return o.(*obj).Name != "a" && o.(*obj).Name != "c"
+	}
+
+	for i, page := range [][]string{{"b", "d"}, {"e"}} {
+
+		out := new(objl)
+		err := stg.List(ctx, stg.Collection().Test(), "", out, match)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		names := make([]string, 0)
+		for _, o := range out.Items {
+			names = append(names, o.Name)
+		}
+
+		assert.Equal(t, page, names, "matched page items are different")
+		assert.Equal(t, int64(3), out.Storage.Total, "matched total count is different")
+
+		if i == 1 {
+			assert.Empty(t, out.Storage.Continue, "continue token on the last matched page")
+			break
+		}
+
+		if !assert.NotEmpty(t, out.Storage.Continue, "continue token is empty") {
+			return
+		}
+
+		match.Continue = out.Storage.Continue
+	}
+
+	opts.Continue = "invalid"
+	err = stg.List(ctx, stg.Collection().Test(), "", new(objl), opts)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrContinueIsInvalid, err.Error(), "err message different")
+	}
+}
+
 func StorageMapAssets(t *testing.T, stg Storage) {
 
 	var ctx = context.Background()
@@ -556,6 +658,78 @@ func StorageSetAssets(t *testing.T, stg Storage) {
 
 }
 
+func StorageRevisionAssets(t *testing.T, stg Storage) {
+
+	var ctx = context.Background()
+
+	type obj struct {
+		types.System
+		Name string `json:"name"`
+		Desc string `json:"desc"`
+	}
+
+	err := stg.Del(ctx, stg.Collection().Test(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = stg.Put(ctx, stg.Collection().Test(), "demo", &obj{Name: "demo", Desc: "demo"}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// unrelated changes should not break revision check of the entity
+	err = stg.Put(ctx, stg.Collection().Test(), "other", &obj{Name: "other", Desc: "other"}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	item := new(obj)
+	err = stg.Get(ctx, stg.Collection().Test(), "demo", item, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NotZero(t, item.Storage.Revision, "revision is not set") {
+		return
+	}
+
+	var (
+		rev  = item.Storage.Revision
+		opts = GetOpts()
+	)
+
+	opts.Rev = &rev
+	item.Desc = "test"
+
+	err = stg.Set(ctx, stg.Collection().Test(), "demo", item, opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotEqual(t, rev, item.Storage.Revision, "revision is not updated after set")
+
+	err = stg.Set(ctx, stg.Collection().Test(), "demo", item, opts)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityConflict, err.Error(), "err message different")
+	}
+
+	err = stg.Set(ctx, stg.Collection().Test(), "unknown", item, opts)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityNotFound, err.Error(), "err message different")
+	}
+
+	// revision should be set also when pointer to pointer is passed
+	out := new(obj)
+	err = stg.Get(ctx, stg.Collection().Test(), "demo", &out, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "test", out.Desc, "object received error")
+	assert.Equal(t, item.Storage.Revision, out.Storage.Revision, "revision different")
+}
+
 func StorageDelAssets(t *testing.T, stg Storage) {
 
 	var ctx = context.Background()
@@ -647,3 +821,49 @@ func StorageDelAssets(t *testing.T, stg Storage) {
 	}
 
 }
+
+func StorageDelRevAssets(t *testing.T, stg Storage) {
+
+	var ctx = context.Background()
+
+	type obj struct {
+		types.System
+		Name string `json:"name"`
+	}
+
+	err := stg.Del(ctx, stg.Collection().Test(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	o := &obj{Name: "demo"}
+	if err := stg.Put(ctx, stg.Collection().Test(), o.Name, o, nil); !assert.NoError(t, err) {
+		return
+	}
+
+	prev := o.Storage.Revision
+
+	if err := stg.Set(ctx, stg.Collection().Test(), o.Name, o, nil); !assert.NoError(t, err) {
+		return
+	}
+
+	err = stg.DelRev(ctx, stg.Collection().Test(), o.Name, prev)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityConflict, err.Error(), "err message different")
+	}
+
+	err = stg.DelRev(ctx, stg.Collection().Test(), o.Name, o.Storage.Revision)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = stg.Get(ctx, stg.Collection().Test(), o.Name, new(obj), nil)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityNotFound, err.Error(), "err message different")
+	}
+
+	err = stg.DelRev(ctx, stg.Collection().Test(), o.Name, o.Storage.Revision)
+	if assert.Error(t, err, "expected err") {
+		assert.Equal(t, errors.ErrEntityNotFound, err.Error(), "err message different")
+	}
+}
@@ -33,9 +33,11 @@ type Collection interface {
 	Volume() string
 	Secret() string
 	Config() string
+	Policy() string
 	Endpoint() string
 	Network() string
 	Subnet() string
+	IPAM() IPAMCollection
 	Manifest() ManifestCollection
 	Job() string
 	Task() string
@@ -55,6 +57,11 @@ type ManifestCollection interface {
 	Endpoint() string
 }
 
+type IPAMCollection interface {
+	Pool() string
+	Lease(pool string) string
+}
+
 type NodeCollection interface {
 	Info() string
 	Status() string
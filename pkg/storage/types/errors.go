@@ -29,4 +29,8 @@ const (
 	ErrStructArgIsInvalid    = errors.ErrStructArgIsInvalid
 	ErrStructOutIsInvalid    = errors.ErrStructOutIsInvalid
 	ErrStructOutIsNotPointer = errors.ErrStructOutIsNotPointer
+	ErrContinueIsInvalid     = errors.ErrContinueIsInvalid
+	ErrContinueIsExpired     = errors.ErrContinueIsExpired
+	ErrEntityConflict        = errors.ErrEntityConflict
+	ErrSchemaIsNewer         = errors.ErrSchemaIsNewer
 )
@@ -22,6 +22,7 @@ type Filter interface {
 	Namespace() NamespaceFilter
 	Service() ServiceFilter
 	Config() ConfigFilter
+	Policy() PolicyFilter
 	Deployment() DeploymentFilter
 	Pod() PodFilter
 	Endpoint() EndpointFilter
@@ -68,6 +69,10 @@ type ConfigFilter interface {
 	ByNamespace(namespace string) string
 }
 
+type PolicyFilter interface {
+	ByNamespace(namespace string) string
+}
+
 type VolumeFilter interface {
 	ByNamespace(namespace string) string
 }
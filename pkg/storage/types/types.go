@@ -18,7 +18,15 @@
 
 package types
 
-import "github.com/lastbackend/lastbackend/pkg/distribution/types"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
 
 const (
 	STORAGEDELETEEVENT = types.EventActionDelete
@@ -63,6 +71,95 @@ type Opts struct {
 	Ttl   uint64
 	Force bool
 	Rev   *int64
+	// Limit of items returned by list request, 0 means no limit
+	Limit int64
+	// Continue token returned by previous list request
+	Continue string
+	// Match filters listed objects before paging, nil matches all objects
+	Match func(obj interface{}) bool
+}
+
+// Continue describes position of the next list page in storage
+type Continue struct {
+	Rev int64  `json:"rev"`
+	Key string `json:"key"`
+}
+
+func (c *Continue) Encode() string {
+	buf, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func DecodeContinue(token string) (*Continue, error) {
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New(ErrContinueIsInvalid)
+	}
+
+	c := new(Continue)
+	if err := json.Unmarshal(buf, c); err != nil || c.Key == "" {
+		return nil, errors.New(ErrContinueIsInvalid)
+	}
+
+	return c, nil
+}
+
+// MatchPage leaves in items slice only objects matched by opts and returns total of matched objects
+// with continue token of the next page. Keys are storage keys of items sorted in ascending order,
+// all items from list beginning should be passed to count total
+func MatchPage(items reflect.Value, keys []string, prefix string, rev int64, opts *Opts) (int64, string, error) {
+
+	var (
+		start string
+		last  string
+		total int64
+		more  bool
+		page  = reflect.MakeSlice(items.Type(), 0, 0)
+	)
+
+	if opts.Continue != "" {
+		c, err := DecodeContinue(opts.Continue)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if !strings.HasPrefix(c.Key, prefix) {
+			return 0, "", errors.New(ErrContinueIsInvalid)
+		}
+
+		start = c.Key
+	}
+
+	for i := 0; i < items.Len() && i < len(keys); i++ {
+
+		if opts.Match != nil && !opts.Match(items.Index(i).Interface()) {
+			continue
+		}
+
+		total++
+
+		if start != "" && keys[i] <= start {
+			continue
+		}
+
+		if opts.Limit > 0 && int64(page.Len()) >= opts.Limit {
+			more = true
+			continue
+		}
+
+		page = reflect.Append(page, items.Index(i))
+		last = keys[i]
+	}
+
+	items.Set(page)
+
+	if !more {
+		return total, "", nil
+	}
+
+	c := Continue{Rev: rev, Key: last}
+	return total, c.Encode(), nil
 }
 
 type System struct {
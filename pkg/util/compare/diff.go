@@ -0,0 +1,191 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change describes a single field which differs between two values
+type Change struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Diff compares json representations of a and b and returns changed fields
+// sorted by path. Fields with names listed in ignore are skipped at any depth.
+func Diff(a, b interface{}, ignore ...string) ([]*Change, error) {
+
+	var (
+		from, to interface{}
+		skip     = make(map[string]bool, len(ignore))
+		changes  = make([]*Change, 0)
+	)
+
+	for _, i := range ignore {
+		skip[i] = true
+	}
+
+	if err := normalize(a, &from); err != nil {
+		return nil, err
+	}
+
+	if err := normalize(b, &to); err != nil {
+		return nil, err
+	}
+
+	walk(&changes, skip, "", from, to)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// Copy makes a deep copy of src json representation in dst, so the copy
+// is compared by Diff the same way as src. Fields hidden from json are not copied.
+func Copy(src, dst interface{}) error {
+
+	buf, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf, dst)
+}
+
+func normalize(in interface{}, out *interface{}) error {
+
+	if in == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf, out)
+}
+
+func walk(changes *[]*Change, skip map[string]bool, path string, from, to interface{}) {
+
+	// compare a missing value as an empty one of the same kind
+	// to report created and removed objects field by field
+	if from == nil {
+		from = zero(to)
+	}
+
+	if to == nil {
+		to = zero(from)
+	}
+
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make(map[string]bool, 0)
+		for k := range f {
+			keys[k] = true
+		}
+		for k := range t {
+			keys[k] = true
+		}
+
+		for k := range keys {
+			if skip[k] {
+				continue
+			}
+			walk(changes, skip, join(path, k), f[k], t[k])
+		}
+		return
+
+	case []interface{}:
+		t, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+
+		l := len(f)
+		if len(t) > l {
+			l = len(t)
+		}
+
+		for i := 0; i < l; i++ {
+			var fi, ti interface{}
+			if i < len(f) {
+				fi = f[i]
+			}
+			if i < len(t) {
+				ti = t[i]
+			}
+			walk(changes, skip, fmt.Sprintf("%s[%d]", path, i), fi, ti)
+		}
+		return
+	}
+
+	if empty(from) && empty(to) {
+		return
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+
+	*changes = append(*changes, &Change{Path: path, From: from, To: to})
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// empty treats missing, null and zero-length values as equal,
+// so that nil and empty collections do not produce changes
+func empty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func zero(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		return make(map[string]interface{}, 0)
+	case []interface{}:
+		return make([]interface{}, 0)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/util/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+type diffSpec struct {
+	Replicas int               `json:"replicas"`
+	Image    string            `json:"image"`
+	Env      map[string]string `json:"env"`
+	Ports    []int             `json:"ports"`
+	Updated  string            `json:"updated"`
+}
+
+func TestDiff(t *testing.T) {
+
+	tests := []struct {
+		name string
+		a    interface{}
+		b    interface{}
+		want []*compare.Change
+	}{
+		{
+			name: "equal values",
+			a:    diffSpec{Replicas: 1, Image: "redis", Env: map[string]string{}},
+			b:    diffSpec{Replicas: 1, Image: "redis"},
+			want: []*compare.Change{},
+		},
+		{
+			name: "changed fields",
+			a:    diffSpec{Replicas: 1, Image: "redis", Env: map[string]string{"A": "1"}, Ports: []int{80}},
+			b:    diffSpec{Replicas: 2, Image: "redis", Env: map[string]string{"B": "2"}, Ports: []int{80, 443}},
+			want: []*compare.Change{
+				{Path: "env.A", From: "1"},
+				{Path: "env.B", To: "2"},
+				{Path: "ports[1]", To: float64(443)},
+				{Path: "replicas", From: float64(1), To: float64(2)},
+			},
+		},
+		{
+			name: "ignored fields",
+			a:    diffSpec{Image: "redis", Updated: "yesterday"},
+			b:    diffSpec{Image: "redis", Updated: "today"},
+			want: []*compare.Change{},
+		},
+		{
+			name: "create from nil",
+			a:    nil,
+			b:    map[string]string{"image": "redis"},
+			want: []*compare.Change{
+				{Path: "image", To: "redis"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := compare.Diff(tc.a, tc.b, "updated")
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, changes)
+		})
+	}
+}
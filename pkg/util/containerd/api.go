@@ -0,0 +1,738 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import "github.com/golang/protobuf/proto"
+
+// Messages below are a subset of the CRI runtime.v1 API served by the containerd
+// cri plugin. Only fields used by lastbackend runtime drivers are declared,
+// field numbers must match k8s.io/cri-api/pkg/apis/runtime/v1/api.proto.
+
+type NamespaceMode int32
+
+const (
+	NamespaceModePod       NamespaceMode = 0
+	NamespaceModeContainer NamespaceMode = 1
+	NamespaceModeNode      NamespaceMode = 2
+)
+
+type Protocol int32
+
+const (
+	ProtocolTCP  Protocol = 0
+	ProtocolUDP  Protocol = 1
+	ProtocolSCTP Protocol = 2
+)
+
+type PodSandboxState int32
+
+const (
+	SandboxReady    PodSandboxState = 0
+	SandboxNotReady PodSandboxState = 1
+)
+
+type ContainerState int32
+
+const (
+	ContainerCreated ContainerState = 0
+	ContainerRunning ContainerState = 1
+	ContainerExited  ContainerState = 2
+	ContainerUnknown ContainerState = 3
+)
+
+type ContainerEventType int32
+
+const (
+	ContainerCreatedEvent ContainerEventType = 0
+	ContainerStartedEvent ContainerEventType = 1
+	ContainerStoppedEvent ContainerEventType = 2
+	ContainerDeletedEvent ContainerEventType = 3
+)
+
+type VersionRequest struct {
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+type VersionResponse struct {
+	Version           string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	RuntimeName       string `protobuf:"bytes,2,opt,name=runtime_name,proto3" json:"runtime_name,omitempty"`
+	RuntimeVersion    string `protobuf:"bytes,3,opt,name=runtime_version,proto3" json:"runtime_version,omitempty"`
+	RuntimeApiVersion string `protobuf:"bytes,4,opt,name=runtime_api_version,proto3" json:"runtime_api_version,omitempty"`
+}
+
+type DNSConfig struct {
+	Servers  []string `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	Searches []string `protobuf:"bytes,2,rep,name=searches,proto3" json:"searches,omitempty"`
+	Options  []string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+}
+
+type PortMapping struct {
+	Protocol      Protocol `protobuf:"varint,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ContainerPort int32    `protobuf:"varint,2,opt,name=container_port,proto3" json:"container_port,omitempty"`
+	HostPort      int32    `protobuf:"varint,3,opt,name=host_port,proto3" json:"host_port,omitempty"`
+	HostIp        string   `protobuf:"bytes,4,opt,name=host_ip,proto3" json:"host_ip,omitempty"`
+}
+
+type Mount struct {
+	ContainerPath string `protobuf:"bytes,1,opt,name=container_path,proto3" json:"container_path,omitempty"`
+	HostPath      string `protobuf:"bytes,2,opt,name=host_path,proto3" json:"host_path,omitempty"`
+	Readonly      bool   `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+}
+
+type NamespaceOption struct {
+	Network NamespaceMode `protobuf:"varint,1,opt,name=network,proto3" json:"network,omitempty"`
+	Pid     NamespaceMode `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	Ipc     NamespaceMode `protobuf:"varint,3,opt,name=ipc,proto3" json:"ipc,omitempty"`
+}
+
+type Int64Value struct {
+	Value int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+type UInt64Value struct {
+	Value uint64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+type LinuxSandboxSecurityContext struct {
+	NamespaceOptions *NamespaceOption `protobuf:"bytes,1,opt,name=namespace_options,proto3" json:"namespace_options,omitempty"`
+	Privileged       bool             `protobuf:"varint,6,opt,name=privileged,proto3" json:"privileged,omitempty"`
+}
+
+type LinuxPodSandboxConfig struct {
+	CgroupParent    string                       `protobuf:"bytes,1,opt,name=cgroup_parent,proto3" json:"cgroup_parent,omitempty"`
+	SecurityContext *LinuxSandboxSecurityContext `protobuf:"bytes,2,opt,name=security_context,proto3" json:"security_context,omitempty"`
+}
+
+type PodSandboxMetadata struct {
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Uid       string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Attempt   uint32 `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+}
+
+type PodSandboxConfig struct {
+	Metadata     *PodSandboxMetadata    `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Hostname     string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	LogDirectory string                 `protobuf:"bytes,3,opt,name=log_directory,proto3" json:"log_directory,omitempty"`
+	DnsConfig    *DNSConfig             `protobuf:"bytes,4,opt,name=dns_config,proto3" json:"dns_config,omitempty"`
+	PortMappings []*PortMapping         `protobuf:"bytes,5,rep,name=port_mappings,proto3" json:"port_mappings,omitempty"`
+	Labels       map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations  map[string]string      `protobuf:"bytes,7,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Linux        *LinuxPodSandboxConfig `protobuf:"bytes,8,opt,name=linux,proto3" json:"linux,omitempty"`
+}
+
+type RunPodSandboxRequest struct {
+	Config         *PodSandboxConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	RuntimeHandler string            `protobuf:"bytes,2,opt,name=runtime_handler,proto3" json:"runtime_handler,omitempty"`
+}
+
+type RunPodSandboxResponse struct {
+	PodSandboxId string `protobuf:"bytes,1,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+}
+
+type StopPodSandboxRequest struct {
+	PodSandboxId string `protobuf:"bytes,1,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+}
+
+type StopPodSandboxResponse struct{}
+
+type RemovePodSandboxRequest struct {
+	PodSandboxId string `protobuf:"bytes,1,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+}
+
+type RemovePodSandboxResponse struct{}
+
+type PodSandboxStatusRequest struct {
+	PodSandboxId string `protobuf:"bytes,1,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+	Verbose      bool   `protobuf:"varint,2,opt,name=verbose,proto3" json:"verbose,omitempty"`
+}
+
+type PodSandboxNetworkStatus struct {
+	Ip            string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	AdditionalIps []*PodIP `protobuf:"bytes,2,rep,name=additional_ips,json=additionalIps,proto3" json:"additional_ips,omitempty"`
+}
+
+type PodIP struct {
+	Ip string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+type PodSandboxStatus struct {
+	Id        string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata  *PodSandboxMetadata      `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	State     PodSandboxState          `protobuf:"varint,3,opt,name=state,proto3" json:"state,omitempty"`
+	CreatedAt int64                    `protobuf:"varint,4,opt,name=created_at,proto3" json:"created_at,omitempty"`
+	Network   *PodSandboxNetworkStatus `protobuf:"bytes,5,opt,name=network,proto3" json:"network,omitempty"`
+	Labels    map[string]string        `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type PodSandboxStatusResponse struct {
+	Status *PodSandboxStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+type PodSandboxStateValue struct {
+	State PodSandboxState `protobuf:"varint,1,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+type PodSandboxFilter struct {
+	Id            string                `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         *PodSandboxStateValue `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	LabelSelector map[string]string     `protobuf:"bytes,3,rep,name=label_selector,proto3" json:"label_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type ListPodSandboxRequest struct {
+	Filter *PodSandboxFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+type PodSandbox struct {
+	Id        string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata  *PodSandboxMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	State     PodSandboxState     `protobuf:"varint,3,opt,name=state,proto3" json:"state,omitempty"`
+	CreatedAt int64               `protobuf:"varint,4,opt,name=created_at,proto3" json:"created_at,omitempty"`
+	Labels    map[string]string   `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type ListPodSandboxResponse struct {
+	Items []*PodSandbox `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+type ImageSpec struct {
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+type KeyValue struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+type LinuxContainerResources struct {
+	CpuPeriod          int64 `protobuf:"varint,1,opt,name=cpu_period,proto3" json:"cpu_period,omitempty"`
+	CpuQuota           int64 `protobuf:"varint,2,opt,name=cpu_quota,proto3" json:"cpu_quota,omitempty"`
+	CpuShares          int64 `protobuf:"varint,3,opt,name=cpu_shares,proto3" json:"cpu_shares,omitempty"`
+	MemoryLimitInBytes int64 `protobuf:"varint,4,opt,name=memory_limit_in_bytes,proto3" json:"memory_limit_in_bytes,omitempty"`
+}
+
+type Capability struct {
+	AddCapabilities  []string `protobuf:"bytes,1,rep,name=add_capabilities,proto3" json:"add_capabilities,omitempty"`
+	DropCapabilities []string `protobuf:"bytes,2,rep,name=drop_capabilities,proto3" json:"drop_capabilities,omitempty"`
+}
+
+type SELinuxOption struct {
+	User  string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Role  string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Type  string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Level string `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+}
+
+type LinuxContainerSecurityContext struct {
+	Capabilities       *Capability      `protobuf:"bytes,1,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Privileged         bool             `protobuf:"varint,2,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	NamespaceOptions   *NamespaceOption `protobuf:"bytes,3,opt,name=namespace_options,proto3" json:"namespace_options,omitempty"`
+	SelinuxOptions     *SELinuxOption   `protobuf:"bytes,4,opt,name=selinux_options,proto3" json:"selinux_options,omitempty"`
+	RunAsUser          *Int64Value      `protobuf:"bytes,5,opt,name=run_as_user,proto3" json:"run_as_user,omitempty"`
+	ReadonlyRootfs     bool             `protobuf:"varint,7,opt,name=readonly_rootfs,proto3" json:"readonly_rootfs,omitempty"`
+	SupplementalGroups []int64          `protobuf:"varint,8,rep,packed,name=supplemental_groups,proto3" json:"supplemental_groups,omitempty"`
+	ApparmorProfile    string           `protobuf:"bytes,9,opt,name=apparmor_profile,proto3" json:"apparmor_profile,omitempty"`
+	SeccompProfilePath string           `protobuf:"bytes,10,opt,name=seccomp_profile_path,proto3" json:"seccomp_profile_path,omitempty"`
+	NoNewPrivs         bool             `protobuf:"varint,11,opt,name=no_new_privs,proto3" json:"no_new_privs,omitempty"`
+	RunAsGroup         *Int64Value      `protobuf:"bytes,12,opt,name=run_as_group,proto3" json:"run_as_group,omitempty"`
+}
+
+type LinuxContainerConfig struct {
+	Resources       *LinuxContainerResources       `protobuf:"bytes,1,opt,name=resources,proto3" json:"resources,omitempty"`
+	SecurityContext *LinuxContainerSecurityContext `protobuf:"bytes,2,opt,name=security_context,proto3" json:"security_context,omitempty"`
+}
+
+type ContainerMetadata struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Attempt uint32 `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+}
+
+type ContainerConfig struct {
+	Metadata    *ContainerMetadata    `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Image       *ImageSpec            `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Command     []string              `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	Args        []string              `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	WorkingDir  string                `protobuf:"bytes,5,opt,name=working_dir,proto3" json:"working_dir,omitempty"`
+	Envs        []*KeyValue           `protobuf:"bytes,6,rep,name=envs,proto3" json:"envs,omitempty"`
+	Mounts      []*Mount              `protobuf:"bytes,7,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Labels      map[string]string     `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations map[string]string     `protobuf:"bytes,10,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LogPath     string                `protobuf:"bytes,11,opt,name=log_path,proto3" json:"log_path,omitempty"`
+	Linux       *LinuxContainerConfig `protobuf:"bytes,15,opt,name=linux,proto3" json:"linux,omitempty"`
+}
+
+type CreateContainerRequest struct {
+	PodSandboxId  string            `protobuf:"bytes,1,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+	Config        *ContainerConfig  `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	SandboxConfig *PodSandboxConfig `protobuf:"bytes,3,opt,name=sandbox_config,proto3" json:"sandbox_config,omitempty"`
+}
+
+type CreateContainerResponse struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+}
+
+type StartContainerRequest struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+}
+
+type StartContainerResponse struct{}
+
+type StopContainerRequest struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+	Timeout     int64  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+type StopContainerResponse struct{}
+
+type RemoveContainerRequest struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+}
+
+type RemoveContainerResponse struct{}
+
+type ExecSyncRequest struct {
+	ContainerId string   `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+	Cmd         []string `protobuf:"bytes,2,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	Timeout     int64    `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+type ExecSyncResponse struct {
+	Stdout   []byte `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr   []byte `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	ExitCode int32  `protobuf:"varint,3,opt,name=exit_code,proto3" json:"exit_code,omitempty"`
+}
+
+type ContainerStatsRequest struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+}
+
+type ContainerStatsResponse struct {
+	Stats *ContainerStats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+}
+
+type ContainerAttributes struct {
+	Id          string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata    *ContainerMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Labels      map[string]string  `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations map[string]string  `protobuf:"bytes,4,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type ContainerStats struct {
+	Attributes    *ContainerAttributes `protobuf:"bytes,1,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	Cpu           *CpuUsage            `protobuf:"bytes,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory        *MemoryUsage         `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	WritableLayer *FilesystemUsage     `protobuf:"bytes,4,opt,name=writable_layer,proto3" json:"writable_layer,omitempty"`
+}
+
+type CpuUsage struct {
+	Timestamp            int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	UsageCoreNanoSeconds *UInt64Value `protobuf:"bytes,2,opt,name=usage_core_nano_seconds,proto3" json:"usage_core_nano_seconds,omitempty"`
+}
+
+type MemoryUsage struct {
+	Timestamp       int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	WorkingSetBytes *UInt64Value `protobuf:"bytes,2,opt,name=working_set_bytes,proto3" json:"working_set_bytes,omitempty"`
+	RssBytes        *UInt64Value `protobuf:"bytes,5,opt,name=rss_bytes,proto3" json:"rss_bytes,omitempty"`
+}
+
+type FilesystemIdentifier struct {
+	Mountpoint string `protobuf:"bytes,1,opt,name=mountpoint,proto3" json:"mountpoint,omitempty"`
+}
+
+type FilesystemUsage struct {
+	Timestamp  int64                 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	FsId       *FilesystemIdentifier `protobuf:"bytes,2,opt,name=fs_id,proto3" json:"fs_id,omitempty"`
+	UsedBytes  *UInt64Value          `protobuf:"bytes,3,opt,name=used_bytes,proto3" json:"used_bytes,omitempty"`
+	InodesUsed *UInt64Value          `protobuf:"bytes,4,opt,name=inodes_used,proto3" json:"inodes_used,omitempty"`
+}
+
+type ContainerStateValue struct {
+	State ContainerState `protobuf:"varint,1,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+type ContainerFilter struct {
+	Id            string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         *ContainerStateValue `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	PodSandboxId  string               `protobuf:"bytes,3,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+	LabelSelector map[string]string    `protobuf:"bytes,4,rep,name=label_selector,proto3" json:"label_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type ListContainersRequest struct {
+	Filter *ContainerFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+type Container struct {
+	Id           string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PodSandboxId string             `protobuf:"bytes,2,opt,name=pod_sandbox_id,proto3" json:"pod_sandbox_id,omitempty"`
+	Metadata     *ContainerMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Image        *ImageSpec         `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	ImageRef     string             `protobuf:"bytes,5,opt,name=image_ref,proto3" json:"image_ref,omitempty"`
+	State        ContainerState     `protobuf:"varint,6,opt,name=state,proto3" json:"state,omitempty"`
+	CreatedAt    int64              `protobuf:"varint,7,opt,name=created_at,proto3" json:"created_at,omitempty"`
+	Labels       map[string]string  `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type ListContainersResponse struct {
+	Containers []*Container `protobuf:"bytes,1,rep,name=containers,proto3" json:"containers,omitempty"`
+}
+
+type ContainerStatusRequest struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+	Verbose     bool   `protobuf:"varint,2,opt,name=verbose,proto3" json:"verbose,omitempty"`
+}
+
+type ContainerStatus struct {
+	Id          string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata    *ContainerMetadata  `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	State       ContainerState      `protobuf:"varint,3,opt,name=state,proto3" json:"state,omitempty"`
+	CreatedAt   int64               `protobuf:"varint,4,opt,name=created_at,proto3" json:"created_at,omitempty"`
+	StartedAt   int64               `protobuf:"varint,5,opt,name=started_at,proto3" json:"started_at,omitempty"`
+	FinishedAt  int64               `protobuf:"varint,6,opt,name=finished_at,proto3" json:"finished_at,omitempty"`
+	ExitCode    int32               `protobuf:"varint,7,opt,name=exit_code,proto3" json:"exit_code,omitempty"`
+	Image       *ImageSpec          `protobuf:"bytes,8,opt,name=image,proto3" json:"image,omitempty"`
+	ImageRef    string              `protobuf:"bytes,9,opt,name=image_ref,proto3" json:"image_ref,omitempty"`
+	Reason      string              `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message     string              `protobuf:"bytes,11,opt,name=message,proto3" json:"message,omitempty"`
+	Labels      map[string]string   `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations map[string]string   `protobuf:"bytes,13,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Mounts      []*Mount            `protobuf:"bytes,14,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	LogPath     string              `protobuf:"bytes,15,opt,name=log_path,proto3" json:"log_path,omitempty"`
+	Resources   *ContainerResources `protobuf:"bytes,16,opt,name=resources,proto3" json:"resources,omitempty"`
+}
+
+type ContainerResources struct {
+	Linux *LinuxContainerResources `protobuf:"bytes,1,opt,name=linux,proto3" json:"linux,omitempty"`
+}
+
+type ContainerStatusResponse struct {
+	Status *ContainerStatus  `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Info   map[string]string `protobuf:"bytes,2,rep,name=info,proto3" json:"info,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type GetEventsRequest struct{}
+
+type ContainerEventResponse struct {
+	ContainerId        string             `protobuf:"bytes,1,opt,name=container_id,proto3" json:"container_id,omitempty"`
+	ContainerEventType ContainerEventType `protobuf:"varint,2,opt,name=container_event_type,proto3" json:"container_event_type,omitempty"`
+	CreatedAt          int64              `protobuf:"varint,3,opt,name=created_at,proto3" json:"created_at,omitempty"`
+}
+
+type ImageFilter struct {
+	Image *ImageSpec `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+type ListImagesRequest struct {
+	Filter *ImageFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+type Image struct {
+	Id          string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RepoTags    []string    `protobuf:"bytes,2,rep,name=repo_tags,proto3" json:"repo_tags,omitempty"`
+	RepoDigests []string    `protobuf:"bytes,3,rep,name=repo_digests,proto3" json:"repo_digests,omitempty"`
+	Size_       uint64      `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Uid         *Int64Value `protobuf:"bytes,5,opt,name=uid,proto3" json:"uid,omitempty"`
+	Username    string      `protobuf:"bytes,6,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+type ListImagesResponse struct {
+	Images []*Image `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+}
+
+type ImageStatusRequest struct {
+	Image   *ImageSpec `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Verbose bool       `protobuf:"varint,2,opt,name=verbose,proto3" json:"verbose,omitempty"`
+}
+
+type ImageStatusResponse struct {
+	Image *Image            `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Info  map[string]string `protobuf:"bytes,2,rep,name=info,proto3" json:"info,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type AuthConfig struct {
+	Username      string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Auth          string `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
+	ServerAddress string `protobuf:"bytes,4,opt,name=server_address,proto3" json:"server_address,omitempty"`
+	IdentityToken string `protobuf:"bytes,5,opt,name=identity_token,proto3" json:"identity_token,omitempty"`
+	RegistryToken string `protobuf:"bytes,6,opt,name=registry_token,proto3" json:"registry_token,omitempty"`
+}
+
+type PullImageRequest struct {
+	Image         *ImageSpec        `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Auth          *AuthConfig       `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	SandboxConfig *PodSandboxConfig `protobuf:"bytes,3,opt,name=sandbox_config,proto3" json:"sandbox_config,omitempty"`
+}
+
+type PullImageResponse struct {
+	ImageRef string `protobuf:"bytes,1,opt,name=image_ref,proto3" json:"image_ref,omitempty"`
+}
+
+type RemoveImageRequest struct {
+	Image *ImageSpec `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+type RemoveImageResponse struct{}
+
+type ImageFsInfoRequest struct{}
+
+type ImageFsInfoResponse struct {
+	ImageFilesystems []*FilesystemUsage `protobuf:"bytes,1,rep,name=image_filesystems,proto3" json:"image_filesystems,omitempty"`
+}
+
+func (m *VersionRequest) Reset()              { *m = VersionRequest{} }
+func (m *VersionRequest) String() string      { return proto.CompactTextString(m) }
+func (*VersionRequest) ProtoMessage()         {}
+func (m *VersionResponse) Reset()             { *m = VersionResponse{} }
+func (m *VersionResponse) String() string     { return proto.CompactTextString(m) }
+func (*VersionResponse) ProtoMessage()        {}
+func (m *DNSConfig) Reset()                   { *m = DNSConfig{} }
+func (m *DNSConfig) String() string           { return proto.CompactTextString(m) }
+func (*DNSConfig) ProtoMessage()              {}
+func (m *PortMapping) Reset()                 { *m = PortMapping{} }
+func (m *PortMapping) String() string         { return proto.CompactTextString(m) }
+func (*PortMapping) ProtoMessage()            {}
+func (m *Mount) Reset()                       { *m = Mount{} }
+func (m *Mount) String() string               { return proto.CompactTextString(m) }
+func (*Mount) ProtoMessage()                  {}
+func (m *NamespaceOption) Reset()             { *m = NamespaceOption{} }
+func (m *NamespaceOption) String() string     { return proto.CompactTextString(m) }
+func (*NamespaceOption) ProtoMessage()        {}
+func (m *Int64Value) Reset()                  { *m = Int64Value{} }
+func (m *Int64Value) String() string          { return proto.CompactTextString(m) }
+func (*Int64Value) ProtoMessage()             {}
+func (m *UInt64Value) Reset()                 { *m = UInt64Value{} }
+func (m *UInt64Value) String() string         { return proto.CompactTextString(m) }
+func (*UInt64Value) ProtoMessage()            {}
+func (m *LinuxSandboxSecurityContext) Reset() { *m = LinuxSandboxSecurityContext{} }
+func (m *LinuxSandboxSecurityContext) String() string {
+	return proto.CompactTextString(m)
+}
+func (*LinuxSandboxSecurityContext) ProtoMessage() {}
+func (m *LinuxPodSandboxConfig) Reset()            { *m = LinuxPodSandboxConfig{} }
+func (m *LinuxPodSandboxConfig) String() string    { return proto.CompactTextString(m) }
+func (*LinuxPodSandboxConfig) ProtoMessage()       {}
+func (m *PodSandboxMetadata) Reset()               { *m = PodSandboxMetadata{} }
+func (m *PodSandboxMetadata) String() string       { return proto.CompactTextString(m) }
+func (*PodSandboxMetadata) ProtoMessage()          {}
+func (m *PodSandboxConfig) Reset()                 { *m = PodSandboxConfig{} }
+func (m *PodSandboxConfig) String() string         { return proto.CompactTextString(m) }
+func (*PodSandboxConfig) ProtoMessage()            {}
+func (m *RunPodSandboxRequest) Reset()             { *m = RunPodSandboxRequest{} }
+func (m *RunPodSandboxRequest) String() string     { return proto.CompactTextString(m) }
+func (*RunPodSandboxRequest) ProtoMessage()        {}
+func (m *RunPodSandboxResponse) Reset()            { *m = RunPodSandboxResponse{} }
+func (m *RunPodSandboxResponse) String() string    { return proto.CompactTextString(m) }
+func (*RunPodSandboxResponse) ProtoMessage()       {}
+func (m *StopPodSandboxRequest) Reset()            { *m = StopPodSandboxRequest{} }
+func (m *StopPodSandboxRequest) String() string    { return proto.CompactTextString(m) }
+func (*StopPodSandboxRequest) ProtoMessage()       {}
+func (m *StopPodSandboxResponse) Reset()           { *m = StopPodSandboxResponse{} }
+func (m *StopPodSandboxResponse) String() string   { return proto.CompactTextString(m) }
+func (*StopPodSandboxResponse) ProtoMessage()      {}
+func (m *RemovePodSandboxRequest) Reset()          { *m = RemovePodSandboxRequest{} }
+func (m *RemovePodSandboxRequest) String() string  { return proto.CompactTextString(m) }
+func (*RemovePodSandboxRequest) ProtoMessage()     {}
+func (m *RemovePodSandboxResponse) Reset()         { *m = RemovePodSandboxResponse{} }
+func (m *RemovePodSandboxResponse) String() string { return proto.CompactTextString(m) }
+func (*RemovePodSandboxResponse) ProtoMessage()    {}
+func (m *PodSandboxStatusRequest) Reset()          { *m = PodSandboxStatusRequest{} }
+func (m *PodSandboxStatusRequest) String() string  { return proto.CompactTextString(m) }
+func (*PodSandboxStatusRequest) ProtoMessage()     {}
+func (m *PodSandboxNetworkStatus) Reset()          { *m = PodSandboxNetworkStatus{} }
+func (m *PodSandboxNetworkStatus) String() string  { return proto.CompactTextString(m) }
+func (*PodSandboxNetworkStatus) ProtoMessage()     {}
+func (m *PodIP) Reset()                            { *m = PodIP{} }
+func (m *PodIP) String() string                    { return proto.CompactTextString(m) }
+func (*PodIP) ProtoMessage()                       {}
+func (m *PodSandboxStatus) Reset()                 { *m = PodSandboxStatus{} }
+func (m *PodSandboxStatus) String() string         { return proto.CompactTextString(m) }
+func (*PodSandboxStatus) ProtoMessage()            {}
+func (m *PodSandboxStatusResponse) Reset()         { *m = PodSandboxStatusResponse{} }
+func (m *PodSandboxStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*PodSandboxStatusResponse) ProtoMessage()    {}
+func (m *PodSandboxStateValue) Reset()             { *m = PodSandboxStateValue{} }
+func (m *PodSandboxStateValue) String() string     { return proto.CompactTextString(m) }
+func (*PodSandboxStateValue) ProtoMessage()        {}
+func (m *PodSandboxFilter) Reset()                 { *m = PodSandboxFilter{} }
+func (m *PodSandboxFilter) String() string         { return proto.CompactTextString(m) }
+func (*PodSandboxFilter) ProtoMessage()            {}
+func (m *ListPodSandboxRequest) Reset()            { *m = ListPodSandboxRequest{} }
+func (m *ListPodSandboxRequest) String() string    { return proto.CompactTextString(m) }
+func (*ListPodSandboxRequest) ProtoMessage()       {}
+func (m *PodSandbox) Reset()                       { *m = PodSandbox{} }
+func (m *PodSandbox) String() string               { return proto.CompactTextString(m) }
+func (*PodSandbox) ProtoMessage()                  {}
+func (m *ListPodSandboxResponse) Reset()           { *m = ListPodSandboxResponse{} }
+func (m *ListPodSandboxResponse) String() string   { return proto.CompactTextString(m) }
+func (*ListPodSandboxResponse) ProtoMessage()      {}
+func (m *ImageSpec) Reset()                        { *m = ImageSpec{} }
+func (m *ImageSpec) String() string                { return proto.CompactTextString(m) }
+func (*ImageSpec) ProtoMessage()                   {}
+func (m *KeyValue) Reset()                         { *m = KeyValue{} }
+func (m *KeyValue) String() string                 { return proto.CompactTextString(m) }
+func (*KeyValue) ProtoMessage()                    {}
+func (m *LinuxContainerResources) Reset()          { *m = LinuxContainerResources{} }
+func (m *LinuxContainerResources) String() string  { return proto.CompactTextString(m) }
+func (*LinuxContainerResources) ProtoMessage()     {}
+func (m *Capability) Reset()                       { *m = Capability{} }
+func (m *Capability) String() string               { return proto.CompactTextString(m) }
+func (*Capability) ProtoMessage()                  {}
+func (m *SELinuxOption) Reset()                    { *m = SELinuxOption{} }
+func (m *SELinuxOption) String() string            { return proto.CompactTextString(m) }
+func (*SELinuxOption) ProtoMessage()               {}
+func (m *LinuxContainerSecurityContext) Reset()    { *m = LinuxContainerSecurityContext{} }
+func (m *LinuxContainerSecurityContext) String() string {
+	return proto.CompactTextString(m)
+}
+func (*LinuxContainerSecurityContext) ProtoMessage() {}
+func (m *LinuxContainerConfig) Reset()               { *m = LinuxContainerConfig{} }
+func (m *LinuxContainerConfig) String() string       { return proto.CompactTextString(m) }
+func (*LinuxContainerConfig) ProtoMessage()          {}
+func (m *ContainerMetadata) Reset()                  { *m = ContainerMetadata{} }
+func (m *ContainerMetadata) String() string          { return proto.CompactTextString(m) }
+func (*ContainerMetadata) ProtoMessage()             {}
+func (m *ContainerConfig) Reset()                    { *m = ContainerConfig{} }
+func (m *ContainerConfig) String() string            { return proto.CompactTextString(m) }
+func (*ContainerConfig) ProtoMessage()               {}
+func (m *CreateContainerRequest) Reset()             { *m = CreateContainerRequest{} }
+func (m *CreateContainerRequest) String() string     { return proto.CompactTextString(m) }
+func (*CreateContainerRequest) ProtoMessage()        {}
+func (m *CreateContainerResponse) Reset()            { *m = CreateContainerResponse{} }
+func (m *CreateContainerResponse) String() string    { return proto.CompactTextString(m) }
+func (*CreateContainerResponse) ProtoMessage()       {}
+func (m *StartContainerRequest) Reset()              { *m = StartContainerRequest{} }
+func (m *StartContainerRequest) String() string      { return proto.CompactTextString(m) }
+func (*StartContainerRequest) ProtoMessage()         {}
+func (m *StartContainerResponse) Reset()             { *m = StartContainerResponse{} }
+func (m *StartContainerResponse) String() string     { return proto.CompactTextString(m) }
+func (*StartContainerResponse) ProtoMessage()        {}
+func (m *StopContainerRequest) Reset()               { *m = StopContainerRequest{} }
+func (m *StopContainerRequest) String() string       { return proto.CompactTextString(m) }
+func (*StopContainerRequest) ProtoMessage()          {}
+func (m *StopContainerResponse) Reset()              { *m = StopContainerResponse{} }
+func (m *StopContainerResponse) String() string      { return proto.CompactTextString(m) }
+func (*StopContainerResponse) ProtoMessage()         {}
+func (m *RemoveContainerRequest) Reset()             { *m = RemoveContainerRequest{} }
+func (m *RemoveContainerRequest) String() string     { return proto.CompactTextString(m) }
+func (*RemoveContainerRequest) ProtoMessage()        {}
+func (m *RemoveContainerResponse) Reset()            { *m = RemoveContainerResponse{} }
+func (m *RemoveContainerResponse) String() string    { return proto.CompactTextString(m) }
+func (*RemoveContainerResponse) ProtoMessage()       {}
+func (m *ExecSyncRequest) Reset()                    { *m = ExecSyncRequest{} }
+func (m *ExecSyncRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExecSyncRequest) ProtoMessage()               {}
+func (m *ExecSyncResponse) Reset()                   { *m = ExecSyncResponse{} }
+func (m *ExecSyncResponse) String() string           { return proto.CompactTextString(m) }
+func (*ExecSyncResponse) ProtoMessage()              {}
+func (m *ContainerStatsRequest) Reset()              { *m = ContainerStatsRequest{} }
+func (m *ContainerStatsRequest) String() string      { return proto.CompactTextString(m) }
+func (*ContainerStatsRequest) ProtoMessage()         {}
+func (m *ContainerStatsResponse) Reset()             { *m = ContainerStatsResponse{} }
+func (m *ContainerStatsResponse) String() string     { return proto.CompactTextString(m) }
+func (*ContainerStatsResponse) ProtoMessage()        {}
+func (m *ContainerAttributes) Reset()                { *m = ContainerAttributes{} }
+func (m *ContainerAttributes) String() string        { return proto.CompactTextString(m) }
+func (*ContainerAttributes) ProtoMessage()           {}
+func (m *ContainerStats) Reset()                     { *m = ContainerStats{} }
+func (m *ContainerStats) String() string             { return proto.CompactTextString(m) }
+func (*ContainerStats) ProtoMessage()                {}
+func (m *CpuUsage) Reset()                           { *m = CpuUsage{} }
+func (m *CpuUsage) String() string                   { return proto.CompactTextString(m) }
+func (*CpuUsage) ProtoMessage()                      {}
+func (m *MemoryUsage) Reset()                        { *m = MemoryUsage{} }
+func (m *MemoryUsage) String() string                { return proto.CompactTextString(m) }
+func (*MemoryUsage) ProtoMessage()                   {}
+func (m *FilesystemIdentifier) Reset()               { *m = FilesystemIdentifier{} }
+func (m *FilesystemIdentifier) String() string       { return proto.CompactTextString(m) }
+func (*FilesystemIdentifier) ProtoMessage()          {}
+func (m *FilesystemUsage) Reset()                    { *m = FilesystemUsage{} }
+func (m *FilesystemUsage) String() string            { return proto.CompactTextString(m) }
+func (*FilesystemUsage) ProtoMessage()               {}
+func (m *ContainerStateValue) Reset()                { *m = ContainerStateValue{} }
+func (m *ContainerStateValue) String() string        { return proto.CompactTextString(m) }
+func (*ContainerStateValue) ProtoMessage()           {}
+func (m *ContainerFilter) Reset()                    { *m = ContainerFilter{} }
+func (m *ContainerFilter) String() string            { return proto.CompactTextString(m) }
+func (*ContainerFilter) ProtoMessage()               {}
+func (m *ListContainersRequest) Reset()              { *m = ListContainersRequest{} }
+func (m *ListContainersRequest) String() string      { return proto.CompactTextString(m) }
+func (*ListContainersRequest) ProtoMessage()         {}
+func (m *Container) Reset()                          { *m = Container{} }
+func (m *Container) String() string                  { return proto.CompactTextString(m) }
+func (*Container) ProtoMessage()                     {}
+func (m *ListContainersResponse) Reset()             { *m = ListContainersResponse{} }
+func (m *ListContainersResponse) String() string     { return proto.CompactTextString(m) }
+func (*ListContainersResponse) ProtoMessage()        {}
+func (m *ContainerStatusRequest) Reset()             { *m = ContainerStatusRequest{} }
+func (m *ContainerStatusRequest) String() string     { return proto.CompactTextString(m) }
+func (*ContainerStatusRequest) ProtoMessage()        {}
+func (m *ContainerStatus) Reset()                    { *m = ContainerStatus{} }
+func (m *ContainerStatus) String() string            { return proto.CompactTextString(m) }
+func (*ContainerStatus) ProtoMessage()               {}
+func (m *ContainerResources) Reset()                 { *m = ContainerResources{} }
+func (m *ContainerResources) String() string         { return proto.CompactTextString(m) }
+func (*ContainerResources) ProtoMessage()            {}
+func (m *ContainerStatusResponse) Reset()            { *m = ContainerStatusResponse{} }
+func (m *ContainerStatusResponse) String() string    { return proto.CompactTextString(m) }
+func (*ContainerStatusResponse) ProtoMessage()       {}
+func (m *GetEventsRequest) Reset()                   { *m = GetEventsRequest{} }
+func (m *GetEventsRequest) String() string           { return proto.CompactTextString(m) }
+func (*GetEventsRequest) ProtoMessage()              {}
+func (m *ContainerEventResponse) Reset()             { *m = ContainerEventResponse{} }
+func (m *ContainerEventResponse) String() string     { return proto.CompactTextString(m) }
+func (*ContainerEventResponse) ProtoMessage()        {}
+func (m *ImageFilter) Reset()                        { *m = ImageFilter{} }
+func (m *ImageFilter) String() string                { return proto.CompactTextString(m) }
+func (*ImageFilter) ProtoMessage()                   {}
+func (m *ListImagesRequest) Reset()                  { *m = ListImagesRequest{} }
+func (m *ListImagesRequest) String() string          { return proto.CompactTextString(m) }
+func (*ListImagesRequest) ProtoMessage()             {}
+func (m *Image) Reset()                              { *m = Image{} }
+func (m *Image) String() string                      { return proto.CompactTextString(m) }
+func (*Image) ProtoMessage()                         {}
+func (m *ListImagesResponse) Reset()                 { *m = ListImagesResponse{} }
+func (m *ListImagesResponse) String() string         { return proto.CompactTextString(m) }
+func (*ListImagesResponse) ProtoMessage()            {}
+func (m *ImageStatusRequest) Reset()                 { *m = ImageStatusRequest{} }
+func (m *ImageStatusRequest) String() string         { return proto.CompactTextString(m) }
+func (*ImageStatusRequest) ProtoMessage()            {}
+func (m *ImageStatusResponse) Reset()                { *m = ImageStatusResponse{} }
+func (m *ImageStatusResponse) String() string        { return proto.CompactTextString(m) }
+func (*ImageStatusResponse) ProtoMessage()           {}
+func (m *AuthConfig) Reset()                         { *m = AuthConfig{} }
+func (m *AuthConfig) String() string                 { return proto.CompactTextString(m) }
+func (*AuthConfig) ProtoMessage()                    {}
+func (m *PullImageRequest) Reset()                   { *m = PullImageRequest{} }
+func (m *PullImageRequest) String() string           { return proto.CompactTextString(m) }
+func (*PullImageRequest) ProtoMessage()              {}
+func (m *PullImageResponse) Reset()                  { *m = PullImageResponse{} }
+func (m *PullImageResponse) String() string          { return proto.CompactTextString(m) }
+func (*PullImageResponse) ProtoMessage()             {}
+func (m *RemoveImageRequest) Reset()                 { *m = RemoveImageRequest{} }
+func (m *RemoveImageRequest) String() string         { return proto.CompactTextString(m) }
+func (*RemoveImageRequest) ProtoMessage()            {}
+func (m *RemoveImageResponse) Reset()                { *m = RemoveImageResponse{} }
+func (m *RemoveImageResponse) String() string        { return proto.CompactTextString(m) }
+func (*RemoveImageResponse) ProtoMessage()           {}
+func (m *ImageFsInfoRequest) Reset()                 { *m = ImageFsInfoRequest{} }
+func (m *ImageFsInfoRequest) String() string         { return proto.CompactTextString(m) }
+func (*ImageFsInfoRequest) ProtoMessage()            {}
+func (m *ImageFsInfoResponse) Reset()                { *m = ImageFsInfoResponse{} }
+func (m *ImageFsInfoResponse) String() string        { return proto.CompactTextString(m) }
+func (*ImageFsInfoResponse) ProtoMessage()           {}
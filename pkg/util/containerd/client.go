@@ -0,0 +1,90 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultHost    = "unix:///run/containerd/containerd.sock"
+	defaultTimeout = 10 * time.Second
+	maxMsgSize     = 1024 * 1024 * 16
+)
+
+// Client holds connection to containerd cri plugin
+type Client struct {
+	conn *grpc.ClientConn
+
+	Runtime RuntimeServiceClient
+	Image   ImageServiceClient
+}
+
+// New dials containerd socket, host should be in unix:///path/to/socket format
+func New(host string, timeout time.Duration) (*Client, error) {
+
+	if len(host) == 0 {
+		host = DefaultHost
+	}
+
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "unix" {
+		return nil, fmt.Errorf("containerd host scheme <%s> not supported", u.Scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, u.Path,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(Client)
+	c.conn = conn
+	c.Runtime = NewRuntimeServiceClient(conn)
+	c.Image = NewImageServiceClient(conn)
+
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
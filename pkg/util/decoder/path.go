@@ -0,0 +1,85 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2019] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package decoder
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YamlPath returns dotted path of the deepest yaml field placed at the line
+// of the document, like `spec.template.containers[0].image`.
+// Empty string is returned if document can not be parsed or line is not found.
+func YamlPath(data []byte, line int) string {
+
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return ""
+	}
+
+	path, _ := yamlPath(&node, "", line)
+	return path
+}
+
+func yamlPath(node *yaml.Node, path string, line int) (string, bool) {
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			if p, ok := yamlPath(n, path, line); ok {
+				return p, true
+			}
+		}
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			p := key.Value
+			if path != "" {
+				p = path + "." + key.Value
+			}
+
+			if sub, ok := yamlPath(value, p, line); ok {
+				return sub, true
+			}
+
+			if key.Line == line || value.Line == line {
+				return p, true
+			}
+		}
+
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			p := fmt.Sprintf("%s[%d]", path, i)
+
+			if sub, ok := yamlPath(n, p, line); ok {
+				return sub, true
+			}
+
+			if n.Line == line {
+				return p, true
+			}
+		}
+	}
+
+	return "", false
+}
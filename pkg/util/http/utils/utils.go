@@ -22,7 +22,9 @@ import (
 	"context"
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/util/converter"
+	"mime"
 	"net/http"
+	"strings"
 )
 
 func Vars(r *http.Request) map[string]string {
@@ -48,3 +50,40 @@ func QueryInt(r *http.Request, param string) int64 {
 func QueryBool(r *http.Request, param string) bool {
 	return converter.StringToBool(r.URL.Query().Get(param))
 }
+
+// IfMatch checks If-Match request header against provided entity version.
+// Missing header or `*` matches any version, weak prefix and quotes are ignored
+func IfMatch(r *http.Request, version string) bool {
+
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return true
+	}
+
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		v = strings.TrimPrefix(v, "W/")
+		v = strings.Trim(v, "\"")
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsYaml checks if request body is sent as yaml by Content-Type header
+func IsYaml(r *http.Request) bool {
+
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	switch mt {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+
+	return false
+}
@@ -98,8 +98,9 @@ func IsPort(port int) bool {
 	return govalidator.IsPort(strconv.Itoa(port))
 }
 
-func IsDomain(domain string) bool { // TODO domain Validator
-	return true
+// IsDomain checks domain is valid DNS name, fully qualified name may end with dot
+func IsDomain(domain string) bool {
+	return govalidator.IsDNSName(strings.TrimSuffix(domain, "."))
 }
 
 func IsProtocol(protocol string) bool {
@@ -188,3 +189,8 @@ func IsValueInList(value string, list []string) bool {
 	}
 	return false
 }
+
+func IsPortName(s string) bool {
+	reg, _ := regexp.Compile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+	return reg.MatchString(s) && len(s) <= 15
+}